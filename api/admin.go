@@ -0,0 +1,35 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package api
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// adminHandler wraps administrative handler, so it is only called with "Authorization: Bearer <AdminToken>" header.
+func (ar *Runner) adminHandler(next http.HandlerFunc) http.HandlerFunc {
+	return func(response http.ResponseWriter, req *http.Request) {
+		token := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+		if ar.cfg.AdminToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(ar.cfg.AdminToken)) != 1 {
+			http.Error(response, "[ adminHandler ] Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next(response, req)
+	}
+}
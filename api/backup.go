@@ -0,0 +1,64 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/core/utils"
+	"github.com/insolar/insolar/instrumentation/inslogger"
+)
+
+// backupHandler streams storage backup. Query parameter "since" sets pulse incremental backup starts from,
+// full snapshot is streamed if it is omitted.
+func (ar *Runner) backupHandler() func(http.ResponseWriter, *http.Request) {
+	return func(response http.ResponseWriter, req *http.Request) {
+		traceID := utils.RandTraceID()
+		ctx, insLog := inslogger.WithTraceField(context.Background(), traceID)
+
+		insLog.Infof("[ backupHandler ] Incoming request: %s", req.RequestURI)
+
+		if ar.StorageSnapshotter == nil {
+			http.Error(response, "[ backupHandler ] Storage backups are not supported", http.StatusNotImplemented)
+			return
+		}
+
+		var since core.PulseNumber
+		if s := req.URL.Query().Get("since"); s != "" {
+			pn, err := strconv.ParseUint(s, 10, 32)
+			if err != nil {
+				http.Error(response, "[ backupHandler ] Bad since pulse: "+s, http.StatusBadRequest)
+				return
+			}
+			since = core.PulseNumber(pn)
+		}
+
+		response.Header().Set("Content-Type", "application/octet-stream")
+		response.Header().Set("Cache-Control", "no-cache")
+		// Backup is streamed while it is taken, so errors after the first byte only break the stream.
+		// Clients detect it by missing end marker.
+		latest, err := ar.StorageSnapshotter.Backup(ctx, response, since)
+		if err != nil {
+			insLog.Error("[ backupHandler ] Failed to write backup: ", err)
+			return
+		}
+		insLog.Infof("[ backupHandler ] Backup since pulse %v is sent, latest pulse %v", since, latest)
+	}
+}
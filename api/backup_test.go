@@ -0,0 +1,80 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package api
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/insolar/insolar/configuration"
+	"github.com/insolar/insolar/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type snapshotterFunc func(ctx context.Context, w io.Writer, since core.PulseNumber) (core.PulseNumber, error)
+
+func (f snapshotterFunc) Backup(ctx context.Context, w io.Writer, since core.PulseNumber) (core.PulseNumber, error) {
+	return f(ctx, w, since)
+}
+
+func TestRunner_backupHandler(t *testing.T) {
+	cfg := configuration.NewAPIRunner()
+	cfg.Backup = "/api/backup"
+	cfg.AdminToken = "secret"
+	ar, err := NewRunner(&cfg)
+	require.NoError(t, err)
+
+	var requested core.PulseNumber
+	ar.StorageSnapshotter = snapshotterFunc(func(ctx context.Context, w io.Writer, since core.PulseNumber) (core.PulseNumber, error) {
+		requested = since
+		_, err := w.Write([]byte("backup"))
+		return 100, err
+	})
+
+	server := httptest.NewServer(ar.adminHandler(ar.backupHandler()))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "?since=65540")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	get := func(url string) (*http.Response, error) {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer secret")
+		return http.DefaultClient.Do(req)
+	}
+	resp, err = get(server.URL + "?since=65540")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, core.PulseNumber(65540), requested)
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "backup", string(body))
+
+	resp, err = get(server.URL + "?since=latest")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
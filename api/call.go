@@ -18,9 +18,11 @@ package api
 
 import (
 	"context"
+	"crypto"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/insolar/insolar/api/seedmanager"
@@ -70,17 +72,20 @@ func UnmarshalRequest(req *http.Request, params interface{}) ([]byte, error) {
 	return body, nil
 }
 
-func (ar *Runner) verifySignature(ctx context.Context, params Request) error {
-	key, err := ar.getMemberPubKey(ctx, params.Reference)
-	if err != nil {
-		return errors.Wrap(err, "[ VerifySignature ] Can't getMemberPubKey")
-	}
-	if key == "" {
-		return errors.New("[ VerifySignature ] Not found public key for this member")
+func verifyWithKeys(keys []crypto.PublicKey, signature core.Signature, args []byte) bool {
+	for _, key := range keys {
+		if scheme.Verifier(key).Verify(signature, args) {
+			return true
+		}
 	}
+	return false
+}
+
+// signedContent returns data of request signed by caller
+func signedContent(params Request) ([]byte, error) {
 	ref, err := core.NewRefFromBase58(params.Reference)
 	if err != nil {
-		return errors.Wrap(err, "[ VerifySignature ] failed to parse params.Reference")
+		return nil, errors.Wrap(err, "failed to parse params.Reference")
 	}
 
 	args, err := core.MarshalArgs(
@@ -89,14 +94,58 @@ func (ar *Runner) verifySignature(ctx context.Context, params Request) error {
 		params.Params,
 		params.Seed)
 	if err != nil {
-		return errors.Wrap(err, "[ VerifySignature ] Can't marshal arguments for verify signature")
+		return nil, errors.Wrap(err, "Can't marshal arguments for verify signature")
+	}
+	return args, nil
+}
+
+func (ar *Runner) verifySignature(ctx context.Context, params Request) error {
+	keys, err := ar.getMemberPubKeys(ctx, params.Reference)
+	if err != nil {
+		return errors.Wrap(err, "[ VerifySignature ] Can't getMemberPubKeys")
 	}
-	verifier := scheme.Verifier(key)
-	verified := verifier.Verify(core.SignatureFromBytes(params.Signature), args)
-	if !verified {
-		return errors.New("[ VerifySignature ] Incorrect signature")
+	if len(keys) == 0 {
+		return errors.New("[ VerifySignature ] Not found public key for this member")
+	}
+	args, err := signedContent(params)
+	if err != nil {
+		return errors.Wrap(err, "[ VerifySignature ]")
+	}
+
+	// Any key of member can sign request, multisig member checks number of co-signers itself
+	signature := core.SignatureFromBytes(params.Signature)
+	if verifyWithKeys(keys, signature, args) {
+		return nil
+	}
+
+	// Key could be rotated through another node since it was cached
+	if ar.refreshMemberPubKeys(params.Reference) {
+		keys, err = ar.getMemberPubKeys(ctx, params.Reference)
+		if err != nil {
+			return errors.Wrap(err, "[ VerifySignature ] Can't getMemberPubKeys")
+		}
+		if verifyWithKeys(keys, signature, args) {
+			return nil
+		}
+	}
+	return errors.New("[ VerifySignature ] Incorrect signature")
+}
+
+// invalidateKeysAfterCall drops cached keys of members whose keys were changed by the call
+func (ar *Runner) invalidateKeysAfterCall(params Request) {
+	switch params.Method {
+	case "RotateKey":
+		ar.invalidateMemberPubKeys(params.Reference)
+	case "RevokeKey":
+		var args []interface{}
+		err := core.Deserialize(params.Params, &args)
+		if err != nil || len(args) == 0 {
+			return
+		}
+		if memberRef, ok := args[0].(string); ok {
+			ar.invalidateMemberPubKeys(memberRef)
+		}
 	}
-	return nil
 }
 
 func (ar *Runner) checkSeed(paramsSeed []byte) error {
@@ -150,6 +199,69 @@ func processError(err error, extraMsg string, resp *answer, insLog core.Logger)
 	insLog.Error(errors.Wrapf(err, "[ CallHandler ] %s", extraMsg))
 }
 
+func writeAnswer(response http.ResponseWriter, resp interface{}, insLog core.Logger) {
+	res, err := json.MarshalIndent(resp, "", "    ")
+	if err != nil {
+		res = []byte(`{"error": "can't marshal answer to json'"}`)
+	}
+	response.Header().Add("Content-Type", "application/json")
+	_, err = response.Write(res)
+	if err != nil {
+		insLog.Errorf("Can't write response\n")
+	}
+}
+
+// processCall checks signature of request and executes it, result or error is stored to resp. Seed is checked by
+// caller, seedErr is the result of the check.
+func (ar *Runner) processCall(ctx context.Context, params Request, seedErr error, resp *answer, insLog core.Logger) {
+	startTime := time.Now()
+	defer func() {
+		success := "success"
+		if resp.Error != "" {
+			success = "fail"
+		}
+		metrics.APIContractExecutionTime.WithLabelValues(params.Method, success).Observe(time.Since(startTime).Seconds())
+	}()
+
+	if seedErr != nil {
+		processError(seedErr, "Can't checkSeed", resp, insLog)
+		return
+	}
+
+	err := ar.verifySignature(ctx, params)
+	if err != nil {
+		processError(err, "Can't verify signature", resp, insLog)
+		return
+	}
+
+	var result interface{}
+	ch := make(chan interface{}, 1)
+	traceID := resp.TraceID
+	go func() {
+		result, err = ar.makeCall(ctx, params)
+		if err == nil {
+			ar.invalidateKeysAfterCall(params)
+		}
+		// Call result is also streamed, so client can get it even after timeout.
+		ar.streams.publishCall(traceID, result, err)
+		ch <- nil
+	}()
+	select {
+
+	case <-ch:
+		if err != nil {
+			processError(err, "Can't makeCall", resp, insLog)
+			return
+		}
+		resp.Result = result
+
+	case <-time.After(time.Duration(ar.cfg.Timeout) * time.Second):
+		resp.Error = "Messagebus timeout exceeded"
+		return
+
+	}
+}
+
 func (ar *Runner) callHandler() func(http.ResponseWriter, *http.Request) {
 	return func(response http.ResponseWriter, req *http.Request) {
 		traceID := utils.RandTraceID()
@@ -161,30 +273,11 @@ func (ar *Runner) callHandler() func(http.ResponseWriter, *http.Request) {
 		params := Request{}
 		resp := answer{}
 
-		startTime := time.Now()
-		defer func() {
-			success := "success"
-			if resp.Error != "" {
-				success = "fail"
-			}
-			metrics.APIContractExecutionTime.WithLabelValues(params.Method, success).Observe(time.Since(startTime).Seconds())
-		}()
-
 		resp.TraceID = traceID
 
 		insLog.Infof("[ callHandler ] Incoming request: %s", req.RequestURI)
 
-		defer func() {
-			res, err := json.MarshalIndent(resp, "", "    ")
-			if err != nil {
-				res = []byte(`{"error": "can't marshal answer to json'"}`)
-			}
-			response.Header().Add("Content-Type", "application/json")
-			_, err = response.Write(res)
-			if err != nil {
-				insLog.Errorf("Can't write response\n")
-			}
-		}()
+		defer writeAnswer(response, &resp, insLog)
 
 		_, err := UnmarshalRequest(req, &params)
 		if err != nil {
@@ -192,44 +285,87 @@ func (ar *Runner) callHandler() func(http.ResponseWriter, *http.Request) {
 			return
 		}
 
-		err = ar.checkSeed(params.Seed)
+		ar.processCall(ctx, params, ar.checkSeed(params.Seed), &resp, insLog)
+	}
+}
+
+// batchCallHandler executes array of independently signed requests concurrently. Answers are returned in the same
+// order as requests, every request gets its own trace id.
+//
+// Requests of one batch may share a seed, every seed is used once per batch. Requests with the same signed content
+// are rejected, so a signed request can't be repeated inside the batch even with another valid signature.
+func (ar *Runner) batchCallHandler() func(http.ResponseWriter, *http.Request) {
+	return func(response http.ResponseWriter, req *http.Request) {
+		traceID := utils.RandTraceID()
+		ctx, insLog := inslogger.WithTraceField(context.Background(), traceID)
+
+		_, span := instracer.StartSpan(ctx, "batchCallHandler")
+		defer span.End()
+
+		insLog.Infof("[ batchCallHandler ] Incoming request: %s", req.RequestURI)
+
+		var batch []Request
+		_, err := UnmarshalRequest(req, &batch)
 		if err != nil {
-			processError(err, "Can't checkSeed", &resp, insLog)
+			resp := answer{TraceID: traceID}
+			processError(err, "Can't unmarshal request", &resp, insLog)
+			writeAnswer(response, &resp, insLog)
 			return
 		}
-
-		err = ar.verifySignature(ctx, params)
-		if err != nil {
-			processError(err, "Can't verify signature", &resp, insLog)
+		if ar.cfg.BatchLimit > 0 && len(batch) > ar.cfg.BatchLimit {
+			resp := answer{TraceID: traceID}
+			err = errors.Errorf("[ batchCallHandler ] Too many requests in batch: %d, limit is %d", len(batch), ar.cfg.BatchLimit)
+			processError(err, "Can't process batch", &resp, insLog)
+			writeAnswer(response, &resp, insLog)
 			return
 		}
 
-		var result interface{}
-		ch := make(chan interface{}, 1)
-		go func() {
-			result, err = ar.makeCall(ctx, params)
-			ch <- nil
-		}()
-		select {
-
-		case <-ch:
-			if err != nil {
-				processError(err, "Can't makeCall", &resp, insLog)
-				return
+		seedErrs := make([]error, len(batch))
+		checkedSeeds := map[string]error{}
+		contents := map[string]struct{}{}
+		for i, params := range batch {
+			// Malformed request fails on signature verification.
+			if content, err := signedContent(params); err == nil {
+				if _, ok := contents[string(content)]; ok {
+					seedErrs[i] = errors.New("[ batchCallHandler ] Duplicate request in batch")
+					continue
+				}
+				contents[string(content)] = struct{}{}
 			}
-			resp.Result = result
-
-		case <-time.After(time.Duration(ar.cfg.Timeout) * time.Second):
-			resp.Error = "Messagebus timeout exceeded"
-			return
 
+			seedErr, ok := checkedSeeds[string(params.Seed)]
+			if !ok {
+				seedErr = ar.checkSeed(params.Seed)
+				checkedSeeds[string(params.Seed)] = seedErr
+			}
+			seedErrs[i] = seedErr
 		}
 
-		if err != nil {
-			processError(err, "Can't makeCall", &resp, insLog)
-			return
+		parallelism := ar.cfg.BatchParallelism
+		if parallelism <= 0 {
+			parallelism = 1
 		}
+		semaphore := make(chan struct{}, parallelism)
+		answers := make([]answer, len(batch))
+		var wg sync.WaitGroup
+		wg.Add(len(batch))
+		for i := range batch {
+			semaphore <- struct{}{}
+			go func(i int) {
+				defer func() {
+					<-semaphore
+					wg.Done()
+				}()
+
+				itemTraceID := utils.RandTraceID()
+				itemCtx, itemLog := inslogger.WithTraceField(context.Background(), itemTraceID)
+				answers[i].TraceID = itemTraceID
+				ar.processCall(itemCtx, batch[i], seedErrs[i], &answers[i], itemLog)
+			}(i)
+		}
+		wg.Wait()
 
-		resp.Result = result
+		metrics.APIBatchSize.Observe(float64(len(batch)))
+		writeAnswer(response, answers, insLog)
 	}
 }
@@ -17,7 +17,9 @@
 package api
 
 import (
+	"bytes"
 	"context"
+	"crypto"
 	"encoding/json"
 	"net/http"
 	"testing"
@@ -36,12 +38,14 @@ import (
 )
 
 const CallUrl = "http://localhost:19192/api/call"
+const BatchCallUrl = "http://localhost:19192/api/batch"
 
 type TimeoutSuite struct {
 	suite.Suite
 	ctx   context.Context
 	api   *Runner
 	user  *requester.UserConfigJSON
+	key   crypto.PrivateKey
 	delay bool
 }
 
@@ -93,6 +97,120 @@ func (suite *TimeoutSuite) TestRunner_callHandlerTimeout() {
 	suite.Equal("", result.Result)
 }
 
+func (suite *TimeoutSuite) signedRequest(method string, seed []byte) Request {
+	ref, err := core.NewRefFromBase58(suite.user.Caller)
+	suite.NoError(err)
+	params := []byte{}
+	args, err := core.MarshalArgs(*ref, method, params, seed)
+	suite.NoError(err)
+	signature, err := scheme.Signer(suite.key).Sign(args)
+	suite.NoError(err)
+
+	return Request{
+		Reference: suite.user.Caller,
+		Method:    method,
+		Params:    params,
+		Seed:      seed,
+		Signature: signature.Bytes(),
+	}
+}
+
+func (suite *TimeoutSuite) TestRunner_batchCallHandler() {
+	seed, err := suite.api.SeedGenerator.Next()
+	suite.NoError(err)
+	suite.api.SeedManager.Add(*seed)
+
+	batch := []Request{
+		suite.signedRequest("first", seed[:]),
+		suite.signedRequest("second", []byte("bad seed")),
+		suite.signedRequest("third", seed[:]),
+	}
+	body, err := json.Marshal(batch)
+	suite.NoError(err)
+
+	resp, err := http.Post(BatchCallUrl, "application/json", bytes.NewReader(body))
+	suite.NoError(err)
+	defer resp.Body.Close()
+
+	var results []answer
+	err = json.NewDecoder(resp.Body).Decode(&results)
+	suite.NoError(err)
+	suite.Len(results, 3)
+	suite.Equal("OK", results[0].Result)
+	suite.Equal("", results[0].Error)
+	suite.Equal("[ checkSeed ] Bad seed param", results[1].Error)
+	suite.Equal("OK", results[2].Result)
+	suite.NotEqual(results[0].TraceID, results[2].TraceID)
+
+	// Seed is used by the batch.
+	resp, err = http.Post(BatchCallUrl, "application/json", bytes.NewReader(body))
+	suite.NoError(err)
+	defer resp.Body.Close()
+	err = json.NewDecoder(resp.Body).Decode(&results)
+	suite.NoError(err)
+	suite.Equal("[ checkSeed ] Incorrect seed", results[0].Error)
+}
+
+func (suite *TimeoutSuite) TestRunner_batchCallHandlerDuplicate() {
+	seed, err := suite.api.SeedGenerator.Next()
+	suite.NoError(err)
+	suite.api.SeedManager.Add(*seed)
+
+	request := suite.signedRequest("first", seed[:])
+	body, err := json.Marshal([]Request{request, request})
+	suite.NoError(err)
+
+	resp, err := http.Post(BatchCallUrl, "application/json", bytes.NewReader(body))
+	suite.NoError(err)
+	defer resp.Body.Close()
+
+	var results []answer
+	err = json.NewDecoder(resp.Body).Decode(&results)
+	suite.NoError(err)
+	suite.Len(results, 2)
+	suite.Equal("OK", results[0].Result)
+	suite.Equal("[ batchCallHandler ] Duplicate request in batch", results[1].Error)
+}
+
+func (suite *TimeoutSuite) TestRunner_batchCallHandlerDuplicateResigned() {
+	seed, err := suite.api.SeedGenerator.Next()
+	suite.NoError(err)
+	suite.api.SeedManager.Add(*seed)
+
+	// Same request signed twice has different valid signatures.
+	first := suite.signedRequest("first", seed[:])
+	second := suite.signedRequest("first", seed[:])
+	suite.NotEqual(first.Signature, second.Signature)
+	body, err := json.Marshal([]Request{first, second})
+	suite.NoError(err)
+
+	resp, err := http.Post(BatchCallUrl, "application/json", bytes.NewReader(body))
+	suite.NoError(err)
+	defer resp.Body.Close()
+
+	var results []answer
+	err = json.NewDecoder(resp.Body).Decode(&results)
+	suite.NoError(err)
+	suite.Len(results, 2)
+	suite.Equal("OK", results[0].Result)
+	suite.Equal("[ batchCallHandler ] Duplicate request in batch", results[1].Error)
+}
+
+func (suite *TimeoutSuite) TestRunner_batchCallHandlerLimit() {
+	batch := make([]Request, suite.api.cfg.BatchLimit+1)
+	body, err := json.Marshal(batch)
+	suite.NoError(err)
+
+	resp, err := http.Post(BatchCallUrl, "application/json", bytes.NewReader(body))
+	suite.NoError(err)
+	defer resp.Body.Close()
+
+	var result answer
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	suite.NoError(err)
+	suite.Contains(result.Error, "Too many requests in batch")
+}
+
 func TestTimeoutSuite(t *testing.T) {
 	timeoutSuite := new(TimeoutSuite)
 	timeoutSuite.ctx, _ = inslogger.WithTraceField(context.Background(), "APItests")
@@ -102,6 +220,7 @@ func TestTimeoutSuite(t *testing.T) {
 	require.NoError(t, err)
 	sKeyString, err := ks.ExportPrivateKeyPEM(sKey)
 	require.NoError(t, err)
+	timeoutSuite.key = sKey
 	pKey := ks.ExtractPublicKey(sKey)
 	pKeyString, err := ks.ExportPublicKeyPEM(pKey)
 	require.NoError(t, err)
@@ -129,8 +248,8 @@ func TestTimeoutSuite(t *testing.T) {
 	cr := testutils.NewContractRequesterMock(t)
 	cr.SendRequestFunc = func(p context.Context, p1 *core.RecordRef, method string, p3 []interface{}) (core.Reply, error) {
 		switch method {
-		case "GetPublicKey":
-			var result = string(pKeyString)
+		case "GetPublicKeys":
+			var result = []string{string(pKeyString)}
 			var contractErr *foundation.Error
 			data, _ := core.MarshalArgs(result, contractErr)
 			return &reply.CallMethod{
@@ -158,3 +277,86 @@ func TestTimeoutSuite(t *testing.T) {
 
 	timeoutSuite.api.Stop(timeoutSuite.ctx)
 }
+
+func TestRunner_verifySignatureAfterKeyRotation(t *testing.T) {
+	ctx := inslogger.TestContext(t)
+	ks := platformpolicy.NewKeyProcessor()
+	newKeys := func() (crypto.PrivateKey, string) {
+		sKey, err := ks.GeneratePrivateKey()
+		require.NoError(t, err)
+		pKeyString, err := ks.ExportPublicKeyPEM(ks.ExtractPublicKey(sKey))
+		require.NoError(t, err)
+		return sKey, string(pKeyString)
+	}
+	oldKey, oldPub := newKeys()
+	newKey, newPub := newKeys()
+
+	currentPub := oldPub
+	requests := 0
+	cr := testutils.NewContractRequesterMock(t)
+	cr.SendRequestFunc = func(p context.Context, p1 *core.RecordRef, method string, p3 []interface{}) (core.Reply, error) {
+		requests++
+		var contractErr *foundation.Error
+		data, _ := core.MarshalArgs([]string{currentPub}, contractErr)
+		return &reply.CallMethod{Result: data}, nil
+	}
+
+	cfg := configuration.NewAPIRunner()
+	runner, err := NewRunner(&cfg)
+	require.NoError(t, err)
+	runner.ContractRequester = cr
+	runner.keyRefreshInterval = time.Hour
+
+	ref := testutils.RandomRef()
+	sign := func(key crypto.PrivateKey) Request {
+		args, err := core.MarshalArgs(ref, "GetMyBalance", []byte{}, []byte("seed"))
+		require.NoError(t, err)
+		signature, err := scheme.Signer(key).Sign(args)
+		require.NoError(t, err)
+		return Request{
+			Reference: ref.String(),
+			Method:    "GetMyBalance",
+			Params:    []byte{},
+			Seed:      []byte("seed"),
+			Signature: signature.Bytes(),
+		}
+	}
+
+	require.NoError(t, runner.verifySignature(ctx, sign(oldKey)))
+	require.NoError(t, runner.verifySignature(ctx, sign(oldKey)))
+	require.Equal(t, 1, requests)
+
+	// key is rotated through another node, cached key is outdated, but keys were requested recently
+	currentPub = newPub
+	require.Error(t, runner.verifySignature(ctx, sign(newKey)))
+	require.Error(t, runner.verifySignature(ctx, sign(newKey)))
+	require.Equal(t, 1, requests)
+
+	// keys are requested again when refresh interval passes
+	runner.keyRefreshInterval = 0
+	require.NoError(t, runner.verifySignature(ctx, sign(newKey)))
+	require.Equal(t, 2, requests)
+	require.Error(t, runner.verifySignature(ctx, sign(oldKey)))
+}
+
+func TestRunner_invalidateKeysAfterCall(t *testing.T) {
+	cfg := configuration.NewAPIRunner()
+	runner, err := NewRunner(&cfg)
+	require.NoError(t, err)
+
+	caller := testutils.RandomRef().String()
+	target := testutils.RandomRef().String()
+	runner.keyCache[caller] = []crypto.PublicKey{}
+	runner.keyCache[target] = []crypto.PublicKey{}
+
+	params, err := core.MarshalArgs(target, "new key")
+	require.NoError(t, err)
+	runner.invalidateKeysAfterCall(Request{Reference: caller, Method: "RevokeKey", Params: params})
+	require.Contains(t, runner.keyCache, caller)
+	require.NotContains(t, runner.keyCache, target)
+
+	params, err = core.MarshalArgs("new key")
+	require.NoError(t, err)
+	runner.invalidateKeysAfterCall(Request{Reference: caller, Method: "RotateKey", Params: params})
+	require.NotContains(t, runner.keyCache, caller)
+}
@@ -0,0 +1,199 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/core/utils"
+	"github.com/insolar/insolar/instrumentation/inslogger"
+	"github.com/pkg/errors"
+)
+
+// exportFlushSize is a number of exported records after which response is flushed.
+const exportFlushSize = 100
+
+var exportTypes = map[string]bool{
+	core.StorageExportTypeGenesis:    true,
+	core.StorageExportTypeChild:      true,
+	core.StorageExportTypeJet:        true,
+	core.StorageExportTypeRequest:    true,
+	core.StorageExportTypeResult:     true,
+	core.StorageExportTypeType:       true,
+	core.StorageExportTypeCode:       true,
+	core.StorageExportTypeActivate:   true,
+	core.StorageExportTypeAmend:      true,
+	core.StorageExportTypeDeactivate: true,
+}
+
+// queryList returns values of query parameter, values could be repeated or separated by comma.
+func queryList(values []string) []string {
+	var res []string
+	for _, list := range values {
+		for _, v := range strings.Split(list, ",") {
+			if v != "" {
+				res = append(res, v)
+			}
+		}
+	}
+	return res
+}
+
+func parseExportFilter(req *http.Request) (core.StorageExportFilter, error) {
+	query := req.URL.Query()
+	filter := core.StorageExportFilter{}
+	for _, t := range queryList(query["type"]) {
+		if !exportTypes[t] {
+			return filter, errors.New("unknown record type " + t)
+		}
+		filter.Types = append(filter.Types, t)
+	}
+	for _, o := range queryList(query["object"]) {
+		ref, err := core.NewRefFromBase58(o)
+		if err != nil {
+			return filter, errors.Wrap(err, "bad object reference "+o)
+		}
+		filter.Objects = append(filter.Objects, *ref.Record())
+	}
+	for _, p := range queryList(query["prototype"]) {
+		ref, err := core.NewRefFromBase58(p)
+		if err != nil {
+			return filter, errors.Wrap(err, "bad prototype reference "+p)
+		}
+		filter.Prototypes = append(filter.Prototypes, *ref)
+	}
+	for _, j := range queryList(query["jet"]) {
+		id, err := core.NewIDFromBase58(j)
+		if err != nil {
+			return filter, errors.Wrap(err, "bad jet id "+j)
+		}
+		filter.Jets = append(filter.Jets, *id)
+	}
+	return filter, nil
+}
+
+// exportStreamHandler streams ledger records as newline delimited JSON in core.StorageExportRecord schema.
+//
+//   Query parameters:
+//     cursor    - opaque cursor from previous export, export starts from the first pulse if omitted;
+//     type      - record types (e.g. "activate,amend"), all types if omitted;
+//     object    - object references, records of all objects if omitted;
+//     prototype - prototype references, records of all prototypes if omitted;
+//     jet       - jet IDs, all jets if omitted;
+//     limit     - maximum number of records, no limit if omitted;
+//     follow    - if "true", new records are streamed as their pulses become exportable until client disconnects.
+//
+// Every line is a record with "cursor" export can be resumed from. Lines of "checkpoint" type carry only cursor
+// and are sent when pulses without matching records are passed.
+func (ar *Runner) exportStreamHandler() func(http.ResponseWriter, *http.Request) {
+	return func(response http.ResponseWriter, req *http.Request) {
+		traceID := utils.RandTraceID()
+		ctx, insLog := inslogger.WithTraceField(context.Background(), traceID)
+
+		insLog.Infof("[ exportStreamHandler ] Incoming request: %s", req.RequestURI)
+
+		if ar.StreamExporter == nil {
+			http.Error(response, "[ exportStreamHandler ] Export is not supported", http.StatusNotImplemented)
+			return
+		}
+		flusher, ok := response.(http.Flusher)
+		if !ok {
+			http.Error(response, "[ exportStreamHandler ] Streaming is not supported", http.StatusInternalServerError)
+			return
+		}
+
+		filter, err := parseExportFilter(req)
+		if err != nil {
+			http.Error(response, "[ exportStreamHandler ] "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		query := req.URL.Query()
+		limit := 0
+		if l := query.Get("limit"); l != "" {
+			limit, err = strconv.Atoi(l)
+			if err != nil || limit < 0 {
+				http.Error(response, "[ exportStreamHandler ] Bad limit: "+l, http.StatusBadRequest)
+				return
+			}
+		}
+		follow := query.Get("follow") == "true"
+		cursor := query.Get("cursor")
+
+		response.Header().Set("Content-Type", "application/x-ndjson")
+		response.Header().Set("Cache-Control", "no-cache")
+
+		encoder := json.NewEncoder(response)
+		sent := 0
+		lastCursor := cursor
+		handler := func(rec *core.StorageExportRecord) error {
+			if err := encoder.Encode(rec); err != nil {
+				return err
+			}
+			lastCursor = rec.Cursor
+			sent++
+			if sent%exportFlushSize == 0 {
+				flusher.Flush()
+			}
+			return nil
+		}
+
+		for {
+			left := 0
+			if limit > 0 {
+				left = limit - sent
+			}
+			next, err := ar.StreamExporter.ExportStream(ctx, cursor, filter, left, handler)
+			if err != nil {
+				if sent == 0 && cursor == query.Get("cursor") {
+					// Nothing is written yet, so error can be reported with status.
+					http.Error(response, "[ exportStreamHandler ] "+err.Error(), http.StatusInternalServerError)
+					return
+				}
+				insLog.Error("[ exportStreamHandler ] Export failed: ", err)
+				return
+			}
+			if next != lastCursor {
+				err := encoder.Encode(&core.StorageExportRecord{
+					SchemaVersion: core.StorageExportSchemaVersion,
+					Cursor:        next,
+					Type:          core.StorageExportTypeCheckpoint,
+				})
+				if err != nil {
+					return
+				}
+				lastCursor = next
+			}
+			flusher.Flush()
+			cursor = next
+
+			if !follow || (limit > 0 && sent >= limit) {
+				return
+			}
+			select {
+			case <-req.Context().Done():
+				return
+			case <-time.After(ar.cfg.ExportPollInterval):
+			}
+		}
+	}
+}
@@ -0,0 +1,87 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/insolar/insolar/configuration"
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type streamExporterFunc func(
+	ctx context.Context, cursor string, filter core.StorageExportFilter, limit int, handler func(*core.StorageExportRecord) error,
+) (string, error)
+
+func (f streamExporterFunc) ExportStream(
+	ctx context.Context, cursor string, filter core.StorageExportFilter, limit int, handler func(*core.StorageExportRecord) error,
+) (string, error) {
+	return f(ctx, cursor, filter, limit, handler)
+}
+
+func TestRunner_exportStreamHandler(t *testing.T) {
+	cfg := configuration.NewAPIRunner()
+	ar, err := NewRunner(&cfg)
+	require.NoError(t, err)
+
+	object := testutils.RandomRef()
+	var requested core.StorageExportFilter
+	ar.StreamExporter = streamExporterFunc(func(
+		ctx context.Context, cursor string, filter core.StorageExportFilter, limit int, handler func(*core.StorageExportRecord) error,
+	) (string, error) {
+		requested = filter
+		assert.Equal(t, "start", cursor)
+		assert.Equal(t, 10, limit)
+		err := handler(&core.StorageExportRecord{Cursor: "first", Type: core.StorageExportTypeActivate})
+		return "end", err
+	})
+
+	server := httptest.NewServer(http.HandlerFunc(ar.exportStreamHandler()))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "?cursor=start&limit=10&type=activate,amend&object=" + object.String())
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []string{core.StorageExportTypeActivate, core.StorageExportTypeAmend}, requested.Types)
+	assert.Equal(t, []core.RecordID{*object.Record()}, requested.Objects)
+
+	var lines []core.StorageExportRecord
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		var rec core.StorageExportRecord
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &rec))
+		lines = append(lines, rec)
+	}
+	require.Len(t, lines, 2)
+	assert.Equal(t, "first", lines[0].Cursor)
+	assert.Equal(t, core.StorageExportTypeCheckpoint, lines[1].Type)
+	assert.Equal(t, "end", lines[1].Cursor)
+
+	resp, err = http.Get(server.URL + "?type=unknown")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
@@ -0,0 +1,191 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/core/utils"
+	"github.com/insolar/insolar/instrumentation/inslogger"
+	"github.com/pkg/errors"
+)
+
+// HistoryArgs is arguments that History service accepts.
+type HistoryArgs struct {
+	Reference string
+	// Pulse limits history to states created before or during this pulse. Zero means no limit.
+	Pulse uint32
+	// Limit is max number of returned states. Zero means no limit.
+	Limit int
+}
+
+// HistoryState is a single object state.
+type HistoryState struct {
+	State       string
+	Pulse       uint32
+	Request     string
+	Prototype   string
+	IsPrototype bool
+	Memory      []byte
+	Approved    bool
+	Deactivated bool
+}
+
+// HistoryReply is reply for History service requests.
+type HistoryReply struct {
+	States []HistoryState
+}
+
+// HistoryStateReply is reply for History service requests for a single state.
+type HistoryStateReply struct {
+	State *HistoryState
+}
+
+// HistoryService is a service that provides API for getting object history.
+type HistoryService struct {
+	runner *Runner
+}
+
+// NewHistoryService creates new History service instance.
+func NewHistoryService(runner *Runner) *HistoryService {
+	return &HistoryService{runner: runner}
+}
+
+// Get returns object states from the latest to the activation.
+//
+//   Request structure:
+//   {
+//     "jsonrpc": "2.0",
+//     "method": "history.Get",
+//     "params": {
+//       "Reference": str, // Object reference.
+//       "Pulse": int, // If not zero, states created after this pulse are skipped.
+//       "Limit": int // If not zero, at most this number of states is returned.
+//     },
+//     "id": str|int|null
+//   }
+//
+//   Response structure:
+//   {
+//     "jsonrpc": "2.0",
+//     "result": {
+//       "States": [{
+//         "State": str, // State record ID.
+//         "Pulse": int, // Pulse number state was created in.
+//         "Request": str, // Reference to request that produced the state.
+//         "Prototype": str, // Prototype (or code for prototypes) reference. Empty for deactivation.
+//         "IsPrototype": bool,
+//         "Memory": str, // Base64 encoded object memory.
+//         "Approved": bool, // True if the state is validated.
+//         "Deactivated": bool // True for deactivation state.
+//       }]
+//     },
+//     "id": str|int|null // same as in request
+//   }
+//
+func (s *HistoryService) Get(r *http.Request, args *HistoryArgs, reply *HistoryReply) error {
+	ctx, inslog := inslogger.WithTraceField(context.Background(), utils.RandTraceID())
+
+	inslog.Infof("[ HistoryService.Get ] Incoming request: %s", r.RequestURI)
+
+	states, err := s.history(ctx, args.Reference, args.Pulse, args.Limit)
+	if err != nil {
+		return errors.Wrap(err, "[ HistoryService.Get ]")
+	}
+
+	reply.States = make([]HistoryState, 0, len(states))
+	for _, state := range states {
+		reply.States = append(reply.States, newHistoryState(state))
+	}
+	return nil
+}
+
+// GetAsOf returns object state as of provided pulse (e.i. the latest state created before or during this pulse).
+//
+//   Request structure:
+//   {
+//     "jsonrpc": "2.0",
+//     "method": "history.GetAsOf",
+//     "params": {
+//       "Reference": str, // Object reference.
+//       "Pulse": int // Pulse number.
+//     },
+//     "id": str|int|null
+//   }
+//
+//   Response structure:
+//   {
+//     "jsonrpc": "2.0",
+//     "result": {
+//       "State": { ... }|null // Same as history.Get state. Null if object did not exist at this pulse.
+//     },
+//     "id": str|int|null // same as in request
+//   }
+//
+func (s *HistoryService) GetAsOf(r *http.Request, args *HistoryArgs, reply *HistoryStateReply) error {
+	ctx, inslog := inslogger.WithTraceField(context.Background(), utils.RandTraceID())
+
+	inslog.Infof("[ HistoryService.GetAsOf ] Incoming request: %s", r.RequestURI)
+
+	if args.Pulse == 0 {
+		return errors.New("[ HistoryService.GetAsOf ] Pulse must not be empty")
+	}
+	states, err := s.history(ctx, args.Reference, args.Pulse, 1)
+	if err != nil {
+		return errors.Wrap(err, "[ HistoryService.GetAsOf ]")
+	}
+
+	if len(states) > 0 {
+		state := newHistoryState(states[0])
+		reply.State = &state
+	}
+	return nil
+}
+
+func (s *HistoryService) history(
+	ctx context.Context, reference string, pulse uint32, limit int,
+) ([]core.ObjectHistoryState, error) {
+	head, err := core.NewRefFromBase58(reference)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse reference")
+	}
+
+	var asOf *core.PulseNumber
+	if pulse != 0 {
+		pn := core.PulseNumber(pulse)
+		asOf = &pn
+	}
+	return s.runner.ArtifactManager.GetObjectHistory(ctx, *head, asOf, limit)
+}
+
+func newHistoryState(state core.ObjectHistoryState) HistoryState {
+	result := HistoryState{
+		State:       state.State.String(),
+		Pulse:       uint32(state.Pulse),
+		Request:     state.Request.String(),
+		IsPrototype: state.IsPrototype,
+		Memory:      state.Memory,
+		Approved:    state.Approved,
+		Deactivated: state.Deactivated,
+	}
+	if state.Prototype != nil {
+		result.Prototype = state.Prototype.String()
+	}
+	return result
+}
@@ -0,0 +1,85 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/insolar/insolar/configuration"
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistoryService(t *testing.T) {
+	cfg := configuration.NewAPIRunner()
+	ar, err := NewRunner(&cfg)
+	require.NoError(t, err)
+
+	head := testutils.RandomRef()
+	prototype := testutils.RandomRef()
+	state := core.ObjectHistoryState{
+		State:     testutils.RandomID(),
+		Pulse:     core.FirstPulseNumber,
+		Request:   testutils.RandomRef(),
+		Prototype: &prototype,
+		Memory:    []byte{1, 2, 3},
+		Approved:  true,
+	}
+
+	am := testutils.NewArtifactManagerMock(t)
+	am.GetObjectHistoryFunc = func(ctx context.Context, ref core.RecordRef, pulse *core.PulseNumber, limit int) ([]core.ObjectHistoryState, error) {
+		assert.Equal(t, head, ref)
+		if pulse != nil {
+			assert.Equal(t, core.PulseNumber(core.FirstPulseNumber+1), *pulse)
+			assert.Equal(t, 1, limit)
+		} else {
+			assert.Equal(t, 10, limit)
+		}
+		return []core.ObjectHistoryState{state}, nil
+	}
+	ar.ArtifactManager = am
+	service := NewHistoryService(ar)
+	req := &http.Request{}
+
+	var reply HistoryReply
+	err = service.Get(req, &HistoryArgs{Reference: head.String(), Limit: 10}, &reply)
+	require.NoError(t, err)
+	require.Len(t, reply.States, 1)
+	assert.Equal(t, HistoryState{
+		State:     state.State.String(),
+		Pulse:     uint32(state.Pulse),
+		Request:   state.Request.String(),
+		Prototype: prototype.String(),
+		Memory:    state.Memory,
+		Approved:  true,
+	}, reply.States[0])
+
+	var stateReply HistoryStateReply
+	err = service.GetAsOf(req, &HistoryArgs{Reference: head.String(), Pulse: core.FirstPulseNumber + 1}, &stateReply)
+	require.NoError(t, err)
+	require.NotNil(t, stateReply.State)
+	assert.Equal(t, reply.States[0], *stateReply.State)
+
+	err = service.GetAsOf(req, &HistoryArgs{Reference: head.String()}, &stateReply)
+	assert.Error(t, err)
+	err = service.Get(req, &HistoryArgs{Reference: "invalid"}, &reply)
+	assert.Error(t, err)
+}
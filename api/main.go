@@ -40,21 +40,30 @@ import (
 
 // Runner implements Component for API
 type Runner struct {
-	CertificateManager  core.CertificateManager  `inject:""`
-	StorageExporter     core.StorageExporter     `inject:""`
-	ContractRequester   core.ContractRequester   `inject:""`
-	NetworkCoordinator  core.NetworkCoordinator  `inject:""`
-	GenesisDataProvider core.GenesisDataProvider `inject:""`
-	NetworkSwitcher     core.NetworkSwitcher     `inject:""`
-	NodeNetwork         core.NodeNetwork         `inject:""`
-	PulseStorage        core.PulseStorage        `inject:""`
+	CertificateManager  core.CertificateManager    `inject:""`
+	ArtifactManager     core.ArtifactManager       `inject:""`
+	StorageExporter     core.StorageExporter       `inject:""`
+	StorageSnapshotter  core.StorageSnapshotter    `inject:""`
+	StreamExporter      core.StorageStreamExporter `inject:""`
+	JetBalancer         core.JetBalancer           `inject:""`
+	ContractRequester   core.ContractRequester     `inject:""`
+	NetworkCoordinator  core.NetworkCoordinator    `inject:""`
+	GenesisDataProvider core.GenesisDataProvider   `inject:""`
+	NetworkSwitcher     core.NetworkSwitcher       `inject:""`
+	NodeNetwork         core.NodeNetwork           `inject:""`
+	PulseStorage        core.PulseStorage          `inject:""`
+	MessageBus          core.MessageBus            `inject:""`
 	server              *http.Server
 	rpcServer           *rpc.Server
 	cfg                 *configuration.APIRunner
-	keyCache            map[string]crypto.PublicKey
+	keyCache            map[string][]crypto.PublicKey
+	keyFetched          map[string]time.Time
+	keyRefreshInterval  time.Duration
 	cacheLock           *sync.RWMutex
 	SeedManager         *seedmanager.SeedManager
 	SeedGenerator       seedmanager.SeedGenerator
+	streams             *streamHub
+	stopWatcher         chan struct{}
 }
 
 func checkConfig(cfg *configuration.APIRunner) error {
@@ -73,6 +82,9 @@ func checkConfig(cfg *configuration.APIRunner) error {
 	if cfg.Timeout == 0 {
 		return errors.New("[ checkConfig ] Timeout must not be null")
 	}
+	if (cfg.Backup != "" || cfg.Replay != "") && cfg.AdminToken == "" {
+		return errors.New("[ checkConfig ] AdminToken must be set if Backup or Replay is enabled")
+	}
 
 	return nil
 }
@@ -103,6 +115,21 @@ func (ar *Runner) registerServices(rpcServer *rpc.Server) error {
 		return errors.New("[ registerServices ] Can't RegisterService: cert")
 	}
 
+	err = rpcServer.RegisterService(NewHistoryService(ar), "history")
+	if err != nil {
+		return errors.New("[ registerServices ] Can't RegisterService: history")
+	}
+
+	err = rpcServer.RegisterService(NewObjectsService(ar), "objects")
+	if err != nil {
+		return errors.New("[ registerServices ] Can't RegisterService: objects")
+	}
+
+	err = rpcServer.RegisterService(NewProofService(ar), "proof")
+	if err != nil {
+		return errors.New("[ registerServices ] Can't RegisterService: proof")
+	}
+
 	return nil
 }
 
@@ -119,8 +146,12 @@ func NewRunner(cfg *configuration.APIRunner) (*Runner, error) {
 		server:    &http.Server{Addr: addrStr},
 		rpcServer: rpcServer,
 		cfg:       cfg,
-		keyCache:  make(map[string]crypto.PublicKey),
+		keyCache:  make(map[string][]crypto.PublicKey),
 		cacheLock: &sync.RWMutex{},
+		streams:   newStreamHub(),
+
+		keyFetched:         make(map[string]time.Time),
+		keyRefreshInterval: defaultKeyRefreshInterval,
 	}
 
 	rpcServer.RegisterCodec(jsonrpc.NewCodec(), "application/json")
@@ -141,6 +172,25 @@ func (ar *Runner) IsAPIRunner() bool {
 func (ar *Runner) Start(ctx context.Context) error {
 	ar.SeedManager = seedmanager.New()
 	http.HandleFunc(ar.cfg.Call, ar.callHandler())
+	if ar.cfg.BatchCall != "" {
+		http.HandleFunc(ar.cfg.BatchCall, ar.batchCallHandler())
+	}
+	if ar.cfg.Stream != "" {
+		http.HandleFunc(ar.cfg.Stream, ar.streamHandler())
+		// Streams are endless, so they must be closed for graceful shutdown.
+		ar.server.RegisterOnShutdown(ar.streams.close)
+		ar.stopWatcher = make(chan struct{})
+		go ar.watchNetwork(ctx, ar.stopWatcher)
+	}
+	if ar.cfg.Backup != "" {
+		http.HandleFunc(ar.cfg.Backup, ar.adminHandler(ar.backupHandler()))
+	}
+	if ar.cfg.ExportStream != "" {
+		http.HandleFunc(ar.cfg.ExportStream, ar.exportStreamHandler())
+	}
+	if ar.cfg.Replay != "" {
+		http.HandleFunc(ar.cfg.Replay, ar.adminHandler(ar.replayHandler()))
+	}
 	http.Handle(ar.cfg.RPC, ar.rpcServer)
 	inslog := inslogger.FromContext(ctx)
 	inslog.Info("Starting ApiRunner ...")
@@ -161,6 +211,11 @@ func (ar *Runner) Start(ctx context.Context) error {
 func (ar *Runner) Stop(ctx context.Context) error {
 	const timeOut = 5
 
+	if ar.stopWatcher != nil {
+		close(ar.stopWatcher)
+		ar.stopWatcher = nil
+	}
+
 	inslogger.FromContext(ctx).Infof("Shutting down server gracefully ...(waiting for %d seconds)", timeOut)
 	ctxWithTimeout, cancel := context.WithTimeout(ctx, time.Duration(timeOut)*time.Second)
 	defer cancel()
@@ -172,36 +227,69 @@ func (ar *Runner) Stop(ctx context.Context) error {
 	return nil
 }
 
-func (ar *Runner) getMemberPubKey(ctx context.Context, ref string) (crypto.PublicKey, error) { //nolint
+// defaultKeyRefreshInterval is a minimal interval between requests of member keys caused by invalid signatures.
+const defaultKeyRefreshInterval = 10 * time.Second
+
+// invalidateMemberPubKeys drops cached keys of member, they are requested again on the next call
+func (ar *Runner) invalidateMemberPubKeys(ref string) bool {
+	ar.cacheLock.Lock()
+	defer ar.cacheLock.Unlock()
+	_, ok := ar.keyCache[ref]
+	delete(ar.keyCache, ref)
+	delete(ar.keyFetched, ref)
+	return ok
+}
+
+// refreshMemberPubKeys drops cached keys of member if they were requested more than keyRefreshInterval ago,
+// so requests with invalid signatures can't make node request keys on every call
+func (ar *Runner) refreshMemberPubKeys(ref string) bool {
+	ar.cacheLock.Lock()
+	defer ar.cacheLock.Unlock()
+	if time.Since(ar.keyFetched[ref]) < ar.keyRefreshInterval {
+		return false
+	}
+	_, ok := ar.keyCache[ref]
+	delete(ar.keyCache, ref)
+	delete(ar.keyFetched, ref)
+	return ok
+}
+
+// getMemberPubKeys returns keys which can sign requests of member, multisig members have several keys
+func (ar *Runner) getMemberPubKeys(ctx context.Context, ref string) ([]crypto.PublicKey, error) { //nolint
 	ar.cacheLock.RLock()
-	publicKey, ok := ar.keyCache[ref]
+	publicKeys, ok := ar.keyCache[ref]
 	ar.cacheLock.RUnlock()
 	if ok {
-		return publicKey, nil
+		return publicKeys, nil
 	}
 
 	reference, err := core.NewRefFromBase58(ref)
 	if err != nil {
-		return nil, errors.Wrap(err, "[ getMemberPubKey ] Can't parse ref")
+		return nil, errors.Wrap(err, "[ getMemberPubKeys ] Can't parse ref")
 	}
-	res, err := ar.ContractRequester.SendRequest(ctx, reference, "GetPublicKey", []interface{}{})
+	res, err := ar.ContractRequester.SendRequest(ctx, reference, "GetPublicKeys", []interface{}{})
 	if err != nil {
-		return nil, errors.Wrap(err, "[ getMemberPubKey ] Can't get public key")
+		return nil, errors.Wrap(err, "[ getMemberPubKeys ] Can't get public key")
 	}
 
-	publicKeyString, err := extractor.PublicKeyResponse(res.(*reply.CallMethod).Result)
+	publicKeyStrings, err := extractor.PublicKeysResponse(res.(*reply.CallMethod).Result)
 	if err != nil {
-		return nil, errors.Wrap(err, "[ getMemberPubKey ] Can't extract response")
+		return nil, errors.Wrap(err, "[ getMemberPubKeys ] Can't extract response")
 	}
 
 	kp := platformpolicy.NewKeyProcessor()
-	publicKey, err = kp.ImportPublicKeyPEM([]byte(publicKeyString))
-	if err != nil {
-		return nil, errors.Wrap(err, "Failed to convert public key")
+	publicKeys = make([]crypto.PublicKey, 0, len(publicKeyStrings))
+	for _, publicKeyString := range publicKeyStrings {
+		publicKey, err := kp.ImportPublicKeyPEM([]byte(publicKeyString))
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to convert public key")
+		}
+		publicKeys = append(publicKeys, publicKey)
 	}
 
 	ar.cacheLock.Lock()
-	ar.keyCache[ref] = publicKey
+	ar.keyCache[ref] = publicKeys
+	ar.keyFetched[ref] = time.Now()
 	ar.cacheLock.Unlock()
-	return publicKey, nil
+	return publicKeys, nil
 }
@@ -0,0 +1,164 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/core/utils"
+	"github.com/insolar/insolar/instrumentation/inslogger"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultObjectsLimit = 1000
+	// maxObjectsLimit bounds number of objects in one reply, so a request can't make heavy node read whole index.
+	maxObjectsLimit = 10000
+)
+
+// ObjectsArgs is arguments that Objects service accepts.
+type ObjectsArgs struct {
+	Prototype string
+	// From is an object id to start listing from (NextFrom of previous reply). Empty means from the first object.
+	From string
+	// Limit is max number of returned objects. It is capped by maxObjectsLimit.
+	Limit int
+}
+
+// ObjectsReply is reply for Objects service list requests.
+type ObjectsReply struct {
+	Objects  []string
+	NextFrom string
+}
+
+// ObjectsCountReply is reply for Objects service count requests.
+type ObjectsCountReply struct {
+	Count int
+}
+
+// ObjectsService is a service that provides API for getting active objects of a prototype.
+type ObjectsService struct {
+	runner *Runner
+}
+
+// NewObjectsService creates new Objects service instance.
+func NewObjectsService(runner *Runner) *ObjectsService {
+	return &ObjectsService{runner: runner}
+}
+
+// List returns references of active objects of provided prototype.
+//
+//   Request structure:
+//   {
+//     "jsonrpc": "2.0",
+//     "method": "objects.List",
+//     "params": {
+//       "Prototype": str, // Prototype reference.
+//       "From": str, // Object id to start from. Use "NextFrom" of previous reply to fetch the next chunk.
+//       "Limit": int // Max number of objects in reply (at most 10000).
+//     },
+//     "id": str|int|null
+//   }
+//
+//   Response structure:
+//   {
+//     "jsonrpc": "2.0",
+//     "result": {
+//       "Objects": [str], // Object references.
+//       "NextFrom": str // Object id to continue from. Empty if there are no more objects.
+//     },
+//     "id": str|int|null // same as in request
+//   }
+//
+func (s *ObjectsService) List(r *http.Request, args *ObjectsArgs, reply *ObjectsReply) error {
+	ctx, inslog := inslogger.WithTraceField(context.Background(), utils.RandTraceID())
+
+	inslog.Infof("[ ObjectsService.List ] Incoming request: %s", r.RequestURI)
+
+	prototype, err := core.NewRefFromBase58(args.Prototype)
+	if err != nil {
+		return errors.Wrap(err, "[ ObjectsService.List ] failed to parse args.Prototype")
+	}
+	var from *core.RecordID
+	if args.From != "" {
+		from, err = core.NewIDFromBase58(args.From)
+		if err != nil {
+			return errors.Wrap(err, "[ ObjectsService.List ] failed to parse args.From")
+		}
+	}
+	limit := args.Limit
+	if limit <= 0 {
+		limit = defaultObjectsLimit
+	}
+	if limit > maxObjectsLimit {
+		limit = maxObjectsLimit
+	}
+
+	refs, next, err := s.runner.ArtifactManager.GetPrototypeObjects(ctx, *prototype, from, limit)
+	if err != nil {
+		return errors.Wrap(err, "[ ObjectsService.List ]")
+	}
+
+	reply.Objects = make([]string, 0, len(refs))
+	for _, ref := range refs {
+		reply.Objects = append(reply.Objects, ref.String())
+	}
+	if next != nil {
+		reply.NextFrom = next.String()
+	}
+	return nil
+}
+
+// Count returns number of active objects of provided prototype.
+//
+//   Request structure:
+//   {
+//     "jsonrpc": "2.0",
+//     "method": "objects.Count",
+//     "params": {
+//       "Prototype": str // Prototype reference.
+//     },
+//     "id": str|int|null
+//   }
+//
+//   Response structure:
+//   {
+//     "jsonrpc": "2.0",
+//     "result": {
+//       "Count": int
+//     },
+//     "id": str|int|null // same as in request
+//   }
+//
+func (s *ObjectsService) Count(r *http.Request, args *ObjectsArgs, reply *ObjectsCountReply) error {
+	ctx, inslog := inslogger.WithTraceField(context.Background(), utils.RandTraceID())
+
+	inslog.Infof("[ ObjectsService.Count ] Incoming request: %s", r.RequestURI)
+
+	prototype, err := core.NewRefFromBase58(args.Prototype)
+	if err != nil {
+		return errors.Wrap(err, "[ ObjectsService.Count ] failed to parse args.Prototype")
+	}
+
+	reply.Count, err = s.runner.ArtifactManager.CountPrototypeObjects(ctx, *prototype)
+	if err != nil {
+		return errors.Wrap(err, "[ ObjectsService.Count ]")
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/insolar/insolar/configuration"
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObjectsService(t *testing.T) {
+	cfg := configuration.NewAPIRunner()
+	ar, err := NewRunner(&cfg)
+	require.NoError(t, err)
+
+	prototype := testutils.RandomRef()
+	objects := []core.RecordRef{testutils.RandomRef(), testutils.RandomRef()}
+	from := testutils.RandomID()
+	next := testutils.RandomID()
+
+	am := testutils.NewArtifactManagerMock(t)
+	am.GetPrototypeObjectsFunc = func(
+		ctx context.Context, p core.RecordRef, f *core.RecordID, limit int,
+	) ([]core.RecordRef, *core.RecordID, error) {
+		assert.Equal(t, prototype, p)
+		if f == nil {
+			assert.Equal(t, defaultObjectsLimit, limit)
+			return objects, &next, nil
+		}
+		assert.Equal(t, from, *f)
+		if limit != 1 {
+			assert.Equal(t, maxObjectsLimit, limit)
+		}
+		return objects[:1], nil, nil
+	}
+	am.CountPrototypeObjectsFunc = func(ctx context.Context, p core.RecordRef) (int, error) {
+		assert.Equal(t, prototype, p)
+		return 42, nil
+	}
+	ar.ArtifactManager = am
+	service := NewObjectsService(ar)
+	req := &http.Request{}
+
+	var reply ObjectsReply
+	err = service.List(req, &ObjectsArgs{Prototype: prototype.String()}, &reply)
+	require.NoError(t, err)
+	assert.Equal(t, []string{objects[0].String(), objects[1].String()}, reply.Objects)
+	assert.Equal(t, next.String(), reply.NextFrom)
+
+	reply = ObjectsReply{}
+	err = service.List(req, &ObjectsArgs{Prototype: prototype.String(), From: from.String(), Limit: 1}, &reply)
+	require.NoError(t, err)
+	assert.Equal(t, []string{objects[0].String()}, reply.Objects)
+	assert.Empty(t, reply.NextFrom)
+
+	reply = ObjectsReply{}
+	err = service.List(req, &ObjectsArgs{Prototype: prototype.String(), From: from.String(), Limit: maxObjectsLimit + 1}, &reply)
+	require.NoError(t, err)
+	assert.Equal(t, []string{objects[0].String()}, reply.Objects)
+
+	var countReply ObjectsCountReply
+	err = service.Count(req, &ObjectsArgs{Prototype: prototype.String()}, &countReply)
+	require.NoError(t, err)
+	assert.Equal(t, 42, countReply.Count)
+
+	err = service.List(req, &ObjectsArgs{Prototype: "invalid"}, &reply)
+	assert.Error(t, err)
+}
@@ -0,0 +1,155 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"sort"
+
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/core/utils"
+	"github.com/insolar/insolar/instrumentation/inslogger"
+	"github.com/pkg/errors"
+)
+
+// ProofArgs is arguments that Proof service accepts.
+type ProofArgs struct {
+	Record string
+}
+
+// ProofStep is a sibling hash on the path from record to records root of jet drop.
+type ProofStep struct {
+	Hash []byte
+	Left bool // If true, sibling is the left node.
+}
+
+// PulseSign is a confirmation of pulse by pulsar.
+type PulseSign struct {
+	PublicKey       string
+	ChosenPublicKey string
+	Signature       []byte
+}
+
+// DropSign is a confirmation of jet drop by jet executor.
+type DropSign struct {
+	Node      string
+	Signature []byte
+}
+
+// ProofReply is reply for Proof service requests.
+type ProofReply struct {
+	Record      []byte
+	ID          string
+	Path        []ProofStep
+	RecordsRoot []byte
+	PrevHash    []byte
+	DropHash    []byte
+	JetPrefix   []byte
+	DropSigns   []DropSign
+	PulseNumber uint32
+	Entropy     []byte
+	Signs       []PulseSign
+}
+
+// ProofService is a service that provides proofs of record inclusion into jet drops.
+type ProofService struct {
+	runner *Runner
+}
+
+// NewProofService creates new Proof service instance.
+func NewProofService(runner *Runner) *ProofService {
+	return &ProofService{runner: runner}
+}
+
+// Get returns proof of record inclusion into jet drop of record pulse.
+//
+// Proof can be checked without trusting the node with VerifyRecordProof from api/sdk.
+//
+//   Request structure:
+//   {
+//     "jsonrpc": "2.0",
+//     "method": "proof.Get",
+//     "params": {
+//       "Record": str // Record id.
+//     },
+//     "id": str|int|null
+//   }
+//
+//   Response structure (binary fields are base64 encoded):
+//   {
+//     "jsonrpc": "2.0",
+//     "result": {
+//       "Record": str, // Serialized record.
+//       "ID": str, // Record id.
+//       "Path": [{"Hash": str, "Left": bool}], // Merkle path from record to records root.
+//       "RecordsRoot": str, // Merkle root of jet drop records.
+//       "PrevHash": str, // Hash of previous jet drop.
+//       "DropHash": str, // Hash of jet drop.
+//       "JetPrefix": str, // Prefix of jet drop jet.
+//       "DropSigns": [{"Node": str, "Signature": str}], // Jet executor confirmations of the drop.
+//       "PulseNumber": int,
+//       "Entropy": str, // Pulse entropy.
+//       "Signs": [{"PublicKey": str, "ChosenPublicKey": str, "Signature": str}] // Pulsar confirmations of the pulse.
+//     },
+//     "id": str|int|null // same as in request
+//   }
+//
+func (s *ProofService) Get(r *http.Request, args *ProofArgs, reply *ProofReply) error {
+	ctx, inslog := inslogger.WithTraceField(context.Background(), utils.RandTraceID())
+
+	inslog.Infof("[ ProofService.Get ] Incoming request: %s", r.RequestURI)
+
+	id, err := core.NewIDFromBase58(args.Record)
+	if err != nil {
+		return errors.Wrap(err, "[ ProofService.Get ] failed to parse args.Record")
+	}
+
+	proof, err := s.runner.ArtifactManager.GetRecordProof(ctx, *id)
+	if err != nil {
+		return errors.Wrap(err, "[ ProofService.Get ]")
+	}
+
+	reply.Record = proof.Record
+	reply.ID = proof.ID.String()
+	reply.Path = make([]ProofStep, 0, len(proof.Path))
+	for _, step := range proof.Path {
+		reply.Path = append(reply.Path, ProofStep{Hash: step.Hash, Left: step.Left})
+	}
+	reply.RecordsRoot = proof.RecordsRoot
+	reply.PrevHash = proof.PrevHash
+	reply.DropHash = proof.DropHash
+	reply.JetPrefix = proof.JetPrefix
+	reply.DropSigns = make([]DropSign, 0, len(proof.Signatures))
+	for _, sign := range proof.Signatures {
+		reply.DropSigns = append(reply.DropSigns, DropSign{Node: sign.Node.String(), Signature: sign.Signature})
+	}
+	reply.PulseNumber = uint32(proof.Pulse.PulseNumber)
+	reply.Entropy = proof.Pulse.Entropy[:]
+	reply.Signs = make([]PulseSign, 0, len(proof.Pulse.Signs))
+	for key, sign := range proof.Pulse.Signs {
+		reply.Signs = append(reply.Signs, PulseSign{
+			PublicKey:       key,
+			ChosenPublicKey: sign.ChosenPublicKey,
+			Signature:       sign.Signature,
+		})
+	}
+	sort.Slice(reply.Signs, func(i, j int) bool {
+		return reply.Signs[i].PublicKey < reply.Signs[j].PublicKey
+	})
+	return nil
+}
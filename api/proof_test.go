@@ -0,0 +1,88 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/insolar/insolar/configuration"
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProofService(t *testing.T) {
+	cfg := configuration.NewAPIRunner()
+	ar, err := NewRunner(&cfg)
+	require.NoError(t, err)
+
+	id := testutils.RandomID()
+	node := testutils.RandomRef()
+	proof := core.RecordProof{
+		Record: []byte{1, 2, 3},
+		ID:     id,
+		Path: []core.MerkleProofStep{
+			{Hash: []byte{4, 5}, Left: true},
+			{Hash: []byte{6, 7}},
+		},
+		RecordsRoot: []byte{8},
+		PrevHash:    []byte{9},
+		DropHash:    []byte{10},
+		JetPrefix:   []byte{14},
+		Signatures:  []core.DropSignature{{Node: node, Signature: []byte{15}}},
+		Pulse: core.Pulse{
+			PulseNumber: core.FirstPulseNumber,
+			Entropy:     core.Entropy{11},
+			Signs: map[string]core.PulseSenderConfirmation{
+				"pulsar2": {ChosenPublicKey: "pulsar1", Signature: []byte{12}},
+				"pulsar1": {ChosenPublicKey: "pulsar1", Signature: []byte{13}},
+			},
+		},
+	}
+
+	am := testutils.NewArtifactManagerMock(t)
+	am.GetRecordProofFunc = func(ctx context.Context, recordID core.RecordID) (*core.RecordProof, error) {
+		assert.Equal(t, id, recordID)
+		return &proof, nil
+	}
+	ar.ArtifactManager = am
+	service := NewProofService(ar)
+
+	var reply ProofReply
+	err = service.Get(&http.Request{}, &ProofArgs{Record: id.String()}, &reply)
+	require.NoError(t, err)
+	assert.Equal(t, proof.Record, reply.Record)
+	assert.Equal(t, id.String(), reply.ID)
+	assert.Equal(t, []ProofStep{{Hash: []byte{4, 5}, Left: true}, {Hash: []byte{6, 7}}}, reply.Path)
+	assert.Equal(t, proof.RecordsRoot, reply.RecordsRoot)
+	assert.Equal(t, proof.PrevHash, reply.PrevHash)
+	assert.Equal(t, proof.DropHash, reply.DropHash)
+	assert.Equal(t, proof.JetPrefix, reply.JetPrefix)
+	assert.Equal(t, []DropSign{{Node: node.String(), Signature: []byte{15}}}, reply.DropSigns)
+	assert.Equal(t, uint32(core.FirstPulseNumber), reply.PulseNumber)
+	assert.Equal(t, proof.Pulse.Entropy[:], reply.Entropy)
+	assert.Equal(t, []PulseSign{
+		{PublicKey: "pulsar1", ChosenPublicKey: "pulsar1", Signature: []byte{13}},
+		{PublicKey: "pulsar2", ChosenPublicKey: "pulsar1", Signature: []byte{12}},
+	}, reply.Signs)
+
+	err = service.Get(&http.Request{}, &ProofArgs{Record: "invalid"}, &reply)
+	assert.Error(t, err)
+}
@@ -0,0 +1,71 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/core/utils"
+	"github.com/insolar/insolar/instrumentation/inslogger"
+	"github.com/insolar/insolar/messagebus"
+)
+
+// replayHandler sends messages from the tape in request body again in order they were recorded. Replies are
+// returned as a tape with the same message hashes, so both tapes can be compared with tapetool.
+func (ar *Runner) replayHandler() http.HandlerFunc {
+	return func(response http.ResponseWriter, req *http.Request) {
+		traceID := utils.RandTraceID()
+		ctx, insLog := inslogger.WithTraceField(context.Background(), traceID)
+
+		insLog.Infof("[ replayHandler ] Incoming request: %s", req.RequestURI)
+
+		if req.Method != http.MethodPost {
+			http.Error(response, "[ replayHandler ] Tape must be posted", http.StatusMethodNotAllowed)
+			return
+		}
+		tape, err := messagebus.ReadFileTape(req.Body)
+		if err != nil {
+			http.Error(response, "[ replayHandler ] Bad tape: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		replayed := messagebus.NewFileTape(tape.Pulse())
+		for _, record := range tape.Records() {
+			if record.Parcel == nil {
+				continue
+			}
+			rep, sendErr := ar.MessageBus.Send(ctx, record.Parcel.Message(), &core.MessageSendOptions{
+				Token: record.Parcel.DelegationToken(),
+			})
+			err = replayed.SetParcel(ctx, record.MsgHash, record.Parcel, rep, sendErr)
+			if err != nil {
+				http.Error(response, "[ replayHandler ] Failed to record reply: "+err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+
+		response.Header().Set("Content-Type", "application/octet-stream")
+		err = replayed.Write(ctx, response)
+		if err != nil {
+			insLog.Error("[ replayHandler ] Failed to write tape: ", err)
+			return
+		}
+		insLog.Infof("[ replayHandler ] %d messages are replayed", len(replayed.Records()))
+	}
+}
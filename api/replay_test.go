@@ -0,0 +1,93 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/insolar/insolar/configuration"
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/core/message"
+	"github.com/insolar/insolar/core/reply"
+	"github.com/insolar/insolar/instrumentation/inslogger"
+	"github.com/insolar/insolar/messagebus"
+	"github.com/insolar/insolar/testutils"
+)
+
+func TestRunner_replayHandler(t *testing.T) {
+	ctx := inslogger.TestContext(t)
+	cfg := configuration.NewAPIRunner()
+	cfg.Replay = "/api/replay"
+	cfg.AdminToken = "secret"
+	ar, err := NewRunner(&cfg)
+	require.NoError(t, err)
+
+	msg := &message.GenesisRequest{Name: "test"}
+	mb := testutils.NewMessageBusMock(t)
+	mb.SendFunc = func(ctx context.Context, m core.Message, ops *core.MessageSendOptions) (core.Reply, error) {
+		assert.Equal(t, msg, m)
+		return &reply.OK{}, nil
+	}
+	ar.MessageBus = mb
+
+	tape := messagebus.NewFileTape(core.FirstPulseNumber)
+	err = tape.SetParcel(ctx, []byte{1}, &message.Parcel{Msg: msg}, &reply.ID{}, nil)
+	require.NoError(t, err)
+	// Records without parcel are skipped.
+	err = tape.Set(ctx, []byte{2}, &reply.OK{}, nil)
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, tape.Write(ctx, &buf))
+
+	server := httptest.NewServer(ar.adminHandler(ar.replayHandler()))
+	defer server.Close()
+
+	resp, err := http.Post(server.URL, "application/octet-stream", bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	req, err := http.NewRequest(http.MethodPost, server.URL, bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer secret")
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	replayed, err := messagebus.ReadFileTape(resp.Body)
+	require.NoError(t, err)
+	records := replayed.Records()
+	require.Equal(t, 1, len(records))
+	assert.Equal(t, []byte{1}, records[0].MsgHash)
+	assert.Equal(t, &reply.OK{}, records[0].Item.Reply)
+	assert.Equal(t, uint64(1), mb.SendCounter)
+}
+
+func TestRunner_ReplayRequiresToken(t *testing.T) {
+	cfg := configuration.NewAPIRunner()
+	cfg.Replay = "/api/replay"
+	_, err := NewRunner(&cfg)
+	require.Error(t, err)
+}
@@ -208,6 +208,20 @@ func Send(ctx context.Context, url string, userCfg *UserConfigJSON, reqCfg *Requ
 	return response, nil
 }
 
+// SendCoSigned sends the same request on behalf of multisig member signed by every co-signer in turn.
+// Every co-signer config must have multisig member reference as caller and one of its keys as private key.
+func SendCoSigned(ctx context.Context, url string, coSigners []*UserConfigJSON, reqCfg *RequestConfigJSON) ([][]byte, error) {
+	responses := make([][]byte, 0, len(coSigners))
+	for i, userCfg := range coSigners {
+		response, err := Send(ctx, url, userCfg, reqCfg)
+		if err != nil {
+			return responses, errors.Wrapf(err, "[ SendCoSigned ] Problem with sending request of co-signer %d", i)
+		}
+		responses = append(responses, response)
+	}
+	return responses, nil
+}
+
 func getDefaultRPCParams(method string) PostParams {
 	return PostParams{
 		"jsonrpc": "2.0",
@@ -267,3 +281,26 @@ func Status(url string) (*StatusResponse, error) {
 
 	return res, nil
 }
+
+// RecordProof makes rpc request to proof.Get method and extracts it
+func RecordProof(url string, record string) (*ProofResponse, error) {
+	params := getDefaultRPCParams("proof.Get")
+	params["params"] = map[string]string{"Record": record}
+
+	body, err := GetResponseBody(url+"/rpc", params)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ RecordProof ]")
+	}
+
+	proofResp := rpcProofResponse{}
+
+	err = json.Unmarshal(body, &proofResp)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ RecordProof ] Can't unmarshal")
+	}
+	if proofResp.Error != nil {
+		return nil, errors.New("[ RecordProof ] Field 'error' is not nil: " + fmt.Sprint(proofResp.Error))
+	}
+
+	return &proofResp.Result, nil
+}
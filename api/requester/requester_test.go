@@ -40,6 +40,14 @@ const TESTSEED = "VGVzdA=="
 var testSeedResponse = seedResponse{Seed: []byte("Test"), TraceID: "testTraceID"}
 var testInfoResponse = InfoResponse{RootMember: "root_member_ref", RootDomain: "root_domain_ref", NodeDomain: "node_domain_ref"}
 var testStatusResponse = StatusResponse{NetworkState: "OK"}
+var testProofResponse = ProofResponse{
+	Record:      []byte{1, 2, 3},
+	ID:          "record_id",
+	Path:        []ProofStepResponse{{Hash: []byte{4, 5}, Left: true}},
+	DropHash:    []byte{6, 7},
+	PulseNumber: 65537,
+	Signs:       []PulseSignResponse{{PublicKey: "pulsar_key", Signature: []byte{8, 9}}},
+}
 
 type rpcRequest struct {
 	RPCVersion string `json:"jsonrpc"`
@@ -98,6 +106,8 @@ func FakeRPCHandler(response http.ResponseWriter, req *http.Request) {
 		answer["result"] = testInfoResponse
 	case "seed.Get":
 		answer["result"] = testSeedResponse
+	case "proof.Get":
+		answer["result"] = testProofResponse
 	}
 	writeReponse(response, answer)
 }
@@ -234,6 +244,17 @@ func TestSend(t *testing.T) {
 	require.Contains(t, string(resp), TESTREFERENCE)
 }
 
+func TestSendCoSigned(t *testing.T) {
+	ctx := inslogger.ContextWithTrace(context.Background(), "TestSendCoSigned")
+	userConf, reqConf := readConfigs(t)
+	resps, err := SendCoSigned(ctx, URL, []*UserConfigJSON{userConf, userConf}, reqConf)
+	require.NoError(t, err)
+	require.Len(t, resps, 2)
+	for _, resp := range resps {
+		require.Contains(t, string(resp), TESTREFERENCE)
+	}
+}
+
 func TestSendWithSeed(t *testing.T) {
 	ctx := inslogger.ContextWithTrace(context.Background(), "TestSendWithSeed")
 	userConf, reqConf := readConfigs(t)
@@ -266,3 +287,9 @@ func TestStatus(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, resp, &testStatusResponse)
 }
+
+func TestRecordProof(t *testing.T) {
+	resp, err := RecordProof(URL, "record_id")
+	require.NoError(t, err)
+	require.Equal(t, resp, &testProofResponse)
+}
@@ -52,3 +52,42 @@ type rpcInfoResponse struct {
 	rpcResponse
 	Result InfoResponse `json:"result"`
 }
+
+// ProofStepResponse represents sibling hash on the path from record to records root
+type ProofStepResponse struct {
+	Hash []byte `json:"Hash"`
+	Left bool   `json:"Left"`
+}
+
+// PulseSignResponse represents confirmation of pulse by pulsar
+type PulseSignResponse struct {
+	PublicKey       string `json:"PublicKey"`
+	ChosenPublicKey string `json:"ChosenPublicKey"`
+	Signature       []byte `json:"Signature"`
+}
+
+// DropSignResponse represents confirmation of jet drop by jet executor
+type DropSignResponse struct {
+	Node      string `json:"Node"`
+	Signature []byte `json:"Signature"`
+}
+
+// ProofResponse represents response from rpc on proof.Get method
+type ProofResponse struct {
+	Record      []byte              `json:"Record"`
+	ID          string              `json:"ID"`
+	Path        []ProofStepResponse `json:"Path"`
+	RecordsRoot []byte              `json:"RecordsRoot"`
+	PrevHash    []byte              `json:"PrevHash"`
+	DropHash    []byte              `json:"DropHash"`
+	JetPrefix   []byte              `json:"JetPrefix"`
+	DropSigns   []DropSignResponse  `json:"DropSigns"`
+	PulseNumber uint32              `json:"PulseNumber"`
+	Entropy     []byte              `json:"Entropy"`
+	Signs       []PulseSignResponse `json:"Signs"`
+}
+
+type rpcProofResponse struct {
+	rpcResponse
+	Result ProofResponse `json:"result"`
+}
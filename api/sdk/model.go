@@ -29,3 +29,19 @@ func NewMember(ref string, key string) *Member {
 		PrivateKey: key,
 	}
 }
+
+// MultiSig model object, Threshold of PrivateKeys co-sign every operation
+type MultiSig struct {
+	Reference   string
+	PrivateKeys []string
+	Threshold   uint
+}
+
+// NewMultiSig creates new MultiSig
+func NewMultiSig(ref string, keys []string, threshold uint) *MultiSig {
+	return &MultiSig{
+		Reference:   ref,
+		PrivateKeys: keys,
+		Threshold:   threshold,
+	}
+}
@@ -0,0 +1,191 @@
+/*
+ *    Copyright 2019 INS Ecosystem
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package sdk
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/insolar/insolar/api/requester"
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/ledger/storage/jet"
+	"github.com/insolar/insolar/ledger/storage/record"
+	"github.com/insolar/insolar/platformpolicy"
+	"github.com/pkg/errors"
+)
+
+// GetRecordProof returns proof of record inclusion into jet drop.
+//
+// Proof should be checked with VerifyRecordProof, node which returned the proof is not trusted.
+func (sdk *SDK) GetRecordProof(recordID string) (*requester.ProofResponse, error) {
+	proof, err := requester.RecordProof(sdk.apiURLs.next(), recordID)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ GetRecordProof ] can't get proof")
+	}
+	return proof, nil
+}
+
+// DefaultDropQuorum requires jet drop to be signed by light executor which built it and heavy executor
+// which stored it.
+const DefaultDropQuorum = 2
+
+// ProofTrust holds keys trusted by VerifyRecordProof.
+type ProofTrust struct {
+	// Pulsars are PEM public keys of trusted pulsars, if set the pulse must be signed by one of them.
+	Pulsars []string
+	// Executors maps references of trusted jet executors to their PEM public keys.
+	Executors map[string]string
+	// Quorum is a number of distinct trusted executors which must sign jet drop, DefaultDropQuorum if not set.
+	Quorum int
+}
+
+// VerifyRecordProof checks that record is included into jet drop confirmed by jet executors.
+//
+// It checks that record id matches record content and pulse, record is a leaf of drop records
+// Merkle tree, drop hash commits to records root and every pulsar signature of the pulse is valid.
+// Pulsar signatures don't cover the drop, so the drop must be signed by a quorum of trusted executors.
+// If trusted pulsars are provided, at least one pulse signature must be made by a trusted pulsar.
+func VerifyRecordProof(proof *requester.ProofResponse, trust ProofTrust) error {
+	scheme := platformpolicy.NewPlatformCryptographyScheme()
+	pulse := core.PulseNumber(proof.PulseNumber)
+
+	id, err := core.NewIDFromBase58(proof.ID)
+	if err != nil {
+		return errors.Wrap(err, "[ VerifyRecordProof ] can't parse record id")
+	}
+	if id.Pulse() != pulse {
+		return errors.New("[ VerifyRecordProof ] record pulse doesn't match proof pulse")
+	}
+	calculated, err := recordID(scheme, pulse, proof.Record)
+	if err != nil {
+		return errors.Wrap(err, "[ VerifyRecordProof ] can't calculate record id")
+	}
+	if *calculated != *id {
+		return errors.New("[ VerifyRecordProof ] record id doesn't match record content")
+	}
+
+	path := make([]core.MerkleProofStep, 0, len(proof.Path))
+	for _, step := range proof.Path {
+		path = append(path, core.MerkleProofStep{Hash: step.Hash, Left: step.Left})
+	}
+	if !jet.VerifyMerkleProof(scheme, proof.Record, path, proof.RecordsRoot) {
+		return errors.New("[ VerifyRecordProof ] record is not included into records root")
+	}
+	if !bytes.Equal(jet.DropHash(scheme, proof.PrevHash, proof.RecordsRoot), proof.DropHash) {
+		return errors.New("[ VerifyRecordProof ] drop hash doesn't match records root")
+	}
+
+	if err := verifyDropSigns(scheme, proof, trust); err != nil {
+		return err
+	}
+	return verifyPulseSigns(scheme, proof, trust.Pulsars)
+}
+
+func verifyDropSigns(scheme core.PlatformCryptographyScheme, proof *requester.ProofResponse, trust ProofTrust) error {
+	quorum := trust.Quorum
+	if quorum <= 0 {
+		quorum = DefaultDropQuorum
+	}
+	if len(trust.Executors) < quorum {
+		return errors.Errorf("[ verifyDropSigns ] %d trusted executors can't make quorum of %d", len(trust.Executors), quorum)
+	}
+
+	data := jet.DropSignData(scheme, proof.JetPrefix, core.PulseNumber(proof.PulseNumber), proof.DropHash)
+	keyProcessor := platformpolicy.NewKeyProcessor()
+	signed := map[string]bool{}
+	for _, sign := range proof.DropSigns {
+		key, ok := trust.Executors[sign.Node]
+		if !ok || signed[sign.Node] {
+			continue
+		}
+		publicKey, err := keyProcessor.ImportPublicKeyPEM([]byte(key))
+		if err != nil {
+			return errors.Wrapf(err, "[ verifyDropSigns ] can't import public key of executor %s", sign.Node)
+		}
+		if !scheme.Verifier(publicKey).Verify(core.SignatureFromBytes(sign.Signature), data) {
+			return errors.Errorf("[ verifyDropSigns ] wrong signature of executor %s", sign.Node)
+		}
+		signed[sign.Node] = true
+	}
+
+	if len(signed) < quorum {
+		return errors.Errorf("[ verifyDropSigns ] drop is signed by %d trusted executors, quorum is %d", len(signed), quorum)
+	}
+	return nil
+}
+
+func recordID(scheme core.PlatformCryptographyScheme, pulse core.PulseNumber, buf []byte) (id *core.RecordID, err error) {
+	if len(buf) < record.TypeIDSize {
+		return nil, errors.New("record is too short")
+	}
+	// Deserialization panics on malformed records.
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Errorf("malformed record: %v", r)
+		}
+	}()
+	return record.NewRecordIDFromRecord(scheme, pulse, record.DeserializeRecord(buf)), nil
+}
+
+func verifyPulseSigns(scheme core.PlatformCryptographyScheme, proof *requester.ProofResponse, trustedPulsars []string) error {
+	if len(proof.Signs) == 0 {
+		return errors.New("[ verifyPulseSigns ] pulse is not signed")
+	}
+	if len(proof.Entropy) != core.EntropySize {
+		return errors.New("[ verifyPulseSigns ] wrong entropy size")
+	}
+
+	trusted := make(map[string]bool, len(trustedPulsars))
+	for _, key := range trustedPulsars {
+		trusted[strings.TrimSpace(key)] = true
+	}
+
+	keyProcessor := platformpolicy.NewKeyProcessor()
+	var signedByTrusted bool
+	for _, sign := range proof.Signs {
+		publicKey, err := keyProcessor.ImportPublicKeyPEM([]byte(sign.PublicKey))
+		if err != nil {
+			return errors.Wrap(err, "[ verifyPulseSigns ] can't import pulsar public key")
+		}
+
+		// Pulsars sign confirmation without signature field.
+		hasher := scheme.IntegrityHasher()
+		_, err = hasher.Write(core.PulseNumber(proof.PulseNumber).Bytes())
+		if err != nil {
+			return errors.Wrap(err, "[ verifyPulseSigns ] can't calculate confirmation hash")
+		}
+		_, err = hasher.Write([]byte(sign.ChosenPublicKey))
+		if err != nil {
+			return errors.Wrap(err, "[ verifyPulseSigns ] can't calculate confirmation hash")
+		}
+		_, err = hasher.Write(proof.Entropy)
+		if err != nil {
+			return errors.Wrap(err, "[ verifyPulseSigns ] can't calculate confirmation hash")
+		}
+		if !scheme.Verifier(publicKey).Verify(core.SignatureFromBytes(sign.Signature), hasher.Sum(nil)) {
+			return errors.New("[ verifyPulseSigns ] wrong pulsar signature")
+		}
+		if trusted[strings.TrimSpace(sign.PublicKey)] {
+			signedByTrusted = true
+		}
+	}
+
+	if len(trusted) > 0 && !signedByTrusted {
+		return errors.New("[ verifyPulseSigns ] pulse is not signed by trusted pulsars")
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package sdk
+
+import (
+	"testing"
+
+	"github.com/insolar/insolar/api/requester"
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/ledger/storage/jet"
+	"github.com/insolar/insolar/ledger/storage/record"
+	"github.com/insolar/insolar/platformpolicy"
+	"github.com/insolar/insolar/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func signedProof(t *testing.T) (*requester.ProofResponse, string, ProofTrust) {
+	scheme := platformpolicy.NewPlatformCryptographyScheme()
+	keyProcessor := platformpolicy.NewKeyProcessor()
+	pulse := core.PulseNumber(core.FirstPulseNumber + 1)
+
+	var records, leaves [][]byte
+	for i := 0; i < 3; i++ {
+		records = append(records, record.SerializeRecord(&record.RequestRecord{Payload: []byte{byte(i)}}))
+		leaves = append(leaves, jet.MerkleLeaf(scheme, records[i]))
+	}
+	id := record.NewRecordIDFromRecord(scheme, pulse, record.DeserializeRecord(records[1]))
+	root := jet.MerkleRoot(scheme, leaves)
+	prevHash := []byte("previous drop")
+
+	var path []requester.ProofStepResponse
+	for _, step := range jet.MerkleProof(scheme, leaves, 1) {
+		path = append(path, requester.ProofStepResponse{Hash: step.Hash, Left: step.Left})
+	}
+
+	privateKey, err := keyProcessor.GeneratePrivateKey()
+	require.NoError(t, err)
+	publicKey, err := keyProcessor.ExportPublicKeyPEM(keyProcessor.ExtractPublicKey(privateKey))
+	require.NoError(t, err)
+
+	trust := ProofTrust{Executors: map[string]string{}}
+	jetPrefix := []byte{1}
+	dropHash := jet.DropHash(scheme, prevHash, root)
+	var dropSigns []requester.DropSignResponse
+	for i := 0; i < DefaultDropQuorum; i++ {
+		executorKey, err := keyProcessor.GeneratePrivateKey()
+		require.NoError(t, err)
+		executorPublicKey, err := keyProcessor.ExportPublicKeyPEM(keyProcessor.ExtractPublicKey(executorKey))
+		require.NoError(t, err)
+		sign, err := scheme.Signer(executorKey).Sign(jet.DropSignData(scheme, jetPrefix, pulse, dropHash))
+		require.NoError(t, err)
+		node := testutils.RandomRef().String()
+		trust.Executors[node] = string(executorPublicKey)
+		dropSigns = append(dropSigns, requester.DropSignResponse{Node: node, Signature: sign.Bytes()})
+	}
+
+	entropy := core.Entropy{1, 2, 3}
+	hasher := scheme.IntegrityHasher()
+	hasher.Write(pulse.Bytes())
+	hasher.Write(publicKey)
+	hasher.Write(entropy[:])
+	signature, err := scheme.Signer(privateKey).Sign(hasher.Sum(nil))
+	require.NoError(t, err)
+
+	return &requester.ProofResponse{
+		Record:      records[1],
+		ID:          id.String(),
+		Path:        path,
+		RecordsRoot: root,
+		PrevHash:    prevHash,
+		DropHash:    dropHash,
+		JetPrefix:   jetPrefix,
+		DropSigns:   dropSigns,
+		PulseNumber: uint32(pulse),
+		Entropy:     entropy[:],
+		Signs: []requester.PulseSignResponse{{
+			PublicKey:       string(publicKey),
+			ChosenPublicKey: string(publicKey),
+			Signature:       signature.Bytes(),
+		}},
+	}, string(publicKey), trust
+}
+
+func TestVerifyRecordProof(t *testing.T) {
+	proof, pulsar, trust := signedProof(t)
+	require.NoError(t, VerifyRecordProof(proof, trust))
+	trust.Pulsars = []string{pulsar}
+	require.NoError(t, VerifyRecordProof(proof, trust))
+	trust.Pulsars = []string{"untrusted pulsar"}
+	assert.Error(t, VerifyRecordProof(proof, trust))
+
+	tamper := func(change func(p *requester.ProofResponse, trust *ProofTrust)) error {
+		p, _, trust := signedProof(t)
+		change(p, &trust)
+		return VerifyRecordProof(p, trust)
+	}
+	assert.Error(t, tamper(func(p *requester.ProofResponse, _ *ProofTrust) {
+		p.Record = record.SerializeRecord(&record.RequestRecord{Payload: []byte("forged")})
+	}), "record doesn't match id")
+	assert.Error(t, tamper(func(p *requester.ProofResponse, _ *ProofTrust) {
+		id := testutils.RandomID()
+		p.ID = id.String()
+	}), "id from another pulse")
+	assert.Error(t, tamper(func(p *requester.ProofResponse, _ *ProofTrust) {
+		p.Path[0].Left = !p.Path[0].Left
+	}), "wrong path")
+	assert.Error(t, tamper(func(p *requester.ProofResponse, _ *ProofTrust) {
+		p.PrevHash = []byte("another drop")
+	}), "drop hash mismatch")
+	assert.Error(t, tamper(func(p *requester.ProofResponse, _ *ProofTrust) {
+		p.Entropy[0]++
+	}), "signature mismatch")
+	assert.Error(t, tamper(func(p *requester.ProofResponse, _ *ProofTrust) {
+		p.Signs = nil
+	}), "unsigned pulse")
+	assert.Error(t, tamper(func(p *requester.ProofResponse, _ *ProofTrust) {
+		p.Record = []byte{1}
+	}), "malformed record")
+	assert.Error(t, tamper(func(p *requester.ProofResponse, _ *ProofTrust) {
+		p.DropSigns = p.DropSigns[:1]
+	}), "no quorum of executors")
+	assert.Error(t, tamper(func(p *requester.ProofResponse, _ *ProofTrust) {
+		p.DropSigns[1] = p.DropSigns[0]
+	}), "duplicated executor signature")
+	assert.Error(t, tamper(func(p *requester.ProofResponse, _ *ProofTrust) {
+		p.JetPrefix = []byte{2}
+	}), "drop of another jet")
+	assert.Error(t, tamper(func(p *requester.ProofResponse, trust *ProofTrust) {
+		trust.Executors = nil
+	}), "no trusted executors")
+	assert.NoError(t, tamper(func(p *requester.ProofResponse, trust *ProofTrust) {
+		p.DropSigns = p.DropSigns[:1]
+		trust.Quorum = 1
+	}), "custom quorum")
+}
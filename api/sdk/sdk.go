@@ -118,28 +118,75 @@ func (sdk *SDK) getResponse(body []byte) (*response, error) {
 	return res, nil
 }
 
-// CreateMember api request creates member with new random keys
-func (sdk *SDK) CreateMember() (*Member, string, error) {
-	ctx := inslogger.ContextWithTrace(context.Background(), "CreateMember")
-	memberName := testutils.RandomString()
+// sendCoSignedRequest sends request on behalf of multisig member signed by Threshold of its keys,
+// response of the last co-signer contains result of execution
+func (sdk *SDK) sendCoSignedRequest(ctx context.Context, method string, params []interface{}, ms *MultiSig) (*response, error) {
+	if uint(len(ms.PrivateKeys)) < ms.Threshold {
+		return nil, errors.New("[ sendCoSignedRequest ] not enough keys to reach threshold")
+	}
+	coSigners := make([]*requester.UserConfigJSON, 0, ms.Threshold)
+	for _, key := range ms.PrivateKeys[:ms.Threshold] {
+		config, err := requester.CreateUserConfig(ms.Reference, key)
+		if err != nil {
+			return nil, errors.Wrap(err, "[ sendCoSignedRequest ] can't create user config")
+		}
+		coSigners = append(coSigners, config)
+	}
+
+	reqCfg := &requester.RequestConfigJSON{
+		Params: params,
+		Method: method,
+	}
+	bodies, err := requester.SendCoSigned(ctx, sdk.apiURLs.next(), coSigners, reqCfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ sendCoSignedRequest ] can not send request")
+	}
+
+	var res *response
+	for _, body := range bodies {
+		res, err = sdk.getResponse(body)
+		if err != nil {
+			return nil, errors.Wrap(err, "[ sendCoSignedRequest ] can't get response")
+		}
+		if res.Error != "" {
+			return res, errors.New(res.Error)
+		}
+	}
+	return res, nil
+}
+
+func generateKeys() (string, string, error) {
 	ks := platformpolicy.NewKeyProcessor()
 
 	privateKey, err := ks.GeneratePrivateKey()
 	if err != nil {
-		return nil, "", errors.Wrap(err, "[ CreateMember ] can't generate private key")
+		return "", "", errors.Wrap(err, "[ generateKeys ] can't generate private key")
 	}
 
 	privateKeyStr, err := ks.ExportPrivateKeyPEM(privateKey)
 	if err != nil {
-		return nil, "", errors.Wrap(err, "[ CreateMember ] can't export private key")
+		return "", "", errors.Wrap(err, "[ generateKeys ] can't export private key")
 	}
 
-	memberPubKeyStr, err := ks.ExportPublicKeyPEM(ks.ExtractPublicKey(privateKey))
+	publicKeyStr, err := ks.ExportPublicKeyPEM(ks.ExtractPublicKey(privateKey))
+	if err != nil {
+		return "", "", errors.Wrap(err, "[ generateKeys ] can't extract public key")
+	}
+
+	return string(privateKeyStr), string(publicKeyStr), nil
+}
+
+// CreateMember api request creates member with new random keys
+func (sdk *SDK) CreateMember() (*Member, string, error) {
+	ctx := inslogger.ContextWithTrace(context.Background(), "CreateMember")
+	memberName := testutils.RandomString()
+
+	privateKeyStr, memberPubKeyStr, err := generateKeys()
 	if err != nil {
-		return nil, "", errors.Wrap(err, "[ CreateMember ] can't extract public key")
+		return nil, "", errors.Wrap(err, "[ CreateMember ] can't generate keys")
 	}
 
-	params := []interface{}{memberName, string(memberPubKeyStr)}
+	params := []interface{}{memberName, memberPubKeyStr}
 	body, err := sdk.sendRequest(ctx, "CreateMember", params, sdk.rootMember)
 	if err != nil {
 		return nil, "", errors.Wrap(err, "[ CreateMember ] can't send request")
@@ -154,7 +201,75 @@ func (sdk *SDK) CreateMember() (*Member, string, error) {
 		return nil, response.TraceID, errors.New(response.Error)
 	}
 
-	return NewMember(response.Result.(string), string(privateKeyStr)), response.TraceID, nil
+	return NewMember(response.Result.(string), privateKeyStr), response.TraceID, nil
+}
+
+// CreateMultiSig api request creates multisig member with given number of new random keys,
+// admin multisig can create members and register nodes
+func (sdk *SDK) CreateMultiSig(keysCount int, threshold uint, admin bool) (*MultiSig, string, error) {
+	ctx := inslogger.ContextWithTrace(context.Background(), "CreateMultiSig")
+	privateKeys := make([]string, 0, keysCount)
+	publicKeys := make([]string, 0, keysCount)
+	for i := 0; i < keysCount; i++ {
+		privateKeyStr, publicKeyStr, err := generateKeys()
+		if err != nil {
+			return nil, "", errors.Wrap(err, "[ CreateMultiSig ] can't generate keys")
+		}
+		privateKeys = append(privateKeys, privateKeyStr)
+		publicKeys = append(publicKeys, publicKeyStr)
+	}
+
+	params := []interface{}{testutils.RandomString(), publicKeys, threshold, admin}
+	body, err := sdk.sendRequest(ctx, "CreateMultiSig", params, sdk.rootMember)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "[ CreateMultiSig ] can't send request")
+	}
+
+	response, err := sdk.getResponse(body)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "[ CreateMultiSig ] can't get response")
+	}
+
+	if response.Error != "" {
+		return nil, response.TraceID, errors.New(response.Error)
+	}
+
+	return NewMultiSig(response.Result.(string), privateKeys, threshold), response.TraceID, nil
+}
+
+// MultiSigCreateMember creates member with new random keys on behalf of admin multisig member
+func (sdk *SDK) MultiSigCreateMember(ms *MultiSig) (*Member, string, error) {
+	ctx := inslogger.ContextWithTrace(context.Background(), "MultiSigCreateMember")
+	privateKeyStr, memberPubKeyStr, err := generateKeys()
+	if err != nil {
+		return nil, "", errors.Wrap(err, "[ MultiSigCreateMember ] can't generate keys")
+	}
+
+	params := []interface{}{testutils.RandomString(), memberPubKeyStr}
+	response, err := sdk.sendCoSignedRequest(ctx, "CreateMember", params, ms)
+	if err != nil {
+		if response != nil {
+			return nil, response.TraceID, errors.Wrap(err, "[ MultiSigCreateMember ] can't create member")
+		}
+		return nil, "", errors.Wrap(err, "[ MultiSigCreateMember ] can't create member")
+	}
+
+	return NewMember(response.Result.(string), privateKeyStr), response.TraceID, nil
+}
+
+// MultiSigTransfer method send money from multisig member to another member
+func (sdk *SDK) MultiSigTransfer(amount uint, from *MultiSig, to *Member) (string, error) {
+	ctx := inslogger.ContextWithTrace(context.Background(), "MultiSigTransfer")
+	params := []interface{}{amount, to.Reference}
+	response, err := sdk.sendCoSignedRequest(ctx, "Transfer", params, from)
+	if err != nil {
+		if response != nil {
+			return response.TraceID, errors.Wrap(err, "[ MultiSigTransfer ] can't transfer")
+		}
+		return "", errors.Wrap(err, "[ MultiSigTransfer ] can't transfer")
+	}
+
+	return response.TraceID, nil
 }
 
 // Transfer method send money from one member to another
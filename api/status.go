@@ -20,6 +20,7 @@ import (
 	"context"
 	"net/http"
 
+	"github.com/insolar/insolar/core"
 	"github.com/insolar/insolar/core/utils"
 	"github.com/insolar/insolar/instrumentation/inslogger"
 )
@@ -37,6 +38,18 @@ type StatusReply struct {
 	ActiveList     []Node
 	PulseNumber    uint32
 	Entropy        []byte
+	Jets           []string      `json:",omitempty"`
+	JetDecisions   []JetDecision `json:",omitempty"`
+}
+
+// JetDecision is a jet split or merge made by light material node.
+type JetDecision struct {
+	Pulse    uint32
+	Action   string
+	From     []string
+	To       []string
+	DropSize uint64
+	Requests uint64
 }
 
 // StatusService is a service that provides API for getting status of node.
@@ -84,5 +97,31 @@ func (s *StatusService) Get(r *http.Request, args *interface{}, reply *StatusRep
 	reply.PulseNumber = uint32(pulse.PulseNumber)
 	reply.Entropy = pulse.Entropy[:]
 
+	if s.runner.JetBalancer != nil && origin.Role() == core.StaticRoleLightMaterial {
+		status, err := s.runner.JetBalancer.JetStatus(ctx)
+		if err != nil {
+			return err
+		}
+		reply.Jets = jetIDs(status.Jets)
+		for _, decision := range status.Decisions {
+			reply.JetDecisions = append(reply.JetDecisions, JetDecision{
+				Pulse:    uint32(decision.Pulse),
+				Action:   decision.Action,
+				From:     jetIDs(decision.From),
+				To:       jetIDs(decision.To),
+				DropSize: decision.DropSize,
+				Requests: decision.Requests,
+			})
+		}
+	}
+
 	return nil
 }
+
+func jetIDs(ids []core.RecordID) []string {
+	res := make([]string, 0, len(ids))
+	for _, id := range ids {
+		res = append(res, id.DebugString())
+	}
+	return res
+}
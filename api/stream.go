@@ -0,0 +1,338 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/insolar/insolar/core/utils"
+	"github.com/insolar/insolar/instrumentation/inslogger"
+)
+
+// Stream topics.
+const (
+	topicPulse        = "pulse"
+	topicNodes        = "nodes"
+	topicNetworkState = "networkState"
+	topicCall         = "call"
+)
+
+const (
+	// streamBufferSize is a number of events queued for subscriber. Subscriber is dropped if it can't keep up.
+	streamBufferSize = 64
+	// streamCallsCacheSize is a number of recent call results sent to subscribers that came after call finished.
+	streamCallsCacheSize = 1024
+	streamKeepAlive      = 15 * time.Second
+	// defaultStreamPollInterval is used if StreamPollInterval is not positive.
+	defaultStreamPollInterval = 500 * time.Millisecond
+)
+
+// PulseEvent is sent to stream subscribers when new pulse comes.
+type PulseEvent struct {
+	PulseNumber     uint32
+	PrevPulseNumber uint32
+	NextPulseNumber uint32
+	Entropy         []byte
+}
+
+// NodesEvent is sent to stream subscribers when active node list changes.
+type NodesEvent struct {
+	ActiveListSize int
+	ActiveList     []Node
+}
+
+// NetworkStateEvent is sent to stream subscribers when network state changes.
+type NetworkStateEvent struct {
+	NetworkState string
+}
+
+type streamEvent struct {
+	topic string
+	// key is a trace id of call for call events.
+	key  string
+	data interface{}
+}
+
+type streamSubscriber struct {
+	topics map[string]bool
+	calls  map[string]bool
+	events chan streamEvent
+}
+
+func (s *streamSubscriber) wants(e streamEvent) bool {
+	if e.topic == topicCall {
+		return s.calls[e.key]
+	}
+	return s.topics[e.topic]
+}
+
+// streamHub delivers events to stream subscribers. New subscriber receives the latest event of every topic it is
+// subscribed to, so it doesn't need to poll initial state.
+type streamHub struct {
+	lock        sync.Mutex
+	subscribers map[*streamSubscriber]struct{}
+	last        map[string]streamEvent
+	calls       map[string]streamEvent
+	callsOrder  []string
+}
+
+func newStreamHub() *streamHub {
+	return &streamHub{
+		subscribers: map[*streamSubscriber]struct{}{},
+		last:        map[string]streamEvent{},
+		calls:       map[string]streamEvent{},
+	}
+}
+
+func (h *streamHub) subscribe(topics []string, calls []string) *streamSubscriber {
+	s := &streamSubscriber{
+		topics: map[string]bool{},
+		calls:  map[string]bool{},
+	}
+	for _, topic := range topics {
+		s.topics[topic] = true
+	}
+	for _, call := range calls {
+		s.calls[call] = true
+	}
+	// Buffer fits every replayed event (one per distinct topic and call) on top of regular queue.
+	s.events = make(chan streamEvent, streamBufferSize+len(s.topics)+len(s.calls))
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	replay := func(e streamEvent) {
+		// Never block under hub lock, buffer is sized for replay anyway.
+		select {
+		case s.events <- e:
+		default:
+		}
+	}
+	for topic := range s.topics {
+		if e, ok := h.last[topic]; ok {
+			replay(e)
+		}
+	}
+	for call := range s.calls {
+		if e, ok := h.calls[call]; ok {
+			replay(e)
+		}
+	}
+	h.subscribers[s] = struct{}{}
+	return s
+}
+
+func (h *streamHub) unsubscribe(s *streamSubscriber) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if _, ok := h.subscribers[s]; ok {
+		delete(h.subscribers, s)
+		close(s.events)
+	}
+}
+
+// close drops all subscribers.
+func (h *streamHub) close() {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	for s := range h.subscribers {
+		delete(h.subscribers, s)
+		close(s.events)
+	}
+}
+
+func (h *streamHub) publish(e streamEvent) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if e.topic == topicCall {
+		if len(h.callsOrder) >= streamCallsCacheSize {
+			delete(h.calls, h.callsOrder[0])
+			h.callsOrder = h.callsOrder[1:]
+		}
+		h.calls[e.key] = e
+		h.callsOrder = append(h.callsOrder, e.key)
+	} else {
+		h.last[e.topic] = e
+	}
+
+	for s := range h.subscribers {
+		if !s.wants(e) {
+			continue
+		}
+		select {
+		case s.events <- e:
+		default:
+			// Slow subscriber would block everyone else, so it is dropped.
+			delete(h.subscribers, s)
+			close(s.events)
+		}
+	}
+}
+
+func (h *streamHub) publishCall(traceID string, result interface{}, err error) {
+	resp := answer{TraceID: traceID, Result: result}
+	if err != nil {
+		resp.Error = err.Error()
+	}
+	h.publish(streamEvent{topic: topicCall, key: traceID, data: resp})
+}
+
+// watchNetwork polls pulse, active nodes and network state and publishes changes to stream subscribers.
+func (ar *Runner) watchNetwork(ctx context.Context, stop <-chan struct{}) {
+	interval := ar.cfg.StreamPollInterval
+	if interval <= 0 {
+		inslogger.FromContext(ctx).Warnf(
+			"[ watchNetwork ] invalid StreamPollInterval %v, using %v", interval, defaultStreamPollInterval,
+		)
+		interval = defaultStreamPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var pulse *PulseEvent
+	var nodes *NodesEvent
+	var state *NetworkStateEvent
+	for {
+		if ar.PulseStorage != nil {
+			current, err := ar.PulseStorage.Current(ctx)
+			if err != nil {
+				inslogger.FromContext(ctx).Debug("[ watchNetwork ] Can't get current pulse: ", err)
+			} else if pulse == nil || pulse.PulseNumber != uint32(current.PulseNumber) {
+				pulse = &PulseEvent{
+					PulseNumber:     uint32(current.PulseNumber),
+					PrevPulseNumber: uint32(current.PrevPulseNumber),
+					NextPulseNumber: uint32(current.NextPulseNumber),
+					Entropy:         current.Entropy[:],
+				}
+				ar.streams.publish(streamEvent{topic: topicPulse, data: pulse})
+			}
+		}
+
+		if ar.NodeNetwork != nil {
+			activeNodes := ar.NodeNetwork.GetActiveNodes()
+			current := &NodesEvent{ActiveListSize: len(activeNodes), ActiveList: make([]Node, len(activeNodes))}
+			for i, node := range activeNodes {
+				current.ActiveList[i] = Node{
+					Reference: node.ID().String(),
+					Role:      node.Role().String(),
+				}
+			}
+			if !reflect.DeepEqual(nodes, current) {
+				nodes = current
+				ar.streams.publish(streamEvent{topic: topicNodes, data: nodes})
+			}
+		}
+
+		if ar.NetworkSwitcher != nil {
+			current := &NetworkStateEvent{NetworkState: ar.NetworkSwitcher.GetState().String()}
+			if state == nil || *state != *current {
+				state = current
+				ar.streams.publish(streamEvent{topic: topicNetworkState, data: state})
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+	}
+}
+
+// streamHandler sends events to client as Server-Sent Events. Topics are passed in "topics" query parameter as comma
+// separated list, results of calls are requested by trace id of call in "call" parameters, e.g.
+// /api/stream?topics=pulse,nodes&call=<traceID>.
+func (ar *Runner) streamHandler() func(http.ResponseWriter, *http.Request) {
+	return func(response http.ResponseWriter, req *http.Request) {
+		traceID := utils.RandTraceID()
+		_, insLog := inslogger.WithTraceField(context.Background(), traceID)
+
+		insLog.Infof("[ streamHandler ] Incoming request: %s", req.RequestURI)
+
+		flusher, ok := response.(http.Flusher)
+		if !ok {
+			http.Error(response, "[ streamHandler ] Streaming is not supported", http.StatusInternalServerError)
+			return
+		}
+
+		query := req.URL.Query()
+		var topics []string
+		for _, list := range query["topics"] {
+			for _, topic := range strings.Split(list, ",") {
+				switch topic {
+				case topicPulse, topicNodes, topicNetworkState:
+					topics = append(topics, topic)
+				default:
+					http.Error(response, "[ streamHandler ] Unknown topic: "+topic, http.StatusBadRequest)
+					return
+				}
+			}
+		}
+		calls := query["call"]
+		if len(topics) == 0 && len(calls) == 0 {
+			http.Error(response, "[ streamHandler ] Nothing to subscribe", http.StatusBadRequest)
+			return
+		}
+
+		response.Header().Set("Content-Type", "text/event-stream")
+		response.Header().Set("Cache-Control", "no-cache")
+		response.Header().Set("Connection", "keep-alive")
+		response.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		subscriber := ar.streams.subscribe(topics, calls)
+		defer ar.streams.unsubscribe(subscriber)
+
+		keepAlive := time.NewTicker(streamKeepAlive)
+		defer keepAlive.Stop()
+		for {
+			select {
+			case e, ok := <-subscriber.events:
+				if !ok {
+					return
+				}
+				data, err := json.Marshal(e.data)
+				if err != nil {
+					insLog.Error("[ streamHandler ] Can't marshal event: ", err)
+					continue
+				}
+				_, err = fmt.Fprintf(response, "event: %s\ndata: %s\n\n", e.topic, data)
+				if err != nil {
+					return
+				}
+			case <-keepAlive.C:
+				_, err := fmt.Fprint(response, ": keep-alive\n\n")
+				if err != nil {
+					return
+				}
+			case <-req.Context().Done():
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,172 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/insolar/insolar/configuration"
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/testutils"
+	"github.com/insolar/insolar/testutils/network"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamHub_Subscribe(t *testing.T) {
+	hub := newStreamHub()
+	hub.publish(streamEvent{topic: topicPulse, data: 1})
+	hub.publish(streamEvent{topic: topicPulse, data: 2})
+	hub.publishCall("trace", "OK", nil)
+
+	s := hub.subscribe([]string{topicPulse}, []string{"trace", "other"})
+	// Latest state and finished calls are sent on subscribe.
+	assert.Equal(t, streamEvent{topic: topicPulse, data: 2}, <-s.events)
+	assert.Equal(t, streamEvent{topic: topicCall, key: "trace", data: answer{TraceID: "trace", Result: "OK"}}, <-s.events)
+
+	hub.publish(streamEvent{topic: topicNodes, data: 3})
+	hub.publishCall("other", nil, assert.AnError)
+	assert.Equal(t, streamEvent{topic: topicCall, key: "other", data: answer{TraceID: "other", Error: assert.AnError.Error()}}, <-s.events)
+
+	hub.unsubscribe(s)
+	_, ok := <-s.events
+	assert.False(t, ok)
+}
+
+func TestStreamHub_SubscribeRepeatedTopics(t *testing.T) {
+	hub := newStreamHub()
+	hub.publish(streamEvent{topic: topicPulse, data: 1})
+	hub.publishCall("trace", "OK", nil)
+
+	var topics, calls []string
+	for i := 0; i < 2*streamBufferSize; i++ {
+		topics = append(topics, topicPulse)
+		calls = append(calls, "trace")
+	}
+	subscribed := make(chan *streamSubscriber)
+	go func() {
+		subscribed <- hub.subscribe(topics, calls)
+	}()
+	var s *streamSubscriber
+	select {
+	case s = <-subscribed:
+	case <-time.After(time.Second):
+		t.Fatal("subscribe is blocked")
+	}
+
+	// Every topic and call is replayed once.
+	assert.Len(t, s.events, 2)
+	hub.publish(streamEvent{topic: topicPulse, data: 2})
+	assert.Len(t, s.events, 3)
+	hub.unsubscribe(s)
+}
+
+func TestStreamHub_SlowSubscriber(t *testing.T) {
+	hub := newStreamHub()
+	s := hub.subscribe([]string{topicPulse}, nil)
+	// Queue has room for replayed event of every topic.
+	require.Equal(t, streamBufferSize+1, cap(s.events))
+	for i := 0; i <= cap(s.events); i++ {
+		hub.publish(streamEvent{topic: topicPulse, data: i})
+	}
+
+	received := 0
+	for range s.events {
+		received++
+	}
+	assert.Equal(t, cap(s.events), received)
+	assert.Empty(t, hub.subscribers)
+}
+
+func TestRunner_streamHandler(t *testing.T) {
+	cfg := configuration.NewAPIRunner()
+	cfg.StreamPollInterval = 10 * time.Millisecond
+	ar, err := NewRunner(&cfg)
+	require.NoError(t, err)
+
+	ps := testutils.NewPulseStorageMock(t)
+	ps.CurrentFunc = func(ctx context.Context) (*core.Pulse, error) {
+		return &core.Pulse{PulseNumber: 100, NextPulseNumber: 110}, nil
+	}
+	ar.PulseStorage = ps
+	nn := network.NewNodeNetworkMock(t)
+	nn.GetActiveNodesFunc = func() []core.Node {
+		return nil
+	}
+	ar.NodeNetwork = nn
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go ar.watchNetwork(context.Background(), stop)
+
+	server := httptest.NewServer(http.HandlerFunc(ar.streamHandler()))
+	defer server.Close()
+	defer ar.streams.close()
+
+	resp, err := http.Get(server.URL + "?topics=unknown")
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, err = http.Get(server.URL + "?topics=pulse,nodes")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	events := map[string]string{}
+	reader := bufio.NewReader(resp.Body)
+	var name string
+	for len(events) < 2 {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			events[name] = strings.TrimPrefix(line, "data: ")
+		}
+	}
+
+	var pulse PulseEvent
+	require.NoError(t, json.Unmarshal([]byte(events[topicPulse]), &pulse))
+	assert.Equal(t, uint32(100), pulse.PulseNumber)
+	assert.Equal(t, uint32(110), pulse.NextPulseNumber)
+	assert.Equal(t, `{"ActiveListSize":0,"ActiveList":[]}`, events[topicNodes])
+}
+
+func TestRunner_watchNetwork_InvalidInterval(t *testing.T) {
+	cfg := configuration.NewAPIRunner()
+	cfg.StreamPollInterval = 0
+	ar, err := NewRunner(&cfg)
+	require.NoError(t, err)
+	defer ar.streams.close()
+
+	stop := make(chan struct{})
+	close(stop)
+	// Ticker panics on non-positive interval, default one must be used instead.
+	assert.NotPanics(t, func() {
+		ar.watchNetwork(context.Background(), stop)
+	})
+}
@@ -30,6 +30,10 @@ type Allowance struct {
 	To         core.RecordRef
 	Amount     uint
 	ExpireTime int64
+	// Asset is a symbol of transferred asset, empty for default currency
+	Asset string
+	// From is a reference of sender member
+	From core.RecordRef
 }
 
 func (a *Allowance) isExpired() bool {
@@ -48,6 +52,16 @@ func (a *Allowance) TakeAmount() (uint, error) {
 	return a.Amount, nil
 }
 
+// GetAsset returns symbol of transferred asset
+func (a *Allowance) GetAsset() (string, error) {
+	return a.Asset, nil
+}
+
+// GetFrom returns reference of sender member
+func (a *Allowance) GetFrom() (core.RecordRef, error) {
+	return a.From, nil
+}
+
 // GetBalanceForOwner returns balance
 func (a *Allowance) GetBalanceForOwner() (uint, error) {
 	return a.Amount, nil
@@ -72,3 +86,14 @@ func New(to *core.RecordRef, amount uint, expire int64) (*Allowance, error) {
 	}
 	return &Allowance{To: *to, Amount: amount, ExpireTime: expire}, nil
 }
+
+// NewAssetAllowance check is caller wallet and makes new allowance for given asset from given member
+func NewAssetAllowance(to *core.RecordRef, from *core.RecordRef, asset string, amount uint, expire int64) (*Allowance, error) {
+	a, err := New(to, amount, expire)
+	if err != nil {
+		return nil, err
+	}
+	a.From = *from
+	a.Asset = asset
+	return a, nil
+}
@@ -0,0 +1,109 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package assetregistry
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/insolar/insolar/application/proxy/wallet"
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/logicrunner/goplugin/foundation"
+)
+
+const maxSymbolLength = 16
+
+// Asset describes a token issued by a member
+type Asset struct {
+	Symbol string
+	Name   string
+	Issuer core.RecordRef
+	Supply uint
+}
+
+// AssetRegistry holds all issued assets
+type AssetRegistry struct {
+	foundation.BaseContract
+	Assets []Asset
+}
+
+// NewAssetRegistry creates new AssetRegistry
+func NewAssetRegistry() (*AssetRegistry, error) {
+	return &AssetRegistry{}, nil
+}
+
+func (ar *AssetRegistry) findAsset(symbol string) (Asset, bool) {
+	for _, a := range ar.Assets {
+		if a.Symbol == symbol {
+			return a, true
+		}
+	}
+	return Asset{}, false
+}
+
+// IssueAsset registers new asset issued by caller and puts whole supply to caller's wallet
+func (ar *AssetRegistry) IssueAsset(symbol string, name string, supply uint) error {
+	if symbol == "" || len(symbol) > maxSymbolLength || strings.ContainsAny(symbol, " \t\n") {
+		return fmt.Errorf("[ IssueAsset ] Invalid asset symbol: %q", symbol)
+	}
+	if _, ok := ar.findAsset(symbol); ok {
+		return fmt.Errorf("[ IssueAsset ] Asset %s already exists", symbol)
+	}
+
+	issuer := *ar.GetContext().Caller
+	w, err := wallet.GetImplementationFrom(issuer)
+	if err != nil {
+		return fmt.Errorf("[ IssueAsset ] Can't get issuer's wallet: %s", err.Error())
+	}
+	err = w.Mint(symbol, supply)
+	if err != nil {
+		return fmt.Errorf("[ IssueAsset ] Can't mint asset: %s", err.Error())
+	}
+
+	ar.Assets = append(ar.Assets, Asset{
+		Symbol: symbol,
+		Name:   name,
+		Issuer: issuer,
+		Supply: supply,
+	})
+	return nil
+}
+
+// HasAsset checks if asset with given symbol is registered
+func (ar *AssetRegistry) HasAsset(symbol string) (bool, error) {
+	_, ok := ar.findAsset(symbol)
+	return ok, nil
+}
+
+// GetAssets returns all registered assets
+func (ar *AssetRegistry) GetAssets() ([]byte, error) {
+	res := make([]map[string]interface{}, len(ar.Assets))
+	for i, a := range ar.Assets {
+		res[i] = map[string]interface{}{
+			"symbol": a.Symbol,
+			"name":   a.Name,
+			"issuer": a.Issuer.String(),
+			"supply": a.Supply,
+		}
+	}
+	resJSON, err := json.Marshal(res)
+	if err != nil {
+		return nil, fmt.Errorf("[ GetAssets ] Can't marshal res: %s", err.Error())
+	}
+	return resJSON, nil
+}
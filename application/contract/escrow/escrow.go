@@ -0,0 +1,191 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package escrow
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/insolar/insolar/application/proxy/wallet"
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/logicrunner/goplugin/foundation"
+)
+
+// Escrow holds amount taken from sender wallet until release conditions are met.
+// Every set condition must be met for release: preimage of HashLock is presented,
+// ReleasePulse and ReleaseTime are reached, Arbiter approved. Sender can refund amount
+// after ExpireTime or after Arbiter rejected the escrow.
+type Escrow struct {
+	foundation.BaseContract
+	// To is a recipient wallet
+	To core.RecordRef
+	// From is a sender member
+	From         core.RecordRef
+	Asset        string
+	Amount       uint
+	HashLock     string
+	ReleasePulse core.PulseNumber
+	ReleaseTime  int64
+	Arbiter      core.RecordRef
+	ExpireTime   int64
+	Approved     bool
+	Rejected     bool
+}
+
+func (e *Escrow) isExpired() bool {
+	return e.GetContext().Time.After(time.Unix(e.ExpireTime, 0))
+}
+
+func (e *Escrow) checkConditions(preimage string) error {
+	if e.Rejected {
+		return fmt.Errorf("escrow is rejected by arbiter")
+	}
+	if e.isExpired() {
+		return fmt.Errorf("escrow expired")
+	}
+	if e.HashLock != "" {
+		hash := sha256.Sum256([]byte(preimage))
+		if hex.EncodeToString(hash[:]) != e.HashLock {
+			return fmt.Errorf("wrong preimage")
+		}
+	}
+	if e.ReleasePulse != 0 && e.GetContext().Pulse.PulseNumber < e.ReleasePulse {
+		return fmt.Errorf("release pulse is not reached")
+	}
+	if e.ReleaseTime != 0 && e.GetContext().Time.Before(time.Unix(e.ReleaseTime, 0)) {
+		return fmt.Errorf("release time is not reached")
+	}
+	if !e.Arbiter.IsEmpty() && !e.Approved {
+		return fmt.Errorf("escrow is not approved by arbiter")
+	}
+	return nil
+}
+
+// Release allows recipient to take amount if conditions are met and deletes escrow
+func (e *Escrow) Release(preimage string) (uint, error) {
+	if *e.GetContext().Caller != e.To {
+		return 0, fmt.Errorf("[ Release ] Only recipient can release escrow")
+	}
+	if err := e.checkConditions(preimage); err != nil {
+		return 0, fmt.Errorf("[ Release ] Can't release escrow: %s", err.Error())
+	}
+	e.SelfDestruct()
+	return e.Amount, nil
+}
+
+// Refund returns amount to sender after expiration or rejection and deletes escrow
+func (e *Escrow) Refund() (uint, error) {
+	if *e.GetContext().Caller != *e.GetContext().Parent {
+		return 0, fmt.Errorf("[ Refund ] Only owner can refund escrow")
+	}
+	if !e.Rejected && !e.isExpired() {
+		return 0, fmt.Errorf("[ Refund ] Escrow is neither expired nor rejected")
+	}
+	e.SelfDestruct()
+	return e.Amount, nil
+}
+
+// Approve allows release of escrow, only arbiter can call it
+func (e *Escrow) Approve() error {
+	if e.Arbiter.IsEmpty() || *e.GetContext().Caller != e.Arbiter {
+		return fmt.Errorf("[ Approve ] Only arbiter can approve escrow")
+	}
+	if e.Rejected {
+		return fmt.Errorf("[ Approve ] Escrow is already rejected")
+	}
+	e.Approved = true
+	return nil
+}
+
+// Reject allows sender to refund escrow immediately, only arbiter can call it
+func (e *Escrow) Reject() error {
+	if e.Arbiter.IsEmpty() || *e.GetContext().Caller != e.Arbiter {
+		return fmt.Errorf("[ Reject ] Only arbiter can reject escrow")
+	}
+	if e.Approved {
+		return fmt.Errorf("[ Reject ] Escrow is already approved")
+	}
+	e.Rejected = true
+	return nil
+}
+
+// GetAsset returns symbol of held asset
+func (e *Escrow) GetAsset() (string, error) {
+	return e.Asset, nil
+}
+
+// GetFrom returns reference of sender member
+func (e *Escrow) GetFrom() (core.RecordRef, error) {
+	return e.From, nil
+}
+
+// GetInfo returns description of escrow
+func (e *Escrow) GetInfo() ([]byte, error) {
+	res := map[string]interface{}{
+		"from":          e.From.String(),
+		"to":            e.To.String(),
+		"asset":         e.Asset,
+		"amount":        e.Amount,
+		"hash_lock":     e.HashLock,
+		"release_pulse": e.ReleasePulse,
+		"release_time":  e.ReleaseTime,
+		"expire_time":   e.ExpireTime,
+		"approved":      e.Approved,
+		"rejected":      e.Rejected,
+		"expired":       e.isExpired(),
+	}
+	if !e.Arbiter.IsEmpty() {
+		res["arbiter"] = e.Arbiter.String()
+	}
+	resJSON, err := json.Marshal(res)
+	if err != nil {
+		return nil, fmt.Errorf("[ GetInfo ] Can't marshal res: %s", err.Error())
+	}
+	return resJSON, nil
+}
+
+// New check is caller wallet and makes new escrow
+func New(
+	to *core.RecordRef, from *core.RecordRef, asset string, amount uint,
+	hashLock string, releasePulse core.PulseNumber, releaseTime int64, arbiter *core.RecordRef, expire int64,
+) (*Escrow, error) {
+	if !wallet.PrototypeReference.Equal(*foundation.GetContext().CallerPrototype) {
+		return nil, fmt.Errorf("[ New Escrow ] : Can't create escrow from not wallet contract")
+	}
+	if releaseTime != 0 && releaseTime >= expire {
+		return nil, fmt.Errorf("[ New Escrow ] : Release time must be before expiration")
+	}
+	if hashLock != "" {
+		if b, err := hex.DecodeString(hashLock); err != nil || len(b) != sha256.Size {
+			return nil, fmt.Errorf("[ New Escrow ] : Hash lock must be hex encoded sha256 hash")
+		}
+	}
+	return &Escrow{
+		To:           *to,
+		From:         *from,
+		Asset:        asset,
+		Amount:       amount,
+		HashLock:     hashLock,
+		ReleasePulse: releasePulse,
+		ReleaseTime:  releaseTime,
+		Arbiter:      *arbiter,
+		ExpireTime:   expire,
+	}, nil
+}
@@ -0,0 +1,72 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package historyentry
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/insolar/insolar/application/proxy/wallet"
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/logicrunner/goplugin/foundation"
+)
+
+// HistoryEntry describes single change of wallet balance, it's saved as child of the wallet
+type HistoryEntry struct {
+	foundation.BaseContract
+	Direction string
+	// Counterparty is a sender or recipient member, asset registry for minted assets
+	// and expired allowance or refunded escrow for reclaimed amounts
+	Counterparty core.RecordRef
+	Asset        string
+	Amount       uint
+	Pulse        core.PulseNumber
+	Request      core.RecordRef
+}
+
+// GetInfo returns description of history entry
+func (he *HistoryEntry) GetInfo() ([]byte, error) {
+	res := map[string]interface{}{
+		"direction":    he.Direction,
+		"counterparty": he.Counterparty.String(),
+		"asset":        he.Asset,
+		"amount":       he.Amount,
+		"pulse":        he.Pulse,
+		"request":      he.Request.String(),
+	}
+	resJSON, err := json.Marshal(res)
+	if err != nil {
+		return nil, fmt.Errorf("[ GetInfo ] Can't marshal res: %s", err.Error())
+	}
+	return resJSON, nil
+}
+
+// New check is caller wallet and makes new history entry of given wallet request
+func New(direction string, counterparty *core.RecordRef, asset string, amount uint, request *core.RecordRef) (*HistoryEntry, error) {
+	ctx := foundation.GetContext()
+	if !wallet.PrototypeReference.Equal(*ctx.CallerPrototype) {
+		return nil, fmt.Errorf("[ New HistoryEntry ] : Can't create history entry from not wallet contract")
+	}
+	return &HistoryEntry{
+		Direction:    direction,
+		Counterparty: *counterparty,
+		Asset:        asset,
+		Amount:       amount,
+		Pulse:        ctx.Pulse.PulseNumber,
+		Request:      *request,
+	}, nil
+}
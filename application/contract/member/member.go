@@ -17,9 +17,13 @@
 package member
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/insolar/insolar/application/contract/member/signer"
+	"github.com/insolar/insolar/application/proxy/assetregistry"
+	"github.com/insolar/insolar/application/proxy/escrow"
+	"github.com/insolar/insolar/application/proxy/member"
 	"github.com/insolar/insolar/application/proxy/nodedomain"
 	"github.com/insolar/insolar/application/proxy/rootdomain"
 	"github.com/insolar/insolar/application/proxy/wallet"
@@ -27,10 +31,26 @@ import (
 	"github.com/insolar/insolar/logicrunner/goplugin/foundation"
 )
 
+// Reasons of key changes
+const (
+	KeyCreated = "created"
+	KeyRotated = "rotated"
+	KeyRevoked = "revoked"
+)
+
+// KeyRecord describes a key which became active at Pulse
+type KeyRecord struct {
+	PublicKey string
+	Pulse     core.PulseNumber
+	Reason    string
+}
+
 type Member struct {
 	foundation.BaseContract
 	Name      string
 	PublicKey string
+	// KeyHistory holds all keys of member ordered by pulse, the last one is current
+	KeyHistory []KeyRecord
 }
 
 func (m *Member) GetName() (string, error) {
@@ -43,13 +63,81 @@ func (m *Member) GetPublicKey() (string, error) {
 	return m.PublicKey, nil
 }
 
+var INSATTR_GetPublicKeys_API = true
+
+// GetPublicKeys returns keys which can sign requests of member
+func (m *Member) GetPublicKeys() ([]string, error) {
+	return []string{m.PublicKey}, nil
+}
+
 func New(name string, key string) (*Member, error) {
 	return &Member{
 		Name:      name,
 		PublicKey: key,
+		// Pulse of the first key is a pulse of member creation, it is resolved from reference in GetKeyHistory
+		KeyHistory: []KeyRecord{{
+			PublicKey: key,
+			Reason:    KeyCreated,
+		}},
 	}, nil
 }
 
+func (m *Member) setKey(key string, reason string) error {
+	if _, err := foundation.ImportPublicKey(key); err != nil {
+		return fmt.Errorf("Invalid public key")
+	}
+	if key == m.PublicKey {
+		return fmt.Errorf("New key must be different from the current one")
+	}
+	if len(m.KeyHistory) == 0 {
+		m.KeyHistory = []KeyRecord{{PublicKey: m.PublicKey, Reason: KeyCreated}}
+	}
+	m.PublicKey = key
+	m.KeyHistory = append(m.KeyHistory, KeyRecord{
+		PublicKey: key,
+		Pulse:     m.GetContext().Pulse.PulseNumber,
+		Reason:    reason,
+	})
+	return nil
+}
+
+// RevokeKey replaces compromised key of member with new one, only root member can call it
+func (m *Member) RevokeKey(newKey string) error {
+	root, err := rootdomain.GetObject(*m.GetContext().Parent).GetRootMemberRef()
+	if err != nil {
+		return fmt.Errorf("[ RevokeKey ] Couldn't get root member reference: %s", err.Error())
+	}
+	if *m.GetContext().Caller != *root {
+		return fmt.Errorf("[ RevokeKey ] Only Root member can revoke keys")
+	}
+	if err := m.setKey(newKey, KeyRevoked); err != nil {
+		return fmt.Errorf("[ RevokeKey ] %s", err.Error())
+	}
+	return nil
+}
+
+// GetKeyHistory returns all keys of member with pulses they became active at
+func (m *Member) GetKeyHistory() ([]byte, error) {
+	res := make([]map[string]interface{}, len(m.KeyHistory))
+	for i, k := range m.KeyHistory {
+		pulse := k.Pulse
+		if k.Reason == KeyCreated {
+			ref := m.GetReference()
+			pulse = ref.Record().Pulse()
+		}
+		res[i] = map[string]interface{}{
+			"public_key": k.PublicKey,
+			"pulse":      pulse,
+			"reason":     k.Reason,
+		}
+	}
+	resJSON, err := json.Marshal(res)
+	if err != nil {
+		return nil, fmt.Errorf("[ GetKeyHistory ] Can't marshal res: %s", err.Error())
+	}
+	return resJSON, nil
+}
+
 func (m *Member) verifySig(method string, params []byte, seed []byte, sign []byte) error {
 	args, err := core.MarshalArgs(m.GetReference(), method, params, seed)
 	if err != nil {
@@ -98,6 +186,34 @@ func (m *Member) Call(rootDomain core.RecordRef, method string, params []byte, s
 		return m.registerNodeCall(rootDomain, params)
 	case "GetNodeRef":
 		return m.getNodeRefCall(rootDomain, params)
+	case "IssueAsset":
+		return m.issueAssetCall(rootDomain, params)
+	case "GetAssets":
+		return m.getAssetsCall(rootDomain)
+	case "TransferAsset":
+		return m.transferAssetCall(params)
+	case "GetHistory":
+		return m.getHistoryCall(params)
+	case "CreateMultiSig":
+		return m.createMultiSigCall(rootDomain, params)
+	case "RotateKey":
+		return m.rotateKeyCall(params)
+	case "RevokeKey":
+		return m.revokeKeyCall(params)
+	case "GetKeyHistory":
+		return m.GetKeyHistory()
+	case "CreateEscrow":
+		return m.createEscrowCall(params)
+	case "ReleaseEscrow":
+		return m.releaseEscrowCall(params)
+	case "RefundEscrow":
+		return m.refundEscrowCall(params)
+	case "ApproveEscrow":
+		return m.approveEscrowCall(params)
+	case "RejectEscrow":
+		return m.rejectEscrowCall(params)
+	case "GetEscrow":
+		return m.getEscrowCall(params)
 	}
 	return nil, &foundation.Error{S: "Unknown method"}
 }
@@ -112,6 +228,46 @@ func (m *Member) createMemberCall(ref core.RecordRef, params []byte) (interface{
 	return rootDomain.CreateMember(name, key)
 }
 
+// rotateKeyCall replaces key of member, request is signed by the current key
+func (m *Member) rotateKeyCall(params []byte) (interface{}, error) {
+	var newKey string
+	if err := signer.UnmarshalParams(params, &newKey); err != nil {
+		return nil, fmt.Errorf("[ rotateKeyCall ] Can't unmarshal params: %s", err.Error())
+	}
+	if err := m.setKey(newKey, KeyRotated); err != nil {
+		return nil, fmt.Errorf("[ rotateKeyCall ] %s", err.Error())
+	}
+	return nil, nil
+}
+
+func (m *Member) revokeKeyCall(params []byte) (interface{}, error) {
+	var memberStr string
+	var newKey string
+	if err := signer.UnmarshalParams(params, &memberStr, &newKey); err != nil {
+		return nil, fmt.Errorf("[ revokeKeyCall ] Can't unmarshal params: %s", err.Error())
+	}
+	memberRef, err := core.NewRefFromBase58(memberStr)
+	if err != nil {
+		return nil, fmt.Errorf("[ revokeKeyCall ] Failed to parse 'member' param: %s", err.Error())
+	}
+	if *memberRef == m.GetReference() {
+		return nil, fmt.Errorf("[ revokeKeyCall ] Use RotateKey to change own key")
+	}
+	return nil, member.GetObject(*memberRef).RevokeKey(newKey)
+}
+
+func (m *Member) createMultiSigCall(ref core.RecordRef, params []byte) (interface{}, error) {
+	rootDomain := rootdomain.GetObject(ref)
+	var name string
+	var keys []string
+	var threshold uint
+	var admin bool
+	if err := signer.UnmarshalParams(params, &name, &keys, &threshold, &admin); err != nil {
+		return nil, fmt.Errorf("[ createMultiSigCall ]: %s", err.Error())
+	}
+	return rootDomain.CreateMultiSig(name, keys, threshold, admin)
+}
+
 func (m *Member) getMyBalanceCall() (interface{}, error) {
 	w, err := wallet.GetImplementationFrom(m.GetReference())
 	if err != nil {
@@ -215,3 +371,215 @@ func (m *Member) getNodeRefCall(ref core.RecordRef, params []byte) (interface{},
 
 	return nodeRef, nil
 }
+
+func (m *Member) getAssetRegistry(ref core.RecordRef) (*assetregistry.AssetRegistry, error) {
+	rootDomain := rootdomain.GetObject(ref)
+	assetRegistryRef, err := rootDomain.GetAssetRegistryRef()
+	if err != nil {
+		return nil, err
+	}
+	if assetRegistryRef.IsEmpty() {
+		return nil, fmt.Errorf("asset registry is not initialized")
+	}
+	return assetregistry.GetObject(assetRegistryRef), nil
+}
+
+func (m *Member) issueAssetCall(ref core.RecordRef, params []byte) (interface{}, error) {
+	var symbol string
+	var name string
+	var supply uint
+	if err := signer.UnmarshalParams(params, &symbol, &name, &supply); err != nil {
+		return nil, fmt.Errorf("[ issueAssetCall ] Can't unmarshal params: %s", err.Error())
+	}
+
+	ar, err := m.getAssetRegistry(ref)
+	if err != nil {
+		return nil, fmt.Errorf("[ issueAssetCall ] Can't get asset registry: %s", err.Error())
+	}
+
+	return nil, ar.IssueAsset(symbol, name, supply)
+}
+
+func (m *Member) getAssetsCall(ref core.RecordRef) (interface{}, error) {
+	ar, err := m.getAssetRegistry(ref)
+	if err != nil {
+		return nil, fmt.Errorf("[ getAssetsCall ] Can't get asset registry: %s", err.Error())
+	}
+	registered, err := ar.GetAssets()
+	if err != nil {
+		return nil, fmt.Errorf("[ getAssetsCall ] Can't get registered assets: %s", err.Error())
+	}
+
+	w, err := wallet.GetImplementationFrom(m.GetReference())
+	if err != nil {
+		return nil, fmt.Errorf("[ getAssetsCall ] Can't get implementation: %s", err.Error())
+	}
+	balances, err := w.GetAssets()
+	if err != nil {
+		return nil, fmt.Errorf("[ getAssetsCall ] Can't get asset balances: %s", err.Error())
+	}
+
+	var assets []map[string]interface{}
+	if err := json.Unmarshal(registered, &assets); err != nil {
+		return nil, fmt.Errorf("[ getAssetsCall ] Can't unmarshal assets: %s", err.Error())
+	}
+	for _, a := range assets {
+		symbol, _ := a["symbol"].(string)
+		a["balance"] = balances[symbol]
+	}
+
+	resJSON, err := json.Marshal(assets)
+	if err != nil {
+		return nil, fmt.Errorf("[ getAssetsCall ] Can't marshal res: %s", err.Error())
+	}
+	return resJSON, nil
+}
+
+func (m *Member) transferAssetCall(params []byte) (interface{}, error) {
+	var asset string
+	var amount uint
+	var toStr string
+	if err := signer.UnmarshalParams(params, &asset, &amount, &toStr); err != nil {
+		return nil, fmt.Errorf("[ transferAssetCall ] Can't unmarshal params: %s", err.Error())
+	}
+	to, err := core.NewRefFromBase58(toStr)
+	if err != nil {
+		return nil, fmt.Errorf("[ transferAssetCall ] Failed to parse 'to' param: %s", err.Error())
+	}
+	if m.GetReference() == *to {
+		return nil, fmt.Errorf("[ transferAssetCall ] Recipient must be different from the sender")
+	}
+	w, err := wallet.GetImplementationFrom(m.GetReference())
+	if err != nil {
+		return nil, fmt.Errorf("[ transferAssetCall ] Can't get implementation: %s", err.Error())
+	}
+
+	return nil, w.TransferAsset(asset, amount, to)
+}
+
+func (m *Member) getHistoryCall(params []byte) (interface{}, error) {
+	var fromPulse core.PulseNumber
+	var toPulse core.PulseNumber
+	var cursor string
+	var limit uint
+	if err := signer.UnmarshalParams(params, &fromPulse, &toPulse, &cursor, &limit); err != nil {
+		return nil, fmt.Errorf("[ getHistoryCall ] Can't unmarshal params: %s", err.Error())
+	}
+	w, err := wallet.GetImplementationFrom(m.GetReference())
+	if err != nil {
+		return nil, fmt.Errorf("[ getHistoryCall ] Can't get implementation: %s", err.Error())
+	}
+
+	return w.GetHistory(fromPulse, toPulse, cursor, limit)
+}
+
+// createEscrowCall holds amount for recipient. Empty asset means default currency, empty hash lock, zero
+// release pulse and time, and empty arbiter mean that the condition is not set. Timeout is a number
+// of seconds after which sender can refund escrow.
+func (m *Member) createEscrowCall(params []byte) (interface{}, error) {
+	var asset string
+	var amount uint
+	var toStr string
+	var hashLock string
+	var releasePulse core.PulseNumber
+	var releaseTime int64
+	var arbiterStr string
+	var timeout int64
+	if err := signer.UnmarshalParams(
+		params, &asset, &amount, &toStr, &hashLock, &releasePulse, &releaseTime, &arbiterStr, &timeout,
+	); err != nil {
+		return nil, fmt.Errorf("[ createEscrowCall ] Can't unmarshal params: %s", err.Error())
+	}
+	to, err := core.NewRefFromBase58(toStr)
+	if err != nil {
+		return nil, fmt.Errorf("[ createEscrowCall ] Failed to parse 'to' param: %s", err.Error())
+	}
+	if m.GetReference() == *to {
+		return nil, fmt.Errorf("[ createEscrowCall ] Recipient must be different from the sender")
+	}
+	arbiter := &core.RecordRef{}
+	if arbiterStr != "" {
+		arbiter, err = core.NewRefFromBase58(arbiterStr)
+		if err != nil {
+			return nil, fmt.Errorf("[ createEscrowCall ] Failed to parse 'arbiter' param: %s", err.Error())
+		}
+		if *arbiter == m.GetReference() || *arbiter == *to {
+			return nil, fmt.Errorf("[ createEscrowCall ] Arbiter must be different from the sender and the recipient")
+		}
+	}
+	if timeout <= 0 {
+		return nil, fmt.Errorf("[ createEscrowCall ] Timeout must be positive")
+	}
+
+	w, err := wallet.GetImplementationFrom(m.GetReference())
+	if err != nil {
+		return nil, fmt.Errorf("[ createEscrowCall ] Can't get implementation: %s", err.Error())
+	}
+	expire := m.GetContext().Time.Unix() + timeout
+	escrowRef, err := w.CreateEscrow(asset, amount, to, hashLock, releasePulse, releaseTime, arbiter, expire)
+	if err != nil {
+		return nil, fmt.Errorf("[ createEscrowCall ] %s", err.Error())
+	}
+	return escrowRef.String(), nil
+}
+
+func unmarshalEscrowRef(params []byte, rest ...interface{}) (*core.RecordRef, error) {
+	var escrowStr string
+	if err := signer.UnmarshalParams(params, append([]interface{}{&escrowStr}, rest...)...); err != nil {
+		return nil, fmt.Errorf("Can't unmarshal params: %s", err.Error())
+	}
+	escrowRef, err := core.NewRefFromBase58(escrowStr)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse 'escrow' param: %s", err.Error())
+	}
+	return escrowRef, nil
+}
+
+func (m *Member) releaseEscrowCall(params []byte) (interface{}, error) {
+	var preimage string
+	escrowRef, err := unmarshalEscrowRef(params, &preimage)
+	if err != nil {
+		return nil, fmt.Errorf("[ releaseEscrowCall ] %s", err.Error())
+	}
+	w, err := wallet.GetImplementationFrom(m.GetReference())
+	if err != nil {
+		return nil, fmt.Errorf("[ releaseEscrowCall ] Can't get implementation: %s", err.Error())
+	}
+	return nil, w.AcceptEscrow(escrowRef, preimage)
+}
+
+func (m *Member) refundEscrowCall(params []byte) (interface{}, error) {
+	escrowRef, err := unmarshalEscrowRef(params)
+	if err != nil {
+		return nil, fmt.Errorf("[ refundEscrowCall ] %s", err.Error())
+	}
+	w, err := wallet.GetImplementationFrom(m.GetReference())
+	if err != nil {
+		return nil, fmt.Errorf("[ refundEscrowCall ] Can't get implementation: %s", err.Error())
+	}
+	return nil, w.RefundEscrow(escrowRef)
+}
+
+func (m *Member) approveEscrowCall(params []byte) (interface{}, error) {
+	escrowRef, err := unmarshalEscrowRef(params)
+	if err != nil {
+		return nil, fmt.Errorf("[ approveEscrowCall ] %s", err.Error())
+	}
+	return nil, escrow.GetObject(*escrowRef).Approve()
+}
+
+func (m *Member) rejectEscrowCall(params []byte) (interface{}, error) {
+	escrowRef, err := unmarshalEscrowRef(params)
+	if err != nil {
+		return nil, fmt.Errorf("[ rejectEscrowCall ] %s", err.Error())
+	}
+	return nil, escrow.GetObject(*escrowRef).Reject()
+}
+
+func (m *Member) getEscrowCall(params []byte) (interface{}, error) {
+	escrowRef, err := unmarshalEscrowRef(params)
+	if err != nil {
+		return nil, fmt.Errorf("[ getEscrowCall ] %s", err.Error())
+	}
+	return escrow.GetObject(*escrowRef).GetInfo()
+}
@@ -0,0 +1,300 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package multisig
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/insolar/insolar/application/contract/member/signer"
+	"github.com/insolar/insolar/application/proxy/nodedomain"
+	"github.com/insolar/insolar/application/proxy/pendingcall"
+	"github.com/insolar/insolar/application/proxy/rootdomain"
+	"github.com/insolar/insolar/application/proxy/wallet"
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/logicrunner/goplugin/foundation"
+)
+
+// pendingCallTTL is a time in seconds given to co-signers to approve operation
+const pendingCallTTL = 24 * 60 * 60
+
+// MultiSig is a member controlled by several keys, operations are executed after Threshold approvals
+type MultiSig struct {
+	foundation.BaseContract
+	Name       string
+	PublicKeys []string
+	Threshold  uint
+}
+
+// New creates new multisig member
+func New(name string, keys []string, threshold uint) (*MultiSig, error) {
+	if threshold == 0 || threshold > uint(len(keys)) {
+		return nil, fmt.Errorf("[ New MultiSig ] Threshold must be between 1 and number of keys")
+	}
+	seen := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		if seen[k] {
+			return nil, fmt.Errorf("[ New MultiSig ] Keys must be unique")
+		}
+		seen[k] = true
+	}
+	return &MultiSig{
+		Name:       name,
+		PublicKeys: keys,
+		Threshold:  threshold,
+	}, nil
+}
+
+// GetName returns name of multisig member
+func (ms *MultiSig) GetName() (string, error) {
+	return ms.Name, nil
+}
+
+var INSATTR_GetPublicKeys_API = true
+
+// GetPublicKeys returns keys which can sign requests of multisig member
+func (ms *MultiSig) GetPublicKeys() ([]string, error) {
+	return ms.PublicKeys, nil
+}
+
+// GetThreshold returns number of approvals needed to execute operation
+func (ms *MultiSig) GetThreshold() (uint, error) {
+	return ms.Threshold, nil
+}
+
+// verifySig returns key which made the signature
+func (ms *MultiSig) verifySig(method string, params []byte, seed []byte, sign []byte) (string, error) {
+	args, err := core.MarshalArgs(ms.GetReference(), method, params, seed)
+	if err != nil {
+		return "", fmt.Errorf("[ verifySig ] Can't MarshalArgs: %s", err.Error())
+	}
+
+	for _, key := range ms.PublicKeys {
+		publicKey, err := foundation.ImportPublicKey(key)
+		if err != nil {
+			return "", fmt.Errorf("[ verifySig ] Invalid public key")
+		}
+		if foundation.Verify(args, sign, publicKey) {
+			return key, nil
+		}
+	}
+	return "", fmt.Errorf("[ verifySig ] Incorrect signature")
+}
+
+var INSATTR_Call_API = true
+
+// Call method for authorized calls. Transfer, RegisterNode and CreateMember are executed after Threshold
+// co-signers sent the same method with the same params.
+func (ms *MultiSig) Call(rootDomain core.RecordRef, method string, params []byte, seed []byte, sign []byte) (interface{}, error) {
+	key, err := ms.verifySig(method, params, seed, sign)
+	if err != nil {
+		return nil, fmt.Errorf("[ Call ]: %s", err.Error())
+	}
+
+	switch method {
+	case "GetMyBalance":
+		return ms.getMyBalanceCall()
+	case "GetPendingCalls":
+		return ms.getPendingCallsCall()
+	case "Transfer", "RegisterNode", "CreateMember":
+		return ms.approve(rootDomain, method, params, key)
+	}
+	return nil, &foundation.Error{S: "Unknown method"}
+}
+
+func (ms *MultiSig) findPendingCall(method string, params []byte) (*pendingcall.PendingCall, error) {
+	iterator, err := ms.NewChildrenTypedIterator(pendingcall.GetPrototype())
+	if err != nil {
+		return nil, fmt.Errorf("Can't get children: %s", err.Error())
+	}
+
+	for iterator.HasNext() {
+		cref, err := iterator.Next()
+		if err != nil {
+			return nil, fmt.Errorf("Can't get next child: %s", err.Error())
+		}
+		if cref.IsEmpty() {
+			continue
+		}
+
+		pc := pendingcall.GetObject(cref)
+		ok, err := pc.Matches(method, params)
+		if err != nil {
+			return nil, fmt.Errorf("Can't check pending call: %s", err.Error())
+		}
+		if ok {
+			return pc, nil
+		}
+	}
+	return nil, nil
+}
+
+func (ms *MultiSig) approve(rootDomain core.RecordRef, method string, params []byte, key string) (interface{}, error) {
+	pc, err := ms.findPendingCall(method, params)
+	if err != nil {
+		return nil, fmt.Errorf("[ approve ] %s", err.Error())
+	}
+	if pc == nil {
+		pcHolder := pendingcall.New(method, params, ms.GetContext().Time.Unix()+pendingCallTTL)
+		pc, err = pcHolder.AsChild(ms.GetReference())
+		if err != nil {
+			return nil, fmt.Errorf("[ approve ] Can't save as child: %s", err.Error())
+		}
+	}
+
+	approvals, err := pc.GetApprovals()
+	if err != nil {
+		return nil, fmt.Errorf("[ approve ] %s", err.Error())
+	}
+	// Approvals are already collected if previous execution has failed, then any co-signer retries it
+	if approvals < ms.Threshold {
+		approvals, err = pc.Approve(key)
+		if err != nil {
+			return nil, fmt.Errorf("[ approve ] %s", err.Error())
+		}
+	}
+	if approvals < ms.Threshold {
+		resJSON, err := json.Marshal(map[string]interface{}{
+			"pending_call": pc.GetReference().String(),
+			"approvals":    approvals,
+			"threshold":    ms.Threshold,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("[ approve ] Can't marshal res: %s", err.Error())
+		}
+		return resJSON, nil
+	}
+
+	res, err := ms.execute(rootDomain, method, params)
+	if err != nil {
+		return nil, fmt.Errorf("[ approve ] Can't execute pending call: %s", err.Error())
+	}
+	err = pc.Close()
+	if err != nil {
+		return nil, fmt.Errorf("[ approve ] Can't close pending call: %s", err.Error())
+	}
+	return res, nil
+}
+
+func (ms *MultiSig) execute(rootDomain core.RecordRef, method string, params []byte) (interface{}, error) {
+	switch method {
+	case "Transfer":
+		return ms.transferCall(params)
+	case "RegisterNode":
+		return ms.registerNodeCall(rootDomain, params)
+	case "CreateMember":
+		return ms.createMemberCall(rootDomain, params)
+	}
+	return nil, &foundation.Error{S: "Unknown method"}
+}
+
+func (ms *MultiSig) getMyBalanceCall() (interface{}, error) {
+	w, err := wallet.GetImplementationFrom(ms.GetReference())
+	if err != nil {
+		return 0, fmt.Errorf("[ getMyBalanceCall ]: %s", err.Error())
+	}
+
+	return w.GetBalance()
+}
+
+func (ms *MultiSig) getPendingCallsCall() (interface{}, error) {
+	iterator, err := ms.NewChildrenTypedIterator(pendingcall.GetPrototype())
+	if err != nil {
+		return nil, fmt.Errorf("[ getPendingCallsCall ] Can't get children: %s", err.Error())
+	}
+
+	res := []map[string]interface{}{}
+	for iterator.HasNext() {
+		cref, err := iterator.Next()
+		if err != nil {
+			return nil, fmt.Errorf("[ getPendingCallsCall ] Can't get next child: %s", err.Error())
+		}
+		if cref.IsEmpty() {
+			continue
+		}
+
+		info, err := pendingcall.GetObject(cref).GetInfo()
+		if err != nil {
+			return nil, fmt.Errorf("[ getPendingCallsCall ] Can't get info: %s", err.Error())
+		}
+		var callInfo map[string]interface{}
+		if err := json.Unmarshal(info, &callInfo); err != nil {
+			return nil, fmt.Errorf("[ getPendingCallsCall ] Can't unmarshal info: %s", err.Error())
+		}
+		callInfo["reference"] = cref.String()
+		res = append(res, callInfo)
+	}
+
+	resJSON, err := json.Marshal(res)
+	if err != nil {
+		return nil, fmt.Errorf("[ getPendingCallsCall ] Can't marshal res: %s", err.Error())
+	}
+	return resJSON, nil
+}
+
+func (ms *MultiSig) transferCall(params []byte) (interface{}, error) {
+	var amount uint
+	var toStr string
+	if err := signer.UnmarshalParams(params, &amount, &toStr); err != nil {
+		return nil, fmt.Errorf("[ transferCall ] Can't unmarshal params: %s", err.Error())
+	}
+	to, err := core.NewRefFromBase58(toStr)
+	if err != nil {
+		return nil, fmt.Errorf("[ transferCall ] Failed to parse 'to' param: %s", err.Error())
+	}
+	if ms.GetReference() == *to {
+		return nil, fmt.Errorf("[ transferCall ] Recipient must be different from the sender")
+	}
+	w, err := wallet.GetImplementationFrom(ms.GetReference())
+	if err != nil {
+		return nil, fmt.Errorf("[ transferCall ] Can't get implementation: %s", err.Error())
+	}
+
+	return nil, w.Transfer(amount, to)
+}
+
+func (ms *MultiSig) registerNodeCall(ref core.RecordRef, params []byte) (interface{}, error) {
+	var publicKey string
+	var role string
+	if err := signer.UnmarshalParams(params, &publicKey, &role); err != nil {
+		return nil, fmt.Errorf("[ registerNodeCall ] Can't unmarshal params: %s", err.Error())
+	}
+
+	rootDomain := rootdomain.GetObject(ref)
+	nodeDomainRef, err := rootDomain.GetNodeDomainRef()
+	if err != nil {
+		return nil, fmt.Errorf("[ registerNodeCall ] %s", err.Error())
+	}
+
+	nd := nodedomain.GetObject(nodeDomainRef)
+	cert, err := nd.RegisterNode(publicKey, role)
+	if err != nil {
+		return nil, fmt.Errorf("[ registerNodeCall ] Problems with RegisterNode: %s", err.Error())
+	}
+
+	return string(cert), nil
+}
+
+func (ms *MultiSig) createMemberCall(ref core.RecordRef, params []byte) (interface{}, error) {
+	rootDomain := rootdomain.GetObject(ref)
+	var name string
+	var key string
+	if err := signer.UnmarshalParams(params, &name, &key); err != nil {
+		return nil, fmt.Errorf("[ createMemberCall ]: %s", err.Error())
+	}
+	return rootDomain.CreateMember(name, key)
+}
@@ -46,12 +46,12 @@ func (nd *NodeDomain) getNodeRecord(ref core.RecordRef) *noderecord.NodeRecord {
 // RegisterNode registers node in system
 func (nd *NodeDomain) RegisterNode(publicKey string, role string) (string, error) {
 
-	root, err := rootdomain.GetObject(*nd.GetContext().Parent).GetRootMemberRef()
+	isAdmin, err := rootdomain.GetObject(*nd.GetContext().Parent).IsAdmin(*nd.GetContext().Caller)
 	if err != nil {
-		return "", fmt.Errorf("[ RegisterNode ] Couldn't get root member reference: %s", err.Error())
+		return "", fmt.Errorf("[ RegisterNode ] Couldn't check caller: %s", err.Error())
 	}
-	if *nd.GetContext().Caller != *root {
-		return "", fmt.Errorf("[ RegisterNode ] Only Root member can register node")
+	if !isAdmin {
+		return "", fmt.Errorf("[ RegisterNode ] Only Root member or admin multisig can register node")
 	}
 
 	newNode := noderecord.NewNodeRecord(publicKey, role)
@@ -0,0 +1,110 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package pendingcall
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/insolar/insolar/application/proxy/multisig"
+	"github.com/insolar/insolar/logicrunner/goplugin/foundation"
+)
+
+// PendingCall is an operation of multisig member waiting for enough approvals
+type PendingCall struct {
+	foundation.BaseContract
+	Method     string
+	Params     []byte
+	Approvals  []string
+	ExpireTime int64
+}
+
+func (pc *PendingCall) isExpired() bool {
+	return pc.GetContext().Time.After(time.Unix(pc.ExpireTime, 0))
+}
+
+func (pc *PendingCall) checkOwner(method string) error {
+	if *pc.GetContext().Caller != *pc.GetContext().Parent {
+		return fmt.Errorf("[ %s ] Only owner can manage pending call", method)
+	}
+	return nil
+}
+
+// Matches checks if pending call is not expired and wraps given method with given params
+func (pc *PendingCall) Matches(method string, params []byte) (bool, error) {
+	if pc.isExpired() {
+		return false, nil
+	}
+	return pc.Method == method && bytes.Equal(pc.Params, params), nil
+}
+
+// Approve adds approval of given key and returns number of collected approvals
+func (pc *PendingCall) Approve(key string) (uint, error) {
+	if err := pc.checkOwner("Approve"); err != nil {
+		return 0, err
+	}
+	if pc.isExpired() {
+		return 0, fmt.Errorf("[ Approve ] Pending call expired")
+	}
+	for _, k := range pc.Approvals {
+		if k == key {
+			return 0, fmt.Errorf("[ Approve ] Pending call is already approved by this key")
+		}
+	}
+	pc.Approvals = append(pc.Approvals, key)
+	return uint(len(pc.Approvals)), nil
+}
+
+// GetApprovals returns number of collected approvals
+func (pc *PendingCall) GetApprovals() (uint, error) {
+	return uint(len(pc.Approvals)), nil
+}
+
+// Close deletes pending call after execution or expiration
+func (pc *PendingCall) Close() error {
+	if err := pc.checkOwner("Close"); err != nil {
+		return err
+	}
+	pc.SelfDestruct()
+	return nil
+}
+
+// GetInfo returns description of pending call
+func (pc *PendingCall) GetInfo() ([]byte, error) {
+	res := map[string]interface{}{
+		"method":      pc.Method,
+		"params":      pc.Params,
+		"approvals":   len(pc.Approvals),
+		"expire_time": pc.ExpireTime,
+		"expired":     pc.isExpired(),
+	}
+	resJSON, err := json.Marshal(res)
+	if err != nil {
+		return nil, fmt.Errorf("[ GetInfo ] Can't marshal res: %s", err.Error())
+	}
+	return resJSON, nil
+}
+
+// New check is caller multisig member and makes new pending call
+func New(method string, params []byte, expire int64) (*PendingCall, error) {
+	if !multisig.PrototypeReference.Equal(*foundation.GetContext().CallerPrototype) {
+		return nil, fmt.Errorf("[ New PendingCall ] : Can't create pending call from not multisig contract")
+	}
+	return &PendingCall{Method: method, Params: params, ExpireTime: expire}, nil
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 
 	"github.com/insolar/insolar/application/proxy/member"
+	"github.com/insolar/insolar/application/proxy/multisig"
 	"github.com/insolar/insolar/application/proxy/wallet"
 	"github.com/insolar/insolar/core"
 	"github.com/insolar/insolar/logicrunner/goplugin/foundation"
@@ -29,14 +30,34 @@ import (
 // RootDomain is smart contract representing entrance point to system
 type RootDomain struct {
 	foundation.BaseContract
-	RootMember    core.RecordRef
-	NodeDomainRef core.RecordRef
+	RootMember       core.RecordRef
+	NodeDomainRef    core.RecordRef
+	AssetRegistryRef core.RecordRef
+	Admins           []core.RecordRef
+}
+
+// isAdmin checks if given member can act on behalf of root member
+func (rd *RootDomain) isAdmin(ref core.RecordRef) bool {
+	if ref == rd.RootMember {
+		return true
+	}
+	for _, admin := range rd.Admins {
+		if admin == ref {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAdmin returns true for root member and multisig members created by root as admins
+func (rd *RootDomain) IsAdmin(ref core.RecordRef) (bool, error) {
+	return rd.isAdmin(ref), nil
 }
 
 // CreateMember processes create member request
 func (rd *RootDomain) CreateMember(name string, key string) (string, error) {
-	if *rd.GetContext().Caller != rd.RootMember {
-		return "", fmt.Errorf("[ CreateMember ] Only Root member can create members")
+	if !rd.isAdmin(*rd.GetContext().Caller) {
+		return "", fmt.Errorf("[ CreateMember ] Only Root member or admin multisig can create members")
 	}
 	memberHolder := member.New(name, key)
 	m, err := memberHolder.AsChild(rd.GetReference())
@@ -53,6 +74,30 @@ func (rd *RootDomain) CreateMember(name string, key string) (string, error) {
 	return m.GetReference().String(), nil
 }
 
+// CreateMultiSig processes create multisig member request, admin multisig can create members and register nodes
+func (rd *RootDomain) CreateMultiSig(name string, keys []string, threshold uint, admin bool) (string, error) {
+	if *rd.GetContext().Caller != rd.RootMember {
+		return "", fmt.Errorf("[ CreateMultiSig ] Only Root member can create multisig members")
+	}
+	msHolder := multisig.New(name, keys, threshold)
+	ms, err := msHolder.AsChild(rd.GetReference())
+	if err != nil {
+		return "", fmt.Errorf("[ CreateMultiSig ] Can't save as child: %s", err.Error())
+	}
+
+	wHolder := wallet.New(0)
+	_, err = wHolder.AsDelegate(ms.GetReference())
+	if err != nil {
+		return "", fmt.Errorf("[ CreateMultiSig ] Can't save as delegate: %s", err.Error())
+	}
+
+	if admin {
+		rd.Admins = append(rd.Admins, ms.GetReference())
+	}
+
+	return ms.GetReference().String(), nil
+}
+
 // GetRootMemberRef returns root member's reference
 func (rd *RootDomain) GetRootMemberRef() (*core.RecordRef, error) {
 	return &rd.RootMember, nil
@@ -135,8 +180,9 @@ var INSATTR_Info_API = true
 // Info returns information about basic objects
 func (rd *RootDomain) Info() (interface{}, error) {
 	res := map[string]interface{}{
-		"root_member": rd.RootMember.String(),
-		"node_domain": rd.NodeDomainRef.String(),
+		"root_member":    rd.RootMember.String(),
+		"node_domain":    rd.NodeDomainRef.String(),
+		"asset_registry": rd.AssetRegistryRef.String(),
 	}
 	resJSON, err := json.Marshal(res)
 	if err != nil {
@@ -150,6 +196,11 @@ func (rd *RootDomain) GetNodeDomainRef() (core.RecordRef, error) {
 	return rd.NodeDomainRef, nil
 }
 
+// GetAssetRegistryRef returns reference of AssetRegistry instance
+func (rd *RootDomain) GetAssetRegistryRef() (core.RecordRef, error) {
+	return rd.AssetRegistryRef, nil
+}
+
 // NewRootDomain creates new RootDomain
 func NewRootDomain() (*RootDomain, error) {
 	return &RootDomain{}, nil
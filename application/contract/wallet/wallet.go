@@ -17,24 +17,54 @@
 package wallet
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/insolar/insolar/application/contract/wallet/safemath"
 	"github.com/insolar/insolar/application/proxy/allowance"
+	"github.com/insolar/insolar/application/proxy/assetregistry"
+	"github.com/insolar/insolar/application/proxy/escrow"
+	"github.com/insolar/insolar/application/proxy/historyentry"
 	"github.com/insolar/insolar/application/proxy/wallet"
 	"github.com/insolar/insolar/core"
 	"github.com/insolar/insolar/logicrunner/goplugin/foundation"
 )
 
+// Directions of history entries
+const (
+	DirectionIn      = "in"
+	DirectionOut     = "out"
+	DirectionReclaim = "reclaim"
+	DirectionMint    = "mint"
+)
+
+const (
+	defaultHistoryLimit = 100
+	maxHistoryLimit     = 1000
+)
+
 // Wallet - basic wallet contract
 type Wallet struct {
 	foundation.BaseContract
 	Balance uint
+	// Assets holds balances of registered assets by symbol
+	Assets map[string]uint
 }
 
 // Transfer transfers money to given wallet
 func (w *Wallet) Transfer(amount uint, to *core.RecordRef) error {
+	return w.transfer("", amount, to)
+}
 
+// TransferAsset transfers given amount of registered asset to given wallet
+func (w *Wallet) TransferAsset(asset string, amount uint, to *core.RecordRef) error {
+	if asset == "" {
+		return fmt.Errorf("[ TransferAsset ] Asset symbol is empty")
+	}
+	return w.transfer(asset, amount, to)
+}
+
+func (w *Wallet) transfer(asset string, amount uint, to *core.RecordRef) error {
 	toWallet, err := wallet.GetImplementationFrom(*to)
 	if err != nil {
 		return fmt.Errorf("[ Transfer ] Can't get implementation: %s", err.Error())
@@ -42,19 +72,23 @@ func (w *Wallet) Transfer(amount uint, to *core.RecordRef) error {
 
 	toWalletRef := toWallet.GetReference()
 
-	newBalance, err := safemath.Sub(w.Balance, amount)
+	newBalance, err := safemath.Sub(w.balanceOf(asset), amount)
 	if err != nil {
 		return fmt.Errorf("[ Transfer ] Not enough balance for transfer: %s", err.Error())
 	}
 
-	ah := allowance.New(&toWalletRef, amount, w.GetContext().Time.Unix()+10)
+	ah := allowance.NewAssetAllowance(&toWalletRef, w.GetContext().Parent, asset, amount, w.GetContext().Time.Unix()+10)
 	a, err := ah.AsChild(w.GetReference())
 	if err != nil {
 		return fmt.Errorf("[ Transfer ] Can't save as child: %s", err.Error())
 	}
 
 	// Changing balance only after allowance was successfully create
-	w.Balance = newBalance
+	w.setBalance(asset, newBalance)
+	err = w.record(DirectionOut, *to, asset, amount)
+	if err != nil {
+		return fmt.Errorf("[ Transfer ] %s", err.Error())
+	}
 
 	r := a.GetReference()
 	err = toWallet.AcceptNoWait(&r)
@@ -63,51 +97,319 @@ func (w *Wallet) Transfer(amount uint, to *core.RecordRef) error {
 
 // Accept transforms allowance to balance
 func (w *Wallet) Accept(aRef *core.RecordRef) error {
-	b, err := allowance.GetObject(*aRef).TakeAmount()
+	a := allowance.GetObject(*aRef)
+	asset, err := a.GetAsset()
+	if err != nil {
+		return fmt.Errorf("[ Accept ] Can't get asset: %s", err.Error())
+	}
+	from, err := a.GetFrom()
+	if err != nil {
+		return fmt.Errorf("[ Accept ] Can't get sender: %s", err.Error())
+	}
+	b, err := a.TakeAmount()
 	if err != nil {
 		return fmt.Errorf("[ Accept ] Can't take amount: %s", err.Error())
 	}
-	w.Balance, err = safemath.Add(w.Balance, b)
+	err = w.credit(asset, b)
 	if err != nil {
 		return fmt.Errorf("[ Accept ] Couldn't add amount to balance: %s", err.Error())
 	}
+	err = w.record(DirectionIn, from, asset, b)
+	if err != nil {
+		return fmt.Errorf("[ Accept ] %s", err.Error())
+	}
+	return nil
+}
+
+// CreateEscrow holds amount of asset for given member until release conditions are met, see escrow.Escrow
+func (w *Wallet) CreateEscrow(
+	asset string, amount uint, to *core.RecordRef,
+	hashLock string, releasePulse core.PulseNumber, releaseTime int64, arbiter *core.RecordRef, expire int64,
+) (core.RecordRef, error) {
+	toWallet, err := wallet.GetImplementationFrom(*to)
+	if err != nil {
+		return core.RecordRef{}, fmt.Errorf("[ CreateEscrow ] Can't get implementation: %s", err.Error())
+	}
+	toWalletRef := toWallet.GetReference()
+
+	newBalance, err := safemath.Sub(w.balanceOf(asset), amount)
+	if err != nil {
+		return core.RecordRef{}, fmt.Errorf("[ CreateEscrow ] Not enough balance for escrow: %s", err.Error())
+	}
+
+	eh := escrow.New(&toWalletRef, w.GetContext().Parent, asset, amount, hashLock, releasePulse, releaseTime, arbiter, expire)
+	e, err := eh.AsChild(w.GetReference())
+	if err != nil {
+		return core.RecordRef{}, fmt.Errorf("[ CreateEscrow ] Can't save as child: %s", err.Error())
+	}
+
+	// Changing balance only after escrow was successfully create
+	w.setBalance(asset, newBalance)
+	err = w.record(DirectionOut, *to, asset, amount)
+	if err != nil {
+		return core.RecordRef{}, fmt.Errorf("[ CreateEscrow ] %s", err.Error())
+	}
+
+	return e.GetReference(), nil
+}
+
+// AcceptEscrow releases escrow with given preimage and adds its amount to balance
+func (w *Wallet) AcceptEscrow(escrowRef *core.RecordRef, preimage string) error {
+	e := escrow.GetObject(*escrowRef)
+	asset, err := e.GetAsset()
+	if err != nil {
+		return fmt.Errorf("[ AcceptEscrow ] Can't get asset: %s", err.Error())
+	}
+	from, err := e.GetFrom()
+	if err != nil {
+		return fmt.Errorf("[ AcceptEscrow ] Can't get sender: %s", err.Error())
+	}
+	amount, err := e.Release(preimage)
+	if err != nil {
+		return fmt.Errorf("[ AcceptEscrow ] %s", err.Error())
+	}
+	err = w.credit(asset, amount)
+	if err != nil {
+		return fmt.Errorf("[ AcceptEscrow ] Couldn't add amount to balance: %s", err.Error())
+	}
+	err = w.record(DirectionIn, from, asset, amount)
+	if err != nil {
+		return fmt.Errorf("[ AcceptEscrow ] %s", err.Error())
+	}
+	return nil
+}
+
+// RefundEscrow returns amount of expired or rejected escrow to balance
+func (w *Wallet) RefundEscrow(escrowRef *core.RecordRef) error {
+	e := escrow.GetObject(*escrowRef)
+	asset, err := e.GetAsset()
+	if err != nil {
+		return fmt.Errorf("[ RefundEscrow ] Can't get asset: %s", err.Error())
+	}
+	amount, err := e.Refund()
+	if err != nil {
+		return fmt.Errorf("[ RefundEscrow ] %s", err.Error())
+	}
+	err = w.credit(asset, amount)
+	if err != nil {
+		return fmt.Errorf("[ RefundEscrow ] Couldn't add amount to balance: %s", err.Error())
+	}
+	err = w.record(DirectionReclaim, *escrowRef, asset, amount)
+	if err != nil {
+		return fmt.Errorf("[ RefundEscrow ] %s", err.Error())
+	}
+	return nil
+}
+
+// Mint adds newly issued asset to balance, only asset registry can call it
+func (w *Wallet) Mint(asset string, amount uint) error {
+	if !assetregistry.PrototypeReference.Equal(*w.GetContext().CallerPrototype) {
+		return fmt.Errorf("[ Mint ] Only asset registry can mint assets")
+	}
+	if asset == "" {
+		return fmt.Errorf("[ Mint ] Asset symbol is empty")
+	}
+	err := w.credit(asset, amount)
+	if err != nil {
+		return fmt.Errorf("[ Mint ] Couldn't add amount to balance: %s", err.Error())
+	}
+	err = w.record(DirectionMint, *w.GetContext().Caller, asset, amount)
+	if err != nil {
+		return fmt.Errorf("[ Mint ] %s", err.Error())
+	}
 	return nil
 }
 
 // GetBalance gets total balance
 func (w *Wallet) GetBalance() (uint, error) {
+	err := w.reclaimExpired()
+	if err != nil {
+		return 0, fmt.Errorf("[ GetBalance ] %s", err.Error())
+	}
+	return w.Balance, nil
+}
+
+// GetAssetBalance gets total balance of given asset
+func (w *Wallet) GetAssetBalance(asset string) (uint, error) {
+	err := w.reclaimExpired()
+	if err != nil {
+		return 0, fmt.Errorf("[ GetAssetBalance ] %s", err.Error())
+	}
+	return w.balanceOf(asset), nil
+}
+
+// GetAssets gets balances of all held assets
+func (w *Wallet) GetAssets() (map[string]uint, error) {
+	err := w.reclaimExpired()
+	if err != nil {
+		return nil, fmt.Errorf("[ GetAssets ] %s", err.Error())
+	}
+	assets := make(map[string]uint, len(w.Assets))
+	for symbol, amount := range w.Assets {
+		assets[symbol] = amount
+	}
+	return assets, nil
+}
+
+// GetHistory returns up to limit history entries with pulse in [fromPulse, toPulse], newest first.
+// Zero toPulse means no upper bound. Empty cursor starts from the newest entry, otherwise it's a reference
+// of the first entry to return. Result contains cursor for the next page, empty if there are no more entries.
+func (w *Wallet) GetHistory(fromPulse core.PulseNumber, toPulse core.PulseNumber, cursor string, limit uint) ([]byte, error) {
+	err := w.reclaimExpired()
+	if err != nil {
+		return nil, fmt.Errorf("[ GetHistory ] %s", err.Error())
+	}
+	if limit == 0 {
+		limit = defaultHistoryLimit
+	}
+	if limit > maxHistoryLimit {
+		limit = maxHistoryLimit
+	}
+
+	var from *core.RecordRef
+	if cursor != "" {
+		from, err = core.NewRefFromBase58(cursor)
+		if err != nil {
+			return nil, fmt.Errorf("[ GetHistory ] Bad cursor: %s", err.Error())
+		}
+	}
+
+	iterator, err := w.NewChildrenTypedIterator(historyentry.GetPrototype())
+	if err != nil {
+		return nil, fmt.Errorf("[ GetHistory ] Can't get children: %s", err.Error())
+	}
+
+	// Children are iterated from the newest one, so entries are ordered by pulse descending
+	// and pulse of entry is known from its reference without calling it.
+	entries := []json.RawMessage{}
+	next := ""
+	for iterator.HasNext() {
+		ref, err := iterator.Next()
+		if err != nil {
+			return nil, fmt.Errorf("[ GetHistory ] Can't get next child: %s", err.Error())
+		}
+		if ref.IsEmpty() {
+			continue
+		}
+		if from != nil {
+			if !ref.Equal(*from) {
+				continue
+			}
+			from = nil
+		}
+
+		pulse := ref.Record().Pulse()
+		if toPulse != 0 && pulse > toPulse {
+			continue
+		}
+		if pulse < fromPulse {
+			break
+		}
+		if uint(len(entries)) == limit {
+			next = ref.String()
+			break
+		}
+
+		info, err := historyentry.GetObject(ref).GetInfo()
+		if err != nil {
+			return nil, fmt.Errorf("[ GetHistory ] Can't get history entry: %s", err.Error())
+		}
+		entries = append(entries, info)
+	}
+
+	resJSON, err := json.Marshal(map[string]interface{}{
+		"entries":     entries,
+		"next_cursor": next,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("[ GetHistory ] Can't marshal res: %s", err.Error())
+	}
+	return resJSON, nil
+}
+
+// record saves history entry of current request as child of the wallet
+func (w *Wallet) record(direction string, counterparty core.RecordRef, asset string, amount uint) error {
+	request := core.RecordRef{}
+	if r := w.GetContext().Request; r != nil {
+		request = *r
+	}
+	_, err := historyentry.New(direction, &counterparty, asset, amount, &request).AsChild(w.GetReference())
+	if err != nil {
+		return fmt.Errorf("Can't save history entry: %s", err.Error())
+	}
+	return nil
+}
+
+// reclaimExpired returns amounts of expired outgoing allowances to balances
+func (w *Wallet) reclaimExpired() error {
 	iterator, err := w.NewChildrenTypedIterator(allowance.GetPrototype())
 	if err != nil {
-		return 0, fmt.Errorf("[ GetBalance ] Can't get children: %s", err.Error())
+		return fmt.Errorf("Can't get children: %s", err.Error())
 	}
 
 	for iterator.HasNext() {
 		cref, err := iterator.Next()
 		if err != nil {
-			return 0, fmt.Errorf("[ GetBalance ] Can't get next child: %s", err.Error())
+			return fmt.Errorf("Can't get next child: %s", err.Error())
 		}
 
 		if !cref.IsEmpty() {
 			a := allowance.GetObject(cref)
 			balance, err := a.GetExpiredBalance()
 
+			if err != nil || balance == 0 {
+				continue
+			}
+
+			asset, err := a.GetAsset()
 			if err != nil {
-				balance = 0
-				//return 0, fmt.Errorf("[ GetBalance ] Can't get balance for owner: %s", err.Error())
+				return fmt.Errorf("Can't get asset of allowance: %s", err.Error())
 			}
 
-			w.Balance, err = safemath.Add(w.Balance, balance)
+			err = w.credit(asset, balance)
 			if err != nil {
-				return 0, fmt.Errorf("[ GetBalance ] Couldn't add expired allowance to balance: %s", err.Error())
+				return fmt.Errorf("Couldn't add expired allowance to balance: %s", err.Error())
+			}
+			err = w.record(DirectionReclaim, cref, asset, balance)
+			if err != nil {
+				return err
 			}
 		}
 	}
-	return w.Balance, nil
+	return nil
+}
+
+func (w *Wallet) balanceOf(asset string) uint {
+	if asset == "" {
+		return w.Balance
+	}
+	return w.Assets[asset]
+}
+
+func (w *Wallet) setBalance(asset string, amount uint) {
+	if asset == "" {
+		w.Balance = amount
+		return
+	}
+	if w.Assets == nil {
+		w.Assets = make(map[string]uint)
+	}
+	w.Assets[asset] = amount
+}
+
+func (w *Wallet) credit(asset string, amount uint) error {
+	b, err := safemath.Add(w.balanceOf(asset), amount)
+	if err != nil {
+		return err
+	}
+	w.setBalance(asset, b)
+	return nil
 }
 
 // New creates new allowance
 func New(balance uint) (*Wallet, error) {
 	return &Wallet{
 		Balance: balance,
+		Assets:  make(map[string]uint),
 	}, nil
 }
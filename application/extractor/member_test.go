@@ -62,6 +62,31 @@ func TestPublicKeyResponse_UnmarshalError(t *testing.T) {
 	require.Equal(t, "", result)
 }
 
+func TestPublicKeysResponse(t *testing.T) {
+	testValue := []string{"first_public_key", "second_public_key"}
+
+	data, err := core.Serialize([]interface{}{testValue, nil})
+	require.NoError(t, err)
+
+	result, err := PublicKeysResponse(data)
+
+	require.NoError(t, err)
+	require.Equal(t, testValue, result)
+}
+
+func TestPublicKeysResponse_ErrorResponse(t *testing.T) {
+	contractErr := &foundation.Error{S: "Custom test error"}
+
+	data, err := core.Serialize([]interface{}{[]string{}, contractErr})
+	require.NoError(t, err)
+
+	result, err := PublicKeysResponse(data)
+
+	require.Contains(t, err.Error(), "Has error in response")
+	require.Contains(t, err.Error(), "Custom test error")
+	require.Nil(t, result)
+}
+
 func TestCallResponse(t *testing.T) {
 	testValue := map[interface{}]interface{}{
 		"string_value": "test_string",
@@ -8,7 +8,7 @@ import (
 
 // PrototypeReference to prototype of this contract
 // error checking hides in generator
-var PrototypeReference, _ = core.NewRefFromBase58("1111237ibkogZ3cs3LKSrEdKwv5eiKzAsNAA9tG6pxJ.11111111111111111111111111111111")
+var PrototypeReference, _ = core.NewRefFromBase58("11113b5ZCAyRmKkRWWkXaPccv73Ge4b4SusS7gU7WXu.11111111111111111111111111111111")
 
 // Allowance holds proxy type
 type Allowance struct {
@@ -76,6 +76,24 @@ func New(to *core.RecordRef, amount uint, expire int64) *ContractConstructorHold
 	return &ContractConstructorHolder{constructorName: "New", argsSerialized: argsSerialized}
 }
 
+// NewAssetAllowance is constructor
+func NewAssetAllowance(to *core.RecordRef, from *core.RecordRef, asset string, amount uint, expire int64) *ContractConstructorHolder {
+	var args [5]interface{}
+	args[0] = to
+	args[1] = from
+	args[2] = asset
+	args[3] = amount
+	args[4] = expire
+
+	var argsSerialized []byte
+	err := proxyctx.Current.Serialize(args, &argsSerialized)
+	if err != nil {
+		panic(err)
+	}
+
+	return &ContractConstructorHolder{constructorName: "NewAssetAllowance", argsSerialized: argsSerialized}
+}
+
 // GetReference returns reference of the object
 func (r *Allowance) GetReference() core.RecordRef {
 	return r.Reference
@@ -192,6 +210,110 @@ func (r *Allowance) TakeAmountNoWait() error {
 	return nil
 }
 
+// GetAsset is proxy generated method
+func (r *Allowance) GetAsset() (string, error) {
+	var args [0]interface{}
+
+	var argsSerialized []byte
+
+	ret := [2]interface{}{}
+	var ret0 string
+	ret[0] = &ret0
+	var ret1 *foundation.Error
+	ret[1] = &ret1
+
+	err := proxyctx.Current.Serialize(args, &argsSerialized)
+	if err != nil {
+		return ret0, err
+	}
+
+	res, err := proxyctx.Current.RouteCall(r.Reference, true, "GetAsset", argsSerialized, *PrototypeReference)
+	if err != nil {
+		return ret0, err
+	}
+
+	err = proxyctx.Current.Deserialize(res, &ret)
+	if err != nil {
+		return ret0, err
+	}
+
+	if ret1 != nil {
+		return ret0, ret1
+	}
+	return ret0, nil
+}
+
+// GetAssetNoWait is proxy generated method
+func (r *Allowance) GetAssetNoWait() error {
+	var args [0]interface{}
+
+	var argsSerialized []byte
+
+	err := proxyctx.Current.Serialize(args, &argsSerialized)
+	if err != nil {
+		return err
+	}
+
+	_, err = proxyctx.Current.RouteCall(r.Reference, false, "GetAsset", argsSerialized, *PrototypeReference)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetFrom is proxy generated method
+func (r *Allowance) GetFrom() (core.RecordRef, error) {
+	var args [0]interface{}
+
+	var argsSerialized []byte
+
+	ret := [2]interface{}{}
+	var ret0 core.RecordRef
+	ret[0] = &ret0
+	var ret1 *foundation.Error
+	ret[1] = &ret1
+
+	err := proxyctx.Current.Serialize(args, &argsSerialized)
+	if err != nil {
+		return ret0, err
+	}
+
+	res, err := proxyctx.Current.RouteCall(r.Reference, true, "GetFrom", argsSerialized, *PrototypeReference)
+	if err != nil {
+		return ret0, err
+	}
+
+	err = proxyctx.Current.Deserialize(res, &ret)
+	if err != nil {
+		return ret0, err
+	}
+
+	if ret1 != nil {
+		return ret0, ret1
+	}
+	return ret0, nil
+}
+
+// GetFromNoWait is proxy generated method
+func (r *Allowance) GetFromNoWait() error {
+	var args [0]interface{}
+
+	var argsSerialized []byte
+
+	err := proxyctx.Current.Serialize(args, &argsSerialized)
+	if err != nil {
+		return err
+	}
+
+	_, err = proxyctx.Current.RouteCall(r.Reference, false, "GetFrom", argsSerialized, *PrototypeReference)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // GetBalanceForOwner is proxy generated method
 func (r *Allowance) GetBalanceForOwner() (uint, error) {
 	var args [0]interface{}
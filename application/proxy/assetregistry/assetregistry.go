@@ -0,0 +1,307 @@
+package assetregistry
+
+import (
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/logicrunner/goplugin/foundation"
+	"github.com/insolar/insolar/logicrunner/goplugin/proxyctx"
+)
+
+type Asset struct {
+	Symbol string
+	Name   string
+	Issuer core.RecordRef
+	Supply uint
+}
+
+// PrototypeReference to prototype of this contract
+// error checking hides in generator
+var PrototypeReference, _ = core.NewRefFromBase58("11113PHvvYZcTMJ32dywhFdNiDZSNeq2Zjy7VR38Ly2.11111111111111111111111111111111")
+
+// AssetRegistry holds proxy type
+type AssetRegistry struct {
+	Reference core.RecordRef
+	Prototype core.RecordRef
+	Code      core.RecordRef
+}
+
+// ContractConstructorHolder holds logic with object construction
+type ContractConstructorHolder struct {
+	constructorName string
+	argsSerialized  []byte
+}
+
+// AsChild saves object as child
+func (r *ContractConstructorHolder) AsChild(objRef core.RecordRef) (*AssetRegistry, error) {
+	ref, err := proxyctx.Current.SaveAsChild(objRef, *PrototypeReference, r.constructorName, r.argsSerialized)
+	if err != nil {
+		return nil, err
+	}
+	return &AssetRegistry{Reference: ref}, nil
+}
+
+// AsDelegate saves object as delegate
+func (r *ContractConstructorHolder) AsDelegate(objRef core.RecordRef) (*AssetRegistry, error) {
+	ref, err := proxyctx.Current.SaveAsDelegate(objRef, *PrototypeReference, r.constructorName, r.argsSerialized)
+	if err != nil {
+		return nil, err
+	}
+	return &AssetRegistry{Reference: ref}, nil
+}
+
+// GetObject returns proxy object
+func GetObject(ref core.RecordRef) (r *AssetRegistry) {
+	return &AssetRegistry{Reference: ref}
+}
+
+// GetPrototype returns reference to the prototype
+func GetPrototype() core.RecordRef {
+	return *PrototypeReference
+}
+
+// GetImplementationFrom returns proxy to delegate of given type
+func GetImplementationFrom(object core.RecordRef) (*AssetRegistry, error) {
+	ref, err := proxyctx.Current.GetDelegate(object, *PrototypeReference)
+	if err != nil {
+		return nil, err
+	}
+	return GetObject(ref), nil
+}
+
+// NewAssetRegistry is constructor
+func NewAssetRegistry() *ContractConstructorHolder {
+	var args [0]interface{}
+
+	var argsSerialized []byte
+	err := proxyctx.Current.Serialize(args, &argsSerialized)
+	if err != nil {
+		panic(err)
+	}
+
+	return &ContractConstructorHolder{constructorName: "NewAssetRegistry", argsSerialized: argsSerialized}
+}
+
+// GetReference returns reference of the object
+func (r *AssetRegistry) GetReference() core.RecordRef {
+	return r.Reference
+}
+
+// GetPrototype returns reference to the code
+func (r *AssetRegistry) GetPrototype() (core.RecordRef, error) {
+	if r.Prototype.IsEmpty() {
+		ret := [2]interface{}{}
+		var ret0 core.RecordRef
+		ret[0] = &ret0
+		var ret1 *foundation.Error
+		ret[1] = &ret1
+
+		res, err := proxyctx.Current.RouteCall(r.Reference, true, "GetPrototype", make([]byte, 0), *PrototypeReference)
+		if err != nil {
+			return ret0, err
+		}
+
+		err = proxyctx.Current.Deserialize(res, &ret)
+		if err != nil {
+			return ret0, err
+		}
+
+		if ret1 != nil {
+			return ret0, ret1
+		}
+
+		r.Prototype = ret0
+	}
+
+	return r.Prototype, nil
+
+}
+
+// GetCode returns reference to the code
+func (r *AssetRegistry) GetCode() (core.RecordRef, error) {
+	if r.Code.IsEmpty() {
+		ret := [2]interface{}{}
+		var ret0 core.RecordRef
+		ret[0] = &ret0
+		var ret1 *foundation.Error
+		ret[1] = &ret1
+
+		res, err := proxyctx.Current.RouteCall(r.Reference, true, "GetCode", make([]byte, 0), *PrototypeReference)
+		if err != nil {
+			return ret0, err
+		}
+
+		err = proxyctx.Current.Deserialize(res, &ret)
+		if err != nil {
+			return ret0, err
+		}
+
+		if ret1 != nil {
+			return ret0, ret1
+		}
+
+		r.Code = ret0
+	}
+
+	return r.Code, nil
+}
+
+// IssueAsset is proxy generated method
+func (r *AssetRegistry) IssueAsset(symbol string, name string, supply uint) error {
+	var args [3]interface{}
+	args[0] = symbol
+	args[1] = name
+	args[2] = supply
+
+	var argsSerialized []byte
+
+	ret := [1]interface{}{}
+	var ret0 *foundation.Error
+	ret[0] = &ret0
+
+	err := proxyctx.Current.Serialize(args, &argsSerialized)
+	if err != nil {
+		return err
+	}
+
+	res, err := proxyctx.Current.RouteCall(r.Reference, true, "IssueAsset", argsSerialized, *PrototypeReference)
+	if err != nil {
+		return err
+	}
+
+	err = proxyctx.Current.Deserialize(res, &ret)
+	if err != nil {
+		return err
+	}
+
+	if ret0 != nil {
+		return ret0
+	}
+	return nil
+}
+
+// IssueAssetNoWait is proxy generated method
+func (r *AssetRegistry) IssueAssetNoWait(symbol string, name string, supply uint) error {
+	var args [3]interface{}
+	args[0] = symbol
+	args[1] = name
+	args[2] = supply
+
+	var argsSerialized []byte
+
+	err := proxyctx.Current.Serialize(args, &argsSerialized)
+	if err != nil {
+		return err
+	}
+
+	_, err = proxyctx.Current.RouteCall(r.Reference, false, "IssueAsset", argsSerialized, *PrototypeReference)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// HasAsset is proxy generated method
+func (r *AssetRegistry) HasAsset(symbol string) (bool, error) {
+	var args [1]interface{}
+	args[0] = symbol
+
+	var argsSerialized []byte
+
+	ret := [2]interface{}{}
+	var ret0 bool
+	ret[0] = &ret0
+	var ret1 *foundation.Error
+	ret[1] = &ret1
+
+	err := proxyctx.Current.Serialize(args, &argsSerialized)
+	if err != nil {
+		return ret0, err
+	}
+
+	res, err := proxyctx.Current.RouteCall(r.Reference, true, "HasAsset", argsSerialized, *PrototypeReference)
+	if err != nil {
+		return ret0, err
+	}
+
+	err = proxyctx.Current.Deserialize(res, &ret)
+	if err != nil {
+		return ret0, err
+	}
+
+	if ret1 != nil {
+		return ret0, ret1
+	}
+	return ret0, nil
+}
+
+// HasAssetNoWait is proxy generated method
+func (r *AssetRegistry) HasAssetNoWait(symbol string) error {
+	var args [1]interface{}
+	args[0] = symbol
+
+	var argsSerialized []byte
+
+	err := proxyctx.Current.Serialize(args, &argsSerialized)
+	if err != nil {
+		return err
+	}
+
+	_, err = proxyctx.Current.RouteCall(r.Reference, false, "HasAsset", argsSerialized, *PrototypeReference)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetAssets is proxy generated method
+func (r *AssetRegistry) GetAssets() ([]byte, error) {
+	var args [0]interface{}
+
+	var argsSerialized []byte
+
+	ret := [2]interface{}{}
+	var ret0 []byte
+	ret[0] = &ret0
+	var ret1 *foundation.Error
+	ret[1] = &ret1
+
+	err := proxyctx.Current.Serialize(args, &argsSerialized)
+	if err != nil {
+		return ret0, err
+	}
+
+	res, err := proxyctx.Current.RouteCall(r.Reference, true, "GetAssets", argsSerialized, *PrototypeReference)
+	if err != nil {
+		return ret0, err
+	}
+
+	err = proxyctx.Current.Deserialize(res, &ret)
+	if err != nil {
+		return ret0, err
+	}
+
+	if ret1 != nil {
+		return ret0, ret1
+	}
+	return ret0, nil
+}
+
+// GetAssetsNoWait is proxy generated method
+func (r *AssetRegistry) GetAssetsNoWait() error {
+	var args [0]interface{}
+
+	var argsSerialized []byte
+
+	err := proxyctx.Current.Serialize(args, &argsSerialized)
+	if err != nil {
+		return err
+	}
+
+	_, err = proxyctx.Current.RouteCall(r.Reference, false, "GetAssets", argsSerialized, *PrototypeReference)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -8,7 +8,7 @@ import (
 
 // PrototypeReference to prototype of this contract
 // error checking hides in generator
-var PrototypeReference, _ = core.NewRefFromBase58("11112dk9h57aFWtx8WwsecAYNbTvCsgnqXopNuHZZiq.11111111111111111111111111111111")
+var PrototypeReference, _ = core.NewRefFromBase58("11112WEkSfdKGRZhTA4sAYGTdTHyvs2P1DGXfn7u9XD.11111111111111111111111111111111")
 
 // RootDomain holds proxy type
 type RootDomain struct {
@@ -137,6 +137,60 @@ func (r *RootDomain) GetCode() (core.RecordRef, error) {
 	return r.Code, nil
 }
 
+// IsAdmin is proxy generated method
+func (r *RootDomain) IsAdmin(ref core.RecordRef) (bool, error) {
+	var args [1]interface{}
+	args[0] = ref
+
+	var argsSerialized []byte
+
+	ret := [2]interface{}{}
+	var ret0 bool
+	ret[0] = &ret0
+	var ret1 *foundation.Error
+	ret[1] = &ret1
+
+	err := proxyctx.Current.Serialize(args, &argsSerialized)
+	if err != nil {
+		return ret0, err
+	}
+
+	res, err := proxyctx.Current.RouteCall(r.Reference, true, "IsAdmin", argsSerialized, *PrototypeReference)
+	if err != nil {
+		return ret0, err
+	}
+
+	err = proxyctx.Current.Deserialize(res, &ret)
+	if err != nil {
+		return ret0, err
+	}
+
+	if ret1 != nil {
+		return ret0, ret1
+	}
+	return ret0, nil
+}
+
+// IsAdminNoWait is proxy generated method
+func (r *RootDomain) IsAdminNoWait(ref core.RecordRef) error {
+	var args [1]interface{}
+	args[0] = ref
+
+	var argsSerialized []byte
+
+	err := proxyctx.Current.Serialize(args, &argsSerialized)
+	if err != nil {
+		return err
+	}
+
+	_, err = proxyctx.Current.RouteCall(r.Reference, false, "IsAdmin", argsSerialized, *PrototypeReference)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // CreateMember is proxy generated method
 func (r *RootDomain) CreateMember(name string, key string) (string, error) {
 	var args [2]interface{}
@@ -193,6 +247,66 @@ func (r *RootDomain) CreateMemberNoWait(name string, key string) error {
 	return nil
 }
 
+// CreateMultiSig is proxy generated method
+func (r *RootDomain) CreateMultiSig(name string, keys []string, threshold uint, admin bool) (string, error) {
+	var args [4]interface{}
+	args[0] = name
+	args[1] = keys
+	args[2] = threshold
+	args[3] = admin
+
+	var argsSerialized []byte
+
+	ret := [2]interface{}{}
+	var ret0 string
+	ret[0] = &ret0
+	var ret1 *foundation.Error
+	ret[1] = &ret1
+
+	err := proxyctx.Current.Serialize(args, &argsSerialized)
+	if err != nil {
+		return ret0, err
+	}
+
+	res, err := proxyctx.Current.RouteCall(r.Reference, true, "CreateMultiSig", argsSerialized, *PrototypeReference)
+	if err != nil {
+		return ret0, err
+	}
+
+	err = proxyctx.Current.Deserialize(res, &ret)
+	if err != nil {
+		return ret0, err
+	}
+
+	if ret1 != nil {
+		return ret0, ret1
+	}
+	return ret0, nil
+}
+
+// CreateMultiSigNoWait is proxy generated method
+func (r *RootDomain) CreateMultiSigNoWait(name string, keys []string, threshold uint, admin bool) error {
+	var args [4]interface{}
+	args[0] = name
+	args[1] = keys
+	args[2] = threshold
+	args[3] = admin
+
+	var argsSerialized []byte
+
+	err := proxyctx.Current.Serialize(args, &argsSerialized)
+	if err != nil {
+		return err
+	}
+
+	_, err = proxyctx.Current.RouteCall(r.Reference, false, "CreateMultiSig", argsSerialized, *PrototypeReference)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // GetRootMemberRef is proxy generated method
 func (r *RootDomain) GetRootMemberRef() (*core.RecordRef, error) {
 	var args [0]interface{}
@@ -454,3 +568,55 @@ func (r *RootDomain) GetNodeDomainRefNoWait() error {
 
 	return nil
 }
+
+// GetAssetRegistryRef is proxy generated method
+func (r *RootDomain) GetAssetRegistryRef() (core.RecordRef, error) {
+	var args [0]interface{}
+
+	var argsSerialized []byte
+
+	ret := [2]interface{}{}
+	var ret0 core.RecordRef
+	ret[0] = &ret0
+	var ret1 *foundation.Error
+	ret[1] = &ret1
+
+	err := proxyctx.Current.Serialize(args, &argsSerialized)
+	if err != nil {
+		return ret0, err
+	}
+
+	res, err := proxyctx.Current.RouteCall(r.Reference, true, "GetAssetRegistryRef", argsSerialized, *PrototypeReference)
+	if err != nil {
+		return ret0, err
+	}
+
+	err = proxyctx.Current.Deserialize(res, &ret)
+	if err != nil {
+		return ret0, err
+	}
+
+	if ret1 != nil {
+		return ret0, ret1
+	}
+	return ret0, nil
+}
+
+// GetAssetRegistryRefNoWait is proxy generated method
+func (r *RootDomain) GetAssetRegistryRefNoWait() error {
+	var args [0]interface{}
+
+	var argsSerialized []byte
+
+	err := proxyctx.Current.Serialize(args, &argsSerialized)
+	if err != nil {
+		return err
+	}
+
+	_, err = proxyctx.Current.RouteCall(r.Reference, false, "GetAssetRegistryRef", argsSerialized, *PrototypeReference)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -8,7 +8,7 @@ import (
 
 // PrototypeReference to prototype of this contract
 // error checking hides in generator
-var PrototypeReference, _ = core.NewRefFromBase58("1111gf5wY3hRPnWsn21LhpAqhum2PF4ZdNp7pM5yVD.11111111111111111111111111111111")
+var PrototypeReference, _ = core.NewRefFromBase58("1111UPUm7rQwSNE7KNKoge6hVuHvsRJBo7Ee3fe3Re.11111111111111111111111111111111")
 
 // Wallet holds proxy type
 type Wallet struct {
@@ -192,6 +192,62 @@ func (r *Wallet) TransferNoWait(amount uint, to *core.RecordRef) error {
 	return nil
 }
 
+// TransferAsset is proxy generated method
+func (r *Wallet) TransferAsset(asset string, amount uint, to *core.RecordRef) error {
+	var args [3]interface{}
+	args[0] = asset
+	args[1] = amount
+	args[2] = to
+
+	var argsSerialized []byte
+
+	ret := [1]interface{}{}
+	var ret0 *foundation.Error
+	ret[0] = &ret0
+
+	err := proxyctx.Current.Serialize(args, &argsSerialized)
+	if err != nil {
+		return err
+	}
+
+	res, err := proxyctx.Current.RouteCall(r.Reference, true, "TransferAsset", argsSerialized, *PrototypeReference)
+	if err != nil {
+		return err
+	}
+
+	err = proxyctx.Current.Deserialize(res, &ret)
+	if err != nil {
+		return err
+	}
+
+	if ret0 != nil {
+		return ret0
+	}
+	return nil
+}
+
+// TransferAssetNoWait is proxy generated method
+func (r *Wallet) TransferAssetNoWait(asset string, amount uint, to *core.RecordRef) error {
+	var args [3]interface{}
+	args[0] = asset
+	args[1] = amount
+	args[2] = to
+
+	var argsSerialized []byte
+
+	err := proxyctx.Current.Serialize(args, &argsSerialized)
+	if err != nil {
+		return err
+	}
+
+	_, err = proxyctx.Current.RouteCall(r.Reference, false, "TransferAsset", argsSerialized, *PrototypeReference)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // Accept is proxy generated method
 func (r *Wallet) Accept(aRef *core.RecordRef) error {
 	var args [1]interface{}
@@ -244,6 +300,234 @@ func (r *Wallet) AcceptNoWait(aRef *core.RecordRef) error {
 	return nil
 }
 
+// CreateEscrow is proxy generated method
+func (r *Wallet) CreateEscrow(asset string, amount uint, to *core.RecordRef, hashLock string, releasePulse core.PulseNumber, releaseTime int64, arbiter *core.RecordRef, expire int64) (core.RecordRef, error) {
+	var args [8]interface{}
+	args[0] = asset
+	args[1] = amount
+	args[2] = to
+	args[3] = hashLock
+	args[4] = releasePulse
+	args[5] = releaseTime
+	args[6] = arbiter
+	args[7] = expire
+
+	var argsSerialized []byte
+
+	ret := [2]interface{}{}
+	var ret0 core.RecordRef
+	ret[0] = &ret0
+	var ret1 *foundation.Error
+	ret[1] = &ret1
+
+	err := proxyctx.Current.Serialize(args, &argsSerialized)
+	if err != nil {
+		return ret0, err
+	}
+
+	res, err := proxyctx.Current.RouteCall(r.Reference, true, "CreateEscrow", argsSerialized, *PrototypeReference)
+	if err != nil {
+		return ret0, err
+	}
+
+	err = proxyctx.Current.Deserialize(res, &ret)
+	if err != nil {
+		return ret0, err
+	}
+
+	if ret1 != nil {
+		return ret0, ret1
+	}
+	return ret0, nil
+}
+
+// CreateEscrowNoWait is proxy generated method
+func (r *Wallet) CreateEscrowNoWait(asset string, amount uint, to *core.RecordRef, hashLock string, releasePulse core.PulseNumber, releaseTime int64, arbiter *core.RecordRef, expire int64) error {
+	var args [8]interface{}
+	args[0] = asset
+	args[1] = amount
+	args[2] = to
+	args[3] = hashLock
+	args[4] = releasePulse
+	args[5] = releaseTime
+	args[6] = arbiter
+	args[7] = expire
+
+	var argsSerialized []byte
+
+	err := proxyctx.Current.Serialize(args, &argsSerialized)
+	if err != nil {
+		return err
+	}
+
+	_, err = proxyctx.Current.RouteCall(r.Reference, false, "CreateEscrow", argsSerialized, *PrototypeReference)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// AcceptEscrow is proxy generated method
+func (r *Wallet) AcceptEscrow(escrowRef *core.RecordRef, preimage string) error {
+	var args [2]interface{}
+	args[0] = escrowRef
+	args[1] = preimage
+
+	var argsSerialized []byte
+
+	ret := [1]interface{}{}
+	var ret0 *foundation.Error
+	ret[0] = &ret0
+
+	err := proxyctx.Current.Serialize(args, &argsSerialized)
+	if err != nil {
+		return err
+	}
+
+	res, err := proxyctx.Current.RouteCall(r.Reference, true, "AcceptEscrow", argsSerialized, *PrototypeReference)
+	if err != nil {
+		return err
+	}
+
+	err = proxyctx.Current.Deserialize(res, &ret)
+	if err != nil {
+		return err
+	}
+
+	if ret0 != nil {
+		return ret0
+	}
+	return nil
+}
+
+// AcceptEscrowNoWait is proxy generated method
+func (r *Wallet) AcceptEscrowNoWait(escrowRef *core.RecordRef, preimage string) error {
+	var args [2]interface{}
+	args[0] = escrowRef
+	args[1] = preimage
+
+	var argsSerialized []byte
+
+	err := proxyctx.Current.Serialize(args, &argsSerialized)
+	if err != nil {
+		return err
+	}
+
+	_, err = proxyctx.Current.RouteCall(r.Reference, false, "AcceptEscrow", argsSerialized, *PrototypeReference)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RefundEscrow is proxy generated method
+func (r *Wallet) RefundEscrow(escrowRef *core.RecordRef) error {
+	var args [1]interface{}
+	args[0] = escrowRef
+
+	var argsSerialized []byte
+
+	ret := [1]interface{}{}
+	var ret0 *foundation.Error
+	ret[0] = &ret0
+
+	err := proxyctx.Current.Serialize(args, &argsSerialized)
+	if err != nil {
+		return err
+	}
+
+	res, err := proxyctx.Current.RouteCall(r.Reference, true, "RefundEscrow", argsSerialized, *PrototypeReference)
+	if err != nil {
+		return err
+	}
+
+	err = proxyctx.Current.Deserialize(res, &ret)
+	if err != nil {
+		return err
+	}
+
+	if ret0 != nil {
+		return ret0
+	}
+	return nil
+}
+
+// RefundEscrowNoWait is proxy generated method
+func (r *Wallet) RefundEscrowNoWait(escrowRef *core.RecordRef) error {
+	var args [1]interface{}
+	args[0] = escrowRef
+
+	var argsSerialized []byte
+
+	err := proxyctx.Current.Serialize(args, &argsSerialized)
+	if err != nil {
+		return err
+	}
+
+	_, err = proxyctx.Current.RouteCall(r.Reference, false, "RefundEscrow", argsSerialized, *PrototypeReference)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Mint is proxy generated method
+func (r *Wallet) Mint(asset string, amount uint) error {
+	var args [2]interface{}
+	args[0] = asset
+	args[1] = amount
+
+	var argsSerialized []byte
+
+	ret := [1]interface{}{}
+	var ret0 *foundation.Error
+	ret[0] = &ret0
+
+	err := proxyctx.Current.Serialize(args, &argsSerialized)
+	if err != nil {
+		return err
+	}
+
+	res, err := proxyctx.Current.RouteCall(r.Reference, true, "Mint", argsSerialized, *PrototypeReference)
+	if err != nil {
+		return err
+	}
+
+	err = proxyctx.Current.Deserialize(res, &ret)
+	if err != nil {
+		return err
+	}
+
+	if ret0 != nil {
+		return ret0
+	}
+	return nil
+}
+
+// MintNoWait is proxy generated method
+func (r *Wallet) MintNoWait(asset string, amount uint) error {
+	var args [2]interface{}
+	args[0] = asset
+	args[1] = amount
+
+	var argsSerialized []byte
+
+	err := proxyctx.Current.Serialize(args, &argsSerialized)
+	if err != nil {
+		return err
+	}
+
+	_, err = proxyctx.Current.RouteCall(r.Reference, false, "Mint", argsSerialized, *PrototypeReference)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // GetBalance is proxy generated method
 func (r *Wallet) GetBalance() (uint, error) {
 	var args [0]interface{}
@@ -295,3 +579,169 @@ func (r *Wallet) GetBalanceNoWait() error {
 
 	return nil
 }
+
+// GetAssetBalance is proxy generated method
+func (r *Wallet) GetAssetBalance(asset string) (uint, error) {
+	var args [1]interface{}
+	args[0] = asset
+
+	var argsSerialized []byte
+
+	ret := [2]interface{}{}
+	var ret0 uint
+	ret[0] = &ret0
+	var ret1 *foundation.Error
+	ret[1] = &ret1
+
+	err := proxyctx.Current.Serialize(args, &argsSerialized)
+	if err != nil {
+		return ret0, err
+	}
+
+	res, err := proxyctx.Current.RouteCall(r.Reference, true, "GetAssetBalance", argsSerialized, *PrototypeReference)
+	if err != nil {
+		return ret0, err
+	}
+
+	err = proxyctx.Current.Deserialize(res, &ret)
+	if err != nil {
+		return ret0, err
+	}
+
+	if ret1 != nil {
+		return ret0, ret1
+	}
+	return ret0, nil
+}
+
+// GetAssetBalanceNoWait is proxy generated method
+func (r *Wallet) GetAssetBalanceNoWait(asset string) error {
+	var args [1]interface{}
+	args[0] = asset
+
+	var argsSerialized []byte
+
+	err := proxyctx.Current.Serialize(args, &argsSerialized)
+	if err != nil {
+		return err
+	}
+
+	_, err = proxyctx.Current.RouteCall(r.Reference, false, "GetAssetBalance", argsSerialized, *PrototypeReference)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetAssets is proxy generated method
+func (r *Wallet) GetAssets() (map[string]uint, error) {
+	var args [0]interface{}
+
+	var argsSerialized []byte
+
+	ret := [2]interface{}{}
+	var ret0 map[string]uint
+	ret[0] = &ret0
+	var ret1 *foundation.Error
+	ret[1] = &ret1
+
+	err := proxyctx.Current.Serialize(args, &argsSerialized)
+	if err != nil {
+		return ret0, err
+	}
+
+	res, err := proxyctx.Current.RouteCall(r.Reference, true, "GetAssets", argsSerialized, *PrototypeReference)
+	if err != nil {
+		return ret0, err
+	}
+
+	err = proxyctx.Current.Deserialize(res, &ret)
+	if err != nil {
+		return ret0, err
+	}
+
+	if ret1 != nil {
+		return ret0, ret1
+	}
+	return ret0, nil
+}
+
+// GetAssetsNoWait is proxy generated method
+func (r *Wallet) GetAssetsNoWait() error {
+	var args [0]interface{}
+
+	var argsSerialized []byte
+
+	err := proxyctx.Current.Serialize(args, &argsSerialized)
+	if err != nil {
+		return err
+	}
+
+	_, err = proxyctx.Current.RouteCall(r.Reference, false, "GetAssets", argsSerialized, *PrototypeReference)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetHistory is proxy generated method
+func (r *Wallet) GetHistory(fromPulse core.PulseNumber, toPulse core.PulseNumber, cursor string, limit uint) ([]byte, error) {
+	var args [4]interface{}
+	args[0] = fromPulse
+	args[1] = toPulse
+	args[2] = cursor
+	args[3] = limit
+
+	var argsSerialized []byte
+
+	ret := [2]interface{}{}
+	var ret0 []byte
+	ret[0] = &ret0
+	var ret1 *foundation.Error
+	ret[1] = &ret1
+
+	err := proxyctx.Current.Serialize(args, &argsSerialized)
+	if err != nil {
+		return ret0, err
+	}
+
+	res, err := proxyctx.Current.RouteCall(r.Reference, true, "GetHistory", argsSerialized, *PrototypeReference)
+	if err != nil {
+		return ret0, err
+	}
+
+	err = proxyctx.Current.Deserialize(res, &ret)
+	if err != nil {
+		return ret0, err
+	}
+
+	if ret1 != nil {
+		return ret0, ret1
+	}
+	return ret0, nil
+}
+
+// GetHistoryNoWait is proxy generated method
+func (r *Wallet) GetHistoryNoWait(fromPulse core.PulseNumber, toPulse core.PulseNumber, cursor string, limit uint) error {
+	var args [4]interface{}
+	args[0] = fromPulse
+	args[1] = toPulse
+	args[2] = cursor
+	args[3] = limit
+
+	var argsSerialized []byte
+
+	err := proxyctx.Current.Serialize(args, &argsSerialized)
+	if err != nil {
+		return err
+	}
+
+	_, err = proxyctx.Current.RouteCall(r.Reference, false, "GetHistory", argsSerialized, *PrototypeReference)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
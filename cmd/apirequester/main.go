@@ -70,4 +70,7 @@ func main() {
 
 	// make several (10) requests in parallel to transfer money (every request make call to different members instances)
 	severalParallelRequestToDifferentMembers(insSDK)
+
+	// make requests on behalf of multisig member, every operation is co-signed by 2 of 3 keys
+	multiSigRequests(insSDK)
 }
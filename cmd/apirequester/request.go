@@ -99,3 +99,25 @@ func severalParallelRequestToDifferentMembers(insSDK *sdk.SDK) {
 	wg.Wait()
 	fmt.Print("severalParallelRequestToDifferentMembers done just fine\n\n")
 }
+
+func multiSigRequests(insSDK *sdk.SDK) {
+	fmt.Println("Try to create admin multisig member (2 of 3 keys):")
+	ms, traceID, err := insSDK.CreateMultiSig(3, 2, true)
+	check("Can not create multisig member, error: ", err)
+	fmt.Println("Success! New multisig member ref: ", ms.Reference, ". TraceId: ", traceID)
+
+	fmt.Println("Try to create member with co-signatures:")
+	m, traceID, err := insSDK.MultiSigCreateMember(ms)
+	check("Can not create member by multisig, error: ", err)
+	fmt.Println("Success! New member ref: ", m.Reference, ". TraceId: ", traceID)
+
+	traceID, err = insSDK.Transfer(10, m, sdk.NewMember(ms.Reference, ""))
+	check("Can not transfer money to multisig, error: ", err)
+	fmt.Println("Transfer to multisig success. TraceId: ", traceID)
+
+	fmt.Println("Try to transfer with co-signatures:")
+	traceID, err = insSDK.MultiSigTransfer(1, ms, m)
+	check("Can not transfer money by multisig, error: ", err)
+	fmt.Println("Transfer success. TraceId: ", traceID)
+	fmt.Print("multiSigRequests done just fine\n\n")
+}
@@ -92,12 +92,17 @@ var (
 	verbose            bool
 	sendUrls           string
 	rootAsCaller       bool
+	sincePulse         uint32
+	dataDir            string
+	snapshotPaths      []string
+	pruned             bool
+	adminToken         string
 )
 
 func parseInputParams() {
 	var rootCmd = &cobra.Command{}
 	rootCmd.Flags().StringVarP(&cmd, "cmd", "c", "",
-		"available commands: default_config | random_ref | version | gen_keys | gen_certificate | send_request | gen_send_configs")
+		"available commands: default_config | random_ref | version | gen_keys | gen_certificate | send_request | gen_send_configs | backup_storage | restore_storage | verify_ledger")
 	rootCmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "be verbose (default false)")
 	rootCmd.Flags().StringVarP(&output, "output", "o", defaultStdoutPath, "output file (use - for STDOUT)")
 	rootCmd.Flags().StringVarP(&sendUrls, "url", "u", defaultURL, "api url")
@@ -105,6 +110,11 @@ func parseInputParams() {
 	rootCmd.Flags().StringVarP(&configPath, "config", "g", "config.json", "path to configuration file")
 	rootCmd.Flags().StringVarP(&paramsPath, "params", "p", "", "path to params file (default params.json)")
 	rootCmd.Flags().BoolVarP(&rootAsCaller, "root_as_caller", "r", false, "use root member as caller")
+	rootCmd.Flags().Uint32VarP(&sincePulse, "since", "s", 0, "fetch incremental backup or verify ledger since pulse (default full snapshot or all pulses)")
+	rootCmd.Flags().StringVarP(&dataDir, "data_dir", "d", "./data", "node data directory to restore or verify")
+	rootCmd.Flags().StringSliceVarP(&snapshotPaths, "snapshot", "i", nil, "snapshot and backups to restore in order of taking")
+	rootCmd.Flags().BoolVarP(&pruned, "pruned", "", false, "ledger is pruned by retention policy, skip drop hashes verification")
+	rootCmd.Flags().StringVarP(&adminToken, "admin_token", "", "", "node admin token for backup_storage")
 	err := rootCmd.Execute()
 	check("Wrong input params:", err)
 
@@ -238,5 +248,11 @@ func main() {
 		sendRequest(out)
 	case "gen_send_configs":
 		genSendConfigs(out)
+	case "backup_storage":
+		backupStorage(out)
+	case "restore_storage":
+		restoreStorage(out)
+	case "verify_ledger":
+		verifyLedger(out)
 	}
 }
@@ -0,0 +1,237 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+
+	"github.com/insolar/insolar/configuration"
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/instrumentation/inslogger"
+	"github.com/insolar/insolar/ledger/storage"
+	"github.com/insolar/insolar/platformpolicy"
+	pulsarstorage "github.com/insolar/insolar/pulsar/storage"
+	"github.com/insolar/insolar/utils/snapshot"
+	"github.com/pkg/errors"
+)
+
+// backupStorage fetches storage backup from running node.
+func backupStorage(out io.Writer) {
+	url := fmt.Sprintf("%s/backup?since=%d", sendUrls, sincePulse)
+	verboseInfo("Fetching backup from " + url)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	check("[ backupStorage ] Can't create request:", err)
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	resp, err := http.DefaultClient.Do(req)
+	check("[ backupStorage ] Can't fetch backup:", err)
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := ioutil.ReadAll(resp.Body)
+		check("[ backupStorage ] Can't fetch backup:", errors.New(string(msg)))
+	}
+
+	_, err = io.Copy(out, resp.Body)
+	check("[ backupStorage ] Can't write backup:", err)
+}
+
+func readSnapshotHeader(path string) (snapshot.Header, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return snapshot.Header{}, err
+	}
+	defer f.Close()
+	r, err := snapshot.NewReader(f)
+	if err != nil {
+		return snapshot.Header{}, errors.Wrap(err, path)
+	}
+	return r.Header(), nil
+}
+
+// checkSnapshotChain checks that snapshots start from full one and every backup continues previous one.
+func checkSnapshotChain(paths []string) (string, error) {
+	if len(paths) == 0 {
+		return "", errors.New("no snapshots provided")
+	}
+	var prev snapshot.Header
+	for i, path := range paths {
+		h, err := readSnapshotHeader(path)
+		if err != nil {
+			return "", err
+		}
+		switch {
+		case i == 0 && !h.IsFull():
+			return "", errors.Errorf("%s: first snapshot should be full, got backup since pulse %v", path, h.Since)
+		case i > 0 && h.Source != prev.Source:
+			return "", errors.Errorf("%s: snapshot of %s can't be applied to %s", path, h.Source, prev.Source)
+		case i > 0 && (h.IsFull() || h.Since > prev.Pulse):
+			return "", errors.Errorf("%s: backup since pulse %v doesn't continue snapshot of pulse %v", path, h.Since, prev.Pulse)
+		}
+		prev = h
+	}
+	return prev.Source, nil
+}
+
+func checkEmptyDir(dir string) error {
+	files, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if len(files) > 0 {
+		return errors.Errorf("data directory %s is not empty", dir)
+	}
+	return nil
+}
+
+func applySnapshots(paths []string, restore func(r io.Reader) (*snapshot.Header, error)) {
+	for _, path := range paths {
+		f, err := os.Open(path)
+		check("[ restoreStorage ] Can't open snapshot:", err)
+		h, err := restore(f)
+		f.Close()
+		check("[ restoreStorage ] Can't restore snapshot "+path+":", err)
+		verboseInfo(fmt.Sprintf("Restored %s: pulse %v, since %v", path, h.Pulse, h.Since))
+	}
+}
+
+// restoreStorage restores node data directory from full snapshot and following backups
+// and verifies drop hashes of restored ledger storage.
+func restoreStorage(out io.Writer) {
+	source, err := checkSnapshotChain(snapshotPaths)
+	check("[ restoreStorage ] Bad snapshots:", err)
+	err = checkEmptyDir(dataDir)
+	check("[ restoreStorage ]", err)
+
+	switch source {
+	case storage.SnapshotSource:
+		restoreLedger(out)
+	case pulsarstorage.SnapshotSource:
+		restorePulsar(out)
+	default:
+		check("[ restoreStorage ]", errors.Errorf("unknown snapshot source %q", source))
+	}
+}
+
+func restoreLedger(out io.Writer) {
+	ctx := inslogger.ContextWithTrace(context.Background(), "insolarUtility")
+
+	conf := configuration.NewLedger()
+	conf.Storage.DataDirectory = dataDir
+	db, err := storage.NewDB(conf, nil)
+	check("[ restoreLedger ] Can't open storage:", err)
+	db.PlatformCryptographyScheme = platformpolicy.NewPlatformCryptographyScheme()
+
+	applySnapshots(snapshotPaths, func(r io.Reader) (*snapshot.Header, error) {
+		return db.Restore(ctx, r)
+	})
+
+	latest, err := db.GetLatestPulse(ctx)
+	check("[ restoreLedger ] Can't get latest pulse:", err)
+	verification, err := db.VerifyDrops(ctx)
+	check("[ restoreLedger ] Can't verify drops:", err)
+	err = db.Close()
+	check("[ restoreLedger ] Can't close storage:", err)
+
+	writeRestoreReport(out, latest.Pulse.PulseNumber, verification)
+	if len(verification.Mismatched) > 0 {
+		os.Exit(1)
+	}
+}
+
+func restorePulsar(out io.Writer) {
+	conf := configuration.NewPulsar()
+	conf.Storage.DataDirectory = dataDir
+	ps, err := pulsarstorage.NewStorageBadger(conf, nil)
+	check("[ restorePulsar ] Can't open storage:", err)
+	defer ps.Close()
+
+	snapshotter, ok := ps.(pulsarstorage.Snapshotter)
+	if !ok {
+		check("[ restorePulsar ]", errors.New("pulsar storage doesn't support snapshots"))
+	}
+	applySnapshots(snapshotPaths, snapshotter.Restore)
+
+	last, err := ps.GetLastPulse()
+	check("[ restorePulsar ] Can't get last pulse:", err)
+	writeRestoreReport(out, last.PulseNumber, nil)
+}
+
+func writeRestoreReport(out io.Writer, pulse core.PulseNumber, verification *storage.DropsVerification) {
+	report := map[string]interface{}{
+		"data_directory": dataDir,
+		"latest_pulse":   pulse,
+	}
+	if verification != nil {
+		report["drops_checked"] = verification.Checked
+		report["drops_skipped"] = verification.Skipped
+		report["drops_mismatched"] = verification.Mismatched
+	}
+	result, err := json.MarshalIndent(report, "", "    ")
+	check("[ writeRestoreReport ] Problems with marshaling report:", err)
+	writeToOutput(out, string(result)+"\n")
+}
+
+// verifyLedger verifies jet drops chains of node data directory and reports the first mismatching pulse.
+func verifyLedger(out io.Writer) {
+	ctx := inslogger.ContextWithTrace(context.Background(), "insolarUtility")
+
+	conf := configuration.NewLedger()
+	conf.Storage.DataDirectory = dataDir
+	db, err := storage.NewDB(conf, nil)
+	check("[ verifyLedger ] Can't open storage:", err)
+	db.PlatformCryptographyScheme = platformpolicy.NewPlatformCryptographyScheme()
+
+	verification, err := db.VerifyChains(ctx, storage.ChainVerifyOptions{
+		From:           core.PulseNumber(sincePulse),
+		SkipDropHashes: pruned,
+	})
+	check("[ verifyLedger ] Can't verify storage:", err)
+	err = db.Close()
+	check("[ verifyLedger ] Can't close storage:", err)
+
+	report := map[string]interface{}{
+		"data_directory":  dataDir,
+		"drops_checked":   verification.Drops,
+		"records_checked": verification.Records,
+		"blobs_checked":   verification.Blobs,
+		"last_pulse":      verification.LastPulse,
+	}
+	if first := verification.FirstMismatch(); first != nil {
+		mismatches := make([]string, 0, len(verification.Mismatches))
+		for _, m := range verification.Mismatches {
+			mismatches = append(mismatches, m.String())
+		}
+		report["first_mismatch_pulse"] = first.Pulse
+		report["mismatches"] = mismatches
+	}
+	result, err := json.MarshalIndent(report, "", "    ")
+	check("[ verifyLedger ] Problems with marshaling report:", err)
+	writeToOutput(out, string(result)+"\n")
+
+	if len(verification.Mismatches) > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,315 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/insolar/insolar/core/reply"
+	"github.com/insolar/insolar/messagebus"
+)
+
+func main() {
+	var cmdList = &cobra.Command{
+		Use:   "list <tape>",
+		Short: "Print tape header and records",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tape, err := readTape(args[0])
+			if err != nil {
+				return err
+			}
+			return listTape(os.Stdout, tape)
+		},
+	}
+
+	var cmdDiff = &cobra.Command{
+		Use:   "diff <tape> <tape>",
+		Short: "Compare replies of two tapes by message hash",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			left, err := readTape(args[0])
+			if err != nil {
+				return err
+			}
+			right, err := readTape(args[1])
+			if err != nil {
+				return err
+			}
+			if diffTapes(os.Stdout, left, right) {
+				os.Exit(1)
+			}
+			return nil
+		},
+	}
+
+	var filter recordFilter
+	var cmdFilter = &cobra.Command{
+		Use:   "filter [flags] <tape> <output tape>",
+		Short: "Copy records matching filter to a new tape",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tape, err := readTape(args[0])
+			if err != nil {
+				return err
+			}
+			return filterTape(tape, args[1], filter)
+		},
+	}
+	cmdFilter.Flags().StringVar(&filter.hashPrefix, "hash", "", "hex prefix of message hash")
+	cmdFilter.Flags().IntVar(&filter.replyType, "reply-type", -1, "reply type number")
+	// default value for bool flags is not displayed automatically, thus it's done manually here
+	cmdFilter.Flags().BoolVar(&filter.errorsOnly, "errors", false, "keep only failed sends (default \"false\")")
+
+	var replay replayTarget
+	var cmdReplay = &cobra.Command{
+		Use:   "replay [flags] <tape> <output tape>",
+		Short: "Send recorded messages to a node again and compare replies",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tape, err := readTape(args[0])
+			if err != nil {
+				return err
+			}
+			replayed, err := replayTape(tape, replay)
+			if err != nil {
+				return err
+			}
+			err = saveTape(replayed, args[1])
+			if err != nil {
+				return err
+			}
+			if diffTapes(os.Stdout, replayable(tape), replayed) {
+				os.Exit(1)
+			}
+			return nil
+		},
+	}
+	cmdReplay.Flags().StringVar(&replay.url, "node", "http://localhost:19101/api/replay", "url of node replay handler")
+	cmdReplay.Flags().StringVar(&replay.token, "token", "", "node admin token")
+
+	var rootCmd = &cobra.Command{
+		Use:          "tapetool",
+		Short:        "Inspect MessageBus recorder tapes",
+		SilenceUsage: true,
+	}
+	rootCmd.AddCommand(cmdList, cmdDiff, cmdFilter, cmdReplay)
+	err := rootCmd.Execute()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+func readTape(path string) (*messagebus.FileTape, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't open tape")
+	}
+	defer f.Close() // nolint: errcheck
+	return messagebus.ReadFileTape(f)
+}
+
+func listTape(out io.Writer, tape *messagebus.FileTape) error {
+	records := tape.Records()
+	fmt.Fprintf(out, "pulse: %d, version: %d, records: %d\n", tape.Pulse(), tape.Version(), len(records))
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "#\tMESSAGE HASH\tREPLY TYPE\tERROR")
+	for i, record := range records {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", i, hex.EncodeToString(record.MsgHash), replyType(record.Item), errorText(record.Item))
+	}
+	return w.Flush()
+}
+
+// diffTapes prints records that are missing in one of the tapes or have different replies. Records with the same
+// message hash are compared in order they were recorded. Returns true if tapes differ.
+func diffTapes(out io.Writer, left, right *messagebus.FileTape) bool {
+	rightRecords := map[string][]messagebus.TapeRecord{}
+	var order []string
+	for _, record := range right.Records() {
+		key := string(record.MsgHash)
+		if _, ok := rightRecords[key]; !ok {
+			order = append(order, key)
+		}
+		rightRecords[key] = append(rightRecords[key], record)
+	}
+
+	differ := false
+	for _, record := range left.Records() {
+		key := string(record.MsgHash)
+		hash := hex.EncodeToString(record.MsgHash)
+		candidates := rightRecords[key]
+		if len(candidates) == 0 {
+			fmt.Fprintf(out, "- %s %s\n", hash, itemText(record.Item))
+			differ = true
+			continue
+		}
+		rightRecords[key] = candidates[1:]
+		if !sameItems(record.Item, candidates[0].Item) {
+			fmt.Fprintf(out, "~ %s %s -> %s\n", hash, itemText(record.Item), itemText(candidates[0].Item))
+			differ = true
+		}
+	}
+	for _, key := range order {
+		for _, record := range rightRecords[key] {
+			fmt.Fprintf(out, "+ %s %s\n", hex.EncodeToString(record.MsgHash), itemText(record.Item))
+			differ = true
+		}
+	}
+	return differ
+}
+
+type recordFilter struct {
+	hashPrefix string
+	replyType  int
+	errorsOnly bool
+}
+
+func (f recordFilter) match(record messagebus.TapeRecord) bool {
+	if !strings.HasPrefix(hex.EncodeToString(record.MsgHash), strings.ToLower(f.hashPrefix)) {
+		return false
+	}
+	if f.replyType >= 0 && (record.Item.Reply == nil || int(record.Item.Reply.Type()) != f.replyType) {
+		return false
+	}
+	if f.errorsOnly && record.Item.Error == nil {
+		return false
+	}
+	return true
+}
+
+func filterTape(tape *messagebus.FileTape, path string, filter recordFilter) error {
+	ctx := context.Background()
+	filtered, err := messagebus.CreateFileTape(path, tape.Pulse())
+	if err != nil {
+		return err
+	}
+	for _, record := range tape.Records() {
+		if !filter.match(record) {
+			continue
+		}
+		err = filtered.SetParcel(ctx, record.MsgHash, record.Parcel, record.Item.Reply, record.Item.Error)
+		if err != nil {
+			filtered.Close() // nolint: errcheck
+			return err
+		}
+	}
+	return filtered.Close()
+}
+
+type replayTarget struct {
+	url   string
+	token string
+}
+
+// replayTape posts tape to node replay handler and returns tape with replies the node got.
+func replayTape(tape *messagebus.FileTape, target replayTarget) (*messagebus.FileTape, error) {
+	var buf bytes.Buffer
+	err := tape.Write(context.Background(), &buf)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, target.url, &buf)
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't create replay request")
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Authorization", "Bearer "+target.token)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't send tape to node")
+	}
+	defer resp.Body.Close() // nolint: errcheck
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("node replied with %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return messagebus.ReadFileTape(resp.Body)
+}
+
+// replayable returns tape that has only records with parcels, the only ones that are sent on replay.
+func replayable(tape *messagebus.FileTape) *messagebus.FileTape {
+	ctx := context.Background()
+	result := messagebus.NewFileTape(tape.Pulse())
+	for _, record := range tape.Records() {
+		if record.Parcel != nil {
+			result.SetParcel(ctx, record.MsgHash, record.Parcel, record.Item.Reply, record.Item.Error) // nolint: errcheck
+		}
+	}
+	return result
+}
+
+func saveTape(tape *messagebus.FileTape, path string) error {
+	ctx := context.Background()
+	saved, err := messagebus.CreateFileTape(path, tape.Pulse())
+	if err != nil {
+		return err
+	}
+	for _, record := range tape.Records() {
+		err = saved.SetParcel(ctx, record.MsgHash, record.Parcel, record.Item.Reply, record.Item.Error)
+		if err != nil {
+			saved.Close() // nolint: errcheck
+			return err
+		}
+	}
+	return saved.Close()
+}
+
+func sameItems(a, b messagebus.TapeItem) bool {
+	if errorText(a) != errorText(b) {
+		return false
+	}
+	if a.Reply == nil || b.Reply == nil {
+		return a.Reply == b.Reply
+	}
+	return bytes.Equal(reply.ToBytes(a.Reply), reply.ToBytes(b.Reply))
+}
+
+func itemText(item messagebus.TapeItem) string {
+	if item.Error != nil {
+		return "error: " + item.Error.Error()
+	}
+	return "reply: " + replyType(item)
+}
+
+func replyType(item messagebus.TapeItem) string {
+	if item.Reply == nil {
+		return "-"
+	}
+	return fmt.Sprint(item.Reply.Type())
+}
+
+func errorText(item messagebus.TapeItem) string {
+	if item.Error == nil {
+		return ""
+	}
+	return item.Error.Error()
+}
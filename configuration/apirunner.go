@@ -18,6 +18,7 @@ package configuration
 
 import (
 	"fmt"
+	"time"
 )
 
 // APIRunner holds configuration for api
@@ -26,6 +27,28 @@ type APIRunner struct {
 	Call    string
 	RPC     string
 	Timeout uint32
+	// BatchCall is a path of batch call handler. Handler is disabled if empty.
+	BatchCall string
+	// BatchLimit is a maximum number of requests in a batch. Zero means no limit.
+	BatchLimit int
+	// BatchParallelism is a number of requests from a batch executed concurrently.
+	BatchParallelism int
+	// Stream is a path of event stream handler. Handler is disabled if empty.
+	Stream string
+	// StreamPollInterval is an interval of checking pulse, active nodes and network state for stream subscribers.
+	// Default interval is used if it is not positive.
+	StreamPollInterval time.Duration
+	// Backup is a path of storage backup handler. Handler is disabled if empty, it requires AdminToken.
+	Backup string
+	// ExportStream is a path of streaming records export handler. Handler is disabled if empty.
+	ExportStream string
+	// ExportPollInterval is an interval of checking new exportable pulses when export is followed.
+	ExportPollInterval time.Duration
+	// Replay is a path of handler that sends messages from recorded tape again. Handler is disabled if empty,
+	// it requires AdminToken.
+	Replay string
+	// AdminToken is a bearer token required by administrative handlers. It must be set if any of them is enabled.
+	AdminToken string
 }
 
 // NewAPIRunner creates new api config
@@ -35,10 +58,20 @@ func NewAPIRunner() APIRunner {
 		Call:    "/api/call",
 		RPC:     "/api/rpc",
 		Timeout: 15,
+
+		BatchCall:        "/api/batch",
+		BatchLimit:       1000,
+		BatchParallelism: 16,
+
+		Stream:             "/api/stream",
+		StreamPollInterval: 500 * time.Millisecond,
+
+		ExportStream:       "/api/export",
+		ExportPollInterval: time.Second,
 	}
 }
 
 func (ar *APIRunner) String() string {
-	res := fmt.Sprintln("Addr ->", ar.Address, ", Call ->", ar.Call, ", RPC ->", ar.RPC)
+	res := fmt.Sprintln("Addr ->", ar.Address, ", Call ->", ar.Call, ", RPC ->", ar.RPC, ", BatchCall ->", ar.BatchCall, ", Stream ->", ar.Stream, ", Backup ->", ar.Backup, ", ExportStream ->", ar.ExportStream, ", Replay ->", ar.Replay)
 	return res
 }
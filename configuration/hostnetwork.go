@@ -18,12 +18,51 @@ package configuration
 
 // Transport holds transport protocol configuration for HostNetwork
 type Transport struct {
-	// protocol type
+	// protocol type: TCP, TLS, PURE_UDP, QUIC or SIMULATED. TLS is TCP encrypted and authenticated with node keys,
+	// SIMULATED is in-process network with programmable faults for integration tests
 	Protocol string
 	// Address to listen
 	Address string
 	// if true transport will use network traversal technique(like STUN) to get PublicAddress
 	BehindNAT bool
+	// FlowControl limits outgoing traffic to each peer
+	FlowControl FlowControl
+}
+
+// FlowClass holds flow control settings of one priority class of outgoing packets
+type FlowClass struct {
+	QueueSize int  // packets waiting to be sent to a peer, extra packets are dropped or blocked
+	Block     bool // block sender until queue has room (at most FlowControl.BlockTimeout) instead of dropping
+}
+
+// FlowControl holds per-peer send queues settings. Classes are sent in order of priority:
+// consensus, pulse and network control, messagebus parcels, heavy sync. Consensus class is used
+// by transport of consensus network, it is started with the same settings
+type FlowControl struct {
+	Enabled      bool
+	Consensus    FlowClass
+	Pulse        FlowClass
+	Parcel       FlowClass
+	Heavy        FlowClass
+	BlockTimeout int // ms
+	RateLimit    int // packets per second sent to a peer, 0 is unlimited
+	Bandwidth    int // bytes per second sent to a peer, 0 is unlimited
+	Burst        int // bytes that may be sent to a peer at once above Bandwidth
+}
+
+// NewFlowControl creates new default FlowControl configuration
+func NewFlowControl() FlowControl {
+	return FlowControl{
+		Enabled:      true,
+		Consensus:    FlowClass{QueueSize: 128, Block: false},
+		Pulse:        FlowClass{QueueSize: 128, Block: false},
+		Parcel:       FlowClass{QueueSize: 1024, Block: true},
+		Heavy:        FlowClass{QueueSize: 64, Block: true},
+		BlockTimeout: 10000,
+		RateLimit:    0,
+		Bandwidth:    0,
+		Burst:        4 * 1024 * 1024,
+	}
 }
 
 // HostNetwork holds configuration for HostNetwork
@@ -35,13 +74,17 @@ type HostNetwork struct {
 	MaxTimeout          int   // bootstrap timeout max
 	TimeoutMult         int   // bootstrap timout multiplier
 	SignMessages        bool  // signing a messages if true
-	HandshakeSessionTTL int32 // ms
+	HandshakeSessionTTL int32 // ms, also lifetime of resumable TLS transport sessions
+	// references of joining nodes allowed to connect over TLS before they become active,
+	// other nodes must be active or discovery
+	BootstrapAllowList []string
+	MaxParcelSize      int // bytes, max size of parcel or its reply received over stream
 }
 
 // NewHostNetwork creates new default HostNetwork configuration
 func NewHostNetwork() HostNetwork {
 	// IP address should not be 0.0.0.0!!!
-	transport := Transport{Protocol: "TCP", Address: "127.0.0.1:0", BehindNAT: false}
+	transport := Transport{Protocol: "TCP", Address: "127.0.0.1:0", BehindNAT: false, FlowControl: NewFlowControl()}
 
 	return HostNetwork{
 		Transport:           transport,
@@ -52,5 +95,7 @@ func NewHostNetwork() HostNetwork {
 		InfinityBootstrap:   false,
 		SignMessages:        false,
 		HandshakeSessionTTL: 5000,
+		BootstrapAllowList:  []string{},
+		MaxParcelSize:       128 * 1024 * 1024,
 	}
 }
@@ -24,6 +24,8 @@ import (
 
 // Storage configures Ledger's storage.
 type Storage struct {
+	// Backend is a name of key-value engine storage works on ("badger", "bolt" or "memory"), badger is used if empty.
+	Backend string
 	// DataDirectory is a directory where database's files live.
 	DataDirectory string
 	// TxRetriesOnConflict defines how many retries on transaction conflicts
@@ -42,10 +44,40 @@ type PulseManager struct {
 	HeavySyncEnabled bool
 	// HeavySyncMessageLimit soft limit of single message for replication to heavy.
 	HeavySyncMessageLimit int
+	// HeavySyncParallelism is a number of pulses of one jet replicated to heavy concurrently.
+	//
+	// IMPORTANT: Heavy accepts the same number of pulses in sync for one jet, so it should be the same on ALL nodes.
+	HeavySyncParallelism int
+	// HeavySyncCompression enables compression of replicated records.
+	HeavySyncCompression bool
 	// Backoff configures retry backoff algorithm for Heavy Sync
 	HeavyBackoff Backoff
 	// SplitThreshold is a drop size threshold in bytes to perform split.
 	SplitThreshold uint64
+	// JetBalancer configures adaptive jet split and merge.
+	JetBalancer JetBalancer
+}
+
+// JetBalancer holds configuration of adaptive jet split and merge. Jet load is measured over the latest drops,
+// split and merge thresholds should leave a gap between them to avoid flapping.
+type JetBalancer struct {
+	// Enabled enables jet splits and merges.
+	Enabled bool
+	// Window is a number of the latest drops used to calculate average jet load.
+	// It must be positive, it's limited by JetSizesHistoryDepth.
+	Window int
+	// Hysteresis is a number of consecutive drops which must cross a threshold to split or merge jet.
+	Hysteresis int
+	// MaxDepth is a max depth of jet tree.
+	MaxDepth int
+	// SplitRequests is a number of requests per drop to perform split. Zero disables splits by requests.
+	SplitRequests uint64
+	// MergeThreshold is a drop size in bytes of both sibling jets together to perform merge.
+	// It must be less than SplitThreshold.
+	MergeThreshold uint64
+	// MergeRequests is a number of requests per drop of both sibling jets together to perform merge. Zero disables
+	// requests check for merge. It must be less than SplitRequests if splits by requests are enabled.
+	MergeRequests uint64
 }
 
 // Backoff configures retry backoff algorithm
@@ -69,6 +101,33 @@ type Exporter struct {
 	ExportLag uint32
 }
 
+// Retention holds configuration of heavy node material storage pruning.
+//
+// Zero values keep everything (archive node).
+type Retention struct {
+	// KeepStates is a number of latest states kept for every object, zero keeps all states.
+	KeepStates int
+	// RequestsPulseAge is a number of pulses request and result records are kept for, zero keeps them forever.
+	RequestsPulseAge int
+	// MessagesPulseAge is a number of pulses message payloads are kept for, zero keeps them forever.
+	MessagesPulseAge int
+	// CompactDeactivated enables removal of memory of deactivated objects.
+	CompactDeactivated bool
+	// DryRun only reports data which could be removed, storage is left untouched.
+	// Dry run scans the whole jet every time.
+	DryRun bool
+	// Interval between pruning of synced jets on heavy node.
+	Interval time.Duration
+}
+
+// Verifier holds configuration of jet drops chains verification job.
+type Verifier struct {
+	// Interval between verifications on heavy node, zero disables the job.
+	Interval time.Duration
+	// BatchSize is a max number of drops verified in one storage transaction, zero means all drops.
+	BatchSize int
+}
+
 // Ledger holds configuration for ledger.
 type Ledger struct {
 	// Storage defines storage configuration.
@@ -93,12 +152,19 @@ type Ledger struct {
 
 	// Exporter holds configuration of Exporter
 	Exporter Exporter
+
+	// Retention holds heavy node storage retention policy.
+	Retention Retention
+
+	// Verifier holds configuration of heavy node storage verification.
+	Verifier Verifier
 }
 
 // NewLedger creates new default Ledger configuration.
 func NewLedger() Ledger {
 	return Ledger{
 		Storage: Storage{
+			Backend:             "badger",
 			DataDirectory:       "./data",
 			TxRetriesOnConflict: 3,
 		},
@@ -116,6 +182,8 @@ func NewLedger() Ledger {
 		PulseManager: PulseManager{
 			HeavySyncEnabled:      true,
 			HeavySyncMessageLimit: 1 << 20, // 1Mb
+			HeavySyncParallelism:  4,
+			HeavySyncCompression:  true,
 			HeavyBackoff: Backoff{
 				Jitter: true,
 				Min:    200 * time.Millisecond,
@@ -123,6 +191,15 @@ func NewLedger() Ledger {
 				Factor: 2,
 			},
 			SplitThreshold: 10 * 100, // 10 megabytes.
+			JetBalancer: JetBalancer{
+				Enabled:        true,
+				Window:         5,
+				Hysteresis:     3,
+				MaxDepth:       5,
+				SplitRequests:  100,
+				MergeThreshold: 10 * 10,
+				MergeRequests:  10,
+			},
 		},
 
 		RecentStorage: RecentStorage{
@@ -136,5 +213,14 @@ func NewLedger() Ledger {
 		Exporter: Exporter{
 			ExportLag: 40, // 40 seconds
 		},
+
+		Retention: Retention{
+			Interval: time.Minute,
+		},
+
+		Verifier: Verifier{
+			Interval:  time.Hour,
+			BatchSize: 1000,
+		},
 	}
 }
@@ -130,6 +130,8 @@ func (cr *ContractRequester) CallMethod(ctx context.Context, base core.Message,
 		cr.ResultMutex.Unlock()
 	}
 
+	// Message is sent without core.SendPolicy: every delivered call registers a new request, so call could be
+	// executed twice if it is resent after reply is lost.
 	res, err := mb.Send(ctx, msg, nil)
 
 	if err != nil {
@@ -22,6 +22,7 @@ type DelegationTokenFactory interface {
 	IssueGetObjectRedirect(sender *RecordRef, redirectedMessage Message) (DelegationToken, error)
 	IssueGetChildrenRedirect(sender *RecordRef, redirectedMessage Message) (DelegationToken, error)
 	IssueGetCodeRedirect(sender *RecordRef, redirectedMessage Message) (DelegationToken, error)
+	IssueGetObjectHistoryRedirect(sender *RecordRef, redirectedMessage Message) (DelegationToken, error)
 	Verify(parcel Parcel) (bool, error)
 }
 
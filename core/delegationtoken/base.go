@@ -89,9 +89,25 @@ func (t *GetCodeRedirectToken) Verify(parcel core.Parcel) (bool, error) {
 	panic("implement me")
 }
 
+// GetObjectHistoryRedirectToken is a redirect token for the GetObjectHistory method
+type GetObjectHistoryRedirectToken struct {
+	Signature []byte
+}
+
+// Type implementation of Token interface.
+func (t *GetObjectHistoryRedirectToken) Type() core.DelegationTokenType {
+	return core.DTTypeGetObjectHistoryRedirect
+}
+
+// Verify implementation of Token interface.
+func (t *GetObjectHistoryRedirectToken) Verify(parcel core.Parcel) (bool, error) {
+	panic("implement me")
+}
+
 func init() {
 	gob.Register(&PendingExecutionToken{})
 	gob.Register(&GetObjectRedirectToken{})
 	gob.Register(&GetChildrenRedirectToken{})
 	gob.Register(&GetCodeRedirectToken{})
+	gob.Register(&GetObjectHistoryRedirectToken{})
 }
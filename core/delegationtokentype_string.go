@@ -4,9 +4,9 @@ package core
 
 import "strconv"
 
-const _DelegationTokenType_name = "DTTypePendingExecutionDTTypeGetObjectRedirectDTTypeGetChildrenRedirectDTTypeGetCodeRedirect"
+const _DelegationTokenType_name = "DTTypePendingExecutionDTTypeGetObjectRedirectDTTypeGetChildrenRedirectDTTypeGetCodeRedirectDTTypeGetObjectHistoryRedirect"
 
-var _DelegationTokenType_index = [...]uint8{0, 22, 45, 70, 91}
+var _DelegationTokenType_index = [...]uint8{0, 22, 45, 70, 91, 121}
 
 func (i DelegationTokenType) String() string {
 	i -= 1
@@ -0,0 +1,121 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package core
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// StorageExportSchemaVersion is a version of StorageExportRecord schema. It is increased on any
+// incompatible change of the schema, new optional fields may be added without increasing it.
+const StorageExportSchemaVersion = 1
+
+// Exported record types.
+const (
+	StorageExportTypeGenesis    = "genesis"
+	StorageExportTypeChild      = "child"
+	StorageExportTypeJet        = "jet"
+	StorageExportTypeRequest    = "request"
+	StorageExportTypeResult     = "result"
+	StorageExportTypeType       = "type"
+	StorageExportTypeCode       = "code"
+	StorageExportTypeActivate   = "activate"
+	StorageExportTypeAmend      = "amend"
+	StorageExportTypeDeactivate = "deactivate"
+	// StorageExportTypeCheckpoint is not a record, it only carries cursor of export progress.
+	StorageExportTypeCheckpoint = "checkpoint"
+)
+
+// StorageExportRecord is a ledger record in stable export schema.
+//
+// Records are exported in order of pulse, jet and record ID. References are base58 encoded
+// RecordRef, identifiers are base58 encoded RecordID.
+type StorageExportRecord struct {
+	// SchemaVersion is StorageExportSchemaVersion of exporting node.
+	SchemaVersion int `json:"schema_version"`
+	// Cursor is an opaque position right after this record, export can be resumed from it.
+	Cursor string `json:"cursor"`
+	// Type is one of StorageExportType* values.
+	Type string `json:"type"`
+
+	Pulse          PulseNumber `json:"pulse,omitempty"`
+	PulseTimestamp int64       `json:"pulse_timestamp,omitempty"`
+	JetID          string      `json:"jet_id,omitempty"`
+	ID             string      `json:"id,omitempty"`
+
+	// Object is an identifier of the object record belongs to (record part of object reference).
+	Object string `json:"object,omitempty"`
+	// Prototype is a prototype reference of object instance states.
+	Prototype string `json:"prototype,omitempty"`
+	// Code is a code reference of prototype states.
+	Code        string `json:"code,omitempty"`
+	IsPrototype bool   `json:"is_prototype,omitempty"`
+	Parent      string `json:"parent,omitempty"`
+	// Child is a reference of child object of child records.
+	Child      string `json:"child,omitempty"`
+	IsDelegate bool   `json:"is_delegate,omitempty"`
+	Domain     string `json:"domain,omitempty"`
+	// Request is a reference of request which caused the record.
+	Request   string `json:"request,omitempty"`
+	PrevState string `json:"prev_state,omitempty"`
+
+	// Memory is object memory converted to JSON, MemoryBinary is set instead if memory can't be converted.
+	Memory       json.RawMessage `json:"memory,omitempty"`
+	MemoryBinary []byte          `json:"memory_binary,omitempty"`
+
+	// Call describes message of request record.
+	Call *StorageExportCall `json:"call,omitempty"`
+	// Payload is a raw payload of result record or request which message can't be decoded.
+	Payload []byte `json:"payload,omitempty"`
+}
+
+// StorageExportCall is a contract call message of request record.
+type StorageExportCall struct {
+	MessageType string `json:"message_type"`
+	Caller      string `json:"caller,omitempty"`
+	Object      string `json:"object,omitempty"`
+	Prototype   string `json:"prototype,omitempty"`
+	Method      string `json:"method,omitempty"`
+	// Arguments are serialized call arguments.
+	Arguments []byte `json:"arguments,omitempty"`
+}
+
+// StorageExportFilter selects records for export. Empty fields match any record.
+type StorageExportFilter struct {
+	// Types are StorageExportType* values.
+	Types []string
+	// Objects are compared with record part of object reference.
+	Objects    []RecordID
+	Prototypes []RecordRef
+	Jets       []RecordID
+}
+
+// StorageStreamExporter provides streaming export of ledger records.
+type StorageStreamExporter interface {
+	// ExportStream calls handler for records after cursor (from the first pulse if cursor is empty) which
+	// match filter, until limit records are exported (no limit if zero) or all exportable pulses are passed.
+	// Returns cursor export should be resumed from. It is empty if export starts from the first pulse and no pulse
+	// is exportable yet.
+	ExportStream(
+		ctx context.Context,
+		cursor string,
+		filter StorageExportFilter,
+		limit int,
+		handler func(*StorageExportRecord) error,
+	) (string, error)
+}
@@ -24,7 +24,11 @@ import (
 //go:generate minimock -i github.com/insolar/insolar/core.HeavySync -o ../testutils -s _mock.go
 type HeavySync interface {
 	Start(ctx context.Context, jet RecordID, pn PulseNumber) error
-	Store(ctx context.Context, jet RecordID, pn PulseNumber, kvs []KV) error
+	// Resume continues interrupted sync, returns cursor of the last payload chunk stored for the pulse.
+	Resume(ctx context.Context, jet RecordID, pn PulseNumber) ([]byte, error)
+	// Store stores payload chunk which follows cursor from and ends with cursor to, returns cursor of the last
+	// payload chunk stored for the pulse.
+	Store(ctx context.Context, jet RecordID, pn PulseNumber, from, to []byte, kvs []KV) ([]byte, error)
 	Stop(ctx context.Context, jet RecordID, pn PulseNumber) error
 	Reset(ctx context.Context, jet RecordID, pn PulseNumber) error
 }
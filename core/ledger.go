@@ -18,6 +18,7 @@ package core
 
 import (
 	"context"
+	"io"
 )
 
 // DynamicRole is number representing a node role.
@@ -141,6 +142,24 @@ type ArtifactManager interface {
 	// During iteration children refs will be fetched from remote source (parent object).
 	GetChildren(ctx context.Context, parent RecordRef, pulse *PulseNumber) (RefIterator, error)
 
+	// GetObjectHistory returns object states walking the amend chain from the latest state to the activation.
+	//
+	// If pulse is provided, states created after this pulse are skipped, so the first returned state is the object
+	// state as of provided pulse. At most limit states are returned (all states if limit is not positive).
+	GetObjectHistory(ctx context.Context, head RecordRef, pulse *PulseNumber, limit int) ([]ObjectHistoryState, error)
+
+	// GetPrototypeObjects returns heads of active objects of provided prototype.
+	//
+	// Objects are ordered by id. Listing starts from provided object (from the first one if from is nil). Returned id
+	// should be passed as from to fetch the next chunk, it is nil if there are no more objects.
+	GetPrototypeObjects(ctx context.Context, prototype RecordRef, from *RecordID, limit int) ([]RecordRef, *RecordID, error)
+
+	// CountPrototypeObjects returns number of active objects of provided prototype.
+	CountPrototypeObjects(ctx context.Context, prototype RecordRef) (int, error)
+
+	// GetRecordProof returns proof of record inclusion into jet drop of its pulse.
+	GetRecordProof(ctx context.Context, id RecordID) (*RecordProof, error)
+
 	// DeclareType creates new type record in storage.
 	//
 	// Type is a contract interface. It contains one method signature.
@@ -248,6 +267,70 @@ type ObjectDescriptor interface {
 	Parent() *RecordRef
 }
 
+// ObjectHistoryState represents a single object state from the object's amend chain.
+type ObjectHistoryState struct {
+	// State is the state record id.
+	State RecordID
+	// Pulse is the pulse state was created in.
+	Pulse PulseNumber
+	// Request is the request that produced the state.
+	Request RecordRef
+	// Prototype is prototype (or code for prototypes) reference. Nil for deactivation.
+	Prototype *RecordRef
+	// IsPrototype determines if the object was a prototype.
+	IsPrototype bool
+	// Memory is the object memory blob.
+	Memory []byte
+	// Approved is true if state is validated (e.i. it is the latest approved state or precedes it).
+	Approved bool
+	// Deactivated is true for deactivation state.
+	Deactivated bool
+}
+
+// MerkleProofStep is a step of Merkle inclusion proof path from leaf to root.
+type MerkleProofStep struct {
+	// Hash is a hash of sibling node.
+	Hash []byte
+	// Left is true if sibling is the left node.
+	Left bool
+}
+
+// DropSignature is a signature of jet drop made by node which built or stored the drop.
+//
+// Signed data is a hash of jet prefix, drop pulse number and drop hash (see jet.DropSignData).
+type DropSignature struct {
+	// Node is a reference of signer.
+	Node RecordRef
+	// Signature is a signature made by node key.
+	Signature []byte
+}
+
+// RecordProof proves that record is included into jet drop.
+//
+// Record hashes to a leaf of Merkle tree, the leaf hashes up to RecordsRoot by Path,
+// drop hash is a hash of PrevHash and RecordsRoot. Pulse contains pulsar signatures of drop pulse,
+// they don't cover drop content, so the drop hash is confirmed by Signatures of jet executors.
+type RecordProof struct {
+	// Record is a serialized record.
+	Record []byte
+	// ID is the record id.
+	ID RecordID
+	// Path is a path from record leaf to the root.
+	Path []MerkleProofStep
+	// RecordsRoot is a Merkle root over drop records.
+	RecordsRoot []byte
+	// PrevHash is a hash of the previous jet drop. Drop of merged jet is chained to both previous drops.
+	PrevHash []byte
+	// DropHash is a hash of jet drop containing the record.
+	DropHash []byte
+	// JetPrefix is a prefix of jet drop jet, it identifies the jet among jets of the pulse.
+	JetPrefix []byte
+	// Signatures are signatures of the drop by light executor that built it and heavy executor that stored it.
+	Signatures []DropSignature
+	// Pulse is the pulse of jet drop.
+	Pulse Pulse
+}
+
 // RefIterator is used for iteration over affined children(parts) of container.
 type RefIterator interface {
 	Next() (*RecordRef, error)
@@ -294,6 +377,42 @@ type StorageExporter interface {
 	Export(ctx context.Context, fromPulse PulseNumber, size int) (*StorageExportResult, error)
 }
 
+// Jet balancing actions.
+const (
+	JetActionSplit = "split"
+	JetActionMerge = "merge"
+)
+
+// JetDecision describes jet tree change made by jet balancer.
+type JetDecision struct {
+	Pulse    PulseNumber // Pulse of the changed tree.
+	Action   string
+	From     []RecordID
+	To       []RecordID
+	DropSize uint64 // Average drop size of changed jets.
+	Requests uint64 // Average number of requests in drop of changed jets.
+}
+
+// JetStatus is a state of jet tree balancing.
+type JetStatus struct {
+	Pulse     PulseNumber
+	Jets      []RecordID
+	Decisions []JetDecision
+}
+
+// JetBalancer provides state of adaptive jet split and merge.
+type JetBalancer interface {
+	// JetStatus returns jets of the latest pulse and the latest split and merge decisions.
+	JetStatus(ctx context.Context) (*JetStatus, error)
+}
+
+// StorageSnapshotter provides online backups of storage.
+type StorageSnapshotter interface {
+	// Backup writes copy of data added since provided pulse (all data for zero pulse) to w.
+	// Returns the latest pulse included into backup.
+	Backup(ctx context.Context, w io.Writer, since PulseNumber) (PulseNumber, error)
+}
+
 var (
 	// TODOJetID temporary stub for passing jet ID in ledger functions
 	// on period Jet ID full implementation
@@ -17,7 +17,12 @@
 package message
 
 import (
+	"bytes"
+	"compress/gzip"
+	"encoding/gob"
+
 	"github.com/insolar/insolar/core"
+	"github.com/pkg/errors"
 )
 
 // HeavyPayload carries Key/Value records and pulse number
@@ -26,6 +31,52 @@ type HeavyPayload struct {
 	JetID    core.RecordID
 	PulseNum core.PulseNumber
 	Records  []core.KV
+	// From is a cursor of the previous payload chunk in pulse (nil for the first chunk).
+	From []byte
+	// To is a cursor of this payload chunk (original key of its last record on light node).
+	// Heavy acknowledges stored chunks by cursor, so sync could be resumed.
+	To []byte
+	// CompressedRecords holds compressed Records, see CompressRecords.
+	CompressedRecords []byte
+}
+
+// CompressRecords moves records into compressed form.
+func (hp *HeavyPayload) CompressRecords() error {
+	var buf bytes.Buffer
+	zw, err := gzip.NewWriterLevel(&buf, gzip.BestSpeed)
+	if err != nil {
+		return errors.Wrap(err, "[ CompressRecords ] failed to create writer")
+	}
+	err = gob.NewEncoder(zw).Encode(hp.Records)
+	if err != nil {
+		return errors.Wrap(err, "[ CompressRecords ] failed to encode records")
+	}
+	err = zw.Close()
+	if err != nil {
+		return errors.Wrap(err, "[ CompressRecords ] failed to compress records")
+	}
+	hp.CompressedRecords = buf.Bytes()
+	hp.Records = nil
+	return nil
+}
+
+// DecompressRecords restores records from compressed form. Does nothing if records are not compressed.
+func (hp *HeavyPayload) DecompressRecords() error {
+	if len(hp.CompressedRecords) == 0 {
+		return nil
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(hp.CompressedRecords))
+	if err != nil {
+		return errors.Wrap(err, "[ DecompressRecords ] failed to create reader")
+	}
+	var records []core.KV
+	err = gob.NewDecoder(zr).Decode(&records)
+	if err != nil {
+		return errors.Wrap(err, "[ DecompressRecords ] failed to decode records")
+	}
+	hp.Records = records
+	hp.CompressedRecords = nil
+	return nil
 }
 
 // AllowedSenderObjectAndRole implements interface method
@@ -58,6 +109,8 @@ type HeavyStartStop struct {
 	JetID    core.RecordID
 	PulseNum core.PulseNumber
 	Finished bool
+	// Resume continues interrupted sync of the pulse instead of starting it over.
+	Resume bool
 }
 
 // AllowedSenderObjectAndRole implements interface method
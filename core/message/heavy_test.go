@@ -0,0 +1,58 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package message
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/insolar/insolar/core"
+)
+
+func TestHeavyPayload_CompressRecords(t *testing.T) {
+	records := []core.KV{
+		{K: []byte("key1"), V: bytes.Repeat([]byte("value"), 100)},
+		{K: []byte("key2"), V: bytes.Repeat([]byte("value"), 100)},
+	}
+	msg := &HeavyPayload{
+		PulseNum: core.FirstPulseNumber,
+		Records:  records,
+		From:     []byte("key0"),
+		To:       []byte("key2"),
+	}
+
+	err := msg.CompressRecords()
+	require.NoError(t, err)
+	require.Nil(t, msg.Records)
+	require.True(t, len(msg.CompressedRecords) < int(core.KVSize(records)))
+
+	decoded, err := Deserialize(bytes.NewBuffer(MustSerializeBytes(msg)))
+	require.NoError(t, err)
+	payload := decoded.(*HeavyPayload)
+	err = payload.DecompressRecords()
+	require.NoError(t, err)
+	require.Equal(t, records, payload.Records)
+	require.Equal(t, []byte("key2"), payload.To)
+	require.Nil(t, payload.CompressedRecords)
+
+	// uncompressed payload is left as is
+	err = payload.DecompressRecords()
+	require.NoError(t, err)
+	require.Equal(t, records, payload.Records)
+}
@@ -226,6 +226,94 @@ func (*GetChildren) Type() core.MessageType {
 	return core.TypeGetChildren
 }
 
+// GetObjectHistory retrieves a chunk of object states walking the amend chain backwards.
+type GetObjectHistory struct {
+	ledgerMessage
+	Head         core.RecordRef
+	FromState    *core.RecordID    // If nil, will start from the latest state.
+	FromApproved bool              // If set, FromState is known to be approved (see reply.ObjectHistory).
+	FromPulse    *core.PulseNumber // If set, states created after this pulse will be skipped.
+	Amount       int               // Max number of returned states, skipped states are not counted.
+}
+
+// AllowedSenderObjectAndRole implements interface method
+func (m *GetObjectHistory) AllowedSenderObjectAndRole() (*core.RecordRef, core.DynamicRole) {
+	// History is read-only and can be requested by any node (e.g. for audit).
+	return nil, core.DynamicRoleUndefined
+}
+
+// DefaultRole returns role for this event
+func (*GetObjectHistory) DefaultRole() core.DynamicRole {
+	return core.DynamicRoleLightExecutor
+}
+
+// DefaultTarget returns of target of this event.
+func (m *GetObjectHistory) DefaultTarget() *core.RecordRef {
+	return &m.Head
+}
+
+// Type implementation of Message interface.
+func (*GetObjectHistory) Type() core.MessageType {
+	return core.TypeGetObjectHistory
+}
+
+// GetPrototypeObjects retrieves a chunk of active objects of the prototype from heavy prototype index.
+type GetPrototypeObjects struct {
+	ledgerMessage
+	Prototype  core.RecordRef
+	FromObject *core.RecordID // If nil, will start from the first object.
+	Amount     int
+	CountOnly  bool // If true, only objects count will be returned.
+}
+
+// AllowedSenderObjectAndRole implements interface method
+func (m *GetPrototypeObjects) AllowedSenderObjectAndRole() (*core.RecordRef, core.DynamicRole) {
+	// Index is read-only and can be requested by any node.
+	return nil, core.DynamicRoleUndefined
+}
+
+// DefaultRole returns role for this event
+func (*GetPrototypeObjects) DefaultRole() core.DynamicRole {
+	return core.DynamicRoleHeavyExecutor
+}
+
+// DefaultTarget returns of target of this event.
+func (m *GetPrototypeObjects) DefaultTarget() *core.RecordRef {
+	return &m.Prototype
+}
+
+// Type implementation of Message interface.
+func (*GetPrototypeObjects) Type() core.MessageType {
+	return core.TypeGetPrototypeObjects
+}
+
+// GetRecordProof retrieves proof of record inclusion into jet drop from heavy.
+type GetRecordProof struct {
+	ledgerMessage
+	Record core.RecordID
+}
+
+// AllowedSenderObjectAndRole implements interface method
+func (m *GetRecordProof) AllowedSenderObjectAndRole() (*core.RecordRef, core.DynamicRole) {
+	// Proofs are public and can be requested by any node.
+	return nil, core.DynamicRoleUndefined
+}
+
+// DefaultRole returns role for this event
+func (*GetRecordProof) DefaultRole() core.DynamicRole {
+	return core.DynamicRoleHeavyExecutor
+}
+
+// DefaultTarget returns of target of this event.
+func (m *GetRecordProof) DefaultTarget() *core.RecordRef {
+	return core.NewRecordRef(core.RecordID{}, m.Record)
+}
+
+// Type implementation of Message interface.
+func (*GetRecordProof) Type() core.MessageType {
+	return core.TypeGetRecordProof
+}
+
 // JetDrop spreads jet drop
 type JetDrop struct {
 	ledgerMessage
@@ -61,6 +61,12 @@ func getEmptyMessage(mt core.MessageType) (core.Message, error) {
 		return &GetDelegate{}, nil
 	case core.TypeGetChildren:
 		return &GetChildren{}, nil
+	case core.TypeGetObjectHistory:
+		return &GetObjectHistory{}, nil
+	case core.TypeGetPrototypeObjects:
+		return &GetPrototypeObjects{}, nil
+	case core.TypeGetRecordProof:
+		return &GetRecordProof{}, nil
 	case core.TypeUpdateObject:
 		return &UpdateObject{}, nil
 	case core.TypeRegisterChild:
@@ -165,6 +171,14 @@ func SerializeParcel(parcel core.Parcel) (io.Reader, error) {
 	return buff, err
 }
 
+// WriteParcel encodes parcel directly to writer.
+//
+// Gob encodes whole parcel into its own buffer before writing it to w, so parcel is not copied once more
+// but it is not split into smaller parts either.
+func WriteParcel(w io.Writer, parcel core.Parcel) error {
+	return gob.NewEncoder(w).Encode(parcel)
+}
+
 // DeserializeParcel returns decoded signed message.
 func DeserializeParcel(buff io.Reader) (core.Parcel, error) {
 	var signed Parcel
@@ -227,6 +241,9 @@ func init() {
 	gob.Register(&Parcel{})
 	gob.Register(core.RecordRef{})
 	gob.Register(&GetChildren{})
+	gob.Register(&GetObjectHistory{})
+	gob.Register(&GetPrototypeObjects{})
+	gob.Register(&GetRecordProof{})
 
 	// NodeCert
 	gob.Register(&NodeSignPayload{})
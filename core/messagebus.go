@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/json"
 	"io"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/ugorji/go/codec"
@@ -138,6 +139,36 @@ type RedirectReply interface {
 type MessageSendOptions struct {
 	Receiver *RecordRef
 	Token    DelegationToken
+	// Policy enables retries, deadlines and redirect following for Send. Message is sent once if nil.
+	// Policy is applied by MessageBus only, its recorder and player wrappers used in validation send message once.
+	Policy *SendPolicy
+}
+
+// SendPolicy describes how MessageBus handles failed and redirected sends.
+type SendPolicy struct {
+	// Retries is a maximum number of resends after failed attempt. Zero disables retries.
+	Retries int
+	// BackoffMin, BackoffMax and BackoffFactor configure exponential delay between retries.
+	BackoffMin, BackoffMax time.Duration
+	BackoffFactor          float64
+	// Deadline limits total time of sending including all retries and redirects. Zero means no limit.
+	Deadline time.Duration
+	// Deadlines overrides Deadline for specific message types.
+	Deadlines map[MessageType]time.Duration
+	// FollowRedirects resends message to receiver from RedirectReply with provided delegation token.
+	FollowRedirects bool
+	// MaxRedirects limits number of redirects in a row. Single redirect is followed if zero.
+	MaxRedirects int
+	// RetryOnPulseChange resends message in a new pulse if pulse has changed while message was sent.
+	RetryOnPulseChange bool
+}
+
+// DeadlineFor returns send deadline for provided message type.
+func (p *SendPolicy) DeadlineFor(t MessageType) time.Duration {
+	if d, ok := p.Deadlines[t]; ok {
+		return d
+	}
+	return p.Deadline
 }
 
 // Safe returns original options, falling back on defaults if nil.
@@ -148,6 +179,16 @@ func (o *MessageSendOptions) Safe() *MessageSendOptions {
 	return o
 }
 
+// Delivery is a result of delivering published message to a single node.
+type Delivery struct {
+	// Receiver is a node the message was published to.
+	Receiver RecordRef
+	// Reply is an acknowledgement from receiver with results of every subscriber.
+	Reply Reply
+	// Err is set when message was not delivered to receiver.
+	Err error
+}
+
 // MessageBus interface
 //go:generate minimock -i github.com/insolar/insolar/core.MessageBus -o ../testutils -s _mock.go
 type MessageBus interface {
@@ -158,6 +199,12 @@ type MessageBus interface {
 	// MustRegister is a Register wrapper that panics if an error was returned.
 	MustRegister(p MessageType, handler MessageHandler)
 
+	// Publish sends a `Message` to every node responsible for its topic (message type and target object) without
+	// waiting for replies. Delivery reports are written to returned channel, it is closed when all nodes are answered.
+	Publish(context.Context, Message, *MessageSendOptions) (<-chan Delivery, error)
+	// Subscribe adds handler for published messages of provided type. Many subscribers can be added for one type.
+	Subscribe(p MessageType, handler MessageHandler) error
+
 	// NewPlayer creates a new player from stream. This is a very long operation, as it saves replies in storage until the
 	// stream is exhausted.
 	//
@@ -255,6 +302,12 @@ const (
 	TypeGetJet
 	// TypeAbandonedRequestsNotification informs virtual node about unclosed requests.
 	TypeAbandonedRequestsNotification
+	// TypeGetObjectHistory retrieves object states from the amend chain.
+	TypeGetObjectHistory
+	// TypeGetPrototypeObjects retrieves active objects of a prototype from heavy prototype index.
+	TypeGetPrototypeObjects
+	// TypeGetRecordProof retrieves proof of record inclusion into jet drop from heavy.
+	TypeGetRecordProof
 
 	// TypeValidationCheck checks if validation of a particular record can be performed.
 	TypeValidationCheck
@@ -289,4 +342,5 @@ const (
 	DTTypeGetObjectRedirect
 	DTTypeGetChildrenRedirect
 	DTTypeGetCodeRedirect
+	DTTypeGetObjectHistoryRedirect
 )
@@ -18,6 +18,7 @@ package core
 
 import (
 	"context"
+	"io"
 )
 
 // Cascade contains routing data for cascade sending
@@ -33,6 +34,10 @@ type Cascade struct {
 // RemoteProcedure is remote procedure call function.
 type RemoteProcedure func(ctx context.Context, args [][]byte) ([]byte, error)
 
+// RemoteStreamProcedure is remote procedure call function which reads its argument from stream while decoding it.
+// Argument size is limited by network, reader fails when the limit is reached.
+type RemoteStreamProcedure func(ctx context.Context, arg io.Reader) ([]byte, error)
+
 // Network is interface for network modules facade.
 type Network interface {
 	// SendParcel sends a message.
@@ -41,6 +46,9 @@ type Network interface {
 	SendCascadeMessage(data Cascade, method string, msg Parcel) error
 	// RemoteProcedureRegister is remote procedure register func.
 	RemoteProcedureRegister(name string, method RemoteProcedure)
+	// RemoteStreamProcedureRegister registers procedure for parcels received over stream,
+	// procedure registered with RemoteProcedureRegister is used for them otherwise.
+	RemoteStreamProcedureRegister(name string, method RemoteStreamProcedure)
 }
 
 // PulseDistributor is interface for pulse distribution.
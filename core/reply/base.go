@@ -39,6 +39,7 @@ const (
 	TypeGetCodeRedirect
 	TypeGetObjectRedirect
 	TypeGetChildrenRedirect
+	TypeGetObjectHistoryRedirect
 
 	// Logicrunner
 
@@ -61,6 +62,12 @@ const (
 	TypeID
 	// TypeChildren is a reply for fetching objects children in chunks.
 	TypeChildren
+	// TypeObjectHistory is a reply for fetching object states in chunks.
+	TypeObjectHistory
+	// TypePrototypeObjects is a reply for fetching objects of a prototype in chunks.
+	TypePrototypeObjects
+	// TypeRecordProof is a reply with proof of record inclusion into jet drop.
+	TypeRecordProof
 	// TypeObjectIndex contains serialized object index. It can be stored in DB without processing.
 	TypeObjectIndex
 	// TypeJetMiss is returned for miscalculated jets due to incomplete jet tree.
@@ -72,8 +79,13 @@ const (
 
 	// TypeHeavyError carries heavy record sync
 	TypeHeavyError
+	// TypeHeavyAck acknowledges stored heavy sync payload chunks.
+	TypeHeavyAck
 
 	TypeNodeSign
+
+	// TypeAck is a reply for published messages.
+	TypeAck
 )
 
 // ErrType is used to determine and compare reply errors.
@@ -104,10 +116,18 @@ func getEmptyReply(t core.ReplyType) (core.Reply, error) {
 		return &ID{}, nil
 	case TypeChildren:
 		return &Children{}, nil
+	case TypeObjectHistory:
+		return &ObjectHistory{}, nil
+	case TypePrototypeObjects:
+		return &PrototypeObjects{}, nil
+	case TypeRecordProof:
+		return &RecordProof{}, nil
 	case TypeError:
 		return &Error{}, nil
 	case TypeHeavyError:
 		return &HeavyError{}, nil
+	case TypeHeavyAck:
+		return &HeavyAck{}, nil
 	case TypeOK:
 		return &OK{}, nil
 	case TypeObjectIndex:
@@ -118,6 +138,8 @@ func getEmptyReply(t core.ReplyType) (core.Reply, error) {
 		return &GetObjectRedirectReply{}, nil
 	case TypeGetChildrenRedirect:
 		return &GetChildrenRedirectReply{}, nil
+	case TypeGetObjectHistoryRedirect:
+		return &GetObjectHistoryRedirectReply{}, nil
 	case TypeJetMiss:
 		return &JetMiss{}, nil
 	case TypePendingRequests:
@@ -127,6 +149,8 @@ func getEmptyReply(t core.ReplyType) (core.Reply, error) {
 
 	case TypeNodeSign:
 		return &NodeSign{}, nil
+	case TypeAck:
+		return &Ack{}, nil
 
 	default:
 		return nil, errors.Errorf("unimplemented reply type: '%d'", t)
@@ -181,14 +205,20 @@ func init() {
 	gob.Register(&Delegate{})
 	gob.Register(&ID{})
 	gob.Register(&Children{})
+	gob.Register(&ObjectHistory{})
+	gob.Register(&PrototypeObjects{})
+	gob.Register(&RecordProof{})
 	gob.Register(&Error{})
 	gob.Register(&OK{})
 	gob.Register(&ObjectIndex{})
 	gob.Register(&GetCodeRedirectReply{})
 	gob.Register(&GetObjectRedirectReply{})
 	gob.Register(&GetChildrenRedirectReply{})
+	gob.Register(&GetObjectHistoryRedirectReply{})
 	gob.Register(&HeavyError{})
+	gob.Register(&HeavyAck{})
 	gob.Register(&JetMiss{})
 	gob.Register(&NodeSign{})
 	gob.Register(&HasPendingRequests{})
+	gob.Register(&Ack{})
 }
@@ -16,7 +16,10 @@
 
 package reply
 
-import "github.com/insolar/insolar/core"
+import (
+	"github.com/insolar/insolar/core"
+	"github.com/pkg/errors"
+)
 
 // OK is a generic reply for signaling a positive result.
 type OK struct {
@@ -36,6 +39,29 @@ func (e *NotOK) Type() core.ReplyType {
 	return TypeNotOK
 }
 
+// Ack is a reply for published messages. It holds delivery results for every subscriber of receiving node.
+type Ack struct {
+	// Errors contains subscriber errors in subscription order. Empty string means successful delivery.
+	Errors []string
+	// Replies contains subscriber replies in subscription order. It is nil for failed subscribers.
+	Replies []core.Reply
+}
+
+// Type implementation of Reply interface.
+func (e *Ack) Type() core.ReplyType {
+	return TypeAck
+}
+
+// Err returns first subscriber error or nil if every subscriber succeeded.
+func (e *Ack) Err() error {
+	for _, s := range e.Errors {
+		if s != "" {
+			return errors.New(s)
+		}
+	}
+	return nil
+}
+
 // Error is common error reaction.
 type Error struct {
 	ErrType ErrType
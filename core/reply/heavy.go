@@ -22,9 +22,12 @@ import (
 	"github.com/insolar/insolar/core"
 )
 
-// ErrHeavySyncInProgress returned when heavy sync in progress.
 const (
+	// ErrHeavySyncInProgress returned when heavy sync in progress.
 	ErrHeavySyncInProgress ErrType = iota + 1
+	// ErrHeavySyncOutOfOrder returned when heavy lost sync state or received unexpected payload chunk.
+	// Sync should be resumed from acknowledged offset.
+	ErrHeavySyncOutOfOrder
 )
 
 // HeavyError carries heavy sync error information.
@@ -52,5 +55,18 @@ func (e *HeavyError) Error() string {
 
 // IsRetryable returns true if retry could be performed.
 func (e *HeavyError) IsRetryable() bool {
-	return e.SubType == ErrHeavySyncInProgress
+	return e.SubType == ErrHeavySyncInProgress || e.SubType == ErrHeavySyncOutOfOrder
+}
+
+// HeavyAck acknowledges heavy sync payload chunks.
+type HeavyAck struct {
+	JetID    core.RecordID
+	PulseNum core.PulseNumber
+	// Cursor is a cursor of the last payload chunk stored by heavy for the pulse (nil if nothing is stored).
+	Cursor []byte
+}
+
+// Type implementation of Reply interface.
+func (e *HeavyAck) Type() core.ReplyType {
+	return TypeHeavyAck
 }
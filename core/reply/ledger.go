@@ -78,6 +78,42 @@ func (e *Children) Type() core.ReplyType {
 	return TypeChildren
 }
 
+// ObjectHistory is a chunk of object states from the amend chain.
+type ObjectHistory struct {
+	States   []core.ObjectHistoryState
+	NextFrom *core.RecordID
+	// NextApproved is set if NextFrom precedes approved state, so it is approved too. It should be passed to the
+	// next request, because approved state could be in the same pulse as NextFrom.
+	NextApproved bool
+}
+
+// Type implementation of Reply interface.
+func (e *ObjectHistory) Type() core.ReplyType {
+	return TypeObjectHistory
+}
+
+// PrototypeObjects is a chunk of active objects of a prototype.
+type PrototypeObjects struct {
+	Refs     []core.RecordRef
+	NextFrom *core.RecordID
+	Count    int // Number of returned objects or total number of objects for count requests.
+}
+
+// Type implementation of Reply interface.
+func (e *PrototypeObjects) Type() core.ReplyType {
+	return TypePrototypeObjects
+}
+
+// RecordProof contains proof of record inclusion into jet drop.
+type RecordProof struct {
+	Proof core.RecordProof
+}
+
+// Type implementation of Reply interface.
+func (e *RecordProof) Type() core.ReplyType {
+	return TypeRecordProof
+}
+
 // ObjectIndex contains serialized object index. It can be stored in DB without processing.
 type ObjectIndex struct {
 	Index []byte
@@ -150,3 +150,55 @@ func (r *GetCodeRedirectReply) Type() core.ReplyType {
 func (r *GetCodeRedirectReply) Redirected(genericMsg core.Message) core.Message {
 	return genericMsg
 }
+
+// GetObjectHistoryRedirectReply is a redirect reply for get object history.
+type GetObjectHistoryRedirectReply struct {
+	Receiver *core.RecordRef
+	Token    core.DelegationToken
+
+	FromState core.RecordID
+}
+
+// NewGetObjectHistoryRedirect creates a new instance of GetObjectHistoryRedirectReply.
+func NewGetObjectHistoryRedirect(
+	factory core.DelegationTokenFactory, parcel core.Parcel, receiver *core.RecordRef, fromState core.RecordID,
+) (*GetObjectHistoryRedirectReply, error) {
+	var err error
+	rep := GetObjectHistoryRedirectReply{
+		Receiver:  receiver,
+		FromState: fromState,
+	}
+	redirectedMessage := rep.Redirected(parcel.Message())
+	sender := parcel.GetSender()
+	rep.Token, err = factory.IssueGetObjectHistoryRedirect(&sender, redirectedMessage)
+	if err != nil {
+		return nil, err
+	}
+	return &rep, nil
+}
+
+// GetReceiver returns node reference to send message to.
+func (r *GetObjectHistoryRedirectReply) GetReceiver() *core.RecordRef {
+	return r.Receiver
+}
+
+// GetToken returns delegation token.
+func (r *GetObjectHistoryRedirectReply) GetToken() core.DelegationToken {
+	return r.Token
+}
+
+// Type returns type of the reply
+func (r *GetObjectHistoryRedirectReply) Type() core.ReplyType {
+	return TypeGetObjectHistoryRedirect
+}
+
+// Redirected creates redirected message from redirect data.
+func (r *GetObjectHistoryRedirectReply) Redirected(genericMsg core.Message) core.Message {
+	msg := genericMsg.(*message.GetObjectHistory)
+	return &message.GetObjectHistory{
+		Head:      msg.Head,
+		FromState: &r.FromState,
+		FromPulse: msg.FromPulse,
+		Amount:    msg.Amount,
+	}
+}
@@ -0,0 +1,109 @@
+// +build functest
+
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package functest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type assetInfo struct {
+	Symbol  string `json:"symbol"`
+	Name    string `json:"name"`
+	Issuer  string `json:"issuer"`
+	Supply  int    `json:"supply"`
+	Balance int    `json:"balance"`
+}
+
+func getAssets(t *testing.T, caller *user) map[string]assetInfo {
+	res, err := signedRequest(caller, "GetAssets")
+	require.NoError(t, err)
+
+	data, err := base64.StdEncoding.DecodeString(res.(string))
+	require.NoError(t, err)
+
+	var list []assetInfo
+	err = json.Unmarshal(data, &list)
+	require.NoError(t, err)
+
+	assets := make(map[string]assetInfo, len(list))
+	for _, a := range list {
+		assets[a.Symbol] = a
+	}
+	return assets
+}
+
+func checkAssetBalanceFewTimes(t *testing.T, caller *user, symbol string, expected int) {
+	for i := 0; i < times; i++ {
+		if getAssets(t, caller)[symbol].Balance == expected {
+			return
+		}
+		time.Sleep(time.Second)
+	}
+	t.Error("Received asset balance is not equal expected")
+}
+
+func TestIssueAsset(t *testing.T) {
+	issuer := createMember(t, "Issuer")
+
+	_, err := signedRequest(issuer, "IssueAsset", "GLD", "Gold", 1000)
+	require.NoError(t, err)
+
+	assets := getAssets(t, issuer)
+	require.Contains(t, assets, "GLD")
+	require.Equal(t, "Gold", assets["GLD"].Name)
+	require.Equal(t, issuer.ref, assets["GLD"].Issuer)
+	require.Equal(t, 1000, assets["GLD"].Supply)
+	require.Equal(t, 1000, assets["GLD"].Balance)
+}
+
+func TestIssueAssetTwice(t *testing.T) {
+	issuer := createMember(t, "Issuer")
+
+	_, err := signedRequest(issuer, "IssueAsset", "SLV", "Silver", 10)
+	require.NoError(t, err)
+	_, err = signedRequest(issuer, "IssueAsset", "SLV", "Silver", 10)
+	require.Contains(t, err.Error(), "already exists")
+}
+
+func TestTransferAsset(t *testing.T) {
+	issuer := createMember(t, "Issuer")
+	receiver := createMember(t, "Receiver")
+
+	_, err := signedRequest(issuer, "IssueAsset", "PLT", "Platinum", 100)
+	require.NoError(t, err)
+
+	_, err = signedRequest(issuer, "TransferAsset", "PLT", 30, receiver.ref)
+	require.NoError(t, err)
+
+	require.Equal(t, 70, getAssets(t, issuer)["PLT"].Balance)
+	checkAssetBalanceFewTimes(t, receiver, "PLT", 30)
+}
+
+func TestTransferAssetNotEnoughBalance(t *testing.T) {
+	sender := createMember(t, "Sender")
+	receiver := createMember(t, "Receiver")
+
+	_, err := signedRequest(sender, "TransferAsset", "NOPE", 1, receiver.ref)
+	require.Contains(t, err.Error(), "Not enough balance")
+}
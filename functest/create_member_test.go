@@ -65,5 +65,5 @@ func TestCreateMembersWithSameName(t *testing.T) {
 func TestCreateMemberByNoRoot(t *testing.T) {
 	member := createMember(t, "Member1")
 	_, err := signedRequest(member, "CreateMember", "Member2", "000")
-	require.EqualError(t, err, "[ makeCall ] Error in called method: [ CreateMember ] Only Root member can create members")
+	require.EqualError(t, err, "[ makeCall ] Error in called method: [ CreateMember ] Only Root member or admin multisig can create members")
 }
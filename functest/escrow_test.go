@@ -0,0 +1,86 @@
+// +build functest
+
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package functest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createEscrow(t *testing.T, from *user, to *user, hashLock string, arbiter string) string {
+	res, err := signedRequest(from, "CreateEscrow", "", 100, to.ref, hashLock, 0, 0, arbiter, 3600)
+	require.NoError(t, err)
+	ref, ok := res.(string)
+	require.True(t, ok)
+	return ref
+}
+
+func TestEscrowHashLock(t *testing.T) {
+	buyer := createMember(t, "Buyer")
+	seller := createMember(t, "Seller")
+
+	hash := sha256.Sum256([]byte("delivered"))
+	escrowRef := createEscrow(t, buyer, seller, hex.EncodeToString(hash[:]), "")
+
+	_, err := signedRequest(seller, "ReleaseEscrow", escrowRef, "wrong")
+	require.Contains(t, err.Error(), "wrong preimage")
+
+	_, err = signedRequest(seller, "ReleaseEscrow", escrowRef, "delivered")
+	require.NoError(t, err)
+}
+
+func TestEscrowArbiter(t *testing.T) {
+	buyer := createMember(t, "Buyer")
+	seller := createMember(t, "Seller")
+	arbiter := createMember(t, "Arbiter")
+
+	escrowRef := createEscrow(t, buyer, seller, "", arbiter.ref)
+
+	_, err := signedRequest(seller, "ReleaseEscrow", escrowRef, "")
+	require.Contains(t, err.Error(), "not approved by arbiter")
+
+	_, err = signedRequest(seller, "ApproveEscrow", escrowRef)
+	require.Contains(t, err.Error(), "Only arbiter can approve escrow")
+
+	_, err = signedRequest(arbiter, "ApproveEscrow", escrowRef)
+	require.NoError(t, err)
+
+	_, err = signedRequest(seller, "ReleaseEscrow", escrowRef, "")
+	require.NoError(t, err)
+}
+
+func TestEscrowRejectAndRefund(t *testing.T) {
+	buyer := createMember(t, "Buyer")
+	seller := createMember(t, "Seller")
+	arbiter := createMember(t, "Arbiter")
+
+	escrowRef := createEscrow(t, buyer, seller, "", arbiter.ref)
+
+	_, err := signedRequest(buyer, "RefundEscrow", escrowRef)
+	require.Contains(t, err.Error(), "neither expired nor rejected")
+
+	_, err = signedRequest(arbiter, "RejectEscrow", escrowRef)
+	require.NoError(t, err)
+
+	_, err = signedRequest(buyer, "RefundEscrow", escrowRef)
+	require.NoError(t, err)
+}
@@ -0,0 +1,91 @@
+// +build functest
+
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package functest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type historyEntry struct {
+	Direction    string `json:"direction"`
+	Counterparty string `json:"counterparty"`
+	Asset        string `json:"asset"`
+	Amount       int    `json:"amount"`
+	Pulse        int    `json:"pulse"`
+	Request      string `json:"request"`
+}
+
+type historyResponse struct {
+	Entries    []historyEntry `json:"entries"`
+	NextCursor string         `json:"next_cursor"`
+}
+
+func getHistory(t *testing.T, caller *user, params ...interface{}) historyResponse {
+	res, err := signedRequest(caller, "GetHistory", params...)
+	require.NoError(t, err)
+
+	data, err := base64.StdEncoding.DecodeString(res.(string))
+	require.NoError(t, err)
+
+	var history historyResponse
+	err = json.Unmarshal(data, &history)
+	require.NoError(t, err)
+	return history
+}
+
+func TestGetHistory(t *testing.T) {
+	firstMember := createMember(t, "Member1")
+	secondMember := createMember(t, "Member2")
+
+	_, err := signedRequest(firstMember, "Transfer", 111, secondMember.ref)
+	require.NoError(t, err)
+
+	history := getHistory(t, firstMember, 0, 0, "", 0)
+	require.Len(t, history.Entries, 1)
+	require.Equal(t, "out", history.Entries[0].Direction)
+	require.Equal(t, secondMember.ref, history.Entries[0].Counterparty)
+	require.Equal(t, 111, history.Entries[0].Amount)
+	require.Empty(t, history.NextCursor)
+}
+
+func TestGetHistoryPagination(t *testing.T) {
+	firstMember := createMember(t, "Member1")
+	secondMember := createMember(t, "Member2")
+
+	for i := 1; i <= 3; i++ {
+		_, err := signedRequest(firstMember, "Transfer", i, secondMember.ref)
+		require.NoError(t, err)
+	}
+
+	// Entries are returned newest first.
+	page := getHistory(t, firstMember, 0, 0, "", 2)
+	require.Len(t, page.Entries, 2)
+	require.Equal(t, 3, page.Entries[0].Amount)
+	require.Equal(t, 2, page.Entries[1].Amount)
+	require.NotEmpty(t, page.NextCursor)
+
+	page = getHistory(t, firstMember, 0, 0, page.NextCursor, 2)
+	require.Len(t, page.Entries, 1)
+	require.Equal(t, 1, page.Entries[0].Amount)
+	require.Empty(t, page.NextCursor)
+}
@@ -0,0 +1,97 @@
+// +build functest
+
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package functest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type keyRecord struct {
+	PublicKey string `json:"public_key"`
+	Pulse     int    `json:"pulse"`
+	Reason    string `json:"reason"`
+}
+
+func getKeyHistory(t *testing.T, caller *user) []keyRecord {
+	res, err := signedRequest(caller, "GetKeyHistory")
+	require.NoError(t, err)
+
+	data, err := base64.StdEncoding.DecodeString(res.(string))
+	require.NoError(t, err)
+
+	var history []keyRecord
+	err = json.Unmarshal(data, &history)
+	require.NoError(t, err)
+	return history
+}
+
+func TestRotateKey(t *testing.T) {
+	member := createMember(t, "Member")
+	oldKey := member.pubKey
+
+	newKeys, err := newUserWithKeys()
+	require.NoError(t, err)
+
+	_, err = signedRequest(member, "RotateKey", newKeys.pubKey)
+	require.NoError(t, err)
+
+	oldMember := *member
+	member.privKey, member.pubKey = newKeys.privKey, newKeys.pubKey
+
+	history := getKeyHistory(t, member)
+	require.Len(t, history, 2)
+	require.Equal(t, oldKey, history[0].PublicKey)
+	require.Equal(t, "created", history[0].Reason)
+	require.Equal(t, newKeys.pubKey, history[1].PublicKey)
+	require.Equal(t, "rotated", history[1].Reason)
+	require.True(t, history[0].Pulse <= history[1].Pulse)
+
+	_, err = signedRequest(&oldMember, "GetMyBalance")
+	require.Contains(t, err.Error(), "Incorrect signature")
+}
+
+func TestRevokeKey(t *testing.T) {
+	member := createMember(t, "Member")
+
+	newKeys, err := newUserWithKeys()
+	require.NoError(t, err)
+
+	_, err = signedRequest(&root, "RevokeKey", member.ref, newKeys.pubKey)
+	require.NoError(t, err)
+
+	member.privKey, member.pubKey = newKeys.privKey, newKeys.pubKey
+	history := getKeyHistory(t, member)
+	require.Len(t, history, 2)
+	require.Equal(t, "revoked", history[1].Reason)
+}
+
+func TestRevokeKeyNotRoot(t *testing.T) {
+	member := createMember(t, "Member")
+	victim := createMember(t, "Victim")
+
+	newKeys, err := newUserWithKeys()
+	require.NoError(t, err)
+
+	_, err = signedRequest(member, "RevokeKey", victim.ref, newKeys.pubKey)
+	require.Contains(t, err.Error(), "Only Root member can revoke keys")
+}
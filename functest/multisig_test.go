@@ -0,0 +1,172 @@
+// +build functest
+
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package functest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createMultiSig(t *testing.T, threshold int, admin bool, coSigners ...*user) string {
+	keys := make([]string, len(coSigners))
+	for i, u := range coSigners {
+		keys[i] = u.pubKey
+	}
+	result, err := signedRequest(&root, "CreateMultiSig", "Treasury", keys, threshold, admin)
+	require.NoError(t, err)
+	ref, ok := result.(string)
+	require.True(t, ok)
+	for _, u := range coSigners {
+		u.ref = ref
+	}
+	return ref
+}
+
+func newCoSigner(t *testing.T) *user {
+	u, err := newUserWithKeys()
+	require.NoError(t, err)
+	return u
+}
+
+func getPendingCalls(t *testing.T, caller *user) []map[string]interface{} {
+	res, err := signedRequest(caller, "GetPendingCalls")
+	require.NoError(t, err)
+	data, err := base64.StdEncoding.DecodeString(res.(string))
+	require.NoError(t, err)
+	var calls []map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &calls))
+	return calls
+}
+
+func TestMultiSigTransfer(t *testing.T) {
+	first, second := newCoSigner(t), newCoSigner(t)
+	msRef := createMultiSig(t, 2, false, first, second)
+	receiver := createMember(t, "Receiver")
+
+	_, err := signedRequest(&root, "Transfer", 100, msRef)
+	require.NoError(t, err)
+
+	res, err := signedRequest(first, "Transfer", 10, receiver.ref)
+	require.NoError(t, err)
+	data, err := base64.StdEncoding.DecodeString(res.(string))
+	require.NoError(t, err)
+	pending := struct {
+		PendingCall string `json:"pending_call"`
+		Approvals   int    `json:"approvals"`
+		Threshold   int    `json:"threshold"`
+	}{}
+	require.NoError(t, json.Unmarshal(data, &pending))
+	require.NotEmpty(t, pending.PendingCall)
+	require.Equal(t, 1, pending.Approvals)
+	require.Equal(t, 2, pending.Threshold)
+
+	_, err = signedRequest(second, "Transfer", 10, receiver.ref)
+	require.NoError(t, err)
+
+	checkBalanceFewTimes(t, &root, receiver.ref, 1000*1000*1000+10)
+	checkBalanceFewTimes(t, &root, msRef, 90)
+	require.Empty(t, getPendingCalls(t, first))
+}
+
+func TestMultiSigCreateMember(t *testing.T) {
+	first, second := newCoSigner(t), newCoSigner(t)
+	createMultiSig(t, 2, true, first, second)
+	member := newCoSigner(t)
+
+	_, err := signedRequest(first, "CreateMember", "Member", member.pubKey)
+	require.NoError(t, err)
+	res, err := signedRequest(second, "CreateMember", "Member", member.pubKey)
+	require.NoError(t, err)
+	memberRef, ok := res.(string)
+	require.True(t, ok)
+
+	resp, err := signedRequest(&root, "DumpUserInfo", memberRef)
+	require.NoError(t, err)
+	data, err := base64.StdEncoding.DecodeString(resp.(string))
+	require.NoError(t, err)
+	result := struct {
+		Member string
+		Wallet int
+	}{}
+	require.NoError(t, json.Unmarshal(data, &result))
+	require.Equal(t, "Member", result.Member)
+	require.Equal(t, 1000*1000*1000, result.Wallet)
+}
+
+func TestMultiSigRegisterNode(t *testing.T) {
+	const publicKey = "multisig_node_public_key"
+	first, second := newCoSigner(t), newCoSigner(t)
+	createMultiSig(t, 2, true, first, second)
+
+	_, err := signedRequest(first, "RegisterNode", publicKey, "virtual")
+	require.NoError(t, err)
+	res, err := signedRequest(second, "RegisterNode", publicKey, "virtual")
+	require.NoError(t, err)
+	ref, ok := res.(string)
+	require.True(t, ok)
+
+	nodeRef, err := getNodeRefSignedCall(publicKey)
+	require.NoError(t, err)
+	require.Equal(t, ref, nodeRef)
+}
+
+func TestMultiSigNotAdminKeepsPendingCall(t *testing.T) {
+	first, second := newCoSigner(t), newCoSigner(t)
+	createMultiSig(t, 2, false, first, second)
+	member := newCoSigner(t)
+
+	_, err := signedRequest(first, "CreateMember", "Member", member.pubKey)
+	require.NoError(t, err)
+	_, err = signedRequest(second, "CreateMember", "Member", member.pubKey)
+	require.Contains(t, err.Error(), "Only Root member or admin multisig can create members")
+
+	calls := getPendingCalls(t, first)
+	require.Len(t, calls, 1)
+	require.Equal(t, "CreateMember", calls[0]["method"])
+	require.Equal(t, float64(2), calls[0]["approvals"])
+}
+
+func TestMultiSigDoubleApprove(t *testing.T) {
+	first, second := newCoSigner(t), newCoSigner(t)
+	createMultiSig(t, 2, false, first, second)
+	receiver := createMember(t, "Receiver")
+
+	_, err := signedRequest(first, "Transfer", 10, receiver.ref)
+	require.NoError(t, err)
+	_, err = signedRequest(first, "Transfer", 10, receiver.ref)
+	require.Contains(t, err.Error(), "already approved")
+}
+
+func TestMultiSigWrongKey(t *testing.T) {
+	first, second := newCoSigner(t), newCoSigner(t)
+	msRef := createMultiSig(t, 1, false, first)
+	second.ref = msRef
+
+	_, err := signedRequest(second, "GetMyBalance")
+	require.Contains(t, err.Error(), "Incorrect signature")
+}
+
+func TestCreateMultiSigBadThreshold(t *testing.T) {
+	first := newCoSigner(t)
+	_, err := signedRequest(&root, "CreateMultiSig", "Treasury", []string{first.pubKey}, 2, false)
+	require.Contains(t, err.Error(), "Threshold must be between 1 and number of keys")
+}
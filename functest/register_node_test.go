@@ -76,7 +76,7 @@ func TestRegisterNodeByNoRoot(t *testing.T) {
 	member := createMember(t, "Member1")
 	const testRole = "virtual"
 	_, err := signedRequest(member, "RegisterNode", TESTPUBLICKEY, testRole)
-	require.Contains(t, err.Error(), "[ RegisterNode ] Only Root member can register node")
+	require.Contains(t, err.Error(), "[ RegisterNode ] Only Root member or admin multisig can register node")
 }
 
 func TestReceiveNodeCert(t *testing.T) {
@@ -25,6 +25,7 @@ import (
 	"path"
 	"strconv"
 
+	"github.com/insolar/insolar/application/contract/assetregistry"
 	"github.com/insolar/insolar/application/contract/member"
 	"github.com/insolar/insolar/application/contract/nodedomain"
 	"github.com/insolar/insolar/application/contract/noderecord"
@@ -45,9 +46,17 @@ const (
 	walletContract    = "wallet"
 	memberContract    = "member"
 	allowanceContract = "allowance"
+	assetRegistry     = "assetregistry"
+	multiSigContract  = "multisig"
+	pendingCall       = "pendingcall"
+	escrowContract    = "escrow"
+	historyEntry      = "historyentry"
 )
 
-var contractNames = []string{walletContract, memberContract, allowanceContract, rootDomain, nodeDomain, nodeRecord}
+var contractNames = []string{
+	walletContract, memberContract, allowanceContract, rootDomain, nodeDomain, nodeRecord, assetRegistry,
+	multiSigContract, pendingCall, escrowContract, historyEntry,
+}
 
 type messageBusLocker interface {
 	Lock(ctx context.Context)
@@ -56,15 +65,16 @@ type messageBusLocker interface {
 
 // Genesis is a component for precreation core contracts types and RootDomain instance
 type Genesis struct {
-	rootDomainRef   *core.RecordRef
-	nodeDomainRef   *core.RecordRef
-	rootMemberRef   *core.RecordRef
-	prototypeRefs   map[string]*core.RecordRef
-	isGenesis       bool
-	config          *Config
-	keyOut          string
-	ArtifactManager core.ArtifactManager `inject:""`
-	MBLock          messageBusLocker     `inject:""`
+	rootDomainRef    *core.RecordRef
+	nodeDomainRef    *core.RecordRef
+	assetRegistryRef *core.RecordRef
+	rootMemberRef    *core.RecordRef
+	prototypeRefs    map[string]*core.RecordRef
+	isGenesis        bool
+	config           *Config
+	keyOut           string
+	ArtifactManager  core.ArtifactManager `inject:""`
+	MBLock           messageBusLocker     `inject:""`
 }
 
 // NewGenesis creates new Genesis
@@ -175,6 +185,47 @@ func (g *Genesis) activateNodeDomain(
 	return desc, nil
 }
 
+func (g *Genesis) activateAssetRegistry(
+	ctx context.Context, domain *core.RecordID, cb *ContractsBuilder,
+) error {
+	ar, err := assetregistry.NewAssetRegistry()
+	if err != nil {
+		return errors.Wrap(err, "[ ActivateAssetRegistry ]")
+	}
+
+	instanceData, err := serializeInstance(ar)
+	if err != nil {
+		return errors.Wrap(err, "[ ActivateAssetRegistry ]")
+	}
+
+	contractID, err := g.ArtifactManager.RegisterRequest(ctx, *g.rootDomainRef, &message.Parcel{Msg: &message.GenesisRequest{Name: "AssetRegistry"}})
+
+	if err != nil {
+		return errors.Wrap(err, "[ ActivateAssetRegistry ] couldn't create asset registry instance")
+	}
+	contract := core.NewRecordRef(*domain, *contractID)
+	_, err = g.ArtifactManager.ActivateObject(
+		ctx,
+		core.RecordRef{},
+		*contract,
+		*g.rootDomainRef,
+		*cb.Prototypes[assetRegistry],
+		false,
+		instanceData,
+	)
+	if err != nil {
+		return errors.Wrap(err, "[ ActivateAssetRegistry ] couldn't create asset registry instance")
+	}
+	_, err = g.ArtifactManager.RegisterResult(ctx, *g.rootDomainRef, *contract, nil)
+	if err != nil {
+		return errors.Wrap(err, "[ ActivateAssetRegistry ] couldn't create asset registry instance")
+	}
+
+	g.assetRegistryRef = contract
+
+	return nil
+}
+
 func (g *Genesis) activateRootMember(
 	ctx context.Context, domain *core.RecordID, cb *ContractsBuilder, rootPubKey string,
 ) error {
@@ -219,7 +270,11 @@ func (g *Genesis) activateRootMember(
 func (g *Genesis) updateRootDomain(
 	ctx context.Context, domainDesc core.ObjectDescriptor,
 ) error {
-	updateData, err := serializeInstance(&rootdomain.RootDomain{RootMember: *g.rootMemberRef, NodeDomainRef: *g.nodeDomainRef})
+	updateData, err := serializeInstance(&rootdomain.RootDomain{
+		RootMember:       *g.rootMemberRef,
+		NodeDomainRef:    *g.nodeDomainRef,
+		AssetRegistryRef: *g.assetRegistryRef,
+	})
 	if err != nil {
 		return errors.Wrap(err, "[ updateRootDomain ]")
 	}
@@ -290,6 +345,10 @@ func (g *Genesis) activateSmartContracts(
 	if err != nil {
 		return nil, errors.Wrap(err, errMsg)
 	}
+	err = g.activateAssetRegistry(ctx, rootDomainID, cb)
+	if err != nil {
+		return nil, errors.Wrap(err, errMsg)
+	}
 	err = g.activateRootMember(ctx, rootDomainID, cb, rootPubKey)
 	if err != nil {
 		return nil, errors.Wrap(err, errMsg)
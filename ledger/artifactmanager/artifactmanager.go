@@ -36,6 +36,7 @@ import (
 
 const (
 	getChildrenChunkSize = 10 * 1000
+	getHistoryChunkSize  = 1000
 	jetMissRetryCount    = 10
 )
 
@@ -51,6 +52,7 @@ type LedgerArtifactManager struct {
 	codeCache     map[core.RecordRef]*cacheEntry
 
 	getChildrenChunkSize int
+	getHistoryChunkSize  int
 }
 
 type cacheEntry struct {
@@ -69,6 +71,7 @@ func NewArtifactManger(db *storage.DB) *LedgerArtifactManager {
 	return &LedgerArtifactManager{
 		db:                   db,
 		getChildrenChunkSize: getChildrenChunkSize,
+		getHistoryChunkSize:  getHistoryChunkSize,
 		codeCacheLock:        &sync.Mutex{},
 		codeCache:            make(map[core.RecordRef]*cacheEntry),
 	}
@@ -285,6 +288,136 @@ func (m *LedgerArtifactManager) GetChildren(
 	return iter, err
 }
 
+// GetObjectHistory returns object states walking the amend chain from the latest state to the activation.
+//
+// If pulse is provided, states created after this pulse are skipped, so the first returned state is the object
+// state as of provided pulse. At most limit states are returned (all states if limit is not positive).
+func (m *LedgerArtifactManager) GetObjectHistory(
+	ctx context.Context, head core.RecordRef, pulse *core.PulseNumber, limit int,
+) ([]core.ObjectHistoryState, error) {
+	inslogger.FromContext(ctx).Debug("LedgerArtifactManager.GetObjectHistory starts ...")
+	var err error
+	defer instrument(ctx, "GetObjectHistory").err(&err).end()
+
+	currentPulse, err := m.PulseStorage.Current(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		states       []core.ObjectHistoryState
+		from         *core.RecordID
+		fromApproved bool
+	)
+	for {
+		amount := m.getHistoryChunkSize
+		if limit > 0 && limit-len(states) < amount {
+			amount = limit - len(states)
+		}
+
+		var rep core.Reply
+		rep, err = sendAndFollowRedirect(ctx, m.bus(ctx), m.db, &message.GetObjectHistory{
+			Head:         head,
+			FromState:    from,
+			FromApproved: fromApproved,
+			FromPulse:    pulse,
+			Amount:       amount,
+		}, *currentPulse)
+		if err != nil {
+			return nil, err
+		}
+
+		switch r := rep.(type) {
+		case *reply.ObjectHistory:
+			states = append(states, r.States...)
+			from, fromApproved = r.NextFrom, r.NextApproved
+		case *reply.Error:
+			err = r.Error()
+			return nil, err
+		default:
+			err = fmt.Errorf("GetObjectHistory: unexpected reply: %#v", rep)
+			return nil, err
+		}
+
+		if from == nil || (limit > 0 && len(states) >= limit) {
+			return states, nil
+		}
+	}
+}
+
+// GetPrototypeObjects returns heads of active objects of provided prototype.
+//
+// Objects are fetched from prototype index maintained by heavy material node.
+func (m *LedgerArtifactManager) GetPrototypeObjects(
+	ctx context.Context, prototype core.RecordRef, from *core.RecordID, limit int,
+) ([]core.RecordRef, *core.RecordID, error) {
+	var err error
+	defer instrument(ctx, "GetPrototypeObjects").err(&err).end()
+
+	rep, err := m.getPrototypeObjects(ctx, &message.GetPrototypeObjects{
+		Prototype:  prototype,
+		FromObject: from,
+		Amount:     limit,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return rep.Refs, rep.NextFrom, nil
+}
+
+// CountPrototypeObjects returns number of active objects of provided prototype.
+func (m *LedgerArtifactManager) CountPrototypeObjects(ctx context.Context, prototype core.RecordRef) (int, error) {
+	var err error
+	defer instrument(ctx, "CountPrototypeObjects").err(&err).end()
+
+	rep, err := m.getPrototypeObjects(ctx, &message.GetPrototypeObjects{
+		Prototype: prototype,
+		CountOnly: true,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return rep.Count, nil
+}
+
+func (m *LedgerArtifactManager) getPrototypeObjects(
+	ctx context.Context, msg *message.GetPrototypeObjects,
+) (*reply.PrototypeObjects, error) {
+	genericReply, err := m.bus(ctx).Send(ctx, msg, nil)
+	if err != nil {
+		return nil, err
+	}
+	switch r := genericReply.(type) {
+	case *reply.PrototypeObjects:
+		return r, nil
+	case *reply.Error:
+		return nil, r.Error()
+	}
+	return nil, fmt.Errorf("getPrototypeObjects: unexpected reply: %#v", genericReply)
+}
+
+// GetRecordProof returns proof of record inclusion into jet drop.
+//
+// Proof is built by heavy material node, so record is provable only after its jet drop is replicated.
+func (m *LedgerArtifactManager) GetRecordProof(ctx context.Context, id core.RecordID) (*core.RecordProof, error) {
+	var err error
+	defer instrument(ctx, "GetRecordProof").err(&err).end()
+
+	genericReply, err := m.bus(ctx).Send(ctx, &message.GetRecordProof{Record: id}, nil)
+	if err != nil {
+		return nil, err
+	}
+	switch r := genericReply.(type) {
+	case *reply.RecordProof:
+		return &r.Proof, nil
+	case *reply.Error:
+		err = r.Error()
+		return nil, err
+	}
+	err = fmt.Errorf("GetRecordProof: unexpected reply: %#v", genericReply)
+	return nil, err
+}
+
 // DeclareType creates new type record in storage.
 //
 // Type is a contract interface. It contains one method signature.
@@ -781,6 +914,10 @@ func (m *LedgerArtifactManager) bus(ctx context.Context) core.MessageBus {
 	return core.MessageBusFromContext(ctx, m.DefaultBus)
 }
 
+// sendAndFollowRedirect sends message, resolving jet misses and following a single redirect.
+//
+// Redirects are followed here instead of with core.SendPolicy: jet miss must be resolved with local jet tree before
+// redirect is followed, and during validation bus from context is a player, which doesn't apply send policy.
 func sendAndFollowRedirect(
 	ctx context.Context,
 	bus core.MessageBus,
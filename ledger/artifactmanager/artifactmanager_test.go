@@ -121,6 +121,7 @@ func getTestData(t *testing.T) (
 		db:                         db,
 		DefaultBus:                 mb,
 		getChildrenChunkSize:       100,
+		getHistoryChunkSize:        100,
 		PlatformCryptographyScheme: scheme,
 		PulseStorage:               pulseStorage,
 	}
@@ -683,6 +684,206 @@ func TestLedgerArtifactManager_GetChildren_FollowsRedirect(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestLedgerArtifactManager_GetObjectHistory(t *testing.T) {
+	t.Parallel()
+	ctx, db, am, cleaner := getTestData(t)
+	defer cleaner()
+	jetID := *jet.NewID(0, nil)
+
+	pulses := []core.PulseNumber{
+		core.GenesisPulse.PulseNumber,
+		core.GenesisPulse.PulseNumber + 10,
+		core.GenesisPulse.PulseNumber + 20,
+	}
+	for _, pn := range pulses[1:] {
+		err := db.AddPulse(ctx, core.Pulse{PulseNumber: pn})
+		require.NoError(t, err)
+		err = db.UpdateJetTree(ctx, pn, true, jetID)
+		require.NoError(t, err)
+	}
+
+	var (
+		prevState *core.RecordID
+		states    []*core.RecordID
+		requests  []core.RecordRef
+	)
+	for i, pn := range pulses {
+		memory := []byte{byte(i)}
+		_, err := db.SetBlob(ctx, jetID, pn, memory)
+		require.NoError(t, err)
+		sideEffect := record.SideEffectRecord{Domain: domainRef, Request: *genRandomRef(pn)}
+		stateRecord := record.ObjectStateRecord{
+			Memory: record.CalculateIDForBlob(am.PlatformCryptographyScheme, pn, memory),
+			Image:  *genRandomRef(0),
+		}
+		var rec record.Record
+		if prevState == nil {
+			rec = &record.ObjectActivateRecord{SideEffectRecord: sideEffect, ObjectStateRecord: stateRecord}
+		} else {
+			rec = &record.ObjectAmendRecord{SideEffectRecord: sideEffect, ObjectStateRecord: stateRecord, PrevState: *prevState}
+		}
+		prevState, err = db.SetRecord(ctx, jetID, pn, rec)
+		require.NoError(t, err)
+		states = append(states, prevState)
+		requests = append(requests, sideEffect.Request)
+	}
+
+	objRef := genRandomRef(0)
+	require.NoError(t, db.SetObjectIndex(ctx, jetID, objRef.Record(), &index.ObjectLifeline{
+		LatestState:         states[2],
+		LatestStateApproved: states[1],
+	}))
+
+	t.Run("full history", func(t *testing.T) {
+		history, err := am.GetObjectHistory(ctx, *objRef, nil, 0)
+		require.NoError(t, err)
+		require.Len(t, history, 3)
+		for i, state := range history {
+			idx := 2 - i
+			assert.Equal(t, *states[idx], state.State)
+			assert.Equal(t, pulses[idx], state.Pulse)
+			assert.Equal(t, requests[idx], state.Request)
+			assert.Equal(t, []byte{byte(idx)}, state.Memory)
+			assert.Equal(t, idx < 2, state.Approved)
+			assert.False(t, state.Deactivated)
+		}
+	})
+
+	t.Run("limited history", func(t *testing.T) {
+		history, err := am.GetObjectHistory(ctx, *objRef, nil, 2)
+		require.NoError(t, err)
+		require.Len(t, history, 2)
+		assert.Equal(t, *states[1], history[1].State)
+	})
+
+	t.Run("state as of pulse", func(t *testing.T) {
+		asOf := pulses[1] + 5
+		history, err := am.GetObjectHistory(ctx, *objRef, &asOf, 1)
+		require.NoError(t, err)
+		require.Len(t, history, 1)
+		assert.Equal(t, *states[1], history[0].State)
+		assert.Equal(t, []byte{1}, history[0].Memory)
+	})
+}
+
+func TestLedgerArtifactManager_GetObjectHistory_Chunks(t *testing.T) {
+	t.Parallel()
+	ctx, db, am, cleaner := getTestData(t)
+	defer cleaner()
+	jetID := *jet.NewID(0, nil)
+
+	pulses := []core.PulseNumber{core.GenesisPulse.PulseNumber, core.GenesisPulse.PulseNumber + 10}
+	require.NoError(t, db.AddPulse(ctx, core.Pulse{PulseNumber: pulses[1]}))
+	require.NoError(t, db.UpdateJetTree(ctx, pulses[1], true, jetID))
+
+	// The first two states are in the same pulse, the second one is the latest approved.
+	var states []*core.RecordID
+	for _, pn := range []core.PulseNumber{pulses[0], pulses[0], pulses[1]} {
+		sideEffect := record.SideEffectRecord{Domain: domainRef, Request: *genRandomRef(pn)}
+		var rec record.Record = &record.ObjectActivateRecord{SideEffectRecord: sideEffect}
+		if len(states) > 0 {
+			rec = &record.ObjectAmendRecord{SideEffectRecord: sideEffect, PrevState: *states[len(states)-1]}
+		}
+		id, err := db.SetRecord(ctx, jetID, pn, rec)
+		require.NoError(t, err)
+		states = append(states, id)
+	}
+	objRef := genRandomRef(0)
+	require.NoError(t, db.SetObjectIndex(ctx, jetID, objRef.Record(), &index.ObjectLifeline{
+		LatestState:         states[2],
+		LatestStateApproved: states[1],
+	}))
+
+	t.Run("approval is carried across chunks", func(t *testing.T) {
+		am.getHistoryChunkSize = 1
+		history, err := am.GetObjectHistory(ctx, *objRef, nil, 0)
+		require.NoError(t, err)
+		require.Len(t, history, 3)
+		assert.Equal(t, *states[0], history[2].State)
+		assert.Equal(t, []bool{false, true, true}, []bool{history[0].Approved, history[1].Approved, history[2].Approved})
+	})
+
+	t.Run("skipped states are not counted", func(t *testing.T) {
+		rep, err := am.DefaultBus.Send(ctx, &message.GetObjectHistory{
+			Head:      *objRef,
+			FromPulse: &pulses[0],
+			Amount:    1,
+		}, nil)
+		require.NoError(t, err)
+		history, ok := rep.(*reply.ObjectHistory)
+		require.True(t, ok)
+		require.Len(t, history.States, 1)
+		assert.Equal(t, *states[1], history.States[0].State)
+		assert.Equal(t, states[0], history.NextFrom)
+		assert.True(t, history.NextApproved)
+	})
+}
+
+func TestLedgerArtifactManager_GetPrototypeObjects(t *testing.T) {
+	t.Parallel()
+	ctx := inslogger.TestContext(t)
+	mc := minimock.NewController(t)
+	defer mc.Finish()
+	am := NewArtifactManger(nil)
+	mb := testutils.NewMessageBusMock(mc)
+
+	prototype := genRandomRef(0)
+	from := genRandomID(0)
+	next := genRandomID(0)
+	refs := []core.RecordRef{*genRandomRef(0), *genRandomRef(0)}
+	mb.SendFunc = func(c context.Context, m core.Message, o *core.MessageSendOptions) (core.Reply, error) {
+		msg, ok := m.(*message.GetPrototypeObjects)
+		require.True(t, ok)
+		assert.Equal(t, *prototype, msg.Prototype)
+		assert.Equal(t, core.DynamicRoleHeavyExecutor, msg.DefaultRole())
+		if msg.CountOnly {
+			return &reply.PrototypeObjects{Count: 42}, nil
+		}
+		assert.Equal(t, from, msg.FromObject)
+		assert.Equal(t, 2, msg.Amount)
+		return &reply.PrototypeObjects{Refs: refs, NextFrom: next, Count: len(refs)}, nil
+	}
+	am.DefaultBus = mb
+
+	objects, nextFrom, err := am.GetPrototypeObjects(ctx, *prototype, from, 2)
+	require.NoError(t, err)
+	assert.Equal(t, refs, objects)
+	assert.Equal(t, next, nextFrom)
+
+	count, err := am.CountPrototypeObjects(ctx, *prototype)
+	require.NoError(t, err)
+	assert.Equal(t, 42, count)
+}
+
+func TestLedgerArtifactManager_GetRecordProof(t *testing.T) {
+	t.Parallel()
+	ctx := inslogger.TestContext(t)
+	mc := minimock.NewController(t)
+	defer mc.Finish()
+	am := NewArtifactManger(nil)
+	mb := testutils.NewMessageBusMock(mc)
+
+	id := genRandomID(0)
+	proof := core.RecordProof{
+		Record:   []byte{1, 2, 3},
+		ID:       *id,
+		Path:     []core.MerkleProofStep{{Hash: []byte{4, 5, 6}, Left: true}},
+		DropHash: []byte{7, 8, 9},
+	}
+	mb.SendFunc = func(c context.Context, m core.Message, o *core.MessageSendOptions) (core.Reply, error) {
+		msg, ok := m.(*message.GetRecordProof)
+		require.True(t, ok)
+		assert.Equal(t, *id, msg.Record)
+		assert.Equal(t, core.DynamicRoleHeavyExecutor, msg.DefaultRole())
+		return &reply.RecordProof{Proof: proof}, nil
+	}
+	am.DefaultBus = mb
+
+	got, err := am.GetRecordProof(ctx, *id)
+	require.NoError(t, err)
+	assert.Equal(t, proof, *got)
+}
+
 func TestLedgerArtifactManager_HandleJetDrop(t *testing.T) {
 	t.Skip("jet drops are for validation and it doesn't work")
 
@@ -771,6 +972,7 @@ func TestLedgerArtifactManager_RegisterValidation(t *testing.T) {
 		db:                         db,
 		DefaultBus:                 mb,
 		getChildrenChunkSize:       100,
+		getHistoryChunkSize:        100,
 		PlatformCryptographyScheme: scheme,
 		PulseStorage:               amPulseStorageMock,
 	}
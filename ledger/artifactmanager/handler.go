@@ -145,6 +145,12 @@ func (h *MessageHandler) setHandlersForLight(m *middleware) {
 			m.checkJet,
 			m.checkEarlyRequestBreaker))
 
+	h.Bus.MustRegister(core.TypeGetObjectHistory,
+		Build(h.handleGetObjectHistory,
+			instrumentHandler("handleGetObjectHistory"),
+			m.checkJet,
+			m.checkEarlyRequestBreaker))
+
 	h.Bus.MustRegister(core.TypeSetRecord,
 		Build(h.handleSetRecord,
 			instrumentHandler("handleSetRecord"),
@@ -209,6 +215,7 @@ func (h *MessageHandler) setReplayHandlers(m *middleware) {
 	h.replayHandlers[core.TypeGetObject] = Build(h.handleGetObject, m.checkJet)
 	h.replayHandlers[core.TypeGetDelegate] = Build(h.handleGetDelegate, m.checkJet)
 	h.replayHandlers[core.TypeGetChildren] = Build(h.handleGetChildren, m.checkJet)
+	h.replayHandlers[core.TypeGetObjectHistory] = Build(h.handleGetObjectHistory, m.checkJet)
 	h.replayHandlers[core.TypeSetRecord] = Build(h.handleSetRecord, m.checkJet)
 	h.replayHandlers[core.TypeUpdateObject] = Build(h.handleUpdateObject, m.checkJet)
 	h.replayHandlers[core.TypeRegisterChild] = Build(h.handleRegisterChild, m.checkJet)
@@ -254,10 +261,22 @@ func (h *MessageHandler) setHandlersForHeavy(m *middleware) {
 			instrumentHandler("handleGetChildren"),
 			m.zeroJetForHeavy))
 
+	h.Bus.MustRegister(core.TypeGetObjectHistory,
+		Build(h.handleGetObjectHistory,
+			instrumentHandler("handleGetObjectHistory"),
+			m.zeroJetForHeavy))
+
 	h.Bus.MustRegister(core.TypeGetObjectIndex,
 		Build(h.handleGetObjectIndex,
 			instrumentHandler("handleGetObjectIndex"),
 			m.zeroJetForHeavy))
+
+	h.Bus.MustRegister(core.TypeGetPrototypeObjects,
+		Build(h.handleGetPrototypeObjects,
+			instrumentHandler("handleGetPrototypeObjects")))
+	h.Bus.MustRegister(core.TypeGetRecordProof,
+		Build(h.handleGetRecordProof,
+			instrumentHandler("handleGetRecordProof")))
 }
 
 // ResetEarlyRequestCircuitBreaker throws timeouts at the end of a pulse
@@ -705,6 +724,158 @@ func (h *MessageHandler) handleGetChildren(
 	return &reply.Children{Refs: refs, NextFrom: nil}, nil
 }
 
+func (h *MessageHandler) handleGetObjectHistory(
+	ctx context.Context, parcel core.Parcel,
+) (core.Reply, error) {
+	logger := inslogger.FromContext(ctx)
+	logger.Debug("CALL handleGetObjectHistory")
+
+	msg := parcel.Message().(*message.GetObjectHistory)
+	jetID := jetFromContext(ctx)
+
+	idx, err := h.db.GetObjectIndex(ctx, jetID, msg.Head.Record(), false)
+	if err == storage.ErrNotFound {
+		if h.isHeavy {
+			return nil, fmt.Errorf("failed to fetch index for %v", msg.Head.Record())
+		}
+
+		heavy, err := h.JetCoordinator.Heavy(ctx, parcel.Pulse())
+		if err != nil {
+			return nil, err
+		}
+		idx, err = h.saveIndexFromHeavy(ctx, h.db, jetID, msg.Head, heavy)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to fetch index from heavy")
+		}
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch object index")
+	} else {
+		if !h.isHeavy {
+			h.RecentStorageProvider.GetStorage(ctx, jetID).AddObject(ctx, *msg.Head.Record())
+		}
+	}
+
+	// Counting from specified state or the latest.
+	currentState := idx.LatestState
+	if msg.FromState != nil {
+		currentState = msg.FromState
+	}
+
+	// The object has no states.
+	if currentState == nil {
+		return &reply.ObjectHistory{}, nil
+	}
+
+	var stateJet *core.RecordID
+	if h.isHeavy {
+		stateJet = &jetID
+	} else {
+		var actual bool
+		onHeavy, err := h.isBeyondLimit(ctx, parcel.Pulse(), currentState.Pulse())
+		if err != nil {
+			return nil, err
+		}
+		if onHeavy {
+			node, err := h.JetCoordinator.Heavy(ctx, parcel.Pulse())
+			if err != nil {
+				return nil, err
+			}
+			return reply.NewGetObjectHistoryRedirect(h.DelegationTokenFactory, parcel, node, *currentState)
+		}
+
+		stateTree, err := h.db.GetJetTree(ctx, currentState.Pulse())
+		if err != nil {
+			return nil, err
+		}
+		stateJet, actual = stateTree.Find(*msg.Head.Record())
+		if !actual {
+			actualJet, err := h.fetchActualJetFromOtherNodes(ctx, *msg.Head.Record(), currentState.Pulse())
+			if err != nil {
+				return nil, err
+			}
+			stateJet = actualJet
+		}
+	}
+
+	// Try to fetch the first state.
+	_, err = h.db.GetRecord(ctx, *stateJet, currentState)
+	if err == storage.ErrNotFound {
+		if h.isHeavy {
+			return nil, fmt.Errorf("failed to fetch state for %v. jet: %v, state: %v", msg.Head.Record(), stateJet.DebugString(), currentState.DebugString())
+		}
+		node, err := h.nodeForJet(ctx, *stateJet, parcel.Pulse(), currentState.Pulse())
+		if err != nil {
+			return nil, err
+		}
+		return reply.NewGetObjectHistoryRedirect(h.DelegationTokenFactory, parcel, node, *currentState)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch state")
+	}
+
+	var states []core.ObjectHistoryState
+	// Approval is carried from the previous chunk, the latest approved state could be returned there.
+	approved := msg.FromApproved
+	for currentState != nil {
+		// We have enough results.
+		if len(states) >= msg.Amount {
+			return &reply.ObjectHistory{States: states, NextFrom: currentState, NextApproved: approved}, nil
+		}
+
+		rec, err := h.db.GetRecord(ctx, *stateJet, currentState)
+		// We don't have this state. Return what was collected.
+		if err == storage.ErrNotFound {
+			// Heavy has nothing to redirect to, older states were pruned by retention policy.
+			if h.isHeavy {
+				return &reply.ObjectHistory{States: states, NextFrom: nil}, nil
+			}
+			return &reply.ObjectHistory{States: states, NextFrom: currentState, NextApproved: approved}, nil
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to fetch state")
+		}
+		state, ok := rec.(record.ObjectState)
+		if !ok {
+			return nil, errors.New("invalid object record")
+		}
+
+		// All states preceding the latest approved one are approved too.
+		if idx.LatestStateApproved != nil {
+			approved = approved ||
+				currentState.Equal(idx.LatestStateApproved) ||
+				currentState.Pulse() < idx.LatestStateApproved.Pulse()
+		}
+
+		stateID := *currentState
+		currentState = state.PrevStateID()
+
+		// Skip states later than specified pulse.
+		if msg.FromPulse != nil && stateID.Pulse() > *msg.FromPulse {
+			continue
+		}
+
+		historyState := core.ObjectHistoryState{
+			State:       stateID,
+			Pulse:       stateID.Pulse(),
+			Request:     stateRequest(rec),
+			Prototype:   state.GetImage(),
+			IsPrototype: state.GetIsPrototype(),
+			Approved:    approved,
+			Deactivated: state.State() == record.StateDeactivation,
+		}
+		if state.GetMemory() != nil {
+			historyState.Memory, err = h.db.GetBlob(ctx, *stateJet, state.GetMemory())
+			// Memory of deactivated objects could be compacted on heavy.
+			if err != nil && err != storage.ErrNotFound {
+				return nil, errors.Wrap(err, "failed to fetch blob")
+			}
+		}
+		states = append(states, historyState)
+	}
+
+	return &reply.ObjectHistory{States: states, NextFrom: nil}, nil
+}
+
 func (h *MessageHandler) handleUpdateObject(ctx context.Context, parcel core.Parcel) (core.Reply, error) {
 	logger := inslogger.FromContext(ctx)
 
@@ -985,6 +1156,35 @@ func (h *MessageHandler) handleGetObjectIndex(ctx context.Context, parcel core.P
 	return &reply.ObjectIndex{Index: buf}, nil
 }
 
+func (h *MessageHandler) handleGetPrototypeObjects(ctx context.Context, parcel core.Parcel) (core.Reply, error) {
+	msg := parcel.Message().(*message.GetPrototypeObjects)
+	prototype := *msg.Prototype.Record()
+
+	if msg.CountOnly {
+		count, err := h.db.CountPrototypeObjects(ctx, prototype)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to count prototype objects")
+		}
+		return &reply.PrototypeObjects{Count: count}, nil
+	}
+
+	refs, next, err := h.db.GetPrototypeObjects(ctx, prototype, msg.FromObject, msg.Amount)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch prototype objects")
+	}
+	return &reply.PrototypeObjects{Refs: refs, NextFrom: next, Count: len(refs)}, nil
+}
+
+func (h *MessageHandler) handleGetRecordProof(ctx context.Context, parcel core.Parcel) (core.Reply, error) {
+	msg := parcel.Message().(*message.GetRecordProof)
+
+	proof, err := h.db.GetRecordProof(ctx, msg.Record)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build record proof")
+	}
+	return &reply.RecordProof{Proof: *proof}, nil
+}
+
 func (h *MessageHandler) handleValidationCheck(ctx context.Context, parcel core.Parcel) (core.Reply, error) {
 	msg := parcel.Message().(*message.ValidationCheck)
 	jetID := jetFromContext(ctx)
@@ -1034,6 +1234,19 @@ func validateState(old record.State, new record.State) error {
 	return nil
 }
 
+// stateRequest returns request reference that produced provided state record.
+func stateRequest(rec record.Record) core.RecordRef {
+	switch r := rec.(type) {
+	case *record.ObjectActivateRecord:
+		return r.Request
+	case *record.ObjectAmendRecord:
+		return r.Request
+	case *record.DeactivationRecord:
+		return r.Request
+	}
+	return core.RecordRef{}
+}
+
 func (h *MessageHandler) saveIndexFromHeavy(
 	ctx context.Context, s storage.Store, jetID core.RecordID, obj core.RecordRef, heavy *core.RecordRef,
 ) (*index.ObjectLifeline, error) {
@@ -1087,6 +1300,13 @@ func (h *MessageHandler) handleHotRecords(ctx context.Context, parcel core.Parce
 	if err != nil {
 		return nil, errors.Wrap(err, "[ handleHotRecords ] Can't SetDropSizeHistory")
 	}
+	if msg.DropJet != jetID {
+		// Jet was split or merged, so it starts with an empty history.
+		err = h.db.SetDropSizeHistory(ctx, jetID, jet.DropSizeHistory{})
+		if err != nil {
+			return nil, errors.Wrap(err, "[ handleHotRecords ] Can't SetDropSizeHistory")
+		}
+	}
 
 	logger.WithFields(map[string]interface{}{
 		"len": len(msg.RecentObjects),
@@ -34,14 +34,18 @@ func (h *MessageHandler) handleHeavyPayload(ctx context.Context, genericMsg core
 
 	inslog := inslogger.FromContext(ctx).WithField("pulseNum", msg.PulseNum)
 	inslog = inslog.WithField("jetID", msg.JetID)
+	if err := msg.DecompressRecords(); err != nil {
+		return nil, err
+	}
 	inslog.Debugf("Heavy sync: get payload message with %v records", len(msg.Records))
 
-	if err := h.HeavySync.Store(ctx, msg.JetID, msg.PulseNum, msg.Records); err != nil {
+	stored, err := h.HeavySync.Store(ctx, msg.JetID, msg.PulseNum, msg.From, msg.To, msg.Records)
+	if err != nil {
 		inslog.Error("Heavy store failed ", err)
 		return heavyerrreply(err)
 	}
 	inslog.Debugf("Heavy sync: stores %v records", len(msg.Records))
-	return &reply.OK{}, nil
+	return &reply.HeavyAck{JetID: msg.JetID, PulseNum: msg.PulseNum, Cursor: stored}, nil
 }
 
 func (h *MessageHandler) handleHeavyStartStop(ctx context.Context, genericMsg core.Parcel) (core.Reply, error) {
@@ -56,12 +60,21 @@ func (h *MessageHandler) handleHeavyStartStop(ctx context.Context, genericMsg co
 		inslog.Debug("Heavy sync: get stop message")
 
 		if err := h.HeavySync.Stop(ctx, msg.JetID, msg.PulseNum); err != nil {
-			return nil, err
+			return heavyerrreply(err)
 		}
 		return &reply.OK{}, nil
 	}
 	// start
 
+	if msg.Resume {
+		inslog.Debug("Heavy sync: get resume message")
+		stored, err := h.HeavySync.Resume(ctx, msg.JetID, msg.PulseNum)
+		if err != nil {
+			return heavyerrreply(err)
+		}
+		return &reply.HeavyAck{JetID: msg.JetID, PulseNum: msg.PulseNum, Cursor: stored}, nil
+	}
+
 	inslog.Debug("Heavy sync: get start message")
 	if err := h.HeavySync.Start(ctx, msg.JetID, msg.PulseNum); err != nil {
 		return heavyerrreply(err)
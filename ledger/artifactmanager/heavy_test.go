@@ -20,8 +20,8 @@ import (
 	"context"
 	"testing"
 
-	"github.com/dgraph-io/badger"
 	"github.com/insolar/insolar/ledger/recentstorage"
+	"github.com/insolar/insolar/ledger/storage"
 	"github.com/insolar/insolar/testutils"
 
 	"github.com/insolar/insolar/core"
@@ -39,8 +39,8 @@ func TestLedgerArtifactManager_handleHeavy(t *testing.T) {
 	// prepare mock
 	heavysync := testutils.NewHeavySyncMock(t)
 	heavysync.StartMock.Return(nil)
-	heavysync.StoreMock.Set(func(ctx context.Context, jetID core.RecordID, pn core.PulseNumber, kvs []core.KV) error {
-		return db.StoreKeyValues(ctx, kvs)
+	heavysync.StoreMock.Set(func(ctx context.Context, jetID core.RecordID, pn core.PulseNumber, from, to []byte, kvs []core.KV) ([]byte, error) {
+		return to, db.StoreKeyValues(ctx, kvs)
 	})
 	heavysync.StopMock.Return(nil)
 
@@ -81,14 +81,9 @@ func TestLedgerArtifactManager_handleHeavy(t *testing.T) {
 	_, err = mh.handleHeavyPayload(ctx, parcel)
 	require.NoError(t, err)
 
-	badgerdb := db.GetBadgerDB()
-	err = badgerdb.View(func(tx *badger.Txn) error {
+	err = db.GetBackend().View(func(tx storage.BackendTxn) error {
 		for _, kv := range payload {
-			item, err := tx.Get(kv.K)
-			if !assert.NoError(t, err) {
-				continue
-			}
-			value, err := item.Value()
+			value, err := tx.Get(kv.K)
 			if !assert.NoError(t, err) {
 				continue
 			}
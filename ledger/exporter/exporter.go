@@ -22,6 +22,7 @@ import (
 	"math"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/insolar/insolar/configuration"
 	"github.com/insolar/insolar/core"
@@ -39,11 +40,15 @@ type Exporter struct {
 	db  *storage.DB
 	ps  *storage.PulseStorage
 	cfg configuration.Exporter
+
+	// states caches objects and prototypes of object states for streaming export.
+	states     map[core.RecordID]stateInfo
+	statesLock sync.Mutex
 }
 
 // NewExporter creates new StorageExporter instance.
 func NewExporter(db *storage.DB, ps *storage.PulseStorage, cfg configuration.Exporter) *Exporter {
-	return &Exporter{db: db, ps: ps, cfg: cfg}
+	return &Exporter{db: db, ps: ps, cfg: cfg, states: map[core.RecordID]stateInfo{}}
 }
 
 type payload map[string]interface{}
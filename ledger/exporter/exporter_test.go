@@ -28,6 +28,7 @@ import (
 	"github.com/insolar/insolar/ledger/storage"
 	"github.com/insolar/insolar/ledger/storage/record"
 	"github.com/insolar/insolar/ledger/storage/storagetest"
+	"github.com/insolar/insolar/testutils"
 	base58 "github.com/jbenet/go-base58"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -116,3 +117,159 @@ func TestExporter_Export(t *testing.T) {
 		assert.Equal(t, core.TypeCallConstructor.String(), request.Payload["Type"])
 	}
 }
+
+func TestExporter_ExportStream(t *testing.T) {
+	ctx := inslogger.TestContext(t)
+	db, clean := storagetest.TmpDB(ctx, t)
+	defer clean()
+	jetID := core.TODOJetID
+	ps := storage.NewPulseStorage(db)
+	var exporter core.StorageStreamExporter = NewExporter(db, ps, configuration.Exporter{ExportLag: 0})
+
+	for i := 1; i <= 3; i++ {
+		err := db.AddPulse(
+			ctx,
+			core.Pulse{
+				PulseNumber:     core.FirstPulseNumber + 10*core.PulseNumber(i),
+				PrevPulseNumber: core.FirstPulseNumber + 10*core.PulseNumber(i-1),
+				PulseTimestamp:  10 * int64(i+1),
+			},
+		)
+		require.NoError(t, err)
+	}
+	pulse := core.PulseNumber(core.FirstPulseNumber + 10)
+
+	mem := make([]byte, 0)
+	codec.NewEncoderBytes(&mem, &codec.CborHandle{}).MustEncode(map[string]string{"Field": "objectValue"})
+	blobID, err := db.SetBlob(ctx, jetID, pulse, mem)
+	require.NoError(t, err)
+
+	prototype := testutils.RandomRef()
+	requestID, err := db.SetRecord(ctx, jetID, pulse, &record.RequestRecord{
+		Payload: message.ToBytes(&message.CallConstructor{PrototypeRef: prototype, Name: "New"}),
+	})
+	require.NoError(t, err)
+	objectRef := core.NewRecordRef(core.DomainID, *requestID)
+	activateID, err := db.SetRecord(ctx, jetID, pulse, &record.ObjectActivateRecord{
+		SideEffectRecord:  record.SideEffectRecord{Request: *objectRef},
+		ObjectStateRecord: record.ObjectStateRecord{Memory: blobID, Image: prototype},
+	})
+	require.NoError(t, err)
+	amendID, err := db.SetRecord(ctx, jetID, pulse, &record.ObjectAmendRecord{
+		ObjectStateRecord: record.ObjectStateRecord{Image: prototype},
+		PrevState:         *activateID,
+	})
+	require.NoError(t, err)
+	_, err = db.SetRecord(ctx, jetID, pulse, &record.ObjectActivateRecord{
+		SideEffectRecord:  record.SideEffectRecord{Request: testutils.RandomRef()},
+		ObjectStateRecord: record.ObjectStateRecord{Image: testutils.RandomRef()},
+	})
+	require.NoError(t, err)
+
+	export := func(cursor string, filter core.StorageExportFilter, limit int) ([]*core.StorageExportRecord, string) {
+		var records []*core.StorageExportRecord
+		next, err := exporter.ExportStream(ctx, cursor, filter, limit, func(r *core.StorageExportRecord) error {
+			records = append(records, r)
+			return nil
+		})
+		require.NoError(t, err)
+		return records, next
+	}
+
+	all, end := export("", core.StorageExportFilter{}, 0)
+	// genesis record and records of the first exportable pulse
+	require.Len(t, all, 5)
+	assert.Equal(t, core.StorageExportTypeGenesis, all[0].Type)
+	for _, r := range all {
+		assert.Equal(t, core.StorageExportSchemaVersion, r.SchemaVersion)
+		_, err := json.Marshal(r)
+		assert.NoError(t, err)
+	}
+
+	records, next := export(end, core.StorageExportFilter{}, 0)
+	assert.Empty(t, records)
+	assert.Equal(t, end, next)
+
+	records, _ = export("", core.StorageExportFilter{
+		Types:      []string{core.StorageExportTypeActivate, core.StorageExportTypeAmend},
+		Prototypes: []core.RecordRef{prototype},
+	}, 0)
+	require.Len(t, records, 2)
+	byID := map[string]*core.StorageExportRecord{}
+	for _, r := range records {
+		byID[r.ID] = r
+		assert.Equal(t, requestID.String(), r.Object)
+		assert.Equal(t, prototype.String(), r.Prototype)
+		assert.Equal(t, pulse, r.Pulse)
+		assert.Equal(t, int64(20), r.PulseTimestamp)
+	}
+	if assert.Contains(t, byID, activateID.String()) {
+		assert.Equal(t, core.StorageExportTypeActivate, byID[activateID.String()].Type)
+		assert.Contains(t, string(byID[activateID.String()].Memory), "objectValue")
+	}
+	if assert.Contains(t, byID, amendID.String()) {
+		assert.Equal(t, activateID.String(), byID[amendID.String()].PrevState)
+	}
+
+	records, _ = export("", core.StorageExportFilter{Objects: []core.RecordID{*requestID}}, 0)
+	require.Len(t, records, 3)
+	for _, r := range records {
+		if r.Type == core.StorageExportTypeRequest {
+			require.NotNil(t, r.Call)
+			assert.Equal(t, "New", r.Call.Method)
+			assert.Equal(t, prototype.String(), r.Call.Prototype)
+		}
+	}
+
+	var resumed []*core.StorageExportRecord
+	cursor := ""
+	for i := 0; i < len(all)+1; i++ {
+		records, cursor = export(cursor, core.StorageExportFilter{}, 2)
+		resumed = append(resumed, records...)
+	}
+	assert.Equal(t, all, resumed)
+	assert.Equal(t, end, cursor)
+
+	_, err = exporter.ExportStream(ctx, "bad cursor", core.StorageExportFilter{}, 0, nil)
+	assert.Error(t, err)
+}
+
+func TestExporter_ExportStream_NothingToExport(t *testing.T) {
+	ctx := inslogger.TestContext(t)
+	db, clean := storagetest.TmpDB(ctx, t)
+	defer clean()
+	ps := storage.NewPulseStorage(db)
+	exporter := NewExporter(db, ps, configuration.Exporter{ExportLag: 0})
+
+	err := db.AddPulse(ctx, core.Pulse{
+		PulseNumber:     core.FirstPulseNumber + 10,
+		PrevPulseNumber: core.FirstPulseNumber,
+	})
+	require.NoError(t, err)
+
+	export := func(cursor string) ([]*core.StorageExportRecord, string) {
+		var records []*core.StorageExportRecord
+		next, err := exporter.ExportStream(ctx, cursor, core.StorageExportFilter{}, 0, func(r *core.StorageExportRecord) error {
+			records = append(records, r)
+			return nil
+		})
+		require.NoError(t, err)
+		return records, next
+	}
+
+	// Genesis pulse is not exportable yet, so there is no position to resume from.
+	records, next := export("")
+	assert.Empty(t, records)
+	assert.Empty(t, next)
+
+	current := core.Pulse{
+		PulseNumber:     core.FirstPulseNumber + 20,
+		PrevPulseNumber: core.FirstPulseNumber + 10,
+	}
+	require.NoError(t, db.AddPulse(ctx, current))
+	ps.Set(&current)
+	records, next = export(next)
+	require.NotEmpty(t, records)
+	assert.Equal(t, core.StorageExportTypeGenesis, records[0].Type)
+	assert.NotEmpty(t, next)
+}
@@ -0,0 +1,530 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"sort"
+
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/core/message"
+	"github.com/insolar/insolar/ledger/storage"
+	"github.com/insolar/insolar/ledger/storage/jet"
+	"github.com/insolar/insolar/ledger/storage/record"
+	base58 "github.com/jbenet/go-base58"
+	"github.com/pkg/errors"
+	"github.com/ugorji/go/codec"
+)
+
+const cursorVersion = 1
+
+// stateCacheSize limits number of object states which object and prototype are cached.
+const stateCacheSize = 1 << 16
+
+var errStopExport = errors.New("export limit is reached")
+
+// cursor is a position of streaming export. Empty jet means pulse is fully exported.
+type cursor struct {
+	pulse core.PulseNumber
+	jet   core.RecordID
+	id    core.RecordID
+}
+
+func (c cursor) String() string {
+	buf := make([]byte, 0, 1+core.PulseNumberSize+2*core.RecordIDSize)
+	buf = append(buf, cursorVersion)
+	buf = append(buf, c.pulse.Bytes()...)
+	buf = append(buf, c.jet[:]...)
+	buf = append(buf, c.id[:]...)
+	return base58.Encode(buf)
+}
+
+func (c cursor) pulseDone() bool {
+	return c.jet == core.RecordID{}
+}
+
+func parseCursor(s string) (*cursor, error) {
+	if s == "" {
+		return nil, nil
+	}
+	buf := base58.Decode(s)
+	if len(buf) != 1+core.PulseNumberSize+2*core.RecordIDSize || buf[0] != cursorVersion {
+		return nil, errors.New("malformed cursor")
+	}
+	buf = buf[1:]
+	c := cursor{pulse: core.NewPulseNumber(buf[:core.PulseNumberSize])}
+	buf = buf[core.PulseNumberSize:]
+	copy(c.jet[:], buf[:core.RecordIDSize])
+	copy(c.id[:], buf[core.RecordIDSize:])
+	return &c, nil
+}
+
+type exportFilter struct {
+	types      map[string]bool
+	objects    map[core.RecordID]bool
+	prototypes map[core.RecordRef]bool
+	jets       map[core.RecordID]bool
+}
+
+func newExportFilter(f core.StorageExportFilter) exportFilter {
+	ef := exportFilter{}
+	if len(f.Types) > 0 {
+		ef.types = map[string]bool{}
+		for _, t := range f.Types {
+			ef.types[t] = true
+		}
+	}
+	if len(f.Objects) > 0 {
+		ef.objects = map[core.RecordID]bool{}
+		for _, o := range f.Objects {
+			ef.objects[o] = true
+		}
+	}
+	if len(f.Prototypes) > 0 {
+		ef.prototypes = map[core.RecordRef]bool{}
+		for _, p := range f.Prototypes {
+			ef.prototypes[p] = true
+		}
+	}
+	if len(f.Jets) > 0 {
+		ef.jets = map[core.RecordID]bool{}
+		for _, j := range f.Jets {
+			ef.jets[j] = true
+		}
+	}
+	return ef
+}
+
+// stateInfo is an object and its prototype the object state belongs to.
+type stateInfo struct {
+	object    core.RecordID
+	prototype *core.RecordRef
+}
+
+type exportedRecord struct {
+	info *stateInfo
+	rec  *core.StorageExportRecord
+}
+
+func (f exportFilter) match(r exportedRecord) bool {
+	if f.objects != nil && (r.info == nil || !f.objects[r.info.object]) {
+		return false
+	}
+	if f.prototypes != nil && (r.info == nil || r.info.prototype == nil || !f.prototypes[*r.info.prototype]) {
+		return false
+	}
+	return true
+}
+
+// ExportStream implements core.StorageStreamExporter.
+func (e *Exporter) ExportStream(
+	ctx context.Context,
+	cursorStr string,
+	filter core.StorageExportFilter,
+	limit int,
+	handler func(*core.StorageExportRecord) error,
+) (string, error) {
+	pos, err := parseCursor(cursorStr)
+	if err != nil {
+		return "", errors.Wrap(err, "[ ExportStream ]")
+	}
+	currentPulse, err := e.ps.Current(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, "[ ExportStream ] failed to get current pulse data")
+	}
+	jetSet, err := e.db.GetJets(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, "[ ExportStream ] failed to get jets")
+	}
+	jets := make([]core.RecordID, 0, len(jetSet))
+	for jetID := range jetSet {
+		jets = append(jets, jetID)
+	}
+	sort.Slice(jets, func(i, j int) bool {
+		return bytes.Compare(jets[i][:], jets[j][:]) < 0
+	})
+
+	var pulse *storage.Pulse
+	// Cursor with empty jet means the pulse is exported, so fresh export has no cursor until the first pulse
+	// is processed.
+	fresh := pos == nil
+	if fresh {
+		pos = &cursor{pulse: core.GenesisPulse.PulseNumber}
+		pulse, err = e.db.GetPulse(ctx, pos.pulse)
+	} else {
+		pulse, err = e.db.GetPulse(ctx, pos.pulse)
+		if err == nil && pos.pulseDone() {
+			if pulse.Next == nil {
+				return pos.String(), nil
+			}
+			pulse, err = e.db.GetPulse(ctx, *pulse.Next)
+		}
+	}
+	if err != nil {
+		return "", errors.Wrap(err, "[ ExportStream ] failed to fetch pulse data")
+	}
+
+	ef := newExportFilter(filter)
+	sent := 0
+	for {
+		// Same as Export, pulses are exported with lag, so all their data is persisted.
+		if pulse.Pulse.PulseNumber >= (currentPulse.PrevPulseNumber - core.PulseNumber(e.cfg.ExportLag)) {
+			break
+		}
+		fresh = false
+
+		for _, jetID := range jets {
+			if pos.pulse == pulse.Pulse.PulseNumber && !pos.pulseDone() && bytes.Compare(jetID[:], pos.jet[:]) < 0 {
+				continue
+			}
+			if ef.jets != nil && !ef.jets[jetID] {
+				continue
+			}
+			err := e.exportJet(ctx, jetID, &pulse.Pulse, pos, ef, func(r *core.StorageExportRecord) error {
+				if err := handler(r); err != nil {
+					return err
+				}
+				sent++
+				if limit > 0 && sent >= limit {
+					return errStopExport
+				}
+				return nil
+			})
+			if err == errStopExport {
+				return pos.String(), nil
+			}
+			if err != nil {
+				return "", err
+			}
+		}
+
+		*pos = cursor{pulse: pulse.Pulse.PulseNumber}
+		if pulse.Next == nil {
+			break
+		}
+		pulse, err = e.db.GetPulse(ctx, *pulse.Next)
+		if err != nil {
+			return "", errors.Wrap(err, "[ ExportStream ] failed to fetch pulse data")
+		}
+	}
+	if fresh {
+		return "", nil
+	}
+	return pos.String(), nil
+}
+
+// exportJet exports records of jet on pulse after pos and moves pos to every processed record.
+func (e *Exporter) exportJet(
+	ctx context.Context,
+	jetID core.RecordID,
+	pulse *core.Pulse,
+	pos *cursor,
+	ef exportFilter,
+	handler func(*core.StorageExportRecord) error,
+) error {
+	skipTill := pos.pulse == pulse.PulseNumber && pos.jet == jetID
+
+	type idRecord struct {
+		id  core.RecordID
+		rec record.Record
+	}
+	// Records are collected first, since exporting them requires storage reads.
+	var records []idRecord
+	err := e.db.IterateRecordsOnPulse(ctx, jetID, pulse.PulseNumber, func(id core.RecordID, rec record.Record) error {
+		if skipTill && bytes.Compare(id[:], pos.id[:]) <= 0 {
+			return nil
+		}
+		records = append(records, idRecord{id: id, rec: rec})
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "[ ExportStream ] failed to iterate records")
+	}
+
+	for _, r := range records {
+		*pos = cursor{pulse: pulse.PulseNumber, jet: jetID, id: r.id}
+		recType := exportType(r.rec)
+		if ef.types != nil && !ef.types[recType] {
+			continue
+		}
+		exported, err := e.exportRecord(ctx, jetID, r.id, r.rec)
+		if err != nil {
+			return errors.Wrapf(err, "[ ExportStream ] failed to export record %s", r.id.String())
+		}
+		if !ef.match(exported) {
+			continue
+		}
+
+		exported.rec.SchemaVersion = core.StorageExportSchemaVersion
+		exported.rec.Cursor = pos.String()
+		exported.rec.Type = recType
+		exported.rec.Pulse = pulse.PulseNumber
+		exported.rec.PulseTimestamp = pulse.PulseTimestamp
+		exported.rec.JetID = jetID.String()
+		exported.rec.ID = r.id.String()
+		if err := handler(exported.rec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func exportType(rec record.Record) string {
+	switch rec.(type) {
+	case *record.GenesisRecord:
+		return core.StorageExportTypeGenesis
+	case *record.ChildRecord:
+		return core.StorageExportTypeChild
+	case *record.JetRecord:
+		return core.StorageExportTypeJet
+	case *record.RequestRecord:
+		return core.StorageExportTypeRequest
+	case *record.ResultRecord:
+		return core.StorageExportTypeResult
+	case *record.TypeRecord:
+		return core.StorageExportTypeType
+	case *record.CodeRecord:
+		return core.StorageExportTypeCode
+	case *record.ObjectActivateRecord:
+		return core.StorageExportTypeActivate
+	case *record.ObjectAmendRecord:
+		return core.StorageExportTypeAmend
+	case *record.DeactivationRecord:
+		return core.StorageExportTypeDeactivate
+	}
+	return rec.Type().String()
+}
+
+func refString(ref core.RecordRef) string {
+	if ref.IsEmpty() {
+		return ""
+	}
+	return ref.String()
+}
+
+func (e *Exporter) exportRecord(
+	ctx context.Context, jetID core.RecordID, id core.RecordID, rec record.Record,
+) (exportedRecord, error) {
+	res := exportedRecord{rec: &core.StorageExportRecord{}}
+	out := res.rec
+
+	switch r := rec.(type) {
+	case *record.ChildRecord:
+		out.Child = refString(r.Ref)
+	case *record.RequestRecord:
+		e.exportRequest(id, r, &res)
+	case *record.ResultRecord:
+		res.info = &stateInfo{object: r.Object}
+		out.Request = refString(r.Request)
+		out.Payload = r.Payload
+	case *record.TypeRecord:
+		out.Domain = refString(r.Domain)
+		out.Request = refString(r.Request)
+		out.Payload = r.TypeDeclaration
+	case *record.CodeRecord:
+		out.Domain = refString(r.Domain)
+		out.Request = refString(r.Request)
+	case *record.ObjectActivateRecord:
+		info := stateInfo{object: *r.Request.Record()}
+		if !r.IsPrototype {
+			info.prototype = &r.Image
+		}
+		e.cacheState(id, info)
+		res.info = &info
+		out.Domain = refString(r.Domain)
+		out.Request = refString(r.Request)
+		out.Parent = refString(r.Parent)
+		out.IsDelegate = r.IsDelegate
+		e.exportImage(&r.ObjectStateRecord, out)
+		if err := e.exportMemory(ctx, jetID, r.Memory, out); err != nil {
+			return res, err
+		}
+	case *record.ObjectAmendRecord:
+		info, err := e.resolveState(ctx, jetID, r.PrevState)
+		if err != nil {
+			return res, err
+		}
+		if info != nil {
+			amended := *info
+			if !r.IsPrototype {
+				amended.prototype = &r.Image
+			}
+			e.cacheState(id, amended)
+			res.info = &amended
+		}
+		out.Domain = refString(r.Domain)
+		out.Request = refString(r.Request)
+		out.PrevState = r.PrevState.String()
+		e.exportImage(&r.ObjectStateRecord, out)
+		if err := e.exportMemory(ctx, jetID, r.Memory, out); err != nil {
+			return res, err
+		}
+	case *record.DeactivationRecord:
+		info, err := e.resolveState(ctx, jetID, r.PrevState)
+		if err != nil {
+			return res, err
+		}
+		res.info = info
+		out.Domain = refString(r.Domain)
+		out.Request = refString(r.Request)
+		out.PrevState = r.PrevState.String()
+	}
+
+	if res.info != nil {
+		out.Object = res.info.object.String()
+		if res.info.prototype != nil {
+			out.Prototype = refString(*res.info.prototype)
+		}
+	}
+	return res, nil
+}
+
+func (e *Exporter) exportImage(state *record.ObjectStateRecord, out *core.StorageExportRecord) {
+	out.IsPrototype = state.IsPrototype
+	if state.IsPrototype {
+		out.Code = refString(state.Image)
+	}
+}
+
+func (e *Exporter) exportRequest(id core.RecordID, r *record.RequestRecord, res *exportedRecord) {
+	if r.Object != (core.RecordID{}) {
+		res.info = &stateInfo{object: r.Object}
+	}
+	if r.Payload == nil {
+		return
+	}
+	msg, err := message.Deserialize(bytes.NewBuffer(r.Payload))
+	if err != nil {
+		res.rec.Payload = r.Payload
+		return
+	}
+
+	call := &core.StorageExportCall{MessageType: msg.Type().String()}
+	switch m := msg.(type) {
+	case *message.CallMethod:
+		call.Caller = refString(m.Caller)
+		call.Object = m.ObjectRef.Record().String()
+		call.Prototype = refString(m.ProxyPrototype)
+		call.Method = m.Method
+		call.Arguments = m.Arguments
+		res.info = &stateInfo{object: *m.ObjectRef.Record()}
+		if !m.ProxyPrototype.IsEmpty() {
+			res.info.prototype = &m.ProxyPrototype
+		}
+	case *message.CallConstructor:
+		// Object created by constructor is referenced by its request.
+		call.Caller = refString(m.Caller)
+		call.Object = id.String()
+		call.Prototype = refString(m.PrototypeRef)
+		call.Method = m.Name
+		call.Arguments = m.Arguments
+		res.info = &stateInfo{object: id, prototype: &m.PrototypeRef}
+	}
+	res.rec.Call = call
+}
+
+func (e *Exporter) exportMemory(ctx context.Context, jetID core.RecordID, memory *core.RecordID, out *core.StorageExportRecord) error {
+	if memory == nil {
+		return nil
+	}
+	blob, err := e.db.GetBlob(ctx, jetID, memory)
+	if err == storage.ErrNotFound {
+		// Blob could be already removed from light node.
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to get memory")
+	}
+
+	var decoded interface{}
+	var converted []byte
+	err = codec.NewDecoderBytes(blob, &codec.CborHandle{}).Decode(&decoded)
+	if err == nil {
+		err = codec.NewEncoderBytes(&converted, &codec.JsonHandle{}).Encode(decoded)
+	}
+	if err != nil {
+		out.MemoryBinary = blob
+		return nil
+	}
+	out.Memory = converted
+	return nil
+}
+
+func (e *Exporter) cacheState(id core.RecordID, info stateInfo) {
+	e.statesLock.Lock()
+	defer e.statesLock.Unlock()
+	if len(e.states) >= stateCacheSize {
+		e.states = map[core.RecordID]stateInfo{}
+	}
+	e.states[id] = info
+}
+
+// resolveState finds object and prototype of object state walking back to object activation.
+// Nil is returned if chain of states is not available in storage.
+func (e *Exporter) resolveState(ctx context.Context, jetID core.RecordID, id core.RecordID) (*stateInfo, error) {
+	e.statesLock.Lock()
+	info, ok := e.states[id]
+	e.statesLock.Unlock()
+	if ok {
+		return &info, nil
+	}
+
+	rec, err := e.getRecord(ctx, jetID, id)
+	if err == storage.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch r := rec.(type) {
+	case *record.ObjectActivateRecord:
+		info = stateInfo{object: *r.Request.Record()}
+		if !r.IsPrototype {
+			info.prototype = &r.Image
+		}
+	case *record.ObjectAmendRecord:
+		prev, err := e.resolveState(ctx, jetID, r.PrevState)
+		if err != nil || prev == nil {
+			return nil, err
+		}
+		info = *prev
+		if !r.IsPrototype {
+			info.prototype = &r.Image
+		}
+	default:
+		return nil, nil
+	}
+	e.cacheState(id, info)
+	return &info, nil
+}
+
+// getRecord looks for record in jet and its parents, since record could be saved before jet split.
+func (e *Exporter) getRecord(ctx context.Context, jetID core.RecordID, id core.RecordID) (record.Record, error) {
+	for {
+		rec, err := e.db.GetRecord(ctx, jetID, &id)
+		if err != storage.ErrNotFound {
+			return rec, err
+		}
+		parent := jet.Parent(jetID)
+		if parent == jetID {
+			return nil, storage.ErrNotFound
+		}
+		jetID = parent
+	}
+}
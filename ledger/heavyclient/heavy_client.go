@@ -37,6 +37,10 @@ type Options struct {
 	SyncMessageLimit int
 	PulsesDeltaLimit int
 	BackoffConf      configuration.Backoff
+	// Parallelism is a number of pulses synced concurrently.
+	Parallelism int
+	// Compression enables compression of synced records.
+	Compression bool
 }
 
 // JetClient heavy replication client. Replicates records for one jet.
@@ -60,6 +64,8 @@ type JetClient struct {
 	muPulses    sync.Mutex
 	leftPulses  []core.PulseNumber
 	syncbackoff *backoff.Backoff
+	// finishing are pulses which payload is sent, but heavy waits sync of previous pulses to finish them
+	finishing map[core.PulseNumber]struct{}
 }
 
 // NewJetClient heavy replication client constructor.
@@ -72,6 +78,7 @@ func NewJetClient(jetID core.RecordID, opts Options) *JetClient {
 		signal:      make(chan struct{}, 1),
 		syncdone:    make(chan struct{}),
 		opts:        opts,
+		finishing:   map[core.PulseNumber]struct{}{},
 	}
 	return jsc
 }
@@ -110,20 +117,19 @@ func (c *JetClient) pulsesLeft() int {
 	return len(c.leftPulses)
 }
 
-// unshiftPulse removes and returns pulse number from head of processing queue.
-func (c *JetClient) unshiftPulse(ctx context.Context) *core.PulseNumber {
+// removePulse removes pulse number from processing queue.
+func (c *JetClient) removePulse(ctx context.Context, pn core.PulseNumber) {
 	c.muPulses.Lock()
 	defer c.muPulses.Unlock()
 
-	if len(c.leftPulses) == 0 {
-		return nil
+	for i, left := range c.leftPulses {
+		if left != pn {
+			continue
+		}
+		c.leftPulses = append(c.leftPulses[:i], c.leftPulses[i+1:]...)
+		break
 	}
-	result := c.leftPulses[0]
-
-	// shift array elements on one position to left
-	shifted := c.leftPulses[:len(c.leftPulses)-1]
-	copy(shifted, c.leftPulses[1:])
-	c.leftPulses = shifted
+	delete(c.finishing, pn)
 
 	if err := c.db.SetSyncClientJetPulses(ctx, c.jetID, c.leftPulses); err != nil {
 		inslogger.FromContext(ctx).Errorf(
@@ -131,17 +137,36 @@ func (c *JetClient) unshiftPulse(ctx context.Context) *core.PulseNumber {
 	}
 
 	c.updateLeftPulsesMetrics(ctx)
-	return &result
 }
 
-func (c *JetClient) nextPulseNumber() (core.PulseNumber, bool) {
+func (c *JetClient) isFinishing(pn core.PulseNumber) bool {
 	c.muPulses.Lock()
 	defer c.muPulses.Unlock()
+	_, ok := c.finishing[pn]
+	return ok
+}
 
-	if len(c.leftPulses) == 0 {
-		return 0, false
+func (c *JetClient) setFinishing(pn core.PulseNumber, finishing bool) {
+	c.muPulses.Lock()
+	defer c.muPulses.Unlock()
+	if finishing {
+		c.finishing[pn] = struct{}{}
+	} else {
+		delete(c.finishing, pn)
 	}
-	return c.leftPulses[0], true
+}
+
+// nextPulseNumbers returns up to limit pulse numbers from head of processing queue.
+func (c *JetClient) nextPulseNumbers(limit int) []core.PulseNumber {
+	c.muPulses.Lock()
+	defer c.muPulses.Unlock()
+
+	if limit > len(c.leftPulses) {
+		limit = len(c.leftPulses)
+	}
+	pns := make([]core.PulseNumber, limit)
+	copy(pns, c.leftPulses)
+	return pns
 }
 
 func (c *JetClient) runOnce(ctx context.Context) {
@@ -160,15 +185,13 @@ func (c *JetClient) syncloop(ctx context.Context) {
 	defer close(c.syncdone)
 
 	var (
-		syncPN     core.PulseNumber
-		hasNext    bool
+		syncPNs    []core.PulseNumber
 		retrydelay time.Duration
 	)
 
-	finishpulse := func() {
-		_ = c.unshiftPulse(ctx)
-		c.syncbackoff.Reset()
-		retrydelay = 0
+	parallelism := c.opts.Parallelism
+	if parallelism < 1 {
+		parallelism = 1
 	}
 
 	for {
@@ -185,9 +208,9 @@ func (c *JetClient) syncloop(ctx context.Context) {
 
 		for {
 			// if we have pulses to sync, process it
-			syncPN, hasNext = c.nextPulseNumber()
-			if hasNext {
-				inslog.Debugf("synchronization next sync pulse num: %v (left=%v)", syncPN, c.leftPulses)
+			syncPNs = c.nextPulseNumbers(parallelism)
+			if len(syncPNs) > 0 {
+				inslog.Debugf("synchronization next sync pulse nums: %v (left=%v)", syncPNs, c.pulsesLeft())
 				break
 			}
 
@@ -199,40 +222,58 @@ func (c *JetClient) syncloop(ctx context.Context) {
 			}
 		}
 
-		if isPulseNumberOutdated(ctx, c.db, c.PulseStorage, syncPN, c.opts.PulsesDeltaLimit) {
-			inslog.Infof("pulse %v on jet %v is outdated, skip it", syncPN, c.jetID)
-			finishpulse()
+		var pns []core.PulseNumber
+		for _, pn := range syncPNs {
+			if isPulseNumberOutdated(ctx, c.db, c.PulseStorage, pn, c.opts.PulsesDeltaLimit) {
+				inslog.Infof("pulse %v on jet %v is outdated, skip it", pn, c.jetID)
+				c.removePulse(ctx, pn)
+				continue
+			}
+			pns = append(pns, pn)
+		}
+		if len(pns) == 0 {
+			c.syncbackoff.Reset()
+			retrydelay = 0
 			continue
 		}
 
-		inslog.Infof("start synchronization to heavy for pulse %v", syncPN)
+		inslog.Infof("start synchronization to heavy for pulses %v", pns)
 
 		shouldretry := false
-		isretry := c.syncbackoff.Attempt() > 0
-
-		syncerr := c.HeavySync(ctx, syncPN, isretry)
-		if syncerr != nil {
-			if heavyerr, ok := syncerr.(*reply.HeavyError); ok {
-				shouldretry = heavyerr.IsRetryable()
+		for i, syncerr := range c.syncPulses(ctx, pns) {
+			pn := pns[i]
+			if syncerr != nil {
+				retryable := false
+				if heavyerr, ok := syncerr.(*reply.HeavyError); ok {
+					retryable = heavyerr.IsRetryable()
+				}
+
+				syncerr = errors.Wrapf(syncerr, "HeavySync failed for pulse %v", pn)
+				inslog.Errorf("%v (on attempt=%v, shouldretry=%v)",
+					syncerr.Error(), c.syncbackoff.Attempt(), retryable)
+
+				if retryable {
+					shouldretry = true
+					continue
+				}
+				// heavy shouldn't wait for the pulse anymore
+				c.resetSync(ctx, pn)
+				// TODO: write some info to dust - 14.Dec.2018 @nordicdyno
+			} else {
+				ctx := insmetrics.InsertTag(ctx, tagJet, c.jetID.DebugString())
+				stats.Record(ctx,
+					statSyncedPulsesCount.M(1),
+				)
 			}
-
-			syncerr = errors.Wrap(syncerr, "HeavySync failed")
-			inslog.Errorf("%v (on attempt=%v, shouldretry=%v)",
-				syncerr.Error(), c.syncbackoff.Attempt(), shouldretry)
-
-			if shouldretry {
-				retrydelay = c.syncbackoff.Duration()
-				continue
-			}
-			// TODO: write some info to dust - 14.Dec.2018 @nordicdyno
-		} else {
-			ctx = insmetrics.InsertTag(ctx, tagJet, c.jetID.DebugString())
-			stats.Record(ctx,
-				statSyncedPulsesCount.M(1),
-			)
+			c.removePulse(ctx, pn)
 		}
 
-		finishpulse()
+		if shouldretry {
+			retrydelay = c.syncbackoff.Duration()
+			continue
+		}
+		c.syncbackoff.Reset()
+		retrydelay = 0
 	}
 
 }
@@ -26,7 +26,6 @@ import (
 	"testing"
 	"time"
 
-	"github.com/dgraph-io/badger"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -47,14 +46,18 @@ import (
 )
 
 func TestPulseManager_SendToHeavyHappyPath(t *testing.T) {
-	sendToHeavy(t, false)
+	sendToHeavy(t, false, 1, false)
 }
 
 func TestPulseManager_SendToHeavyWithRetry(t *testing.T) {
-	sendToHeavy(t, true)
+	sendToHeavy(t, true, 1, false)
 }
 
-func sendToHeavy(t *testing.T, withretry bool) {
+func TestPulseManager_SendToHeavyParallelCompressed(t *testing.T) {
+	sendToHeavy(t, true, 3, true)
+}
+
+func sendToHeavy(t *testing.T, withretry bool, parallelism int, compression bool) {
 	ctx := inslogger.TestContext(t)
 	db, cleaner := storagetest.TmpDB(ctx, t)
 	defer cleaner()
@@ -125,8 +128,15 @@ func sendToHeavy(t *testing.T, withretry bool) {
 	}
 	syncmessagesPerMessage := map[int32]*messageStat{}
 	var bussendfailed int32
+	// acked holds cursors of stored payload chunks by pulse
+	acked := map[core.PulseNumber][]byte{}
 	busMock.SendFunc = func(ctx context.Context, msg core.Message, ops *core.MessageSendOptions) (core.Reply, error) {
 		// fmt.Printf("got msg: %T (%s)\n", msg, msg.Type())
+		if startmsg, ok := msg.(*message.HeavyStartStop); ok && startmsg.Resume {
+			statMutex.Lock()
+			defer statMutex.Unlock()
+			return &reply.HeavyAck{Cursor: acked[startmsg.PulseNum]}, nil
+		}
 		heavymsg, ok := msg.(*message.HeavyPayload)
 		if ok {
 			if withretry && atomic.AddInt32(&bussendfailed, 1) < 2 {
@@ -135,6 +145,9 @@ func sendToHeavy(t *testing.T, withretry bool) {
 					Message: "retryable error",
 				}, nil
 			}
+			require.Equal(t, compression, len(heavymsg.CompressedRecords) > 0)
+			err := heavymsg.DecompressRecords()
+			require.NoError(t, err)
 
 			syncsendedNewVal := atomic.AddInt32(&syncsended, 1)
 			var size int
@@ -146,12 +159,15 @@ func sendToHeavy(t *testing.T, withretry bool) {
 			}
 
 			statMutex.Lock()
+			defer statMutex.Unlock()
+			require.Equal(t, acked[heavymsg.PulseNum], heavymsg.From, "payload chunk follows acknowledged one")
+			acked[heavymsg.PulseNum] = heavymsg.To
 			synckeys = append(synckeys, keys...)
 			syncmessagesPerMessage[syncsendedNewVal] = &messageStat{
 				size: size,
 				keys: keys,
 			}
-			statMutex.Unlock()
+			return &reply.HeavyAck{PulseNum: heavymsg.PulseNum, Cursor: heavymsg.To}, nil
 		}
 		return nil, nil
 	}
@@ -162,6 +178,8 @@ func sendToHeavy(t *testing.T, withretry bool) {
 	pmconf := configuration.PulseManager{
 		HeavySyncEnabled:      true,
 		HeavySyncMessageLimit: 2 * kb,
+		HeavySyncParallelism:  parallelism,
+		HeavySyncCompression:  compression,
 		HeavyBackoff: configuration.Backoff{
 			Jitter: true,
 			Min:    minretry,
@@ -241,7 +259,7 @@ func sendToHeavy(t *testing.T, withretry bool) {
 
 	synckeys = uniqkeys(sortkeys(synckeys))
 
-	recs := getallkeys(db.GetBadgerDB())
+	recs := getallkeys(db.GetBackend())
 	recs = filterkeys(recs, func(k key) bool {
 		return storage.Key(k).PulseNumber() != 0
 	})
@@ -296,6 +314,7 @@ var (
 	scopeIDRecord   = byte(2)
 	scopeIDJetDrop  = byte(3)
 	scopeIDBlob     = byte(7)
+	scopeIDDropSign = byte(11)
 )
 
 type key []byte
@@ -304,26 +323,26 @@ func (k key) String() string {
 	return storage.Key(k).String()
 }
 
-func getallkeys(db *badger.DB) (records []key) {
-	txn := db.NewTransaction(true)
-	defer txn.Discard()
-
-	it := txn.NewIterator(badger.DefaultIteratorOptions)
-	defer it.Close()
-	for it.Rewind(); it.Valid(); it.Next() {
-		item := it.Item()
-		k := item.KeyCopy(nil)
-		if storage.Key(k).PulseNumber() == 0 {
-			continue
-		}
-		switch k[0] {
-		case
-			scopeIDRecord,
-			scopeIDJetDrop,
-			scopeIDLifeline,
-			scopeIDBlob:
-			records = append(records, k)
-		}
+func getallkeys(db storage.Backend) (records []key) {
+	err := db.View(func(txn storage.BackendTxn) error {
+		return txn.Iterate(nil, nil, func(k, _ []byte) (bool, error) {
+			if storage.Key(k).PulseNumber() == 0 {
+				return true, nil
+			}
+			switch k[0] {
+			case
+				scopeIDRecord,
+				scopeIDJetDrop,
+				scopeIDLifeline,
+				scopeIDBlob,
+				scopeIDDropSign:
+				records = append(records, k)
+			}
+			return true, nil
+		})
+	})
+	if err != nil {
+		panic(err)
 	}
 	return
 }
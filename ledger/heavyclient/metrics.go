@@ -33,6 +33,7 @@ var (
 	statFirstUnsyncedPulse  = stats.Int64("heavyclient/unsynced/firstpulse", "First unsynced pulse number", stats.UnitDimensionless)
 
 	statSyncedPulsesCount = stats.Int64("heavyclient/synced/count", "How many pulses unsynced", stats.UnitDimensionless)
+	statResumedPulses     = stats.Int64("heavyclient/resumed/pulses", "How many pulse syncs resumed after chunks stored by heavy", stats.UnitDimensionless)
 )
 
 func init() {
@@ -59,6 +60,13 @@ func init() {
 			Aggregation: view.Count(),
 			TagKeys:     commontags,
 		},
+		&view.View{
+			Name:        statResumedPulses.Name(),
+			Description: statResumedPulses.Description(),
+			Measure:     statResumedPulses,
+			Aggregation: view.Count(),
+			TagKeys:     commontags,
+		},
 	)
 	if err != nil {
 		panic(err)
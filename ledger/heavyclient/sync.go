@@ -18,66 +18,137 @@ package heavyclient
 
 import (
 	"context"
+	"sync"
 
 	"github.com/insolar/insolar/core"
 	"github.com/insolar/insolar/core/message"
 	"github.com/insolar/insolar/core/reply"
 	"github.com/insolar/insolar/instrumentation/inslogger"
+	"github.com/insolar/insolar/instrumentation/insmetrics"
 	"github.com/insolar/insolar/ledger/storage"
+	"go.opencensus.io/stats"
 )
 
-func messageToHeavy(ctx context.Context, bus core.MessageBus, msg core.Message) error {
+func messageToHeavy(ctx context.Context, bus core.MessageBus, msg core.Message) (core.Reply, error) {
 	busreply, buserr := bus.Send(ctx, msg, nil)
 	if buserr != nil {
-		return buserr
+		return nil, buserr
 	}
 	if busreply != nil {
 		herr, ok := busreply.(*reply.HeavyError)
 		if ok {
-			return herr
+			return nil, herr
 		}
 	}
+	return busreply, nil
+}
+
+// ackedCursor returns cursor of the last payload chunk acknowledged by heavy.
+func ackedCursor(rep core.Reply) []byte {
+	if ack, ok := rep.(*reply.HeavyAck); ok {
+		return ack.Cursor
+	}
 	return nil
 }
 
 // HeavySync syncs records from light to heavy node, returns last synced pulse and error.
 //
-// It syncs records from start to end of provided pulse numbers.
+// It syncs records from start to end of provided pulse numbers. Interrupted sync is resumed
+// from the cursor of the last payload chunk acknowledged by heavy.
 func (c *JetClient) HeavySync(
 	ctx context.Context,
 	pn core.PulseNumber,
-	retry bool,
 ) error {
-	jetID := c.jetID
-	inslog := inslogger.FromContext(ctx)
-	inslog = inslog.WithField("jetID", jetID.DebugString())
-	inslog = inslog.WithField("pulseNum", pn)
+	cursor, err := c.startSync(ctx, pn)
+	if err != nil {
+		return err
+	}
+	return c.transferSync(ctx, pn, cursor)
+}
 
-	inslog.Debug("JetClient.HeavySync")
-	if retry {
-		inslog.Info("synchronize: send reset message (retry sync)")
-		resetMsg := &message.HeavyReset{
-			JetID:    jetID,
-			PulseNum: pn,
+// syncPulses syncs provided pulses concurrently, returns sync error for each of them.
+//
+// Heavy requires pulses to be started in order, so only payloads are sent concurrently.
+// If pulse could not be started, following pulses are not synced and are not included into result.
+// Pulses which payload is already sent are only finished.
+func (c *JetClient) syncPulses(ctx context.Context, pns []core.PulseNumber) []error {
+	var cursors [][]byte
+	var errs []error
+	for _, pn := range pns {
+		if c.isFinishing(pn) {
+			cursors = append(cursors, nil)
+			errs = append(errs, nil)
+			continue
 		}
-		if err := messageToHeavy(ctx, c.Bus, resetMsg); err != nil {
-			inslog.Error("synchronize: reset failed")
-			return err
+		cursor, err := c.startSync(ctx, pn)
+		if err != nil {
+			errs = append(errs, err)
+			break
 		}
+		cursors = append(cursors, cursor)
+		errs = append(errs, nil)
 	}
 
+	var wg sync.WaitGroup
+	wg.Add(len(cursors))
+	for i := range cursors {
+		i := i
+		go func() {
+			defer wg.Done()
+			if c.isFinishing(pns[i]) {
+				errs[i] = c.finishSync(ctx, pns[i])
+				return
+			}
+			errs[i] = c.transferSync(ctx, pns[i], cursors[i])
+		}()
+	}
+	wg.Wait()
+	return errs
+}
+
+// startSync starts or resumes pulse sync, returns cursor of the last payload chunk stored by heavy.
+//
+// Heavy starts sync if it has no state of the pulse, so resume is safe on the first attempt too.
+func (c *JetClient) startSync(ctx context.Context, pn core.PulseNumber) ([]byte, error) {
+	inslog := inslogger.FromContext(ctx)
+	inslog = inslog.WithField("jetID", c.jetID.DebugString())
+	inslog = inslog.WithField("pulseNum", pn)
+
+	inslog.Debug("JetClient.HeavySync")
 	signalMsg := &message.HeavyStartStop{
-		JetID:    jetID,
+		JetID:    c.jetID,
 		PulseNum: pn,
+		Resume:   true,
 	}
-	if err := messageToHeavy(ctx, c.Bus, signalMsg); err != nil {
+	rep, err := messageToHeavy(ctx, c.Bus, signalMsg)
+	if err != nil {
 		inslog.Error("synchronize: start failed")
-		return err
+		return nil, err
+	}
+	cursor := ackedCursor(rep)
+	if cursor != nil {
+		inslog.Infof("synchronize: resume after key %x", cursor)
 	}
 	inslog.Debug("synchronize: sucessfully send start message")
+	return cursor, nil
+}
+
+// transferSync sends payload chunks following the cursor and finishes pulse sync.
+func (c *JetClient) transferSync(ctx context.Context, pn core.PulseNumber, cursor []byte) error {
+	jetID := c.jetID
+	inslog := inslogger.FromContext(ctx)
+	inslog = inslog.WithField("jetID", jetID.DebugString())
+	inslog = inslog.WithField("pulseNum", pn)
 
 	replicator := storage.NewReplicaIter(
 		ctx, c.db, jetID, pn, pn+1, c.opts.SyncMessageLimit)
+	if cursor != nil {
+		if err := replicator.Skip(cursor); err != nil {
+			return err
+		}
+		// already stored by heavy
+		stats.Record(insmetrics.InsertTag(ctx, tagJet, jetID.DebugString()), statResumedPulses.M(1))
+	}
 	for {
 		recs, err := replicator.NextRecords()
 		if err == storage.ErrReplicatorDone {
@@ -90,22 +161,62 @@ func (c *JetClient) HeavySync(
 			JetID:    jetID,
 			PulseNum: pn,
 			Records:  recs,
+			From:     cursor,
+			To:       replicator.LastKey(),
+		}
+		if c.opts.Compression {
+			if err := msg.CompressRecords(); err != nil {
+				return err
+			}
 		}
-		if err := messageToHeavy(ctx, c.Bus, msg); err != nil {
+		rep, err := messageToHeavy(ctx, c.Bus, msg)
+		if err != nil {
 			inslog.Error("synchronize: payload failed")
 			return err
 		}
+		cursor = ackedCursor(rep)
 		inslog.Debug("synchronize: sucessfully send save message")
 	}
 
-	signalMsg.Finished = true
-	if err := messageToHeavy(ctx, c.Bus, signalMsg); err != nil {
-		inslog.Error("synchronize: finish failed")
+	c.setFinishing(pn, true)
+	if err := c.finishSync(ctx, pn); err != nil {
 		return err
 	}
-	inslog.Debug("synchronize: sucessfully send finish message")
 
 	lastMeetPulse := replicator.LastSeenPulse()
 	inslog.Debugf("synchronize: finished (maximum pulse of saved messages is %v)", lastMeetPulse)
 	return nil
 }
+
+// finishSync sends finish message for pulse which payload is sent.
+//
+// Heavy marks pulses as synced in order, so it replies with retryable error until previous pulses are synced.
+// On other errors payload is sent again on retry.
+func (c *JetClient) finishSync(ctx context.Context, pn core.PulseNumber) error {
+	signalMsg := &message.HeavyStartStop{
+		JetID:    c.jetID,
+		PulseNum: pn,
+		Finished: true,
+	}
+	_, err := messageToHeavy(ctx, c.Bus, signalMsg)
+	if err != nil {
+		if herr, ok := err.(*reply.HeavyError); !ok || herr.SubType != reply.ErrHeavySyncInProgress {
+			c.setFinishing(pn, false)
+		}
+		inslogger.FromContext(ctx).Errorf("synchronize: finish failed: jetID=%v, pulse=%v", c.jetID, pn)
+		return err
+	}
+	inslogger.FromContext(ctx).Debugf("synchronize: sucessfully send finish message: jetID=%v, pulse=%v", c.jetID, pn)
+	return nil
+}
+
+// resetSync resets pulse sync on heavy, so heavy doesn't wait for the pulse anymore.
+func (c *JetClient) resetSync(ctx context.Context, pn core.PulseNumber) {
+	resetMsg := &message.HeavyReset{
+		JetID:    c.jetID,
+		PulseNum: pn,
+	}
+	if _, err := messageToHeavy(ctx, c.Bus, resetMsg); err != nil {
+		inslogger.FromContext(ctx).Errorf("synchronize: reset failed: jetID=%v, pulse=%v: %v", c.jetID, pn, err)
+	}
+}
@@ -17,18 +17,22 @@
 package heavyserver
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/pkg/errors"
 	"go.opencensus.io/stats"
 
+	"github.com/insolar/insolar/configuration"
 	"github.com/insolar/insolar/core"
 	"github.com/insolar/insolar/core/reply"
 	"github.com/insolar/insolar/instrumentation/inslogger"
 	"github.com/insolar/insolar/instrumentation/insmetrics"
 	"github.com/insolar/insolar/ledger/storage"
+	"github.com/insolar/insolar/ledger/storage/jet"
 )
 
 func errSyncInProgress(jetID core.RecordID, pn core.PulseNumber) *reply.HeavyError {
@@ -40,27 +44,69 @@ func errSyncInProgress(jetID core.RecordID, pn core.PulseNumber) *reply.HeavyErr
 	}
 }
 
+func errSyncOutOfOrder(jetID core.RecordID, pn core.PulseNumber, msg string) *reply.HeavyError {
+	return &reply.HeavyError{
+		Message:  msg,
+		SubType:  reply.ErrHeavySyncOutOfOrder,
+		JetID:    jetID,
+		PulseNum: pn,
+	}
+}
+
+// chunk is a payload chunk received for pulse which is not the first one in sync yet.
+type chunk struct {
+	cursor []byte
+	kvs    []core.KV
+}
+
+// pulsestate is a state of one pulse sync.
+type pulsestate struct {
+	// cursor is a cursor of the last received payload chunk.
+	cursor []byte
+	// buffered are received payload chunks waiting for sync of previous pulses.
+	buffered []chunk
+	insync   bool
+	finished bool
+}
+
 // in testnet we start with only one jet
 type syncstate struct {
 	sync.Mutex
 	lastok core.PulseNumber
-	// insyncend core.PulseNumber
-	syncpulse *core.PulseNumber
-	insync    bool
+	// pulses are pulses in sync ordered by pulse number, they are stored and marked as synced in this order.
+	pulses []core.PulseNumber
+	states map[core.PulseNumber]*pulsestate
 }
 
 // Sync provides methods for syncing records to heavy storage.
+//
+// Synced jet drops are checked against their records and signed by heavy, so record proofs are confirmed
+// by both light executor which built the drop and heavy executor.
 type Sync struct {
-	db *storage.DB
+	CryptographyService core.CryptographyService `inject:""`
+	NodeNet             core.NodeNetwork         `inject:""`
+
+	db     *storage.DB
+	pruner *Pruner
+	// parallel is a max number of pulses in sync for one jet.
+	parallel int
 
 	sync.Mutex
 	jetSyncStates map[core.RecordID]*syncstate
 }
 
 // NewSync creates new Sync instance.
-func NewSync(db *storage.DB) *Sync {
+//
+// Synced jets are passed to pruner, it could be nil if pruning is not required.
+func NewSync(db *storage.DB, conf configuration.Ledger, pruner *Pruner) *Sync {
+	parallel := conf.PulseManager.HeavySyncParallelism
+	if parallel < 1 {
+		parallel = 1
+	}
 	return &Sync{
 		db:            db,
+		pruner:        pruner,
+		parallel:      parallel,
 		jetSyncStates: map[core.RecordID]*syncstate{},
 	}
 }
@@ -96,7 +142,9 @@ func (s *Sync) getJetSyncState(ctx context.Context, jetID core.RecordID) *syncst
 	s.Lock()
 	jetState, ok := s.jetSyncStates[jetID]
 	if !ok {
-		jetState = &syncstate{}
+		jetState = &syncstate{
+			states: map[core.PulseNumber]*pulsestate{},
+		}
 		s.jetSyncStates[jetID] = jetState
 	}
 	s.Unlock()
@@ -104,17 +152,27 @@ func (s *Sync) getJetSyncState(ctx context.Context, jetID core.RecordID) *syncst
 }
 
 // Start try to start heavy sync for provided pulse.
+//
+// Several pulses of one jet could be in sync, but they should be started in order.
 func (s *Sync) Start(ctx context.Context, jetID core.RecordID, pn core.PulseNumber) error {
 	jetState := s.getJetSyncState(ctx, jetID)
 	jetState.Lock()
 	defer jetState.Unlock()
 
-	if jetState.syncpulse != nil {
-		if *jetState.syncpulse >= pn {
+	return s.start(ctx, jetID, jetState, pn)
+}
+
+// should be called under jet state lock
+func (s *Sync) start(ctx context.Context, jetID core.RecordID, jetState *syncstate, pn core.PulseNumber) error {
+	if len(jetState.pulses) > 0 {
+		last := jetState.pulses[len(jetState.pulses)-1]
+		if last >= pn {
 			return fmt.Errorf("heavyserver: pulse %v is not greater than current in-sync pulse %v (jet=%v)",
-				pn, *jetState.syncpulse, jetID)
+				pn, last, jetID)
+		}
+		if len(jetState.pulses) >= s.parallel {
+			return errSyncInProgress(jetID, pn)
 		}
-		return errSyncInProgress(jetID, pn)
 	}
 
 	if pn <= core.FirstPulseNumber {
@@ -125,51 +183,135 @@ func (s *Sync) Start(ctx context.Context, jetID core.RecordID, pn core.PulseNumb
 		return err
 	}
 
-	jetState.syncpulse = &pn
+	jetState.pulses = append(jetState.pulses, pn)
+	jetState.states[pn] = &pulsestate{}
 	return nil
 }
 
+// Resume continues interrupted sync of provided pulse, sync is started if heavy has no state of the pulse.
+//
+// Returns cursor of the last payload chunk received for the pulse. If heavy was restarted, cursor of the last
+// stored payload chunk is restored from storage.
+func (s *Sync) Resume(ctx context.Context, jetID core.RecordID, pn core.PulseNumber) ([]byte, error) {
+	jetState := s.getJetSyncState(ctx, jetID)
+	jetState.Lock()
+	defer jetState.Unlock()
+
+	if pulseState, ok := jetState.states[pn]; ok {
+		if pulseState.finished {
+			// pulse waits for sync of previous pulses
+			return nil, errSyncInProgress(jetID, pn)
+		}
+		inslogger.FromContext(ctx).Debugf("heavyserver: Resume sync: jetID=%v, pulse=%v, cursor=%x",
+			jetID, pn, pulseState.cursor)
+		return pulseState.cursor, nil
+	}
+
+	if err := s.start(ctx, jetID, jetState, pn); err != nil {
+		return nil, err
+	}
+	cursor, err := s.db.GetHeavySyncCursor(ctx, jetID, pn)
+	if err != nil {
+		return nil, errors.Wrap(err, "heavyserver: GetHeavySyncCursor failed")
+	}
+	jetState.states[pn].cursor = cursor
+	return cursor, nil
+}
+
 // Store stores recieved key/value pairs at heavy storage.
 //
+// Payload chunks should be received in order: from is a cursor of the previous chunk, to is a cursor of
+// the received one. Already received chunks are acknowledged without storing. Pulses are stored strictly
+// in order, so chunks of the pulse are buffered in memory until sync of all previous pulses is stopped.
+// Returns cursor of the last received payload chunk.
+//
 // TODO: check actual jet and pulse in keys
-func (s *Sync) Store(ctx context.Context, jetID core.RecordID, pn core.PulseNumber, kvs []core.KV) error {
+func (s *Sync) Store(
+	ctx context.Context,
+	jetID core.RecordID,
+	pn core.PulseNumber,
+	from, to []byte,
+	kvs []core.KV,
+) ([]byte, error) {
 	inslog := inslogger.FromContext(ctx)
 	jetState := s.getJetSyncState(ctx, jetID)
 
-	err := func() error {
-		jetState.Lock()
-		defer jetState.Unlock()
-		if jetState.syncpulse == nil {
-			return fmt.Errorf("heavyserver: jet %v not in sync mode", jetID)
-		}
-		if *jetState.syncpulse != pn {
-			return fmt.Errorf("heavyserver: passed pulse %v doesn't match in-sync pulse %v", pn, *jetState.syncpulse)
-		}
-		if jetState.insync {
-			return errSyncInProgress(jetID, pn)
-		}
-		jetState.insync = true
-		return nil
-	}()
+	jetState.Lock()
+	pulseState, ok := jetState.states[pn]
+	if !ok {
+		jetState.Unlock()
+		return nil, errSyncOutOfOrder(jetID, pn, fmt.Sprintf("heavyserver: jet %v not in sync mode for pulse %v", jetID, pn))
+	}
+	if pulseState.finished {
+		jetState.Unlock()
+		return nil, fmt.Errorf("heavyserver: pulse %v sync is already finished (jet=%v)", pn, jetID)
+	}
+	if pulseState.insync {
+		jetState.Unlock()
+		return nil, errSyncInProgress(jetID, pn)
+	}
+	if bytes.Equal(to, pulseState.cursor) {
+		cursor := pulseState.cursor
+		jetState.Unlock()
+		inslog.Debugf("heavyserver: skip received chunk: jetID=%v, pulse=%v, cursor=%x", jetID, pn, to)
+		stats.Record(insmetrics.InsertTag(ctx, tagJet, jetID.DebugString()), statSkippedChunks.M(1))
+		return cursor, nil
+	}
+	if !bytes.Equal(from, pulseState.cursor) {
+		jetState.Unlock()
+		return nil, errSyncOutOfOrder(jetID, pn, fmt.Sprintf(
+			"heavyserver: got payload chunk after %x, expected after %x", from, pulseState.cursor))
+	}
+	if jetState.pulses[0] != pn || len(pulseState.buffered) > 0 {
+		pulseState.buffered = append(pulseState.buffered, chunk{cursor: to, kvs: kvs})
+		pulseState.cursor = to
+		jetState.Unlock()
+		inslog.Debugf("heavyserver: buffer chunk: jetID=%v, pulse=%v, cursor=%x", jetID, pn, to)
+		return to, nil
+	}
+	pulseState.insync = true
+	jetState.Unlock()
+
+	err := s.store(ctx, jetID, pn, to, kvs)
+
+	jetState.Lock()
+	defer jetState.Unlock()
+	pulseState.insync = false
 	if err != nil {
-		return err
+		return nil, err
 	}
+	pulseState.cursor = to
+	return to, nil
+}
 
-	defer func() {
-		jetState.Lock()
-		jetState.insync = false
-		jetState.Unlock()
-	}()
+func (s *Sync) store(ctx context.Context, jetID core.RecordID, pn core.PulseNumber, cursor []byte, kvs []core.KV) error {
 	// TODO: check jet in keys?
+	// changed lifelines are found by comparison with stored ones
+	err := s.pruner.enqueue(ctx, kvs)
+	if err != nil {
+		return errors.Wrapf(err, "heavyserver: prune queue update failed")
+	}
 	err = s.db.StoreKeyValues(ctx, kvs)
 	if err != nil {
 		return errors.Wrapf(err, "heavyserver: store failed")
 	}
+	err = s.db.IndexPrototypeObjects(ctx, kvs)
+	if err != nil {
+		return errors.Wrapf(err, "heavyserver: prototype index update failed")
+	}
+	err = s.db.IndexDropRecords(ctx, jetID, kvs)
+	if err != nil {
+		return errors.Wrapf(err, "heavyserver: drop records index update failed")
+	}
+	err = s.db.SetHeavySyncCursor(ctx, jetID, pn, cursor)
+	if err != nil {
+		return errors.Wrapf(err, "heavyserver: sync cursor update failed")
+	}
 
 	// heavy stats
 	recordsCount := int64(len(kvs))
 	recordsSize := core.KVSize(kvs)
-	inslog.Debugf("heavy store stat: JetID=%v, recordsCount+=%v, recordsSize+=%v\n", jetID.DebugString(), recordsCount, recordsSize)
+	inslogger.FromContext(ctx).Debugf("heavy store stat: JetID=%v, recordsCount+=%v, recordsSize+=%v\n", jetID.DebugString(), recordsCount, recordsSize)
 
 	ctx = insmetrics.InsertTag(ctx, tagJet, jetID.DebugString())
 	stats.Record(ctx,
@@ -183,45 +325,135 @@ func (s *Sync) Store(ctx context.Context, jetID core.RecordID, pn core.PulseNumb
 
 // Stop successfully stops replication for specified pulse.
 //
+// Pulses are marked as synced in order they were started, so the pulse is marked as synced when sync of all
+// previous pulses is stopped. Until then retryable error is returned, so sender doesn't forget the pulse.
+//
 // TODO: call Stop if range sync too long
 func (s *Sync) Stop(ctx context.Context, jetID core.RecordID, pn core.PulseNumber) error {
 	jetState := s.getJetSyncState(ctx, jetID)
 	jetState.Lock()
 	defer jetState.Unlock()
 
-	if jetState.syncpulse == nil {
-		return errors.Errorf("Jet %v not in sync mode", jetID)
+	pulseState, ok := jetState.states[pn]
+	if !ok {
+		synced, err := s.db.GetHeavySyncedPulse(ctx, jetID)
+		if err != nil {
+			return errors.Wrap(err, "heavyserver: GetHeavySyncedPulse failed")
+		}
+		if synced != 0 && pn <= synced {
+			// pulse was synced after previous pulses had been stopped
+			return nil
+		}
+		return errSyncOutOfOrder(jetID, pn, fmt.Sprintf(
+			"heavyserver: Passed pulse %v doesn't match any pulse in sync for jet %v", pn, jetID))
+	}
+	if pulseState.insync {
+		return errSyncInProgress(jetID, pn)
 	}
-	if *jetState.syncpulse != pn {
-		return fmt.Errorf(
-			"heavyserver: Passed pulse %v doesn't match pulse %v current in sync for jet %v",
-			pn, *jetState.syncpulse, jetID)
+	pulseState.finished = true
+
+	if err := s.flush(ctx, jetID, jetState); err != nil {
+		return err
 	}
-	if jetState.insync {
+	if _, ok := jetState.states[pn]; ok {
 		return errSyncInProgress(jetID, pn)
 	}
-	jetState.syncpulse = nil
+	return nil
+}
 
-	err := s.db.SetHeavySyncedPulse(ctx, jetID, pn)
+// flush stores buffered payload chunks of the first pulses in sync and marks finished pulses as synced.
+//
+// Should be called under jet state lock.
+func (s *Sync) flush(ctx context.Context, jetID core.RecordID, jetState *syncstate) error {
+	for len(jetState.pulses) > 0 {
+		next := jetState.pulses[0]
+		pulseState := jetState.states[next]
+		if pulseState.insync {
+			return nil
+		}
+		for len(pulseState.buffered) > 0 {
+			buffered := pulseState.buffered[0]
+			if err := s.store(ctx, jetID, next, buffered.cursor, buffered.kvs); err != nil {
+				return err
+			}
+			pulseState.buffered = pulseState.buffered[1:]
+		}
+		if !pulseState.finished {
+			return nil
+		}
+
+		if err := s.signDrop(ctx, jetID, next); err != nil {
+			// Drop is stored anyway, proofs of its records won't have heavy signature.
+			inslogger.FromContext(ctx).Errorf("heavyserver: drop is not signed: jetID=%v, pulse=%v: %v", jetID, next, err)
+		}
+		err := s.db.SetHeavySyncedPulse(ctx, jetID, next)
+		if err != nil {
+			return err
+		}
+		err = s.db.RemoveHeavySyncCursor(ctx, jetID, next)
+		if err != nil {
+			return err
+		}
+		inslogger.FromContext(ctx).Debugf("heavyserver: Fin sync: jetID=%v, pulse=%v", jetID, next)
+		jetState.lastok = next
+		jetState.pulses = jetState.pulses[1:]
+		delete(jetState.states, next)
+		s.pruner.notify(jetID, next)
+	}
+	return nil
+}
+
+// signDrop checks synced jet drop against synced records and signs it.
+func (s *Sync) signDrop(ctx context.Context, jetID core.RecordID, pn core.PulseNumber) error {
+	drop, err := s.db.GetDrop(ctx, jetID, pn)
+	if err == storage.ErrNotFound {
+		return nil
+	}
 	if err != nil {
+		return errors.Wrap(err, "failed to fetch drop")
+	}
+	if err = s.db.CheckDrop(ctx, jetID, drop); err != nil {
 		return err
 	}
-	inslogger.FromContext(ctx).Debugf("heavyserver: Fin sync: jetID=%v, pulse=%v", jetID, pn)
-	jetState.lastok = pn
-	return nil
+
+	_, jetPrefix := jet.Jet(jetID)
+	signature, err := s.CryptographyService.Sign(
+		jet.DropSignData(s.db.PlatformCryptographyScheme, jetPrefix, pn, drop.Hash),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to sign drop")
+	}
+	return s.db.SetDropSignature(ctx, jetID, pn, core.DropSignature{
+		Node:      s.NodeNet.GetOrigin().ID(),
+		Signature: signature.Bytes(),
+	})
 }
 
-// Reset resets sync for provided pulse.
+// Reset resets sync for provided pulse and pulses started after it.
 func (s *Sync) Reset(ctx context.Context, jetID core.RecordID, pn core.PulseNumber) error {
 	jetState := s.getJetSyncState(ctx, jetID)
 	jetState.Lock()
 	defer jetState.Unlock()
 
-	if jetState.insync {
-		return errSyncInProgress(jetID, pn)
+	if pn <= jetState.lastok {
+		// pulse is already synced, following pulses are still valid
+		return nil
+	}
+	pulses := jetState.pulses
+	idx := sort.Search(len(pulses), func(i int) bool { return pulses[i] >= pn })
+	for _, reset := range pulses[idx:] {
+		if jetState.states[reset].insync {
+			return errSyncInProgress(jetID, pn)
+		}
 	}
 
 	inslogger.FromContext(ctx).Debugf("heavyserver: Reset sync: jetID=%v, pulse=%v", jetID, pn)
-	jetState.syncpulse = nil
+	for _, reset := range pulses[idx:] {
+		delete(jetState.states, reset)
+		if err := s.db.RemoveHeavySyncCursor(ctx, jetID, reset); err != nil {
+			return err
+		}
+	}
+	jetState.pulses = pulses[:idx]
 	return nil
 }
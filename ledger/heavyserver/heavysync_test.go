@@ -17,12 +17,23 @@
 package heavyserver
 
 import (
+	"crypto"
 	"testing"
 
+	"github.com/gojuno/minimock"
+	"github.com/insolar/insolar/configuration"
 	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/core/reply"
+	"github.com/insolar/insolar/cryptography"
 	"github.com/insolar/insolar/instrumentation/inslogger"
+	"github.com/insolar/insolar/ledger/storage"
+	"github.com/insolar/insolar/ledger/storage/jet"
+	"github.com/insolar/insolar/ledger/storage/record"
 	"github.com/insolar/insolar/ledger/storage/storagetest"
+	"github.com/insolar/insolar/platformpolicy"
 	"github.com/insolar/insolar/testutils"
+	"github.com/insolar/insolar/testutils/network"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -40,11 +51,11 @@ func TestHeavy_SyncBasic(t *testing.T) {
 	// TODO: call every case in subtest
 	jetID := testutils.RandomJet()
 
-	sync := NewSync(db)
+	sync := NewSync(db, configuration.Ledger{}, nil)
 	err = sync.Start(ctx, jetID, pnum)
 	require.Error(t, err, "start with zero pulse")
 
-	err = sync.Store(ctx, jetID, pnum, kvalues)
+	_, err = sync.Store(ctx, jetID, pnum, nil, kvalues[0].K, kvalues)
 	require.Error(t, err, "store values on non started sync")
 
 	err = sync.Stop(ctx, jetID, pnum)
@@ -95,24 +106,27 @@ func TestHeavy_SyncBasic(t *testing.T) {
 	err = sync.Start(ctx, jetID, pnumNext)
 	require.NoError(t, err, "start next pulse")
 
-	err = sync.Store(ctx, jetID, pnumNextPlus, kvalues)
+	_, err = sync.Store(ctx, jetID, pnumNextPlus, nil, kvalues[0].K, kvalues)
 	require.Error(t, err, "store from other pulse at the same jet")
 
 	err = sync.Stop(ctx, jetID, pnumNextPlus)
+	require.NoError(t, err, "stop of already synced pulse is acknowledged")
+
+	err = sync.Stop(ctx, jetID, pnumNextPlus+10)
 	require.Error(t, err, "stop from other pulse at the same jet")
 
-	err = sync.Store(ctx, jetID, pnumNext, kvalues)
+	_, err = sync.Store(ctx, jetID, pnumNext, nil, kvalues[0].K, kvalues)
 	require.NoError(t, err, "store on current range")
-	err = sync.Store(ctx, jetID, pnumNext, kvalues)
+	_, err = sync.Store(ctx, jetID, pnumNext, nil, kvalues[0].K, kvalues)
 	require.NoError(t, err, "store the same on current range")
 	err = sync.Stop(ctx, jetID, pnumNext)
 	require.NoError(t, err, "stop current range")
 
 	preparepulse(pnumNextPlus) // should set corret next for previous pulse
-	sync = NewSync(db)
+	sync = NewSync(db, configuration.Ledger{}, nil)
 	err = sync.Start(ctx, jetID, pnumNextPlus)
 	require.NoError(t, err, "start next+1 range on new sync instance (checkpoint check)")
-	err = sync.Store(ctx, jetID, pnumNextPlus, kvalues)
+	_, err = sync.Store(ctx, jetID, pnumNextPlus, nil, kvalues[0].K, kvalues)
 	require.NoError(t, err, "store next+1 pulse")
 	err = sync.Stop(ctx, jetID, pnumNextPlus)
 	require.NoError(t, err, "stop next+1 range on new sync instance")
@@ -147,7 +161,7 @@ func TestHeavy_SyncByJet(t *testing.T) {
 		require.NoError(t, err)
 	}
 
-	sync := NewSync(db)
+	sync := NewSync(db, configuration.Ledger{}, nil)
 
 	pnum = core.FirstPulseNumber + 1
 	pnumNext := pnum + 1
@@ -163,10 +177,10 @@ func TestHeavy_SyncByJet(t *testing.T) {
 	err = sync.Start(ctx, jetID2, pnum)
 	require.NoError(t, err, "start from first+1 pulse on empty storage, jet2")
 
-	err = sync.Store(ctx, jetID2, pnum, kvalues2)
+	_, err = sync.Store(ctx, jetID2, pnum, nil, kvalues2[0].K, kvalues2)
 	require.NoError(t, err, "store jet2 pulse")
 
-	err = sync.Store(ctx, jetID1, pnum, kvalues1)
+	_, err = sync.Store(ctx, jetID1, pnum, nil, kvalues1[0].K, kvalues1)
 	require.NoError(t, err, "store jet1 pulse")
 
 	// stop previous
@@ -174,3 +188,194 @@ func TestHeavy_SyncByJet(t *testing.T) {
 	err = sync.Stop(ctx, jetID2, pnum)
 	require.NoError(t, err)
 }
+
+func TestHeavy_SyncResume(t *testing.T) {
+	ctx := inslogger.TestContext(t)
+	db, cleaner := storagetest.TmpDB(ctx, t)
+	defer cleaner()
+
+	chunk1 := []core.KV{{K: []byte("100"), V: []byte("500")}}
+	chunk2 := []core.KV{{K: []byte("200"), V: []byte("600")}}
+	chunk3 := []core.KV{{K: []byte("300"), V: []byte("700")}}
+	jetID := testutils.RandomJet()
+	pnum := core.PulseNumber(core.FirstPulseNumber + 1)
+
+	sync := NewSync(db, configuration.Ledger{}, nil)
+	cursor, err := sync.Resume(ctx, jetID, pnum)
+	require.NoError(t, err, "resume starts sync if heavy has no sync state")
+	require.Nil(t, cursor)
+
+	cursor, err = sync.Store(ctx, jetID, pnum, nil, chunk1[0].K, chunk1)
+	require.NoError(t, err)
+	require.Equal(t, chunk1[0].K, cursor)
+
+	_, err = sync.Store(ctx, jetID, pnum, chunk2[0].K, chunk3[0].K, chunk3)
+	require.Error(t, err, "store chunk after gap")
+	herr, ok := err.(*reply.HeavyError)
+	require.True(t, ok)
+	require.True(t, herr.IsRetryable())
+
+	cursor, err = sync.Resume(ctx, jetID, pnum)
+	require.NoError(t, err)
+	require.Equal(t, chunk1[0].K, cursor, "resume returns cursor of stored chunk")
+
+	cursor, err = sync.Store(ctx, jetID, pnum, nil, chunk1[0].K, chunk1)
+	require.NoError(t, err, "stored chunk is acknowledged")
+	require.Equal(t, chunk1[0].K, cursor)
+
+	cursor, err = sync.Store(ctx, jetID, pnum, chunk1[0].K, chunk2[0].K, chunk2)
+	require.NoError(t, err)
+	require.Equal(t, chunk2[0].K, cursor)
+
+	// heavy restart
+	sync = NewSync(db, configuration.Ledger{}, nil)
+	cursor, err = sync.Resume(ctx, jetID, pnum)
+	require.NoError(t, err)
+	require.Equal(t, chunk2[0].K, cursor, "resume after restart returns persisted cursor")
+
+	cursor, err = sync.Store(ctx, jetID, pnum, chunk2[0].K, chunk3[0].K, chunk3)
+	require.NoError(t, err)
+	require.Equal(t, chunk3[0].K, cursor)
+
+	err = sync.Stop(ctx, jetID, pnum)
+	require.NoError(t, err)
+	cursor, err = db.GetHeavySyncCursor(ctx, jetID, pnum)
+	require.NoError(t, err)
+	require.Nil(t, cursor, "cursor is removed after sync")
+
+	_, err = sync.Resume(ctx, jetID, pnum)
+	require.Error(t, err, "resume synced pulse")
+}
+
+func TestHeavy_SyncParallel(t *testing.T) {
+	ctx := inslogger.TestContext(t)
+	db, cleaner := storagetest.TmpDB(ctx, t)
+	defer cleaner()
+
+	kvalues1 := []core.KV{{K: []byte("100"), V: []byte("500")}}
+	kvalues2 := []core.KV{{K: []byte("200"), V: []byte("600")}}
+	jetID := testutils.RandomJet()
+	pn1 := core.PulseNumber(core.FirstPulseNumber + 1)
+	pn2 := pn1 + 1
+	pn3 := pn2 + 1
+
+	sync := NewSync(db, configuration.Ledger{
+		PulseManager: configuration.PulseManager{HeavySyncParallelism: 2},
+	}, nil)
+	require.NoError(t, sync.Start(ctx, jetID, pn1))
+	require.NoError(t, sync.Start(ctx, jetID, pn2))
+	require.Error(t, sync.Start(ctx, jetID, pn3), "parallel pulses limit is reached")
+	require.Error(t, sync.Start(ctx, jetID, pn1), "start pulse out of order")
+
+	cursor, err := sync.Store(ctx, jetID, pn2, nil, kvalues2[0].K, kvalues2)
+	require.NoError(t, err)
+	require.Equal(t, kvalues2[0].K, cursor, "chunk of the next pulse is acknowledged")
+	cursor, err = db.GetHeavySyncCursor(ctx, jetID, pn2)
+	require.NoError(t, err)
+	require.Nil(t, cursor, "chunk of the next pulse is buffered until previous pulse is synced")
+
+	err = sync.Stop(ctx, jetID, pn2)
+	require.Error(t, err, "pulse is not synced until previous pulse is synced")
+	herr, ok := err.(*reply.HeavyError)
+	require.True(t, ok)
+	require.True(t, herr.IsRetryable())
+	synced, err := db.GetHeavySyncedPulse(ctx, jetID)
+	require.NoError(t, err)
+	require.Equal(t, core.PulseNumber(0), synced)
+
+	_, err = sync.Store(ctx, jetID, pn1, nil, kvalues1[0].K, kvalues1)
+	require.NoError(t, err)
+	cursor, err = db.GetHeavySyncCursor(ctx, jetID, pn1)
+	require.NoError(t, err)
+	require.Equal(t, kvalues1[0].K, cursor, "chunk of the first pulse is stored")
+
+	require.NoError(t, sync.Stop(ctx, jetID, pn1))
+	synced, err = db.GetHeavySyncedPulse(ctx, jetID)
+	require.NoError(t, err)
+	require.Equal(t, pn2, synced, "buffered pulse is synced after previous pulse")
+	require.NoError(t, sync.Stop(ctx, jetID, pn2), "stop of synced pulse is acknowledged")
+
+	require.NoError(t, sync.Start(ctx, jetID, pn3))
+	require.NoError(t, sync.Reset(ctx, jetID, pn3))
+	require.NoError(t, sync.Start(ctx, jetID, pn3), "start after reset")
+}
+
+func TestHeavy_SyncSignsDrop(t *testing.T) {
+	ctx := inslogger.TestContext(t)
+	mc := minimock.NewController(t)
+	defer mc.Finish()
+	lightDB, lightCleaner := storagetest.TmpDB(ctx, t)
+	defer lightCleaner()
+	heavyDB, heavyCleaner := storagetest.TmpDB(ctx, t)
+	defer heavyCleaner()
+	scheme := platformpolicy.NewPlatformCryptographyScheme()
+	keyProcessor := platformpolicy.NewKeyProcessor()
+
+	lightKey, err := keyProcessor.GeneratePrivateKey()
+	require.NoError(t, err)
+	light := cryptography.NewKeyBoundCryptographyService(lightKey)
+	lightRef := testutils.RandomRef()
+	heavyKey, err := keyProcessor.GeneratePrivateKey()
+	require.NoError(t, err)
+	heavyRef := testutils.RandomRef()
+
+	node := network.NewNodeMock(mc)
+	node.IDMock.Return(heavyRef)
+	nodeNet := network.NewNodeNetworkMock(mc)
+	nodeNet.GetOriginMock.Return(node)
+	sync := NewSync(heavyDB, configuration.Ledger{}, nil)
+	sync.CryptographyService = cryptography.NewKeyBoundCryptographyService(heavyKey)
+	sync.NodeNet = nodeNet
+
+	// heavy stores records of both jets without jet in keys
+	pn := core.PulseNumber(core.FirstPulseNumber + 1)
+	require.NoError(t, heavyDB.AddPulse(ctx, core.Pulse{PulseNumber: pn}))
+	left, right, err := jet.NewTree(true).Split(*jet.NewID(0, nil))
+	require.NoError(t, err)
+	var ids []*core.RecordID
+	for _, jetID := range []core.RecordID{*left, *right} {
+		for i := 0; i < 3; i++ {
+			id, err := lightDB.SetRecord(ctx, jetID, pn, &record.RequestRecord{Payload: append(jetID[:], byte(i))})
+			require.NoError(t, err)
+			ids = append(ids, id)
+		}
+		drop, _, _, err := lightDB.CreateDrop(ctx, jetID, pn, []byte("previous drop"))
+		require.NoError(t, err)
+		require.NoError(t, lightDB.SetDrop(ctx, jetID, drop))
+		_, jetPrefix := jet.Jet(jetID)
+		signature, err := light.Sign(jet.DropSignData(scheme, jetPrefix, pn, drop.Hash))
+		require.NoError(t, err)
+		err = lightDB.SetDropSignature(ctx, jetID, pn, core.DropSignature{Node: lightRef, Signature: signature.Bytes()})
+		require.NoError(t, err)
+
+		require.NoError(t, sync.Start(ctx, jetID, pn))
+		replicator := storage.NewReplicaIter(ctx, lightDB, jetID, pn, pn+1, 100)
+		var cursor []byte
+		for {
+			kvs, err := replicator.NextRecords()
+			if err == storage.ErrReplicatorDone {
+				break
+			}
+			require.NoError(t, err)
+			cursor, err = sync.Store(ctx, jetID, pn, cursor, replicator.LastKey(), kvs)
+			require.NoError(t, err)
+		}
+		require.NoError(t, sync.Stop(ctx, jetID, pn))
+	}
+
+	keys := map[core.RecordRef]crypto.PublicKey{
+		lightRef: keyProcessor.ExtractPublicKey(lightKey),
+		heavyRef: keyProcessor.ExtractPublicKey(heavyKey),
+	}
+	for _, id := range ids {
+		proof, err := heavyDB.GetRecordProof(ctx, *id)
+		require.NoError(t, err)
+		assert.True(t, jet.VerifyMerkleProof(scheme, proof.Record, proof.Path, proof.RecordsRoot))
+		require.Len(t, proof.Signatures, 2)
+		data := jet.DropSignData(scheme, proof.JetPrefix, pn, proof.DropHash)
+		for _, sign := range proof.Signatures {
+			require.Contains(t, keys, sign.Node)
+			assert.True(t, scheme.Verifier(keys[sign.Node]).Verify(core.SignatureFromBytes(sign.Signature), data))
+		}
+	}
+}
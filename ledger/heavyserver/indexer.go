@@ -0,0 +1,56 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package heavyserver
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/instrumentation/inslogger"
+	"github.com/insolar/insolar/ledger/storage"
+)
+
+// Indexer builds secondary indexes of heavy node storage for data synced before they were introduced.
+type Indexer struct {
+	NodeNet core.NodeNetwork `inject:""`
+
+	db *storage.DB
+}
+
+// NewIndexer creates new Indexer instance.
+func NewIndexer(db *storage.DB) *Indexer {
+	return &Indexer{db: db}
+}
+
+// Start builds missing indexes on heavy node.
+//
+// It blocks until indexes are built, so sync doesn't update them concurrently.
+func (i *Indexer) Start(ctx context.Context) error {
+	if i.NodeNet.GetOrigin().Role() != core.StaticRoleHeavyMaterial {
+		return nil
+	}
+
+	started := time.Now()
+	if err := i.db.BuildPrototypeIndex(ctx); err != nil {
+		return errors.Wrap(err, "heavyserver: failed to build prototype index")
+	}
+	inslogger.FromContext(ctx).Infof("heavyserver: indexes are ready, time spent=%v", time.Since(started))
+	return nil
+}
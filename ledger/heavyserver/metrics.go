@@ -25,7 +25,8 @@ import (
 )
 
 var (
-	tagJet = insmetrics.MustTagKey("jet")
+	tagJet       = insmetrics.MustTagKey("jet")
+	tagPruneKind = insmetrics.MustTagKey("kind")
 )
 
 var (
@@ -33,6 +34,14 @@ var (
 	statSyncedRecords = stats.Int64("heavyserver/synced/records", "The number synced records", stats.UnitDimensionless)
 	statSyncedPulse   = stats.Int64("heavyserver/synced/pulse", "Last synced pulse", stats.UnitDimensionless)
 	statSyncedBytes   = stats.Int64("heavyserver/synced/bytes", "Amount of synced records in bytes", stats.UnitBytes)
+	statSkippedChunks = stats.Int64("heavyserver/synced/skipped", "The number of already stored payload chunks received again", stats.UnitDimensionless)
+
+	statPrunedCount = stats.Int64("heavyserver/pruned/count", "The number of entries removed by retention policy", stats.UnitDimensionless)
+	statPrunedBytes = stats.Int64("heavyserver/pruned/bytes", "Amount of space reclaimed by retention policy in bytes", stats.UnitBytes)
+
+	statVerifiedDrops     = stats.Int64("heavyserver/verify/drops", "The number of drops checked by the last verification", stats.UnitDimensionless)
+	statVerifyMismatches  = stats.Int64("heavyserver/verify/mismatches", "The number of mismatches found by the last verification", stats.UnitDimensionless)
+	statVerifyFirstBroken = stats.Int64("heavyserver/verify/first/pulse", "The first pulse with mismatch found by the last verification", stats.UnitDimensionless)
 )
 
 func init() {
@@ -66,6 +75,45 @@ func init() {
 			Aggregation: view.Sum(),
 			TagKeys:     commontags,
 		},
+		&view.View{
+			Name:        statSkippedChunks.Name(),
+			Description: statSkippedChunks.Description(),
+			Measure:     statSkippedChunks,
+			Aggregation: view.Count(),
+			TagKeys:     commontags,
+		},
+		&view.View{
+			Name:        statPrunedCount.Name(),
+			Description: statPrunedCount.Description(),
+			Measure:     statPrunedCount,
+			Aggregation: view.Sum(),
+			TagKeys:     []tag.Key{tagJet, tagPruneKind},
+		},
+		&view.View{
+			Name:        statPrunedBytes.Name(),
+			Description: statPrunedBytes.Description(),
+			Measure:     statPrunedBytes,
+			Aggregation: view.Sum(),
+			TagKeys:     []tag.Key{tagJet, tagPruneKind},
+		},
+		&view.View{
+			Name:        statVerifiedDrops.Name(),
+			Description: statVerifiedDrops.Description(),
+			Measure:     statVerifiedDrops,
+			Aggregation: view.LastValue(),
+		},
+		&view.View{
+			Name:        statVerifyMismatches.Name(),
+			Description: statVerifyMismatches.Description(),
+			Measure:     statVerifyMismatches,
+			Aggregation: view.LastValue(),
+		},
+		&view.View{
+			Name:        statVerifyFirstBroken.Name(),
+			Description: statVerifyFirstBroken.Description(),
+			Measure:     statVerifyFirstBroken,
+			Aggregation: view.LastValue(),
+		},
 	)
 	if err != nil {
 		panic(err)
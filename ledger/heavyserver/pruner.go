@@ -0,0 +1,194 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package heavyserver
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.opencensus.io/stats"
+
+	"github.com/insolar/insolar/configuration"
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/instrumentation/inslogger"
+	"github.com/insolar/insolar/instrumentation/insmetrics"
+	"github.com/insolar/insolar/ledger/storage"
+)
+
+// Pruner periodically removes data of synced jets according to retention configuration.
+type Pruner struct {
+	NodeNet core.NodeNetwork `inject:""`
+
+	db        *storage.DB
+	retention configuration.Retention
+
+	lock sync.Mutex
+	// synced holds the latest synced pulses of jets waiting for pruning.
+	synced map[core.RecordID]core.PulseNumber
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewPruner creates new Pruner instance.
+func NewPruner(db *storage.DB, conf configuration.Ledger) *Pruner {
+	return &Pruner{
+		db:        db,
+		retention: conf.Retention,
+		synced:    map[core.RecordID]core.PulseNumber{},
+	}
+}
+
+func (p *Pruner) enabled() bool {
+	conf := p.retention
+	return conf.KeepStates > 0 || conf.RequestsPulseAge > 0 || conf.MessagesPulseAge > 0 || conf.CompactDeactivated
+}
+
+// Start starts pruning job on heavy node.
+func (p *Pruner) Start(ctx context.Context) error {
+	if !p.enabled() || p.retention.Interval <= 0 || p.NodeNet.GetOrigin().Role() != core.StaticRoleHeavyMaterial {
+		return nil
+	}
+
+	p.stop = make(chan struct{})
+	p.done = make(chan struct{})
+	go func() {
+		defer close(p.done)
+		ticker := time.NewTicker(p.retention.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-p.stop:
+				return
+			case <-ticker.C:
+				p.pruneSynced(ctx)
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops pruning job.
+func (p *Pruner) Stop(ctx context.Context) error {
+	if p.stop == nil {
+		return nil
+	}
+	close(p.stop)
+	<-p.done
+	return nil
+}
+
+// enqueue marks objects changed by synced key/value pairs for states pruning.
+func (p *Pruner) enqueue(ctx context.Context, kvs []core.KV) error {
+	if p == nil || p.retention.DryRun || (p.retention.KeepStates == 0 && !p.retention.CompactDeactivated) {
+		return nil
+	}
+	return p.db.EnqueuePruneLifelines(ctx, kvs)
+}
+
+// notify schedules pruning of the jet synced up to provided pulse.
+func (p *Pruner) notify(jetID core.RecordID, pn core.PulseNumber) {
+	if p == nil || !p.enabled() {
+		return
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if pn > p.synced[jetID] {
+		p.synced[jetID] = pn
+	}
+}
+
+func (p *Pruner) pruneSynced(ctx context.Context) {
+	p.lock.Lock()
+	synced := p.synced
+	p.synced = map[core.RecordID]core.PulseNumber{}
+	p.lock.Unlock()
+
+	for jetID, pn := range synced {
+		if err := p.Prune(ctx, jetID, pn); err != nil {
+			inslogger.FromContext(ctx).Errorf("heavyserver: prune failed: jetID=%v, pulse=%v: %v", jetID, pn, err)
+		}
+	}
+}
+
+// Prune removes data of jet synced up to provided pulse according to retention configuration.
+func (p *Pruner) Prune(ctx context.Context, jetID core.RecordID, pn core.PulseNumber) error {
+	if !p.enabled() {
+		return nil
+	}
+	conf := p.retention
+
+	policy := storage.RetentionPolicy{
+		KeepStates:         conf.KeepStates,
+		CompactDeactivated: conf.CompactDeactivated,
+	}
+	var err error
+	if policy.RequestsUntil, err = p.pulseBefore(ctx, pn, conf.RequestsPulseAge); err != nil {
+		return err
+	}
+	if policy.MessagesUntil, err = p.pulseBefore(ctx, pn, conf.MessagesPulseAge); err != nil {
+		return err
+	}
+
+	stat, err := p.db.PruneJet(ctx, jetID, policy, conf.DryRun)
+	if err != nil {
+		return err
+	}
+
+	inslog := inslogger.FromContext(ctx)
+	if conf.DryRun {
+		inslog.Infof("heavyserver: prune dry run: jetID=%v, pulse=%v, policy=%+v, removable=%v, bytes=%v (total %v)",
+			jetID.DebugString(), pn, policy, stat.Removed, stat.Bytes, stat.TotalBytes())
+		return nil
+	}
+	inslog.Debugf("heavyserver: pruned: jetID=%v, pulse=%v, removed=%v, bytes=%v (total %v)",
+		jetID.DebugString(), pn, stat.Removed, stat.Bytes, stat.TotalBytes())
+
+	ctx = insmetrics.InsertTag(ctx, tagJet, jetID.DebugString())
+	for kind, removed := range stat.Removed {
+		kindctx := insmetrics.InsertTag(ctx, tagPruneKind, kind)
+		stats.Record(kindctx,
+			statPrunedCount.M(int64(removed)),
+			statPrunedBytes.M(stat.Bytes[kind]),
+		)
+	}
+	return nil
+}
+
+// pulseBefore returns pulse number which is age pulses before provided one.
+// Zero is returned if age is zero or there is no such pulse.
+func (p *Pruner) pulseBefore(ctx context.Context, pn core.PulseNumber, age int) (core.PulseNumber, error) {
+	if age <= 0 {
+		return 0, nil
+	}
+	for i := 0; i < age; i++ {
+		prev, err := p.db.GetPreviousPulse(ctx, pn)
+		if err == storage.ErrNotFound {
+			return 0, nil
+		}
+		if err != nil {
+			return 0, errors.Wrap(err, "failed to fetch previous pulse")
+		}
+		if prev == nil {
+			return 0, nil
+		}
+		pn = prev.Pulse.PulseNumber
+	}
+	return pn, nil
+}
@@ -0,0 +1,78 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package heavyserver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gojuno/minimock"
+	"github.com/insolar/insolar/configuration"
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/instrumentation/inslogger"
+	"github.com/insolar/insolar/ledger/storage/storagetest"
+	"github.com/insolar/insolar/testutils"
+	"github.com/insolar/insolar/testutils/network"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPruner(t *testing.T) {
+	ctx := inslogger.TestContext(t)
+	mc := minimock.NewController(t)
+	defer mc.Finish()
+	db, cleaner := storagetest.TmpDB(ctx, t)
+	defer cleaner()
+
+	conf := configuration.NewLedger()
+	conf.Retention.Interval = time.Hour
+	pruner := NewPruner(db, conf)
+
+	node := network.NewNodeMock(mc)
+	node.RoleMock.Return(core.StaticRoleHeavyMaterial)
+	nodeNet := network.NewNodeNetworkMock(mc)
+	nodeNet.GetOriginMock.Return(node)
+	pruner.NodeNet = nodeNet
+
+	// job is not started without retention policy
+	require.NoError(t, pruner.Start(ctx))
+	assert.Nil(t, pruner.stop)
+	require.NoError(t, pruner.Stop(ctx))
+
+	conf.Retention.KeepStates = 1
+	pruner = NewPruner(db, conf)
+	pruner.NodeNet = nodeNet
+	require.NoError(t, pruner.Start(ctx))
+	require.NotNil(t, pruner.stop)
+	defer pruner.Stop(ctx)
+
+	// sync stop schedules pruning instead of pruning in place
+	jetID := testutils.RandomJet()
+	pn1 := core.PulseNumber(core.FirstPulseNumber + 1)
+	pn2 := pn1 + 1
+	sync := NewSync(db, conf, pruner)
+	for _, pn := range []core.PulseNumber{pn1, pn2} {
+		require.NoError(t, sync.Start(ctx, jetID, pn))
+		require.NoError(t, sync.Stop(ctx, jetID, pn))
+	}
+	pruner.lock.Lock()
+	assert.Equal(t, map[core.RecordID]core.PulseNumber{jetID: pn2}, pruner.synced)
+	pruner.lock.Unlock()
+
+	pruner.pruneSynced(ctx)
+	assert.Empty(t, pruner.synced)
+}
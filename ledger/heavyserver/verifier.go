@@ -0,0 +1,151 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package heavyserver
+
+import (
+	"context"
+	"time"
+
+	"go.opencensus.io/stats"
+
+	"github.com/insolar/insolar/configuration"
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/instrumentation/inslogger"
+	"github.com/insolar/insolar/ledger/storage"
+)
+
+// Verifier periodically verifies jet drops chains of heavy node storage.
+type Verifier struct {
+	NodeNet core.NodeNetwork `inject:""`
+
+	db        *storage.DB
+	interval  time.Duration
+	batchSize int
+	// pruned is set if retention policy removes records, so drop hashes can't be verified.
+	pruned bool
+
+	// pass holds results of the current verification pass, it is resumed from pass.Next if the job was stopped.
+	pass    *storage.ChainVerification
+	started time.Time
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewVerifier creates new Verifier instance.
+func NewVerifier(db *storage.DB, conf configuration.Ledger) *Verifier {
+	retention := conf.Retention
+	return &Verifier{
+		db:        db,
+		interval:  conf.Verifier.Interval,
+		batchSize: conf.Verifier.BatchSize,
+		pruned:    !retention.DryRun && (retention.KeepStates > 0 || retention.RequestsPulseAge > 0),
+	}
+}
+
+// Start starts verification job on heavy node.
+func (v *Verifier) Start(ctx context.Context) error {
+	if v.interval <= 0 || v.NodeNet.GetOrigin().Role() != core.StaticRoleHeavyMaterial {
+		return nil
+	}
+
+	v.stop = make(chan struct{})
+	v.done = make(chan struct{})
+	go func() {
+		defer close(v.done)
+		ticker := time.NewTicker(v.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-v.stop:
+				return
+			case <-ticker.C:
+				if _, err := v.Verify(ctx); err != nil {
+					inslogger.FromContext(ctx).Error("heavyserver: storage verification failed: ", err)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops verification job.
+func (v *Verifier) Stop(ctx context.Context) error {
+	if v.stop == nil {
+		return nil
+	}
+	close(v.stop)
+	<-v.done
+	return nil
+}
+
+func (v *Verifier) stopping() bool {
+	select {
+	case <-v.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// Verify verifies all jet drops chains of storage and reports found mismatches.
+//
+// Drops are verified in batches, each one in its own storage transaction. If the job is stopped between
+// batches, nil is returned and the next call resumes the pass from the first unverified drop.
+func (v *Verifier) Verify(ctx context.Context) (*storage.ChainVerification, error) {
+	inslog := inslogger.FromContext(ctx)
+	if v.pass == nil {
+		v.pass = &storage.ChainVerification{}
+		v.started = time.Now()
+	}
+	for {
+		batch, err := v.db.VerifyChains(ctx, storage.ChainVerifyOptions{
+			SkipDropHashes: v.pruned,
+			Cursor:         v.pass.Next,
+			Limit:          v.batchSize,
+		})
+		if err != nil {
+			return nil, err
+		}
+		v.pass.Append(batch)
+		if v.pass.Next == nil {
+			break
+		}
+		if v.stopping() {
+			inslog.Infof("heavyserver: storage verification interrupted after %v drops", v.pass.Drops)
+			return nil, nil
+		}
+	}
+	verification, started := v.pass, v.started
+	v.pass = nil
+
+	var firstBroken core.PulseNumber
+	if first := verification.FirstMismatch(); first != nil {
+		firstBroken = first.Pulse
+		inslog.Errorf("heavyserver: storage verification found %v mismatches, the first one: %v",
+			len(verification.Mismatches), first)
+	}
+	inslog.Infof("heavyserver: storage verified: drops=%v, records=%v, blobs=%v, last pulse=%v, time spent=%v",
+		verification.Drops, verification.Records, verification.Blobs, verification.LastPulse, time.Since(started))
+
+	stats.Record(ctx,
+		statVerifiedDrops.M(int64(verification.Drops)),
+		statVerifyMismatches.M(int64(len(verification.Mismatches))),
+		statVerifyFirstBroken.M(int64(firstBroken)),
+	)
+	return verification, nil
+}
@@ -0,0 +1,101 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package heavyserver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gojuno/minimock"
+	"github.com/insolar/insolar/configuration"
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/instrumentation/inslogger"
+	"github.com/insolar/insolar/ledger/storage/jet"
+	"github.com/insolar/insolar/ledger/storage/storagetest"
+	"github.com/insolar/insolar/testutils/network"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifier(t *testing.T) {
+	ctx := inslogger.TestContext(t)
+	mc := minimock.NewController(t)
+	defer mc.Finish()
+	db, cleaner := storagetest.TmpDB(ctx, t)
+	defer cleaner()
+
+	conf := configuration.NewLedger()
+	conf.Verifier.Interval = time.Millisecond
+	conf.Retention.KeepStates = 1
+	verifier := NewVerifier(db, conf)
+	assert.True(t, verifier.pruned)
+
+	node := network.NewNodeMock(mc)
+	node.RoleMock.Return(core.StaticRoleLightMaterial)
+	nodeNet := network.NewNodeNetworkMock(mc)
+	nodeNet.GetOriginMock.Return(node)
+	verifier.NodeNet = nodeNet
+
+	// job is not started on light node
+	require.NoError(t, verifier.Start(ctx))
+	assert.Nil(t, verifier.stop)
+	require.NoError(t, verifier.Stop(ctx))
+
+	node.RoleMock.Return(core.StaticRoleHeavyMaterial)
+	require.NoError(t, verifier.Start(ctx))
+	require.NotNil(t, verifier.stop)
+	require.NoError(t, verifier.Stop(ctx))
+
+	verification, err := verifier.Verify(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, verification.FirstMismatch())
+	assert.Equal(t, 1, verification.Drops)
+}
+
+func TestVerifier_Resume(t *testing.T) {
+	ctx := inslogger.TestContext(t)
+	db, cleaner := storagetest.TmpDB(ctx, t)
+	defer cleaner()
+
+	root := *jet.NewID(0, nil)
+	for i := 1; i <= 2; i++ {
+		pn := core.FirstPulseNumber + core.PulseNumber(i)
+		require.NoError(t, db.AddPulse(ctx, core.Pulse{PulseNumber: pn}))
+		require.NoError(t, db.SetDrop(ctx, root, &jet.JetDrop{Pulse: pn}))
+	}
+
+	conf := configuration.NewLedger()
+	conf.Verifier.BatchSize = 1
+	verifier := NewVerifier(db, conf)
+
+	// Stopped job is interrupted after the first batch.
+	verifier.stop = make(chan struct{})
+	close(verifier.stop)
+	verification, err := verifier.Verify(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, verification)
+	require.NotNil(t, verifier.pass)
+	assert.Equal(t, 1, verifier.pass.Drops)
+
+	verifier.stop = nil
+	verification, err = verifier.Verify(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, verification)
+	assert.Nil(t, verification.FirstMismatch())
+	assert.Equal(t, 3, verification.Drops)
+	assert.Nil(t, verifier.pass)
+}
@@ -92,6 +92,7 @@ func GetLedgerComponents(conf configuration.Ledger, certificate core.Certificate
 	}
 
 	ps := storage.NewPulseStorage(db)
+	pruner := heavyserver.NewPruner(db, conf)
 
 	return []interface{}{
 		db,
@@ -102,7 +103,10 @@ func GetLedgerComponents(conf configuration.Ledger, certificate core.Certificate
 		pulsemanager.NewPulseManager(db, conf),
 		artifactmanager.NewMessageHandler(db, &conf, certificate),
 		localstorage.NewLocalStorage(db),
-		heavyserver.NewSync(db),
+		heavyserver.NewSync(db, conf, pruner),
+		pruner,
+		heavyserver.NewVerifier(db, conf),
+		heavyserver.NewIndexer(db),
 		exporter.NewExporter(db, ps, conf.Exporter),
 	}
 }
@@ -0,0 +1,209 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package pulsemanager
+
+import (
+	"context"
+	"sync"
+
+	"github.com/insolar/insolar/configuration"
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/instrumentation/inslogger"
+	"github.com/insolar/insolar/ledger/storage/jet"
+	"github.com/pkg/errors"
+	"go.opencensus.io/stats"
+)
+
+// jetDecisionsLimit is a number of the latest decisions kept for status.
+const jetDecisionsLimit = 100
+
+// jetLoad is a load of jet drop or average load of several drops.
+type jetLoad struct {
+	size     uint64
+	requests uint64
+}
+
+// jetBalancer decides jet splits and merges from drop sizes and request rates of the latest drops.
+//
+// Jet is split when its average load over the window and load of each of the latest 'Hysteresis' drops are above
+// split thresholds. Siblings are merged when their load together is below merge thresholds in the same way. Gap
+// between thresholds and required consecutive drops prevent jets from flapping.
+type jetBalancer struct {
+	conf           configuration.JetBalancer
+	splitThreshold uint64
+	window         int
+
+	lock      sync.RWMutex
+	decisions []core.JetDecision
+}
+
+func newJetBalancer(conf configuration.JetBalancer, splitThreshold uint64, historyDepth int) *jetBalancer {
+	window := conf.Window
+	if window > historyDepth {
+		window = historyDepth
+	}
+	if conf.Hysteresis < 1 {
+		conf.Hysteresis = 1
+	}
+	return &jetBalancer{
+		conf:           conf,
+		splitThreshold: splitThreshold,
+		window:         window,
+	}
+}
+
+// validate checks that thresholds leave a gap between split and merge and window is not empty. Configuration of
+// disabled balancer is not checked.
+func (b *jetBalancer) validate() error {
+	if !b.conf.Enabled {
+		return nil
+	}
+	if b.window < 1 {
+		return errors.Errorf(
+			"[ validate ] window must be positive, got %v (it's limited by jet sizes history depth)", b.window,
+		)
+	}
+	if b.conf.MergeThreshold >= b.splitThreshold {
+		return errors.Errorf(
+			"[ validate ] merge threshold %v must be less than split threshold %v",
+			b.conf.MergeThreshold, b.splitThreshold,
+		)
+	}
+	if b.conf.SplitRequests > 0 && b.conf.MergeRequests >= b.conf.SplitRequests {
+		return errors.Errorf(
+			"[ validate ] merge requests %v must be less than split requests %v",
+			b.conf.MergeRequests, b.conf.SplitRequests,
+		)
+	}
+	return nil
+}
+
+// shouldSplit checks if jet is overloaded. Returns average jet load over the window.
+func (b *jetBalancer) shouldSplit(jetID core.RecordID, history jet.DropSizeHistory) (jetLoad, bool) {
+	loads := make([]jetLoad, 0, len(history))
+	for _, drop := range history {
+		loads = append(loads, jetLoad{size: drop.DropSize, requests: drop.Requests})
+	}
+	average := b.average(loads)
+
+	depth, _ := jet.Jet(jetID)
+	if !b.conf.Enabled || int(depth) >= b.conf.MaxDepth {
+		return average, false
+	}
+	return average, b.sustained(loads, b.overloaded)
+}
+
+// shouldMerge checks if sibling jets are underloaded together. Returns average load of both jets over the window.
+func (b *jetBalancer) shouldMerge(left, right jet.DropSizeHistory) (jetLoad, bool) {
+	// Siblings are created together, so their drops are matched by pulse.
+	rightLoads := make(map[core.PulseNumber]jet.DropSize, len(right))
+	for _, drop := range right {
+		rightLoads[drop.PulseNo] = drop
+	}
+	var loads []jetLoad
+	for _, drop := range left {
+		sibling, ok := rightLoads[drop.PulseNo]
+		if !ok {
+			continue
+		}
+		loads = append(loads, jetLoad{
+			size:     drop.DropSize + sibling.DropSize,
+			requests: drop.Requests + sibling.Requests,
+		})
+	}
+	average := b.average(loads)
+
+	if !b.conf.Enabled {
+		return average, false
+	}
+	return average, b.sustained(loads, b.underloaded)
+}
+
+func (b *jetBalancer) overloaded(load jetLoad) bool {
+	if load.size > b.splitThreshold {
+		return true
+	}
+	return b.conf.SplitRequests > 0 && load.requests > b.conf.SplitRequests
+}
+
+func (b *jetBalancer) underloaded(load jetLoad) bool {
+	if load.size >= b.conf.MergeThreshold {
+		return false
+	}
+	return b.conf.MergeRequests == 0 || load.requests < b.conf.MergeRequests
+}
+
+// sustained checks that each of the latest drops in hysteresis and average load over the window satisfy condition.
+func (b *jetBalancer) sustained(loads []jetLoad, condition func(jetLoad) bool) bool {
+	if len(loads) < b.conf.Hysteresis {
+		return false
+	}
+	for _, load := range loads[len(loads)-b.conf.Hysteresis:] {
+		if !condition(load) {
+			return false
+		}
+	}
+	return condition(b.average(loads))
+}
+
+func (b *jetBalancer) average(loads []jetLoad) jetLoad {
+	if len(loads) > b.window {
+		loads = loads[len(loads)-b.window:]
+	}
+	if len(loads) == 0 {
+		return jetLoad{}
+	}
+	var sum jetLoad
+	for _, load := range loads {
+		sum.size += load.size
+		sum.requests += load.requests
+	}
+	return jetLoad{
+		size:     sum.size / uint64(len(loads)),
+		requests: sum.requests / uint64(len(loads)),
+	}
+}
+
+// record saves decision for status and metrics.
+func (b *jetBalancer) record(ctx context.Context, decision core.JetDecision) {
+	b.lock.Lock()
+	b.decisions = append(b.decisions, decision)
+	if len(b.decisions) > jetDecisionsLimit {
+		b.decisions = b.decisions[len(b.decisions)-jetDecisionsLimit:]
+	}
+	b.lock.Unlock()
+
+	inslogger.FromContext(ctx).Infof(
+		"[ jetBalancer ] %v %v -> %v in pulse %v (drop size: %v, requests: %v)",
+		decision.Action, decision.From, decision.To, decision.Pulse, decision.DropSize, decision.Requests,
+	)
+	if decision.Action == core.JetActionSplit {
+		stats.Record(ctx, statJetSplits.M(1))
+	} else {
+		stats.Record(ctx, statJetMerges.M(1))
+	}
+}
+
+// latest returns the latest decisions.
+func (b *jetBalancer) latest() []core.JetDecision {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	decisions := make([]core.JetDecision, len(b.decisions))
+	copy(decisions, b.decisions)
+	return decisions
+}
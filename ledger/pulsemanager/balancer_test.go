@@ -0,0 +1,267 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package pulsemanager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/insolar/insolar/configuration"
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/instrumentation/inslogger"
+	"github.com/insolar/insolar/ledger/recentstorage"
+	"github.com/insolar/insolar/ledger/storage/jet"
+	"github.com/insolar/insolar/ledger/storage/storagetest"
+	"github.com/insolar/insolar/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testBalancerConfig() configuration.JetBalancer {
+	return configuration.JetBalancer{
+		Enabled:        true,
+		Window:         4,
+		Hysteresis:     2,
+		MaxDepth:       3,
+		SplitRequests:  10,
+		MergeThreshold: 100,
+		MergeRequests:  2,
+	}
+}
+
+func dropHistory(jetID core.RecordID, sizes ...uint64) jet.DropSizeHistory {
+	var history jet.DropSizeHistory
+	for i, size := range sizes {
+		history = append(history, jet.DropSize{
+			JetID:    jetID,
+			PulseNo:  core.FirstPulseNumber + core.PulseNumber(i),
+			DropSize: size,
+		})
+	}
+	return history
+}
+
+func TestJetBalancer_ShouldSplit(t *testing.T) {
+	b := newJetBalancer(testBalancerConfig(), 1000, 10)
+	jetID := *jet.NewID(1, nil)
+
+	t.Run("sustained load splits", func(t *testing.T) {
+		load, ok := b.shouldSplit(jetID, dropHistory(jetID, 1500, 1500, 2000, 2500))
+		assert.True(t, ok)
+		assert.Equal(t, uint64(1875), load.size)
+	})
+
+	t.Run("single spike doesn't split", func(t *testing.T) {
+		_, ok := b.shouldSplit(jetID, dropHistory(jetID, 100, 100, 100, 5000))
+		assert.False(t, ok)
+	})
+
+	t.Run("low average doesn't split", func(t *testing.T) {
+		_, ok := b.shouldSplit(jetID, dropHistory(jetID, 100, 100, 1100, 1100))
+		assert.False(t, ok)
+	})
+
+	t.Run("short history doesn't split", func(t *testing.T) {
+		_, ok := b.shouldSplit(jetID, dropHistory(jetID, 5000))
+		assert.False(t, ok)
+	})
+
+	t.Run("requests rate splits", func(t *testing.T) {
+		history := dropHistory(jetID, 10, 10)
+		for i := range history {
+			history[i].Requests = 20
+		}
+		load, ok := b.shouldSplit(jetID, history)
+		assert.True(t, ok)
+		assert.Equal(t, uint64(20), load.requests)
+	})
+
+	t.Run("max depth is respected", func(t *testing.T) {
+		deep := *jet.NewID(3, nil)
+		_, ok := b.shouldSplit(deep, dropHistory(deep, 5000, 5000))
+		assert.False(t, ok)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		conf := testBalancerConfig()
+		conf.Enabled = false
+		_, ok := newJetBalancer(conf, 1000, 10).shouldSplit(jetID, dropHistory(jetID, 5000, 5000))
+		assert.False(t, ok)
+	})
+}
+
+func TestJetBalancer_ShouldMerge(t *testing.T) {
+	b := newJetBalancer(testBalancerConfig(), 1000, 10)
+	left := *jet.NewID(1, nil)
+	right := jet.Sibling(left)
+
+	t.Run("underloaded siblings merge", func(t *testing.T) {
+		load, ok := b.shouldMerge(dropHistory(left, 10, 20, 30), dropHistory(right, 10, 20, 30))
+		assert.True(t, ok)
+		assert.Equal(t, uint64(40), load.size)
+	})
+
+	t.Run("load of both jets is checked", func(t *testing.T) {
+		_, ok := b.shouldMerge(dropHistory(left, 10, 60, 60), dropHistory(right, 10, 60, 60))
+		assert.False(t, ok)
+	})
+
+	t.Run("drops are matched by pulse", func(t *testing.T) {
+		rightHistory := dropHistory(right, 0, 10)
+		_, ok := b.shouldMerge(dropHistory(left, 10), rightHistory)
+		assert.False(t, ok)
+	})
+
+	t.Run("requests rate prevents merge", func(t *testing.T) {
+		leftHistory := dropHistory(left, 10, 10)
+		for i := range leftHistory {
+			leftHistory[i].Requests = 5
+		}
+		_, ok := b.shouldMerge(leftHistory, dropHistory(right, 10, 10))
+		assert.False(t, ok)
+	})
+}
+
+func TestJetBalancer_Record(t *testing.T) {
+	ctx := inslogger.TestContext(t)
+	b := newJetBalancer(testBalancerConfig(), 1000, 10)
+
+	for i := 0; i < jetDecisionsLimit+1; i++ {
+		b.record(ctx, core.JetDecision{
+			Pulse:  core.PulseNumber(i),
+			Action: core.JetActionSplit,
+		})
+	}
+
+	decisions := b.latest()
+	require.Len(t, decisions, jetDecisionsLimit)
+	assert.Equal(t, core.PulseNumber(1), decisions[0].Pulse)
+}
+
+func TestJetBalancer_Validate(t *testing.T) {
+	assert.NoError(t, newJetBalancer(testBalancerConfig(), 1000, 10).validate())
+
+	t.Run("disabled balancer is not checked", func(t *testing.T) {
+		assert.NoError(t, newJetBalancer(configuration.JetBalancer{}, 0, 0).validate())
+	})
+
+	t.Run("empty window", func(t *testing.T) {
+		conf := testBalancerConfig()
+		conf.Window = 0
+		assert.Error(t, newJetBalancer(conf, 1000, 10).validate())
+		assert.Error(t, newJetBalancer(testBalancerConfig(), 1000, 0).validate())
+	})
+
+	t.Run("merge threshold reaches split threshold", func(t *testing.T) {
+		assert.Error(t, newJetBalancer(testBalancerConfig(), 100, 10).validate())
+	})
+
+	t.Run("merge requests reach split requests", func(t *testing.T) {
+		conf := testBalancerConfig()
+		conf.MergeRequests = conf.SplitRequests
+		assert.Error(t, newJetBalancer(conf, 1000, 10).validate())
+	})
+}
+
+func TestPulseManager_MergeJets(t *testing.T) {
+	ctx := inslogger.TestContext(t)
+	db, cleaner := storagetest.TmpDB(ctx, t)
+	defer cleaner()
+
+	currentPulse := core.FirstPulseNumber + core.PulseNumber(10)
+	newPulse := core.FirstPulseNumber + core.PulseNumber(20)
+	root := *jet.NewID(0, nil)
+	left, right, ok := jet.Children(root)
+	require.True(t, ok)
+	_, _, err := db.SplitJetTree(ctx, newPulse, root)
+	require.NoError(t, err)
+	require.NoError(t, db.SetDropSizeHistory(ctx, left, dropHistory(left, 10, 20)))
+	require.NoError(t, db.SetDropSizeHistory(ctx, right, dropHistory(right, 10, 20)))
+
+	me := testutils.RandomRef()
+	coordinator := testutils.NewJetCoordinatorMock(t)
+	coordinator.MeMock.Return(me)
+	coordinator.LightExecutorForJetFunc = func(
+		_ context.Context, _ core.RecordID, pn core.PulseNumber,
+	) (*core.RecordRef, error) {
+		if pn == currentPulse {
+			return &me, nil
+		}
+		// Next executor is someone else, so hot data is not rewritten.
+		next := testutils.RandomRef()
+		return &next, nil
+	}
+	recent := recentstorage.NewRecentStorageMock(t)
+	recent.DecreaseTTLMock.Return()
+	provider := recentstorage.NewProviderMock(t)
+	provider.GetStorageMock.Return(recent)
+	crypto := testutils.NewCryptographyServiceMock(t)
+	crypto.SignFunc = func([]byte) (*core.Signature, error) {
+		signature := core.SignatureFromBytes(nil)
+		return &signature, nil
+	}
+	scheme := testutils.NewPlatformCryptographyScheme()
+
+	pm := &PulseManager{
+		db:                         db,
+		balancer:                   newJetBalancer(testBalancerConfig(), 1000, 10),
+		JetCoordinator:             coordinator,
+		RecentStorageProvider:      provider,
+		CryptographyService:        crypto,
+		PlatformCryptographyScheme: scheme,
+	}
+
+	t.Run("sibling split further is not merged", func(t *testing.T) {
+		infos, err := pm.mergeJets(ctx, left, map[core.RecordID]struct{}{left: {}}, currentPulse, newPulse)
+		require.NoError(t, err)
+		assert.Nil(t, infos)
+	})
+
+	t.Run("underloaded siblings are merged", func(t *testing.T) {
+		leaves := map[core.RecordID]struct{}{left: {}, right: {}}
+		infos, err := pm.mergeJets(ctx, left, leaves, currentPulse, newPulse)
+		require.NoError(t, err)
+		require.Len(t, infos, 2)
+		assert.Equal(t, left, infos[0].id)
+		assert.Equal(t, right, infos[1].id)
+		require.NotNil(t, infos[0].parent)
+		assert.Equal(t, root, infos[0].parent.id)
+		assert.False(t, infos[0].parent.mineNext)
+
+		tree, err := db.GetJetTree(ctx, newPulse)
+		require.NoError(t, err)
+		assert.Equal(t, []core.RecordID{root}, tree.LeafIDs())
+		history, err := db.GetDropSizeHistory(ctx, root)
+		require.NoError(t, err)
+		assert.Empty(t, history)
+	})
+
+	t.Run("merged drop is chained to both branches", func(t *testing.T) {
+		createDrop := func(jetID core.RecordID) *jet.JetDrop {
+			drop, _, _, err := db.CreateDrop(ctx, jetID, currentPulse, []byte("prev of "+jetID.DebugString()))
+			require.NoError(t, err)
+			require.NoError(t, db.SetDrop(ctx, jetID, drop))
+			return drop
+		}
+		leftDrop := createDrop(left)
+		rightDrop := createDrop(right)
+
+		drop, _, _, err := pm.createDrop(ctx, root, currentPulse, newPulse)
+		require.NoError(t, err)
+		assert.Equal(t, jet.MergedPrevHash(scheme, leftDrop.Hash, rightDrop.Hash), drop.PrevHash)
+	})
+}
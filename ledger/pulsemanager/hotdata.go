@@ -0,0 +1,84 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package pulsemanager
+
+import (
+	"context"
+	"time"
+
+	"github.com/insolar/insolar/core/message"
+	"github.com/insolar/insolar/core/reply"
+	"github.com/pkg/errors"
+)
+
+const (
+	// hotDataRetryCount is a number of attempts to send hot data if receiver doesn't execute the jet.
+	hotDataRetryCount = 3
+	// hotDataRetryDelay gives receiver time to catch up with pulse and jet tree before the next attempt.
+	hotDataRetryDelay = 100 * time.Millisecond
+)
+
+// publishHotData sends hot data to the next jet executor. Message is sent again if receiver replies with
+// reply.JetMiss, an error is returned when retry limit is exceeded or message is not delivered.
+func (m *PulseManager) publishHotData(ctx context.Context, msg *message.HotData) error {
+	for attempt := 0; attempt < hotDataRetryCount; attempt++ {
+		if attempt > 0 {
+			time.Sleep(hotDataRetryDelay)
+		}
+		jetMiss, err := m.publishHotDataOnce(ctx, msg)
+		if err != nil {
+			return err
+		}
+		if !jetMiss {
+			return nil
+		}
+	}
+	return errors.New("[ publishHotData ] receiver doesn't execute jet (retry limit exceeded)")
+}
+
+func (m *PulseManager) publishHotDataOnce(ctx context.Context, msg *message.HotData) (bool, error) {
+	deliveries, err := m.Bus.Publish(ctx, msg, nil)
+	if err != nil {
+		return false, err
+	}
+
+	jetMiss := false
+	for delivery := range deliveries {
+		if delivery.Err != nil {
+			err = delivery.Err
+			continue
+		}
+		ack, ok := delivery.Reply.(*reply.Ack)
+		if !ok {
+			err = errors.Errorf("[ publishHotData ] unexpected reply %T", delivery.Reply)
+			continue
+		}
+		for _, rep := range ack.Replies {
+			switch rep.(type) {
+			case *reply.OK:
+			case *reply.JetMiss:
+				jetMiss = true
+			default:
+				err = errors.Errorf("[ publishHotData ] unexpected subscriber reply %T", rep)
+			}
+		}
+	}
+	if err != nil {
+		return false, err
+	}
+	return jetMiss, nil
+}
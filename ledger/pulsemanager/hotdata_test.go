@@ -0,0 +1,83 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package pulsemanager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/core/message"
+	"github.com/insolar/insolar/core/reply"
+	"github.com/insolar/insolar/instrumentation/inslogger"
+	"github.com/insolar/insolar/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPulseManager_publishHotData(t *testing.T) {
+	ctx := inslogger.TestContext(t)
+
+	publish := func(replies ...core.Delivery) *testutils.MessageBusMock {
+		bus := testutils.NewMessageBusMock(t)
+		bus.PublishFunc = func(context.Context, core.Message, *core.MessageSendOptions) (<-chan core.Delivery, error) {
+			// The last reply is repeated for the next attempts.
+			attempt := int(bus.PublishMinimockCounter())
+			if attempt >= len(replies) {
+				attempt = len(replies) - 1
+			}
+			deliveries := make(chan core.Delivery, 1)
+			deliveries <- replies[attempt]
+			close(deliveries)
+			return deliveries, nil
+		}
+		return bus
+	}
+	ack := func(rep core.Reply) core.Delivery {
+		return core.Delivery{Reply: &reply.Ack{Errors: []string{""}, Replies: []core.Reply{rep}}}
+	}
+
+	t.Run("delivered", func(t *testing.T) {
+		pm := &PulseManager{Bus: publish(ack(&reply.OK{}))}
+		require.NoError(t, pm.publishHotData(ctx, &message.HotData{}))
+		assert.Equal(t, uint64(1), pm.Bus.(*testutils.MessageBusMock).PublishMinimockCounter())
+	})
+
+	t.Run("jet miss is retried", func(t *testing.T) {
+		pm := &PulseManager{Bus: publish(ack(&reply.JetMiss{}), ack(&reply.OK{}))}
+		require.NoError(t, pm.publishHotData(ctx, &message.HotData{}))
+		assert.Equal(t, uint64(2), pm.Bus.(*testutils.MessageBusMock).PublishMinimockCounter())
+	})
+
+	t.Run("retry limit", func(t *testing.T) {
+		pm := &PulseManager{Bus: publish(ack(&reply.JetMiss{}))}
+		err := pm.publishHotData(ctx, &message.HotData{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "retry limit exceeded")
+		assert.Equal(t, uint64(hotDataRetryCount), pm.Bus.(*testutils.MessageBusMock).PublishMinimockCounter())
+	})
+
+	t.Run("delivery error", func(t *testing.T) {
+		pm := &PulseManager{Bus: publish(core.Delivery{Err: assert.AnError})}
+		assert.Equal(t, assert.AnError, pm.publishHotData(ctx, &message.HotData{}))
+	})
+
+	t.Run("unexpected reply", func(t *testing.T) {
+		pm := &PulseManager{Bus: publish(ack(&reply.Error{}))}
+		assert.Error(t, pm.publishHotData(ctx, &message.HotData{}))
+	})
+}
@@ -0,0 +1,61 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package pulsemanager
+
+import (
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+)
+
+var (
+	statJetSplits   = stats.Int64("pulsemanager/jets/splits", "The number of jet splits", stats.UnitDimensionless)
+	statJetMerges   = stats.Int64("pulsemanager/jets/merges", "The number of jet merges", stats.UnitDimensionless)
+	statJetCount    = stats.Int64("pulsemanager/jets/count", "The number of jets in the latest jet tree", stats.UnitDimensionless)
+	statJetMaxDepth = stats.Int64("pulsemanager/jets/depth", "Max depth of the latest jet tree", stats.UnitDimensionless)
+)
+
+func init() {
+	err := view.Register(
+		&view.View{
+			Name:        statJetSplits.Name(),
+			Description: statJetSplits.Description(),
+			Measure:     statJetSplits,
+			Aggregation: view.Count(),
+		},
+		&view.View{
+			Name:        statJetMerges.Name(),
+			Description: statJetMerges.Description(),
+			Measure:     statJetMerges,
+			Aggregation: view.Count(),
+		},
+		&view.View{
+			Name:        statJetCount.Name(),
+			Description: statJetCount.Description(),
+			Measure:     statJetCount,
+			Aggregation: view.LastValue(),
+		},
+		&view.View{
+			Name:        statJetMaxDepth.Name(),
+			Description: statJetMaxDepth.Description(),
+			Measure:     statJetMaxDepth,
+			Aggregation: view.LastValue(),
+		},
+	)
+	if err != nil {
+		panic(err)
+	}
+}
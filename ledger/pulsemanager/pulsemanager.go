@@ -17,15 +17,14 @@
 package pulsemanager
 
 import (
+	"bytes"
 	"context"
-	"math/rand"
 	"sync"
 	"time"
 
 	"github.com/insolar/insolar/configuration"
 	"github.com/insolar/insolar/core"
 	"github.com/insolar/insolar/core/message"
-	"github.com/insolar/insolar/core/reply"
 	"github.com/insolar/insolar/instrumentation/inslogger"
 	"github.com/insolar/insolar/instrumentation/instracer"
 	"github.com/insolar/insolar/ledger/heavyclient"
@@ -35,6 +34,7 @@ import (
 	"github.com/insolar/insolar/ledger/storage/jet"
 	"github.com/insolar/insolar/ledger/storage/record"
 	"github.com/pkg/errors"
+	"go.opencensus.io/stats"
 	"go.opencensus.io/trace"
 	"golang.org/x/sync/errgroup"
 	"golang.org/x/sync/singleflight"
@@ -75,6 +75,8 @@ type PulseManager struct {
 
 	// stores pulse manager options
 	options pmOptions
+	// balancer decides when jets should be split or merged
+	balancer *jetBalancer
 }
 
 type jetInfo struct {
@@ -82,6 +84,8 @@ type jetInfo struct {
 	mineNext bool
 	left     *jetInfo
 	right    *jetInfo
+	// parent is set when jet is merged with its sibling.
+	parent *jetInfo
 }
 
 // TODO: @andreyromancev. 15.01.19. Just store ledger configuration in PM. This is not required.
@@ -100,6 +104,9 @@ func NewPulseManager(db *storage.DB, conf configuration.Ledger) *PulseManager {
 		heavyclient.Options{
 			SyncMessageLimit: pmconf.HeavySyncMessageLimit,
 			PulsesDeltaLimit: conf.LightChainLimit,
+			BackoffConf:      pmconf.HeavyBackoff,
+			Parallelism:      pmconf.HeavySyncParallelism,
+			Compression:      pmconf.HeavySyncCompression,
 		},
 	)
 	pm := &PulseManager{
@@ -112,6 +119,7 @@ func NewPulseManager(db *storage.DB, conf configuration.Ledger) *PulseManager {
 			storeLightPulses: conf.LightChainLimit,
 		},
 		syncClientsPool: heavySyncPool,
+		balancer:        newJetBalancer(pmconf.JetBalancer, pmconf.SplitThreshold, conf.JetSizesHistoryDepth),
 	}
 	return pm
 }
@@ -150,7 +158,7 @@ func (m *PulseManager) processEndPulse(
 				defer span.End()
 				msg.Jet = *core.NewRecordRef(core.DomainID, jetID)
 				start := time.Now()
-				genericRep, err := m.Bus.Send(ctx, &msg, nil)
+				err := m.publishHotData(ctx, &msg)
 				sendTime := time.Since(start)
 				if sendTime > time.Second {
 					logger.Debugf("[send] jet: %v, long send: %s. Success: %v", jetID.DebugString(), sendTime, err == nil)
@@ -159,14 +167,15 @@ func (m *PulseManager) processEndPulse(
 					logger.Debugf("[jet]: %v send hot. Pulse: %v, DropJet: %v, Error: %s", jetID.DebugString(), currentPulse.PulseNumber, msg.DropJet.DebugString(), err)
 					return
 				}
-				if _, ok := genericRep.(*reply.OK); !ok {
-					logger.Debugf("[jet]: %v send hot. Pulse: %v, DropJet: %v, Unexpected reply: %#v", jetID.DebugString(), currentPulse.PulseNumber, msg.DropJet.DebugString(), genericRep)
-					return
-				}
 				logger.Debugf("[jet]: %v send hot. Pulse: %v, DropJet: %v, Success", jetID.DebugString(), currentPulse.PulseNumber, msg.DropJet.DebugString())
 			}
 
-			if info.left == nil && info.right == nil {
+			if info.parent != nil {
+				// Merge happened.
+				if !info.parent.mineNext {
+					go sender(*msg, info.parent.id)
+				}
+			} else if info.left == nil && info.right == nil {
 				// No split happened.
 				if !info.mineNext {
 					go sender(*msg, info.id)
@@ -214,16 +223,18 @@ func (m *PulseManager) processEndPulse(
 // 			for reqID := range objectRequests {
 // 				toSend = append(toSend, reqID)
 // 			}
-// 			rep, err := m.Bus.Send(ctx, &message.AbandonedRequestsNotification{
+// 			deliveries, err := m.Bus.Publish(ctx, &message.AbandonedRequestsNotification{
 // 				Object:   object,
 // 				Requests: toSend,
-// 			}, *pulse, nil)
+// 			}, nil)
 // 			if err != nil {
 // 				inslogger.FromContext(ctx).Error("failed to notify about pending requests")
 // 				return
 // 			}
-// 			if _, ok := rep.(*reply.OK); !ok {
-// 				inslogger.FromContext(ctx).Error("received unexpected reply on pending notification")
+// 			for delivery := range deliveries {
+// 				if delivery.Err != nil {
+// 					inslogger.FromContext(ctx).Error("failed to deliver pending notification: ", delivery.Err)
+// 				}
 // 			}
 // 		}(objID, requests)
 // 	}
@@ -231,6 +242,38 @@ func (m *PulseManager) processEndPulse(
 // 	wg.Wait()
 // }
 
+// prevDropHash returns hash the next drop of jet is chained to. If jet was merged in previous pulse, the hash
+// commits to the drops of both branches. If jet was split, the drop of its parent is used.
+func (m *PulseManager) prevDropHash(
+	ctx context.Context, jetID core.RecordID, prevPulse core.PulseNumber,
+) ([]byte, error) {
+	if _, rightID, ok := jet.Children(jetID); ok {
+		rightDrop, err := m.db.GetDrop(ctx, rightID, prevPulse)
+		if err == nil {
+			// Left branch has the same prefix as the jet, so its drop is stored under the jet key.
+			leftDrop, err := m.db.GetDrop(ctx, jetID, prevPulse)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to find left branch of merged jet")
+			}
+			return jet.MergedPrevHash(m.PlatformCryptographyScheme, leftDrop.Hash, rightDrop.Hash), nil
+		}
+		if err != storage.ErrNotFound {
+			return nil, err
+		}
+	}
+
+	prevDrop, err := m.db.GetDrop(ctx, jetID, prevPulse)
+	if err == storage.ErrNotFound {
+		prevDrop, err = m.db.GetDrop(ctx, jet.Parent(jetID), prevPulse)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to find parent")
+		}
+	} else if err != nil {
+		return nil, err
+	}
+	return prevDrop.Hash, nil
+}
+
 func (m *PulseManager) createDrop(
 	ctx context.Context,
 	jetID core.RecordID,
@@ -241,18 +284,12 @@ func (m *PulseManager) createDrop(
 	messages [][]byte,
 	err error,
 ) {
-	var prevDrop *jet.JetDrop
-	prevDrop, err = m.db.GetDrop(ctx, jetID, prevPulse)
-	if err == storage.ErrNotFound {
-		prevDrop, err = m.db.GetDrop(ctx, jet.Parent(jetID), prevPulse)
-		if err != nil {
-			return nil, nil, nil, errors.Wrap(err, "[ createDrop ] failed to find parent")
-		}
-	} else if err != nil {
+	prevHash, err := m.prevDropHash(ctx, jetID, prevPulse)
+	if err != nil {
 		return nil, nil, nil, errors.Wrap(err, "[ createDrop ] Can't GetDrop")
 	}
 
-	drop, messages, dropSize, err := m.db.CreateDrop(ctx, jetID, currentPulse, prevDrop.Hash)
+	drop, messages, dropSizeData, err := m.db.CreateDrop(ctx, jetID, currentPulse, prevHash)
 	if err != nil {
 		return nil, nil, nil, errors.Wrap(err, "[ createDrop ] Can't CreateDrop")
 	}
@@ -261,27 +298,36 @@ func (m *PulseManager) createDrop(
 		return nil, nil, nil, errors.Wrap(err, "[ createDrop ] Can't SetDrop")
 	}
 
+	_, jetPrefix := jet.Jet(jetID)
+	dropSignature, err := m.CryptographyService.Sign(
+		jet.DropSignData(m.PlatformCryptographyScheme, jetPrefix, drop.Pulse, drop.Hash),
+	)
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "[ createDrop ] Can't sign drop")
+	}
+	err = m.db.SetDropSignature(ctx, jetID, drop.Pulse, core.DropSignature{
+		Node:      m.JetCoordinator.Me(),
+		Signature: dropSignature.Bytes(),
+	})
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "[ createDrop ] Can't SetDropSignature")
+	}
+
 	dropSerialized, err = jet.Encode(drop)
 	if err != nil {
 		return nil, nil, nil, errors.Wrap(err, "[ createDrop ] Can't Encode")
 	}
 
-	dropSizeData := &jet.DropSize{
-		JetID:    jetID,
-		PulseNo:  currentPulse,
-		DropSize: dropSize,
-	}
 	hasher := m.PlatformCryptographyScheme.IntegrityHasher()
 	_, err = dropSizeData.WriteHashData(hasher)
 	if err != nil {
 		return nil, nil, nil, errors.Wrap(err, "[ createDrop ] Can't WriteHashData")
 	}
 	signature, err := m.CryptographyService.Sign(hasher.Sum(nil))
-	dropSizeData.Signature = signature.Bytes()
-
 	if err != nil {
 		return nil, nil, nil, errors.Wrap(err, "[ createDrop ] Can't Sign")
 	}
+	dropSizeData.Signature = signature.Bytes()
 
 	err = m.db.AddDropSize(ctx, dropSizeData)
 	if err != nil {
@@ -375,9 +421,6 @@ func (m *PulseManager) getExecutorHotData(
 	return msg, nil
 }
 
-// TODO: @andreyromancev. 12.01.19. Remove when dynamic split is working.
-var splitCount = 5
-
 func (m *PulseManager) processJets(ctx context.Context, currentPulse, newPulse core.PulseNumber) ([]jetInfo, error) {
 	ctx, span := instracer.StartSpan(ctx, "jets.process")
 	defer span.End()
@@ -393,10 +436,17 @@ func (m *PulseManager) processJets(ctx context.Context, currentPulse, newPulse c
 
 	var results []jetInfo
 	jetIDs := tree.LeafIDs()
+	leaves := make(map[core.RecordID]struct{}, len(jetIDs))
+	for _, jetID := range jetIDs {
+		leaves[jetID] = struct{}{}
+	}
+	merged := map[core.RecordID]struct{}{}
 	me := m.JetCoordinator.Me()
 	logger := inslogger.FromContext(ctx)
-	indexToSplit := rand.Intn(len(jetIDs))
-	for i, jetID := range jetIDs {
+	for _, jetID := range jetIDs {
+		if _, ok := merged[jetID]; ok {
+			continue
+		}
 		executor, err := m.JetCoordinator.LightExecutorForJet(ctx, jetID, currentPulse)
 		if err != nil {
 			return nil, err
@@ -409,83 +459,242 @@ func (m *PulseManager) processJets(ctx context.Context, currentPulse, newPulse c
 
 		m.RecentStorageProvider.GetStorage(ctx, jetID).DecreaseTTL(ctx)
 
-		info := jetInfo{id: jetID}
-		if indexToSplit == i && splitCount > 0 {
-			splitCount--
-
-			leftJetID, rightJetID, err := m.db.SplitJetTree(
-				ctx,
-				newPulse,
-				jetID,
-			)
-			if err != nil {
-				return nil, errors.Wrap(err, "failed to split jet tree")
-			}
-			err = m.db.AddJets(ctx, *leftJetID, *rightJetID)
-			if err != nil {
-				return nil, errors.Wrap(err, "failed to add jets")
-			}
-			// Set actual because we are the last executor for jet.
-			err = m.db.UpdateJetTree(ctx, newPulse, true, *leftJetID, *rightJetID)
-			if err != nil {
-				return nil, errors.Wrap(err, "failed to update tree")
-			}
-
-			info.left = &jetInfo{id: *leftJetID}
-			info.right = &jetInfo{id: *rightJetID}
-			nextLeftExecutor, err := m.JetCoordinator.LightExecutorForJet(ctx, *leftJetID, newPulse)
-			if err != nil {
-				return nil, err
-			}
-			if *nextLeftExecutor == me {
-				info.left.mineNext = true
-				err := m.rewriteHotData(ctx, jetID, *leftJetID)
-				logger.Debugf("[jet]: %v rewrite hot left. Pulse: %v, Error: %s", info.left.id.DebugString(), currentPulse, err)
-				if err != nil {
-					return nil, err
-				}
-			}
-			nextRightExecutor, err := m.JetCoordinator.LightExecutorForJet(ctx, *rightJetID, newPulse)
+		history, err := m.db.GetDropSizeHistory(ctx, jetID)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to fetch drop size history")
+		}
+		if load, ok := m.balancer.shouldSplit(jetID, history); ok {
+			info, err := m.splitJet(ctx, jetID, currentPulse, newPulse, load)
 			if err != nil {
 				return nil, err
 			}
-			if *nextRightExecutor == me {
-				info.right.mineNext = true
-				err := m.rewriteHotData(ctx, jetID, *rightJetID)
-				logger.Debugf("[jet]: %v rewrite hot right. Pulse: %v, Error: %s", info.right.id.DebugString(), currentPulse, err)
-				if err != nil {
-					return nil, err
-				}
-			}
+			results = append(results, *info)
+			continue
+		}
 
-			logger.Debugf(
-				"SPLIT HAPPENED parent: %v, left: %v, right: %v",
-				jetID.DebugString(),
-				leftJetID.DebugString(),
-				rightJetID.DebugString(),
-			)
-		} else {
-			// Set actual because we are the last executor for jet.
-			err = m.db.UpdateJetTree(ctx, newPulse, true, jetID)
-			if err != nil {
-				return nil, errors.Wrap(err, "failed to update tree")
+		mergedInfos, err := m.mergeJets(ctx, jetID, leaves, currentPulse, newPulse)
+		if err != nil {
+			return nil, err
+		}
+		if mergedInfos != nil {
+			for _, info := range mergedInfos {
+				merged[info.id] = struct{}{}
 			}
-			nextExecutor, err := m.JetCoordinator.LightExecutorForJet(ctx, jetID, newPulse)
+			results = append(results, mergedInfos...)
+			continue
+		}
+
+		info := jetInfo{id: jetID}
+		// Set actual because we are the last executor for jet.
+		err = m.db.UpdateJetTree(ctx, newPulse, true, jetID)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to update tree")
+		}
+		nextExecutor, err := m.JetCoordinator.LightExecutorForJet(ctx, jetID, newPulse)
+		if err != nil {
+			return nil, err
+		}
+		if *nextExecutor == me {
+			info.mineNext = true
+			logger.Debugf("[jet]: %v preserve hot. Pulse: %v", info.id.DebugString(), currentPulse)
+		}
+		results = append(results, info)
+	}
+
+	m.recordJetTree(ctx, newPulse)
+
+	return results, nil
+}
+
+func (m *PulseManager) splitJet(
+	ctx context.Context, jetID core.RecordID, currentPulse, newPulse core.PulseNumber, load jetLoad,
+) (*jetInfo, error) {
+	logger := inslogger.FromContext(ctx)
+	me := m.JetCoordinator.Me()
+
+	leftJetID, rightJetID, err := m.db.SplitJetTree(
+		ctx,
+		newPulse,
+		jetID,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to split jet tree")
+	}
+	err = m.db.AddJets(ctx, *leftJetID, *rightJetID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to add jets")
+	}
+	// Set actual because we are the last executor for jet.
+	err = m.db.UpdateJetTree(ctx, newPulse, true, *leftJetID, *rightJetID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to update tree")
+	}
+
+	info := &jetInfo{id: jetID}
+	info.left = &jetInfo{id: *leftJetID}
+	info.right = &jetInfo{id: *rightJetID}
+	for _, child := range []*jetInfo{info.left, info.right} {
+		// Jet could exist before, its stale history shouldn't affect the next decisions.
+		err = m.db.SetDropSizeHistory(ctx, child.id, jet.DropSizeHistory{})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to reset drop size history")
+		}
+		nextExecutor, err := m.JetCoordinator.LightExecutorForJet(ctx, child.id, newPulse)
+		if err != nil {
+			return nil, err
+		}
+		if *nextExecutor == me {
+			child.mineNext = true
+			err := m.rewriteHotData(ctx, jetID, child.id)
+			logger.Debugf("[jet]: %v rewrite hot. Pulse: %v, Error: %s", child.id.DebugString(), currentPulse, err)
 			if err != nil {
 				return nil, err
 			}
-			if *nextExecutor == me {
-				info.mineNext = true
-				logger.Debugf("[jet]: %v preserve hot. Pulse: %v", info.id.DebugString(), currentPulse)
-			}
 		}
-		results = append(results, info)
 	}
 
-	return results, nil
+	logger.Debugf(
+		"SPLIT HAPPENED parent: %v, left: %v, right: %v",
+		jetID.DebugString(),
+		leftJetID.DebugString(),
+		rightJetID.DebugString(),
+	)
+	m.balancer.record(ctx, core.JetDecision{
+		Pulse:    newPulse,
+		Action:   core.JetActionSplit,
+		From:     []core.RecordID{jetID},
+		To:       []core.RecordID{*leftJetID, *rightJetID},
+		DropSize: load.size,
+		Requests: load.requests,
+	})
+	return info, nil
 }
 
-func (m *PulseManager) rewriteHotData(ctx context.Context, fromJetID, toJetID core.RecordID) error {
+// mergeJets merges left jet with its sibling if both are underloaded. Returns nil if merge is not performed.
+//
+// Jets are merged only if current node executes both of them, because it requires hot data of both jets.
+func (m *PulseManager) mergeJets(
+	ctx context.Context,
+	leftJetID core.RecordID,
+	leaves map[core.RecordID]struct{},
+	currentPulse, newPulse core.PulseNumber,
+) ([]jetInfo, error) {
+	depth, prefix := jet.Jet(leftJetID)
+	if depth == 0 {
+		return nil, nil
+	}
+	parentID := jet.Parent(leftJetID)
+	_, parentPrefix := jet.Jet(parentID)
+	if !bytes.Equal(prefix, parentPrefix) {
+		// Merge is evaluated for left jet only.
+		return nil, nil
+	}
+	rightJetID := jet.Sibling(leftJetID)
+	if _, ok := leaves[rightJetID]; !ok {
+		// Sibling is split further.
+		return nil, nil
+	}
+	rightExecutor, err := m.JetCoordinator.LightExecutorForJet(ctx, rightJetID, currentPulse)
+	if err != nil {
+		return nil, err
+	}
+	me := m.JetCoordinator.Me()
+	if *rightExecutor != me {
+		return nil, nil
+	}
+
+	leftHistory, err := m.db.GetDropSizeHistory(ctx, leftJetID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch drop size history")
+	}
+	rightHistory, err := m.db.GetDropSizeHistory(ctx, rightJetID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch drop size history")
+	}
+	load, ok := m.balancer.shouldMerge(leftHistory, rightHistory)
+	if !ok {
+		return nil, nil
+	}
+
+	err = m.db.CollapseJetTree(ctx, newPulse, parentID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to collapse jet tree")
+	}
+	err = m.db.AddJets(ctx, parentID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to add jets")
+	}
+	// Set actual because we are the last executor for both jets.
+	err = m.db.UpdateJetTree(ctx, newPulse, true, parentID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to update tree")
+	}
+	// Jet could exist before, its stale history shouldn't affect the next decisions.
+	err = m.db.SetDropSizeHistory(ctx, parentID, jet.DropSizeHistory{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to reset drop size history")
+	}
+
+	m.RecentStorageProvider.GetStorage(ctx, rightJetID).DecreaseTTL(ctx)
+
+	parent := &jetInfo{id: parentID}
+	nextExecutor, err := m.JetCoordinator.LightExecutorForJet(ctx, parentID, newPulse)
+	if err != nil {
+		return nil, err
+	}
+	logger := inslogger.FromContext(ctx)
+	if *nextExecutor == me {
+		parent.mineNext = true
+		err := m.rewriteHotData(ctx, leftJetID, parentID)
+		logger.Debugf("[jet]: %v rewrite hot. Pulse: %v, Error: %s", parentID.DebugString(), currentPulse, err)
+		if err != nil {
+			return nil, err
+		}
+		err = m.mergeHotData(ctx, rightJetID, parentID)
+		logger.Debugf("[jet]: %v merge hot. Pulse: %v, Error: %s", parentID.DebugString(), currentPulse, err)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	logger.Debugf(
+		"MERGE HAPPENED parent: %v, left: %v, right: %v",
+		parentID.DebugString(),
+		leftJetID.DebugString(),
+		rightJetID.DebugString(),
+	)
+	m.balancer.record(ctx, core.JetDecision{
+		Pulse:    newPulse,
+		Action:   core.JetActionMerge,
+		From:     []core.RecordID{leftJetID, rightJetID},
+		To:       []core.RecordID{parentID},
+		DropSize: load.size,
+		Requests: load.requests,
+	})
+	return []jetInfo{
+		{id: leftJetID, parent: parent},
+		{id: rightJetID, parent: parent},
+	}, nil
+}
+
+func (m *PulseManager) recordJetTree(ctx context.Context, pulse core.PulseNumber) {
+	tree, err := m.db.GetJetTree(ctx, pulse)
+	if err != nil {
+		inslogger.FromContext(ctx).Error(errors.Wrap(err, "failed to fetch jet tree for metrics"))
+		return
+	}
+	jetIDs := tree.LeafIDs()
+	var maxDepth uint8
+	for _, jetID := range jetIDs {
+		if depth, _ := jet.Jet(jetID); depth > maxDepth {
+			maxDepth = depth
+		}
+	}
+	stats.Record(ctx, statJetCount.M(int64(len(jetIDs))), statJetMaxDepth.M(int64(maxDepth)))
+}
+
+// copyHotData copies indexes of recent objects and pending requests from one jet to another.
+func (m *PulseManager) copyHotData(ctx context.Context, fromJetID, toJetID core.RecordID) error {
 	recentStorage := m.RecentStorageProvider.GetStorage(ctx, fromJetID)
 
 	for id := range recentStorage.GetObjects() {
@@ -517,6 +726,14 @@ func (m *PulseManager) rewriteHotData(ctx context.Context, fromJetID, toJetID co
 			}
 		}
 	}
+	return nil
+}
+
+func (m *PulseManager) rewriteHotData(ctx context.Context, fromJetID, toJetID core.RecordID) error {
+	err := m.copyHotData(ctx, fromJetID, toJetID)
+	if err != nil {
+		return err
+	}
 
 	inslogger.FromContext(ctx).Debugf("{LEAK} CloneStorage from - %v, to - %v", fromJetID, toJetID)
 	m.RecentStorageProvider.CloneStorage(ctx, fromJetID, toJetID)
@@ -524,6 +741,26 @@ func (m *PulseManager) rewriteHotData(ctx context.Context, fromJetID, toJetID co
 	return nil
 }
 
+// mergeHotData adds hot data of one jet to recent storage of another, unlike rewriteHotData it keeps existing data.
+func (m *PulseManager) mergeHotData(ctx context.Context, fromJetID, toJetID core.RecordID) error {
+	err := m.copyHotData(ctx, fromJetID, toJetID)
+	if err != nil {
+		return err
+	}
+
+	from := m.RecentStorageProvider.GetStorage(ctx, fromJetID)
+	to := m.RecentStorageProvider.GetStorage(ctx, toJetID)
+	for id, ttl := range from.GetObjects() {
+		to.AddObjectWithTLL(ctx, id, ttl)
+	}
+	for objID, requests := range from.GetRequests() {
+		for reqID := range requests {
+			to.AddPendingRequest(ctx, objID, reqID)
+		}
+	}
+	return nil
+}
+
 // Set set's new pulse and closes current jet drop.
 func (m *PulseManager) Set(ctx context.Context, newPulse core.Pulse, persist bool) error {
 	m.setLock.Lock()
@@ -649,7 +886,14 @@ func (m *PulseManager) postProcessJets(ctx context.Context, newPulse core.Pulse,
 	defer span.End()
 
 	for _, jetInfo := range jets {
-		if jetInfo.left == nil && jetInfo.right == nil {
+		if jetInfo.parent != nil {
+			// Merge happened.
+			m.RecentStorageProvider.RemoveStorage(ctx, jetInfo.id)
+			if !jetInfo.parent.mineNext {
+				logger.Debugf("[postProcessJets] clear recent storage for merged jet - %v, pulse - %v", jetInfo.parent.id, newPulse.PulseNumber)
+				m.RecentStorageProvider.RemoveStorage(ctx, jetInfo.parent.id)
+			}
+		} else if jetInfo.left == nil && jetInfo.right == nil {
 			// No split happened.
 			if !jetInfo.mineNext {
 				logger.Debugf("[postProcessJets] clear recent storage for root jet - %v, pulse - %v", jetInfo.id, newPulse.PulseNumber)
@@ -744,7 +988,13 @@ func (m *PulseManager) prepareArtifactManagerMessageHandlerForNextPulse(ctx cont
 
 	for _, jetInfo := range jets {
 
-		if jetInfo.left == nil && jetInfo.right == nil {
+		if jetInfo.parent != nil {
+			// Merge happened.
+			if jetInfo.parent.mineNext {
+				logger.Debugf("[breakermiddleware] [prepareHandlerForNextPulse] fetch jetInfo merged %v, pulse - %v", jetInfo.parent.id.DebugString(), newPulse.PulseNumber)
+				m.ArtifactManagerMessageHandler.CloseEarlyRequestCircuitBreakerForJet(ctx, jetInfo.parent.id)
+			}
+		} else if jetInfo.left == nil && jetInfo.right == nil {
 			// No split happened.
 			if jetInfo.mineNext {
 				logger.Debugf("[breakermiddleware] [prepareHandlerForNextPulse] fetch jetInfo root %v, pulse - %v", jetInfo.id.DebugString(), newPulse.PulseNumber)
@@ -764,8 +1014,29 @@ func (m *PulseManager) prepareArtifactManagerMessageHandlerForNextPulse(ctx cont
 	}
 }
 
+// JetStatus returns jets of the latest pulse and recent split and merge decisions made by this node.
+func (m *PulseManager) JetStatus(ctx context.Context) (*core.JetStatus, error) {
+	pulse, err := m.db.GetLatestPulse(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ JetStatus ] Can't GetLatestPulse")
+	}
+	tree, err := m.db.GetJetTree(ctx, pulse.Pulse.PulseNumber)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ JetStatus ] Can't GetJetTree")
+	}
+	return &core.JetStatus{
+		Pulse:     pulse.Pulse.PulseNumber,
+		Jets:      tree.LeafIDs(),
+		Decisions: m.balancer.latest(),
+	}, nil
+}
+
 // Start starts pulse manager, spawns replication goroutine under a hood.
 func (m *PulseManager) Start(ctx context.Context) error {
+	if err := m.balancer.validate(); err != nil {
+		return errors.Wrap(err, "invalid jet balancer configuration")
+	}
+
 	// FIXME: @andreyromancev. 21.12.18. Find a proper place for me. Somewhere at the genesis.
 	err := m.db.SetActiveNodes(core.FirstPulseNumber, m.NodeNet.GetActiveNodes())
 	if err != nil && err != storage.ErrOverride {
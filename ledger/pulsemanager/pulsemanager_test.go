@@ -86,10 +86,13 @@ func TestPulseManager_Set_CheckHotIndexesSending(t *testing.T) {
 	mbMock.OnPulseFunc = func(context.Context, core.Pulse) error {
 		return nil
 	}
-	mbMock.SendFunc = func(p context.Context, p1 core.Message, p2 *core.MessageSendOptions) (r core.Reply, r1 error) {
+	mbMock.PublishFunc = func(p context.Context, p1 core.Message, p2 *core.MessageSendOptions) (r <-chan core.Delivery, r1 error) {
+		deliveries := make(chan core.Delivery)
+		close(deliveries)
+
 		val, ok := p1.(*message.HotData)
 		if !ok {
-			return nil, nil
+			return deliveries, nil
 		}
 
 		// Assert
@@ -105,7 +108,7 @@ func TestPulseManager_Set_CheckHotIndexesSending(t *testing.T) {
 		require.Equal(t, firstIndex, *decodedIndex)
 		require.Equal(t, 1, val.RecentObjects[*firstID].TTL)
 
-		return nil, nil
+		return deliveries, nil
 	}
 
 	nodeMock := network.NewNodeMock(t)
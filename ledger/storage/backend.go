@@ -0,0 +1,104 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package storage
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/insolar/insolar/configuration"
+	"github.com/pkg/errors"
+)
+
+// Backend is a key-value engine DB stores its data in.
+//
+// Keys are ordered bytewise, iteration returns keys in this order.
+type Backend interface {
+	// View runs read-only transaction.
+	View(fn func(txn BackendTxn) error) error
+	// Update runs read-write transaction and commits it if fn returns no error.
+	// Returns ErrConflict if transaction conflicts with concurrent one.
+	Update(fn func(txn BackendTxn) error) error
+	// Close flushes pending writes and releases backend resources.
+	Close() error
+}
+
+// BackendTxn is a transaction of Backend.
+type BackendTxn interface {
+	// Get returns copy of value by key or ErrNotFound.
+	Get(key []byte) ([]byte, error)
+	// Set stores value by key, fails in read-only transaction.
+	Set(key, value []byte) error
+	// Delete removes key, fails in read-only transaction.
+	Delete(key []byte) error
+	// Iterate calls handler for every key starting from start (inclusive) while key has provided prefix.
+	// Iteration stops if handler returns false or error. Key and value are copies and can be retained.
+	Iterate(prefix, start []byte, handler func(k, v []byte) (bool, error)) error
+}
+
+// BackendFactory opens backend configured by storage configuration.
+type BackendFactory func(conf configuration.Storage) (Backend, error)
+
+var (
+	backendsLock sync.RWMutex
+	backends     = map[string]BackendFactory{
+		BackendBadger: openBadgerBackend,
+		BackendBolt:   openBoltBackend,
+		BackendMemory: openMemoryBackend,
+	}
+)
+
+// Names of built-in backends.
+const (
+	BackendBadger = "badger"
+	BackendBolt   = "bolt"
+	BackendMemory = "memory"
+)
+
+// RegisterBackend makes backend available for configuration.Storage.Backend by name.
+func RegisterBackend(name string, factory BackendFactory) {
+	backendsLock.Lock()
+	defer backendsLock.Unlock()
+	backends[name] = factory
+}
+
+// Backends returns sorted names of registered backends.
+func Backends() []string {
+	backendsLock.RLock()
+	defer backendsLock.RUnlock()
+	names := make([]string, 0, len(backends))
+	for name := range backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// OpenBackend opens backend chosen by conf.Backend, badger is used if it is empty.
+func OpenBackend(conf configuration.Storage) (Backend, error) {
+	name := conf.Backend
+	if name == "" {
+		name = BackendBadger
+	}
+	backendsLock.RLock()
+	factory, ok := backends[name]
+	backendsLock.RUnlock()
+	if !ok {
+		return nil, errors.Errorf("unknown storage backend %q", name)
+	}
+	return factory(conf)
+}
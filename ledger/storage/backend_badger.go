@@ -0,0 +1,134 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package storage
+
+import (
+	"path/filepath"
+
+	"github.com/dgraph-io/badger"
+	"github.com/insolar/insolar/configuration"
+	"github.com/pkg/errors"
+)
+
+// badgerBackend is a Backend implementation on top of BadgerDB.
+type badgerBackend struct {
+	db *badger.DB
+}
+
+// NewBadgerBackend opens BadgerDB in dir with provided options, default options are used if opts is nil.
+func NewBadgerBackend(dir string, opts *badger.Options) (Backend, error) {
+	opts = setOptions(opts)
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	opts.Dir = dir
+	opts.ValueDir = dir
+
+	bdb, err := badger.Open(*opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "local database open failed")
+	}
+	return &badgerBackend{db: bdb}, nil
+}
+
+func openBadgerBackend(conf configuration.Storage) (Backend, error) {
+	return NewBadgerBackend(conf.DataDirectory, nil)
+}
+
+func setOptions(o *badger.Options) *badger.Options {
+	newo := &badger.Options{}
+	if o != nil {
+		*newo = *o
+	} else {
+		*newo = badger.DefaultOptions
+	}
+	return newo
+}
+
+func (b *badgerBackend) View(fn func(txn BackendTxn) error) error {
+	return b.db.View(func(txn *badger.Txn) error {
+		return fn(&badgerTxn{txn: txn})
+	})
+}
+
+func (b *badgerBackend) Update(fn func(txn BackendTxn) error) error {
+	err := b.db.Update(func(txn *badger.Txn) error {
+		return fn(&badgerTxn{txn: txn})
+	})
+	if err == badger.ErrConflict {
+		return ErrConflict
+	}
+	return err
+}
+
+// Close wraps BadgerDB Close method.
+//
+// From https://godoc.org/github.com/dgraph-io/badger#DB.Close:
+// «It's crucial to call it to ensure all the pending updates make their way to disk.
+// Calling DB.Close() multiple times is not safe and wouldcause panic.»
+func (b *badgerBackend) Close() error {
+	return b.db.Close()
+}
+
+type badgerTxn struct {
+	txn *badger.Txn
+}
+
+func (t *badgerTxn) Get(key []byte) ([]byte, error) {
+	item, err := t.txn.Get(key)
+	if err != nil {
+		if err == badger.ErrKeyNotFound {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return item.ValueCopy(nil)
+}
+
+func (t *badgerTxn) Set(key, value []byte) error {
+	return t.txn.Set(key, value)
+}
+
+func (t *badgerTxn) Delete(key []byte) error {
+	return t.txn.Delete(key)
+}
+
+func (t *badgerTxn) Iterate(prefix, start []byte, handler func(k, v []byte) (bool, error)) error {
+	it := t.txn.NewIterator(badger.DefaultIteratorOptions)
+	defer it.Close()
+
+	for it.Seek(start); it.ValidForPrefix(prefix); it.Next() {
+		item := it.Item()
+		if item == nil {
+			break
+		}
+		value, err := item.ValueCopy(nil)
+		if err != nil {
+			return err
+		}
+		next, err := handler(item.KeyCopy(nil), value)
+		if err != nil {
+			return err
+		}
+		if !next {
+			break
+		}
+	}
+	return nil
+}
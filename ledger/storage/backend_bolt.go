@@ -0,0 +1,117 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package storage
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	"github.com/insolar/insolar/configuration"
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+)
+
+// boltFileName is a name of database file in storage data directory.
+const boltFileName = "insolar.bolt"
+
+// boltBucket is a single bucket all keys are stored in, so keys keep bytewise order across scopes.
+var boltBucket = []byte("insolar")
+
+// boltBackend is a Backend implementation on top of bbolt.
+//
+// Update transactions are serialized by bbolt, so they never conflict.
+type boltBackend struct {
+	db *bolt.DB
+}
+
+// NewBoltBackend opens bbolt database file in dir, the file is created if it doesn't exist.
+func NewBoltBackend(dir string) (Backend, error) {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, errors.Wrap(err, "local database directory creation failed")
+	}
+	bdb, err := bolt.Open(filepath.Join(dir, boltFileName), 0644, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "local database open failed")
+	}
+	err = bdb.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(boltBucket)
+		return err
+	})
+	if err != nil {
+		bdb.Close() // nolint: errcheck
+		return nil, errors.Wrap(err, "local database bucket creation failed")
+	}
+	return &boltBackend{db: bdb}, nil
+}
+
+func openBoltBackend(conf configuration.Storage) (Backend, error) {
+	return NewBoltBackend(conf.DataDirectory)
+}
+
+func (b *boltBackend) View(fn func(txn BackendTxn) error) error {
+	return b.db.View(func(tx *bolt.Tx) error {
+		return fn(&boltTxn{bucket: tx.Bucket(boltBucket)})
+	})
+}
+
+func (b *boltBackend) Update(fn func(txn BackendTxn) error) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return fn(&boltTxn{bucket: tx.Bucket(boltBucket)})
+	})
+}
+
+func (b *boltBackend) Close() error {
+	return b.db.Close()
+}
+
+// boltTxn copies keys and values, because bbolt ones are only valid until transaction ends.
+type boltTxn struct {
+	bucket *bolt.Bucket
+}
+
+func (t *boltTxn) Get(key []byte) ([]byte, error) {
+	// Bucket Get returns nil for both missing key and empty value, cursor distinguishes them.
+	k, v := t.bucket.Cursor().Seek(key)
+	if k == nil || !bytes.Equal(k, key) {
+		return nil, ErrNotFound
+	}
+	return append([]byte{}, v...), nil
+}
+
+func (t *boltTxn) Set(key, value []byte) error {
+	return t.bucket.Put(key, value)
+}
+
+func (t *boltTxn) Delete(key []byte) error {
+	return t.bucket.Delete(key)
+}
+
+func (t *boltTxn) Iterate(prefix, start []byte, handler func(k, v []byte) (bool, error)) error {
+	c := t.bucket.Cursor()
+	for k, v := c.Seek(start); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+		next, err := handler(append([]byte(nil), k...), append([]byte{}, v...))
+		if err != nil {
+			return err
+		}
+		if !next {
+			break
+		}
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package storage
+
+import (
+	"bytes"
+	"runtime"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/insolar/insolar/configuration"
+	"github.com/pkg/errors"
+)
+
+// memoryBackend is a Backend implementation which keeps data in memory, it is intended for tests.
+//
+// Update transactions are serialized, so they never conflict. Lock isn't reentrant, thus update transaction started
+// inside another transaction in the same goroutine (or any transaction inside update one) fails with error instead of
+// deadlock. Nested read-only transactions reuse the lock of outer one.
+type memoryBackend struct {
+	lock sync.RWMutex
+	data map[string][]byte
+
+	activeLock sync.Mutex
+	// active holds goroutines running transactions, value tells if it is update transaction.
+	active map[uint64]bool
+}
+
+// NewMemoryBackend creates empty in-memory backend.
+func NewMemoryBackend() Backend {
+	return &memoryBackend{
+		data:   map[string][]byte{},
+		active: map[uint64]bool{},
+	}
+}
+
+var errNestedTxn = errors.New("update transaction can't be nested with another transaction")
+
+// enter marks current goroutine as running transaction and returns function that unmarks it. Returned nil function
+// means that read-only transaction is nested into another one and lock is already taken.
+func (b *memoryBackend) enter(update bool) (func(), error) {
+	id := goroutineID()
+	b.activeLock.Lock()
+	defer b.activeLock.Unlock()
+	if outerUpdate, ok := b.active[id]; ok {
+		if update || outerUpdate {
+			return nil, errNestedTxn
+		}
+		return nil, nil
+	}
+	b.active[id] = update
+	return func() {
+		b.activeLock.Lock()
+		delete(b.active, id)
+		b.activeLock.Unlock()
+	}, nil
+}
+
+// goroutineID parses id of current goroutine from its stack trace header "goroutine <id> [...".
+func goroutineID() uint64 {
+	buf := make([]byte, 64)
+	buf = buf[:runtime.Stack(buf, false)]
+	buf = bytes.TrimPrefix(buf, []byte("goroutine "))
+	id, _ := strconv.ParseUint(string(buf[:bytes.IndexByte(buf, ' ')]), 10, 64)
+	return id
+}
+
+func openMemoryBackend(configuration.Storage) (Backend, error) {
+	return NewMemoryBackend(), nil
+}
+
+func (b *memoryBackend) View(fn func(txn BackendTxn) error) error {
+	leave, err := b.enter(false)
+	if err != nil {
+		return err
+	}
+	if leave == nil {
+		return fn(&memoryTxn{backend: b})
+	}
+	defer leave()
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	return fn(&memoryTxn{backend: b})
+}
+
+func (b *memoryBackend) Update(fn func(txn BackendTxn) error) error {
+	leave, err := b.enter(true)
+	if err != nil {
+		return err
+	}
+	defer leave()
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	txn := &memoryTxn{backend: b, update: true, writes: map[string][]byte{}}
+	if err := fn(txn); err != nil {
+		return err
+	}
+	for k, v := range txn.writes {
+		if v == nil {
+			delete(b.data, k)
+			continue
+		}
+		b.data[k] = v
+	}
+	return nil
+}
+
+func (b *memoryBackend) Close() error {
+	return nil
+}
+
+type memoryTxn struct {
+	backend *memoryBackend
+	update  bool
+	// writes holds pending changes, nil value means removed key
+	writes map[string][]byte
+}
+
+var errReadOnlyTxn = errors.New("no writes are allowed in read-only transaction")
+
+func (t *memoryTxn) lookup(key string) ([]byte, bool) {
+	if v, ok := t.writes[key]; ok {
+		return v, v != nil
+	}
+	v, ok := t.backend.data[key]
+	return v, ok
+}
+
+func (t *memoryTxn) Get(key []byte) ([]byte, error) {
+	v, ok := t.lookup(string(key))
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return append([]byte(nil), v...), nil
+}
+
+func (t *memoryTxn) Set(key, value []byte) error {
+	if !t.update {
+		return errReadOnlyTxn
+	}
+	t.writes[string(key)] = append([]byte{}, value...)
+	return nil
+}
+
+func (t *memoryTxn) Delete(key []byte) error {
+	if !t.update {
+		return errReadOnlyTxn
+	}
+	t.writes[string(key)] = nil
+	return nil
+}
+
+func (t *memoryTxn) Iterate(prefix, start []byte, handler func(k, v []byte) (bool, error)) error {
+	var keys []string
+	collect := func(k string) {
+		kb := []byte(k)
+		if bytes.HasPrefix(kb, prefix) && bytes.Compare(kb, start) >= 0 {
+			keys = append(keys, k)
+		}
+	}
+	for k := range t.backend.data {
+		if _, ok := t.writes[k]; !ok {
+			collect(k)
+		}
+	}
+	for k, v := range t.writes {
+		if v != nil {
+			collect(k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		v, _ := t.lookup(k)
+		next, err := handler([]byte(k), append([]byte(nil), v...))
+		if err != nil {
+			return err
+		}
+		if !next {
+			break
+		}
+	}
+	return nil
+}
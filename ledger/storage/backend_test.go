@@ -0,0 +1,136 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package storage_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/insolar/insolar/configuration"
+	"github.com/insolar/insolar/ledger/storage"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackend_Conformance(t *testing.T) {
+	t.Parallel()
+	for _, name := range storage.Backends() {
+		name := name
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			tmpdir, err := ioutil.TempDir("", "backend-test-")
+			require.NoError(t, err)
+			defer os.RemoveAll(tmpdir)
+
+			backend, err := storage.OpenBackend(configuration.Storage{Backend: name, DataDirectory: tmpdir})
+			require.NoError(t, err)
+			defer backend.Close()
+
+			err = backend.Update(func(txn storage.BackendTxn) error {
+				for _, k := range []string{"a1", "a3", "a2", "b1"} {
+					if err := txn.Set([]byte(k), []byte("v"+k)); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+			require.NoError(t, err)
+
+			err = backend.Update(func(txn storage.BackendTxn) error {
+				if err := txn.Set([]byte("a4"), []byte("va4")); err != nil {
+					return err
+				}
+				return errors.New("rollback")
+			})
+			require.Error(t, err)
+
+			err = backend.Update(func(txn storage.BackendTxn) error {
+				return txn.Delete([]byte("a3"))
+			})
+			require.NoError(t, err)
+
+			err = backend.View(func(txn storage.BackendTxn) error {
+				v, err := txn.Get([]byte("a1"))
+				require.NoError(t, err)
+				assert.Equal(t, []byte("va1"), v)
+
+				_, err = txn.Get([]byte("a3"))
+				assert.Equal(t, storage.ErrNotFound, err)
+				_, err = txn.Get([]byte("a4"))
+				assert.Equal(t, storage.ErrNotFound, err)
+
+				var keys []string
+				err = txn.Iterate([]byte("a"), []byte("a"), func(k, v []byte) (bool, error) {
+					keys = append(keys, string(k))
+					return true, nil
+				})
+				require.NoError(t, err)
+				assert.Equal(t, []string{"a1", "a2"}, keys)
+
+				keys = nil
+				err = txn.Iterate([]byte("a"), []byte("a2"), func(k, v []byte) (bool, error) {
+					keys = append(keys, string(k))
+					return false, nil
+				})
+				require.NoError(t, err)
+				assert.Equal(t, []string{"a2"}, keys)
+				return nil
+			})
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestOpenBackend_Unknown(t *testing.T) {
+	t.Parallel()
+	_, err := storage.OpenBackend(configuration.Storage{Backend: "nosuchbackend"})
+	require.Error(t, err)
+}
+
+func TestMemoryBackend_NestedTxn(t *testing.T) {
+	t.Parallel()
+	backend := storage.NewMemoryBackend()
+
+	err := backend.View(func(storage.BackendTxn) error {
+		return backend.Update(func(storage.BackendTxn) error {
+			return nil
+		})
+	})
+	require.Error(t, err)
+
+	err = backend.Update(func(storage.BackendTxn) error {
+		return backend.View(func(storage.BackendTxn) error {
+			return nil
+		})
+	})
+	require.Error(t, err)
+
+	err = backend.View(func(storage.BackendTxn) error {
+		return backend.View(func(storage.BackendTxn) error {
+			return nil
+		})
+	})
+	require.NoError(t, err)
+
+	// Goroutine is released after nested transaction failure.
+	err = backend.Update(func(storage.BackendTxn) error {
+		return nil
+	})
+	require.NoError(t, err)
+}
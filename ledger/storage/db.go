@@ -19,10 +19,8 @@ package storage
 import (
 	"bytes"
 	"context"
-	"path/filepath"
 	"sync"
 
-	"github.com/dgraph-io/badger"
 	"github.com/insolar/insolar/configuration"
 	"github.com/insolar/insolar/core"
 	"github.com/insolar/insolar/core/message"
@@ -34,14 +32,20 @@ import (
 )
 
 const (
-	scopeIDLifeline byte = 1
-	scopeIDRecord   byte = 2
-	scopeIDJetDrop  byte = 3
-	scopeIDPulse    byte = 4
-	scopeIDSystem   byte = 5
-	scopeIDMessage  byte = 6
-	scopeIDBlob     byte = 7
-	scopeIDLocal    byte = 8
+	scopeIDLifeline  byte = 1
+	scopeIDRecord    byte = 2
+	scopeIDJetDrop   byte = 3
+	scopeIDPulse     byte = 4
+	scopeIDSystem    byte = 5
+	scopeIDMessage   byte = 6
+	scopeIDBlob      byte = 7
+	scopeIDLocal     byte = 8
+	scopeIDPrototype byte = 9
+	// records of jet drops on heavy, where records are stored without jet in keys
+	scopeIDDropRecord byte = 10
+	scopeIDDropSign   byte = 11
+	// reverse prototype index of objects, see IndexPrototypeObjects
+	scopeIDObjectPrototype byte = 12
 
 	sysGenesis                byte = 1
 	sysLatestPulse            byte = 2
@@ -50,13 +54,17 @@ const (
 	sysJetTree                byte = 5
 	sysJetList                byte = 6
 	sysDropSizeHistory        byte = 7
+	sysPruneQueue             byte = 8
+	sysPruneMark              byte = 9
+	sysHeavySyncCursor        byte = 10
+	sysPrototypeIndexBuilt    byte = 11
 )
 
-// DB represents BadgerDB storage implementation.
+// DB represents ledger storage on top of key-value Backend.
 type DB struct {
 	PlatformCryptographyScheme core.PlatformCryptographyScheme `inject:""`
 
-	db         *badger.DB
+	backend    Backend
 	genesisRef *core.RecordRef
 
 	// dropLock protects dropWG from concurrent calls to Add and Wait
@@ -98,35 +106,19 @@ func (db *DB) GetJetSizesHistoryDepth() int {
 	return db.jetSizesHistoryDepth
 }
 
-func setOptions(o *badger.Options) *badger.Options {
-	newo := &badger.Options{}
-	if o != nil {
-		*newo = *o
-	} else {
-		*newo = badger.DefaultOptions
-	}
-	return newo
-}
-
-// NewDB returns storage.DB with BadgerDB instance initialized by opts.
-// Creates database in provided dir or in current directory if dir parameter is empty.
-func NewDB(conf configuration.Ledger, opts *badger.Options) (*DB, error) {
-	opts = setOptions(opts)
-	dir, err := filepath.Abs(conf.Storage.DataDirectory)
-	if err != nil {
-		return nil, err
-	}
-
-	opts.Dir = dir
-	opts.ValueDir = dir
-
-	bdb, err := badger.Open(*opts)
-	if err != nil {
-		return nil, errors.Wrap(err, "local database open failed")
+// NewDB returns storage.DB on top of provided backend. If backend is nil, it is opened
+// according to conf.Storage (BadgerDB in conf.Storage.DataDirectory by default).
+func NewDB(conf configuration.Ledger, backend Backend) (*DB, error) {
+	if backend == nil {
+		var err error
+		backend, err = OpenBackend(conf.Storage)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	db := &DB{
-		db:                   bdb,
+		backend:              backend,
 		txretiries:           conf.Storage.TxRetriesOnConflict,
 		jetSizesHistoryDepth: conf.JetSizesHistoryDepth,
 		idlocker:             NewIDLocker(),
@@ -215,11 +207,7 @@ func (db *DB) GenesisRef() *core.RecordRef {
 	return db.genesisRef
 }
 
-// Close wraps BadgerDB Close method.
-//
-// From https://godoc.org/github.com/dgraph-io/badger#DB.Close:
-// «It's crucial to call it to ensure all the pending updates make their way to disk.
-// Calling DB.Close() multiple times is not safe and wouldcause panic.»
+// Close closes storage backend. Calling it multiple times returns ErrClosed.
 func (db *DB) Close() error {
 	db.closeLock.Lock()
 	defer db.closeLock.Unlock()
@@ -228,7 +216,7 @@ func (db *DB) Close() error {
 	}
 	db.isClosed = true
 
-	return db.db.Close()
+	return db.backend.Close()
 }
 
 // Stop stops DB component.
@@ -403,7 +391,7 @@ func (db *DB) Update(ctx context.Context, fn func(*TransactionManager) error) er
 		if err == nil {
 			break
 		}
-		if err != badger.ErrConflict {
+		if err != ErrConflict {
 			break
 		}
 		if tries < 1 {
@@ -422,9 +410,9 @@ func (db *DB) Update(ctx context.Context, fn func(*TransactionManager) error) er
 	return err
 }
 
-// GetBadgerDB return badger.DB instance (for internal usage, like tests)
-func (db *DB) GetBadgerDB() *badger.DB {
-	return db.db
+// GetBackend returns key-value backend of storage (for internal usage, like tests)
+func (db *DB) GetBackend() Backend {
+	return db.backend
 }
 
 // SetMessage persists message to the database
@@ -557,21 +545,9 @@ func (db *DB) iterate(
 		return ErrClosed
 	}
 
-	return db.db.View(func(txn *badger.Txn) error {
-		it := txn.NewIterator(badger.DefaultIteratorOptions)
-		defer it.Close()
-
-		for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
-			key := it.Item().KeyCopy(nil)[len(prefix):]
-			value, err := it.Item().ValueCopy(nil)
-			if err != nil {
-				return err
-			}
-			err = handler(key, value)
-			if err != nil {
-				return err
-			}
-		}
-		return nil
+	return db.backend.View(func(txn BackendTxn) error {
+		return txn.Iterate(prefix, prefix, func(k, v []byte) (bool, error) {
+			return true, handler(k[len(prefix):], v)
+		})
 	})
 }
@@ -0,0 +1,165 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package storage
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/ledger/storage/jet"
+	"github.com/pkg/errors"
+)
+
+// heavyJetPrefix is a jet prefix of records stored on heavy (see NullifyJetInKey).
+var heavyJetPrefix = make([]byte, core.RecordHashSize-1)
+
+// SetDropSignature saves signature of jet drop by node.
+func (db *DB) SetDropSignature(ctx context.Context, jetID core.RecordID, pulse core.PulseNumber, sign core.DropSignature) error {
+	_, prefix := jet.Jet(jetID)
+	return db.set(ctx, prefixkey(scopeIDDropSign, prefix, pulse.Bytes(), sign.Node[:]), sign.Signature)
+}
+
+// GetDropSignatures returns signatures of jet drop ordered by signer reference.
+func (db *DB) GetDropSignatures(ctx context.Context, jetID core.RecordID, pulse core.PulseNumber) ([]core.DropSignature, error) {
+	var signs []core.DropSignature
+	_, prefix := jet.Jet(jetID)
+	err := db.backend.View(func(txn BackendTxn) error {
+		var err error
+		signs, err = dropSignatures(txn, prefix, pulse)
+		return err
+	})
+	return signs, err
+}
+
+func dropSignatures(txn BackendTxn, jetPrefix []byte, pulse core.PulseNumber) ([]core.DropSignature, error) {
+	var signs []core.DropSignature
+	signPrefix := prefixkey(scopeIDDropSign, jetPrefix, pulse.Bytes())
+	err := txn.Iterate(signPrefix, signPrefix, func(k, v []byte) (bool, error) {
+		sign := core.DropSignature{Signature: append([]byte(nil), v...)}
+		copy(sign.Node[:], k[len(signPrefix):])
+		signs = append(signs, sign)
+		return true, nil
+	})
+	return signs, err
+}
+
+// IndexDropRecords updates index of jet drop records from replicated key/value pairs of the jet.
+//
+// Heavy stores records without jet in keys, so the index keeps ids of records of each synced jet drop
+// to recalculate drop records root and to build inclusion proofs. Replicated drop marks the drop as indexed.
+func (db *DB) IndexDropRecords(ctx context.Context, jetID core.RecordID, kvs []core.KV) error {
+	_, prefix := jet.Jet(jetID)
+	return db.backend.Update(func(txn BackendTxn) error {
+		for _, kv := range kvs {
+			switch {
+			case kv.K[0] == scopeIDRecord && len(kv.K) == core.RecordHashSize+core.RecordIDSize:
+				if err := txn.Set(prefixkey(scopeIDDropRecord, prefix, kv.K[core.RecordHashSize:]), nil); err != nil {
+					return err
+				}
+			case kv.K[0] == scopeIDJetDrop && len(kv.K) == core.RecordHashSize+core.PulseNumberSize:
+				if err := txn.Set(prefixkey(scopeIDDropRecord, prefix, pulseBytesFromKey(kv.K)), nil); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// iterateDropRecords calls handler for every record of jet drop in order of record ids.
+//
+// Records of drops indexed on heavy are fetched by index, other drops are built on the node and records
+// are stored with jet in keys.
+func iterateDropRecords(
+	txn BackendTxn,
+	jetPrefix []byte,
+	pulse core.PulseNumber,
+	handler func(id core.RecordID, rec []byte) error,
+) error {
+	indexPrefix := prefixkey(scopeIDDropRecord, jetPrefix, pulse.Bytes())
+	_, err := txn.Get(indexPrefix)
+	if err == ErrNotFound {
+		recordPrefix := prefixkey(scopeIDRecord, jetPrefix, pulse.Bytes())
+		return txn.Iterate(recordPrefix, recordPrefix, func(k, rec []byte) (bool, error) {
+			return true, handler(idFromKey(k), rec)
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	return txn.Iterate(indexPrefix, indexPrefix, func(k, _ []byte) (bool, error) {
+		// skip index mark
+		if len(k) == len(indexPrefix) {
+			return true, nil
+		}
+		id := idFromKey(k)
+		rec, err := txn.Get(prefixkey(scopeIDRecord, heavyJetPrefix, id[:]))
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to fetch drop record %v", id.DebugString())
+		}
+		return true, handler(id, rec)
+	})
+}
+
+// hasDropRecord checks that record belongs to jet drop.
+func hasDropRecord(txn BackendTxn, jetPrefix []byte, id core.RecordID) (bool, error) {
+	key := prefixkey(scopeIDRecord, jetPrefix, id[:])
+	if _, err := txn.Get(prefixkey(scopeIDDropRecord, jetPrefix, id.Pulse().Bytes())); err == nil {
+		key = prefixkey(scopeIDDropRecord, jetPrefix, id[:])
+	} else if err != ErrNotFound {
+		return false, err
+	}
+	_, err := txn.Get(key)
+	if err == ErrNotFound {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// CheckDrop recalculates records root and hash of stored jet drop and compares them with the drop.
+func (db *DB) CheckDrop(ctx context.Context, jetID core.RecordID, drop *jet.JetDrop) error {
+	_, prefix := jet.Jet(jetID)
+	var records [][]byte
+	err := db.backend.View(func(txn BackendTxn) error {
+		return iterateDropRecords(txn, prefix, drop.Pulse, func(_ core.RecordID, rec []byte) error {
+			records = append(records, append([]byte(nil), rec...))
+			return nil
+		})
+	})
+	if err != nil {
+		return err
+	}
+	if !checkDropHash(db.PlatformCryptographyScheme, drop, records) {
+		return errors.New("drop hash doesn't match drop records")
+	}
+	return nil
+}
+
+// checkDropHash checks records root and hash of drop, drops without records root are checked with legacy hash.
+func checkDropHash(scheme core.PlatformCryptographyScheme, drop *jet.JetDrop, records [][]byte) bool {
+	if len(drop.RecordsRoot) == 0 && len(records) > 0 {
+		return bytes.Equal(jet.LegacyDropHash(scheme, drop.PrevHash, records), drop.Hash)
+	}
+	leaves := make([][]byte, 0, len(records))
+	for _, rec := range records {
+		leaves = append(leaves, jet.MerkleLeaf(scheme, rec))
+	}
+	root := jet.MerkleRoot(scheme, leaves)
+	return bytes.Equal(root, drop.RecordsRoot) && bytes.Equal(jet.DropHash(scheme, drop.PrevHash, root), drop.Hash)
+}
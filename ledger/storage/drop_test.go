@@ -74,7 +74,7 @@ func TestStore_DropWaitWrites(t *testing.T) {
 		if droperr != nil {
 			panic(droperr)
 		}
-		require.NotEqual(t, 0, dropSize)
+		require.NotNil(t, dropSize)
 		dropFin = time.Now()
 		log.Debugln("end CreateDrop")
 		wg.Done()
@@ -18,8 +18,6 @@ package storage
 
 import (
 	"errors"
-
-	"github.com/dgraph-io/badger"
 )
 
 var (
@@ -29,8 +27,8 @@ var (
 	// ErrConflictRetriesOver is returned if Update transaction fails on all retry attempts.
 	ErrConflictRetriesOver = errors.New("transaction conflict retries limit exceeded")
 
-	// ErrConflict is the alias for badger.ErrConflict.
-	ErrConflict = badger.ErrConflict
+	// ErrConflict is returned by Backend if transaction conflicts with concurrent one.
+	ErrConflict = errors.New("transaction conflict, please retry")
 
 	// ErrOverride is returned if SetRecord tries to update existing record.
 	ErrOverride = errors.New("records override is forbidden")
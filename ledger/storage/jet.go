@@ -21,10 +21,10 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/dgraph-io/badger"
 	"github.com/insolar/insolar/core"
 	"github.com/insolar/insolar/instrumentation/inslogger"
 	"github.com/insolar/insolar/ledger/storage/jet"
+	"github.com/insolar/insolar/ledger/storage/record"
 	"github.com/pkg/errors"
 	"github.com/ugorji/go/codec"
 )
@@ -47,22 +47,16 @@ func (db *DB) GetDrop(ctx context.Context, jetID core.RecordID, pulse core.Pulse
 
 // CreateDrop creates and stores jet drop for given pulse number.
 //
-// On success returns saved drop object, slot records, drop size.
+// On success returns saved drop object, slot records, drop size stats (without signature).
 func (db *DB) CreateDrop(ctx context.Context, jetID core.RecordID, pulse core.PulseNumber, prevHash []byte) (
 	*jet.JetDrop,
 	[][]byte,
-	uint64,
+	*jet.DropSize,
 	error,
 ) {
 	var err error
 	db.waitinflight()
 
-	hw := db.PlatformCryptographyScheme.ReferenceHasher()
-	_, err = hw.Write(prevHash)
-	if err != nil {
-		return nil, nil, 0, err
-	}
-
 	var messages [][]byte
 	_, jetPrefix := jet.Jet(jetID)
 	// messagesPrefix := prefixkey(scopeIDMessage, jetPrefix, pulse.Bytes())
@@ -81,38 +75,35 @@ func (db *DB) CreateDrop(ctx context.Context, jetID core.RecordID, pulse core.Pu
 	// 	return nil
 	// })
 	// if err != nil {
-	// 	return nil, nil, 0, err
+	// 	return nil, nil, nil, err
 	// }
 
-	var dropSize uint64
+	var leaves [][]byte
+	dropSize := jet.DropSize{JetID: jetID, PulseNo: pulse}
+	requestType := record.SerializeType((&record.RequestRecord{}).Type())
 	recordPrefix := prefixkey(scopeIDRecord, jetPrefix, pulse.Bytes())
-	err = db.db.View(func(txn *badger.Txn) error {
-		it := txn.NewIterator(badger.DefaultIteratorOptions)
-		defer it.Close()
-
-		for it.Seek(recordPrefix); it.ValidForPrefix(recordPrefix); it.Next() {
-			val, err := it.Item().ValueCopy(nil)
-			if err != nil {
-				return err
+	err = db.backend.View(func(txn BackendTxn) error {
+		return txn.Iterate(recordPrefix, recordPrefix, func(k, val []byte) (bool, error) {
+			leaves = append(leaves, jet.MerkleLeaf(db.PlatformCryptographyScheme, val))
+			dropSize.DropSize += uint64(len(val))
+			if bytes.HasPrefix(val, requestType) {
+				dropSize.Requests++
 			}
-			_, err = hw.Write(val)
-			if err != nil {
-				return err
-			}
-			dropSize += uint64(len(val))
-		}
-		return nil
+			return true, nil
+		})
 	})
 	if err != nil {
-		return nil, nil, 0, err
+		return nil, nil, nil, err
 	}
 
+	root := jet.MerkleRoot(db.PlatformCryptographyScheme, leaves)
 	drop := jet.JetDrop{
-		Pulse:    pulse,
-		PrevHash: prevHash,
-		Hash:     hw.Sum(nil),
+		Pulse:       pulse,
+		PrevHash:    prevHash,
+		RecordsRoot: root,
+		Hash:        jet.DropHash(db.PlatformCryptographyScheme, prevHash, root),
 	}
-	return &drop, messages, dropSize, nil
+	return &drop, messages, &dropSize, nil
 }
 
 // SetDrop saves provided JetDrop in db.
@@ -206,6 +197,24 @@ func (db *DB) SplitJetTree(
 	return left, right, nil
 }
 
+// CollapseJetTree performs jet merge, removing branches of provided jet.
+func (db *DB) CollapseJetTree(ctx context.Context, pulse core.PulseNumber, jetID core.RecordID) error {
+	db.jetTreeLock.Lock()
+	defer db.jetTreeLock.Unlock()
+
+	k := prefixkey(scopeIDSystem, []byte{sysJetTree}, pulse.Bytes())
+	tree, err := db.getJetTree(ctx, pulse)
+	if err != nil {
+		return err
+	}
+
+	err = tree.Collapse(jetID)
+	if err != nil {
+		return err
+	}
+	return db.set(ctx, k, tree.Bytes())
+}
+
 // CloneJetTree copies tree from one pulse to another. Use it to copy past tree into new pulse.
 func (db *DB) CloneJetTree(
 	ctx context.Context, from, to core.PulseNumber,
@@ -29,6 +29,13 @@ type JetDrop struct {
 	// PrevHash is a hash of all record hashes belongs to previous pulse.
 	PrevHash []byte
 
-	// Hash is a hash of all record hashes belongs to one pulse and previous drop hash.
+	// RecordsRoot is a Merkle root over records belongs to one pulse (see MerkleRoot).
+	RecordsRoot []byte
+
+	// Hash is a hash of previous drop hash and records root (see DropHash).
+	//
+	// Drops stored before RecordsRoot was introduced have no root and their hash is a hash of previous drop
+	// hash and all records (see LegacyDropHash). Such drops are verified with the legacy hash, but records
+	// inclusion can't be proved for them. Hash of empty drop is the same in both formats.
 	Hash []byte
 }
@@ -37,9 +37,11 @@ func encode(data interface{}) []byte {
 
 // DropSize contains info about size of drop
 type DropSize struct {
-	JetID     core.RecordID
-	PulseNo   core.PulseNumber
-	DropSize  uint64
+	JetID    core.RecordID
+	PulseNo  core.PulseNumber
+	DropSize uint64
+	// Requests is a number of requests registered in the drop.
+	Requests  uint64
 	Signature []byte
 }
 
@@ -56,6 +58,10 @@ func (ds *DropSize) serializeDropSize() []byte {
 
 	result = append(result, ds.JetID.Bytes()...)
 
+	buff = make([]byte, 8)
+	binary.LittleEndian.PutUint64(buff, ds.Requests)
+	result = append(result, buff...)
+
 	return result
 }
 
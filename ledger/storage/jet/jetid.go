@@ -69,3 +69,32 @@ func Parent(id core.RecordID) core.RecordID {
 
 	return *NewID(depth-1, ResetBits(prefix, depth-1))
 }
+
+// Sibling returns the other branch of jet parent. Root jet has no sibling, so it is returned as is.
+func Sibling(id core.RecordID) core.RecordID {
+	depth, prefix := Jet(id)
+	if depth == 0 {
+		return id
+	}
+
+	sibling := ResetBits(prefix, depth-1)
+	if !getBit(prefix, depth-1) {
+		sibling = append([]byte(nil), sibling...)
+		setBit(sibling, depth-1)
+	}
+	return *NewID(depth, sibling)
+}
+
+// Children returns branches jet is split into (see Tree.Split). Jet of the maximum depth can't be split, false is
+// returned for it.
+func Children(id core.RecordID) (core.RecordID, core.RecordID, bool) {
+	depth, prefix := Jet(id)
+	if int(depth) >= len(prefix)*8 {
+		return id, id, false
+	}
+
+	left := ResetBits(prefix, depth)
+	right := append([]byte(nil), left...)
+	setBit(right, depth)
+	return *NewID(depth+1, left), *NewID(depth+1, right), true
+}
@@ -0,0 +1,127 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package jet
+
+import (
+	"bytes"
+
+	"github.com/insolar/insolar/core"
+)
+
+// Prefixes separate leaves from inner nodes, so inner node can't be passed off as a record. Merge prefix separates
+// previous hash of merged jet from inner nodes.
+const (
+	merkleLeafPrefix  byte = 0
+	merkleNodePrefix  byte = 1
+	merkleMergePrefix byte = 2
+)
+
+// MerkleLeaf returns hash of Merkle tree leaf for serialized record.
+func MerkleLeaf(scheme core.PlatformCryptographyScheme, record []byte) []byte {
+	return merkleHash(scheme, []byte{merkleLeafPrefix}, record)
+}
+
+func merkleNode(scheme core.PlatformCryptographyScheme, left, right []byte) []byte {
+	return merkleHash(scheme, []byte{merkleNodePrefix}, left, right)
+}
+
+func merkleHash(scheme core.PlatformCryptographyScheme, parts ...[]byte) []byte {
+	hasher := scheme.ReferenceHasher()
+	for _, part := range parts {
+		if _, err := hasher.Write(part); err != nil {
+			panic(err)
+		}
+	}
+	return hasher.Sum(nil)
+}
+
+// merkleLevel hashes pairs of nodes, the last odd node is promoted to the next level as is.
+func merkleLevel(scheme core.PlatformCryptographyScheme, nodes [][]byte) [][]byte {
+	next := make([][]byte, 0, (len(nodes)+1)/2)
+	for i := 0; i < len(nodes); i += 2 {
+		if i+1 == len(nodes) {
+			next = append(next, nodes[i])
+			continue
+		}
+		next = append(next, merkleNode(scheme, nodes[i], nodes[i+1]))
+	}
+	return next
+}
+
+// MerkleRoot calculates root of Merkle tree over provided leaves. Root of empty tree is nil.
+func MerkleRoot(scheme core.PlatformCryptographyScheme, leaves [][]byte) []byte {
+	if len(leaves) == 0 {
+		return nil
+	}
+	for len(leaves) > 1 {
+		leaves = merkleLevel(scheme, leaves)
+	}
+	return leaves[0]
+}
+
+// MerkleProof returns path from leaf with provided index to the root of Merkle tree over provided leaves.
+func MerkleProof(scheme core.PlatformCryptographyScheme, leaves [][]byte, index int) []core.MerkleProofStep {
+	var path []core.MerkleProofStep
+	for len(leaves) > 1 {
+		sibling := index ^ 1
+		if sibling < len(leaves) {
+			path = append(path, core.MerkleProofStep{Hash: leaves[sibling], Left: sibling < index})
+		}
+		leaves = merkleLevel(scheme, leaves)
+		index /= 2
+	}
+	return path
+}
+
+// VerifyMerkleProof checks that serialized record is a leaf of Merkle tree with provided root.
+func VerifyMerkleProof(
+	scheme core.PlatformCryptographyScheme, record []byte, path []core.MerkleProofStep, root []byte,
+) bool {
+	hash := MerkleLeaf(scheme, record)
+	for _, step := range path {
+		if step.Left {
+			hash = merkleNode(scheme, step.Hash, hash)
+		} else {
+			hash = merkleNode(scheme, hash, step.Hash)
+		}
+	}
+	return bytes.Equal(hash, root)
+}
+
+// DropHash calculates drop hash from previous drop hash and root of drop records.
+func DropHash(scheme core.PlatformCryptographyScheme, prevHash, recordsRoot []byte) []byte {
+	return merkleHash(scheme, prevHash, recordsRoot)
+}
+
+// MergedPrevHash calculates previous drop hash for the first drop of merged jet. It commits to the last drops of both
+// merged branches, so neither of them can be replaced later.
+func MergedPrevHash(scheme core.PlatformCryptographyScheme, leftHash, rightHash []byte) []byte {
+	return merkleHash(scheme, []byte{merkleMergePrefix}, leftHash, rightHash)
+}
+
+// DropSignData calculates data signed by jet executors to confirm jet drop.
+//
+// Jet is identified by its prefix (see Jet), prefixes of jets of one pulse don't collide.
+func DropSignData(scheme core.PlatformCryptographyScheme, jetPrefix []byte, pulse core.PulseNumber, dropHash []byte) []byte {
+	return merkleHash(scheme, jetPrefix, pulse.Bytes(), dropHash)
+}
+
+// LegacyDropHash calculates drop hash of drops without records root, it is a hash of previous drop hash
+// and serialized records. Such drops are created before records root was added and have no inclusion proofs.
+func LegacyDropHash(scheme core.PlatformCryptographyScheme, prevHash []byte, records [][]byte) []byte {
+	return merkleHash(scheme, append([][]byte{prevHash}, records...)...)
+}
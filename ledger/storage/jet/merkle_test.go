@@ -0,0 +1,68 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package jet
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/insolar/insolar/platformpolicy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerkleProof(t *testing.T) {
+	scheme := platformpolicy.NewPlatformCryptographyScheme()
+	assert.Nil(t, MerkleRoot(scheme, nil))
+
+	for count := 1; count <= 7; count++ {
+		var (
+			records [][]byte
+			leaves  [][]byte
+		)
+		for i := 0; i < count; i++ {
+			rec := []byte(fmt.Sprintf("record %d", i))
+			records = append(records, rec)
+			leaves = append(leaves, MerkleLeaf(scheme, rec))
+		}
+		root := MerkleRoot(scheme, leaves)
+
+		for i, rec := range records {
+			path := MerkleProof(scheme, leaves, i)
+			assert.True(t, VerifyMerkleProof(scheme, rec, path, root), "records: %d, index: %d", count, i)
+			assert.False(t, VerifyMerkleProof(scheme, []byte("forged"), path, root))
+		}
+	}
+}
+
+func TestMerkleProof_InnerNodeIsNotLeaf(t *testing.T) {
+	scheme := platformpolicy.NewPlatformCryptographyScheme()
+	leaves := [][]byte{MerkleLeaf(scheme, []byte("a")), MerkleLeaf(scheme, []byte("b"))}
+	root := MerkleRoot(scheme, leaves)
+
+	inner := append(append([]byte{}, leaves[0]...), leaves[1]...)
+	assert.False(t, VerifyMerkleProof(scheme, inner, nil, root))
+}
+
+func TestMergedPrevHash(t *testing.T) {
+	scheme := platformpolicy.NewPlatformCryptographyScheme()
+	left, right := []byte("left"), []byte("right")
+
+	merged := MergedPrevHash(scheme, left, right)
+	assert.NotEqual(t, merged, MergedPrevHash(scheme, right, left))
+	assert.NotEqual(t, merged, merkleNode(scheme, left, right))
+	assert.NotEqual(t, merged, MergedPrevHash(scheme, left, []byte("forged")))
+}
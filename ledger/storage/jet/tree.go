@@ -50,11 +50,14 @@ func (j *jet) Find(val []byte, depth uint8) (*jet, uint8) {
 	return j, depth
 }
 
-// Update add missing tree branches for provided prefix.
+// Update add missing tree branches for provided prefix. Actual jet is a leaf, so its branches are removed (they are
+// left after merge).
 func (j *jet) Update(prefix []byte, setActual bool, maxDepth, depth uint8) {
 	if depth == maxDepth {
 		if setActual {
 			j.Actual = true
+			j.Left = nil
+			j.Right = nil
 		}
 		return
 	}
@@ -247,7 +250,7 @@ func (t *Tree) Find(id core.RecordID) (*core.RecordID, bool) {
 }
 
 // Update add missing tree branches for provided prefix. If 'setActual' is set, all encountered nodes will be marked as
-// actual.
+// actual and branches of provided jet will be removed.
 func (t *Tree) Update(id core.RecordID, setActual bool) {
 	maxDepth, prefix := Jet(id)
 	t.Head.Update(prefix, setActual, maxDepth, 0)
@@ -281,6 +284,30 @@ func (t *Tree) Split(jetID core.RecordID) (*core.RecordID, *core.RecordID, error
 	return NewID(depth+1, leftPrefix), NewID(depth+1, rightPrefix), nil
 }
 
+// Collapse removes both branches of provided jet, reverting Split. Branches must be leaves, otherwise an error will be
+// returned.
+func (t *Tree) Collapse(jetID core.RecordID) error {
+	depth, prefix := Jet(jetID)
+	j := t.Head
+	for d := uint8(0); d < depth && j != nil; d++ {
+		if getBit(prefix, d) {
+			j = j.Right
+		} else {
+			j = j.Left
+		}
+	}
+	if j == nil || !isLeaf(j.Left) || !isLeaf(j.Right) {
+		return errors.New("failed to collapse: incorrect jet provided")
+	}
+	j.Left = nil
+	j.Right = nil
+	return nil
+}
+
+func isLeaf(j *jet) bool {
+	return j != nil && j.Left == nil && j.Right == nil
+}
+
 // ResetActual resets actual mark, which will signify uncertain state on nodes and require actualization.
 func (t *Tree) ResetActual() {
 	t.Head.ResetActual()
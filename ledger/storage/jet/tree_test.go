@@ -125,6 +125,18 @@ func TestTree_Split(t *testing.T) {
 		assert.Equal(t, uint8(okDepth+1), rDepth)
 		assert.Equal(t, lExpectedPrefix, lPrefix)
 		assert.Equal(t, rExpectedPrefix, rPrefix)
+
+		lChild, rChild, canSplit := Children(*ok)
+		require.True(t, canSplit)
+		assert.Equal(t, *left, lChild)
+		assert.Equal(t, *right, rChild)
+		assert.Equal(t, lChild, Sibling(rChild))
+	})
+
+	t.Run("jet of max depth has no children", func(t *testing.T) {
+		_, prefix := Jet(*ok)
+		_, _, canSplit := Children(*NewID(uint8(len(prefix)*8), prefix))
+		assert.False(t, canSplit)
 	})
 }
 
@@ -465,3 +477,47 @@ func TestTree_LeafIDs(t *testing.T) {
 	assert.Equal(t, leafIDs[2], *NewID(4, []byte{0xD0})) // 1101
 	assert.Equal(t, leafIDs[3], *NewID(3, []byte{0xE0})) // 1110
 }
+
+func TestTree_Collapse(t *testing.T) {
+	tree := NewTree(true)
+	left, right, err := tree.Split(*NewID(0, nil))
+	require.NoError(t, err)
+	assert.Equal(t, *right, Sibling(*left))
+	assert.Equal(t, *left, Sibling(*right))
+	rightLeft, _, err := tree.Split(*right)
+	require.NoError(t, err)
+
+	t.Run("branches are not leaves", func(t *testing.T) {
+		err := tree.Collapse(*NewID(0, nil))
+		assert.Error(t, err)
+	})
+
+	t.Run("leaf jet", func(t *testing.T) {
+		err := tree.Collapse(*rightLeft)
+		assert.Error(t, err)
+	})
+
+	t.Run("collapses jet", func(t *testing.T) {
+		err := tree.Collapse(*right)
+		require.NoError(t, err)
+		assert.Equal(t, []core.RecordID{*left, *right}, tree.LeafIDs())
+
+		err = tree.Collapse(*NewID(0, nil))
+		require.NoError(t, err)
+		assert.Equal(t, []core.RecordID{*NewID(0, nil)}, tree.LeafIDs())
+	})
+}
+
+func TestTree_Update_ActualRemovesBranches(t *testing.T) {
+	tree := NewTree(false)
+	left, right, err := tree.Split(*NewID(0, nil))
+	require.NoError(t, err)
+
+	tree.Update(*left, true)
+	assert.Equal(t, []core.RecordID{*left, *right}, tree.LeafIDs())
+
+	tree.Update(*NewID(0, nil), true)
+	assert.Equal(t, []core.RecordID{*NewID(0, nil)}, tree.LeafIDs())
+	_, actual := tree.Find(*core.NewRecordID(core.FirstPulseNumber, []byte{0xFF}))
+	assert.True(t, actual)
+}
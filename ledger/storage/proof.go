@@ -0,0 +1,136 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package storage
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/ledger/storage/jet"
+	"github.com/pkg/errors"
+)
+
+// GetRecordProof builds proof of record inclusion into jet drop of record pulse.
+//
+// Drop prefixes are scanned to find the jet record belongs to. ErrNotFound is returned
+// if record is not stored or its drop is not created yet.
+func (db *DB) GetRecordProof(ctx context.Context, id core.RecordID) (*core.RecordProof, error) {
+	var proof *core.RecordProof
+	err := db.backend.View(func(txn BackendTxn) error {
+		jetPrefix, drop, err := findRecordDrop(txn, id)
+		if err != nil {
+			return err
+		}
+
+		var (
+			leaves [][]byte
+			rec    []byte
+			index  = -1
+		)
+		err = iterateDropRecords(txn, jetPrefix, id.Pulse(), func(recID core.RecordID, val []byte) error {
+			if recID == id {
+				index = len(leaves)
+				rec = val
+			}
+			leaves = append(leaves, jet.MerkleLeaf(db.PlatformCryptographyScheme, val))
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if index < 0 {
+			return ErrNotFound
+		}
+		if len(drop.RecordsRoot) == 0 {
+			return errors.New("drop has legacy format without records root")
+		}
+
+		signs, err := dropSignatures(txn, jetPrefix, id.Pulse())
+		if err != nil {
+			return errors.Wrap(err, "failed to fetch drop signatures")
+		}
+
+		buf, err := txn.Get(prefixkey(scopeIDPulse, id.Pulse().Bytes()))
+		if err != nil {
+			return errors.Wrap(err, "failed to fetch pulse")
+		}
+		pulse, err := toPulse(buf)
+		if err != nil {
+			return err
+		}
+
+		proof = &core.RecordProof{
+			Record:      rec,
+			ID:          id,
+			Path:        jet.MerkleProof(db.PlatformCryptographyScheme, leaves, index),
+			RecordsRoot: drop.RecordsRoot,
+			PrevHash:    drop.PrevHash,
+			DropHash:    drop.Hash,
+			JetPrefix:   append([]byte(nil), jetPrefix...),
+			Signatures:  signs,
+			Pulse:       pulse.Pulse,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return proof, nil
+}
+
+// findRecordDrop looks for jet prefix which has both record and drop of record pulse.
+func findRecordDrop(txn BackendTxn, id core.RecordID) ([]byte, *jet.JetDrop, error) {
+	dropScope := []byte{scopeIDJetDrop}
+	// Pulse bytes are followed by this suffix to skip all drops of prefix.
+	skipPrefix := bytes.Repeat([]byte{0xFF}, core.PulseNumberSize+1)
+	start := dropScope
+	for {
+		var jetPrefix []byte
+		err := txn.Iterate(dropScope, start, func(k, _ []byte) (bool, error) {
+			jetPrefix = append([]byte(nil), k[1:len(k)-core.PulseNumberSize]...)
+			return false, nil
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		if jetPrefix == nil {
+			return nil, nil, ErrNotFound
+		}
+		start = prefixkey(scopeIDJetDrop, jetPrefix, skipPrefix)
+
+		buf, err := txn.Get(prefixkey(scopeIDJetDrop, jetPrefix, id.Pulse().Bytes()))
+		if err == ErrNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		found, err := hasDropRecord(txn, jetPrefix, id)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !found {
+			continue
+		}
+		drop, err := jet.Decode(buf)
+		if err != nil {
+			return nil, nil, err
+		}
+		return jetPrefix, drop, nil
+	}
+}
@@ -0,0 +1,120 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package storage_test
+
+import (
+	"bytes"
+	"sort"
+	"testing"
+
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/instrumentation/inslogger"
+	"github.com/insolar/insolar/ledger/storage"
+	"github.com/insolar/insolar/ledger/storage/jet"
+	"github.com/insolar/insolar/ledger/storage/record"
+	"github.com/insolar/insolar/ledger/storage/storagetest"
+	"github.com/insolar/insolar/platformpolicy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDB_GetRecordProof(t *testing.T) {
+	t.Parallel()
+	ctx := inslogger.TestContext(t)
+	db, cleaner := storagetest.TmpDB(ctx, t)
+	defer cleaner()
+	scheme := platformpolicy.NewPlatformCryptographyScheme()
+
+	root := *jet.NewID(0, nil)
+	pn := core.PulseNumber(core.FirstPulseNumber + 10)
+	require.NoError(t, db.AddPulse(ctx, core.Pulse{PulseNumber: pn, Entropy: core.Entropy{1, 2, 3}}))
+	left, right, err := jet.NewTree(true).Split(root)
+	require.NoError(t, err)
+	require.NoError(t, db.UpdateJetTree(ctx, pn, true, *left, *right))
+
+	var ids []*core.RecordID
+	for i := 0; i < 3; i++ {
+		id, err := db.SetRecord(ctx, *right, pn, &record.RequestRecord{Payload: []byte{byte(i)}})
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+	addRecords(ctx, t, db, *left, pn)
+
+	_, err = db.GetRecordProof(ctx, *ids[0])
+	assert.Equal(t, storage.ErrNotFound, err, "drop is not created yet")
+
+	prevHash := []byte("previous hash")
+	drop, _, _, err := db.CreateDrop(ctx, *right, pn, prevHash)
+	require.NoError(t, err)
+	require.NoError(t, db.SetDrop(ctx, *right, drop))
+
+	for _, id := range ids {
+		proof, err := db.GetRecordProof(ctx, *id)
+		require.NoError(t, err)
+		assert.Equal(t, *id, proof.ID)
+		assert.Equal(t, pn, proof.Pulse.PulseNumber)
+		assert.Equal(t, core.Entropy{1, 2, 3}, proof.Pulse.Entropy)
+		assert.Equal(t, prevHash, proof.PrevHash)
+		assert.Equal(t, drop.Hash, proof.DropHash)
+		assert.True(t, jet.VerifyMerkleProof(scheme, proof.Record, proof.Path, proof.RecordsRoot))
+		assert.Equal(t, proof.DropHash, jet.DropHash(scheme, proof.PrevHash, proof.RecordsRoot))
+	}
+
+	_, err = db.GetRecordProof(ctx, *core.NewRecordID(pn, []byte("unknown")))
+	assert.Equal(t, storage.ErrNotFound, err)
+}
+
+func TestDB_LegacyDrop(t *testing.T) {
+	t.Parallel()
+	ctx := inslogger.TestContext(t)
+	db, cleaner := storagetest.TmpDB(ctx, t)
+	defer cleaner()
+	scheme := platformpolicy.NewPlatformCryptographyScheme()
+
+	jetID := core.TODOJetID
+	pn := core.PulseNumber(core.FirstPulseNumber + 10)
+	var ids []core.RecordID
+	for i := 0; i < 3; i++ {
+		id, err := db.SetRecord(ctx, jetID, pn, &record.RequestRecord{Payload: []byte{byte(i)}})
+		require.NoError(t, err)
+		ids = append(ids, *id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return bytes.Compare(ids[i][:], ids[j][:]) < 0 })
+	var records [][]byte
+	for _, id := range ids {
+		rec, err := db.GetRecord(ctx, jetID, &id)
+		require.NoError(t, err)
+		records = append(records, record.SerializeRecord(rec))
+	}
+
+	// drop stored before records root was introduced
+	prevHash := []byte("previous hash")
+	require.NoError(t, db.SetDrop(ctx, jetID, &jet.JetDrop{
+		Pulse:    pn,
+		PrevHash: prevHash,
+		Hash:     jet.LegacyDropHash(scheme, prevHash, records),
+	}))
+
+	verification, err := db.VerifyDrops(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, verification.Checked)
+	assert.Empty(t, verification.Mismatched)
+
+	_, err = db.GetRecordProof(ctx, ids[0])
+	require.Error(t, err)
+	assert.NotEqual(t, storage.ErrNotFound, err)
+}
@@ -0,0 +1,219 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package storage
+
+import (
+	"context"
+
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/instrumentation/inslogger"
+	"github.com/insolar/insolar/ledger/storage/index"
+	"github.com/insolar/insolar/ledger/storage/record"
+	"github.com/pkg/errors"
+)
+
+// prototypeIndexBatchSize is a max number of key/value pairs indexed in one transaction by BuildPrototypeIndex.
+const prototypeIndexBatchSize = 1000
+
+func prototypeIndexKey(prototype core.RecordID, object *core.RecordID) []byte {
+	if object == nil {
+		return prefixkey(scopeIDPrototype, prototype[:])
+	}
+	return prefixkey(scopeIDPrototype, prototype[:], object[:])
+}
+
+// objectPrototypeKey is a key of reverse index entry which holds prototype of activated object. Empty value
+// marks deactivated object.
+func objectPrototypeKey(object core.RecordID) []byte {
+	return prefixkey(scopeIDObjectPrototype, object[:])
+}
+
+// IndexPrototypeObjects updates prototype index from provided replicated key/value pairs.
+//
+// Activated instances are added to index of their prototype. Objects which lifelines point to deactivation
+// record are removed from it. Prototype of object is taken from reverse index entry stored on activation, and
+// deactivated objects are marked there, so records and lifelines may come in any order and in any chunks.
+func (db *DB) IndexPrototypeObjects(ctx context.Context, kvs []core.KV) error {
+	return db.backend.Update(func(txn BackendTxn) error {
+		for _, kv := range kvs {
+			if len(kv.K) != core.RecordHashSize+core.RecordIDSize {
+				continue
+			}
+			var err error
+			switch kv.K[0] {
+			case scopeIDRecord:
+				activate, ok := record.DeserializeRecord(kv.V).(*record.ObjectActivateRecord)
+				if !ok || activate.IsPrototype {
+					continue
+				}
+				err = indexActivatedObject(txn, activate)
+			case scopeIDLifeline:
+				var object core.RecordID
+				copy(object[:], kv.K[core.RecordHashSize:])
+				err = indexLifeline(txn, object, kv.V)
+				if err != nil {
+					err = errors.Wrapf(err, "failed to index object %v", object.DebugString())
+				}
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func indexActivatedObject(txn BackendTxn, activate *record.ObjectActivateRecord) error {
+	object := *activate.Request.Record()
+	prototype := *activate.Image.Record()
+	_, err := txn.Get(objectPrototypeKey(object))
+	if err == nil {
+		// Object is already indexed or deactivated.
+		return nil
+	}
+	if err != ErrNotFound {
+		return err
+	}
+	if err := txn.Set(objectPrototypeKey(object), prototype[:]); err != nil {
+		return err
+	}
+	return txn.Set(prototypeIndexKey(prototype, &object), activate.Request[:])
+}
+
+func indexLifeline(txn BackendTxn, object core.RecordID, lifeline []byte) error {
+	idx, err := index.DecodeObjectLifeline(lifeline)
+	if err != nil {
+		return err
+	}
+	if idx.State != record.StateDeactivation {
+		return nil
+	}
+
+	buf, err := txn.Get(objectPrototypeKey(object))
+	if err != nil && err != ErrNotFound {
+		return err
+	}
+	if len(buf) > 0 {
+		var prototype core.RecordID
+		copy(prototype[:], buf)
+		if err := txn.Delete(prototypeIndexKey(prototype, &object)); err != nil {
+			return err
+		}
+	}
+	// Activation record may come after the lifeline, so deactivation is remembered.
+	return txn.Set(objectPrototypeKey(object), []byte{})
+}
+
+// BuildPrototypeIndex indexes objects stored before prototype index was introduced.
+//
+// It does nothing if index is already built.
+func (db *DB) BuildPrototypeIndex(ctx context.Context) error {
+	inslog := inslogger.FromContext(ctx)
+	builtKey := prefixkey(scopeIDSystem, []byte{sysPrototypeIndexBuilt})
+	_, err := db.get(ctx, builtKey)
+	if err == nil {
+		return nil
+	}
+	if err != ErrNotFound {
+		return err
+	}
+
+	for _, scope := range []byte{scopeIDRecord, scopeIDLifeline} {
+		count := 0
+		start := []byte{scope}
+		for start != nil {
+			var kvs []core.KV
+			kvs, start, err = db.scanBatch([]byte{scope}, start, prototypeIndexBatchSize)
+			if err != nil {
+				return errors.Wrap(err, "[ BuildPrototypeIndex ] failed to read storage")
+			}
+			if err = db.IndexPrototypeObjects(ctx, kvs); err != nil {
+				return errors.Wrap(err, "[ BuildPrototypeIndex ] failed to update index")
+			}
+			count += len(kvs)
+		}
+		inslog.Infof("prototype index: scanned %v keys of scope %v", count, scope)
+	}
+	return db.set(ctx, builtKey, []byte{1})
+}
+
+// scanBatch returns at most limit key/value pairs with provided prefix starting from start key
+// and the key to continue from (nil if there are no more keys).
+func (db *DB) scanBatch(prefix, start []byte, limit int) ([]core.KV, []byte, error) {
+	var (
+		kvs  []core.KV
+		next []byte
+	)
+	err := db.backend.View(func(txn BackendTxn) error {
+		return txn.Iterate(prefix, start, func(k, v []byte) (bool, error) {
+			if len(kvs) >= limit {
+				next = k
+				return false, nil
+			}
+			kvs = append(kvs, core.KV{K: k, V: v})
+			return true, nil
+		})
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return kvs, next, nil
+}
+
+// GetPrototypeObjects returns heads of active objects of provided prototype ordered by object id.
+//
+// Iteration starts from provided object id (or from the first object if it is nil). At most limit
+// references are returned. Id of the object to continue from is returned if there are more objects.
+func (db *DB) GetPrototypeObjects(
+	ctx context.Context, prototype core.RecordID, from *core.RecordID, limit int,
+) ([]core.RecordRef, *core.RecordID, error) {
+	var (
+		refs []core.RecordRef
+		next *core.RecordID
+	)
+	err := db.backend.View(func(txn BackendTxn) error {
+		prefix := prototypeIndexKey(prototype, nil)
+		return txn.Iterate(prefix, prototypeIndexKey(prototype, from), func(key, value []byte) (bool, error) {
+			if len(refs) >= limit {
+				next = &core.RecordID{}
+				copy(next[:], key[len(prefix):])
+				return false, nil
+			}
+			var ref core.RecordRef
+			copy(ref[:], value)
+			refs = append(refs, ref)
+			return true, nil
+		})
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return refs, next, nil
+}
+
+// CountPrototypeObjects returns number of active objects of provided prototype.
+func (db *DB) CountPrototypeObjects(ctx context.Context, prototype core.RecordID) (int, error) {
+	count := 0
+	err := db.backend.View(func(txn BackendTxn) error {
+		prefix := prototypeIndexKey(prototype, nil)
+		return txn.Iterate(prefix, prefix, func(_, _ []byte) (bool, error) {
+			count++
+			return true, nil
+		})
+	})
+	return count, err
+}
@@ -0,0 +1,237 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package storage_test
+
+import (
+	"testing"
+
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/instrumentation/inslogger"
+	"github.com/insolar/insolar/ledger/storage"
+	"github.com/insolar/insolar/ledger/storage/index"
+	"github.com/insolar/insolar/ledger/storage/jet"
+	"github.com/insolar/insolar/ledger/storage/record"
+	"github.com/insolar/insolar/ledger/storage/storagetest"
+	"github.com/insolar/insolar/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDB_IndexPrototypeObjects(t *testing.T) {
+	ctx := inslogger.TestContext(t)
+	light, lightCleaner := storagetest.TmpDB(ctx, t)
+	defer lightCleaner()
+	heavy, heavyCleaner := storagetest.TmpDB(ctx, t)
+	defer heavyCleaner()
+
+	jetID := *jet.NewID(0, nil)
+	pulse := core.FirstPulseNumber + 1
+	wallet := testutils.RandomRef()
+	member := testutils.RandomRef()
+
+	activate := func(prototype core.RecordRef, isPrototype bool) (core.RecordRef, *core.RecordID) {
+		headID := testutils.RandomID()
+		head := *core.NewRecordRef(testutils.RandomID(), *core.NewRecordID(core.PulseNumber(pulse), headID.Hash()))
+		id, err := light.SetRecord(ctx, jetID, core.PulseNumber(pulse), &record.ObjectActivateRecord{
+			SideEffectRecord:  record.SideEffectRecord{Request: head},
+			ObjectStateRecord: record.ObjectStateRecord{Image: prototype, IsPrototype: isPrototype},
+		})
+		require.NoError(t, err)
+		err = light.SetObjectIndex(ctx, jetID, head.Record(), &index.ObjectLifeline{
+			LatestState: id,
+			State:       record.StateActivation,
+		})
+		require.NoError(t, err)
+		return head, id
+	}
+	replicate := func(from, to core.PulseNumber) {
+		iter := storage.NewReplicaIter(ctx, light, jetID, from, to, 1<<20)
+		for {
+			kvs, err := iter.NextRecords()
+			if err == storage.ErrReplicatorDone {
+				return
+			}
+			require.NoError(t, err)
+			require.NoError(t, heavy.StoreKeyValues(ctx, kvs))
+			require.NoError(t, heavy.IndexPrototypeObjects(ctx, kvs))
+		}
+	}
+
+	var wallets []core.RecordRef
+	var walletStates []*core.RecordID
+	for i := 0; i < 3; i++ {
+		head, state := activate(wallet, false)
+		wallets = append(wallets, head)
+		walletStates = append(walletStates, state)
+	}
+	activate(member, false)
+	// Prototype itself should not be indexed by its code.
+	activate(wallet, true)
+	replicate(core.FirstPulseNumber, core.PulseNumber(pulse+1))
+
+	count, err := heavy.CountPrototypeObjects(ctx, *wallet.Record())
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+	count, err = heavy.CountPrototypeObjects(ctx, *member.Record())
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	first, next, err := heavy.GetPrototypeObjects(ctx, *wallet.Record(), nil, 2)
+	require.NoError(t, err)
+	require.Len(t, first, 2)
+	require.NotNil(t, next)
+	rest, next, err := heavy.GetPrototypeObjects(ctx, *wallet.Record(), next, 2)
+	require.NoError(t, err)
+	require.Len(t, rest, 1)
+	assert.Nil(t, next)
+	assert.ElementsMatch(t, wallets, append(first, rest...))
+
+	// Deactivated object is removed from index.
+	pulse++
+	deactivated, err := light.SetRecord(ctx, jetID, core.PulseNumber(pulse), &record.DeactivationRecord{
+		PrevState: *walletStates[0],
+	})
+	require.NoError(t, err)
+	err = light.SetObjectIndex(ctx, jetID, wallets[0].Record(), &index.ObjectLifeline{
+		LatestState: deactivated,
+		State:       record.StateDeactivation,
+	})
+	require.NoError(t, err)
+	replicate(core.PulseNumber(pulse), core.PulseNumber(pulse+1))
+
+	refs, _, err := heavy.GetPrototypeObjects(ctx, *wallet.Record(), nil, 10)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, wallets[1:], refs)
+}
+
+func TestDB_BuildPrototypeIndex(t *testing.T) {
+	ctx := inslogger.TestContext(t)
+	db, cleaner := storagetest.TmpDB(ctx, t)
+	defer cleaner()
+
+	parent := *jet.NewID(0, nil)
+	right := *jet.NewID(1, []byte{0x80})
+	pulse := core.FirstPulseNumber + 1
+	prototype := testutils.RandomRef()
+
+	activate := func() (core.RecordRef, *core.RecordID) {
+		headID := testutils.RandomID()
+		head := *core.NewRecordRef(testutils.RandomID(), *core.NewRecordID(core.PulseNumber(pulse), headID.Hash()))
+		id, err := db.SetRecord(ctx, parent, core.PulseNumber(pulse), &record.ObjectActivateRecord{
+			SideEffectRecord:  record.SideEffectRecord{Request: head},
+			ObjectStateRecord: record.ObjectStateRecord{Image: prototype},
+		})
+		require.NoError(t, err)
+		err = db.SetObjectIndex(ctx, parent, head.Record(), &index.ObjectLifeline{
+			LatestState: id,
+			State:       record.StateActivation,
+		})
+		require.NoError(t, err)
+		return head, id
+	}
+	active, _ := activate()
+	moved, movedState := activate()
+
+	// Object is deactivated after the jet split, so its activation is stored with parent jet prefix.
+	deactivated, err := db.SetRecord(ctx, right, core.PulseNumber(pulse+1), &record.DeactivationRecord{
+		PrevState: *movedState,
+	})
+	require.NoError(t, err)
+	err = db.SetObjectIndex(ctx, right, moved.Record(), &index.ObjectLifeline{
+		LatestState: deactivated,
+		State:       record.StateDeactivation,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, db.BuildPrototypeIndex(ctx))
+	refs, next, err := db.GetPrototypeObjects(ctx, *prototype.Record(), nil, 10)
+	require.NoError(t, err)
+	assert.Nil(t, next)
+	assert.Equal(t, []core.RecordRef{active}, refs)
+
+	// Index is built only once.
+	activate()
+	require.NoError(t, db.BuildPrototypeIndex(ctx))
+	count, err := db.CountPrototypeObjects(ctx, *prototype.Record())
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func TestDB_IndexPrototypeObjects_Chunks(t *testing.T) {
+	ctx := inslogger.TestContext(t)
+	light, lightCleaner := storagetest.TmpDB(ctx, t)
+	defer lightCleaner()
+
+	jetID := *jet.NewID(0, nil)
+	pulse := core.FirstPulseNumber + 1
+	prototype := testutils.RandomRef()
+
+	activate := func() core.RecordRef {
+		headID := testutils.RandomID()
+		head := *core.NewRecordRef(testutils.RandomID(), *core.NewRecordID(core.PulseNumber(pulse), headID.Hash()))
+		id, err := light.SetRecord(ctx, jetID, core.PulseNumber(pulse), &record.ObjectActivateRecord{
+			SideEffectRecord:  record.SideEffectRecord{Request: head},
+			ObjectStateRecord: record.ObjectStateRecord{Image: prototype},
+		})
+		require.NoError(t, err)
+		err = light.SetObjectIndex(ctx, jetID, head.Record(), &index.ObjectLifeline{
+			LatestState: id,
+			State:       record.StateActivation,
+		})
+		require.NoError(t, err)
+		return head
+	}
+	active := activate()
+	// Object is activated and deactivated in the same pulse.
+	deactivated := activate()
+	deactivation, err := light.SetRecord(ctx, jetID, core.PulseNumber(pulse), &record.DeactivationRecord{})
+	require.NoError(t, err)
+	err = light.SetObjectIndex(ctx, jetID, deactivated.Record(), &index.ObjectLifeline{
+		LatestState: deactivation,
+		State:       record.StateDeactivation,
+	})
+	require.NoError(t, err)
+
+	iter := storage.NewReplicaIter(ctx, light, jetID, core.FirstPulseNumber, core.PulseNumber(pulse+1), 1<<20)
+	kvs, err := iter.NextRecords()
+	require.NoError(t, err)
+
+	reversed := make([]core.KV, 0, len(kvs))
+	for i := len(kvs) - 1; i >= 0; i-- {
+		reversed = append(reversed, kvs[i])
+	}
+	for name, order := range map[string][]core.KV{"in order": kvs, "reversed": reversed} {
+		t.Run(name, func(t *testing.T) {
+			for _, chunkSize := range []int{1, len(order)} {
+				heavy, heavyCleaner := storagetest.TmpDB(ctx, t)
+				for i := 0; i < len(order); i += chunkSize {
+					chunk := order[i:]
+					if len(chunk) > chunkSize {
+						chunk = chunk[:chunkSize]
+					}
+					require.NoError(t, heavy.StoreKeyValues(ctx, chunk))
+					require.NoError(t, heavy.IndexPrototypeObjects(ctx, chunk))
+				}
+
+				refs, _, err := heavy.GetPrototypeObjects(ctx, *prototype.Record(), nil, 10)
+				require.NoError(t, err)
+				assert.Equal(t, []core.RecordRef{active}, refs, "chunk size %v", chunkSize)
+				heavyCleaner()
+			}
+		})
+	}
+}
@@ -0,0 +1,508 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package storage
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/ledger/storage/index"
+	"github.com/insolar/insolar/ledger/storage/jet"
+	"github.com/insolar/insolar/ledger/storage/record"
+	"github.com/pkg/errors"
+)
+
+// RetentionPolicy describes which data could be dropped from heavy material storage.
+//
+// Zero policy keeps everything.
+type RetentionPolicy struct {
+	// KeepStates is a number of latest states kept for every object, zero keeps all states.
+	// The latest approved state is never removed.
+	KeepStates int
+	// RequestsUntil is a pulse number request and result records older than are removed, zero keeps them.
+	RequestsUntil core.PulseNumber
+	// MessagesUntil is a pulse number message payloads older than are removed, zero keeps them.
+	MessagesUntil core.PulseNumber
+	// CompactDeactivated enables removal of memory blobs of deactivated objects.
+	CompactDeactivated bool
+}
+
+// pruneBatchSize is a max number of keys read or removed in one transaction.
+const pruneBatchSize = 1000
+
+// Kinds of pruning watermarks stored for every jet.
+const (
+	pruneMarkMessages byte = 1
+	pruneMarkRequests byte = 2
+	pruneMarkStates   byte = 3
+)
+
+// Kinds of pruned data in PruneStat.
+const (
+	PruneKindStates   = "states"
+	PruneKindBlobs    = "blobs"
+	PruneKindRequests = "requests"
+	PruneKindResults  = "results"
+	PruneKindMessages = "messages"
+)
+
+// PruneStat holds number of removed entries and reclaimed bytes (keys and values) by kind of data.
+type PruneStat struct {
+	Removed map[string]int
+	Bytes   map[string]int64
+}
+
+// TotalBytes returns number of reclaimed bytes for all kinds of data.
+func (s *PruneStat) TotalBytes() int64 {
+	var total int64
+	for _, b := range s.Bytes {
+		total += b
+	}
+	return total
+}
+
+// EnqueuePruneLifelines marks object lifelines from provided key/value pairs which differ from stored ones,
+// states of marked objects are checked by the next PruneJet call. It should be called before pairs are stored.
+func (db *DB) EnqueuePruneLifelines(ctx context.Context, kvs []core.KV) error {
+	return db.backend.Update(func(txn BackendTxn) error {
+		for _, kv := range kvs {
+			if len(kv.K) == 0 || kv.K[0] != scopeIDLifeline {
+				continue
+			}
+			stored, err := txn.Get(kv.K)
+			if err != nil && err != ErrNotFound {
+				return err
+			}
+			if err == nil && bytes.Equal(stored, kv.V) {
+				continue
+			}
+			if err := txn.Set(pruneQueueKey(kv.K[1:]), []byte{}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func pruneQueueKey(parts ...[]byte) []byte {
+	return prefixkey(scopeIDSystem, append([][]byte{{sysPruneQueue}}, parts...)...)
+}
+
+func pruneMarkKey(jetID core.RecordID, kind byte) []byte {
+	return prefixkey(scopeIDSystem, []byte{sysPruneMark}, jetID[:], []byte{kind})
+}
+
+// PruneJet removes data of provided jet which is not required by retention policy.
+//
+// Data is read and removed by bounded batches. Messages and requests are scanned from pulse reached by
+// the previous call, states are checked only for objects enqueued by EnqueuePruneLifelines, the first call
+// checks all objects of the jet.
+//
+// In dry run mode storage is left untouched and the whole jet is scanned, returned stat reports what would be removed.
+func (db *DB) PruneJet(
+	ctx context.Context,
+	jetID core.RecordID,
+	policy RetentionPolicy,
+	dryRun bool,
+) (*PruneStat, error) {
+	_, jetPrefix := jet.Jet(jetID)
+	p := &pruner{
+		backend:    db.backend,
+		jetID:      jetID,
+		jetPrefix:  jetPrefix,
+		dryRun:     dryRun,
+		stat:       &PruneStat{Removed: map[string]int{}, Bytes: map[string]int64{}},
+		removed:    map[string]struct{}{},
+		compacted:  map[string]struct{}{},
+		keptMemory: map[core.RecordID]struct{}{},
+		dropMemory: map[core.RecordID]struct{}{},
+		marks:      map[byte][]byte{},
+	}
+	if policy.MessagesUntil > 0 {
+		if err := p.pruneMessages(policy.MessagesUntil); err != nil {
+			return nil, errors.Wrap(err, "failed to prune messages")
+		}
+	}
+	if policy.RequestsUntil > 0 {
+		if err := p.pruneRequests(policy.RequestsUntil); err != nil {
+			return nil, errors.Wrap(err, "failed to prune requests")
+		}
+	}
+	if policy.KeepStates > 0 || policy.CompactDeactivated {
+		if err := p.pruneStates(policy.KeepStates, policy.CompactDeactivated); err != nil {
+			return nil, errors.Wrap(err, "failed to prune states")
+		}
+	}
+	if err := p.flush(); err != nil {
+		return nil, err
+	}
+	if err := p.saveMarks(); err != nil {
+		return nil, errors.Wrap(err, "failed to save pruning watermarks")
+	}
+	return p.stat, nil
+}
+
+type pruner struct {
+	backend   Backend
+	jetID     core.RecordID
+	jetPrefix []byte
+	dryRun    bool
+	stat      *PruneStat
+
+	// removed holds removed keys which are not deleted from backend yet, in dry run it holds all removed keys.
+	removed map[string]struct{}
+	// batch holds keys to delete by the next flush.
+	batch [][]byte
+	// compacted holds keys of kept states which memory is removed.
+	compacted map[string]struct{}
+	// keptMemory and dropMemory hold memory of kept and removed states.
+	keptMemory map[core.RecordID]struct{}
+	dropMemory map[core.RecordID]struct{}
+	// marks holds watermarks saved after successful pruning.
+	marks map[byte][]byte
+}
+
+func (p *pruner) isRemoved(key []byte) bool {
+	_, ok := p.removed[string(key)]
+	return ok
+}
+
+func (p *pruner) remove(kind string, key []byte, size int) {
+	if p.isRemoved(key) {
+		return
+	}
+	p.removed[string(key)] = struct{}{}
+	p.stat.Removed[kind]++
+	p.stat.Bytes[kind] += int64(len(key) + size)
+	if !p.dryRun {
+		p.batch = append(p.batch, key)
+	}
+}
+
+// flushFull deletes collected keys if batch is full.
+func (p *pruner) flushFull() error {
+	if len(p.batch) < pruneBatchSize {
+		return nil
+	}
+	return p.flush()
+}
+
+// flush deletes collected keys from backend in one transaction.
+func (p *pruner) flush() error {
+	if len(p.batch) == 0 {
+		return nil
+	}
+	err := p.backend.Update(func(txn BackendTxn) error {
+		for _, key := range p.batch {
+			if err := txn.Delete(key); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to delete pruned keys")
+	}
+	for _, key := range p.batch {
+		delete(p.removed, string(key))
+	}
+	p.batch = nil
+	return nil
+}
+
+// mark returns stored watermark of provided kind, nil is returned if there is no watermark or in dry run.
+func (p *pruner) mark(kind byte) ([]byte, error) {
+	if p.dryRun {
+		return nil, nil
+	}
+	var mark []byte
+	err := p.backend.View(func(txn BackendTxn) error {
+		var err error
+		mark, err = txn.Get(pruneMarkKey(p.jetID, kind))
+		if err == ErrNotFound {
+			return nil
+		}
+		return err
+	})
+	return mark, err
+}
+
+func (p *pruner) saveMarks() error {
+	if p.dryRun || len(p.marks) == 0 {
+		return nil
+	}
+	return p.backend.Update(func(txn BackendTxn) error {
+		for kind, mark := range p.marks {
+			if err := txn.Set(pruneMarkKey(p.jetID, kind), mark); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// scan calls handler for keys with provided prefix starting from start key, keys are read by bounded batches.
+// Handler returns false to stop scan.
+func (p *pruner) scan(prefix, start []byte, handler func(key, value []byte) (bool, error)) error {
+	for start != nil {
+		var (
+			kvs  []core.KV
+			next []byte
+		)
+		err := p.backend.View(func(txn BackendTxn) error {
+			return txn.Iterate(prefix, start, func(key, value []byte) (bool, error) {
+				if len(kvs) == pruneBatchSize {
+					next = key
+					return false, nil
+				}
+				kvs = append(kvs, core.KV{K: key, V: value})
+				return true, nil
+			})
+		})
+		if err != nil {
+			return err
+		}
+		for _, kv := range kvs {
+			ok, err := handler(kv.K, kv.V)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return nil
+			}
+		}
+		if err := p.flushFull(); err != nil {
+			return err
+		}
+		start = next
+	}
+	return nil
+}
+
+// scanUntil calls handler for jet keys of provided scope older than provided pulse number. Scan starts from
+// the pulse stored in watermark of provided kind, the watermark is moved to provided pulse number.
+func (p *pruner) scanUntil(scope byte, markKind byte, pn core.PulseNumber, handler func(key, value []byte)) error {
+	from, err := p.mark(markKind)
+	if err != nil {
+		return err
+	}
+	if from == nil {
+		from = rmScanFromPulse
+	}
+	if core.NewPulseNumber(from) >= pn {
+		return nil
+	}
+
+	err = p.scan(prefixkey(scope, p.jetPrefix), prefixkey(scope, p.jetPrefix, from), func(key, value []byte) (bool, error) {
+		if pulseFromKey(key) >= pn {
+			return false, nil
+		}
+		handler(key, value)
+		return true, nil
+	})
+	if err != nil {
+		return err
+	}
+	p.marks[markKind] = pn.Bytes()
+	return nil
+}
+
+func (p *pruner) pruneMessages(pn core.PulseNumber) error {
+	return p.scanUntil(scopeIDMessage, pruneMarkMessages, pn, func(key, value []byte) {
+		p.remove(PruneKindMessages, key, len(value))
+	})
+}
+
+func (p *pruner) pruneRequests(pn core.PulseNumber) error {
+	return p.scanUntil(scopeIDRecord, pruneMarkRequests, pn, func(key, value []byte) {
+		switch record.DeserializeRecord(value).(type) {
+		case *record.RequestRecord:
+			p.remove(PruneKindRequests, key, len(value))
+		case *record.ResultRecord:
+			p.remove(PruneKindResults, key, len(value))
+		}
+	})
+}
+
+// pruneStates walks states of changed objects in jet. States older than keep latest ones are removed
+// with their memory. Memory of all states is removed for deactivated objects if compact is set.
+//
+// All objects are walked if there is no states watermark, e.g. on the first call.
+func (p *pruner) pruneStates(keep int, compact bool) error {
+	walked, err := p.mark(pruneMarkStates)
+	if err != nil {
+		return err
+	}
+	if walked == nil {
+		// Lifelines changed before the first pruning are not enqueued.
+		prefix := prefixkey(scopeIDLifeline, p.jetPrefix)
+		err := p.scan(prefix, prefix, func(_, value []byte) (bool, error) {
+			return true, p.pruneObject(value, keep, compact)
+		})
+		if err != nil {
+			return err
+		}
+		p.marks[pruneMarkStates] = []byte{1}
+	}
+
+	// Queue is left untouched in dry run.
+	if !p.dryRun {
+		prefix := pruneQueueKey(p.jetPrefix)
+		err := p.scan(prefix, prefix, func(key, _ []byte) (bool, error) {
+			if walked != nil {
+				lifelineKey := prefixkey(scopeIDLifeline, key[len(pruneQueueKey()):])
+				var value []byte
+				err := p.backend.View(func(txn BackendTxn) error {
+					var err error
+					value, err = txn.Get(lifelineKey)
+					return err
+				})
+				if err != nil && err != ErrNotFound {
+					return false, err
+				}
+				if err == nil {
+					if err := p.pruneObject(value, keep, compact); err != nil {
+						return false, err
+					}
+				}
+			}
+			p.batch = append(p.batch, key)
+			return true, nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := p.flush(); err != nil {
+		return err
+	}
+	return p.pruneMemory()
+}
+
+// pruneObject walks states of object and selects states and memory to remove.
+func (p *pruner) pruneObject(value []byte, keep int, compact bool) error {
+	idx, err := index.DecodeObjectLifeline(value)
+	if err != nil {
+		return err
+	}
+	compactMemory := compact && idx.State == record.StateDeactivation
+
+	return p.backend.View(func(txn BackendTxn) error {
+		count := 0
+		for id := idx.LatestState; id != nil; {
+			key := prefixkey(scopeIDRecord, p.jetPrefix, id[:])
+			// Older states were removed already.
+			if p.isRemoved(key) {
+				break
+			}
+			buf, err := txn.Get(key)
+			if err == ErrNotFound {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			state, ok := record.DeserializeRecord(buf).(record.ObjectState)
+			if !ok {
+				return errors.New("invalid object state record")
+			}
+			count++
+
+			keepState := keep == 0 || count <= keep ||
+				(idx.LatestStateApproved != nil && idx.LatestStateApproved.Equal(id))
+			if !keepState {
+				p.remove(PruneKindStates, key, len(buf))
+			}
+			if memory := state.GetMemory(); memory != nil {
+				switch {
+				case keepState && !compactMemory:
+					p.keptMemory[*memory] = struct{}{}
+				case keepState:
+					p.compacted[string(key)] = struct{}{}
+					p.dropMemory[*memory] = struct{}{}
+				default:
+					p.dropMemory[*memory] = struct{}{}
+				}
+			}
+			id = state.PrevStateID()
+		}
+		return nil
+	})
+}
+
+// pruneMemory removes memory of removed and compacted states.
+//
+// Memory blobs are addressed by content and pulse, so states of other objects created in the same pulse could
+// share the blob. Blob is removed only if none of remaining states of its pulse refers to it.
+func (p *pruner) pruneMemory() error {
+	byPulse := map[core.PulseNumber][]core.RecordID{}
+	for memory := range p.dropMemory {
+		if _, ok := p.keptMemory[memory]; ok {
+			continue
+		}
+		byPulse[memory.Pulse()] = append(byPulse[memory.Pulse()], memory)
+	}
+
+	for pn, candidates := range byPulse {
+		referred := map[core.RecordID]struct{}{}
+		prefix := prefixkey(scopeIDRecord, p.jetPrefix, pn.Bytes())
+		err := p.scan(prefix, prefix, func(key, value []byte) (bool, error) {
+			if p.isRemoved(key) {
+				return true, nil
+			}
+			if _, ok := p.compacted[string(key)]; ok {
+				return true, nil
+			}
+			if state, ok := record.DeserializeRecord(value).(record.ObjectState); ok {
+				if memory := state.GetMemory(); memory != nil {
+					referred[*memory] = struct{}{}
+				}
+			}
+			return true, nil
+		})
+		if err != nil {
+			return err
+		}
+
+		err = p.backend.View(func(txn BackendTxn) error {
+			for _, memory := range candidates {
+				if _, ok := referred[memory]; ok {
+					continue
+				}
+				key := prefixkey(scopeIDBlob, p.jetPrefix, memory[:])
+				buf, err := txn.Get(key)
+				if err == ErrNotFound {
+					continue
+				}
+				if err != nil {
+					return err
+				}
+				p.remove(PruneKindBlobs, key, len(buf))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if err := p.flushFull(); err != nil {
+			return err
+		}
+	}
+	return p.flush()
+}
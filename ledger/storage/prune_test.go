@@ -0,0 +1,242 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package storage_test
+
+import (
+	"testing"
+
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/core/message"
+	"github.com/insolar/insolar/instrumentation/inslogger"
+	"github.com/insolar/insolar/ledger/storage"
+	"github.com/insolar/insolar/ledger/storage/index"
+	"github.com/insolar/insolar/ledger/storage/jet"
+	"github.com/insolar/insolar/ledger/storage/record"
+	"github.com/insolar/insolar/ledger/storage/storagetest"
+	"github.com/insolar/insolar/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDB_PruneJet(t *testing.T) {
+	t.Parallel()
+	ctx := inslogger.TestContext(t)
+	db, cleaner := storagetest.TmpDB(ctx, t)
+	defer cleaner()
+
+	jetID := testutils.RandomJet()
+	pulse := func(n int) core.PulseNumber {
+		return core.FirstPulseNumber + core.PulseNumber(n)
+	}
+
+	setBlob := func(pn core.PulseNumber, memory string) *core.RecordID {
+		id, err := db.SetBlob(ctx, jetID, pn, []byte(memory))
+		require.NoError(t, err)
+		return id
+	}
+	setRecord := func(pn core.PulseNumber, rec record.Record) *core.RecordID {
+		id, err := db.SetRecord(ctx, jetID, pn, rec)
+		require.NoError(t, err)
+		return id
+	}
+
+	// Active object with three states, the first one is approved.
+	activeMemory := []*core.RecordID{setBlob(pulse(1), "1"), setBlob(pulse(2), "2"), setBlob(pulse(3), "3")}
+	activeStates := []*core.RecordID{setRecord(pulse(1), &record.ObjectActivateRecord{
+		ObjectStateRecord: record.ObjectStateRecord{Memory: activeMemory[0]},
+	})}
+	for i := 1; i < len(activeMemory); i++ {
+		activeStates = append(activeStates, setRecord(pulse(i+1), &record.ObjectAmendRecord{
+			ObjectStateRecord: record.ObjectStateRecord{Memory: activeMemory[i]},
+			PrevState:         *activeStates[i-1],
+		}))
+	}
+	activeObj := testutils.RandomID()
+	err := db.SetObjectIndex(ctx, jetID, &activeObj, &index.ObjectLifeline{
+		LatestState:         activeStates[2],
+		LatestStateApproved: activeStates[0],
+		State:               record.StateAmend,
+	})
+	require.NoError(t, err)
+
+	// Deactivated object with approved activation, its memory is removed by compaction only.
+	deactivatedMemory := setBlob(pulse(1), "deactivated")
+	deactivatedActivation := setRecord(pulse(1), &record.ObjectActivateRecord{
+		ObjectStateRecord: record.ObjectStateRecord{Memory: deactivatedMemory},
+	})
+	deactivation := setRecord(pulse(2), &record.DeactivationRecord{PrevState: *deactivatedActivation})
+	deactivatedObj := testutils.RandomID()
+	err = db.SetObjectIndex(ctx, jetID, &deactivatedObj, &index.ObjectLifeline{
+		LatestState:         deactivation,
+		LatestStateApproved: deactivatedActivation,
+		State:               record.StateDeactivation,
+	})
+	require.NoError(t, err)
+
+	// Requests, results and messages.
+	oldRequest := setRecord(pulse(1), &record.RequestRecord{Payload: []byte("old")})
+	oldResult := setRecord(pulse(1), &record.ResultRecord{Payload: []byte("old")})
+	newRequest := setRecord(pulse(3), &record.RequestRecord{Payload: []byte("new")})
+	require.NoError(t, db.SetMessage(ctx, jetID, pulse(1), &message.GenesisRequest{Name: "old"}))
+	require.NoError(t, db.SetMessage(ctx, jetID, pulse(3), &message.GenesisRequest{Name: "new"}))
+
+	policy := storage.RetentionPolicy{
+		KeepStates:         1,
+		RequestsUntil:      pulse(2),
+		MessagesUntil:      pulse(2),
+		CompactDeactivated: true,
+	}
+	expected := map[string]int{
+		storage.PruneKindStates:   1,
+		storage.PruneKindBlobs:    2,
+		storage.PruneKindRequests: 1,
+		storage.PruneKindResults:  1,
+		storage.PruneKindMessages: 1,
+	}
+
+	t.Run("dry run keeps storage untouched", func(t *testing.T) {
+		stat, err := db.PruneJet(ctx, jetID, policy, true)
+		require.NoError(t, err)
+		assert.Equal(t, expected, stat.Removed)
+		assert.True(t, stat.TotalBytes() > 0)
+
+		_, err = db.GetRecord(ctx, jetID, activeStates[1])
+		assert.NoError(t, err)
+		_, err = db.GetBlob(ctx, jetID, deactivatedMemory)
+		assert.NoError(t, err)
+	})
+
+	t.Run("prune removes data", func(t *testing.T) {
+		stat, err := db.PruneJet(ctx, jetID, policy, false)
+		require.NoError(t, err)
+		assert.Equal(t, expected, stat.Removed)
+
+		// The latest and the approved states are kept.
+		for _, id := range []*core.RecordID{activeStates[0], activeStates[2], deactivatedActivation, deactivation, newRequest} {
+			_, err = db.GetRecord(ctx, jetID, id)
+			assert.NoError(t, err)
+		}
+		for _, id := range []*core.RecordID{activeMemory[0], activeMemory[2]} {
+			_, err = db.GetBlob(ctx, jetID, id)
+			assert.NoError(t, err)
+		}
+
+		for _, id := range []*core.RecordID{activeStates[1], oldRequest, oldResult} {
+			_, err = db.GetRecord(ctx, jetID, id)
+			assert.Equal(t, storage.ErrNotFound, err)
+		}
+		for _, id := range []*core.RecordID{activeMemory[1], deactivatedMemory} {
+			_, err = db.GetBlob(ctx, jetID, id)
+			assert.Equal(t, storage.ErrNotFound, err)
+		}
+	})
+
+	t.Run("second prune has nothing to remove", func(t *testing.T) {
+		stat, err := db.PruneJet(ctx, jetID, policy, false)
+		require.NoError(t, err)
+		assert.Empty(t, stat.Removed)
+		assert.Equal(t, int64(0), stat.TotalBytes())
+	})
+}
+
+func TestDB_PruneJetIncremental(t *testing.T) {
+	t.Parallel()
+	ctx := inslogger.TestContext(t)
+	light, lightCleaner := storagetest.TmpDB(ctx, t)
+	defer lightCleaner()
+	heavy, heavyCleaner := storagetest.TmpDB(ctx, t)
+	defer heavyCleaner()
+
+	jetID := *jet.NewID(0, nil)
+	pulse := func(n int) core.PulseNumber {
+		return core.FirstPulseNumber + core.PulseNumber(n)
+	}
+	replicate := func(from, to core.PulseNumber) {
+		iter := storage.NewReplicaIter(ctx, light, jetID, from, to, 1<<20)
+		for {
+			kvs, err := iter.NextRecords()
+			if err == storage.ErrReplicatorDone {
+				return
+			}
+			require.NoError(t, err)
+			require.NoError(t, heavy.EnqueuePruneLifelines(ctx, kvs))
+			require.NoError(t, heavy.StoreKeyValues(ctx, kvs))
+		}
+	}
+	activate := func(pn core.PulseNumber, memory string) (core.RecordID, *core.RecordID, *core.RecordID) {
+		blob, err := light.SetBlob(ctx, jetID, pn, []byte(memory))
+		require.NoError(t, err)
+		state, err := light.SetRecord(ctx, jetID, pn, &record.ObjectActivateRecord{
+			SideEffectRecord:  record.SideEffectRecord{Request: testutils.RandomRef()},
+			ObjectStateRecord: record.ObjectStateRecord{Memory: blob},
+		})
+		require.NoError(t, err)
+		headID := testutils.RandomID()
+		obj := *core.NewRecordID(pn, headID.Hash())
+		require.NoError(t, light.SetObjectIndex(ctx, jetID, &obj, &index.ObjectLifeline{
+			LatestState: state,
+			State:       record.StateActivation,
+		}))
+		return obj, state, blob
+	}
+	policy := storage.RetentionPolicy{KeepStates: 1}
+
+	// Both objects have the same memory in the same pulse, so they share memory blob.
+	first, firstState, shared := activate(pulse(1), "shared")
+	_, secondState, secondMemory := activate(pulse(1), "shared")
+	require.Equal(t, *shared, *secondMemory)
+	replicate(pulse(1), pulse(2))
+
+	stat, err := heavy.PruneJet(ctx, jetID, policy, false)
+	require.NoError(t, err)
+	assert.Empty(t, stat.Removed)
+
+	amendMemory, err := light.SetBlob(ctx, jetID, pulse(2), []byte("amend"))
+	require.NoError(t, err)
+	amend, err := light.SetRecord(ctx, jetID, pulse(2), &record.ObjectAmendRecord{
+		ObjectStateRecord: record.ObjectStateRecord{Memory: amendMemory},
+		PrevState:         *firstState,
+	})
+	require.NoError(t, err)
+	require.NoError(t, light.SetObjectIndex(ctx, jetID, &first, &index.ObjectLifeline{
+		LatestState: amend,
+		State:       record.StateAmend,
+	}))
+	replicate(pulse(2), pulse(3))
+
+	t.Run("shared memory is kept", func(t *testing.T) {
+		stat, err := heavy.PruneJet(ctx, jetID, policy, false)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{storage.PruneKindStates: 1}, stat.Removed)
+
+		_, err = heavy.GetRecord(ctx, jetID, firstState)
+		assert.Equal(t, storage.ErrNotFound, err)
+		for _, id := range []*core.RecordID{secondState, amend} {
+			_, err = heavy.GetRecord(ctx, jetID, id)
+			assert.NoError(t, err)
+		}
+		_, err = heavy.GetBlob(ctx, jetID, shared)
+		assert.NoError(t, err)
+	})
+
+	t.Run("unchanged objects are not checked again", func(t *testing.T) {
+		replicate(pulse(2), pulse(3))
+		stat, err := heavy.PruneJet(ctx, jetID, policy, false)
+		require.NoError(t, err)
+		assert.Empty(t, stat.Removed)
+	})
+}
@@ -19,7 +19,6 @@ package storage
 import (
 	"context"
 
-	"github.com/dgraph-io/badger"
 	multierror "github.com/hashicorp/go-multierror"
 	"github.com/insolar/insolar/core"
 	"github.com/insolar/insolar/ledger/recentstorage"
@@ -95,29 +94,26 @@ func (db *DB) removeJetRecordsUntil(
 	startprefix := prefixkey(namespace, prefix, rmScanFromPulse)
 
 	count := 0
-	return count, db.db.Update(func(txn *badger.Txn) error {
+	return count, db.backend.Update(func(txn BackendTxn) error {
 		var id core.RecordID
 
-		it := txn.NewIterator(badger.DefaultIteratorOptions)
-		defer it.Close()
-		for it.Seek(startprefix); it.ValidForPrefix(jetprefix); it.Next() {
-			key := it.Item().KeyCopy(nil)
+		return txn.Iterate(jetprefix, startprefix, func(key, _ []byte) (bool, error) {
 			if pulseFromKey(key) >= pn {
-				break
+				return false, nil
 			}
 
 			if recent != nil {
 				copy(id[:], key[len(jetprefix):])
 				if recent.IsRecordIDCached(id) {
-					continue
+					return true, nil
 				}
 			}
 
 			if err := txn.Delete(key); err != nil {
-				return err
+				return false, err
 			}
 			count++
-		}
-		return nil
+			return true, nil
+		})
 	})
 }
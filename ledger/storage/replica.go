@@ -22,7 +22,6 @@ import (
 	"encoding/gob"
 	"io"
 
-	"github.com/dgraph-io/badger"
 	"github.com/insolar/insolar/core"
 	"github.com/pkg/errors"
 )
@@ -46,6 +45,33 @@ func (db *DB) GetHeavySyncedPulse(ctx context.Context, jetID core.RecordID) (pn
 	return
 }
 
+func heavySyncCursorKey(jetID core.RecordID, pulsenum core.PulseNumber) []byte {
+	return prefixkey(scopeIDSystem, jetID[:], []byte{sysHeavySyncCursor}, pulsenum.Bytes())
+}
+
+// SetHeavySyncCursor saves cursor of the last payload chunk of pulse stored on heavy node (see ReplicaIter.LastKey).
+func (db *DB) SetHeavySyncCursor(ctx context.Context, jetID core.RecordID, pulsenum core.PulseNumber, cursor []byte) error {
+	return db.Update(ctx, func(tx *TransactionManager) error {
+		return tx.set(ctx, heavySyncCursorKey(jetID, pulsenum), cursor)
+	})
+}
+
+// GetHeavySyncCursor returns cursor of the last payload chunk of pulse stored on heavy node, nil if nothing is stored.
+func (db *DB) GetHeavySyncCursor(ctx context.Context, jetID core.RecordID, pulsenum core.PulseNumber) ([]byte, error) {
+	cursor, err := db.get(ctx, heavySyncCursorKey(jetID, pulsenum))
+	if err == ErrNotFound {
+		return nil, nil
+	}
+	return cursor, err
+}
+
+// RemoveHeavySyncCursor removes cursor of pulse when pulse sync is finished or reset.
+func (db *DB) RemoveHeavySyncCursor(ctx context.Context, jetID core.RecordID, pulsenum core.PulseNumber) error {
+	return db.backend.Update(func(txn BackendTxn) error {
+		return txn.Delete(heavySyncCursorKey(jetID, pulsenum))
+	})
+}
+
 var sysHeavyClientStatePrefix = prefixkey(scopeIDSystem, []byte{sysHeavyClientState})
 
 func sysHeavyClientStateKeyForJet(jetID []byte) []byte {
@@ -85,31 +111,20 @@ func (db *DB) SetSyncClientJetPulses(ctx context.Context, jetID core.RecordID, p
 // GetAllSyncClientJets returns map of all jet's processed by node.
 func (db *DB) GetAllSyncClientJets(ctx context.Context) (map[core.RecordID][]core.PulseNumber, error) {
 	jets := map[core.RecordID][]core.PulseNumber{}
-	err := db.db.View(func(txn *badger.Txn) error {
-		it := txn.NewIterator(badger.DefaultIteratorOptions)
-		defer it.Close()
-
-		for it.Seek(sysHeavyClientStatePrefix); it.ValidForPrefix(sysHeavyClientStatePrefix); it.Next() {
-			item := it.Item()
-			if item == nil {
-				break
-			}
-			key := item.Key()
-			value, err := it.Item().Value()
-			if err != nil {
-				return err
-			}
+	err := db.backend.View(func(txn BackendTxn) error {
+		prefix := sysHeavyClientStatePrefix
+		return txn.Iterate(prefix, prefix, func(key, value []byte) (bool, error) {
 			syncPulses, err := decodePulsesList(bytes.NewReader(value))
 			if err != nil {
-				return err
+				return false, err
 			}
 
 			var jetID core.RecordID
 			offset := len(sysHeavyClientStatePrefix)
 			copy(jetID[:], key[offset:offset+len(jetID)])
 			jets[jetID] = syncPulses
-		}
-		return nil
+			return true, nil
+		})
 	})
 	if err != nil {
 		return nil, err
@@ -21,7 +21,6 @@ import (
 	"context"
 	"errors"
 
-	"github.com/dgraph-io/badger"
 	"github.com/insolar/insolar/core"
 	"github.com/insolar/insolar/ledger/storage/jet"
 )
@@ -50,6 +49,7 @@ type ReplicaIter struct {
 	limitBytes int
 	istates    []*iterstate
 	lastpulse  core.PulseNumber
+	lastkey    []byte
 }
 
 // NewReplicaIter creates ReplicaIter what iterates over records on jet,
@@ -88,6 +88,7 @@ func NewReplicaIter(
 			newit(scopeIDBlob, jetID, start, end),
 			newit(scopeIDLifeline, jetID, core.FirstPulseNumber, end),
 			newit(scopeIDJetDrop, jetID, start, end),
+			newit(scopeIDDropSign, jetID, start, end),
 		},
 	}
 }
@@ -98,7 +99,7 @@ func (r *ReplicaIter) NextRecords() ([]core.KV, error) {
 		return nil, ErrReplicatorDone
 	}
 	fc := &fetchchunk{
-		db:    r.db.db,
+		db:    r.db.backend,
 		limit: r.limitBytes,
 	}
 	for _, is := range r.istates {
@@ -115,6 +116,9 @@ func (r *ReplicaIter) NextRecords() ([]core.KV, error) {
 			r.lastpulse = lastpulse
 		}
 	}
+	if fc.lastkey != nil {
+		r.lastkey = fc.lastkey
+	}
 	return fc.records, nil
 }
 
@@ -123,6 +127,34 @@ func (r *ReplicaIter) LastSeenPulse() core.PulseNumber {
 	return r.lastpulse
 }
 
+// LastKey returns original (not nullified) key of the last record returned by NextRecords.
+//
+// It is used as a cursor for resuming iteration with Skip.
+func (r *ReplicaIter) LastKey() []byte {
+	return r.lastkey
+}
+
+// Skip moves iterator right after the cursor key returned by LastKey.
+func (r *ReplicaIter) Skip(cursor []byte) error {
+	if len(cursor) == 0 {
+		return nil
+	}
+	for i, is := range r.istates {
+		if is.prefix[0] != cursor[0] {
+			continue
+		}
+		for _, prev := range r.istates[:i] {
+			prev.start = nil
+		}
+		if is.start != nil && bytes.Compare(cursor, is.start) >= 0 {
+			is.start = append(append([]byte(nil), cursor...), 0)
+		}
+		r.lastkey = cursor
+		return nil
+	}
+	return errors.New("cursor doesn't belong to replica iterator scopes")
+}
+
 // ErrReplicatorDone is returned by an Replicator NextRecords method when the iteration is complete.
 var ErrReplicatorDone = errors.New("no more items in iterator")
 
@@ -136,10 +168,11 @@ func (r *ReplicaIter) isDone() bool {
 }
 
 type fetchchunk struct {
-	db      *badger.DB
+	db      Backend
 	records []core.KV
 	size    int
 	limit   int
+	lastkey []byte
 }
 
 func (fc *fetchchunk) fetch(
@@ -154,42 +187,30 @@ func (fc *fetchchunk) fetch(
 
 	var nextstart []byte
 	var lastpulse core.PulseNumber
-	err := fc.db.View(func(txn *badger.Txn) error {
-		it := txn.NewIterator(badger.DefaultIteratorOptions)
-		defer it.Close()
-
-		for it.Seek(start); it.ValidForPrefix(prefix); it.Next() {
-			item := it.Item()
-			if item == nil {
-				break
-			}
+	err := fc.db.View(func(txn BackendTxn) error {
+		nextstart = nil
+		return txn.Iterate(prefix, start, func(key, value []byte) (bool, error) {
 			// key prefix < end
-			if bytes.Compare(item.Key()[:len(end)], end) != -1 {
-				break
+			if bytes.Compare(key[:len(end)], end) != -1 {
+				return false, nil
 			}
 
-			key := item.KeyCopy(nil)
 			if fc.size > fc.limit {
 				nextstart = key
 				// inslogger.FromContext(ctx).Warnf("size > r.limit: %v > %v (nextstart=%v)",
 				// 	fc.size, fc.limit, hex.EncodeToString(key))
-				return nil
+				return false, nil
 			}
 
 			lastpulse = pulseFromKey(key)
+			fc.lastkey = append(fc.lastkey[:0], key...)
 			// fmt.Printf("Replica> key: %v (pulse=%v)\n", hex.EncodeToString(key), lastpulse)
 
-			value, err := it.Item().ValueCopy(nil)
-			if err != nil {
-				return err
-			}
-
 			NullifyJetInKey(key)
 			fc.records = append(fc.records, core.KV{K: key, V: value})
 			fc.size += len(key) + len(value)
-		}
-		nextstart = nil
-		return nil
+			return true, nil
+		})
 	})
 	return nextstart, lastpulse, err
 }
@@ -198,7 +219,7 @@ func (fc *fetchchunk) fetch(
 func NullifyJetInKey(key []byte) {
 	// if we remove jet part from drop, different drops from same pulses collapsed
 	// TODO: figure out how we want to send jet drops on heavy nodes - @Alexander Orlovsky 18.01.2019
-	if key[0] == scopeIDJetDrop {
+	if key[0] == scopeIDJetDrop || key[0] == scopeIDDropSign {
 		return
 	}
 	for i := 1; i < core.RecordHashSize; i++ {
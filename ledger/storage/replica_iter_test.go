@@ -23,7 +23,6 @@ import (
 	"sort"
 	"testing"
 
-	"github.com/dgraph-io/badger"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -76,7 +75,7 @@ func Test_StoreKeyValues(t *testing.T) {
 				allKVs = append(allKVs, recs...)
 			}
 		}
-		expectedrecs, expectedidxs = getallkeys(db.GetBadgerDB())
+		expectedrecs, expectedidxs = getallkeys(db.GetBackend())
 		nullifyJetInKeys(expectedrecs)
 		nullifyJetInKeys(expectedidxs)
 		sortkeys(expectedrecs)
@@ -92,7 +91,7 @@ func Test_StoreKeyValues(t *testing.T) {
 		defer cleaner()
 		err := db.StoreKeyValues(ctx, allKVs)
 		require.NoError(t, err)
-		gotrecs, gotidxs = getallkeys(db.GetBadgerDB())
+		gotrecs, gotidxs = getallkeys(db.GetBackend())
 	}()
 
 	assert.Equal(t, len(expectedrecs), len(gotrecs), "records counts are the same after restore")
@@ -133,7 +132,7 @@ func Test_ReplicaIter_FirstPulse(t *testing.T) {
 	}
 
 	got = sortkeys(got)
-	all, idxs := getallkeys(db.GetBadgerDB())
+	all, idxs := getallkeys(db.GetBackend())
 	all = append(all, idxs...)
 	all = sortkeys(all)
 
@@ -156,7 +155,7 @@ func Test_ReplicaIter_Base(t *testing.T) {
 	// it's easy to test simple case with zero Jet
 	jetID := *jet.NewID(0, nil)
 
-	recsBefore, idxBefore := getallkeys(db.GetBadgerDB())
+	recsBefore, idxBefore := getallkeys(db.GetBackend())
 	require.Nil(t, recsBefore)
 	require.Nil(t, idxBefore)
 
@@ -170,17 +169,17 @@ func Test_ReplicaIter_Base(t *testing.T) {
 		addRecords(ctx, t, db, jetID, lastPulse)
 		setDrop(ctx, t, db, jetID, lastPulse)
 
-		recs, _ := getallkeys(db.GetBadgerDB())
+		recs, _ := getallkeys(db.GetBackend())
 		recKeys := getdelta(recsBefore, recs)
 		recsBefore = recs
 
-		_, idxAll := getallkeys(db.GetBadgerDB())
+		_, idxAll := getallkeys(db.GetBackend())
 
 		recsPerPulse[i] = recKeys
 		ttPerPulse[i] = append(ttPerPulse[i], recKeys...)
 		ttPerPulse[i] = append(ttPerPulse[i], idxAll...)
 	}
-	_, idxsAfter := getallkeys(db.GetBadgerDB())
+	_, idxsAfter := getallkeys(db.GetBackend())
 
 	for i := 0; i < pulsescount; i++ {
 		// in range should be all record from the next pulses
@@ -275,7 +274,7 @@ func setDrop(
 	if err != nil {
 		require.NoError(t, err)
 	}
-	require.NotEqual(t, 0, dropSize)
+	require.NotNil(t, dropSize)
 	err = db.SetDrop(ctx, jetID, drop)
 	require.NoError(t, err)
 }
@@ -334,30 +333,29 @@ var (
 	scopeIDBlob     = byte(7)
 )
 
-func getallkeys(db *badger.DB) (records []key, indexes []key) {
-	txn := db.NewTransaction(true)
-	defer txn.Discard()
-
-	it := txn.NewIterator(badger.DefaultIteratorOptions)
-	defer it.Close()
-	for it.Rewind(); it.Valid(); it.Next() {
-		item := it.Item()
-		k := item.KeyCopy(nil)
-		pn := storage.Key(k).PulseNumber()
-		if pn == 0 {
-			continue
-		}
+func getallkeys(db storage.Backend) (records []key, indexes []key) {
+	err := db.View(func(txn storage.BackendTxn) error {
+		return txn.Iterate(nil, nil, func(k, _ []byte) (bool, error) {
+			pn := storage.Key(k).PulseNumber()
+			if pn == 0 {
+				return true, nil
+			}
 
-		switch k[0] {
-		case scopeIDRecord:
-			records = append(records, k)
-		case scopeIDBlob:
-			records = append(records, k)
-		case scopeIDJetDrop:
-			records = append(records, k)
-		case scopeIDLifeline:
-			indexes = append(indexes, k)
-		}
+			switch k[0] {
+			case scopeIDRecord:
+				records = append(records, k)
+			case scopeIDBlob:
+				records = append(records, k)
+			case scopeIDJetDrop:
+				records = append(records, k)
+			case scopeIDLifeline:
+				indexes = append(indexes, k)
+			}
+			return true, nil
+		})
+	})
+	if err != nil {
+		panic(err)
 	}
 	return
 }
@@ -386,3 +384,44 @@ func nullifyJetInKeys(keys []key) {
 		storage.NullifyJetInKey(k)
 	}
 }
+
+func Test_ReplicaIter_Skip(t *testing.T) {
+	t.Parallel()
+	ctx := inslogger.TestContext(t)
+	db, cleaner := storagetest.TmpDB(ctx, t)
+	defer cleaner()
+
+	jetID := testutils.RandomJet()
+	pn := pulseDelta(1)
+	addRecords(ctx, t, db, jetID, pn)
+	setDrop(ctx, t, db, jetID, pn)
+
+	nextkeys := func(replicator *storage.ReplicaIter, chunks int) (keys []key) {
+		for i := 0; i != chunks; i++ {
+			recs, err := replicator.NextRecords()
+			if err == storage.ErrReplicatorDone {
+				break
+			}
+			require.NoError(t, err)
+			for _, rec := range recs {
+				keys = append(keys, rec.K)
+			}
+		}
+		return
+	}
+	all := nextkeys(storage.NewReplicaIter(ctx, db, jetID, pn, pn+1, 99), -1)
+	require.NotEmpty(t, all)
+
+	for chunks := 1; chunks < 4; chunks++ {
+		replicator := storage.NewReplicaIter(ctx, db, jetID, pn, pn+1, 99)
+		got := nextkeys(replicator, chunks)
+
+		resumed := storage.NewReplicaIter(ctx, db, jetID, pn, pn+1, 99)
+		require.NoError(t, resumed.Skip(replicator.LastKey()))
+		got = append(got, nextkeys(resumed, -1)...)
+		require.Equal(t, all, got, "iteration resumed after %v chunks", chunks)
+	}
+
+	replicator := storage.NewReplicaIter(ctx, db, jetID, pn, pn+1, 99)
+	require.Error(t, replicator.Skip([]byte{0xff}), "cursor of unknown scope")
+}
@@ -0,0 +1,245 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package storage
+
+import (
+	"context"
+	"io"
+
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/instrumentation/inslogger"
+	"github.com/insolar/insolar/ledger/storage/jet"
+	"github.com/insolar/insolar/utils/snapshot"
+	"github.com/pkg/errors"
+)
+
+// SnapshotSource is a source name of ledger storage snapshots.
+const SnapshotSource = "ledger"
+
+// restoreBatchSize is a number of entries written in one transaction on restore.
+const restoreBatchSize = 1000
+
+// keyPulse returns pulse number of storage key. Zero is returned for keys which values
+// are mutable (indexes, pulses linked to the next one, system records), such keys are
+// included in every backup.
+func keyPulse(key []byte) core.PulseNumber {
+	switch key[0] {
+	case scopeIDSystem, scopeIDLifeline, scopeIDPulse, scopeIDPrototype, scopeIDObjectPrototype:
+		return 0
+	case scopeIDLocal:
+		if len(key) < 1+core.PulseNumberSize {
+			return 0
+		}
+		return pulseNumFromKey(1, key)
+	}
+	if len(key) < core.RecordHashSize+core.PulseNumberSize {
+		return 0
+	}
+	return pulseNumFromKey(core.RecordHashSize, key)
+}
+
+// Snapshot writes consistent point-in-time copy of the whole storage to w.
+//
+// Returns the latest pulse included into snapshot.
+func (db *DB) Snapshot(ctx context.Context, w io.Writer) (core.PulseNumber, error) {
+	return db.Backup(ctx, w, 0)
+}
+
+// Backup writes consistent copy of data added since provided pulse to w. Records, blobs,
+// drops, messages and local data are filtered by pulse, while pulses, indexes, jet trees,
+// drop sizes and sync client state are always written in full, since they are updated in place.
+//
+// The whole backup is read in one read-only transaction, so indexes and the records they point to
+// are taken at the same moment. Backends keep the read snapshot without blocking writers.
+//
+// Returns the latest pulse included into backup.
+func (db *DB) Backup(ctx context.Context, w io.Writer, since core.PulseNumber) (core.PulseNumber, error) {
+	db.closeLock.RLock()
+	defer db.closeLock.RUnlock()
+	if db.isClosed {
+		return 0, ErrClosed
+	}
+
+	var (
+		latest core.PulseNumber
+		count  uint64
+	)
+	err := db.backend.View(func(txn BackendTxn) error {
+		buf, err := txn.Get(prefixkey(scopeIDSystem, []byte{sysLatestPulse}))
+		if err != nil {
+			return errors.Wrap(err, "failed to get latest pulse")
+		}
+		pulse, err := toPulse(buf)
+		if err != nil {
+			return errors.Wrap(err, "failed to decode latest pulse")
+		}
+		latest = pulse.Pulse.PulseNumber
+
+		sw, err := snapshot.NewWriter(w, snapshot.Header{
+			Source: SnapshotSource,
+			Pulse:  latest,
+			Since:  since,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = txn.Iterate(nil, nil, func(k, v []byte) (bool, error) {
+			// Data of pulses after the latest one may be written before the pulse itself.
+			pn := keyPulse(k)
+			if pn != 0 && (pn > latest || since > 0 && pn < since) {
+				return true, nil
+			}
+			return true, sw.Write(k, v)
+		})
+		if err != nil {
+			return err
+		}
+		count = sw.Count()
+		return sw.Close()
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "[ Backup ] failed to write backup")
+	}
+
+	inslogger.FromContext(ctx).Infof(
+		"storage backup since pulse %v is written: latest pulse %v, %v entries", since, latest, count,
+	)
+	return latest, nil
+}
+
+// Restore writes snapshot or incremental backup from r into storage.
+//
+// Full snapshot should be restored into empty storage, backups are applied on top of it
+// in the order they were taken.
+func (db *DB) Restore(ctx context.Context, r io.Reader) (*snapshot.Header, error) {
+	db.closeLock.RLock()
+	defer db.closeLock.RUnlock()
+	if db.isClosed {
+		return nil, ErrClosed
+	}
+
+	sr, err := snapshot.NewReader(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ Restore ] failed to read snapshot header")
+	}
+	header := sr.Header()
+	if header.Source != SnapshotSource {
+		return nil, errors.Errorf("[ Restore ] snapshot of %q can't be restored into ledger storage", header.Source)
+	}
+
+	type entry struct{ k, v []byte }
+	batch := make([]entry, 0, restoreBatchSize)
+	flush := func() error {
+		err := db.backend.Update(func(txn BackendTxn) error {
+			for _, e := range batch {
+				if err := txn.Set(e.k, e.v); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		batch = batch[:0]
+		return err
+	}
+
+	var count int
+	for {
+		k, v, err := sr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "[ Restore ] failed to read snapshot")
+		}
+		batch = append(batch, entry{k: k, v: v})
+		count++
+		if len(batch) == restoreBatchSize {
+			if err := flush(); err != nil {
+				return nil, errors.Wrap(err, "[ Restore ] failed to write entries")
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return nil, errors.Wrap(err, "[ Restore ] failed to write entries")
+	}
+
+	inslogger.FromContext(ctx).Infof(
+		"storage snapshot is restored: latest pulse %v, since pulse %v, %v entries", header.Pulse, header.Since, count,
+	)
+	return &header, nil
+}
+
+// DropMismatch describes jet drop which hash doesn't match stored records.
+type DropMismatch struct {
+	JetPrefix []byte
+	Pulse     core.PulseNumber
+}
+
+// DropsVerification is a result of drop hashes verification.
+type DropsVerification struct {
+	// Checked is a number of verified drops.
+	Checked int
+	// Skipped is a number of drops without hash (e.g. genesis drop).
+	Skipped int
+	// Mismatched drops have hash which differs from hash of stored records.
+	Mismatched []DropMismatch
+}
+
+// VerifyDrops recalculates hashes of all stored jet drops from their records and compares them
+// with saved ones. It is intended to check storage after restore.
+//
+// Light nodes remove records synced to heavy, so mismatches are expected there for cleaned up pulses.
+func (db *DB) VerifyDrops(ctx context.Context) (*DropsVerification, error) {
+	result := &DropsVerification{}
+	err := db.backend.View(func(txn BackendTxn) error {
+		dropPrefix := []byte{scopeIDJetDrop}
+		return txn.Iterate(dropPrefix, dropPrefix, func(k, v []byte) (bool, error) {
+			drop, err := jet.Decode(v)
+			if err != nil {
+				return false, errors.Wrapf(err, "failed to decode drop %v", bytes2hex(k))
+			}
+			if len(drop.Hash) == 0 {
+				result.Skipped++
+				return true, nil
+			}
+
+			var records [][]byte
+			jetPrefix := k[1 : len(k)-core.PulseNumberSize]
+			err = iterateDropRecords(txn, jetPrefix, drop.Pulse, func(_ core.RecordID, rec []byte) error {
+				records = append(records, append([]byte(nil), rec...))
+				return nil
+			})
+			if err != nil {
+				return false, err
+			}
+
+			result.Checked++
+			if !checkDropHash(db.PlatformCryptographyScheme, drop, records) {
+				result.Mismatched = append(result.Mismatched, DropMismatch{
+					JetPrefix: k[1 : len(k)-core.PulseNumberSize],
+					Pulse:     drop.Pulse,
+				})
+			}
+			return true, nil
+		})
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "[ VerifyDrops ] failed to verify drops")
+	}
+	return result, nil
+}
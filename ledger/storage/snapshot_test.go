@@ -0,0 +1,208 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package storage_test
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/instrumentation/inslogger"
+	"github.com/insolar/insolar/ledger/storage"
+	"github.com/insolar/insolar/ledger/storage/storagetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func allKeys(t *testing.T, db *storage.DB) map[string][]byte {
+	kv := map[string][]byte{}
+	err := db.GetBackend().View(func(txn storage.BackendTxn) error {
+		return txn.Iterate(nil, nil, func(k, v []byte) (bool, error) {
+			kv[string(k)] = v
+			return true, nil
+		})
+	})
+	require.NoError(t, err)
+	return kv
+}
+
+func TestDB_SnapshotAndRestore(t *testing.T) {
+	t.Parallel()
+	storagetest.ForEachBackend(t, func(t *testing.T, backend storagetest.Option) {
+		ctx := inslogger.TestContext(t)
+		db, cleaner := storagetest.TmpDB(ctx, t, backend)
+		defer cleaner()
+
+		jetID := core.TODOJetID
+		pulse1 := core.FirstPulseNumber + 1
+		pulse2 := core.FirstPulseNumber + 2
+
+		require.NoError(t, db.AddPulse(ctx, core.Pulse{PulseNumber: core.PulseNumber(pulse1)}))
+		addRecords(ctx, t, db, jetID, core.PulseNumber(pulse1))
+		setDrop(ctx, t, db, jetID, core.PulseNumber(pulse1))
+
+		var full bytes.Buffer
+		latest, err := db.Snapshot(ctx, &full)
+		require.NoError(t, err)
+		assert.Equal(t, core.PulseNumber(pulse1), latest)
+
+		require.NoError(t, db.AddPulse(ctx, core.Pulse{PulseNumber: core.PulseNumber(pulse2)}))
+		addRecords(ctx, t, db, jetID, core.PulseNumber(pulse2))
+		setDrop(ctx, t, db, jetID, core.PulseNumber(pulse2))
+
+		var incremental bytes.Buffer
+		latest, err = db.Backup(ctx, &incremental, core.PulseNumber(pulse2))
+		require.NoError(t, err)
+		assert.Equal(t, core.PulseNumber(pulse2), latest)
+		incrementalData := incremental.Bytes()
+
+		restored, restoredCleaner := storagetest.TmpDB(ctx, t, backend, storagetest.DisableBootstrap())
+		defer restoredCleaner()
+
+		header, err := restored.Restore(ctx, &full)
+		require.NoError(t, err)
+		assert.True(t, header.IsFull())
+		header, err = restored.Restore(ctx, &incremental)
+		require.NoError(t, err)
+		assert.Equal(t, core.PulseNumber(pulse2), header.Since)
+
+		assert.Equal(t, allKeys(t, db), allKeys(t, restored))
+
+		// incremental backup doesn't contain drops of previous pulses
+		partial, partialCleaner := storagetest.TmpDB(ctx, t, backend, storagetest.DisableBootstrap())
+		defer partialCleaner()
+		_, err = partial.Restore(ctx, bytes.NewReader(incrementalData))
+		require.NoError(t, err)
+		_, err = partial.GetDrop(ctx, jetID, core.PulseNumber(pulse1))
+		assert.Equal(t, storage.ErrNotFound, err)
+		_, err = partial.GetDrop(ctx, jetID, core.PulseNumber(pulse2))
+		assert.NoError(t, err)
+
+		verification, err := restored.VerifyDrops(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 2, verification.Checked)
+		assert.Empty(t, verification.Mismatched)
+	})
+}
+
+func TestDB_Backup_Large(t *testing.T) {
+	t.Parallel()
+	ctx := inslogger.TestContext(t)
+	db, cleaner := storagetest.TmpDB(ctx, t, storagetest.Backend(storage.BackendMemory))
+	defer cleaner()
+
+	pulse := core.PulseNumber(core.FirstPulseNumber + 1)
+	require.NoError(t, db.AddPulse(ctx, core.Pulse{PulseNumber: pulse}))
+	for i := 0; i < 2500; i++ {
+		require.NoError(t, db.SetLocalData(ctx, pulse, []byte(fmt.Sprintf("key%04d", i)), []byte{1}))
+	}
+	// Entries of pulses after the latest one are not included.
+	require.NoError(t, db.SetLocalData(ctx, pulse+1, []byte("future"), []byte{1}))
+
+	var full bytes.Buffer
+	latest, err := db.Snapshot(ctx, &full)
+	require.NoError(t, err)
+	assert.Equal(t, pulse, latest)
+
+	restored, restoredCleaner := storagetest.TmpDB(
+		ctx, t, storagetest.Backend(storage.BackendMemory), storagetest.DisableBootstrap(),
+	)
+	defer restoredCleaner()
+	_, err = restored.Restore(ctx, &full)
+	require.NoError(t, err)
+
+	expected := allKeys(t, db)
+	assert.Equal(t, len(expected)-1, len(allKeys(t, restored)))
+	data, err := restored.GetLocalData(ctx, pulse, []byte("key2499"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte{1}, data)
+	_, err = restored.GetLocalData(ctx, pulse+1, []byte("future"))
+	assert.Equal(t, storage.ErrNotFound, err)
+}
+
+// blockingWriter blocks the first write until release is closed.
+type blockingWriter struct {
+	bytes.Buffer
+	once    sync.Once
+	blocked chan struct{}
+	release chan struct{}
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	w.once.Do(func() {
+		close(w.blocked)
+		<-w.release
+	})
+	return w.Buffer.Write(p)
+}
+
+func TestDB_Backup_Consistent(t *testing.T) {
+	t.Parallel()
+	ctx := inslogger.TestContext(t)
+	db, cleaner := storagetest.TmpDB(ctx, t, storagetest.Backend(storage.BackendBadger))
+	defer cleaner()
+
+	pulse := core.PulseNumber(core.FirstPulseNumber + 1)
+	require.NoError(t, db.AddPulse(ctx, core.Pulse{PulseNumber: pulse}))
+	require.NoError(t, db.SetLocalData(ctx, pulse, []byte("before"), []byte{1}))
+
+	w := &blockingWriter{blocked: make(chan struct{}), release: make(chan struct{})}
+	done := make(chan error)
+	go func() {
+		_, err := db.Snapshot(ctx, w)
+		done <- err
+	}()
+
+	// Data written while backup is in progress is not included into it.
+	<-w.blocked
+	require.NoError(t, db.SetLocalData(ctx, pulse, []byte("during"), []byte{1}))
+	close(w.release)
+	require.NoError(t, <-done)
+
+	restored, restoredCleaner := storagetest.TmpDB(
+		ctx, t, storagetest.Backend(storage.BackendMemory), storagetest.DisableBootstrap(),
+	)
+	defer restoredCleaner()
+	_, err := restored.Restore(ctx, &w.Buffer)
+	require.NoError(t, err)
+
+	_, err = restored.GetLocalData(ctx, pulse, []byte("before"))
+	require.NoError(t, err)
+	_, err = restored.GetLocalData(ctx, pulse, []byte("during"))
+	assert.Equal(t, storage.ErrNotFound, err)
+}
+
+func TestDB_VerifyDrops_Mismatch(t *testing.T) {
+	t.Parallel()
+	ctx := inslogger.TestContext(t)
+	db, cleaner := storagetest.TmpDB(ctx, t)
+	defer cleaner()
+
+	jetID := core.TODOJetID
+	pulse := core.PulseNumber(core.FirstPulseNumber + 1)
+	addRecords(ctx, t, db, jetID, pulse)
+	setDrop(ctx, t, db, jetID, pulse)
+	// record added after drop is created breaks drop hash
+	addRecords(ctx, t, db, jetID, pulse)
+
+	verification, err := db.VerifyDrops(ctx)
+	require.NoError(t, err)
+	require.Len(t, verification.Mismatched, 1)
+	assert.Equal(t, pulse, verification.Mismatched[0].Pulse)
+}
@@ -29,8 +29,13 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+// BackendEnv is an environment variable which sets default storage backend of TmpDB,
+// so the whole ledger test suite can be run against any backend.
+const BackendEnv = "INSOLAR_TEST_STORAGE_BACKEND"
+
 type tmpDBOptions struct {
 	dir         string
+	backend     string
 	nobootstrap bool
 }
 
@@ -44,6 +49,23 @@ func Dir(dir string) Option {
 	}
 }
 
+// Backend defines storage backend for database.
+func Backend(name string) Option {
+	return func(opts *tmpDBOptions) {
+		opts.backend = name
+	}
+}
+
+// ForEachBackend runs test as subtest for every registered storage backend.
+func ForEachBackend(t *testing.T, test func(t *testing.T, backend Option)) {
+	for _, name := range storage.Backends() {
+		name := name
+		t.Run(name, func(t *testing.T) {
+			test(t, Backend(name))
+		})
+	}
+}
+
 // DisableBootstrap skip bootstrap records creation.
 func DisableBootstrap() Option {
 	return func(opts *tmpDBOptions) {
@@ -51,11 +73,12 @@ func DisableBootstrap() Option {
 	}
 }
 
-// TmpDB returns BadgerDB's storage implementation and cleanup function.
+// TmpDB returns storage implementation and cleanup function.
 //
-// Creates BadgerDB in temporary directory and uses t for errors reporting.
+// Creates database in temporary directory and uses t for errors reporting. Backend is taken
+// from options, BackendEnv environment variable or BadgerDB is used.
 func TmpDB(ctx context.Context, t testing.TB, options ...Option) (*storage.DB, func()) {
-	opts := &tmpDBOptions{}
+	opts := &tmpDBOptions{backend: os.Getenv(BackendEnv)}
 	for _, o := range options {
 		o(opts)
 	}
@@ -65,6 +88,7 @@ func TmpDB(ctx context.Context, t testing.TB, options ...Option) (*storage.DB, f
 	db, err := storage.NewDB(configuration.Ledger{
 		JetSizesHistoryDepth: 10,
 		Storage: configuration.Storage{
+			Backend:       opts.backend,
 			DataDirectory: tmpdir,
 		},
 	}, nil)
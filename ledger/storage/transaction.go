@@ -19,7 +19,6 @@ package storage
 import (
 	"context"
 
-	"github.com/dgraph-io/badger"
 	"github.com/insolar/insolar/core"
 	"github.com/insolar/insolar/ledger/storage/index"
 	"github.com/insolar/insolar/ledger/storage/jet"
@@ -55,19 +54,15 @@ func (m *TransactionManager) Commit() error {
 	if len(m.txupdates) == 0 {
 		return nil
 	}
-	var err error
-	tx := m.db.db.NewTransaction(m.update)
-	defer tx.Discard()
-	for _, rec := range m.txupdates {
-		err = tx.Set(rec.k, rec.v)
-		if err != nil {
-			break
+	return m.db.backend.Update(func(txn BackendTxn) error {
+		for _, rec := range m.txupdates {
+			err := txn.Set(rec.k, rec.v)
+			if err != nil {
+				return err
+			}
 		}
-	}
-	if err != nil {
-		return err
-	}
-	return tx.Commit(nil)
+		return nil
+	})
 }
 
 // Discard terminates transaction without disk writes.
@@ -106,14 +101,14 @@ func (m *TransactionManager) SetBlob(ctx context.Context, jetID core.RecordID, p
 	k := prefixkey(scopeIDBlob, jetPrefix, id[:])
 
 	// TODO: @andreyromancev. 16.01.19. Blob override is ok.
-	// geterr := m.db.db.View(func(tx *badger.Txn) error {
+	// geterr := m.db.backend.View(func(tx BackendTxn) error {
 	// 	_, err := tx.Get(k)
 	// 	return err
 	// })
 	// if geterr == nil {
 	// 	return id, ErrOverride
 	// }
-	// if geterr != badger.ErrKeyNotFound {
+	// if geterr != ErrNotFound {
 	// 	return nil, ErrNotFound
 	// }
 
@@ -145,14 +140,14 @@ func (m *TransactionManager) SetRecord(ctx context.Context, j core.RecordID, pul
 	id := record.NewRecordIDFromRecord(m.db.PlatformCryptographyScheme, pulseNumber, rec)
 	_, prefix := jet.Jet(j)
 	k := prefixkey(scopeIDRecord, prefix, id[:])
-	geterr := m.db.db.View(func(tx *badger.Txn) error {
+	geterr := m.db.backend.View(func(tx BackendTxn) error {
 		_, err := tx.Get(k)
 		return err
 	})
 	if geterr == nil {
 		return id, ErrOverride
 	}
-	if geterr != badger.ErrKeyNotFound {
+	if geterr != ErrNotFound {
 		return nil, geterr
 	}
 
@@ -225,29 +220,23 @@ func (m *TransactionManager) get(ctx context.Context, key []byte) ([]byte, error
 		return kv.v, nil
 	}
 
-	txn := m.db.db.NewTransaction(false)
-	defer txn.Discard()
-	item, err := txn.Get(key)
+	var value []byte
+	err := m.db.backend.View(func(txn BackendTxn) error {
+		var err error
+		value, err = txn.Get(key)
+		return err
+	})
 	if err != nil {
-		if err == badger.ErrKeyNotFound {
-			return nil, ErrNotFound
-		}
 		return nil, err
 	}
-	return item.ValueCopy(nil)
+	return value, nil
 }
 
 // removes value by key
 func (m *TransactionManager) remove(ctx context.Context, key []byte) error {
 	debugf(ctx, "get key %v", bytes2hex(key))
 
-	txn := m.db.db.NewTransaction(true)
-	defer txn.Discard()
-
-	err := txn.Delete(key)
-	if err != nil {
-		return err
-	}
-
-	return txn.Commit(nil)
+	return m.db.backend.Update(func(txn BackendTxn) error {
+		return txn.Delete(key)
+	})
 }
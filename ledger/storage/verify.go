@@ -0,0 +1,353 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/ledger/storage/jet"
+	"github.com/insolar/insolar/ledger/storage/record"
+	"github.com/pkg/errors"
+	"github.com/ugorji/go/codec"
+)
+
+// Reasons of ChainMismatch.
+const (
+	MismatchRecordHash = "record hash mismatch"
+	MismatchBlobHash   = "blob hash mismatch"
+	MismatchDropHash   = "drop hash mismatch"
+	MismatchPrevHash   = "previous drop hash mismatch"
+	MismatchJetTree    = "drop doesn't match jet tree"
+)
+
+// ChainMismatch describes inconsistency found in jet drops chain.
+type ChainMismatch struct {
+	JetPrefix []byte
+	Pulse     core.PulseNumber
+	Reason    string
+	// ID is set for mismatched records and blobs.
+	ID *core.RecordID
+}
+
+func (m ChainMismatch) String() string {
+	s := fmt.Sprintf("pulse %v, jet prefix %v: %v", m.Pulse, bytes2hex(m.JetPrefix), m.Reason)
+	if m.ID != nil {
+		s += " " + m.ID.DebugString()
+	}
+	return s
+}
+
+// ChainVerification is a result of jet drops chains verification.
+type ChainVerification struct {
+	// Drops is a number of verified drops.
+	Drops int
+	// Records and Blobs are numbers of verified records and blobs.
+	Records int
+	Blobs   int
+	// LastPulse is the latest pulse of verified drops.
+	LastPulse core.PulseNumber
+	// Mismatches are ordered by pulse.
+	Mismatches []ChainMismatch
+	// Next is a cursor of the first drop left unverified because of ChainVerifyOptions.Limit.
+	// It is nil if all drops are verified.
+	Next []byte
+}
+
+// Append adds results of verification of the next batch of drops.
+func (v *ChainVerification) Append(batch *ChainVerification) {
+	v.Drops += batch.Drops
+	v.Records += batch.Records
+	v.Blobs += batch.Blobs
+	if batch.LastPulse > v.LastPulse {
+		v.LastPulse = batch.LastPulse
+	}
+	v.Mismatches = append(v.Mismatches, batch.Mismatches...)
+	sort.SliceStable(v.Mismatches, func(i, j int) bool {
+		return v.Mismatches[i].Pulse < v.Mismatches[j].Pulse
+	})
+	v.Next = batch.Next
+}
+
+// FirstMismatch returns the earliest found mismatch or nil if chains are consistent.
+func (v *ChainVerification) FirstMismatch() *ChainMismatch {
+	if len(v.Mismatches) == 0 {
+		return nil
+	}
+	return &v.Mismatches[0]
+}
+
+// ChainVerifyOptions configures VerifyChains.
+type ChainVerifyOptions struct {
+	// From is a pulse verification starts from, all drops are verified if it is zero.
+	From core.PulseNumber
+	// SkipDropHashes disables drop hash checks. Records of storage pruned by retention policy
+	// don't match drop hashes anymore, but hash chains and hashes of left records are still verified.
+	SkipDropHashes bool
+	// Cursor is a drop verification starts from (Next of previous verification), nil means the first drop.
+	Cursor []byte
+	// Limit is a max number of drops visited by verification, zero means no limit.
+	Limit int
+}
+
+// VerifyChains walks stored jet drops and checks them against data they are built from:
+//
+// - ids of drop records and blobs are recalculated from their content;
+// - records Merkle root and drop hash are recalculated from previous drop hash and drop records;
+// - previous hash is compared to hash of the same jet drop in previous pulse (parent after split, branches after merge);
+// - drop jet is checked to be a leaf of jet tree of drop pulse.
+//
+// Drops without records root are checked with legacy hash (see jet.JetDrop). Checks which require missing data
+// (e.g. previous pulse drop removed on light node) are skipped.
+//
+// All drops are verified in one storage transaction unless opts.Limit is set. Use Next of the result as
+// opts.Cursor to verify the next batch.
+func (db *DB) VerifyChains(ctx context.Context, opts ChainVerifyOptions) (*ChainVerification, error) {
+	result := &ChainVerification{}
+	err := db.backend.View(func(txn BackendTxn) error {
+		v := &chainVerifier{
+			txn:    txn,
+			scheme: db.PlatformCryptographyScheme,
+			opts:   opts,
+			result: result,
+			trees:  map[core.PulseNumber]*jet.Tree{},
+		}
+		dropPrefix := []byte{scopeIDJetDrop}
+		start := dropPrefix
+		if opts.Cursor != nil {
+			start = opts.Cursor
+		}
+		visited := 0
+		return txn.Iterate(dropPrefix, start, func(k, val []byte) (bool, error) {
+			if opts.Limit > 0 && visited == opts.Limit {
+				result.Next = k
+				return false, nil
+			}
+			visited++
+			jetPrefix := k[1 : len(k)-core.PulseNumberSize]
+			drop, err := jet.Decode(val)
+			if err != nil {
+				return false, errors.Wrapf(err, "failed to decode drop %v", bytes2hex(k))
+			}
+			if drop.Pulse < opts.From {
+				return true, nil
+			}
+			return true, v.verifyDrop(jetPrefix, drop)
+		})
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "[ VerifyChains ] failed to verify chains")
+	}
+	sort.SliceStable(result.Mismatches, func(i, j int) bool {
+		return result.Mismatches[i].Pulse < result.Mismatches[j].Pulse
+	})
+	return result, nil
+}
+
+type chainVerifier struct {
+	txn    BackendTxn
+	scheme core.PlatformCryptographyScheme
+	opts   ChainVerifyOptions
+	result *ChainVerification
+	// trees caches jet trees by pulse, nil is cached for pulses without tree.
+	trees map[core.PulseNumber]*jet.Tree
+}
+
+func (v *chainVerifier) mismatch(jetPrefix []byte, pn core.PulseNumber, reason string, id *core.RecordID) {
+	v.result.Mismatches = append(v.result.Mismatches, ChainMismatch{
+		JetPrefix: append([]byte(nil), jetPrefix...),
+		Pulse:     pn,
+		Reason:    reason,
+		ID:        id,
+	})
+}
+
+func (v *chainVerifier) verifyDrop(jetPrefix []byte, drop *jet.JetDrop) error {
+	v.result.Drops++
+	if drop.Pulse > v.result.LastPulse {
+		v.result.LastPulse = drop.Pulse
+	}
+
+	var records [][]byte
+	err := iterateDropRecords(v.txn, jetPrefix, drop.Pulse, func(id core.RecordID, rec []byte) error {
+		records = append(records, append([]byte(nil), rec...))
+		v.result.Records++
+		if calculated := v.recordID(drop.Pulse, rec); calculated == nil || *calculated != id {
+			v.mismatch(jetPrefix, drop.Pulse, MismatchRecordHash, &id)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	blobPrefix := prefixkey(scopeIDBlob, jetPrefix, drop.Pulse.Bytes())
+	err = v.txn.Iterate(blobPrefix, blobPrefix, func(k, blob []byte) (bool, error) {
+		v.result.Blobs++
+		id := idFromKey(k)
+		if *record.CalculateIDForBlob(v.scheme, drop.Pulse, blob) != id {
+			v.mismatch(jetPrefix, drop.Pulse, MismatchBlobHash, &id)
+		}
+		return true, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Genesis drops have no hash.
+	if !v.opts.SkipDropHashes && len(drop.Hash) > 0 && !checkDropHash(v.scheme, drop, records) {
+		v.mismatch(jetPrefix, drop.Pulse, MismatchDropHash, nil)
+	}
+
+	jetID, err := v.dropJet(jetPrefix, drop.Pulse)
+	if err != nil {
+		return err
+	}
+	prevHash, ok, err := v.prevHash(jetPrefix, jetID, drop.Pulse)
+	if err != nil {
+		return err
+	}
+	if ok && !bytes.Equal(prevHash, drop.PrevHash) {
+		v.mismatch(jetPrefix, drop.Pulse, MismatchPrevHash, nil)
+	}
+	return nil
+}
+
+// recordID calculates record id from serialized record. Nil is returned for corrupted records.
+func (v *chainVerifier) recordID(pn core.PulseNumber, buf []byte) (id *core.RecordID) {
+	defer func() {
+		if r := recover(); r != nil {
+			id = nil
+		}
+	}()
+	if len(buf) < record.TypeIDSize {
+		return nil
+	}
+	return record.NewRecordIDFromRecord(v.scheme, pn, record.DeserializeRecord(buf))
+}
+
+// dropJet finds jet of drop in jet tree of drop pulse. Nil is returned if there is no tree for pulse
+// or drop doesn't belong to tree leaf (mismatch is reported in this case).
+func (v *chainVerifier) dropJet(jetPrefix []byte, pn core.PulseNumber) (*core.RecordID, error) {
+	tree, err := v.jetTree(pn)
+	if err != nil || tree == nil {
+		return nil, err
+	}
+	var id core.RecordID
+	copy(id[core.PulseNumberSize:], jetPrefix)
+	jetID, _ := tree.Find(id)
+	if _, leafPrefix := jet.Jet(*jetID); !bytes.Equal(leafPrefix, jetPrefix) {
+		v.mismatch(jetPrefix, pn, MismatchJetTree, nil)
+		return nil, nil
+	}
+	return jetID, nil
+}
+
+func (v *chainVerifier) jetTree(pn core.PulseNumber) (*jet.Tree, error) {
+	if tree, ok := v.trees[pn]; ok {
+		return tree, nil
+	}
+	buf, err := v.txn.Get(prefixkey(scopeIDSystem, []byte{sysJetTree}, pn.Bytes()))
+	if err == ErrNotFound {
+		v.trees[pn] = nil
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var tree jet.Tree
+	if err := codec.NewDecoderBytes(buf, &codec.CborHandle{}).Decode(&tree); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode jet tree of pulse %v", pn)
+	}
+	v.trees[pn] = &tree
+	return &tree, nil
+}
+
+// prevHash returns hash of the previous pulse drop the drop is chained to. If jet is known and both of its branches
+// have drops in the previous pulse, jet was merged and the hash commits to both of them. Otherwise drop of the same
+// prefix is tried first (jet is the same or it is the left child after split), then drop of the parent jet.
+// False is returned if there is no such drop in storage.
+func (v *chainVerifier) prevHash(jetPrefix []byte, jetID *core.RecordID, pn core.PulseNumber) ([]byte, bool, error) {
+	buf, err := v.txn.Get(prefixkey(scopeIDPulse, pn.Bytes()))
+	if err == ErrNotFound {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	pulse, err := toPulse(buf)
+	if err != nil {
+		return nil, false, err
+	}
+	if pulse.Prev == nil {
+		return nil, false, nil
+	}
+
+	if jetID != nil {
+		if _, rightID, ok := jet.Children(*jetID); ok {
+			_, rightPrefix := jet.Jet(rightID)
+			right, err := v.drop(rightPrefix, *pulse.Prev)
+			if err != nil {
+				return nil, false, err
+			}
+			if right != nil {
+				left, err := v.drop(jetPrefix, *pulse.Prev)
+				if err != nil || left == nil {
+					return nil, false, err
+				}
+				return jet.MergedPrevHash(v.scheme, left.Hash, right.Hash), true, nil
+			}
+		}
+	}
+
+	prefixes := [][]byte{jetPrefix}
+	if jetID != nil {
+		_, parentPrefix := jet.Jet(jet.Parent(*jetID))
+		prefixes = append(prefixes, parentPrefix)
+	}
+	for _, prefix := range prefixes {
+		prev, err := v.drop(prefix, *pulse.Prev)
+		if err != nil {
+			return nil, false, err
+		}
+		if prev != nil {
+			return prev.Hash, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+// drop returns drop of provided prefix and pulse. Nil is returned if there is no such drop in storage.
+func (v *chainVerifier) drop(jetPrefix []byte, pn core.PulseNumber) (*jet.JetDrop, error) {
+	buf, err := v.txn.Get(prefixkey(scopeIDJetDrop, jetPrefix, pn.Bytes()))
+	if err == ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return jet.Decode(buf)
+}
+
+func idFromKey(key []byte) core.RecordID {
+	var id core.RecordID
+	copy(id[:], key[core.RecordHashSize:])
+	return id
+}
@@ -0,0 +1,201 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package storage_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/instrumentation/inslogger"
+	"github.com/insolar/insolar/ledger/storage"
+	"github.com/insolar/insolar/ledger/storage/jet"
+	"github.com/insolar/insolar/ledger/storage/record"
+	"github.com/insolar/insolar/ledger/storage/storagetest"
+	"github.com/insolar/insolar/platformpolicy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDB_VerifyChains(t *testing.T) {
+	t.Parallel()
+	ctx := inslogger.TestContext(t)
+	db, cleaner := storagetest.TmpDB(ctx, t)
+	defer cleaner()
+
+	root := *jet.NewID(0, nil)
+	p1 := core.PulseNumber(core.FirstPulseNumber + 10)
+	p2 := core.PulseNumber(core.FirstPulseNumber + 20)
+	p3 := core.PulseNumber(core.FirstPulseNumber + 30)
+	for _, pn := range []core.PulseNumber{p1, p2, p3} {
+		require.NoError(t, db.AddPulse(ctx, core.Pulse{PulseNumber: pn}))
+	}
+
+	createDrop := func(jetID core.RecordID, pn core.PulseNumber, prevHash []byte) *jet.JetDrop {
+		drop, _, _, err := db.CreateDrop(ctx, jetID, pn, prevHash)
+		require.NoError(t, err)
+		require.NoError(t, db.SetDrop(ctx, jetID, drop))
+		return drop
+	}
+
+	// Root jet is split in the second pulse.
+	addRecords(ctx, t, db, root, p1)
+	rootDrop := createDrop(root, p1, nil)
+
+	left, right, err := jet.NewTree(true).Split(root)
+	require.NoError(t, err)
+	require.NoError(t, db.UpdateJetTree(ctx, p2, true, *left, *right))
+	addRecords(ctx, t, db, *left, p2)
+	corruptedID, err := db.SetRecord(ctx, *right, p2, &record.RequestRecord{Payload: []byte("request")})
+	require.NoError(t, err)
+	createDrop(*left, p2, rootDrop.Hash)
+	createDrop(*right, p2, rootDrop.Hash)
+
+	t.Run("consistent chains", func(t *testing.T) {
+		verification, err := db.VerifyChains(ctx, storage.ChainVerifyOptions{})
+		require.NoError(t, err)
+		assert.Nil(t, verification.FirstMismatch())
+		// Genesis drop is verified too.
+		assert.Equal(t, 4, verification.Drops)
+		assert.Equal(t, p2, verification.LastPulse)
+	})
+
+	t.Run("batches", func(t *testing.T) {
+		verification := &storage.ChainVerification{}
+		batches := 0
+		for {
+			batch, err := db.VerifyChains(ctx, storage.ChainVerifyOptions{Cursor: verification.Next, Limit: 3})
+			require.NoError(t, err)
+			batches++
+			verification.Append(batch)
+			if verification.Next == nil {
+				break
+			}
+		}
+		assert.Equal(t, 2, batches)
+		assert.Nil(t, verification.FirstMismatch())
+		assert.Equal(t, 4, verification.Drops)
+		assert.Equal(t, p2, verification.LastPulse)
+	})
+
+	t.Run("broken previous hash", func(t *testing.T) {
+		require.NoError(t, db.UpdateJetTree(ctx, p3, true, *left, *right))
+		createDrop(*right, p3, []byte("wrong hash"))
+
+		verification, err := db.VerifyChains(ctx, storage.ChainVerifyOptions{From: p3})
+		require.NoError(t, err)
+		require.Len(t, verification.Mismatches, 1)
+		assert.Equal(t, storage.MismatchPrevHash, verification.Mismatches[0].Reason)
+		assert.Equal(t, p3, verification.Mismatches[0].Pulse)
+		assert.Equal(t, 1, verification.Drops)
+	})
+
+	t.Run("corrupted record", func(t *testing.T) {
+		corrupted := record.SerializeRecord(&record.RequestRecord{Payload: []byte("corrupted")})
+		err := db.GetBackend().Update(func(txn storage.BackendTxn) error {
+			var recordKey []byte
+			err := txn.Iterate(nil, nil, func(k, _ []byte) (bool, error) {
+				if bytes.HasSuffix(k, corruptedID[:]) {
+					recordKey = k
+					return false, nil
+				}
+				return true, nil
+			})
+			require.NoError(t, err)
+			require.NotNil(t, recordKey)
+			return txn.Set(recordKey, corrupted)
+		})
+		require.NoError(t, err)
+
+		verification, err := db.VerifyChains(ctx, storage.ChainVerifyOptions{})
+		require.NoError(t, err)
+		first := verification.FirstMismatch()
+		require.NotNil(t, first)
+		assert.Equal(t, p2, first.Pulse)
+
+		var reasons []string
+		for _, m := range verification.Mismatches {
+			if m.Pulse == p2 {
+				reasons = append(reasons, m.Reason)
+			}
+		}
+		assert.Equal(t, []string{storage.MismatchRecordHash, storage.MismatchDropHash}, reasons)
+		assert.Equal(t, corruptedID, verification.Mismatches[0].ID)
+
+		verification, err = db.VerifyChains(ctx, storage.ChainVerifyOptions{SkipDropHashes: true})
+		require.NoError(t, err)
+		for _, m := range verification.Mismatches {
+			assert.NotEqual(t, storage.MismatchDropHash, m.Reason)
+		}
+	})
+}
+
+func TestDB_VerifyChains_Merge(t *testing.T) {
+	t.Parallel()
+	ctx := inslogger.TestContext(t)
+	db, cleaner := storagetest.TmpDB(ctx, t)
+	defer cleaner()
+
+	scheme := platformpolicy.NewPlatformCryptographyScheme()
+	root := *jet.NewID(0, nil)
+	left, right, ok := jet.Children(root)
+	require.True(t, ok)
+	p1 := core.PulseNumber(core.FirstPulseNumber + 10)
+	p2 := core.PulseNumber(core.FirstPulseNumber + 20)
+	p3 := core.PulseNumber(core.FirstPulseNumber + 30)
+	for _, pn := range []core.PulseNumber{p1, p2, p3} {
+		require.NoError(t, db.AddPulse(ctx, core.Pulse{PulseNumber: pn}))
+	}
+
+	createDrop := func(jetID core.RecordID, pn core.PulseNumber, prevHash []byte) *jet.JetDrop {
+		drop, _, _, err := db.CreateDrop(ctx, jetID, pn, prevHash)
+		require.NoError(t, err)
+		require.NoError(t, db.SetDrop(ctx, jetID, drop))
+		return drop
+	}
+
+	// Jets are split in the first pulse and merged in the second.
+	require.NoError(t, db.UpdateJetTree(ctx, p1, true, left, right))
+	addRecords(ctx, t, db, left, p1)
+	addRecords(ctx, t, db, right, p1)
+	leftDrop := createDrop(left, p1, nil)
+	rightDrop := createDrop(right, p1, nil)
+
+	require.NoError(t, db.UpdateJetTree(ctx, p2, true, root))
+	addRecords(ctx, t, db, root, p2)
+	createDrop(root, p2, jet.MergedPrevHash(scheme, leftDrop.Hash, rightDrop.Hash))
+
+	verification, err := db.VerifyChains(ctx, storage.ChainVerifyOptions{From: p2})
+	require.NoError(t, err)
+	assert.Nil(t, verification.FirstMismatch())
+	assert.Equal(t, 1, verification.Drops)
+
+	// Jets are split again and merged drop is chained to the left branch only.
+	p4 := core.PulseNumber(core.FirstPulseNumber + 40)
+	require.NoError(t, db.AddPulse(ctx, core.Pulse{PulseNumber: p4}))
+	require.NoError(t, db.UpdateJetTree(ctx, p3, true, left, right))
+	leftDrop = createDrop(left, p3, nil)
+	createDrop(right, p3, nil)
+	require.NoError(t, db.UpdateJetTree(ctx, p4, true, root))
+	createDrop(root, p4, leftDrop.Hash)
+
+	verification, err = db.VerifyChains(ctx, storage.ChainVerifyOptions{From: p4})
+	require.NoError(t, err)
+	require.Len(t, verification.Mismatches, 1)
+	assert.Equal(t, storage.MismatchPrevHash, verification.Mismatches[0].Reason)
+	assert.Equal(t, p4, verification.Mismatches[0].Pulse)
+}
@@ -166,6 +166,26 @@ func (t *TestArtifactManager) GetChildren(ctx context.Context, parent core.Recor
 	panic("implement me")
 }
 
+// GetObjectHistory implementation for tests
+func (t *TestArtifactManager) GetObjectHistory(ctx context.Context, head core.RecordRef, pulse *core.PulseNumber, limit int) ([]core.ObjectHistoryState, error) {
+	panic("implement me")
+}
+
+// GetPrototypeObjects implementation for tests
+func (t *TestArtifactManager) GetPrototypeObjects(ctx context.Context, prototype core.RecordRef, from *core.RecordID, limit int) ([]core.RecordRef, *core.RecordID, error) {
+	panic("implement me")
+}
+
+// CountPrototypeObjects implementation for tests
+func (t *TestArtifactManager) CountPrototypeObjects(ctx context.Context, prototype core.RecordRef) (int, error) {
+	panic("implement me")
+}
+
+// GetRecordProof implementation for tests
+func (t *TestArtifactManager) GetRecordProof(ctx context.Context, id core.RecordID) (*core.RecordProof, error) {
+	panic("implement me")
+}
+
 // NewTestArtifactManager implementation for tests
 func NewTestArtifactManager() *TestArtifactManager {
 	return &TestArtifactManager{
@@ -1105,16 +1105,21 @@ func TestRootDomainContract(t *testing.T) {
 	if err != nil {
 		fmt.Print(err)
 	}
+	historyEntryCode, err := ioutil.ReadFile("../application/contract/historyentry/historyentry.go")
+	if err != nil {
+		fmt.Print(err)
+	}
 
 	ctx := context.TODO()
 	// TODO need use pulseManager to sync all refs
 	lr, am, cb, pm, cleaner := PrepareLrAmCbPm(t)
 	defer cleaner()
 	err = cb.Build(map[string]string{
-		"member":     string(memberCode),
-		"allowance":  string(allowanceCode),
-		"wallet":     string(walletCode),
-		"rootdomain": string(rootDomainCode),
+		"member":       string(memberCode),
+		"allowance":    string(allowanceCode),
+		"wallet":       string(walletCode),
+		"historyentry": string(historyEntryCode),
+		"rootdomain":   string(rootDomainCode),
 	})
 	assert.NoError(t, err)
 
@@ -1502,16 +1507,21 @@ func (r *One) CreateAllowance(member string) (error) {
 	if err != nil {
 		fmt.Print(err)
 	}
+	historyEntryCode, err := ioutil.ReadFile("../application/contract/historyentry/historyentry.go")
+	if err != nil {
+		fmt.Print(err)
+	}
 
 	ctx := context.TODO()
 	lr, am, cb, pm, cleaner := PrepareLrAmCbPm(t)
 	defer cleaner()
 	err = cb.Build(map[string]string{
-		"one":        contractOneCode,
-		"member":     string(memberCode),
-		"allowance":  string(allowanceCode),
-		"wallet":     string(walletCode),
-		"rootdomain": string(rootDomainCode),
+		"one":          contractOneCode,
+		"member":       string(memberCode),
+		"allowance":    string(allowanceCode),
+		"wallet":       string(walletCode),
+		"historyentry": string(historyEntryCode),
+		"rootdomain":   string(rootDomainCode),
 	})
 	assert.NoError(t, err)
 
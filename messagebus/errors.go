@@ -23,4 +23,6 @@ import (
 var (
 	// ErrNoReply is returned from player when there is no stored reply for provided message.
 	ErrNoReply = errors.New("no such reply")
+	// ErrRedirectLimit is returned from Send when reply is redirected more times than send policy allows.
+	ErrRedirectLimit = errors.New("redirect limit exceeded")
 )
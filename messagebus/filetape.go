@@ -0,0 +1,384 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package messagebus
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/ugorji/go/codec"
+
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/core/message"
+	"github.com/insolar/insolar/core/reply"
+)
+
+// FileTapeVersion is a current version of on-disk tape format. Version 2 adds sent parcels to records, tapes of
+// version 1 are still readable.
+const FileTapeVersion uint16 = 2
+
+// On-disk tape layout (all numbers are big endian):
+//
+//   header: magic [6]byte | version uint16 | pulse uint32 | crc32 of previous fields uint32
+//   record: payload length uint32 | crc32 of payload uint32 | payload (cbor encoded itemBlob)
+//
+// Records are only appended, so a tape interrupted in the middle of a write is still readable up to the last
+// complete record. Message hash index is not stored, it is rebuilt from records when tape is opened.
+var fileTapeMagic = []byte("INSTAP")
+
+const (
+	fileTapeHeaderSize       = 16
+	fileTapeRecordHeaderSize = 8
+	// fileTapeMaxRecordSize limits record payload, so a corrupted length doesn't make reader allocate gigabytes.
+	fileTapeMaxRecordSize = 64 * 1024 * 1024
+)
+
+// TapeRecord is a single message hash and reply/error pair stored on the tape. Parcel is the sent message, it is
+// nil for records added with Set and for tapes of version 1.
+type TapeRecord struct {
+	MsgHash []byte
+	Parcel  core.Parcel
+	Item    TapeItem
+}
+
+// FileTape is an append-only tape that is stored on disk. Replies are looked up by message hash index, so replay
+// doesn't depend on order of sent messages.
+type FileTape struct {
+	lock    sync.Mutex
+	version uint16
+	pulse   core.PulseNumber
+	file    *os.File
+	records []TapeRecord
+	// index maps message hash to positions of records that were not replayed yet.
+	index map[string][]int
+}
+
+// IsFileTape checks if provided data starts with on-disk tape header.
+func IsFileTape(data []byte) bool {
+	return bytes.HasPrefix(data, fileTapeMagic)
+}
+
+// CreateFileTape creates new tape file for provided pulse. File must not exist.
+func CreateFileTape(path string, pulse core.PulseNumber) (*FileTape, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ CreateFileTape ] can't create tape file")
+	}
+	_, err = f.Write(encodeFileTapeHeader(pulse))
+	if err != nil {
+		f.Close() // nolint: errcheck
+		return nil, errors.Wrap(err, "[ CreateFileTape ] can't write header")
+	}
+
+	t := newFileTape(pulse)
+	t.file = f
+	return t, nil
+}
+
+// OpenFileTape opens existing tape file. New records are appended to the file. Incomplete last record is dropped.
+func OpenFileTape(path string) (*FileTape, error) {
+	f, err := os.OpenFile(path, os.O_RDWR, 0644)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ OpenFileTape ] can't open tape file")
+	}
+
+	t, size, err := readFileTape(bufio.NewReader(f))
+	if err != nil {
+		f.Close() // nolint: errcheck
+		return nil, err
+	}
+	// Cut off incomplete record so new records are appended right after the last valid one.
+	if err = f.Truncate(size); err == nil {
+		_, err = f.Seek(size, io.SeekStart)
+	}
+	if err != nil {
+		f.Close() // nolint: errcheck
+		return nil, errors.Wrap(err, "[ OpenFileTape ] can't prepare tape for appending")
+	}
+
+	t.file = f
+	return t, nil
+}
+
+// ReadFileTape reads tape from provided reader. Returned tape is kept in memory only.
+func ReadFileTape(r io.Reader) (*FileTape, error) {
+	t, _, err := readFileTape(r)
+	return t, err
+}
+
+// NewFileTape creates empty tape for provided pulse that is kept in memory only. It can be saved with Write.
+func NewFileTape(pulse core.PulseNumber) *FileTape {
+	return newFileTape(pulse)
+}
+
+func newFileTape(pulse core.PulseNumber) *FileTape {
+	return &FileTape{
+		version: FileTapeVersion,
+		pulse:   pulse,
+		index:   map[string][]int{},
+	}
+}
+
+// readFileTape reads tape and returns it with the size of its valid part.
+func readFileTape(r io.Reader) (*FileTape, int64, error) {
+	header := make([]byte, fileTapeHeaderSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, 0, errors.Wrap(err, "[ FileTape ] can't read header")
+	}
+	if !IsFileTape(header) {
+		return nil, 0, errors.New("[ FileTape ] not a tape file")
+	}
+	version := binary.BigEndian.Uint16(header[6:8])
+	if version == 0 || version > FileTapeVersion {
+		return nil, 0, fmt.Errorf("[ FileTape ] unsupported tape version %d", version)
+	}
+	if crc32.ChecksumIEEE(header[:12]) != binary.BigEndian.Uint32(header[12:]) {
+		return nil, 0, errors.New("[ FileTape ] header checksum mismatch")
+	}
+
+	t := newFileTape(core.PulseNumber(binary.BigEndian.Uint32(header[8:12])))
+	t.version = version
+	size := int64(fileTapeHeaderSize)
+	recordHeader := make([]byte, fileTapeRecordHeaderSize)
+	for {
+		_, err := io.ReadFull(r, recordHeader)
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return t, size, nil
+		}
+		if err != nil {
+			return nil, 0, errors.Wrap(err, "[ FileTape ] can't read record")
+		}
+
+		length := binary.BigEndian.Uint32(recordHeader[:4])
+		if length > fileTapeMaxRecordSize {
+			return nil, 0, fmt.Errorf("[ FileTape ] record at offset %d is too large: %d bytes", size, length)
+		}
+		payload := make([]byte, length)
+		_, err = io.ReadFull(r, payload)
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return t, size, nil
+		}
+		if err != nil {
+			return nil, 0, errors.Wrap(err, "[ FileTape ] can't read record")
+		}
+		if crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(recordHeader[4:]) {
+			return nil, 0, fmt.Errorf("[ FileTape ] record checksum mismatch at offset %d", size)
+		}
+
+		record, err := decodeTapeRecord(payload)
+		if err != nil {
+			return nil, 0, errors.Wrapf(err, "[ FileTape ] can't decode record at offset %d", size)
+		}
+		t.add(record)
+		size += int64(len(recordHeader) + len(payload))
+	}
+}
+
+// Pulse returns pulse number the tape was recorded in.
+func (t *FileTape) Pulse() core.PulseNumber {
+	return t.pulse
+}
+
+// Version returns on-disk format version the tape was read with.
+func (t *FileTape) Version() uint16 {
+	return t.version
+}
+
+// Records returns all records of the tape in order they were written.
+func (t *FileTape) Records() []TapeRecord {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	records := make([]TapeRecord, len(t.records))
+	copy(records, t.records)
+	return records
+}
+
+// Close flushes tape file to disk and closes it.
+func (t *FileTape) Close() error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.file == nil {
+		return nil
+	}
+	err := t.file.Sync()
+	if err != nil {
+		return errors.Wrap(err, "[ FileTape ] can't sync tape file")
+	}
+	err = t.file.Close()
+	t.file = nil
+	return err
+}
+
+// Write writes the whole tape in on-disk format.
+func (t *FileTape) Write(ctx context.Context, w io.Writer) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	_, err := w.Write(encodeFileTapeHeader(t.pulse))
+	if err != nil {
+		return errors.Wrap(err, "[ FileTape ] can't write header")
+	}
+	for _, record := range t.records {
+		data, err := encodeTapeRecord(record)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		if err != nil {
+			return errors.Wrap(err, "[ FileTape ] can't write record")
+		}
+	}
+	return nil
+}
+
+// Get returns the first not replayed item for provided message hash.
+func (t *FileTape) Get(ctx context.Context, msgHash []byte) (*TapeItem, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	positions := t.index[string(msgHash)]
+	if len(positions) == 0 {
+		return nil, errors.New("Validation error. Message is not expected")
+	}
+	t.index[string(msgHash)] = positions[1:]
+
+	item := t.records[positions[0]].Item
+	return &item, nil
+}
+
+// Set appends reply/error pair for message hash to the tape. Record is written to file immediately.
+func (t *FileTape) Set(ctx context.Context, msgHash []byte, rep core.Reply, gotError error) error {
+	return t.SetParcel(ctx, msgHash, nil, rep, gotError)
+}
+
+// SetParcel appends reply/error pair for message hash to the tape along with sent parcel, so the message can be
+// sent again when tape is replayed against a node.
+func (t *FileTape) SetParcel(ctx context.Context, msgHash []byte, parcel core.Parcel, rep core.Reply, gotError error) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	record := TapeRecord{
+		MsgHash: msgHash,
+		Parcel:  parcel,
+		Item: TapeItem{
+			Reply: rep,
+			Error: gotError,
+		},
+	}
+	if t.file != nil {
+		data, err := encodeTapeRecord(record)
+		if err != nil {
+			return err
+		}
+		_, err = t.file.Write(data)
+		if err != nil {
+			return errors.Wrap(err, "[ FileTape ] can't write record")
+		}
+	}
+	t.add(record)
+	return nil
+}
+
+func (t *FileTape) add(record TapeRecord) {
+	key := string(record.MsgHash)
+	t.index[key] = append(t.index[key], len(t.records))
+	t.records = append(t.records, record)
+}
+
+func encodeFileTapeHeader(pulse core.PulseNumber) []byte {
+	header := make([]byte, fileTapeHeaderSize)
+	copy(header, fileTapeMagic)
+	binary.BigEndian.PutUint16(header[6:8], FileTapeVersion)
+	binary.BigEndian.PutUint32(header[8:12], uint32(pulse))
+	binary.BigEndian.PutUint32(header[12:], crc32.ChecksumIEEE(header[:12]))
+	return header
+}
+
+func encodeTapeRecord(record TapeRecord) ([]byte, error) {
+	blob := itemBlob{
+		MsgHash: record.MsgHash,
+	}
+	if record.Item.Reply != nil {
+		blob.ReplyB = reply.ToBytes(record.Item.Reply)
+	}
+	if record.Item.Error != nil {
+		blob.ErrorB = []byte(record.Item.Error.Error())
+	}
+	if record.Parcel != nil {
+		buf, err := message.SerializeParcel(record.Parcel)
+		if err != nil {
+			return nil, errors.Wrap(err, "[ FileTape ] can't encode parcel")
+		}
+		blob.ParcelB, err = ioutil.ReadAll(buf)
+		if err != nil {
+			return nil, errors.Wrap(err, "[ FileTape ] can't encode parcel")
+		}
+	}
+
+	var payload []byte
+	err := codec.NewEncoderBytes(&payload, new(codec.CborHandle)).Encode(blob)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ FileTape ] can't encode record")
+	}
+	if len(payload) > fileTapeMaxRecordSize {
+		return nil, fmt.Errorf("[ FileTape ] record is too large: %d bytes", len(payload))
+	}
+
+	data := make([]byte, fileTapeRecordHeaderSize, fileTapeRecordHeaderSize+len(payload))
+	binary.BigEndian.PutUint32(data[:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(data[4:], crc32.ChecksumIEEE(payload))
+	return append(data, payload...), nil
+}
+
+func decodeTapeRecord(payload []byte) (TapeRecord, error) {
+	var blob itemBlob
+	err := codec.NewDecoderBytes(payload, new(codec.CborHandle)).Decode(&blob)
+	if err != nil {
+		return TapeRecord{}, err
+	}
+
+	record := TapeRecord{MsgHash: blob.MsgHash}
+	if blob.ReplyB != nil {
+		rep, err := reply.Deserialize(bytes.NewReader(blob.ReplyB))
+		if err != nil {
+			return TapeRecord{}, err
+		}
+		record.Item.Reply = rep
+	}
+	if blob.ErrorB != nil {
+		record.Item.Error = errors.New(string(blob.ErrorB))
+	}
+	if blob.ParcelB != nil {
+		parcel, err := message.DeserializeParcel(bytes.NewReader(blob.ParcelB))
+		if err != nil {
+			return TapeRecord{}, err
+		}
+		record.Parcel = parcel
+	}
+	return record, nil
+}
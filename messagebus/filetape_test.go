@@ -0,0 +1,198 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package messagebus
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/core/message"
+	"github.com/insolar/insolar/core/reply"
+	"github.com/insolar/insolar/instrumentation/inslogger"
+)
+
+func tempTapePath(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "tape")
+	require.NoError(t, err)
+	return filepath.Join(dir, "test.tape"), func() { os.RemoveAll(dir) }
+}
+
+func TestFileTape_Reopen(t *testing.T) {
+	ctx := inslogger.TestContext(t)
+	path, cleaner := tempTapePath(t)
+	defer cleaner()
+
+	pn := core.PulseNumber(core.FirstPulseNumber + 1000)
+	tp, err := CreateFileTape(path, pn)
+	require.NoError(t, err)
+	err = tp.Set(ctx, []byte{4, 5, 6}, &reply.Object{Memory: []byte{9, 9, 9}}, nil)
+	require.NoError(t, err)
+	err = tp.Set(ctx, []byte{4, 5, 7}, nil, errors.New("send failed"))
+	require.NoError(t, err)
+	require.NoError(t, tp.Close())
+
+	// Append to existing tape.
+	tp, err = OpenFileTape(path)
+	require.NoError(t, err)
+	err = tp.Set(ctx, []byte{4, 5, 6}, &reply.OK{}, nil)
+	require.NoError(t, err)
+	require.NoError(t, tp.Close())
+
+	tp, err = OpenFileTape(path)
+	require.NoError(t, err)
+	defer tp.Close()
+	assert.Equal(t, pn, tp.Pulse())
+	require.Equal(t, 3, len(tp.Records()))
+
+	// Replies for the same message are returned in order they were recorded.
+	item, err := tp.Get(ctx, []byte{4, 5, 6})
+	require.NoError(t, err)
+	assert.Equal(t, &reply.Object{Memory: []byte{9, 9, 9}}, item.Reply)
+	item, err = tp.Get(ctx, []byte{4, 5, 7})
+	require.NoError(t, err)
+	assert.EqualError(t, item.Error, "send failed")
+	item, err = tp.Get(ctx, []byte{4, 5, 6})
+	require.NoError(t, err)
+	assert.Equal(t, &reply.OK{}, item.Reply)
+
+	_, err = tp.Get(ctx, []byte{4, 5, 6})
+	require.Error(t, err)
+}
+
+func TestFileTape_Write(t *testing.T) {
+	ctx := inslogger.TestContext(t)
+	tp := newFileTape(core.PulseNumber(42))
+	err := tp.Set(ctx, []byte{1}, &reply.OK{}, nil)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	err = tp.Write(ctx, &buf)
+	require.NoError(t, err)
+	require.True(t, IsFileTape(buf.Bytes()))
+
+	rTape, err := ReadFileTape(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, core.PulseNumber(42), rTape.Pulse())
+	assert.Equal(t, tp.Records(), rTape.Records())
+}
+
+func TestFileTape_Corrupted(t *testing.T) {
+	ctx := inslogger.TestContext(t)
+	tp := newFileTape(core.PulseNumber(42))
+	err := tp.Set(ctx, []byte{1}, &reply.OK{}, nil)
+	require.NoError(t, err)
+	err = tp.Set(ctx, []byte{2}, &reply.OK{}, nil)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	err = tp.Write(ctx, &buf)
+	require.NoError(t, err)
+	data := buf.Bytes()
+
+	// Incomplete last record is ignored.
+	rTape, err := ReadFileTape(bytes.NewReader(data[:len(data)-1]))
+	require.NoError(t, err)
+	assert.Equal(t, 1, len(rTape.Records()))
+
+	// Damaged record fails checksum.
+	damaged := append([]byte{}, data...)
+	damaged[len(damaged)-1] ^= 0xff
+	_, err = ReadFileTape(bytes.NewReader(damaged))
+	require.Error(t, err)
+
+	// Damaged header.
+	damaged = append([]byte{}, data...)
+	damaged[8] ^= 0xff
+	_, err = ReadFileTape(bytes.NewReader(damaged))
+	require.EqualError(t, err, "[ FileTape ] header checksum mismatch")
+}
+
+func TestFileTape_Parcel(t *testing.T) {
+	ctx := inslogger.TestContext(t)
+	path, cleaner := tempTapePath(t)
+	defer cleaner()
+
+	parcel := &message.Parcel{
+		Msg:         &message.GenesisRequest{Name: "test"},
+		Signature:   []byte{1, 2, 3},
+		PulseNumber: core.FirstPulseNumber,
+	}
+	tp, err := CreateFileTape(path, core.FirstPulseNumber)
+	require.NoError(t, err)
+	err = tp.SetParcel(ctx, []byte{1}, parcel, &reply.OK{}, nil)
+	require.NoError(t, err)
+	err = tp.Set(ctx, []byte{2}, &reply.OK{}, nil)
+	require.NoError(t, err)
+	require.NoError(t, tp.Close())
+
+	// Index is rebuilt on open, so replies are found by hash.
+	tp, err = OpenFileTape(path)
+	require.NoError(t, err)
+	defer tp.Close()
+	assert.Equal(t, FileTapeVersion, tp.Version())
+	records := tp.Records()
+	require.Equal(t, 2, len(records))
+	assert.Equal(t, parcel, records[0].Parcel)
+	assert.Nil(t, records[1].Parcel)
+	item, err := tp.Get(ctx, []byte{2})
+	require.NoError(t, err)
+	assert.Equal(t, &reply.OK{}, item.Reply)
+}
+
+func TestFileTape_Version1(t *testing.T) {
+	ctx := inslogger.TestContext(t)
+	tp := newFileTape(core.PulseNumber(42))
+	err := tp.Set(ctx, []byte{1}, &reply.OK{}, nil)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	err = tp.Write(ctx, &buf)
+	require.NoError(t, err)
+	data := buf.Bytes()
+	binary.BigEndian.PutUint16(data[6:8], 1)
+	binary.BigEndian.PutUint32(data[12:16], crc32.ChecksumIEEE(data[:12]))
+
+	rTape, err := ReadFileTape(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, uint16(1), rTape.Version())
+	assert.Equal(t, tp.Records(), rTape.Records())
+
+	binary.BigEndian.PutUint16(data[6:8], FileTapeVersion+1)
+	binary.BigEndian.PutUint32(data[12:16], crc32.ChecksumIEEE(data[:12]))
+	_, err = ReadFileTape(bytes.NewReader(data))
+	require.Error(t, err)
+}
+
+func TestFileTape_RecordTooLarge(t *testing.T) {
+	header := make([]byte, fileTapeRecordHeaderSize)
+	binary.BigEndian.PutUint32(header[:4], fileTapeMaxRecordSize+1)
+	data := append(encodeFileTapeHeader(core.PulseNumber(42)), header...)
+
+	_, err := ReadFileTape(bytes.NewReader(data))
+	require.EqualError(t, err, fmt.Sprintf("[ FileTape ] record at offset 16 is too large: %d bytes", fileTapeMaxRecordSize+1))
+}
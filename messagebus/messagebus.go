@@ -17,6 +17,7 @@
 package messagebus
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/gob"
@@ -39,7 +40,10 @@ import (
 	"github.com/pkg/errors"
 )
 
-const deliverRPCMethodName = "MessageBus.Deliver"
+const (
+	deliverRPCMethodName = "MessageBus.Deliver"
+	publishRPCMethodName = "MessageBus.Notify"
+)
 
 // MessageBus is component that routes application logic requests,
 // e.g. glue between network and logic runner
@@ -57,6 +61,9 @@ type MessageBus struct {
 	handlers     map[core.MessageType]core.MessageHandler
 	signmessages bool
 
+	subscribersLock sync.RWMutex
+	subscribers     map[core.MessageType][]core.MessageHandler
+
 	globalLock                  sync.RWMutex
 	NextPulseMessagePoolChan    chan interface{}
 	NextPulseMessagePoolCounter uint32
@@ -68,6 +75,7 @@ type MessageBus struct {
 func NewMessageBus(config configuration.Configuration) (*MessageBus, error) {
 	mb := &MessageBus{
 		handlers:                 map[core.MessageType]core.MessageHandler{},
+		subscribers:              map[core.MessageType][]core.MessageHandler{},
 		signmessages:             config.Host.SignMessages,
 		NextPulseMessagePoolChan: make(chan interface{}),
 	}
@@ -79,12 +87,25 @@ func NewMessageBus(config configuration.Configuration) (*MessageBus, error) {
 // stream is exhausted.
 //
 // Player can be created from MessageBus and passed as MessageBus instance.
+//
+// Both in-memory tape blobs and on-disk tapes are accepted.
 func (mb *MessageBus) NewPlayer(ctx context.Context, reader io.Reader) (core.MessageBus, error) {
-	tape, err := newMemoryTapeFromReader(ctx, reader)
+	buffered := bufio.NewReader(reader)
+	header, err := buffered.Peek(len(fileTapeMagic))
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "[ NewPlayer ] can't read tape")
+	}
+
+	var t tape
+	if IsFileTape(header) {
+		t, err = ReadFileTape(buffered)
+	} else {
+		t, err = newMemoryTapeFromReader(ctx, buffered)
+	}
 	if err != nil {
 		return nil, err
 	}
-	pl := newPlayer(mb, tape, mb.PlatformCryptographyScheme, mb.PulseStorage)
+	pl := newPlayer(mb, t, mb.PlatformCryptographyScheme, mb.PulseStorage)
 	return pl, nil
 }
 
@@ -97,9 +118,22 @@ func (mb *MessageBus) NewRecorder(ctx context.Context, currentPulse core.Pulse)
 	return rec, nil
 }
 
+// NewFileRecorder creates a new recorder that appends message replies to on-disk tape at provided path. Tape file
+// is closed when recorder is closed.
+func (mb *MessageBus) NewFileRecorder(ctx context.Context, currentPulse core.Pulse, path string) (*FileRecorder, error) {
+	tape, err := CreateFileTape(path, currentPulse.PulseNumber)
+	if err != nil {
+		return nil, err
+	}
+	rec := newRecorder(mb, tape, mb.PlatformCryptographyScheme, mb.PulseStorage)
+	return &FileRecorder{recorder: rec, tape: tape}, nil
+}
+
 // Start initializes message bus.
 func (mb *MessageBus) Start(ctx context.Context) error {
 	mb.Network.RemoteProcedureRegister(deliverRPCMethodName, mb.deliver)
+	mb.Network.RemoteStreamProcedureRegister(deliverRPCMethodName, mb.deliverStream)
+	mb.Network.RemoteProcedureRegister(publishRPCMethodName, mb.notify)
 
 	return nil
 }
@@ -137,8 +171,17 @@ func (mb *MessageBus) MustRegister(p core.MessageType, handler core.MessageHandl
 	}
 }
 
-// Send an `Message` and get a `Value` or error from remote host.
+// Send an `Message` and get a `Value` or error from remote host. If options contain send policy, message is
+// retried and redirected according to it.
 func (mb *MessageBus) Send(ctx context.Context, msg core.Message, ops *core.MessageSendOptions) (core.Reply, error) {
+	if ops.Safe().Policy != nil {
+		return sendWithPolicy(ctx, mb.PulseStorage, mb.send, msg, ops)
+	}
+	return mb.send(ctx, msg, ops)
+}
+
+// send makes single attempt to send message in current pulse.
+func (mb *MessageBus) send(ctx context.Context, msg core.Message, ops *core.MessageSendOptions) (core.Reply, error) {
 	ctx, span := instracer.StartSpan(ctx, "MessageBus.Send "+msg.Type().String())
 	defer span.End()
 
@@ -175,23 +218,9 @@ func (mb *MessageBus) SendParcel(
 
 	readBarrier(ctx, &mb.globalLock)
 
-	var (
-		nodes []core.RecordRef
-		err   error
-	)
-	if options != nil && options.Receiver != nil {
-		nodes = []core.RecordRef{*options.Receiver}
-	} else {
-		// TODO: send to all actors of the role if nil Target
-		target := parcel.DefaultTarget()
-		// FIXME: @andreyromancev. 21.12.18. Temp hack. All messages should have a default target.
-		if target == nil {
-			target = &core.RecordRef{}
-		}
-		nodes, err = mb.JetCoordinator.QueryRole(ctx, parcel.DefaultRole(), *target.Record(), currentPulse.PulseNumber)
-		if err != nil {
-			return nil, err
-		}
+	nodes, err := mb.getReceivers(ctx, parcel, currentPulse, options)
+	if err != nil {
+		return nil, err
 	}
 
 	start := time.Now()
@@ -226,6 +255,25 @@ func (mb *MessageBus) SendParcel(
 	return reply.Deserialize(bytes.NewBuffer(res))
 }
 
+func (mb *MessageBus) getReceivers(
+	ctx context.Context,
+	parcel core.Parcel,
+	currentPulse core.Pulse,
+	options *core.MessageSendOptions,
+) ([]core.RecordRef, error) {
+	if options != nil && options.Receiver != nil {
+		return []core.RecordRef{*options.Receiver}, nil
+	}
+
+	// TODO: send to all actors of the role if nil Target
+	target := parcel.DefaultTarget()
+	// FIXME: @andreyromancev. 21.12.18. Temp hack. All messages should have a default target.
+	if target == nil {
+		target = &core.RecordRef{}
+	}
+	return mb.JetCoordinator.QueryRole(ctx, parcel.DefaultRole(), *target.Record(), currentPulse.PulseNumber)
+}
+
 type serializableError struct {
 	S string
 }
@@ -369,6 +417,32 @@ func (mb *MessageBus) checkPulse(ctx context.Context, parcel core.Parcel, locked
 // this method is registered as RPC stub
 func (mb *MessageBus) deliver(ctx context.Context, args [][]byte) (result []byte, err error) {
 	inslogger.FromContext(ctx).Debug("MessageBus.deliver starts ...")
+	return mb.receive(ctx, args, mb.doDeliver)
+}
+
+// deliverStream is deliver for large parcels received over stream, parcel is decoded while it is read
+// this method is registered as RPC stub
+func (mb *MessageBus) deliverStream(ctx context.Context, arg io.Reader) (result []byte, err error) {
+	inslogger.FromContext(ctx).Debug("MessageBus.deliverStream starts ...")
+	parcel, err := message.DeserializeParcel(arg)
+	if err != nil {
+		return nil, err
+	}
+	return mb.receiveParcel(ctx, parcel, mb.doDeliver)
+}
+
+// notify passes published message to local subscribers
+// this method is registered as RPC stub
+func (mb *MessageBus) notify(ctx context.Context, args [][]byte) (result []byte, err error) {
+	inslogger.FromContext(ctx).Debug("MessageBus.notify starts ...")
+	return mb.receive(ctx, args, mb.doPublish)
+}
+
+func (mb *MessageBus) receive(
+	ctx context.Context,
+	args [][]byte,
+	process func(context.Context, core.Parcel) (core.Reply, error),
+) ([]byte, error) {
 	if len(args) < 1 {
 		return nil, errors.New("need exactly one argument when mb.deliver()")
 	}
@@ -376,9 +450,17 @@ func (mb *MessageBus) deliver(ctx context.Context, args [][]byte) (result []byte
 	if err != nil {
 		return nil, err
 	}
+	return mb.receiveParcel(ctx, parcel, process)
+}
 
+func (mb *MessageBus) receiveParcel(
+	ctx context.Context,
+	parcel core.Parcel,
+	process func(context.Context, core.Parcel) (core.Reply, error),
+) ([]byte, error) {
+	var err error
 	parcelCtx := parcel.Context(context.Background()) // use ctx when network provide context
-	inslogger.FromContext(ctx).Debugf("MessageBus.deliver after deserialize msg. Msg Type: %s", parcel.Type())
+	inslogger.FromContext(ctx).Debugf("MessageBus.receive after deserialize msg. Msg Type: %s", parcel.Type())
 
 	mb.globalLock.RLock()
 
@@ -393,7 +475,7 @@ func (mb *MessageBus) deliver(ctx context.Context, args [][]byte) (result []byte
 	}
 	mb.globalLock.RUnlock()
 
-	resp, err := mb.doDeliver(parcelCtx, parcel)
+	resp, err := process(parcelCtx, parcel)
 	if err != nil {
 		return nil, err
 	}
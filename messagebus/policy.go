@@ -0,0 +1,178 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package messagebus
+
+import (
+	"context"
+	"time"
+
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/instrumentation/inslogger"
+	"github.com/insolar/insolar/metrics"
+	"github.com/insolar/insolar/utils/backoff"
+	"github.com/pkg/errors"
+)
+
+// Send policy outcomes used as metric labels.
+const (
+	outcomeSuccess       = "success"
+	outcomeError         = "error"
+	outcomeExhausted     = "exhausted"
+	outcomeDeadline      = "deadline"
+	outcomeRedirectLimit = "redirect_limit"
+
+	retryReasonError = "error"
+	retryReasonPulse = "pulse"
+)
+
+type sendFunc func(context.Context, core.Message, *core.MessageSendOptions) (core.Reply, error)
+
+type sendResult struct {
+	reply core.Reply
+	err   error
+}
+
+// sendWithPolicy sends message with provided function, retrying it and following redirects as options policy says.
+func sendWithPolicy(
+	ctx context.Context,
+	pulseStorage core.PulseStorage,
+	send sendFunc,
+	msg core.Message,
+	ops *core.MessageSendOptions,
+) (core.Reply, error) {
+	if deadline := ops.Policy.DeadlineFor(msg.Type()); deadline > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, deadline)
+		defer cancel()
+	}
+
+	rep, outcome, err := followPolicy(ctx, pulseStorage, send, msg, ops)
+	metrics.SendPolicyOutcomesTotal.WithLabelValues(msg.Type().String(), outcome).Inc()
+	return rep, err
+}
+
+func followPolicy(
+	ctx context.Context,
+	pulseStorage core.PulseStorage,
+	send sendFunc,
+	msg core.Message,
+	ops *core.MessageSendOptions,
+) (core.Reply, string, error) {
+	policy := ops.Policy
+	maxRedirects := policy.MaxRedirects
+	if maxRedirects <= 0 {
+		maxRedirects = 1
+	}
+	retryBackoff := &backoff.Backoff{
+		Min:    policy.BackoffMin,
+		Max:    policy.BackoffMax,
+		Factor: policy.BackoffFactor,
+	}
+
+	// Policy is applied here, so it is not passed further.
+	attemptOps := &core.MessageSendOptions{Receiver: ops.Receiver, Token: ops.Token}
+	retries, redirects := 0, 0
+	for {
+		pulse, err := pulseStorage.Current(ctx)
+		if err != nil {
+			return nil, outcomeError, errors.Wrap(err, "[ sendWithPolicy ] couldn't get current pulse")
+		}
+
+		rep, err := sendWithContext(ctx, send, msg, attemptOps)
+		if err == nil {
+			redirect, ok := rep.(core.RedirectReply)
+			if !ok || !policy.FollowRedirects {
+				return rep, outcomeSuccess, nil
+			}
+			if redirects >= maxRedirects {
+				return nil, outcomeRedirectLimit, ErrRedirectLimit
+			}
+			redirects++
+			msg = redirect.Redirected(msg)
+			attemptOps = &core.MessageSendOptions{Receiver: redirect.GetReceiver(), Token: redirect.GetToken()}
+			continue
+		}
+		if ctx.Err() != nil {
+			return nil, outcomeDeadline, errors.Wrap(err, "[ sendWithPolicy ] send deadline exceeded")
+		}
+
+		reason := retryReasonError
+		changed, pulseErr := pulseChanged(ctx, pulseStorage, pulse)
+		if pulseErr != nil {
+			return nil, outcomeError, errors.Wrap(pulseErr, "[ sendWithPolicy ] couldn't get current pulse")
+		}
+		if changed {
+			if !policy.RetryOnPulseChange {
+				return nil, outcomeError, err
+			}
+			reason = retryReasonPulse
+		}
+
+		if retries >= policy.Retries {
+			return nil, outcomeExhausted, errors.Wrapf(err, "[ sendWithPolicy ] failed after %d retries", retries)
+		}
+		retries++
+		// New pulse has already come, so there is no need to wait before resend.
+		delay := time.Duration(0)
+		if reason != retryReasonPulse {
+			delay = retryBackoff.Duration()
+		}
+		metrics.SendPolicyRetriesTotal.WithLabelValues(msg.Type().String(), reason).Inc()
+		inslogger.FromContext(ctx).Debugf(
+			"[ sendWithPolicy ] resending %s in %s (reason: %s): %s", msg.Type(), delay, reason, err,
+		)
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, outcomeDeadline, errors.Wrap(ctx.Err(), "[ sendWithPolicy ] send deadline exceeded")
+		}
+	}
+}
+
+// sendWithContext stops waiting for reply when context is done. Send gets the same context, but network layer
+// doesn't take it and waits for reply until its own RPC timeout, so send is made in separate goroutine. Result
+// channel is buffered, so the goroutine exits as soon as send returns even if nobody waits for it anymore.
+func sendWithContext(
+	ctx context.Context, send sendFunc, msg core.Message, ops *core.MessageSendOptions,
+) (core.Reply, error) {
+	if ctx.Done() == nil {
+		// Context can't be canceled, there is nothing to wait for besides reply.
+		return send(ctx, msg, ops)
+	}
+
+	done := make(chan sendResult, 1)
+	go func() {
+		rep, err := send(ctx, msg, ops)
+		done <- sendResult{reply: rep, err: err}
+	}()
+
+	select {
+	case res := <-done:
+		return res.reply, res.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func pulseChanged(ctx context.Context, pulseStorage core.PulseStorage, sent *core.Pulse) (bool, error) {
+	current, err := pulseStorage.Current(ctx)
+	if err != nil {
+		return false, err
+	}
+	return current.PulseNumber != sent.PulseNumber, nil
+}
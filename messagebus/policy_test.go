@@ -0,0 +1,210 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package messagebus
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/core/delegationtoken"
+	"github.com/insolar/insolar/core/message"
+	"github.com/insolar/insolar/core/reply"
+	"github.com/insolar/insolar/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func preparePulseStorage(t *testing.T, pn *uint32) *testutils.PulseStorageMock {
+	ps := testutils.NewPulseStorageMock(t)
+	ps.CurrentFunc = func(ctx context.Context) (*core.Pulse, error) {
+		return &core.Pulse{PulseNumber: core.PulseNumber(atomic.LoadUint32(pn))}, nil
+	}
+	return ps
+}
+
+func TestSendWithPolicy_RetriesOnError(t *testing.T) {
+	ctx := context.Background()
+	pn := uint32(100)
+	ps := preparePulseStorage(t, &pn)
+
+	var calls int32
+	send := func(ctx context.Context, msg core.Message, ops *core.MessageSendOptions) (core.Reply, error) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			return nil, errors.New("network error")
+		}
+		return testReply, nil
+	}
+	ops := &core.MessageSendOptions{Policy: &core.SendPolicy{Retries: 2, BackoffMin: time.Millisecond}}
+
+	rep, err := sendWithPolicy(ctx, ps, send, &message.GenesisRequest{}, ops)
+	require.NoError(t, err)
+	assert.Equal(t, testReply, rep)
+	assert.Equal(t, int32(3), calls)
+}
+
+func TestSendWithPolicy_RetriesExhausted(t *testing.T) {
+	ctx := context.Background()
+	pn := uint32(100)
+	ps := preparePulseStorage(t, &pn)
+
+	var calls int32
+	send := func(ctx context.Context, msg core.Message, ops *core.MessageSendOptions) (core.Reply, error) {
+		atomic.AddInt32(&calls, 1)
+		return nil, errors.New("network error")
+	}
+	ops := &core.MessageSendOptions{Policy: &core.SendPolicy{Retries: 2, BackoffMin: time.Millisecond}}
+
+	_, err := sendWithPolicy(ctx, ps, send, &message.GenesisRequest{}, ops)
+	require.EqualError(t, err, "[ sendWithPolicy ] failed after 2 retries: network error")
+	assert.Equal(t, int32(3), calls)
+}
+
+func TestSendWithPolicy_PulseChange(t *testing.T) {
+	ctx := context.Background()
+	pn := uint32(100)
+	ps := preparePulseStorage(t, &pn)
+
+	var calls int32
+	send := func(ctx context.Context, msg core.Message, ops *core.MessageSendOptions) (core.Reply, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			atomic.AddUint32(&pn, 1)
+			return nil, errors.New("incorrect message pulse")
+		}
+		return testReply, nil
+	}
+
+	ops := &core.MessageSendOptions{Policy: &core.SendPolicy{Retries: 1, BackoffMin: time.Hour, BackoffMax: time.Hour}}
+	_, err := sendWithPolicy(ctx, ps, send, &message.GenesisRequest{}, ops)
+	require.EqualError(t, err, "incorrect message pulse")
+	assert.Equal(t, int32(1), calls)
+
+	// Resend in new pulse happens without backoff delay.
+	calls = 0
+	ops.Policy.RetryOnPulseChange = true
+	rep, err := sendWithPolicy(ctx, ps, send, &message.GenesisRequest{}, ops)
+	require.NoError(t, err)
+	assert.Equal(t, testReply, rep)
+	assert.Equal(t, int32(2), calls)
+}
+
+func TestSendWithPolicy_FollowsRedirect(t *testing.T) {
+	ctx := context.Background()
+	pn := uint32(100)
+	ps := preparePulseStorage(t, &pn)
+
+	head := testutils.RandomRef()
+	receiver := testutils.RandomRef()
+	state := testutils.RandomID()
+	token := &delegationtoken.GetObjectRedirectToken{Signature: []byte{1, 2, 3}}
+
+	var calls int32
+	send := func(ctx context.Context, msg core.Message, ops *core.MessageSendOptions) (core.Reply, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			assert.Nil(t, ops.Receiver)
+			return &reply.GetObjectRedirectReply{Receiver: &receiver, Token: token, StateID: &state}, nil
+		}
+		assert.Equal(t, &receiver, ops.Receiver)
+		assert.Equal(t, token, ops.Token)
+		assert.Nil(t, ops.Policy)
+		assert.Equal(t, &message.GetObject{Head: head, State: &state}, msg)
+		return testReply, nil
+	}
+
+	ops := &core.MessageSendOptions{Policy: &core.SendPolicy{FollowRedirects: true}}
+	rep, err := sendWithPolicy(ctx, ps, send, &message.GetObject{Head: head}, ops)
+	require.NoError(t, err)
+	assert.Equal(t, testReply, rep)
+	assert.Equal(t, int32(2), calls)
+}
+
+func TestSendWithPolicy_RedirectLimit(t *testing.T) {
+	ctx := context.Background()
+	pn := uint32(100)
+	ps := preparePulseStorage(t, &pn)
+
+	receiver := testutils.RandomRef()
+	send := func(ctx context.Context, msg core.Message, ops *core.MessageSendOptions) (core.Reply, error) {
+		return &reply.GetObjectRedirectReply{Receiver: &receiver}, nil
+	}
+
+	ops := &core.MessageSendOptions{Policy: &core.SendPolicy{FollowRedirects: true}}
+	_, err := sendWithPolicy(ctx, ps, send, &message.GetObject{}, ops)
+	require.Equal(t, ErrRedirectLimit, err)
+
+	// Redirect reply is returned as is when policy doesn't follow redirects.
+	ops.Policy.FollowRedirects = false
+	rep, err := sendWithPolicy(ctx, ps, send, &message.GetObject{}, ops)
+	require.NoError(t, err)
+	assert.IsType(t, &reply.GetObjectRedirectReply{}, rep)
+}
+
+func TestSendWithPolicy_Deadline(t *testing.T) {
+	ctx := context.Background()
+	pn := uint32(100)
+	ps := preparePulseStorage(t, &pn)
+
+	send := func(ctx context.Context, msg core.Message, ops *core.MessageSendOptions) (core.Reply, error) {
+		time.Sleep(time.Second)
+		return testReply, nil
+	}
+
+	ops := &core.MessageSendOptions{Policy: &core.SendPolicy{
+		Retries:   10,
+		Deadline:  time.Hour,
+		Deadlines: map[core.MessageType]time.Duration{core.TypeBootstrapRequest: 10 * time.Millisecond},
+	}}
+	_, err := sendWithPolicy(ctx, ps, send, &message.GenesisRequest{}, ops)
+	require.EqualError(t, err, "[ sendWithPolicy ] send deadline exceeded: context deadline exceeded")
+}
+
+func TestSendWithContext_SenderExits(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	release := make(chan struct{})
+	send := func(ctx context.Context, msg core.Message, ops *core.MessageSendOptions) (core.Reply, error) {
+		<-release
+		return testReply, nil
+	}
+
+	before := runtime.NumGoroutine()
+	cancel()
+	_, err := sendWithContext(ctx, send, &message.GenesisRequest{}, nil)
+	require.Equal(t, context.Canceled, err)
+
+	// Nobody reads the result, but sender goroutine must not block on it.
+	close(release)
+	for deadline := time.Now().Add(time.Second); runtime.NumGoroutine() > before; {
+		if time.Now().After(deadline) {
+			t.Fatal("sender goroutine is blocked")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestSendWithContext_NotCancelable(t *testing.T) {
+	send := func(ctx context.Context, msg core.Message, ops *core.MessageSendOptions) (core.Reply, error) {
+		return testReply, nil
+	}
+
+	rep, err := sendWithContext(context.Background(), send, &message.GenesisRequest{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, testReply, rep)
+}
@@ -0,0 +1,171 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package messagebus
+
+import (
+	"bytes"
+	"context"
+	"sync"
+
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/core/reply"
+	"github.com/insolar/insolar/instrumentation/hack"
+	"github.com/insolar/insolar/instrumentation/inslogger"
+	"github.com/insolar/insolar/instrumentation/instracer"
+	"github.com/insolar/insolar/metrics"
+	"github.com/pkg/errors"
+)
+
+// Subscribe adds a handler for published messages of provided type. Unlike Register, any number of subscribers
+// can be added for the same type. Handler registered with Register is treated as the first subscriber.
+func (mb *MessageBus) Subscribe(p core.MessageType, handler core.MessageHandler) error {
+	if handler == nil {
+		return errors.New("subscriber can't be nil")
+	}
+
+	mb.subscribersLock.Lock()
+	defer mb.subscribersLock.Unlock()
+
+	mb.subscribers[p] = append(mb.subscribers[p], handler)
+	return nil
+}
+
+// Publish sends a `Message` to every node responsible for its topic without waiting for replies. Topic is
+// defined by message type and its default target. Returned channel receives a delivery report for every node
+// and is closed when all nodes answered. It's safe to ignore the channel.
+func (mb *MessageBus) Publish(
+	ctx context.Context, msg core.Message, ops *core.MessageSendOptions,
+) (<-chan core.Delivery, error) {
+	currentPulse, err := mb.PulseStorage.Current(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	parcel, err := mb.CreateParcel(ctx, msg, ops.Safe().Token, *currentPulse)
+	if err != nil {
+		return nil, err
+	}
+
+	return mb.PublishParcel(ctx, parcel, *currentPulse, ops)
+}
+
+// PublishParcel sends provided message to every node of its topic.
+func (mb *MessageBus) PublishParcel(
+	ctx context.Context,
+	parcel core.Parcel,
+	currentPulse core.Pulse,
+	options *core.MessageSendOptions,
+) (<-chan core.Delivery, error) {
+	parcelType := parcel.Type().String()
+	ctx, span := instracer.StartSpan(ctx, "MessageBus.PublishParcel "+parcelType)
+	defer span.End()
+
+	readBarrier(ctx, &mb.globalLock)
+
+	nodes, err := mb.getReceivers(ctx, parcel, currentPulse, options)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics.ParcelsPublishedTotal.WithLabelValues(parcelType).Inc()
+
+	// Buffered for every node, so nobody is blocked if caller doesn't read deliveries.
+	deliveries := make(chan core.Delivery, len(nodes))
+	var wg sync.WaitGroup
+	wg.Add(len(nodes))
+	for _, node := range nodes {
+		go func(receiver core.RecordRef) {
+			defer wg.Done()
+			rep, err := mb.publishTo(ctx, parcel, receiver)
+			if err == nil {
+				if ack, ok := rep.(*reply.Ack); ok {
+					err = ack.Err()
+				}
+			}
+			if err != nil {
+				metrics.PublishedParcelsFailedTotal.WithLabelValues(parcelType).Inc()
+				inslogger.FromContext(ctx).Debugf(
+					"[ PublishParcel ] failed to deliver %s to %s: %s", parcelType, receiver, err,
+				)
+			}
+			deliveries <- core.Delivery{Receiver: receiver, Reply: rep, Err: err}
+		}(node)
+	}
+
+	go func() {
+		wg.Wait()
+		close(deliveries)
+	}()
+
+	return deliveries, nil
+}
+
+func (mb *MessageBus) publishTo(ctx context.Context, parcel core.Parcel, receiver core.RecordRef) (core.Reply, error) {
+	// Short path when publishing to self node. Skip serialization
+	if receiver.Equal(mb.NodeNetwork.GetOrigin().ID()) {
+		metrics.LocallyDeliveredParcelsTotal.WithLabelValues(parcel.Type().String()).Inc()
+		return mb.doPublish(parcel.Context(context.Background()), parcel)
+	}
+
+	res, err := mb.Network.SendMessage(receiver, publishRPCMethodName, parcel)
+	if err != nil {
+		return nil, err
+	}
+
+	return reply.Deserialize(bytes.NewBuffer(res))
+}
+
+// doPublish passes parcel to every local subscriber of its type. Subscriber errors don't stop delivery to others,
+// they are reported in returned Ack.
+func (mb *MessageBus) doPublish(ctx context.Context, parcel core.Parcel) (core.Reply, error) {
+	ctx, span := instracer.StartSpan(ctx, "MessageBus.doPublish")
+	defer span.End()
+	if err := mb.checkPulse(ctx, parcel, false); err != nil {
+		return nil, errors.Wrap(err, "[ doPublish ] error in checkPulse")
+	}
+
+	// We must check barrier just before exiting function
+	// to deliver reply right after pulse switches if it is switching right now.
+	defer readBarrier(ctx, &mb.globalLock)
+
+	if parcel.GetSender().Equal(mb.NodeNetwork.GetOrigin().ID()) {
+		ctx = hack.SetSkipValidation(ctx, true)
+	}
+
+	var subscribers []core.MessageHandler
+	if handler, ok := mb.handlers[parcel.Type()]; ok {
+		subscribers = append(subscribers, handler)
+	}
+	mb.subscribersLock.RLock()
+	subscribers = append(subscribers, mb.subscribers[parcel.Type()]...)
+	mb.subscribersLock.RUnlock()
+	if len(subscribers) == 0 {
+		return nil, errors.New("no subscribers for published message type")
+	}
+
+	ack := &reply.Ack{Errors: make([]string, len(subscribers)), Replies: make([]core.Reply, len(subscribers))}
+	for i, handler := range subscribers {
+		rep, err := handler(ctx, parcel)
+		if err != nil {
+			ack.Errors[i] = err.Error()
+			continue
+		}
+		ack.Replies[i] = rep
+	}
+
+	return ack, nil
+}
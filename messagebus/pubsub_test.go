@@ -0,0 +1,106 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package messagebus
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/core/reply"
+	"github.com/insolar/insolar/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func failingHandler(ctx context.Context, msg core.Parcel) (core.Reply, error) {
+	return nil, errors.New("subscriber failed")
+}
+
+func TestMessageBus_Subscribe_Nil(t *testing.T) {
+	ctx := context.Background()
+	mb, _, _ := prepare(t, ctx, 100, 100)
+
+	err := mb.Subscribe(testType, nil)
+	require.Error(t, err)
+}
+
+func TestMessageBus_doPublish(t *testing.T) {
+	ctx := context.Background()
+	mb, _, parcel := prepare(t, ctx, 100, 100)
+
+	err := mb.Subscribe(testType, failingHandler)
+	require.NoError(t, err)
+	err = mb.Subscribe(testType, testHandler)
+	require.NoError(t, err)
+
+	rep, err := mb.doPublish(ctx, parcel)
+	require.NoError(t, err)
+	ack, ok := rep.(*reply.Ack)
+	require.True(t, ok)
+	assert.Equal(t, []string{"", "subscriber failed", ""}, ack.Errors)
+	assert.Equal(t, []core.Reply{testReply, nil, testReply}, ack.Replies)
+	assert.EqualError(t, ack.Err(), "subscriber failed")
+}
+
+func TestMessageBus_doPublish_NoSubscribers(t *testing.T) {
+	ctx := context.Background()
+	mb, _, parcel := prepare(t, ctx, 100, 100)
+	parcel.(*testutils.ParcelMock).TypeFunc = func() core.MessageType {
+		return core.MessageType(125)
+	}
+
+	_, err := mb.doPublish(ctx, parcel)
+	require.EqualError(t, err, "no subscribers for published message type")
+}
+
+func TestMessageBus_PublishParcel(t *testing.T) {
+	ctx := context.Background()
+	mb, _, parcel := prepare(t, ctx, 100, 100)
+	parcelMock := parcel.(*testutils.ParcelMock)
+	parcelMock.DefaultTargetFunc = func() *core.RecordRef {
+		return nil
+	}
+	parcelMock.DefaultRoleFunc = func() core.DynamicRole {
+		return core.DynamicRoleLightExecutor
+	}
+	parcelMock.ContextFunc = func(ctx context.Context) context.Context {
+		return ctx
+	}
+
+	jc := mb.JetCoordinator.(*testutils.JetCoordinatorMock)
+	jc.QueryRoleMock.Return([]core.RecordRef{{}}, nil)
+
+	err := mb.Subscribe(testType, failingHandler)
+	require.NoError(t, err)
+
+	deliveries, err := mb.PublishParcel(ctx, parcel, core.Pulse{PulseNumber: 100}, nil)
+	require.NoError(t, err)
+
+	var received []core.Delivery
+	for delivery := range deliveries {
+		received = append(received, delivery)
+	}
+	require.Equal(t, 1, len(received))
+	assert.Equal(t, core.RecordRef{}, received[0].Receiver)
+	assert.EqualError(t, received[0].Err, "subscriber failed")
+	assert.Equal(t, &reply.Ack{
+		Errors:  []string{"", "subscriber failed"},
+		Replies: []core.Reply{testReply, nil},
+	}, received[0].Reply)
+}
@@ -42,6 +42,23 @@ func newRecorder(s sender, tape tape, scheme core.PlatformCryptographyScheme, pu
 	}
 }
 
+// parcelTape is a tape that keeps sent parcels along with replies.
+type parcelTape interface {
+	SetParcel(ctx context.Context, msgHash []byte, parcel core.Parcel, rep core.Reply, gotError error) error
+}
+
+// FileRecorder is a recorder that appends replies to on-disk tape as soon as they are received, so the tape
+// survives node crash.
+type FileRecorder struct {
+	*recorder
+	tape *FileTape
+}
+
+// Close flushes and closes recorder's tape file.
+func (r *FileRecorder) Close() error {
+	return r.tape.Close()
+}
+
 // WriteTape writes recorder's tape to the provided writer.
 func (r *recorder) WriteTape(ctx context.Context, w io.Writer) error {
 	return r.tape.Write(ctx, w)
@@ -65,7 +82,11 @@ func (r *recorder) Send(ctx context.Context, msg core.Message, ops *core.Message
 
 	// Save the received Value on the tape.
 	id := GetMessageHash(r.scheme, parcel)
-	err = r.tape.Set(ctx, id, rep, sendErr)
+	if pt, ok := r.tape.(parcelTape); ok {
+		err = pt.SetParcel(ctx, id, parcel, rep, sendErr)
+	} else {
+		err = r.tape.Set(ctx, id, rep, sendErr)
+	}
 	if err != nil {
 		return nil, err
 	}
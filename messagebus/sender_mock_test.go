@@ -46,6 +46,11 @@ type senderMock struct {
 	OnPulsePreCounter uint64
 	OnPulseMock       msenderMockOnPulse
 
+	PublishFunc       func(p context.Context, p1 core.Message, p2 *core.MessageSendOptions) (r <-chan core.Delivery, r1 error)
+	PublishCounter    uint64
+	PublishPreCounter uint64
+	PublishMock       msenderMockPublish
+
 	RegisterFunc       func(p core.MessageType, p1 core.MessageHandler) (r error)
 	RegisterCounter    uint64
 	RegisterPreCounter uint64
@@ -60,6 +65,11 @@ type senderMock struct {
 	SendParcelCounter    uint64
 	SendParcelPreCounter uint64
 	SendParcelMock       msenderMockSendParcel
+
+	SubscribeFunc       func(p core.MessageType, p1 core.MessageHandler) (r error)
+	SubscribeCounter    uint64
+	SubscribePreCounter uint64
+	SubscribeMock       msenderMockSubscribe
 }
 
 //NewsenderMock returns a mock for github.com/insolar/insolar/messagebus.sender
@@ -75,9 +85,11 @@ func NewsenderMock(t minimock.Tester) *senderMock {
 	m.NewPlayerMock = msenderMockNewPlayer{mock: m}
 	m.NewRecorderMock = msenderMockNewRecorder{mock: m}
 	m.OnPulseMock = msenderMockOnPulse{mock: m}
+	m.PublishMock = msenderMockPublish{mock: m}
 	m.RegisterMock = msenderMockRegister{mock: m}
 	m.SendMock = msenderMockSend{mock: m}
 	m.SendParcelMock = msenderMockSendParcel{mock: m}
+	m.SubscribeMock = msenderMockSubscribe{mock: m}
 
 	return m
 }
@@ -809,6 +821,158 @@ func (m *senderMock) OnPulseFinished() bool {
 	return true
 }
 
+type msenderMockPublish struct {
+	mock              *senderMock
+	mainExpectation   *senderMockPublishExpectation
+	expectationSeries []*senderMockPublishExpectation
+}
+
+type senderMockPublishExpectation struct {
+	input  *senderMockPublishInput
+	result *senderMockPublishResult
+}
+
+type senderMockPublishInput struct {
+	p  context.Context
+	p1 core.Message
+	p2 *core.MessageSendOptions
+}
+
+type senderMockPublishResult struct {
+	r  <-chan core.Delivery
+	r1 error
+}
+
+//Expect specifies that invocation of sender.Publish is expected from 1 to Infinity times
+func (m *msenderMockPublish) Expect(p context.Context, p1 core.Message, p2 *core.MessageSendOptions) *msenderMockPublish {
+	m.mock.PublishFunc = nil
+	m.expectationSeries = nil
+
+	if m.mainExpectation == nil {
+		m.mainExpectation = &senderMockPublishExpectation{}
+	}
+	m.mainExpectation.input = &senderMockPublishInput{p, p1, p2}
+	return m
+}
+
+//Return specifies results of invocation of sender.Publish
+func (m *msenderMockPublish) Return(r <-chan core.Delivery, r1 error) *senderMock {
+	m.mock.PublishFunc = nil
+	m.expectationSeries = nil
+
+	if m.mainExpectation == nil {
+		m.mainExpectation = &senderMockPublishExpectation{}
+	}
+	m.mainExpectation.result = &senderMockPublishResult{r, r1}
+	return m.mock
+}
+
+//ExpectOnce specifies that invocation of sender.Publish is expected once
+func (m *msenderMockPublish) ExpectOnce(p context.Context, p1 core.Message, p2 *core.MessageSendOptions) *senderMockPublishExpectation {
+	m.mock.PublishFunc = nil
+	m.mainExpectation = nil
+
+	expectation := &senderMockPublishExpectation{}
+	expectation.input = &senderMockPublishInput{p, p1, p2}
+	m.expectationSeries = append(m.expectationSeries, expectation)
+	return expectation
+}
+
+func (e *senderMockPublishExpectation) Return(r <-chan core.Delivery, r1 error) {
+	e.result = &senderMockPublishResult{r, r1}
+}
+
+//Set uses given function f as a mock of sender.Publish method
+func (m *msenderMockPublish) Set(f func(p context.Context, p1 core.Message, p2 *core.MessageSendOptions) (r <-chan core.Delivery, r1 error)) *senderMock {
+	m.mainExpectation = nil
+	m.expectationSeries = nil
+
+	m.mock.PublishFunc = f
+	return m.mock
+}
+
+//Publish implements github.com/insolar/insolar/messagebus.sender interface
+func (m *senderMock) Publish(p context.Context, p1 core.Message, p2 *core.MessageSendOptions) (r <-chan core.Delivery, r1 error) {
+	counter := atomic.AddUint64(&m.PublishPreCounter, 1)
+	defer atomic.AddUint64(&m.PublishCounter, 1)
+
+	if len(m.PublishMock.expectationSeries) > 0 {
+		if counter > uint64(len(m.PublishMock.expectationSeries)) {
+			m.t.Fatalf("Unexpected call to senderMock.Publish. %v %v %v", p, p1, p2)
+			return
+		}
+
+		input := m.PublishMock.expectationSeries[counter-1].input
+		testify_assert.Equal(m.t, *input, senderMockPublishInput{p, p1, p2}, "sender.Publish got unexpected parameters")
+
+		result := m.PublishMock.expectationSeries[counter-1].result
+		if result == nil {
+			m.t.Fatal("No results are set for the senderMock.Publish")
+			return
+		}
+
+		r = result.r
+		r1 = result.r1
+
+		return
+	}
+
+	if m.PublishMock.mainExpectation != nil {
+
+		input := m.PublishMock.mainExpectation.input
+		if input != nil {
+			testify_assert.Equal(m.t, *input, senderMockPublishInput{p, p1, p2}, "sender.Publish got unexpected parameters")
+		}
+
+		result := m.PublishMock.mainExpectation.result
+		if result == nil {
+			m.t.Fatal("No results are set for the senderMock.Publish")
+		}
+
+		r = result.r
+		r1 = result.r1
+
+		return
+	}
+
+	if m.PublishFunc == nil {
+		m.t.Fatalf("Unexpected call to senderMock.Publish. %v %v %v", p, p1, p2)
+		return
+	}
+
+	return m.PublishFunc(p, p1, p2)
+}
+
+//PublishMinimockCounter returns a count of senderMock.PublishFunc invocations
+func (m *senderMock) PublishMinimockCounter() uint64 {
+	return atomic.LoadUint64(&m.PublishCounter)
+}
+
+//PublishMinimockPreCounter returns the value of senderMock.Publish invocations
+func (m *senderMock) PublishMinimockPreCounter() uint64 {
+	return atomic.LoadUint64(&m.PublishPreCounter)
+}
+
+//PublishFinished returns true if mock invocations count is ok
+func (m *senderMock) PublishFinished() bool {
+	// if expectation series were set then invocations count should be equal to expectations count
+	if len(m.PublishMock.expectationSeries) > 0 {
+		return atomic.LoadUint64(&m.PublishCounter) == uint64(len(m.PublishMock.expectationSeries))
+	}
+
+	// if main expectation was set then invocations count should be greater than zero
+	if m.PublishMock.mainExpectation != nil {
+		return atomic.LoadUint64(&m.PublishCounter) > 0
+	}
+
+	// if func was set then invocations count should be greater than zero
+	if m.PublishFunc != nil {
+		return atomic.LoadUint64(&m.PublishCounter) > 0
+	}
+
+	return true
+}
+
 type msenderMockRegister struct {
 	mock              *senderMock
 	mainExpectation   *senderMockRegisterExpectation
@@ -1262,6 +1426,154 @@ func (m *senderMock) SendParcelFinished() bool {
 	return true
 }
 
+type msenderMockSubscribe struct {
+	mock              *senderMock
+	mainExpectation   *senderMockSubscribeExpectation
+	expectationSeries []*senderMockSubscribeExpectation
+}
+
+type senderMockSubscribeExpectation struct {
+	input  *senderMockSubscribeInput
+	result *senderMockSubscribeResult
+}
+
+type senderMockSubscribeInput struct {
+	p  core.MessageType
+	p1 core.MessageHandler
+}
+
+type senderMockSubscribeResult struct {
+	r error
+}
+
+//Expect specifies that invocation of sender.Subscribe is expected from 1 to Infinity times
+func (m *msenderMockSubscribe) Expect(p core.MessageType, p1 core.MessageHandler) *msenderMockSubscribe {
+	m.mock.SubscribeFunc = nil
+	m.expectationSeries = nil
+
+	if m.mainExpectation == nil {
+		m.mainExpectation = &senderMockSubscribeExpectation{}
+	}
+	m.mainExpectation.input = &senderMockSubscribeInput{p, p1}
+	return m
+}
+
+//Return specifies results of invocation of sender.Subscribe
+func (m *msenderMockSubscribe) Return(r error) *senderMock {
+	m.mock.SubscribeFunc = nil
+	m.expectationSeries = nil
+
+	if m.mainExpectation == nil {
+		m.mainExpectation = &senderMockSubscribeExpectation{}
+	}
+	m.mainExpectation.result = &senderMockSubscribeResult{r}
+	return m.mock
+}
+
+//ExpectOnce specifies that invocation of sender.Subscribe is expected once
+func (m *msenderMockSubscribe) ExpectOnce(p core.MessageType, p1 core.MessageHandler) *senderMockSubscribeExpectation {
+	m.mock.SubscribeFunc = nil
+	m.mainExpectation = nil
+
+	expectation := &senderMockSubscribeExpectation{}
+	expectation.input = &senderMockSubscribeInput{p, p1}
+	m.expectationSeries = append(m.expectationSeries, expectation)
+	return expectation
+}
+
+func (e *senderMockSubscribeExpectation) Return(r error) {
+	e.result = &senderMockSubscribeResult{r}
+}
+
+//Set uses given function f as a mock of sender.Subscribe method
+func (m *msenderMockSubscribe) Set(f func(p core.MessageType, p1 core.MessageHandler) (r error)) *senderMock {
+	m.mainExpectation = nil
+	m.expectationSeries = nil
+
+	m.mock.SubscribeFunc = f
+	return m.mock
+}
+
+//Subscribe implements github.com/insolar/insolar/messagebus.sender interface
+func (m *senderMock) Subscribe(p core.MessageType, p1 core.MessageHandler) (r error) {
+	counter := atomic.AddUint64(&m.SubscribePreCounter, 1)
+	defer atomic.AddUint64(&m.SubscribeCounter, 1)
+
+	if len(m.SubscribeMock.expectationSeries) > 0 {
+		if counter > uint64(len(m.SubscribeMock.expectationSeries)) {
+			m.t.Fatalf("Unexpected call to senderMock.Subscribe. %v %v", p, p1)
+			return
+		}
+
+		input := m.SubscribeMock.expectationSeries[counter-1].input
+		testify_assert.Equal(m.t, *input, senderMockSubscribeInput{p, p1}, "sender.Subscribe got unexpected parameters")
+
+		result := m.SubscribeMock.expectationSeries[counter-1].result
+		if result == nil {
+			m.t.Fatal("No results are set for the senderMock.Subscribe")
+			return
+		}
+
+		r = result.r
+
+		return
+	}
+
+	if m.SubscribeMock.mainExpectation != nil {
+
+		input := m.SubscribeMock.mainExpectation.input
+		if input != nil {
+			testify_assert.Equal(m.t, *input, senderMockSubscribeInput{p, p1}, "sender.Subscribe got unexpected parameters")
+		}
+
+		result := m.SubscribeMock.mainExpectation.result
+		if result == nil {
+			m.t.Fatal("No results are set for the senderMock.Subscribe")
+		}
+
+		r = result.r
+
+		return
+	}
+
+	if m.SubscribeFunc == nil {
+		m.t.Fatalf("Unexpected call to senderMock.Subscribe. %v %v", p, p1)
+		return
+	}
+
+	return m.SubscribeFunc(p, p1)
+}
+
+//SubscribeMinimockCounter returns a count of senderMock.SubscribeFunc invocations
+func (m *senderMock) SubscribeMinimockCounter() uint64 {
+	return atomic.LoadUint64(&m.SubscribeCounter)
+}
+
+//SubscribeMinimockPreCounter returns the value of senderMock.Subscribe invocations
+func (m *senderMock) SubscribeMinimockPreCounter() uint64 {
+	return atomic.LoadUint64(&m.SubscribePreCounter)
+}
+
+//SubscribeFinished returns true if mock invocations count is ok
+func (m *senderMock) SubscribeFinished() bool {
+	// if expectation series were set then invocations count should be equal to expectations count
+	if len(m.SubscribeMock.expectationSeries) > 0 {
+		return atomic.LoadUint64(&m.SubscribeCounter) == uint64(len(m.SubscribeMock.expectationSeries))
+	}
+
+	// if main expectation was set then invocations count should be greater than zero
+	if m.SubscribeMock.mainExpectation != nil {
+		return atomic.LoadUint64(&m.SubscribeCounter) > 0
+	}
+
+	// if func was set then invocations count should be greater than zero
+	if m.SubscribeFunc != nil {
+		return atomic.LoadUint64(&m.SubscribeCounter) > 0
+	}
+
+	return true
+}
+
 //ValidateCallCounters checks that all mocked methods of the interface have been called at least once
 //Deprecated: please use MinimockFinish method or use Finish method of minimock.Controller
 func (m *senderMock) ValidateCallCounters() {
@@ -1286,6 +1598,10 @@ func (m *senderMock) ValidateCallCounters() {
 		m.t.Fatal("Expected call to senderMock.OnPulse")
 	}
 
+	if !m.PublishFinished() {
+		m.t.Fatal("Expected call to senderMock.Publish")
+	}
+
 	if !m.RegisterFinished() {
 		m.t.Fatal("Expected call to senderMock.Register")
 	}
@@ -1298,6 +1614,10 @@ func (m *senderMock) ValidateCallCounters() {
 		m.t.Fatal("Expected call to senderMock.SendParcel")
 	}
 
+	if !m.SubscribeFinished() {
+		m.t.Fatal("Expected call to senderMock.Subscribe")
+	}
+
 }
 
 //CheckMocksCalled checks that all mocked methods of the interface have been called at least once
@@ -1335,6 +1655,10 @@ func (m *senderMock) MinimockFinish() {
 		m.t.Fatal("Expected call to senderMock.OnPulse")
 	}
 
+	if !m.PublishFinished() {
+		m.t.Fatal("Expected call to senderMock.Publish")
+	}
+
 	if !m.RegisterFinished() {
 		m.t.Fatal("Expected call to senderMock.Register")
 	}
@@ -1347,6 +1671,10 @@ func (m *senderMock) MinimockFinish() {
 		m.t.Fatal("Expected call to senderMock.SendParcel")
 	}
 
+	if !m.SubscribeFinished() {
+		m.t.Fatal("Expected call to senderMock.Subscribe")
+	}
+
 }
 
 //Wait waits for all mocked methods to be called at least once
@@ -1366,9 +1694,11 @@ func (m *senderMock) MinimockWait(timeout time.Duration) {
 		ok = ok && m.NewPlayerFinished()
 		ok = ok && m.NewRecorderFinished()
 		ok = ok && m.OnPulseFinished()
+		ok = ok && m.PublishFinished()
 		ok = ok && m.RegisterFinished()
 		ok = ok && m.SendFinished()
 		ok = ok && m.SendParcelFinished()
+		ok = ok && m.SubscribeFinished()
 
 		if ok {
 			return
@@ -1397,6 +1727,10 @@ func (m *senderMock) MinimockWait(timeout time.Duration) {
 				m.t.Error("Expected call to senderMock.OnPulse")
 			}
 
+			if !m.PublishFinished() {
+				m.t.Error("Expected call to senderMock.Publish")
+			}
+
 			if !m.RegisterFinished() {
 				m.t.Error("Expected call to senderMock.Register")
 			}
@@ -1409,6 +1743,10 @@ func (m *senderMock) MinimockWait(timeout time.Duration) {
 				m.t.Error("Expected call to senderMock.SendParcel")
 			}
 
+			if !m.SubscribeFinished() {
+				m.t.Error("Expected call to senderMock.Subscribe")
+			}
+
 			m.t.Fatalf("Some mocks were not called on time: %s", timeout)
 			return
 		default:
@@ -1441,6 +1779,10 @@ func (m *senderMock) AllMocksCalled() bool {
 		return false
 	}
 
+	if !m.PublishFinished() {
+		return false
+	}
+
 	if !m.RegisterFinished() {
 		return false
 	}
@@ -1453,5 +1795,9 @@ func (m *senderMock) AllMocksCalled() bool {
 		return false
 	}
 
+	if !m.SubscribeFinished() {
+		return false
+	}
+
 	return true
 }
@@ -62,6 +62,8 @@ type itemBlob struct {
 	MsgHash []byte
 	ReplyB  []byte
 	ErrorB  []byte
+	// ParcelB is only written by FileTape.
+	ParcelB []byte `codec:",omitempty"`
 }
 
 func newMemoryTape(pulse core.PulseNumber) *memoryTape {
@@ -9,3 +9,11 @@ var APIContractExecutionTime = prometheus.NewSummaryVec(prometheus.SummaryOpts{
 	Subsystem:  "API",
 	Objectives: map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.95: 0.005, 0.99: 0.001},
 }, []string{"method", "success"})
+
+var APIBatchSize = prometheus.NewSummary(prometheus.SummaryOpts{
+	Name:       "batch_size",
+	Help:       "Number of requests in batch call",
+	Namespace:  insolarNamespace,
+	Subsystem:  "API",
+	Objectives: map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.95: 0.005, 0.99: 0.001},
+})
@@ -24,16 +24,23 @@ func GetInsolarRegistry() *prometheus.Registry {
 	registry.MustRegister(NetworkPacketReceivedTotal)
 	registry.MustRegister(NetworkParcelReceivedTotal)
 	registry.MustRegister(NetworkComplete)
+	registry.MustRegister(NetworkSendQueueDepth)
+	registry.MustRegister(NetworkPacketDroppedTotal)
 
 	registry.MustRegister(ParcelsSentTotal)
 	registry.MustRegister(ParcelsTime)
 	registry.MustRegister(ParcelsSentSizeBytes)
 	registry.MustRegister(ParcelsReplySizeBytes)
 	registry.MustRegister(LocallyDeliveredParcelsTotal)
+	registry.MustRegister(ParcelsPublishedTotal)
+	registry.MustRegister(PublishedParcelsFailedTotal)
+	registry.MustRegister(SendPolicyOutcomesTotal)
+	registry.MustRegister(SendPolicyRetriesTotal)
 
 	registry.MustRegister(GopluginContractExecutionTime)
 
 	registry.MustRegister(APIContractExecutionTime)
+	registry.MustRegister(APIBatchSize)
 
 	return registry
 }
@@ -70,3 +70,43 @@ var ParcelsReplySizeBytes = prometheus.NewSummaryVec(
 	},
 	[]string{"messageType"},
 )
+
+var ParcelsPublishedTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: insolarNamespace,
+		Subsystem: "messagebus",
+		Name:      "parcels_published_total",
+		Help:      "Total number of parcels published to subscribers",
+	},
+	[]string{"messageType"},
+)
+
+var PublishedParcelsFailedTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: insolarNamespace,
+		Subsystem: "messagebus",
+		Name:      "published_parcels_failed_total",
+		Help:      "Total number of failed deliveries of published parcels",
+	},
+	[]string{"messageType"},
+)
+
+var SendPolicyOutcomesTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: insolarNamespace,
+		Subsystem: "messagebus",
+		Name:      "send_policy_outcomes_total",
+		Help:      "Total number of parcels sent with send policy by outcome",
+	},
+	[]string{"messageType", "outcome"},
+)
+
+var SendPolicyRetriesTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: insolarNamespace,
+		Subsystem: "messagebus",
+		Name:      "send_policy_retries_total",
+		Help:      "Total number of parcel resends made by send policy",
+	},
+	[]string{"messageType", "reason"},
+)
@@ -83,3 +83,19 @@ var NetworkComplete = prometheus.NewGauge(prometheus.GaugeOpts{
 	Namespace: insolarNamespace,
 	Subsystem: "network",
 })
+
+// NetworkSendQueueDepth is current number of packets waiting in per-peer send queues metric
+var NetworkSendQueueDepth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name:      "send_queue_depth",
+	Help:      "Current number of packets waiting in per-peer send queues",
+	Namespace: insolarNamespace,
+	Subsystem: "network",
+}, []string{"priority"})
+
+// NetworkPacketDroppedTotal is total number of packets dropped by flow control metric
+var NetworkPacketDroppedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name:      "packet_dropped_total",
+	Help:      "Total number of packets dropped because of full send queue",
+	Namespace: insolarNamespace,
+	Subsystem: "network",
+}, []string{"priority"})
@@ -18,7 +18,6 @@ package bootstrap
 
 import (
 	"context"
-	"encoding/gob"
 
 	"github.com/insolar/insolar/certificate"
 	"github.com/insolar/insolar/component"
@@ -27,6 +26,7 @@ import (
 	"github.com/insolar/insolar/instrumentation/inslogger"
 	"github.com/insolar/insolar/network"
 	"github.com/insolar/insolar/network/controller/common"
+	"github.com/insolar/insolar/network/transport/packet"
 	"github.com/insolar/insolar/network/transport/packet/types"
 	"github.com/insolar/insolar/platformpolicy"
 	"github.com/pkg/errors"
@@ -80,10 +80,10 @@ type RegistrationResponse struct {
 }
 
 func init() {
-	gob.Register(&AuthorizationRequest{})
-	gob.Register(&AuthorizationResponse{})
-	gob.Register(&RegistrationRequest{})
-	gob.Register(&RegistrationResponse{})
+	packet.RegisterPayload(packet.PayloadAuthorizationRequest, &AuthorizationRequest{})
+	packet.RegisterPayload(packet.PayloadAuthorizationResponse, &AuthorizationResponse{})
+	packet.RegisterPayload(packet.PayloadRegistrationRequest, &RegistrationRequest{})
+	packet.RegisterPayload(packet.PayloadRegistrationResponse, &RegistrationResponse{})
 }
 
 // Authorize node on the discovery node (step 2 of the bootstrap process)
@@ -18,7 +18,6 @@ package bootstrap
 
 import (
 	"context"
-	"encoding/gob"
 	"fmt"
 	"strings"
 	"sync"
@@ -33,6 +32,7 @@ import (
 	"github.com/insolar/insolar/network/controller/pinger"
 	"github.com/insolar/insolar/network/nodenetwork"
 	"github.com/insolar/insolar/network/transport/host"
+	"github.com/insolar/insolar/network/transport/packet"
 	"github.com/insolar/insolar/network/transport/packet/types"
 	"github.com/insolar/insolar/platformpolicy"
 	"github.com/pkg/errors"
@@ -154,12 +154,12 @@ const (
 )
 
 func init() {
-	gob.Register(&NodeBootstrapRequest{})
-	gob.Register(&NodeBootstrapResponse{})
-	gob.Register(&StartSessionRequest{})
-	gob.Register(&StartSessionResponse{})
-	gob.Register(&GenesisRequest{})
-	gob.Register(&GenesisResponse{})
+	packet.RegisterPayload(packet.PayloadNodeBootstrapRequest, &NodeBootstrapRequest{})
+	packet.RegisterPayload(packet.PayloadNodeBootstrapResponse, &NodeBootstrapResponse{})
+	packet.RegisterPayload(packet.PayloadStartSessionRequest, &StartSessionRequest{})
+	packet.RegisterPayload(packet.PayloadStartSessionResponse, &StartSessionResponse{})
+	packet.RegisterPayload(packet.PayloadGenesisRequest, &GenesisRequest{})
+	packet.RegisterPayload(packet.PayloadGenesisResponse, &GenesisResponse{})
 }
 
 // Bootstrap on the discovery node (step 1 of the bootstrap process)
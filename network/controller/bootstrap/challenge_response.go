@@ -18,7 +18,6 @@ package bootstrap
 
 import (
 	"context"
-	"encoding/gob"
 
 	"github.com/insolar/insolar/component"
 	"github.com/insolar/insolar/core"
@@ -27,6 +26,7 @@ import (
 	"github.com/insolar/insolar/network"
 	"github.com/insolar/insolar/network/controller/common"
 	"github.com/insolar/insolar/network/transport/host"
+	"github.com/insolar/insolar/network/transport/packet"
 	"github.com/insolar/insolar/network/transport/packet/types"
 	"github.com/jbenet/go-base58"
 	"github.com/pkg/errors"
@@ -98,10 +98,10 @@ type ChallengePayload struct {
 }
 
 func init() {
-	gob.Register(&ChallengeRequest{})
-	gob.Register(&SignedChallengeResponse{})
-	gob.Register(&SignedChallengeRequest{})
-	gob.Register(&ChallengeResponse{})
+	packet.RegisterPayload(packet.PayloadChallengeRequest, &ChallengeRequest{})
+	packet.RegisterPayload(packet.PayloadSignedChallengeResponse, &SignedChallengeResponse{})
+	packet.RegisterPayload(packet.PayloadSignedChallengeRequest, &SignedChallengeRequest{})
+	packet.RegisterPayload(packet.PayloadChallengeResponse, &ChallengeResponse{})
 }
 
 func (cr *challengeResponseController) processChallenge1(ctx context.Context, request network.Request) (network.Response, error) {
@@ -47,4 +47,7 @@ type Options struct {
 
 	// HandshakeSession TTL
 	HandshakeSessionTTL time.Duration
+
+	// The maximum size of parcel or reply received over stream
+	MaxParcelSize int64
 }
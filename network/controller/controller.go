@@ -54,6 +54,11 @@ func (c *Controller) RemoteProcedureRegister(name string, method core.RemoteProc
 	c.RPCController.RemoteProcedureRegister(name, method)
 }
 
+// RemoteStreamProcedureRegister register remote procedure that will be executed when message is received over stream.
+func (c *Controller) RemoteStreamProcedureRegister(name string, method core.RemoteStreamProcedure) {
+	c.RPCController.RemoteStreamProcedureRegister(name, method)
+}
+
 // SendCascadeMessage sends a message from MessageBus to a cascade of nodes.
 func (c *Controller) SendCascadeMessage(data core.Cascade, method string, msg core.Parcel) error {
 	return c.RPCController.SendCascadeMessage(data, method, msg)
@@ -83,6 +88,7 @@ func ConfigureOptions(config configuration.HostNetwork) *common.Options {
 		PacketTimeout:       10 * time.Second,
 		BootstrapTimeout:    10 * time.Second,
 		HandshakeSessionTTL: time.Duration(config.HandshakeSessionTTL) * time.Millisecond,
+		MaxParcelSize:       int64(config.MaxParcelSize),
 	}
 }
 
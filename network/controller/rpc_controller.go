@@ -18,8 +18,9 @@ package controller
 
 import (
 	"context"
-	"encoding/gob"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"strings"
 	"time"
 
@@ -32,7 +33,10 @@ import (
 	"github.com/insolar/insolar/network"
 	"github.com/insolar/insolar/network/cascade"
 	"github.com/insolar/insolar/network/controller/common"
+	"github.com/insolar/insolar/network/transport"
+	"github.com/insolar/insolar/network/transport/packet"
 	"github.com/insolar/insolar/network/transport/packet/types"
+	"github.com/insolar/insolar/network/utils"
 	"github.com/pkg/errors"
 )
 
@@ -45,14 +49,16 @@ type RPCController interface {
 	SendMessage(nodeID core.RecordRef, name string, msg core.Parcel) ([]byte, error)
 	SendCascadeMessage(data core.Cascade, method string, msg core.Parcel) error
 	RemoteProcedureRegister(name string, method core.RemoteProcedure)
+	RemoteStreamProcedureRegister(name string, method core.RemoteStreamProcedure)
 }
 
 type rpcController struct {
 	Scheme core.PlatformCryptographyScheme `inject:""`
 
-	options     *common.Options
-	hostNetwork network.HostNetwork
-	methodTable map[string]core.RemoteProcedure
+	options           *common.Options
+	hostNetwork       network.HostNetwork
+	methodTable       map[string]core.RemoteProcedure
+	streamMethodTable map[string]core.RemoteStreamProcedure
 }
 
 type RequestRPC struct {
@@ -78,10 +84,10 @@ type ResponseCascade struct {
 }
 
 func init() {
-	gob.Register(&RequestRPC{})
-	gob.Register(&ResponseRPC{})
-	gob.Register(&RequestCascade{})
-	gob.Register(&ResponseCascade{})
+	packet.RegisterPayload(packet.PayloadRequestRPC, &RequestRPC{})
+	packet.RegisterPayload(packet.PayloadResponseRPC, &ResponseRPC{})
+	packet.RegisterPayload(packet.PayloadRequestCascade, &RequestCascade{})
+	packet.RegisterPayload(packet.PayloadResponseCascade, &ResponseCascade{})
 }
 
 func (rpc *rpcController) IAmRPCController() {
@@ -92,6 +98,10 @@ func (rpc *rpcController) RemoteProcedureRegister(name string, method core.Remot
 	rpc.methodTable[name] = method
 }
 
+func (rpc *rpcController) RemoteStreamProcedureRegister(name string, method core.RemoteStreamProcedure) {
+	rpc.streamMethodTable[name] = method
+}
+
 func (rpc *rpcController) invoke(ctx context.Context, name string, data [][]byte) ([]byte, error) {
 	method, exists := rpc.methodTable[name]
 	if !exists {
@@ -185,19 +195,66 @@ func (rpc *rpcController) requestCascadeSendMessage(ctx context.Context, data co
 	return nil
 }
 
+// isHeavyParcel checks if parcel is heavy replication traffic which is sent after other parcels.
+func isHeavyParcel(t core.MessageType) bool {
+	switch t {
+	case core.TypeHeavyStartStop, core.TypeHeavyPayload, core.TypeHeavyReset:
+		return true
+	}
+	return false
+}
+
+// isStreamParcel checks if parcel or its reply may be large, such parcels are sent over stream,
+// so they don't block other packets to the node.
+func isStreamParcel(t core.MessageType) bool {
+	switch t {
+	case core.TypeHeavyPayload, core.TypeGetCode:
+		return true
+	}
+	return false
+}
+
 func (rpc *rpcController) SendMessage(nodeID core.RecordRef, name string, msg core.Parcel) ([]byte, error) {
-	msgBytes := message.ParcelToBytes(msg)
-	metrics.ParcelsSentSizeBytes.WithLabelValues(msg.Type().String()).Observe(float64(len(msgBytes)))
+	start := time.Now()
+	ctx := msg.Context(context.Background())
+	if isHeavyParcel(msg.Type()) {
+		ctx = transport.WithPriority(ctx, transport.PriorityHeavy)
+	}
+	logger := inslogger.FromContext(ctx)
+	logger.Debugf("SendParcel with nodeID = %s method = %s, message reference = %s", nodeID.String(),
+		name, msg.DefaultTarget().String())
+
+	var result []byte
+	var err error
+	streamed := isStreamParcel(msg.Type())
+	if streamed {
+		result, err = rpc.sendStreamMessage(ctx, nodeID, name, msg)
+		if errors.Cause(err) == transport.ErrStreamsNotSupported {
+			streamed = false
+		}
+	}
+	if !streamed {
+		msgBytes := message.ParcelToBytes(msg)
+		metrics.ParcelsSentSizeBytes.WithLabelValues(msg.Type().String()).Observe(float64(len(msgBytes)))
+		result, err = rpc.sendRequestMessage(ctx, nodeID, name, msgBytes)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debugf("Inside SendParcel: type - '%s', target - %s, caller - %s, targetRole - %s, time - %s",
+		msg.Type(), msg.DefaultTarget(), msg.GetCaller(), msg.DefaultRole(), time.Since(start))
+	metrics.ParcelsReplySizeBytes.WithLabelValues(msg.Type().String()).Observe(float64(len(result)))
+	metrics.NetworkParcelSentTotal.WithLabelValues(msg.Type().String()).Inc()
+	return result, nil
+}
+
+func (rpc *rpcController) sendRequestMessage(ctx context.Context, nodeID core.RecordRef, name string, msgBytes []byte) ([]byte, error) {
 	request := rpc.hostNetwork.NewRequestBuilder().Type(types.RPC).Data(&RequestRPC{
 		Method: name,
 		Data:   [][]byte{msgBytes},
 	}).Build()
 
-	start := time.Now()
-	ctx := msg.Context(context.Background())
-	logger := inslogger.FromContext(ctx)
-	logger.Debugf("SendParcel with nodeID = %s method = %s, message reference = %s, RequestID = %d", nodeID.String(),
-		name, msg.DefaultTarget().String(), request.GetRequestID())
 	future, err := rpc.hostNetwork.SendRequest(ctx, request, nodeID)
 	if err != nil {
 		return nil, errors.Wrapf(err, "Error sending RPC request to node %s", nodeID.String())
@@ -207,16 +264,88 @@ func (rpc *rpcController) SendMessage(nodeID core.RecordRef, name string, msg co
 		return nil, errors.Wrapf(err, "Error getting RPC response from node %s", nodeID.String())
 	}
 	data := response.GetData().(*ResponseRPC)
-	logger.Debugf("Inside SendParcel: type - '%s', target - %s, caller - %s, targetRole - %s, time - %s",
-		msg.Type(), msg.DefaultTarget(), msg.GetCaller(), msg.DefaultRole(), time.Since(start))
 	if !data.Success {
 		return nil, errors.New("RPC call returned error: " + data.Error)
 	}
-	metrics.ParcelsReplySizeBytes.WithLabelValues(msg.Type().String()).Observe(float64(len(data.Result)))
-	metrics.NetworkParcelSentTotal.WithLabelValues(msg.Type().String()).Inc()
 	return data.Result, nil
 }
 
+// Stream RPC reply starts with status byte, it is followed by result on success and by error message on failure.
+const (
+	streamRPCFailure byte = iota
+	streamRPCSuccess
+)
+
+// defaultMaxParcelSize is used if max size of parcel received over stream is not configured.
+const defaultMaxParcelSize = 128 * 1024 * 1024
+
+func (rpc *rpcController) maxParcelSize() int64 {
+	if rpc.options.MaxParcelSize > 0 {
+		return rpc.options.MaxParcelSize
+	}
+	return defaultMaxParcelSize
+}
+
+// readStream reads stream until remote side closes it, it fails if stream is larger than max parcel size.
+func (rpc *rpcController) readStream(stream io.Reader) ([]byte, error) {
+	limit := rpc.maxParcelSize()
+	data, err := ioutil.ReadAll(io.LimitReader(stream, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, errors.Errorf("stream is larger than %d bytes", limit)
+	}
+	return data, nil
+}
+
+// countingWriter counts bytes written to w.
+type countingWriter struct {
+	w io.Writer
+	n int
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += n
+	return n, err
+}
+
+// sendStreamMessage encodes parcel to stream and reads reply until remote side closes the stream.
+func (rpc *rpcController) sendStreamMessage(ctx context.Context, nodeID core.RecordRef, name string, msg core.Parcel) ([]byte, error) {
+	request := rpc.hostNetwork.NewRequestBuilder().Type(types.RPC).Data(&RequestRPC{Method: name}).Build()
+
+	stream, err := rpc.hostNetwork.OpenStream(ctx, request, nodeID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error opening RPC stream to node %s", nodeID.String())
+	}
+	defer utils.CloseVerbose(stream)
+
+	err = stream.SetDeadline(time.Now().Add(rpc.options.PacketTimeout))
+	if err != nil {
+		return nil, errors.Wrap(err, "Error setting RPC stream deadline")
+	}
+	written := &countingWriter{w: stream}
+	if err = message.WriteParcel(written, msg); err != nil {
+		return nil, errors.Wrapf(err, "Error writing RPC stream to node %s", nodeID.String())
+	}
+	metrics.ParcelsSentSizeBytes.WithLabelValues(msg.Type().String()).Observe(float64(written.n))
+	if err = stream.CloseWrite(); err != nil {
+		return nil, errors.Wrapf(err, "Error closing RPC stream to node %s", nodeID.String())
+	}
+	reply, err := rpc.readStream(stream)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error reading RPC stream from node %s", nodeID.String())
+	}
+	if len(reply) == 0 {
+		return nil, errors.Errorf("Empty RPC stream reply from node %s", nodeID.String())
+	}
+	if reply[0] != streamRPCSuccess {
+		return nil, errors.New("RPC call returned error: " + string(reply[1:]))
+	}
+	return reply[1:], nil
+}
+
 func (rpc *rpcController) processMessage(ctx context.Context, request network.Request) (network.Response, error) {
 	payload := request.GetData().(*RequestRPC)
 	result, err := rpc.invoke(ctx, payload.Method, payload.Data)
@@ -227,6 +356,54 @@ func (rpc *rpcController) processMessage(ctx context.Context, request network.Re
 	return rpc.hostNetwork.BuildResponse(ctx, request, &ResponseRPC{Success: true, Result: result}), nil
 }
 
+func (rpc *rpcController) processStream(ctx context.Context, request network.Request, stream network.Stream) {
+	defer utils.CloseVerbose(stream)
+	logger := inslogger.FromContext(ctx)
+
+	err := stream.SetDeadline(time.Now().Add(rpc.options.PacketTimeout))
+	if err != nil {
+		logger.Error("Failed to set RPC stream deadline: ", err)
+		return
+	}
+	payload := request.GetData().(*RequestRPC)
+	var result []byte
+	if method, ok := rpc.streamMethodTable[payload.Method]; ok {
+		// parcel is decoded while it is read from stream
+		limited := &io.LimitedReader{R: stream, N: rpc.maxParcelSize()}
+		result, err = method(ctx, limited)
+		if err != nil && limited.N == 0 {
+			// sender is still writing, so stream is closed without reply
+			logger.Errorf("Failed to read RPC stream from node %s: stream is larger than %d bytes",
+				request.GetSender(), rpc.maxParcelSize())
+			return
+		}
+	} else {
+		var data []byte
+		data, err = rpc.readStream(stream)
+		if err != nil {
+			logger.Errorf("Failed to read RPC stream from node %s: %s", request.GetSender(), err)
+			return
+		}
+		result, err = rpc.invoke(ctx, payload.Method, [][]byte{data})
+	}
+	metrics.NetworkParcelReceivedTotal.WithLabelValues(request.GetType().String()).Inc()
+
+	status := streamRPCSuccess
+	if err != nil {
+		status, result = streamRPCFailure, []byte(err.Error())
+	}
+	if _, err = stream.Write([]byte{status}); err == nil {
+		_, err = stream.Write(result)
+	}
+	if err != nil {
+		logger.Errorf("Failed to write RPC stream to node %s: %s", request.GetSender(), err)
+		return
+	}
+	if err = stream.CloseWrite(); err != nil {
+		logger.Errorf("Failed to close RPC stream to node %s: %s", request.GetSender(), err)
+	}
+}
+
 func (rpc *rpcController) processCascade(ctx context.Context, request network.Request) (network.Response, error) {
 	payload := request.GetData().(*RequestCascade)
 	ctx, logger := inslogger.WithTraceField(ctx, payload.TraceID)
@@ -252,12 +429,14 @@ func (rpc *rpcController) processCascade(ctx context.Context, request network.Re
 func (rpc *rpcController) Start(ctx context.Context) error {
 	rpc.hostNetwork.RegisterRequestHandler(types.RPC, rpc.processMessage)
 	rpc.hostNetwork.RegisterRequestHandler(types.Cascade, rpc.processCascade)
+	rpc.hostNetwork.RegisterStreamHandler(types.RPC, rpc.processStream)
 	return nil
 }
 
 func NewRPCController(options *common.Options, hostNetwork network.HostNetwork) RPCController {
 	return &rpcController{options: options,
-		hostNetwork: hostNetwork,
-		methodTable: make(map[string]core.RemoteProcedure),
+		hostNetwork:       hostNetwork,
+		methodTable:       make(map[string]core.RemoteProcedure),
+		streamMethodTable: make(map[string]core.RemoteStreamProcedure),
 	}
 }
@@ -0,0 +1,205 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/core/message"
+	"github.com/insolar/insolar/instrumentation/instracer"
+	"github.com/insolar/insolar/network"
+	"github.com/insolar/insolar/network/controller/common"
+	"github.com/insolar/insolar/network/hostnetwork"
+	"github.com/insolar/insolar/network/transport"
+	"github.com/insolar/insolar/network/transport/packet/types"
+	"github.com/insolar/insolar/testutils"
+	networkUtils "github.com/insolar/insolar/testutils/network"
+)
+
+// pipeStream is network.Stream over pair of pipes.
+type pipeStream struct {
+	*io.PipeReader
+	*io.PipeWriter
+}
+
+func newPipeStreams() (*pipeStream, *pipeStream) {
+	r1, w1 := io.Pipe()
+	r2, w2 := io.Pipe()
+	return &pipeStream{PipeReader: r1, PipeWriter: w2}, &pipeStream{PipeReader: r2, PipeWriter: w1}
+}
+
+func (s *pipeStream) Close() error {
+	s.PipeReader.Close()
+	return s.PipeWriter.Close()
+}
+
+func (s *pipeStream) CloseWrite() error {
+	return s.PipeWriter.Close()
+}
+
+func (s *pipeStream) SetDeadline(time.Time) error {
+	return nil
+}
+
+// sentRequest is request received from sender.
+type sentRequest struct {
+	network.Request
+	sender core.RecordRef
+}
+
+func (r *sentRequest) GetSender() core.RecordRef {
+	return r.sender
+}
+
+type responseFuture struct {
+	response network.Response
+}
+
+func (f *responseFuture) GetRequest() network.Request {
+	return nil
+}
+
+func (f *responseFuture) Response() <-chan network.Response {
+	result := make(chan network.Response, 1)
+	result <- f.response
+	return result
+}
+
+func (f *responseFuture) GetResponse(time.Duration) (network.Response, error) {
+	return f.response, nil
+}
+
+func newCodeParcel(code core.RecordRef) *message.Parcel {
+	return &message.Parcel{
+		Msg:           &message.GetCode{Code: code},
+		TraceSpanData: instracer.MustSerialize(context.Background()),
+	}
+}
+
+func newTestRPCController(t *testing.T) (*rpcController, *networkUtils.HostNetworkMock) {
+	hostNetwork := networkUtils.NewHostNetworkMock(t)
+	hostNetwork.NewRequestBuilderMock.Set(func() network.RequestBuilder {
+		return &hostnetwork.Builder{}
+	})
+	hostNetwork.RegisterRequestHandlerMock.Set(func(types.PacketType, network.RequestHandler) {})
+	hostNetwork.RegisterStreamHandlerMock.Set(func(p types.PacketType, handler network.StreamHandler) {
+		hostNetwork.OpenStreamMock.Set(func(ctx context.Context, request network.Request, receiver core.RecordRef) (network.Stream, error) {
+			local, remote := newPipeStreams()
+			go handler(ctx, &sentRequest{Request: request, sender: testutils.RandomRef()}, remote)
+			return local, nil
+		})
+	})
+
+	rpc := NewRPCController(&common.Options{PacketTimeout: time.Second}, hostNetwork).(*rpcController)
+	require.NoError(t, rpc.Start(context.Background()))
+	return rpc, hostNetwork
+}
+
+func TestRPCController_SendStreamMessage(t *testing.T) {
+	rpc, hostNetwork := newTestRPCController(t)
+	parcel := newCodeParcel(testutils.RandomRef())
+	code := make([]byte, 1024*1024)
+	rpc.RemoteProcedureRegister("test", func(ctx context.Context, args [][]byte) ([]byte, error) {
+		assert.Equal(t, [][]byte{message.ParcelToBytes(parcel)}, args)
+		return code, nil
+	})
+
+	result, err := rpc.SendMessage(testutils.RandomRef(), "test", parcel)
+	require.NoError(t, err)
+	assert.Equal(t, code, result)
+	assert.Equal(t, uint64(1), hostNetwork.OpenStreamCounter)
+	assert.Equal(t, uint64(0), hostNetwork.SendRequestCounter)
+}
+
+func TestRPCController_SendStreamMessageError(t *testing.T) {
+	rpc, _ := newTestRPCController(t)
+	rpc.RemoteProcedureRegister("test", func(ctx context.Context, args [][]byte) ([]byte, error) {
+		return nil, errors.New("code not found")
+	})
+
+	_, err := rpc.SendMessage(testutils.RandomRef(), "test", newCodeParcel(core.RecordRef{}))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "code not found")
+}
+
+func TestRPCController_SendStreamMessageFallback(t *testing.T) {
+	rpc, hostNetwork := newTestRPCController(t)
+	hostNetwork.OpenStreamMock.Return(nil, transport.ErrStreamsNotSupported)
+	response := (&hostnetwork.Builder{}).Data(&ResponseRPC{Success: true, Result: []byte("code")}).Build()
+	hostNetwork.SendRequestMock.Return(&responseFuture{response: response}, nil)
+
+	result, err := rpc.SendMessage(testutils.RandomRef(), "test", newCodeParcel(core.RecordRef{}))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("code"), result)
+	assert.Equal(t, uint64(1), hostNetwork.SendRequestCounter)
+}
+
+func TestRPCController_SendStreamProcedure(t *testing.T) {
+	rpc, _ := newTestRPCController(t)
+	parcel := newCodeParcel(testutils.RandomRef())
+	rpc.RemoteStreamProcedureRegister("test", func(ctx context.Context, arg io.Reader) ([]byte, error) {
+		received, err := message.DeserializeParcel(arg)
+		require.NoError(t, err)
+		assert.Equal(t, parcel.Message(), received.Message())
+		return []byte("code"), nil
+	})
+
+	result, err := rpc.SendMessage(testutils.RandomRef(), "test", parcel)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("code"), result)
+}
+
+func TestRPCController_SendStreamMessageTooLarge(t *testing.T) {
+	rpc, _ := newTestRPCController(t)
+	rpc.options.MaxParcelSize = 64
+	rpc.RemoteProcedureRegister("test", func(ctx context.Context, args [][]byte) ([]byte, error) {
+		t.Error("too large parcel is passed to procedure")
+		return nil, nil
+	})
+	rpc.RemoteStreamProcedureRegister("stream", func(ctx context.Context, arg io.Reader) ([]byte, error) {
+		_, err := message.DeserializeParcel(arg)
+		return nil, err
+	})
+
+	// stream is closed without reply
+	_, err := rpc.SendMessage(testutils.RandomRef(), "test", newCodeParcel(core.RecordRef{}))
+	require.Error(t, err)
+
+	_, err = rpc.SendMessage(testutils.RandomRef(), "stream", newCodeParcel(core.RecordRef{}))
+	require.Error(t, err)
+}
+
+func TestRPCController_ReadStreamLimit(t *testing.T) {
+	rpc, _ := newTestRPCController(t)
+	rpc.options.MaxParcelSize = 4
+
+	data, err := rpc.readStream(bytes.NewReader([]byte("code")))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("code"), data)
+
+	_, err = rpc.readStream(bytes.NewReader([]byte("codes")))
+	require.EqualError(t, err, "stream is larger than 4 bytes")
+}
@@ -31,12 +31,14 @@ import (
 	"github.com/insolar/insolar/network/transport/packet"
 	"github.com/insolar/insolar/network/transport/packet/types"
 	"github.com/insolar/insolar/network/transport/relay"
+	"github.com/insolar/insolar/network/utils"
 	"github.com/pkg/errors"
 )
 
 type hostTransport struct {
 	transportBase
-	handlers map[types.PacketType]network.RequestHandler
+	handlers       map[types.PacketType]network.RequestHandler
+	streamHandlers map[types.PacketType]network.StreamHandler
 }
 
 type packetWrapper packet.Packet
@@ -115,6 +117,20 @@ func (h *hostTransport) processMessage(msg *packet.Packet) {
 	}
 }
 
+func (h *hostTransport) processStream(stream transport.Stream) {
+	msg := stream.Packet()
+	ctx, logger := inslogger.WithTraceField(context.Background(), msg.TraceID)
+	logger.Debugf("Got %s stream from host %s", msg.Type.String(), msg.Sender.String())
+	handler, exist := h.streamHandlers[msg.Type]
+	if !exist {
+		logger.Errorf("No stream handler set for packet type %s from node %s",
+			msg.Type.String(), msg.Sender.NodeID.String())
+		utils.CloseVerbose(stream)
+		return
+	}
+	handler(ctx, (*packetWrapper)(msg), stream)
+}
+
 // SendRequestPacket send request packet to a remote node.
 func (h *hostTransport) SendRequestPacket(ctx context.Context, request network.Request, receiver *host.Host) (network.Future, error) {
 	inslogger.FromContext(ctx).Debugf("Send %s request to host %s", request.GetType().String(), receiver.String())
@@ -134,6 +150,21 @@ func (h *hostTransport) RegisterPacketHandler(t types.PacketType, handler networ
 	h.handlers[t] = handler
 }
 
+// OpenStreamPacket open stream to a remote node, request is passed to stream handler of the remote node.
+func (h *hostTransport) OpenStreamPacket(ctx context.Context, request network.Request, receiver *host.Host) (network.Stream, error) {
+	inslogger.FromContext(ctx).Debugf("Open %s stream to host %s", request.GetType().String(), receiver.String())
+	return h.transport.OpenStream(ctx, h.buildRequest(ctx, request, receiver))
+}
+
+// RegisterStreamHandler register a handler function to process incoming streams of a specific type.
+func (h *hostTransport) RegisterStreamHandler(t types.PacketType, handler network.StreamHandler) {
+	_, exists := h.streamHandlers[t]
+	if exists {
+		panic(fmt.Sprintf("multiple stream handlers for packet type %s are not supported!", t.String()))
+	}
+	h.streamHandlers[t] = handler
+}
+
 // BuildResponse create response to an incoming request with Data set to responseData.
 func (h *hostTransport) BuildResponse(ctx context.Context, request network.Request, responseData interface{}) network.Response {
 	sender := request.(*packetWrapper).Sender
@@ -143,7 +174,16 @@ func (h *hostTransport) BuildResponse(ctx context.Context, request network.Reque
 }
 
 func NewInternalTransport(conf configuration.Configuration, nodeRef string) (network.InternalTransport, error) {
-	tp, err := transport.NewTransport(conf.Host.Transport, relay.NewProxy())
+	return NewSecureInternalTransport(conf, nodeRef, nil)
+}
+
+// NewSecureInternalTransport creates internal transport, TLS protocol authenticates peers with security.
+func NewSecureInternalTransport(
+	conf configuration.Configuration,
+	nodeRef string,
+	security *transport.Security,
+) (network.InternalTransport, error) {
+	tp, err := transport.NewSecureTransport(conf.Host.Transport, relay.NewProxy(), security)
 	if err != nil {
 		return nil, errors.Wrap(err, "error creating transport")
 	}
@@ -151,10 +191,14 @@ func NewInternalTransport(conf configuration.Configuration, nodeRef string) (net
 	if err != nil {
 		return nil, errors.Wrap(err, "error getting origin")
 	}
-	result := &hostTransport{handlers: make(map[types.PacketType]network.RequestHandler)}
+	result := &hostTransport{
+		handlers:       make(map[types.PacketType]network.RequestHandler),
+		streamHandlers: make(map[types.PacketType]network.StreamHandler),
+	}
 	result.sequenceGenerator = sequence.NewGeneratorImpl()
 	result.transport = tp
 	result.origin = origin
 	result.messageProcessor = result.processMessage
+	result.streamProcessor = result.processStream
 	return result, nil
 }
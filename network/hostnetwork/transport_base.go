@@ -28,6 +28,7 @@ import (
 	"github.com/insolar/insolar/network/transport"
 	"github.com/insolar/insolar/network/transport/host"
 	"github.com/insolar/insolar/network/transport/packet"
+	"github.com/insolar/insolar/network/utils"
 	"github.com/pkg/errors"
 )
 
@@ -36,6 +37,7 @@ type transportBase struct {
 	transport         transport.Transport
 	origin            *host.Host
 	messageProcessor  func(msg *packet.Packet)
+	streamProcessor   func(stream transport.Stream)
 	sequenceGenerator sequence.Generator
 }
 
@@ -62,6 +64,8 @@ func (h *transportBase) listen(ctx context.Context) {
 				log.Warnf("Received error response: %s", msg.Error.Error())
 			}
 			go h.messageProcessor(msg)
+		case stream := <-h.transport.Streams():
+			go h.processStream(stream)
 		case <-h.transport.Stopped():
 			if atomic.CompareAndSwapUint32(&h.started, 1, 0) {
 				h.transport.Close()
@@ -71,6 +75,15 @@ func (h *transportBase) listen(ctx context.Context) {
 	}
 }
 
+func (h *transportBase) processStream(stream transport.Stream) {
+	if h.streamProcessor == nil {
+		log.Warnf("Streams are not supported, close %s stream", stream.Packet().Type)
+		utils.CloseVerbose(stream)
+		return
+	}
+	h.streamProcessor(stream)
+}
+
 // Disconnect stop listening to network requests.
 func (h *transportBase) Stop() {
 	if atomic.CompareAndSwapUint32(&h.started, 1, 0) {
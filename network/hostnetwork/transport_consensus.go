@@ -76,13 +76,28 @@ func (tc *transportConsensus) processMessage(msg *packet.Packet) {
 	handler((*packetWrapper)(msg))
 }
 
+// NewConsensusNetwork creates consensus network over UDP, consensus packets are queued with Consensus class of flow.
 func NewConsensusNetwork(address, nodeID string, shortID core.ShortNodeID,
-	resolver network.RoutingTable) (network.ConsensusNetwork, error) {
+	resolver network.RoutingTable, flow configuration.FlowControl) (network.ConsensusNetwork, error) {
+
+	return newConsensusNetwork("PURE_UDP", address, nodeID, shortID, resolver, flow)
+}
+
+// NewSimulatedConsensusNetwork creates consensus network over transport.DefaultSimulatedNetwork.
+func NewSimulatedConsensusNetwork(address, nodeID string, shortID core.ShortNodeID,
+	resolver network.RoutingTable, flow configuration.FlowControl) (network.ConsensusNetwork, error) {
+
+	return newConsensusNetwork(transport.SimulatedProtocol, address, nodeID, shortID, resolver, flow)
+}
+
+func newConsensusNetwork(protocol, address, nodeID string, shortID core.ShortNodeID,
+	resolver network.RoutingTable, flow configuration.FlowControl) (network.ConsensusNetwork, error) {
 
 	conf := configuration.Transport{}
 	conf.Address = address
-	conf.Protocol = "PURE_UDP"
+	conf.Protocol = protocol
 	conf.BehindNAT = false
+	conf.FlowControl = flow
 
 	tp, err := transport.NewTransport(conf, relay.NewProxy())
 	if err != nil {
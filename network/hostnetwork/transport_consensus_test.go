@@ -22,6 +22,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/insolar/insolar/configuration"
 	"github.com/insolar/insolar/consensus/packets"
 	"github.com/insolar/insolar/core"
 	"github.com/insolar/insolar/log"
@@ -35,11 +36,11 @@ import (
 func createTwoConsensusNetworks(id1, id2 core.ShortNodeID) (t1, t2 network.ConsensusNetwork, err error) {
 	m := newMockResolver()
 
-	cn1, err := NewConsensusNetwork("127.0.0.1:0", ID1+DOMAIN, id1, m)
+	cn1, err := NewConsensusNetwork("127.0.0.1:0", ID1+DOMAIN, id1, m, configuration.NewFlowControl())
 	if err != nil {
 		return nil, nil, err
 	}
-	cn2, err := NewConsensusNetwork("127.0.0.1:0", ID2+DOMAIN, id2, m)
+	cn2, err := NewConsensusNetwork("127.0.0.1:0", ID2+DOMAIN, id2, m, configuration.NewFlowControl())
 	if err != nil {
 		return nil, nil, err
 	}
@@ -99,7 +100,7 @@ func TestTransportConsensus_SendRequest(t *testing.T) {
 func TestTransportConsensus_RegisterPacketHandler(t *testing.T) {
 	m := newMockResolver()
 
-	cn, err := NewConsensusNetwork("127.0.0.1:0", ID1+DOMAIN, 0, m)
+	cn, err := NewConsensusNetwork("127.0.0.1:0", ID1+DOMAIN, 0, m, configuration.FlowControl{})
 	require.NoError(t, err)
 	defer cn.Stop()
 	handler := func(request network.Request) {
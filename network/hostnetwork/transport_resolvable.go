@@ -69,6 +69,24 @@ func (tr *TransportResolvable) RegisterRequestHandler(t types.PacketType, handle
 	tr.internalTransport.RegisterPacketHandler(t, f)
 }
 
+// OpenStream open stream to a remote node, request is passed to stream handler of the remote node.
+func (tr *TransportResolvable) OpenStream(ctx context.Context, request network.Request, receiver core.RecordRef) (network.Stream, error) {
+	h, err := tr.resolver.Resolve(receiver)
+	if err != nil {
+		return nil, errors.Wrap(err, "error resolving NodeID -> Address")
+	}
+	return tr.internalTransport.OpenStreamPacket(ctx, request, h)
+}
+
+// RegisterStreamHandler register a handler function to process incoming streams of a specific type.
+func (tr *TransportResolvable) RegisterStreamHandler(t types.PacketType, handler network.StreamHandler) {
+	f := func(ctx context.Context, request network.Request, stream network.Stream) {
+		tr.resolver.AddToKnownHosts(request.GetSenderHost())
+		handler(ctx, request, stream)
+	}
+	tr.internalTransport.RegisterStreamHandler(t, f)
+}
+
 // NewRequestBuilder create packet Builder for an outgoing request with sender set to current node.
 func (tr *TransportResolvable) NewRequestBuilder() network.RequestBuilder {
 	return tr.internalTransport.NewRequestBuilder()
@@ -17,8 +17,9 @@
 package hostnetwork
 
 import (
+	"bytes"
 	"context"
-	"encoding/gob"
+	"io/ioutil"
 	"sync"
 	"testing"
 	"time"
@@ -28,6 +29,7 @@ import (
 	"github.com/insolar/insolar/log"
 	"github.com/insolar/insolar/network"
 	"github.com/insolar/insolar/network/transport/host"
+	"github.com/insolar/insolar/network/transport/packet"
 	"github.com/insolar/insolar/network/transport/packet/types"
 	"github.com/insolar/insolar/network/utils"
 	"github.com/pkg/errors"
@@ -281,7 +283,7 @@ func TestHostTransport_SendRequestPacket3(t *testing.T) {
 	type Data struct {
 		Number int
 	}
-	gob.Register(&Data{})
+	packet.RegisterPayload(packet.PayloadType(1100), &Data{})
 
 	handler := func(ctx context.Context, r network.Request) (network.Response, error) {
 		log.Info("handler triggered")
@@ -422,3 +424,86 @@ func TestHostTransport_RegisterPacketHandler(t *testing.T) {
 	require.NotPanics(t, f, "first request handler register should not panic")
 	require.Panics(t, f, "second request handler register should panic because it is already registered")
 }
+
+func TestHostTransport_OpenStream(t *testing.T) {
+	t1, t2, err := createTwoHostNetworks(ID1+DOMAIN, ID2+DOMAIN)
+	require.NoError(t, err)
+	ctx := context.Background()
+	ctx2 := context.Background()
+
+	senders := make(chan core.RecordRef, 1)
+	handler := func(ctx context.Context, r network.Request, stream network.Stream) {
+		defer stream.Close()
+		senders <- r.GetSender()
+		data, err := ioutil.ReadAll(stream)
+		if err != nil {
+			log.Error(err)
+			return
+		}
+		_, err = stream.Write(append(data, data...))
+		if err != nil {
+			log.Error(err)
+		}
+	}
+	t2.RegisterStreamHandler(types.RPC, handler)
+
+	t2.Start(ctx)
+	t1.Start(ctx2)
+	defer func() {
+		t1.Stop()
+		t2.Stop()
+	}()
+
+	request := t1.NewRequestBuilder().Type(types.RPC).Build()
+	stream, err := t1.OpenStream(ctx, request, t2.GetNodeID())
+	require.NoError(t, err)
+	defer stream.Close()
+	require.NoError(t, stream.SetDeadline(time.Now().Add(time.Second)))
+
+	data := bytes.Repeat([]byte("stream data"), 100000)
+	_, err = stream.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, stream.CloseWrite())
+
+	reply, err := ioutil.ReadAll(stream)
+	require.NoError(t, err)
+	require.Equal(t, append(data, data...), reply)
+	require.Equal(t, t1.GetNodeID(), <-senders)
+}
+
+func TestHostTransport_OpenStreamWithoutHandler(t *testing.T) {
+	t1, t2, err := createTwoHostNetworks(ID1+DOMAIN, ID2+DOMAIN)
+	require.NoError(t, err)
+	ctx := context.Background()
+	ctx2 := context.Background()
+
+	t2.Start(ctx)
+	t1.Start(ctx2)
+	defer func() {
+		t1.Stop()
+		t2.Stop()
+	}()
+
+	request := t1.NewRequestBuilder().Type(types.RPC).Build()
+	stream, err := t1.OpenStream(ctx, request, t2.GetNodeID())
+	require.NoError(t, err)
+	defer stream.Close()
+	require.NoError(t, stream.SetDeadline(time.Now().Add(time.Second)))
+
+	_, err = ioutil.ReadAll(stream)
+	require.Error(t, err)
+}
+
+func TestHostTransport_RegisterStreamHandler(t *testing.T) {
+	m := newMockResolver()
+
+	i1, err := NewInternalTransport(mockConfiguration("127.0.0.1:0"), ID1+DOMAIN)
+	require.NoError(t, err)
+	tr1 := NewHostTransport(i1, m)
+	handler := func(ctx context.Context, request network.Request, stream network.Stream) {}
+	f := func() {
+		tr1.RegisterStreamHandler(types.RPC, handler)
+	}
+	require.NotPanics(t, f, "first stream handler register should not panic")
+	require.Panics(t, f, "second stream handler register should panic because it is already registered")
+}
@@ -18,6 +18,7 @@ package network
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"github.com/insolar/insolar/component"
@@ -34,6 +35,8 @@ type Controller interface {
 	SendMessage(nodeID core.RecordRef, name string, msg core.Parcel) ([]byte, error)
 	// RemoteProcedureRegister register remote procedure that will be executed when message is received.
 	RemoteProcedureRegister(name string, method core.RemoteProcedure)
+	// RemoteStreamProcedureRegister register remote procedure that will be executed when message is received over stream.
+	RemoteStreamProcedureRegister(name string, method core.RemoteStreamProcedure)
 	// SendCascadeMessage sends a message from MessageBus to a cascade of nodes.
 	SendCascadeMessage(data core.Cascade, method string, msg core.Parcel) error
 	// Bootstrap init complex bootstrap process. Blocks until bootstrap is complete.
@@ -50,6 +53,19 @@ type Controller interface {
 // RequestHandler handler function to process incoming requests from network.
 type RequestHandler func(context.Context, Request) (Response, error)
 
+// Stream is a bidirectional byte stream to a remote node. Streams are multiplexed with requests, so large transfers
+// don't block other requests to the node. Write blocks while remote side doesn't read written data.
+type Stream interface {
+	io.ReadWriteCloser
+	// CloseWrite closes writing side of the stream, remote side reads io.EOF after all written data.
+	CloseWrite() error
+	// SetDeadline sets deadline for Read and Write calls.
+	SetDeadline(t time.Time) error
+}
+
+// StreamHandler handler function to process incoming streams from network, handler should close the stream.
+type StreamHandler func(context.Context, Request, Stream)
+
 // HostNetwork simple interface to send network requests and process network responses.
 //go:generate minimock -i github.com/insolar/insolar/network.HostNetwork -o ../testutils/network -s _mock.go
 type HostNetwork interface {
@@ -66,6 +82,10 @@ type HostNetwork interface {
 	SendRequest(ctx context.Context, request Request, receiver core.RecordRef) (Future, error)
 	// RegisterRequestHandler register a handler function to process incoming requests of a specific type.
 	RegisterRequestHandler(t types.PacketType, handler RequestHandler)
+	// OpenStream open stream to a remote node, request is passed to stream handler of the remote node.
+	OpenStream(ctx context.Context, request Request, receiver core.RecordRef) (Stream, error)
+	// RegisterStreamHandler register a handler function to process incoming streams of a specific type.
+	RegisterStreamHandler(t types.PacketType, handler StreamHandler)
 	// NewRequestBuilder create packet builder for an outgoing request with sender set to current node.
 	NewRequestBuilder() RequestBuilder
 	// BuildResponse create response to an incoming request with Data set to responseData.
@@ -233,6 +253,10 @@ type InternalTransport interface {
 	SendRequestPacket(ctx context.Context, request Request, receiver *host.Host) (Future, error)
 	// RegisterPacketHandler register a handler function to process incoming requests of a specific type.
 	RegisterPacketHandler(t types.PacketType, handler RequestHandler)
+	// OpenStreamPacket open stream to a remote node, request is passed to stream handler of the remote node.
+	OpenStreamPacket(ctx context.Context, request Request, receiver *host.Host) (Stream, error)
+	// RegisterStreamHandler register a handler function to process incoming streams of a specific type.
+	RegisterStreamHandler(t types.PacketType, handler StreamHandler)
 	// NewRequestBuilder create packet builder for an outgoing request with sender set to current node.
 	NewRequestBuilder() RequestBuilder
 	// BuildResponse create response to an incoming request with Data set to responseData.
@@ -17,9 +17,12 @@
 package servicenetwork
 
 import (
+	"bytes"
 	"context"
+	"crypto"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/insolar/insolar/component"
 	"github.com/insolar/insolar/configuration"
@@ -33,6 +36,8 @@ import (
 	"github.com/insolar/insolar/network/hostnetwork"
 	"github.com/insolar/insolar/network/merkle"
 	"github.com/insolar/insolar/network/routing"
+	"github.com/insolar/insolar/network/transport"
+	"github.com/insolar/insolar/platformpolicy"
 	"github.com/pkg/errors"
 )
 
@@ -61,6 +66,8 @@ type ServiceNetwork struct {
 	// fakePulsar *fakepulsar.FakePulsar
 	isGenesis bool
 	skip      int
+	// joining nodes allowed to connect over TLS transport
+	allowedPeers map[core.RecordRef]struct{}
 }
 
 // NewServiceNetwork returns a new ServiceNetwork.
@@ -84,6 +91,11 @@ func (n *ServiceNetwork) RemoteProcedureRegister(name string, method core.Remote
 	n.Controller.RemoteProcedureRegister(name, method)
 }
 
+// RemoteStreamProcedureRegister registers procedure for remote call over stream on this host.
+func (n *ServiceNetwork) RemoteStreamProcedureRegister(name string, method core.RemoteStreamProcedure) {
+	n.Controller.RemoteStreamProcedureRegister(name, method)
+}
+
 // incrementPort increments port number if it not equals 0
 func incrementPort(address string) (string, error) {
 	parts := strings.Split(address, ":")
@@ -103,10 +115,77 @@ func incrementPort(address string) (string, error) {
 	return strings.Join(parts, ":"), nil
 }
 
+// newTransportSecurity creates peer authentication for TLS host transport, nil for other protocols.
+func (n *ServiceNetwork) newTransportSecurity() (*transport.Security, error) {
+	if n.cfg.Host.Transport.Protocol != "TLS" {
+		return nil, nil
+	}
+	n.allowedPeers = make(map[core.RecordRef]struct{}, len(n.cfg.Host.BootstrapAllowList))
+	for _, s := range n.cfg.Host.BootstrapAllowList {
+		ref, err := core.NewRefFromBase58(s)
+		if err != nil {
+			return nil, errors.Wrapf(err, "[ newTransportSecurity ] invalid bootstrap allow list reference %s", s)
+		}
+		n.allowedPeers[*ref] = struct{}{}
+	}
+	return transport.NewSecurity(
+		*n.CertificateManager.GetCertificate().GetNodeRef(),
+		n.CryptographyService,
+		platformpolicy.NewKeyProcessor(),
+		n.authorizePeer,
+		time.Duration(n.cfg.Host.HandshakeSessionTTL)*time.Millisecond,
+	)
+}
+
+// authorizePeer checks that peer key matches the key known for its reference from active nodes or discovery
+// nodes of the certificate. Other nodes are joiners and are accepted only from bootstrap allow list,
+// their certificates are validated on bootstrap.
+func (n *ServiceNetwork) authorizePeer(ref core.RecordRef, key crypto.PublicKey) error {
+	var known crypto.PublicKey
+	if node := n.NodeKeeper.GetActiveNode(ref); node != nil {
+		known = node.PublicKey()
+	} else {
+		for _, discovery := range n.CertificateManager.GetCertificate().GetDiscoveryNodes() {
+			if *discovery.GetNodeRef() == ref {
+				known = discovery.GetPublicKey()
+				break
+			}
+		}
+	}
+	if known == nil {
+		if _, ok := n.allowedPeers[ref]; ok {
+			return nil
+		}
+		return errors.Errorf("[ authorizePeer ] node %s is unknown", ref)
+	}
+
+	keyProcessor := platformpolicy.NewKeyProcessor()
+	expected, err := keyProcessor.ExportPublicKeyBinary(known)
+	if err != nil {
+		return errors.Wrap(err, "[ authorizePeer ] failed to export known key")
+	}
+	actual, err := keyProcessor.ExportPublicKeyBinary(key)
+	if err != nil {
+		return errors.Wrap(err, "[ authorizePeer ] failed to export peer key")
+	}
+	if !bytes.Equal(expected, actual) {
+		return errors.Errorf("[ authorizePeer ] key of node %s does not match", ref)
+	}
+	return nil
+}
+
 // Start implements component.Initer
 func (n *ServiceNetwork) Init(ctx context.Context) error {
 	n.routingTable = &routing.Table{}
-	internalTransport, err := hostnetwork.NewInternalTransport(n.cfg, n.CertificateManager.GetCertificate().GetNodeRef().String())
+	security, err := n.newTransportSecurity()
+	if err != nil {
+		return errors.Wrap(err, "Failed to create transport security")
+	}
+	internalTransport, err := hostnetwork.NewSecureInternalTransport(
+		n.cfg,
+		n.CertificateManager.GetCertificate().GetNodeRef().String(),
+		security,
+	)
 	if err != nil {
 		return errors.Wrap(err, "Failed to create internal transport")
 	}
@@ -117,11 +196,16 @@ func (n *ServiceNetwork) Init(ctx context.Context) error {
 		return errors.Wrap(err, "failed to increment port.")
 	}
 
-	consensusNetwork, err := hostnetwork.NewConsensusNetwork(
+	newConsensusNetwork := hostnetwork.NewConsensusNetwork
+	if n.cfg.Host.Transport.Protocol == transport.SimulatedProtocol {
+		newConsensusNetwork = hostnetwork.NewSimulatedConsensusNetwork
+	}
+	consensusNetwork, err := newConsensusNetwork(
 		n.cfg.Host.Transport.Address,
 		n.CertificateManager.GetCertificate().GetNodeRef().String(),
 		n.NodeKeeper.GetOrigin().ShortID(),
 		n.routingTable,
+		n.cfg.Host.Transport.FlowControl,
 	)
 	if err != nil {
 		return errors.Wrap(err, "Failed to create consensus network.")
@@ -0,0 +1,121 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package servicenetwork
+
+import (
+	"context"
+	"testing"
+
+	"github.com/insolar/insolar/certificate"
+	"github.com/insolar/insolar/component"
+	"github.com/insolar/insolar/configuration"
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/cryptography"
+	"github.com/insolar/insolar/instrumentation/inslogger"
+	"github.com/insolar/insolar/network/nodenetwork"
+	"github.com/insolar/insolar/network/transport"
+	"github.com/insolar/insolar/platformpolicy"
+	"github.com/insolar/insolar/testutils"
+	"github.com/stretchr/testify/require"
+)
+
+type simulatedNode struct {
+	cm             *component.Manager
+	serviceNetwork *ServiceNetwork
+	ref            core.RecordRef
+	discovery      certificate.BootstrapNode
+}
+
+// newSimulatedNode creates service network over transport.DefaultSimulatedNetwork. Node is discovery one
+// if discovery list is empty.
+func newSimulatedNode(t *testing.T, address string, discovery []certificate.BootstrapNode) *simulatedNode {
+	ref := testutils.RandomRef()
+	keyProcessor := platformpolicy.NewKeyProcessor()
+	key, err := keyProcessor.GeneratePrivateKey()
+	require.NoError(t, err)
+	cs := cryptography.NewKeyBoundCryptographyService(key)
+	publicKey, err := cs.GetPublicKey()
+	require.NoError(t, err)
+	publicKeyPEM, err := keyProcessor.ExportPublicKeyPEM(publicKey)
+	require.NoError(t, err)
+
+	self := *certificate.NewBootstrapNode(publicKey, string(publicKeyPEM), address, ref.String())
+	if len(discovery) == 0 {
+		discovery = []certificate.BootstrapNode{self}
+	}
+	certManager := initCertificate(t, discovery, publicKey, ref)
+
+	cfg := configuration.NewConfiguration()
+	cfg.Host.Transport.Protocol = transport.SimulatedProtocol
+	cfg.Host.Transport.Address = address
+
+	origin := nodenetwork.NewNode(ref, core.StaticRoleVirtual, publicKey, address, "")
+	keeper := nodenetwork.NewNodeKeeper(origin)
+
+	netCoordinator := testutils.NewNetworkCoordinatorMock(t)
+	netCoordinator.ValidateCertMock.Return(true, nil)
+
+	cm := &component.Manager{}
+	cm.Register(
+		keeper,
+		certManager,
+		cs,
+		platformpolicy.NewPlatformCryptographyScheme(),
+		netCoordinator,
+		testutils.NewPulseManagerMock(t),
+		testutils.NewPulseStorageMock(t),
+		testutils.NewNetworkSwitcherMock(t),
+		testutils.NewArtifactManagerMock(t),
+	)
+	serviceNetwork, err := NewServiceNetwork(cfg, platformpolicy.NewPlatformCryptographyScheme(), cm, false)
+	require.NoError(t, err)
+	cm.Inject(serviceNetwork)
+
+	return &simulatedNode{cm: cm, serviceNetwork: serviceNetwork, ref: ref, discovery: self}
+}
+
+func TestServiceNetwork_SimulatedPartitionOnBootstrap(t *testing.T) {
+	ctx := inslogger.TestContext(t)
+	simulated := transport.DefaultSimulatedNetwork()
+	simulated.Reset(1)
+	defer simulated.Reset(1)
+
+	discovery := newSimulatedNode(t, "127.0.0.1:20100", nil)
+	require.NoError(t, discovery.cm.Init(ctx))
+	require.NoError(t, discovery.cm.Start(ctx))
+	defer discovery.cm.Stop(context.Background())
+
+	joiner := newSimulatedNode(t, "127.0.0.1:20102", []certificate.BootstrapNode{discovery.discovery})
+	require.NoError(t, joiner.cm.Init(ctx))
+	defer joiner.cm.Stop(context.Background())
+
+	// Joiner can't reach the discovery node, so it fails to bootstrap.
+	rules := simulated.Partition([]core.RecordRef{joiner.ref}, []core.RecordRef{discovery.ref})
+	err := joiner.cm.Start(ctx)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Failed to bootstrap")
+	require.NotZero(t, simulated.Stats().Dropped)
+
+	// Discovery node is not affected by failed attempt and accepts joiners when partition is healed.
+	for _, id := range rules {
+		simulated.RemoveRule(id)
+	}
+	next := newSimulatedNode(t, "127.0.0.1:20104", []certificate.BootstrapNode{discovery.discovery})
+	require.NoError(t, next.cm.Init(ctx))
+	defer next.cm.Stop(context.Background())
+	require.NoError(t, next.cm.Start(ctx))
+}
@@ -23,6 +23,8 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/insolar/insolar/configuration"
+	"github.com/insolar/insolar/core"
 	"github.com/insolar/insolar/instrumentation/inslogger"
 	"github.com/insolar/insolar/metrics"
 	"github.com/insolar/insolar/network"
@@ -51,6 +53,7 @@ type baseTransport struct {
 	serializer    transportSerializer
 	proxy         relay.Proxy
 	packetHandler packetHandler
+	streams       chan Stream
 
 	disconnectStarted  chan bool
 	disconnectFinished chan bool
@@ -59,6 +62,9 @@ type baseTransport struct {
 
 	publicAddress string
 	sendFunc      func(recvAddress string, data []byte) error
+	flow          *flowController
+	// expectPeer is called with receiver of packet sent directly to its address, it is set by authenticated transports.
+	expectPeer func(address string, ref core.RecordRef)
 }
 
 func newBaseTransport(proxy relay.Proxy, publicAddress string) baseTransport {
@@ -66,6 +72,7 @@ func newBaseTransport(proxy relay.Proxy, publicAddress string) baseTransport {
 	return baseTransport{
 		futureManager: futureManager,
 		packetHandler: newPacketHandler(futureManager),
+		streams:       make(chan Stream),
 		proxy:         proxy,
 		serializer:    &baseSerializer{},
 
@@ -118,7 +125,15 @@ func (t *baseTransport) Stopped() <-chan bool {
 	return t.disconnectStarted
 }
 
+// enableFlowControl makes transport send packets through per-peer queues, must be called after sendFunc is set.
+func (t *baseTransport) enableFlowControl(cfg configuration.FlowControl) {
+	t.flow = newFlowController(cfg, t.sendFunc)
+}
+
 func (t *baseTransport) prepareDisconnect() {
+	if t.flow != nil {
+		t.flow.Stop()
+	}
 	t.disconnectStarted <- true
 	close(t.disconnectStarted)
 }
@@ -139,6 +154,9 @@ func (t *baseTransport) SendPacket(ctx context.Context, p *packet.Packet) error
 	}
 	if len(recvAddress) == 0 {
 		recvAddress = p.Receiver.Address.String()
+		if t.expectPeer != nil && !p.Receiver.NodeID.IsEmpty() {
+			t.expectPeer(recvAddress, p.Receiver.NodeID)
+		}
 	}
 
 	data, err := t.serializer.SerializePacket(p)
@@ -147,5 +165,8 @@ func (t *baseTransport) SendPacket(ctx context.Context, p *packet.Packet) error
 	}
 
 	inslogger.FromContext(ctx).Debugf("Send %s packet to %s with RequestID = %d", p.Type, recvAddress, p.RequestID)
+	if t.flow != nil {
+		return t.flow.Send(ctx, recvAddress, packetPriority(ctx, p), data)
+	}
 	return t.sendFunc(recvAddress, data)
 }
@@ -0,0 +1,384 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package transport
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/insolar/insolar/configuration"
+	"github.com/insolar/insolar/metrics"
+	"github.com/insolar/insolar/network/transport/packet"
+	"github.com/insolar/insolar/network/transport/packet/types"
+)
+
+// Priority is a class of outgoing packets. Queued packets of a higher class are sent to the peer first.
+type Priority int
+
+const (
+	// PriorityConsensus is for consensus phases packets.
+	PriorityConsensus Priority = iota
+	// PriorityPulse is for pulses and network control packets (ping, bootstrap, authorization).
+	PriorityPulse
+	// PriorityParcel is for messagebus parcels.
+	PriorityParcel
+	// PriorityHeavy is for heavy replication parcels.
+	PriorityHeavy
+
+	priorityCount = int(PriorityHeavy) + 1
+)
+
+var priorityNames = [priorityCount]string{"consensus", "pulse", "parcel", "heavy"}
+
+func (p Priority) String() string {
+	if p < 0 || int(p) >= priorityCount {
+		return "unknown"
+	}
+	return priorityNames[p]
+}
+
+type priorityKey struct{}
+
+// WithPriority returns context, packets sent with it are queued with priority p.
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, p)
+}
+
+func packetPriority(ctx context.Context, p *packet.Packet) Priority {
+	if priority, ok := ctx.Value(priorityKey{}).(Priority); ok && priority >= 0 && int(priority) < priorityCount {
+		return priority
+	}
+	switch p.Type {
+	case types.Phase1, types.Phase2, types.Phase3:
+		return PriorityConsensus
+	case types.RPC, types.Cascade:
+		return PriorityParcel
+	default:
+		return PriorityPulse
+	}
+}
+
+var (
+	// ErrQueueFull is returned when packet is dropped because send queue of the peer is full.
+	ErrQueueFull = errors.New("peer send queue is full")
+
+	errFlowStopped = errors.New("transport is stopped")
+)
+
+// peerIdleTimeout is a default time after which idle peer is evicted.
+const peerIdleTimeout = time.Minute
+
+// queuedPacket is data queued to be sent by peer worker. Data of streams is written by stream owner,
+// so its queued packet has no data and only reserves size bytes of bandwidth.
+type queuedPacket struct {
+	data   []byte
+	size   int
+	result chan error
+}
+
+// flowController sends packets through per-peer queues. Each peer has a worker which sends queued packets
+// one by one in order of priority and within rate and bandwidth limits. Sender waits until its packet is sent,
+// so send errors are reported as without flow control.
+type flowController struct {
+	cfg  configuration.FlowControl
+	send func(address string, data []byte) error
+	// idle is a time after which worker of the peer without packets is stopped.
+	idle time.Duration
+
+	mutex sync.Mutex
+	peers map[string]*peerQueue
+	stop  chan struct{}
+}
+
+func newFlowController(cfg configuration.FlowControl, send func(address string, data []byte) error) *flowController {
+	return &flowController{
+		cfg:   cfg,
+		send:  send,
+		idle:  peerIdleTimeout,
+		peers: make(map[string]*peerQueue),
+		stop:  make(chan struct{}),
+	}
+}
+
+// Send queues data for address and waits until it is sent.
+func (f *flowController) Send(ctx context.Context, address string, priority Priority, data []byte) error {
+	return f.enqueue(ctx, address, priority, &queuedPacket{data: data, size: len(data), result: make(chan error, 1)})
+}
+
+// Acquire waits until size bytes could be written to address, data is written by caller itself.
+//
+// It is used by streams, so stream data is sent after queued packets of higher priority and within limits.
+func (f *flowController) Acquire(ctx context.Context, address string, priority Priority, size int) error {
+	return f.enqueue(ctx, address, priority, &queuedPacket{size: size, result: make(chan error, 1)})
+}
+
+func (f *flowController) enqueue(ctx context.Context, address string, priority Priority, item *queuedPacket) error {
+	peer, stop := f.peer(address)
+
+	err := peer.push(ctx, priority, item, time.Duration(f.cfg.BlockTimeout)*time.Millisecond, stop)
+	f.release(peer)
+	if err != nil {
+		metrics.NetworkPacketDroppedTotal.WithLabelValues(priority.String()).Inc()
+		return errors.Wrapf(err, "[ Send ] failed to queue %s packet to %s", priority, address)
+	}
+
+	select {
+	case err = <-item.result:
+		return err
+	case <-ctx.Done():
+		// queued packet is sent anyway, result channel is buffered
+		return ctx.Err()
+	case <-stop:
+		return errFlowStopped
+	}
+}
+
+// Stop stops peer workers and drops queued packets.
+func (f *flowController) Stop() {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	close(f.stop)
+	for _, peer := range f.peers {
+		for priority, queue := range peer.queues {
+			metrics.NetworkSendQueueDepth.WithLabelValues(Priority(priority).String()).Sub(float64(len(queue)))
+		}
+	}
+	f.peers = make(map[string]*peerQueue)
+	f.stop = make(chan struct{})
+}
+
+func (f *flowController) peer(address string) (*peerQueue, chan struct{}) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	peer, ok := f.peers[address]
+	if !ok {
+		peer = newPeerQueue(address, f.cfg)
+		f.peers[address] = peer
+		go f.serve(peer, f.stop)
+	}
+	peer.users++
+	return peer, f.stop
+}
+
+// release marks that caller of peer doesn't push to the peer anymore.
+func (f *flowController) release(peer *peerQueue) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	peer.users--
+}
+
+// evict removes idle peer, so workers of peers which are not sent to anymore are stopped.
+// Returns false if packets are being pushed to the peer.
+func (f *flowController) evict(peer *peerQueue) bool {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if peer.users > 0 || peer.queued() > 0 {
+		return false
+	}
+	if f.peers[peer.address] == peer {
+		delete(f.peers, peer.address)
+	}
+	return true
+}
+
+func (f *flowController) serve(peer *peerQueue, stop chan struct{}) {
+	for {
+		item, ok := peer.pop(stop, f.idle)
+		if !ok {
+			return
+		}
+		if item == nil {
+			if f.evict(peer) {
+				return
+			}
+			continue
+		}
+		if !peer.rate.take(1, stop) || !peer.bandwidth.take(item.size, stop) {
+			item.result <- errFlowStopped
+			return
+		}
+		if item.data == nil {
+			item.result <- nil
+			continue
+		}
+		item.result <- f.send(peer.address, item.data)
+	}
+}
+
+type peerQueue struct {
+	address string
+	queues  [priorityCount]chan *queuedPacket
+	block   [priorityCount]bool
+	notify  chan struct{}
+	// users is a number of callers pushing to the peer, guarded by flowController mutex
+	users int
+
+	// used by peer worker only
+	rate      *tokenBucket
+	bandwidth *tokenBucket
+}
+
+func newPeerQueue(address string, cfg configuration.FlowControl) *peerQueue {
+	peer := &peerQueue{
+		address:   address,
+		notify:    make(chan struct{}, 1),
+		rate:      newTokenBucket(cfg.RateLimit, cfg.RateLimit),
+		bandwidth: newTokenBucket(cfg.Bandwidth, cfg.Burst),
+	}
+	classes := [priorityCount]configuration.FlowClass{cfg.Consensus, cfg.Pulse, cfg.Parcel, cfg.Heavy}
+	for priority, class := range classes {
+		size := class.QueueSize
+		if size < 1 {
+			size = 1
+		}
+		peer.queues[priority] = make(chan *queuedPacket, size)
+		peer.block[priority] = class.Block
+	}
+	return peer
+}
+
+func (q *peerQueue) push(
+	ctx context.Context,
+	priority Priority,
+	item *queuedPacket,
+	timeout time.Duration,
+	stop chan struct{},
+) error {
+	queue := q.queues[priority]
+	select {
+	case queue <- item:
+		q.pushed(priority)
+		return nil
+	default:
+	}
+	if !q.block[priority] {
+		return ErrQueueFull
+	}
+
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+	select {
+	case queue <- item:
+		q.pushed(priority)
+		return nil
+	case <-expired:
+		return ErrQueueFull
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-stop:
+		return errFlowStopped
+	}
+}
+
+func (q *peerQueue) pushed(priority Priority) {
+	metrics.NetworkSendQueueDepth.WithLabelValues(priority.String()).Inc()
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (q *peerQueue) queued() int {
+	queued := 0
+	for _, queue := range q.queues {
+		queued += len(queue)
+	}
+	return queued
+}
+
+// pop returns queued packet of the highest priority, it waits for a packet if queues are empty.
+// Returns nil packet if there is no packets during idle time.
+func (q *peerQueue) pop(stop chan struct{}, idle time.Duration) (*queuedPacket, bool) {
+	timer := time.NewTimer(idle)
+	defer timer.Stop()
+	for {
+		for priority, queue := range q.queues {
+			select {
+			case item := <-queue:
+				metrics.NetworkSendQueueDepth.WithLabelValues(Priority(priority).String()).Dec()
+				return item, true
+			default:
+			}
+		}
+		select {
+		case <-q.notify:
+		case <-timer.C:
+			return nil, true
+		case <-stop:
+			return nil, false
+		}
+	}
+}
+
+// tokenBucket limits rate of some resource, nil bucket is unlimited.
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate int, burst int) *tokenBucket {
+	if rate <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = rate
+	}
+	return &tokenBucket{
+		rate:   float64(rate),
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// take takes n tokens, waiting while previous takes are in debt. Tokens above burst are taken in advance,
+// so the next take waits longer. Returns false if stop is closed while waiting.
+func (b *tokenBucket) take(n int, stop chan struct{}) bool {
+	if b == nil {
+		return true
+	}
+	now := time.Now()
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+
+	if b.tokens < 0 {
+		timer := time.NewTimer(time.Duration(-b.tokens / b.rate * float64(time.Second)))
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-stop:
+			return false
+		}
+		b.tokens = 0
+		b.last = time.Now()
+	}
+	b.tokens -= float64(n)
+	return true
+}
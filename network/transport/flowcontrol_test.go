@@ -0,0 +1,306 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package transport
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/insolar/insolar/configuration"
+	"github.com/insolar/insolar/network/transport/packet"
+	"github.com/insolar/insolar/network/transport/packet/types"
+)
+
+// blockingSender records sent data, it blocks sending until released.
+type blockingSender struct {
+	mutex   sync.Mutex
+	sent    []string
+	started chan struct{}
+	release chan struct{}
+}
+
+func newBlockingSender() *blockingSender {
+	return &blockingSender{started: make(chan struct{}, 100), release: make(chan struct{})}
+}
+
+func (s *blockingSender) send(address string, data []byte) error {
+	s.started <- struct{}{}
+	<-s.release
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.sent = append(s.sent, string(data))
+	return nil
+}
+
+func (s *blockingSender) result() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]string{}, s.sent...)
+}
+
+func sendAsync(f *flowController, priority Priority, data string) chan error {
+	result := make(chan error, 1)
+	go func() {
+		result <- f.Send(context.Background(), "peer", priority, []byte(data))
+	}()
+	return result
+}
+
+func waitQueued(t *testing.T, f *flowController, count int) {
+	peer, _ := f.peer("peer")
+	defer f.release(peer)
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		queued := 0
+		for _, queue := range peer.queues {
+			queued += len(queue)
+		}
+		if queued == count {
+			return
+		}
+	}
+	t.Fatalf("%d packets are not queued", count)
+}
+
+func TestFlowController_Priority(t *testing.T) {
+	sender := newBlockingSender()
+	f := newFlowController(configuration.NewFlowControl(), sender.send)
+	defer f.Stop()
+
+	results := []chan error{sendAsync(f, PriorityParcel, "first")}
+	<-sender.started
+
+	results = append(results, sendAsync(f, PriorityHeavy, "heavy"))
+	waitQueued(t, f, 1)
+	results = append(results, sendAsync(f, PriorityParcel, "parcel"))
+	waitQueued(t, f, 2)
+	results = append(results, sendAsync(f, PriorityConsensus, "consensus"))
+	waitQueued(t, f, 3)
+	results = append(results, sendAsync(f, PriorityPulse, "pulse"))
+	waitQueued(t, f, 4)
+
+	close(sender.release)
+	for _, result := range results {
+		require.NoError(t, <-result)
+	}
+	assert.Equal(t, []string{"first", "consensus", "pulse", "parcel", "heavy"}, sender.result())
+}
+
+func TestFlowController_DropWhenFull(t *testing.T) {
+	cfg := configuration.NewFlowControl()
+	cfg.Consensus = configuration.FlowClass{QueueSize: 1, Block: false}
+	sender := newBlockingSender()
+	f := newFlowController(cfg, sender.send)
+	defer f.Stop()
+
+	first := sendAsync(f, PriorityConsensus, "first")
+	<-sender.started
+	queued := sendAsync(f, PriorityConsensus, "queued")
+	waitQueued(t, f, 1)
+
+	err := f.Send(context.Background(), "peer", PriorityConsensus, []byte("dropped"))
+	require.Error(t, err)
+	assert.Equal(t, ErrQueueFull, errors.Cause(err))
+
+	close(sender.release)
+	require.NoError(t, <-first)
+	require.NoError(t, <-queued)
+	assert.Equal(t, []string{"first", "queued"}, sender.result())
+}
+
+func TestFlowController_BlockWhenFull(t *testing.T) {
+	cfg := configuration.NewFlowControl()
+	cfg.Heavy = configuration.FlowClass{QueueSize: 1, Block: true}
+	cfg.BlockTimeout = 50
+	sender := newBlockingSender()
+	f := newFlowController(cfg, sender.send)
+	defer f.Stop()
+
+	first := sendAsync(f, PriorityHeavy, "first")
+	<-sender.started
+	queued := sendAsync(f, PriorityHeavy, "queued")
+	waitQueued(t, f, 1)
+
+	start := time.Now()
+	err := f.Send(context.Background(), "peer", PriorityHeavy, []byte("timed out"))
+	assert.Equal(t, ErrQueueFull, errors.Cause(err))
+	assert.True(t, time.Since(start) >= 50*time.Millisecond)
+
+	blocked := sendAsync(f, PriorityHeavy, "blocked")
+	close(sender.release)
+	require.NoError(t, <-first)
+	require.NoError(t, <-queued)
+	require.NoError(t, <-blocked)
+	assert.Equal(t, []string{"first", "queued", "blocked"}, sender.result())
+}
+
+func TestFlowController_SendError(t *testing.T) {
+	f := newFlowController(configuration.NewFlowControl(), func(address string, data []byte) error {
+		return errors.New("connection refused")
+	})
+	defer f.Stop()
+
+	err := f.Send(context.Background(), "peer", PriorityParcel, []byte("data"))
+	require.EqualError(t, err, "connection refused")
+}
+
+func TestFlowController_Stop(t *testing.T) {
+	sender := newBlockingSender()
+	f := newFlowController(configuration.NewFlowControl(), sender.send)
+
+	first := sendAsync(f, PriorityParcel, "first")
+	<-sender.started
+	queued := sendAsync(f, PriorityParcel, "queued")
+	waitQueued(t, f, 1)
+
+	f.Stop()
+	assert.Equal(t, errFlowStopped, <-first)
+	assert.Equal(t, errFlowStopped, <-queued)
+	close(sender.release)
+}
+
+func TestFlowController_SendContextDone(t *testing.T) {
+	sender := newBlockingSender()
+	f := newFlowController(configuration.NewFlowControl(), sender.send)
+	defer f.Stop()
+
+	first := sendAsync(f, PriorityParcel, "first")
+	<-sender.started
+
+	ctx, cancel := context.WithCancel(context.Background())
+	canceled := make(chan error, 1)
+	go func() {
+		canceled <- f.Send(ctx, "peer", PriorityParcel, []byte("canceled"))
+	}()
+	waitQueued(t, f, 1)
+	cancel()
+	assert.Equal(t, context.Canceled, <-canceled, "sender doesn't wait for queued packet")
+
+	close(sender.release)
+	require.NoError(t, <-first)
+}
+
+func TestFlowController_EvictIdlePeer(t *testing.T) {
+	f := newFlowController(configuration.NewFlowControl(), func(address string, data []byte) error {
+		return nil
+	})
+	f.idle = 10 * time.Millisecond
+	defer f.Stop()
+
+	require.NoError(t, f.Send(context.Background(), "peer", PriorityParcel, []byte("data")))
+	peers := func() int {
+		f.mutex.Lock()
+		defer f.mutex.Unlock()
+		return len(f.peers)
+	}
+	require.Equal(t, 1, peers())
+	for deadline := time.Now().Add(time.Second); peers() > 0 && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+	}
+	require.Equal(t, 0, peers(), "idle peer is evicted")
+
+	require.NoError(t, f.Send(context.Background(), "peer", PriorityParcel, []byte("data")), "peer is recreated")
+}
+
+func TestFlowController_Bandwidth(t *testing.T) {
+	cfg := configuration.NewFlowControl()
+	cfg.Bandwidth = 1000
+	cfg.Burst = 100
+	f := newFlowController(cfg, func(address string, data []byte) error {
+		return nil
+	})
+	defer f.Stop()
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		require.NoError(t, f.Send(context.Background(), "peer", PriorityParcel, make([]byte, 100)))
+	}
+	// burst covers first packet and the second one is sent in advance
+	assert.True(t, time.Since(start) >= 150*time.Millisecond, "elapsed %s", time.Since(start))
+}
+
+// bufferStream is Stream which writes to buffer.
+type bufferStream struct {
+	Stream
+	buffer bytes.Buffer
+}
+
+func (s *bufferStream) Write(p []byte) (int, error) {
+	return s.buffer.Write(p)
+}
+
+func TestFlowStream_Priority(t *testing.T) {
+	sender := newBlockingSender()
+	f := newFlowController(configuration.NewFlowControl(), sender.send)
+	defer f.Stop()
+
+	first := sendAsync(f, PriorityParcel, "first")
+	<-sender.started
+
+	stream := &bufferStream{}
+	heavy := &flowStream{Stream: stream, ctx: context.Background(), flow: f, address: "peer", priority: PriorityHeavy}
+	written := make(chan error, 1)
+	go func() {
+		_, err := heavy.Write([]byte("heavy"))
+		written <- err
+	}()
+	waitQueued(t, f, 1)
+	parcel := sendAsync(f, PriorityParcel, "parcel")
+	waitQueued(t, f, 2)
+
+	close(sender.release)
+	require.NoError(t, <-first)
+	require.NoError(t, <-parcel)
+	require.NoError(t, <-written)
+	assert.Equal(t, []string{"first", "parcel"}, sender.result(), "stream is written after packets of higher priority")
+	assert.Equal(t, "heavy", stream.buffer.String())
+}
+
+func TestFlowStream_Bandwidth(t *testing.T) {
+	cfg := configuration.NewFlowControl()
+	cfg.Bandwidth = 1000
+	cfg.Burst = 100
+	f := newFlowController(cfg, func(address string, data []byte) error {
+		return nil
+	})
+	defer f.Stop()
+
+	stream := &flowStream{Stream: &bufferStream{}, ctx: context.Background(), flow: f, address: "peer", priority: PriorityHeavy}
+	start := time.Now()
+	require.NoError(t, f.Send(context.Background(), "peer", PriorityParcel, make([]byte, 100)))
+	for i := 0; i < 2; i++ {
+		_, err := stream.Write(make([]byte, 100))
+		require.NoError(t, err)
+	}
+	// stream shares bandwidth with packets
+	assert.True(t, time.Since(start) >= 90*time.Millisecond, "elapsed %s", time.Since(start))
+}
+
+func TestPacketPriority(t *testing.T) {
+	ctx := context.Background()
+	assert.Equal(t, PriorityConsensus, packetPriority(ctx, &packet.Packet{Type: types.Phase2}))
+	assert.Equal(t, PriorityPulse, packetPriority(ctx, &packet.Packet{Type: types.Pulse}))
+	assert.Equal(t, PriorityPulse, packetPriority(ctx, &packet.Packet{Type: types.Ping}))
+	assert.Equal(t, PriorityParcel, packetPriority(ctx, &packet.Packet{Type: types.RPC}))
+	assert.Equal(t, PriorityHeavy, packetPriority(WithPriority(ctx, PriorityHeavy), &packet.Packet{Type: types.RPC}))
+}
@@ -0,0 +1,485 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package transport
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/insolar/insolar/log"
+	"github.com/insolar/insolar/network/transport/packet"
+)
+
+// Streams over TCP are multiplexed into frames of a dedicated connection. Every stream may have at most
+// streamWindow bytes sent and not read by remote side, reader returns credit with window frames.
+const (
+	streamWindow      = 256 * 1024
+	streamFrameSize   = 32 * 1024
+	maxOpenFrameSize  = 16 << 20
+	muxFrameHeaderLen = 9
+)
+
+type frameType byte
+
+const (
+	frameOpen frameType = iota + 1
+	frameData
+	frameWindow
+	frameClose
+	frameReset
+)
+
+var (
+	errStreamReset   = errors.New("stream is reset by remote side")
+	errStreamClosed  = errors.New("stream is closed")
+	errStreamTimeout = errors.New("stream deadline exceeded")
+)
+
+// muxSession multiplexes streams over connection. Streams are opened by dialing side only,
+// accepting side passes them to accept callback.
+type muxSession struct {
+	conn    net.Conn
+	reader  io.Reader
+	accept  func(*muxStream)
+	onClose func()
+
+	writeMutex sync.Mutex
+
+	mutex   sync.Mutex
+	streams map[uint32]*muxStream
+	nextID  uint32
+	err     error
+}
+
+func newMuxSession(conn net.Conn, reader io.Reader, accept func(*muxStream)) *muxSession {
+	return &muxSession{
+		conn:    conn,
+		reader:  reader,
+		accept:  accept,
+		streams: make(map[uint32]*muxStream),
+	}
+}
+
+// open opens new stream, p is passed to remote side.
+func (s *muxSession) open(p *packet.Packet) (*muxStream, error) {
+	data, err := packet.SerializePacket(p)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ open ] Failed to serialize packet")
+	}
+
+	s.mutex.Lock()
+	if s.err != nil {
+		s.mutex.Unlock()
+		return nil, s.err
+	}
+	s.nextID++
+	stream := newMuxStream(s, s.nextID, p)
+	s.streams[stream.id] = stream
+	s.mutex.Unlock()
+
+	if err := s.writeFrame(frameOpen, stream.id, data); err != nil {
+		s.remove(stream.id)
+		return nil, errors.Wrap(err, "[ open ] Failed to open stream")
+	}
+	return stream, nil
+}
+
+// serve reads frames until connection fails or closes.
+func (s *muxSession) serve() {
+	header := make([]byte, muxFrameHeaderLen)
+	for {
+		if _, err := io.ReadFull(s.reader, header); err != nil {
+			s.fail(err)
+			return
+		}
+		kind := frameType(header[0])
+		id := binary.BigEndian.Uint32(header[1:5])
+		length := binary.BigEndian.Uint32(header[5:9])
+		if length > maxOpenFrameSize || (kind != frameOpen && length > streamFrameSize) {
+			s.fail(errors.Errorf("[ serve ] too big frame: %d", length))
+			return
+		}
+		payload := make([]byte, length)
+		if _, err := io.ReadFull(s.reader, payload); err != nil {
+			s.fail(err)
+			return
+		}
+		s.handleFrame(kind, id, payload)
+	}
+}
+
+func (s *muxSession) handleFrame(kind frameType, id uint32, payload []byte) {
+	if kind == frameOpen {
+		s.handleOpen(id, payload)
+		return
+	}
+
+	s.mutex.Lock()
+	stream, ok := s.streams[id]
+	s.mutex.Unlock()
+	if !ok {
+		return
+	}
+
+	switch kind {
+	case frameData:
+		if !stream.received(payload) {
+			log.Warnf("[ handleFrame ] stream %d exceeded window, reset it", id)
+			stream.reset()
+			s.sendFrame(frameReset, id, nil)
+		}
+	case frameWindow:
+		if len(payload) == 4 {
+			stream.addCredit(int(binary.BigEndian.Uint32(payload)))
+		}
+	case frameClose:
+		stream.remoteClose()
+	case frameReset:
+		stream.reset()
+	default:
+		log.Warnf("[ handleFrame ] unknown frame type %d", kind)
+	}
+}
+
+func (s *muxSession) handleOpen(id uint32, payload []byte) {
+	if s.accept == nil {
+		log.Warn("[ handleOpen ] streams could not be opened by accepting side")
+		s.sendFrame(frameReset, id, nil)
+		return
+	}
+	p, err := packet.DeserializePacket(bytes.NewReader(payload))
+	if err != nil {
+		log.Error("[ handleOpen ] Failed to deserialize stream packet: ", err)
+		s.sendFrame(frameReset, id, nil)
+		return
+	}
+
+	s.mutex.Lock()
+	if _, ok := s.streams[id]; ok {
+		s.mutex.Unlock()
+		s.fail(errors.Errorf("[ handleOpen ] stream %d is already open", id))
+		return
+	}
+	stream := newMuxStream(s, id, p)
+	s.streams[id] = stream
+	s.mutex.Unlock()
+
+	go s.accept(stream)
+}
+
+func (s *muxSession) writeFrame(kind frameType, id uint32, payload []byte) error {
+	frame := make([]byte, muxFrameHeaderLen+len(payload))
+	frame[0] = byte(kind)
+	binary.BigEndian.PutUint32(frame[1:5], id)
+	binary.BigEndian.PutUint32(frame[5:9], uint32(len(payload)))
+	copy(frame[muxFrameHeaderLen:], payload)
+
+	s.writeMutex.Lock()
+	defer s.writeMutex.Unlock()
+
+	if _, err := s.conn.Write(frame); err != nil {
+		go s.fail(err)
+		return errors.Wrap(err, "[ writeFrame ] Failed to write frame")
+	}
+	return nil
+}
+
+// sendFrame writes frame which loss is handled by session failure.
+func (s *muxSession) sendFrame(kind frameType, id uint32, payload []byte) {
+	if err := s.writeFrame(kind, id, payload); err != nil {
+		log.Debug("[ sendFrame ] ", err)
+	}
+}
+
+func (s *muxSession) remove(id uint32) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.streams, id)
+}
+
+func (s *muxSession) closed() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.err != nil
+}
+
+// Close closes connection and fails all streams.
+func (s *muxSession) Close() error {
+	s.fail(errors.New("stream session is closed"))
+	return nil
+}
+
+func (s *muxSession) fail(err error) {
+	s.mutex.Lock()
+	if s.err != nil {
+		s.mutex.Unlock()
+		return
+	}
+	s.err = errors.Wrap(err, "stream session failed")
+	streams := s.streams
+	s.streams = make(map[uint32]*muxStream)
+	s.mutex.Unlock()
+
+	if err := s.conn.Close(); err != nil {
+		log.Debug("[ fail ] Failed to close stream connection: ", err)
+	}
+	for _, stream := range streams {
+		stream.fail(s.err)
+	}
+	if s.onClose != nil {
+		s.onClose()
+	}
+}
+
+// muxStream is Stream of muxSession.
+type muxStream struct {
+	session *muxSession
+	id      uint32
+	packet  *packet.Packet
+
+	mutex        sync.Mutex
+	cond         *sync.Cond
+	buffer       bytes.Buffer
+	consumed     int
+	credit       int
+	remoteClosed bool
+	writeClosed  bool
+	closed       bool
+	err          error
+	deadline     time.Time
+	timer        *time.Timer
+}
+
+func newMuxStream(session *muxSession, id uint32, p *packet.Packet) *muxStream {
+	stream := &muxStream{session: session, id: id, packet: p, credit: streamWindow}
+	stream.cond = sync.NewCond(&stream.mutex)
+	return stream
+}
+
+// Packet returns packet the stream was opened with.
+func (s *muxStream) Packet() *packet.Packet {
+	return s.packet
+}
+
+// Read reads data sent by remote side, it returns io.EOF after remote side closed writing.
+func (s *muxStream) Read(p []byte) (int, error) {
+	s.mutex.Lock()
+	for s.buffer.Len() == 0 && !s.closed && !s.remoteClosed && s.err == nil && !s.timedOut() {
+		s.cond.Wait()
+	}
+	switch {
+	case s.closed:
+		s.mutex.Unlock()
+		return 0, errStreamClosed
+	case s.buffer.Len() > 0:
+	case s.remoteClosed:
+		s.mutex.Unlock()
+		return 0, io.EOF
+	case s.err != nil:
+		s.mutex.Unlock()
+		return 0, s.err
+	default:
+		s.mutex.Unlock()
+		return 0, errStreamTimeout
+	}
+
+	n, _ := s.buffer.Read(p)
+	s.consumed += n
+	var update int
+	if s.consumed >= streamWindow/2 && !s.remoteClosed {
+		update = s.consumed
+		s.consumed = 0
+	}
+	s.mutex.Unlock()
+
+	if update > 0 {
+		credit := make([]byte, 4)
+		binary.BigEndian.PutUint32(credit, uint32(update))
+		s.session.sendFrame(frameWindow, s.id, credit)
+	}
+	return n, nil
+}
+
+// Write sends data in frames, it blocks while remote side has no window for the data.
+func (s *muxStream) Write(p []byte) (int, error) {
+	written := 0
+	for written < len(p) {
+		s.mutex.Lock()
+		for s.credit == 0 && !s.closed && !s.writeClosed && s.err == nil && !s.timedOut() {
+			s.cond.Wait()
+		}
+		switch {
+		case s.closed || s.writeClosed:
+			s.mutex.Unlock()
+			return written, errStreamClosed
+		case s.err != nil:
+			s.mutex.Unlock()
+			return written, s.err
+		case s.credit == 0:
+			s.mutex.Unlock()
+			return written, errStreamTimeout
+		}
+		n := len(p) - written
+		if n > s.credit {
+			n = s.credit
+		}
+		if n > streamFrameSize {
+			n = streamFrameSize
+		}
+		s.credit -= n
+		s.mutex.Unlock()
+
+		if err := s.session.writeFrame(frameData, s.id, p[written:written+n]); err != nil {
+			return written, err
+		}
+		written += n
+	}
+	return written, nil
+}
+
+// CloseWrite closes writing side of the stream.
+func (s *muxStream) CloseWrite() error {
+	s.mutex.Lock()
+	if s.writeClosed || s.err != nil {
+		s.mutex.Unlock()
+		return nil
+	}
+	s.writeClosed = true
+	finished := s.remoteClosed
+	s.cond.Broadcast()
+	s.mutex.Unlock()
+
+	err := s.session.writeFrame(frameClose, s.id, nil)
+	if finished {
+		s.session.remove(s.id)
+	}
+	return err
+}
+
+// Close closes the stream, remote side gets error on writing if it hasn't finished yet.
+func (s *muxStream) Close() error {
+	s.mutex.Lock()
+	if s.closed {
+		s.mutex.Unlock()
+		return nil
+	}
+	s.closed = true
+	kind := frameType(0)
+	if s.err == nil {
+		if !s.remoteClosed {
+			kind = frameReset
+		} else if !s.writeClosed {
+			kind = frameClose
+		}
+	}
+	s.writeClosed = true
+	if s.timer != nil {
+		s.timer.Stop()
+	}
+	s.cond.Broadcast()
+	s.mutex.Unlock()
+
+	s.session.remove(s.id)
+	if kind != 0 {
+		return s.session.writeFrame(kind, s.id, nil)
+	}
+	return nil
+}
+
+// SetDeadline sets deadline for Read and Write calls.
+func (s *muxStream) SetDeadline(t time.Time) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.deadline = t
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	if !t.IsZero() {
+		s.timer = time.AfterFunc(time.Until(t), func() {
+			s.mutex.Lock()
+			defer s.mutex.Unlock()
+			s.cond.Broadcast()
+		})
+	}
+	s.cond.Broadcast()
+	return nil
+}
+
+func (s *muxStream) timedOut() bool {
+	return !s.deadline.IsZero() && !time.Now().Before(s.deadline)
+}
+
+// received buffers data frame, it returns false if remote side exceeded window.
+func (s *muxStream) received(data []byte) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closed {
+		return true
+	}
+	if s.buffer.Len()+len(data) > streamWindow {
+		return false
+	}
+	s.buffer.Write(data)
+	s.cond.Broadcast()
+	return true
+}
+
+func (s *muxStream) addCredit(n int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.credit += n
+	s.cond.Broadcast()
+}
+
+func (s *muxStream) remoteClose() {
+	s.mutex.Lock()
+	s.remoteClosed = true
+	finished := s.writeClosed
+	s.cond.Broadcast()
+	s.mutex.Unlock()
+
+	if finished {
+		s.session.remove(s.id)
+	}
+}
+
+func (s *muxStream) reset() {
+	s.fail(errStreamReset)
+	s.session.remove(s.id)
+}
+
+func (s *muxStream) fail(err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.err == nil {
+		s.err = err
+	}
+	s.cond.Broadcast()
+}
@@ -0,0 +1,183 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package transport
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/insolar/insolar/network/transport/host"
+	"github.com/insolar/insolar/network/transport/packet"
+	"github.com/insolar/insolar/network/transport/packet/types"
+)
+
+func newMuxPair(t *testing.T) (*muxSession, <-chan *muxStream) {
+	clientConn, serverConn := net.Pipe()
+	accepted := make(chan *muxStream, 100)
+	server := newMuxSession(serverConn, serverConn, func(stream *muxStream) {
+		accepted <- stream
+	})
+	client := newMuxSession(clientConn, clientConn, nil)
+	go server.serve()
+	go client.serve()
+	return client, accepted
+}
+
+func openTestStream(t *testing.T, session *muxSession, accepted <-chan *muxStream) (*muxStream, *muxStream) {
+	sender, err := host.NewHost("127.0.0.1:31337")
+	require.NoError(t, err)
+	p := packet.NewBuilder(sender).Receiver(sender).Type(types.RPC).Request(&packet.RequestTest{Data: []byte("open")}).Build()
+
+	stream, err := session.open(p)
+	require.NoError(t, err)
+	select {
+	case remote := <-accepted:
+		return stream, remote
+	case <-time.After(time.Second):
+		require.FailNow(t, "stream is not accepted")
+		return nil, nil
+	}
+}
+
+func TestMuxStream_Transfer(t *testing.T) {
+	session, accepted := newMuxPair(t)
+	defer session.Close()
+	stream, remote := openTestStream(t, session, accepted)
+
+	assert.Equal(t, types.RPC, remote.Packet().Type)
+	assert.Equal(t, []byte("open"), remote.Packet().Data.(*packet.RequestTest).Data)
+
+	data, err := generateRandomBytes(4 * streamWindow)
+	require.NoError(t, err)
+	go func() {
+		_, err := stream.Write(data)
+		assert.NoError(t, err)
+		assert.NoError(t, stream.CloseWrite())
+	}()
+
+	received, err := ioutil.ReadAll(remote)
+	require.NoError(t, err)
+	assert.Equal(t, data, received)
+
+	_, err = remote.Write([]byte("reply"))
+	require.NoError(t, err)
+	require.NoError(t, remote.Close())
+
+	reply, err := ioutil.ReadAll(stream)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("reply"), reply)
+	assert.NoError(t, stream.Close())
+}
+
+func TestMuxStream_Backpressure(t *testing.T) {
+	session, accepted := newMuxPair(t)
+	defer session.Close()
+	stream, remote := openTestStream(t, session, accepted)
+
+	require.NoError(t, stream.SetDeadline(time.Now().Add(200*time.Millisecond)))
+	n, err := stream.Write(make([]byte, 2*streamWindow))
+	assert.Equal(t, errStreamTimeout, err)
+	assert.Equal(t, streamWindow, n)
+
+	_, err = io.ReadFull(remote, make([]byte, streamWindow))
+	require.NoError(t, err)
+
+	require.NoError(t, stream.SetDeadline(time.Now().Add(time.Second)))
+	n, err = stream.Write(make([]byte, streamWindow))
+	assert.NoError(t, err)
+	assert.Equal(t, streamWindow, n)
+}
+
+func TestMuxStream_Reset(t *testing.T) {
+	session, accepted := newMuxPair(t)
+	defer session.Close()
+	stream, remote := openTestStream(t, session, accepted)
+
+	require.NoError(t, remote.Close())
+	_, err := remote.Read(make([]byte, 1))
+	assert.Equal(t, errStreamClosed, err)
+
+	require.NoError(t, stream.SetDeadline(time.Now().Add(time.Second)))
+	_, err = stream.Read(make([]byte, 1))
+	assert.Equal(t, errStreamReset, err)
+	_, err = stream.Write([]byte("data"))
+	assert.Equal(t, errStreamReset, err)
+}
+
+func TestMuxStream_Concurrent(t *testing.T) {
+	session, accepted := newMuxPair(t)
+	defer session.Close()
+
+	go func() {
+		for remote := range accepted {
+			go func(remote *muxStream) {
+				defer remote.Close()
+				data, err := ioutil.ReadAll(remote)
+				assert.NoError(t, err)
+				_, err = remote.Write(data)
+				assert.NoError(t, err)
+			}(remote)
+		}
+	}()
+
+	sender, err := host.NewHost("127.0.0.1:31337")
+	require.NoError(t, err)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			stream, err := session.open(packet.NewBuilder(sender).Receiver(sender).Type(types.RPC).Build())
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer stream.Close()
+
+			data := bytes.Repeat([]byte(fmt.Sprintf("stream %d;", i)), 50000)
+			_, err = stream.Write(data)
+			assert.NoError(t, err)
+			assert.NoError(t, stream.CloseWrite())
+			echo, err := ioutil.ReadAll(stream)
+			assert.NoError(t, err)
+			assert.Equal(t, data, echo)
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestMuxSession_Close(t *testing.T) {
+	session, accepted := newMuxPair(t)
+	stream, remote := openTestStream(t, session, accepted)
+
+	require.NoError(t, session.Close())
+	_, err := stream.Read(make([]byte, 1))
+	assert.Error(t, err)
+	_, err = remote.Read(make([]byte, 1))
+	assert.Error(t, err)
+
+	_, err = session.open(stream.Packet())
+	assert.Error(t, err)
+}
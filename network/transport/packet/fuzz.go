@@ -0,0 +1,40 @@
+//go:build gofuzz
+// +build gofuzz
+
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package packet
+
+import (
+	"bytes"
+)
+
+// Fuzz is an entry point for go-fuzz: go-fuzz-build github.com/insolar/insolar/network/transport/packet
+func Fuzz(data []byte) int {
+	msg, err := DeserializePacket(bytes.NewReader(data))
+	if err != nil {
+		return 0
+	}
+	serialized, err := SerializePacket(msg)
+	if err != nil {
+		panic(err)
+	}
+	if _, err := DeserializePacket(bytes.NewReader(serialized)); err != nil {
+		panic(err)
+	}
+	return 1
+}
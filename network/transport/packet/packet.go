@@ -19,8 +19,8 @@ package packet
 import (
 	"bytes"
 	"encoding/binary"
-	"encoding/gob"
 	"io"
+	"reflect"
 
 	"github.com/insolar/insolar/log"
 	"github.com/insolar/insolar/network"
@@ -43,52 +43,125 @@ type Packet struct {
 	IsResponse bool
 }
 
+// Packet wire format:
+//
+//	| length uint32 | version uint8 | fields |
+//
+// length is a big endian size of the rest of the packet. Each field is | number uvarint | size uvarint | value |,
+// fields are encoded like payload structs (see schema.go) and Data is preceded by its explicit PayloadType.
+// Decoder skips unknown fields and leaves missing ones zero, so fields can be added without bumping the
+// version. Version changes only when old nodes can not read new packets.
+//
+// Schema covers packet and its Data only. Parcels sent over RPC (RequestRPC.Data) are opaque bytes encoded by
+// core/message with gob, so their format is still Go-specific and is versioned with message types, not packets.
+const (
+	packetVersion = 1
+	lengthSize    = 4
+
+	// maxPacketSize limits length of packet, longer packets are rejected before they are read.
+	maxPacketSize = 128 << 20
+	// maxPrealloc limits memory allocated for packet before its bytes actually arrive.
+	maxPrealloc = 16 << 20
+)
+
+const (
+	fieldSender uint64 = iota + 1
+	fieldReceiver
+	fieldType
+	fieldRequestID
+	fieldRemoteAddress
+	fieldTraceID
+	fieldIsResponse
+	fieldError
+	fieldPayloadType
+	fieldData
+)
+
 // SerializePacket converts packet to byte slice.
 func SerializePacket(q *Packet) ([]byte, error) {
-	var msgBuffer bytes.Buffer
-	enc := gob.NewEncoder(&msgBuffer)
-	err := enc.Encode(q)
-	if err != nil {
-		return nil, errors.Wrap(err, "Failed to serialize packet")
+	var payloadType PayloadType
+	var payload []byte
+	if q.Data != nil {
+		var err error
+		payloadType, payload, err = marshalPayload(q.Data)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to serialize packet")
+		}
 	}
 
-	length := msgBuffer.Len()
-
-	var lengthBytes [8]byte
-	binary.PutUvarint(lengthBytes[:], uint64(length))
+	result := make([]byte, lengthSize, 256+len(payload))
+	result = append(result, packetVersion)
+
+	var err error
+	appendField := func(number uint64, value interface{}) {
+		if err != nil {
+			return
+		}
+		var encoded []byte
+		encoded, err = encodeValue(nil, reflect.ValueOf(value))
+		if err != nil {
+			err = errors.Wrapf(err, "failed to encode field %d", number)
+			return
+		}
+		if len(encoded) > 0 {
+			result = appendBytes(appendUvarint(result, number), encoded)
+		}
+	}
 
-	var result []byte
-	result = append(result, lengthBytes[:]...)
-	result = append(result, msgBuffer.Bytes()...)
+	appendField(fieldSender, q.Sender)
+	appendField(fieldReceiver, q.Receiver)
+	appendField(fieldType, q.Type)
+	appendField(fieldRequestID, q.RequestID)
+	appendField(fieldRemoteAddress, q.RemoteAddress)
+	appendField(fieldTraceID, q.TraceID)
+	appendField(fieldIsResponse, q.IsResponse)
+	if q.Error != nil {
+		appendField(fieldError, q.Error.Error())
+	}
+	if q.Data != nil {
+		appendField(fieldPayloadType, payloadType)
+		result = appendBytes(appendUvarint(result, fieldData), payload)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to serialize packet")
+	}
 
+	length := len(result) - lengthSize
+	if length > maxPacketSize {
+		return nil, errors.Errorf("[ SerializePacket ] packet is too large: %d bytes, max %d", length, maxPacketSize)
+	}
+	binary.BigEndian.PutUint32(result, uint32(length))
 	return result, nil
 }
 
 // DeserializePacket reads packet from io.Reader.
 func DeserializePacket(conn io.Reader) (*Packet, error) {
-
-	lengthBytes := make([]byte, 8)
-	if _, err := io.ReadFull(conn, lengthBytes); err != nil {
+	var lengthBytes [lengthSize]byte
+	if _, err := io.ReadFull(conn, lengthBytes[:]); err != nil {
 		return nil, err
 	}
-	lengthReader := bytes.NewBuffer(lengthBytes)
-	length, err := binary.ReadUvarint(lengthReader)
-	if err != nil {
-		return nil, io.ErrUnexpectedEOF
-	}
+	length := int64(binary.BigEndian.Uint32(lengthBytes[:]))
 
 	log.Debugf("[ DeserializePacket ] packet length %d", length)
-	buf := make([]byte, length)
-	if _, err := io.ReadFull(conn, buf); err != nil {
+	if length > maxPacketSize {
+		return nil, errors.Errorf("[ DeserializePacket ] packet is too large: %d bytes, max %d", length, maxPacketSize)
+	}
+	var buf bytes.Buffer
+	if length < maxPrealloc {
+		buf.Grow(int(length))
+	} else {
+		buf.Grow(maxPrealloc)
+	}
+	if _, err := io.CopyN(&buf, conn, length); err != nil {
 		log.Error("[ DeserializePacket ] couldn't read packet: ", err)
+		if err == io.EOF {
+			return nil, io.ErrUnexpectedEOF
+		}
 		return nil, err
 	}
 	log.Debugf("[ DeserializePacket ] read packet")
 
-	msg := &Packet{}
-	dec := gob.NewDecoder(bytes.NewReader(buf))
-
-	err = dec.Decode(msg)
+	msg, err := decodePacket(buf.Bytes())
 	if err != nil {
 		log.Error("[ DeserializePacket ] couldn't decode packet: ", err)
 		return nil, err
@@ -99,10 +172,78 @@ func DeserializePacket(conn io.Reader) (*Packet, error) {
 	return msg, nil
 }
 
+func decodePacket(data []byte) (*Packet, error) {
+	if len(data) == 0 {
+		return nil, errors.New("[ decodePacket ] packet is empty")
+	}
+	if data[0] != packetVersion {
+		return nil, errors.Errorf("[ decodePacket ] unsupported packet version %d", data[0])
+	}
+	data = data[1:]
+
+	msg := &Packet{}
+	var payloadType PayloadType
+	var payload []byte
+	var errorText string
+	for len(data) > 0 {
+		number, rest, err := readUvarint(data)
+		if err != nil {
+			return nil, errors.Wrap(err, "[ decodePacket ] failed to read field number")
+		}
+		value, rest, err := readBytes(rest)
+		if err != nil {
+			return nil, errors.Wrapf(err, "[ decodePacket ] failed to read field %d", number)
+		}
+		data = rest
+
+		var target interface{}
+		switch number {
+		case fieldSender:
+			target = &msg.Sender
+		case fieldReceiver:
+			target = &msg.Receiver
+		case fieldType:
+			target = &msg.Type
+		case fieldRequestID:
+			target = &msg.RequestID
+		case fieldRemoteAddress:
+			target = &msg.RemoteAddress
+		case fieldTraceID:
+			target = &msg.TraceID
+		case fieldIsResponse:
+			target = &msg.IsResponse
+		case fieldError:
+			target = &errorText
+		case fieldPayloadType:
+			target = &payloadType
+		case fieldData:
+			payload = value
+			continue
+		default:
+			continue
+		}
+		if err := decodeValue(value, reflect.ValueOf(target).Elem()); err != nil {
+			return nil, errors.Wrapf(err, "[ decodePacket ] failed to decode field %d", number)
+		}
+	}
+
+	if errorText != "" {
+		msg.Error = errors.New(errorText)
+	}
+	if payload != nil {
+		data, err := unmarshalPayload(payloadType, payload)
+		if err != nil {
+			return nil, errors.Wrap(err, "[ decodePacket ] failed to decode data")
+		}
+		msg.Data = data
+	}
+	return msg, nil
+}
+
 func init() {
-	gob.Register(&RequestPulse{})
-	gob.Register(&RequestGetRandomHosts{})
+	RegisterPayload(PayloadRequestPulse, &RequestPulse{})
+	RegisterPayload(PayloadRequestGetRandomHosts, &RequestGetRandomHosts{})
 
-	gob.Register(&ResponsePulse{})
-	gob.Register(&ResponseGetRandomHosts{})
+	RegisterPayload(PayloadResponsePulse, &ResponsePulse{})
+	RegisterPayload(PayloadResponseGetRandomHosts, &ResponseGetRandomHosts{})
 }
@@ -19,11 +19,16 @@ package packet
 import (
 	"bytes"
 	"crypto/rand"
+	"encoding/binary"
 	"encoding/gob"
+	mathrand "math/rand"
 	"testing"
 
+	"github.com/insolar/insolar/core"
 	"github.com/insolar/insolar/network/transport/host"
+	"github.com/insolar/insolar/network/transport/packet/types"
 	"github.com/insolar/insolar/testutils"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 )
 
@@ -81,3 +86,209 @@ func TestDeserializeBigPacket(t *testing.T) {
 	deserializedData := deserializedMsg.Data.(*RequestTest).Data
 	require.Equal(t, data, deserializedData)
 }
+
+func TestDeserializePacket_TooLarge(t *testing.T) {
+	var header [lengthSize]byte
+	binary.BigEndian.PutUint32(header[:], maxPacketSize+1)
+	// Packet body is not sent, decoder must fail on length before reading it.
+	_, err := DeserializePacket(bytes.NewReader(header[:]))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "packet is too large")
+}
+
+func TestSerializePacket_TooLarge(t *testing.T) {
+	hostOne, _ := host.NewHost("127.0.0.1:31337")
+	msg := NewBuilder(hostOne).Receiver(hostOne).Type(TestPacket).
+		Request(&RequestTest{make([]byte, maxPacketSize)}).Build()
+
+	_, err := SerializePacket(msg)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "packet is too large")
+}
+
+func newFullPacket(t require.TestingT) *Packet {
+	sender, err := host.NewHostNS("127.0.0.1:31337", testutils.RandomRef(), 15)
+	require.NoError(t, err)
+	receiver, err := host.NewHostN("127.0.0.2:31338", testutils.RandomRef())
+	require.NoError(t, err)
+
+	pulse := core.Pulse{
+		PulseNumber:     core.FirstPulseNumber,
+		NextPulseNumber: core.FirstPulseNumber + 10,
+		PulseTimestamp:  -42,
+		Entropy:         core.Entropy{1, 2, 3},
+		Signs: map[string]core.PulseSenderConfirmation{
+			"pulsar": {ChosenPublicKey: "key", Signature: []byte{4, 5, 6}},
+		},
+	}
+	return NewBuilder(sender).Receiver(receiver).Type(types.Pulse).RequestID(100500).TraceID("trace").
+		Request(&RequestPulse{Pulse: pulse}).Build()
+}
+
+func reserialize(t *testing.T, msg *Packet) *Packet {
+	serialized, err := SerializePacket(msg)
+	require.NoError(t, err)
+	deserialized, err := DeserializePacket(bytes.NewReader(serialized))
+	require.NoError(t, err)
+	return deserialized
+}
+
+func TestSerializePacket_AllFields(t *testing.T) {
+	msg := newFullPacket(t)
+	require.Equal(t, msg, reserialize(t, msg))
+
+	response := NewBuilder(msg.Receiver).Receiver(msg.Sender).Type(types.Pulse).
+		Response(&ResponseGetRandomHosts{Hosts: []host.Host{*msg.Sender, *msg.Receiver}}).Build()
+	response.Error = errors.New("failed")
+	deserialized := reserialize(t, response)
+	require.Equal(t, "failed", deserialized.Error.Error())
+	deserialized.Error = response.Error
+	require.Equal(t, response, deserialized)
+}
+
+func TestSerializePacket_NoData(t *testing.T) {
+	sender, _ := host.NewHost("127.0.0.1:31337")
+	msg := NewBuilder(sender).Receiver(sender).Type(types.Ping).Response(nil).Build()
+	require.Equal(t, msg, reserialize(t, msg))
+}
+
+func TestSerializePacket_UnregisteredData(t *testing.T) {
+	type unregistered struct{}
+	sender, _ := host.NewHost("127.0.0.1:31337")
+	msg := NewBuilder(sender).Receiver(sender).Type(types.Ping).Request(&unregistered{}).Build()
+
+	_, err := SerializePacket(msg)
+	require.Error(t, err)
+}
+
+// withField appends field to serialized packet and fixes packet length.
+func withField(serialized []byte, number uint64, value []byte) []byte {
+	result := appendBytes(appendUvarint(append([]byte{}, serialized...), number), value)
+	binary.BigEndian.PutUint32(result, uint32(len(result)-lengthSize))
+	return result
+}
+
+func TestDeserializePacket_SkipsUnknownFields(t *testing.T) {
+	msg := newFullPacket(t)
+	serialized, err := SerializePacket(msg)
+	require.NoError(t, err)
+
+	serialized = withField(serialized, 1000, []byte("field from the future"))
+	deserialized, err := DeserializePacket(bytes.NewReader(serialized))
+	require.NoError(t, err)
+	require.Equal(t, msg, deserialized)
+}
+
+func TestDeserializePacket_MissingFields(t *testing.T) {
+	serialized := withField([]byte{0, 0, 0, 0, packetVersion}, fieldType, appendVarint(nil, int64(types.Ping)))
+
+	deserialized, err := DeserializePacket(bytes.NewReader(serialized))
+	require.NoError(t, err)
+	require.Equal(t, &Packet{Type: types.Ping}, deserialized)
+}
+
+func TestDeserializePacket_UnsupportedVersion(t *testing.T) {
+	serialized, err := SerializePacket(newFullPacket(t))
+	require.NoError(t, err)
+	serialized[lengthSize] = packetVersion + 1
+
+	_, err = DeserializePacket(bytes.NewReader(serialized))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unsupported packet version")
+}
+
+func TestDeserializePacket_Truncated(t *testing.T) {
+	serialized, err := SerializePacket(newFullPacket(t))
+	require.NoError(t, err)
+
+	for i := 0; i < len(serialized); i++ {
+		_, err = DeserializePacket(bytes.NewReader(serialized[:i]))
+		require.Error(t, err, "prefix of %d bytes", i)
+	}
+}
+
+// TestDeserializePacket_Mutated is a short in-tree fuzzing run, use go-fuzz with Fuzz for a long one.
+func TestDeserializePacket_Mutated(t *testing.T) {
+	serialized, err := SerializePacket(newFullPacket(t))
+	require.NoError(t, err)
+
+	random := mathrand.New(mathrand.NewSource(42))
+	iterations := 20000
+	if testing.Short() {
+		iterations = 1000
+	}
+	for i := 0; i < iterations; i++ {
+		mutated := append([]byte{}, serialized...)
+		for n := random.Intn(8) + 1; n > 0; n-- {
+			mutated[random.Intn(len(mutated))] = byte(random.Intn(256))
+		}
+		require.NotPanics(t, func() {
+			msg, err := DeserializePacket(bytes.NewReader(mutated))
+			if err != nil {
+				return
+			}
+			_, err = SerializePacket(msg)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func benchmarkPacket(size int) *Packet {
+	sender, _ := host.NewHostN("127.0.0.1:31337", testutils.RandomRef())
+	data := make([]byte, size)
+	rand.Read(data)
+	return NewBuilder(sender).Receiver(sender).Type(TestPacket).Request(&RequestTest{data}).Build()
+}
+
+func gobSerialize(msg *Packet) ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(msg)
+	return buf.Bytes(), err
+}
+
+func benchmarkSerialize(b *testing.B, size int, serialize func(*Packet) ([]byte, error)) {
+	msg := benchmarkPacket(size)
+	b.SetBytes(int64(size))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := serialize(msg); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkDeserialize(b *testing.B, size int, useGob bool) {
+	msg := benchmarkPacket(size)
+	var serialized []byte
+	var err error
+	if useGob {
+		serialized, err = gobSerialize(msg)
+	} else {
+		serialized, err = SerializePacket(msg)
+	}
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.SetBytes(int64(size))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if useGob {
+			err = gob.NewDecoder(bytes.NewReader(serialized)).Decode(&Packet{})
+		} else {
+			_, err = DeserializePacket(bytes.NewReader(serialized))
+		}
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSerializePacket_Small(b *testing.B)    { benchmarkSerialize(b, 64, SerializePacket) }
+func BenchmarkSerializePacket_SmallGob(b *testing.B) { benchmarkSerialize(b, 64, gobSerialize) }
+func BenchmarkSerializePacket_Big(b *testing.B)      { benchmarkSerialize(b, 1024*1024, SerializePacket) }
+func BenchmarkSerializePacket_BigGob(b *testing.B)   { benchmarkSerialize(b, 1024*1024, gobSerialize) }
+
+func BenchmarkDeserializePacket_Small(b *testing.B)    { benchmarkDeserialize(b, 64, false) }
+func BenchmarkDeserializePacket_SmallGob(b *testing.B) { benchmarkDeserialize(b, 64, true) }
+func BenchmarkDeserializePacket_Big(b *testing.B)      { benchmarkDeserialize(b, 1024*1024, false) }
+func BenchmarkDeserializePacket_BigGob(b *testing.B)   { benchmarkDeserialize(b, 1024*1024, true) }
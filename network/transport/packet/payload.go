@@ -0,0 +1,133 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package packet
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// PayloadType is a wire identifier of packet Data type. Identifiers are part of the protocol:
+// never renumber or reuse them, append new ones instead.
+type PayloadType uint16
+
+const (
+	PayloadRequestPulse PayloadType = iota + 1
+	PayloadRequestGetRandomHosts
+	PayloadResponsePulse
+	PayloadResponseGetRandomHosts
+
+	PayloadRequestRPC
+	PayloadResponseRPC
+	PayloadRequestCascade
+	PayloadResponseCascade
+
+	PayloadChallengeRequest
+	PayloadSignedChallengeResponse
+	PayloadSignedChallengeRequest
+	PayloadChallengeResponse
+
+	PayloadNodeBootstrapRequest
+	PayloadNodeBootstrapResponse
+	PayloadStartSessionRequest
+	PayloadStartSessionResponse
+	PayloadGenesisRequest
+	PayloadGenesisResponse
+
+	PayloadAuthorizationRequest
+	PayloadAuthorizationResponse
+	PayloadRegistrationRequest
+	PayloadRegistrationResponse
+)
+
+// Payload types starting from 1000 are reserved for tests.
+const (
+	PayloadRequestTest PayloadType = 1000 + iota
+	PayloadResponseTest
+)
+
+var payloads = struct {
+	sync.RWMutex
+	types map[PayloadType]reflect.Type
+	ids   map[reflect.Type]PayloadType
+}{
+	types: make(map[PayloadType]reflect.Type),
+	ids:   make(map[reflect.Type]PayloadType),
+}
+
+// RegisterPayload registers pointer to struct prototype as packet Data with wire identifier id.
+// It panics if prototype can not be encoded or identifier is already taken, so call it from init().
+func RegisterPayload(id PayloadType, prototype interface{}) {
+	t := reflect.TypeOf(prototype)
+	if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		panic(fmt.Sprintf("payload %d must be a pointer to struct, got %T", id, prototype))
+	}
+	if err := checkSchema(t.Elem(), make(map[reflect.Type]bool)); err != nil {
+		panic(fmt.Sprintf("payload %s can not be encoded: %s", t, err))
+	}
+
+	payloads.Lock()
+	defer payloads.Unlock()
+
+	if other, ok := payloads.types[id]; ok && other != t {
+		panic(fmt.Sprintf("payload %d is already registered for %s", id, other))
+	}
+	if other, ok := payloads.ids[t]; ok && other != id {
+		panic(fmt.Sprintf("payload %s is already registered as %d", t, other))
+	}
+	payloads.types[id] = t
+	payloads.ids[t] = id
+}
+
+func marshalPayload(data interface{}) (PayloadType, []byte, error) {
+	t := reflect.TypeOf(data)
+
+	payloads.RLock()
+	id, ok := payloads.ids[t]
+	payloads.RUnlock()
+
+	if !ok {
+		return 0, nil, errors.Errorf("payload %s is not registered", t)
+	}
+	value := reflect.ValueOf(data)
+	if value.IsNil() {
+		return 0, nil, errors.Errorf("payload %s is nil", t)
+	}
+	encoded, err := encodeValue(nil, value.Elem())
+	if err != nil {
+		return 0, nil, errors.Wrapf(err, "failed to encode payload %s", t)
+	}
+	return id, encoded, nil
+}
+
+func unmarshalPayload(id PayloadType, data []byte) (interface{}, error) {
+	payloads.RLock()
+	t, ok := payloads.types[id]
+	payloads.RUnlock()
+
+	if !ok {
+		return nil, errors.Errorf("unknown payload type %d", id)
+	}
+	value := reflect.New(t.Elem())
+	if err := decodeValue(data, value.Elem()); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode payload %s", t)
+	}
+	return value.Interface(), nil
+}
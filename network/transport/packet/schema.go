@@ -0,0 +1,358 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package packet
+
+import (
+	"encoding/binary"
+	"math"
+	"reflect"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// Schema encoding of payload structs.
+//
+// Struct is a sequence of fields | number uvarint | size uvarint | value |. Field number is taken from `wire:"N"`
+// tag or is the field position starting from 1, so new fields must be appended (or tagged) and numbers of
+// removed fields must not be reused. Fields with empty encoding are omitted, unknown fields are skipped and
+// missing fields are left zero, which keeps old and new nodes compatible.
+//
+// Values: bool is one byte, signed integers are zigzag varints, unsigned integers are uvarints, floats are
+// 8 bytes IEEE 754, strings and byte slices/arrays are raw bytes, pointer is presence byte followed by value,
+// other slices, arrays and maps are sequences of size-prefixed elements (maps alternate keys and values).
+
+func checkSchema(t reflect.Type, visited map[reflect.Type]bool) error {
+	if visited[t] {
+		return nil
+	}
+	visited[t] = true
+
+	switch t.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return nil
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return checkSchema(t.Elem(), visited)
+	case reflect.Map:
+		if err := checkSchema(t.Key(), visited); err != nil {
+			return err
+		}
+		return checkSchema(t.Elem(), visited)
+	case reflect.Struct:
+		numbers := make(map[uint64]string)
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			number, err := fieldNumber(field, i)
+			if err != nil {
+				return err
+			}
+			if other, ok := numbers[number]; ok {
+				return errors.Errorf("fields %s and %s of %s have same number %d", other, field.Name, t, number)
+			}
+			numbers[number] = field.Name
+			if err := checkSchema(field.Type, visited); err != nil {
+				return errors.Wrapf(err, "field %s of %s", field.Name, t)
+			}
+		}
+		return nil
+	default:
+		return errors.Errorf("unsupported type %s", t)
+	}
+}
+
+func fieldNumber(field reflect.StructField, index int) (uint64, error) {
+	tag, ok := field.Tag.Lookup("wire")
+	if !ok {
+		return uint64(index + 1), nil
+	}
+	number, err := strconv.ParseUint(tag, 10, 32)
+	if err != nil || number == 0 {
+		return 0, errors.Errorf("invalid wire tag %q of field %s", tag, field.Name)
+	}
+	return number, nil
+}
+
+func appendBytes(buf []byte, data []byte) []byte {
+	buf = appendUvarint(buf, uint64(len(data)))
+	return append(buf, data...)
+}
+
+func appendUvarint(buf []byte, x uint64) []byte {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(tmp[:], x)
+	return append(buf, tmp[:n]...)
+}
+
+func appendVarint(buf []byte, x int64) []byte {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutVarint(tmp[:], x)
+	return append(buf, tmp[:n]...)
+}
+
+func readUvarint(data []byte) (uint64, []byte, error) {
+	x, n := binary.Uvarint(data)
+	if n <= 0 {
+		return 0, nil, errors.New("invalid uvarint")
+	}
+	return x, data[n:], nil
+}
+
+func readBytes(data []byte) ([]byte, []byte, error) {
+	size, rest, err := readUvarint(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	if size > uint64(len(rest)) {
+		return nil, nil, errors.New("value is truncated")
+	}
+	return rest[:size], rest[size:], nil
+}
+
+func isBytes(t reflect.Type) bool {
+	return (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && t.Elem().Kind() == reflect.Uint8
+}
+
+func appendRaw(buf []byte, v reflect.Value) []byte {
+	if v.Kind() == reflect.Slice {
+		return append(buf, v.Bytes()...)
+	}
+	start := len(buf)
+	buf = append(buf, make([]byte, v.Len())...)
+	reflect.Copy(reflect.ValueOf(buf[start:]), v)
+	return buf
+}
+
+// encodeValue appends schema encoding of v to buf.
+func encodeValue(buf []byte, v reflect.Value) ([]byte, error) {
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			return append(buf, 1), nil
+		}
+		return append(buf, 0), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return appendVarint(buf, v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return appendUvarint(buf, v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		var tmp [8]byte
+		binary.BigEndian.PutUint64(tmp[:], math.Float64bits(v.Float()))
+		return append(buf, tmp[:]...), nil
+	case reflect.String:
+		return append(buf, v.String()...), nil
+	case reflect.Ptr:
+		if v.IsNil() {
+			return append(buf, 0), nil
+		}
+		return encodeValue(append(buf, 1), v.Elem())
+	case reflect.Slice, reflect.Array:
+		if isBytes(v.Type()) {
+			return appendRaw(buf, v), nil
+		}
+		for i := 0; i < v.Len(); i++ {
+			elem, err := encodeValue(nil, v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			buf = appendBytes(buf, elem)
+		}
+		return buf, nil
+	case reflect.Map:
+		for _, key := range v.MapKeys() {
+			k, err := encodeValue(nil, key)
+			if err != nil {
+				return nil, err
+			}
+			e, err := encodeValue(nil, v.MapIndex(key))
+			if err != nil {
+				return nil, err
+			}
+			buf = appendBytes(appendBytes(buf, k), e)
+		}
+		return buf, nil
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			number, err := fieldNumber(field, i)
+			if err != nil {
+				return nil, err
+			}
+			value, err := encodeValue(nil, v.Field(i))
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to encode field %s", field.Name)
+			}
+			if len(value) == 0 {
+				continue
+			}
+			buf = appendBytes(appendUvarint(buf, number), value)
+		}
+		return buf, nil
+	default:
+		return nil, errors.Errorf("unsupported type %s", v.Type())
+	}
+}
+
+// decodeValue decodes schema encoding data into settable v.
+func decodeValue(data []byte, v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Bool:
+		if len(data) != 1 {
+			return errors.New("invalid bool")
+		}
+		v.SetBool(data[0] != 0)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		x, n := binary.Varint(data)
+		if n <= 0 || n != len(data) || v.OverflowInt(x) {
+			return errors.Errorf("invalid %s", v.Type())
+		}
+		v.SetInt(x)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		x, n := binary.Uvarint(data)
+		if n <= 0 || n != len(data) || v.OverflowUint(x) {
+			return errors.Errorf("invalid %s", v.Type())
+		}
+		v.SetUint(x)
+	case reflect.Float32, reflect.Float64:
+		if len(data) != 8 {
+			return errors.New("invalid float")
+		}
+		v.SetFloat(math.Float64frombits(binary.BigEndian.Uint64(data)))
+	case reflect.String:
+		v.SetString(string(data))
+	case reflect.Ptr:
+		if len(data) == 0 {
+			return errors.New("invalid pointer")
+		}
+		if data[0] == 0 {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+		elem := reflect.New(v.Type().Elem())
+		if err := decodeValue(data[1:], elem.Elem()); err != nil {
+			return err
+		}
+		v.Set(elem)
+	case reflect.Slice:
+		if isBytes(v.Type()) {
+			// data is owned by decoded packet, so byte slices share it instead of copying
+			v.Set(reflect.ValueOf(data[:len(data):len(data)]).Convert(v.Type()))
+			return nil
+		}
+		slice := reflect.MakeSlice(v.Type(), 0, 0)
+		for len(data) > 0 {
+			elem, rest, err := readBytes(data)
+			if err != nil {
+				return err
+			}
+			value := reflect.New(v.Type().Elem()).Elem()
+			if err := decodeValue(elem, value); err != nil {
+				return err
+			}
+			slice = reflect.Append(slice, value)
+			data = rest
+		}
+		v.Set(slice)
+	case reflect.Array:
+		if isBytes(v.Type()) {
+			if len(data) != v.Len() {
+				return errors.Errorf("invalid %s length %d", v.Type(), len(data))
+			}
+			reflect.Copy(v, reflect.ValueOf(data))
+			return nil
+		}
+		for i := 0; len(data) > 0; i++ {
+			if i >= v.Len() {
+				return errors.Errorf("too many elements for %s", v.Type())
+			}
+			elem, rest, err := readBytes(data)
+			if err != nil {
+				return err
+			}
+			if err := decodeValue(elem, v.Index(i)); err != nil {
+				return err
+			}
+			data = rest
+		}
+	case reflect.Map:
+		m := reflect.MakeMap(v.Type())
+		for len(data) > 0 {
+			k, rest, err := readBytes(data)
+			if err != nil {
+				return err
+			}
+			e, rest, err := readBytes(rest)
+			if err != nil {
+				return err
+			}
+			key := reflect.New(v.Type().Key()).Elem()
+			if err := decodeValue(k, key); err != nil {
+				return err
+			}
+			value := reflect.New(v.Type().Elem()).Elem()
+			if err := decodeValue(e, value); err != nil {
+				return err
+			}
+			m.SetMapIndex(key, value)
+			data = rest
+		}
+		v.Set(m)
+	case reflect.Struct:
+		fields := make(map[uint64]int)
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			if t.Field(i).PkgPath != "" {
+				continue
+			}
+			number, err := fieldNumber(t.Field(i), i)
+			if err != nil {
+				return err
+			}
+			fields[number] = i
+		}
+		for len(data) > 0 {
+			number, rest, err := readUvarint(data)
+			if err != nil {
+				return err
+			}
+			value, rest, err := readBytes(rest)
+			if err != nil {
+				return err
+			}
+			data = rest
+			index, ok := fields[number]
+			if !ok {
+				continue
+			}
+			if err := decodeValue(value, v.Field(index)); err != nil {
+				return errors.Wrapf(err, "failed to decode field %s", t.Field(index).Name)
+			}
+		}
+	default:
+		return errors.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
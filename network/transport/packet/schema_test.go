@@ -0,0 +1,85 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package packet
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type schemaV1 struct {
+	Number int64
+	Name   string
+	Nested *schemaV1
+}
+
+type schemaV2 struct {
+	Number int64
+	Name   string
+	Nested *schemaV1
+	Tags   map[string][]uint16
+	Moved  bool `wire:"10"`
+}
+
+func transcode(t *testing.T, from interface{}, to interface{}) {
+	encoded, err := encodeValue(nil, reflect.ValueOf(from))
+	require.NoError(t, err)
+	require.NoError(t, decodeValue(encoded, reflect.ValueOf(to).Elem()))
+}
+
+func TestSchema_NewReadsOld(t *testing.T) {
+	old := schemaV1{Number: -7, Name: "old", Nested: &schemaV1{Number: 1}}
+	var decoded schemaV2
+	transcode(t, old, &decoded)
+	require.Equal(t, schemaV2{Number: -7, Name: "old", Nested: &schemaV1{Number: 1}}, decoded)
+}
+
+func TestSchema_OldReadsNew(t *testing.T) {
+	actual := schemaV2{Number: 7, Name: "new", Tags: map[string][]uint16{"a": {1, 2}}, Moved: true}
+	var decoded schemaV1
+	transcode(t, actual, &decoded)
+	require.Equal(t, schemaV1{Number: 7, Name: "new"}, decoded)
+
+	var same schemaV2
+	transcode(t, actual, &same)
+	require.Equal(t, actual, same)
+}
+
+func TestSchema_Overflow(t *testing.T) {
+	var small struct{ Number int8 }
+	encoded, err := encodeValue(nil, reflect.ValueOf(struct{ Number int64 }{1000}))
+	require.NoError(t, err)
+	require.Error(t, decodeValue(encoded, reflect.ValueOf(&small).Elem()))
+}
+
+func TestRegisterPayload(t *testing.T) {
+	type withInterface struct {
+		Value interface{}
+	}
+	type duplicateNumbers struct {
+		A int `wire:"2"`
+		B int
+	}
+	require.Panics(t, func() { RegisterPayload(PayloadType(1900), withInterface{}) })
+	require.Panics(t, func() { RegisterPayload(PayloadType(1900), &withInterface{}) })
+	require.Panics(t, func() { RegisterPayload(PayloadType(1900), &duplicateNumbers{}) })
+	require.Panics(t, func() { RegisterPayload(PayloadRequestTest, &ResponseTest{}) })
+	require.Panics(t, func() { RegisterPayload(PayloadType(1900), &RequestTest{}) })
+	require.NotPanics(t, func() { RegisterPayload(PayloadRequestTest, &RequestTest{}) })
+}
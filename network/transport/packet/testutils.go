@@ -31,3 +31,8 @@ type RequestTest struct {
 type ResponseTest struct {
 	Number int
 }
+
+func init() {
+	RegisterPayload(PayloadRequestTest, &RequestTest{})
+	RegisterPayload(PayloadResponseTest, &ResponseTest{})
+}
@@ -17,16 +17,20 @@
 package transport
 
 import (
+	"bufio"
 	"context"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/pem"
+	"io"
 	"math/big"
 	"net"
+	"sync"
 
 	"github.com/insolar/insolar/log"
+	"github.com/insolar/insolar/network/transport/packet"
 	"github.com/insolar/insolar/network/transport/relay"
 	"github.com/insolar/insolar/network/utils"
 	"github.com/lucas-clemente/quic-go"
@@ -42,9 +46,39 @@ type quicTransport struct {
 	baseTransport
 	l           quic.Listener
 	conn        net.PacketConn
+	connMutex   sync.Mutex
 	connections map[string]quicConnection
 }
 
+// quicStream is Stream over native QUIC stream.
+type quicStream struct {
+	quic.Stream
+	reader io.Reader
+	packet *packet.Packet
+}
+
+func (s *quicStream) Read(p []byte) (int, error) {
+	return s.reader.Read(p)
+}
+
+// CloseWrite closes writing side of the stream, QUIC stream Close does the same.
+func (s *quicStream) CloseWrite() error {
+	return s.Stream.Close()
+}
+
+// Close closes both sides of the stream.
+func (s *quicStream) Close() error {
+	if err := s.Stream.CancelRead(0); err != nil {
+		log.Debug("[ Close ] Failed to cancel stream reading: ", err)
+	}
+	return s.Stream.Close()
+}
+
+// Packet returns packet the stream was opened with.
+func (s *quicStream) Packet() *packet.Packet {
+	return s.packet
+}
+
 func newQuicTransport(conn net.PacketConn, proxy relay.Proxy, publicAddress string) (*quicTransport, error) {
 	listener, err := quic.Listen(conn, generateTLSConfig(), nil)
 	if err != nil {
@@ -62,22 +96,30 @@ func newQuicTransport(conn net.PacketConn, proxy relay.Proxy, publicAddress stri
 	return transport, nil
 }
 
+func (t *quicTransport) getConnection(address string) (quicConnection, error) {
+	t.connMutex.Lock()
+	defer t.connMutex.Unlock()
+
+	conn, ok := t.connections[address]
+	if ok {
+		return conn, nil
+	}
+	session, stream, err := createConnection(address)
+	if err != nil {
+		return quicConnection{}, errors.Wrap(err, "[ getConnection ] failed to create a connection")
+	}
+	conn = quicConnection{session, stream}
+	t.connections[address] = conn
+	return conn, nil
+}
+
 func (t *quicTransport) send(recvAddress string, data []byte) error {
-	conn, ok := t.connections[recvAddress]
-	var stream quic.Stream
-	var err error
-	if !ok {
-		var session quic.Session
-		session, stream, err = createConnection(recvAddress)
-		if err != nil {
-			return errors.Wrap(err, "[ send ] failed to create a connection")
-		}
-		t.connections[recvAddress] = quicConnection{session, stream}
-	} else {
-		stream = conn.stream
+	conn, err := t.getConnection(recvAddress)
+	if err != nil {
+		return errors.Wrap(err, "[ send ] failed to get a connection")
 	}
 
-	n, err := stream.Write(data)
+	n, err := conn.stream.Write(data)
 	if err != nil {
 		return errors.Wrap(err, "[ send ] failed to write to a stream")
 	}
@@ -115,28 +157,74 @@ func (t *quicTransport) Stop() {
 
 	utils.CloseVerbose(t.l)
 
+	t.connMutex.Lock()
 	for _, conn := range t.connections {
 		utils.CloseVerbose(conn.stream)
 		utils.CloseVerbose(conn.session)
 	}
+	t.connMutex.Unlock()
 
 	utils.CloseVerbose(t.conn)
 }
 
-func (t *quicTransport) handleAcceptedConnection(session quic.Session) {
-	stream, err := session.AcceptStream()
+// OpenStream opens new QUIC stream in session to the packet receiver.
+func (t *quicTransport) OpenStream(ctx context.Context, p *packet.Packet) (Stream, error) {
+	address := p.Receiver.Address.String()
+	conn, err := t.getConnection(address)
 	if err != nil {
-		log.Error(err, "[ handleAcceptedConnection ] failed to get a stream")
+		return nil, errors.Wrap(err, "[ OpenStream ] failed to get a connection")
 	}
-
-	msg, err := t.serializer.DeserializePacket(stream)
+	data, err := t.serializer.SerializePacket(p)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ OpenStream ] failed to serialize packet")
+	}
+	stream, err := conn.session.OpenStreamSync()
 	if err != nil {
-		log.Error(err, "[ handleAcceptedConnection ] failed to deserialize a packet")
+		return nil, errors.Wrap(err, "[ OpenStream ] failed to open a stream")
+	}
+	if _, err := stream.Write(append(append([]byte{}, streamPreamble...), data...)); err != nil {
+		utils.CloseVerbose(stream)
+		return nil, errors.Wrap(err, "[ OpenStream ] failed to write stream packet")
+	}
+	return t.limitStream(ctx, address, p, &quicStream{Stream: stream, reader: stream, packet: p}), nil
+}
+
+func (t *quicTransport) handleAcceptedConnection(session quic.Session) {
+	for {
+		stream, err := session.AcceptStream()
+		if err != nil {
+			log.Debug("[ handleAcceptedConnection ] failed to get a stream: ", err)
+			return
+		}
+		go t.handleAcceptedStream(stream)
+	}
+}
+
+func (t *quicTransport) handleAcceptedStream(stream quic.Stream) {
+	reader := bufio.NewReader(stream)
+	if isStream, _ := readStreamPreamble(reader); isStream {
+		msg, err := t.serializer.DeserializePacket(reader)
+		if err != nil {
+			log.Error(err, "[ handleAcceptedStream ] failed to deserialize a stream packet")
+			utils.CloseVerbose(stream)
+			return
+		}
+		t.acceptStream(&quicStream{Stream: stream, reader: reader, packet: msg})
+		return
 	}
+	defer utils.CloseVerbose(stream)
 
-	go t.packetHandler.Handle(context.TODO(), msg)
+	for {
+		msg, err := t.serializer.DeserializePacket(reader)
+		if err != nil {
+			if err != io.EOF && err != io.ErrUnexpectedEOF {
+				log.Error(err, "[ handleAcceptedStream ] failed to deserialize a packet")
+			}
+			return
+		}
 
-	utils.CloseVerbose(stream)
+		go t.packetHandler.Handle(context.TODO(), msg)
+	}
 }
 
 func createConnection(addr string) (quic.Session, quic.Stream, error) {
@@ -0,0 +1,531 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package transport
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/binary"
+	"encoding/gob"
+	"io"
+	"math/big"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/insolar/insolar/core"
+)
+
+const (
+	handshakeTimeout    = 10 * time.Second
+	maxHandshakeMessage = 64 * 1024
+
+	bindingLabel       = "insolar transport binding"
+	sessionIDLabel     = "insolar transport session id"
+	sessionSecretLabel = "insolar transport session secret"
+
+	clientRole = "client"
+	serverRole = "server"
+)
+
+// PeerAuthorizer checks that the node with reference ref owns public key key.
+type PeerAuthorizer func(ref core.RecordRef, key crypto.PublicKey) error
+
+// handshakeHello is sent by both sides right after the TLS handshake. Full hello carries the node public key
+// and the signature of the TLS channel binding, resumed hello carries the session id and HMAC of the binding.
+type handshakeHello struct {
+	Ref       core.RecordRef
+	PublicKey []byte
+	SessionID []byte
+	Proof     []byte
+	Resumed   bool
+}
+
+type securitySession struct {
+	peer    core.RecordRef
+	id      []byte
+	secret  []byte
+	expires time.Time
+}
+
+func (s *securitySession) expired() bool {
+	return time.Now().After(s.expires)
+}
+
+// securedConn is a connection authenticated by Security.
+type securedConn struct {
+	net.Conn
+	peer core.RecordRef
+}
+
+// authenticatedPeer returns reference of remote node of connection authenticated by Security.
+func authenticatedPeer(conn net.Conn) (core.RecordRef, bool) {
+	secured, ok := conn.(*securedConn)
+	if !ok {
+		return core.RecordRef{}, false
+	}
+	return secured.peer, true
+}
+
+// Security authenticates TLS transport connections with node keys.
+//
+// TLS itself uses an ephemeral certificate and only encrypts the channel. Peer identity is proven on top of it:
+// each side signs the TLS keying material with its node key, so the session is bound to the RecordRef owner.
+// Authenticated sessions are cached for sessionTTL and later connections prove knowledge of the session secret
+// instead of signing again.
+type Security struct {
+	origin       core.RecordRef
+	publicKey    []byte
+	service      core.CryptographyService
+	keyProcessor core.KeyProcessor
+	authorize    PeerAuthorizer
+	sessionTTL   time.Duration
+
+	serverConfig *tls.Config
+	clientConfig *tls.Config
+
+	mutex          sync.Mutex
+	clientSessions map[string]*securitySession
+	serverSessions map[string]*securitySession
+	// expected holds nodes expected to listen on addresses, connected holds nodes authenticated on them.
+	expected  map[string]core.RecordRef
+	connected map[string]core.RecordRef
+}
+
+// NewSecurity creates Security for node with reference origin and keys of service.
+func NewSecurity(
+	origin core.RecordRef,
+	service core.CryptographyService,
+	keyProcessor core.KeyProcessor,
+	authorize PeerAuthorizer,
+	sessionTTL time.Duration,
+) (*Security, error) {
+	publicKey, err := service.GetPublicKey()
+	if err != nil {
+		return nil, errors.Wrap(err, "[ NewSecurity ] failed to get public key")
+	}
+	exported, err := keyProcessor.ExportPublicKeyBinary(publicKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ NewSecurity ] failed to export public key")
+	}
+	certificate, err := newEphemeralCertificate()
+	if err != nil {
+		return nil, errors.Wrap(err, "[ NewSecurity ] failed to generate TLS certificate")
+	}
+
+	serverConfig := &tls.Config{
+		Certificates:           []tls.Certificate{certificate},
+		MinVersion:             tls.VersionTLS12,
+		SessionTicketsDisabled: sessionTTL <= 0,
+	}
+	// Server certificate is ephemeral, peer identity is verified by the node key proof bound to the channel.
+	clientConfig := &tls.Config{
+		InsecureSkipVerify: true, // nolint: gosec
+		MinVersion:         tls.VersionTLS12,
+	}
+	if sessionTTL > 0 {
+		clientConfig.ClientSessionCache = tls.NewLRUClientSessionCache(0)
+	}
+
+	return &Security{
+		origin:         origin,
+		publicKey:      exported,
+		service:        service,
+		keyProcessor:   keyProcessor,
+		authorize:      authorize,
+		sessionTTL:     sessionTTL,
+		serverConfig:   serverConfig,
+		clientConfig:   clientConfig,
+		clientSessions: make(map[string]*securitySession),
+		serverSessions: make(map[string]*securitySession),
+		expected:       make(map[string]core.RecordRef),
+		connected:      make(map[string]core.RecordRef),
+	}, nil
+}
+
+// ExpectPeer sets node expected to listen on address, connection to address fails if other node is authenticated.
+// Returns true if other node is already connected on address, such connection should be closed.
+func (s *Security) ExpectPeer(address string, ref core.RecordRef) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.expected[address] = ref
+	connected, ok := s.connected[address]
+	return ok && connected != ref
+}
+
+// Client secures outgoing connection to address and authenticates remote node.
+func (s *Security) Client(conn net.Conn, address string) (net.Conn, error) {
+	tlsConn := tls.Client(conn, s.clientConfig)
+	binding, err := s.tlsHandshake(tlsConn)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ Client ] TLS handshake failed")
+	}
+
+	session := s.clientSession(address)
+	var hello *handshakeHello
+	if session != nil {
+		hello = s.resumedHello(binding, clientRole, session)
+	} else {
+		hello, err = s.fullHello(binding, clientRole)
+		if err != nil {
+			return nil, errors.Wrap(err, "[ Client ] failed to create hello")
+		}
+	}
+	if err = writeHello(tlsConn, hello); err != nil {
+		return nil, errors.Wrap(err, "[ Client ] failed to send hello")
+	}
+
+	reply, err := readHello(tlsConn)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ Client ] failed to receive hello")
+	}
+
+	if reply.Resumed {
+		if session == nil {
+			return nil, errors.New("[ Client ] peer resumed unknown session")
+		}
+		if err = s.verifyResumed(reply, binding, serverRole, session); err != nil {
+			return nil, errors.Wrap(err, "[ Client ] failed to authenticate peer")
+		}
+		if err = s.checkExpected(address, reply.Ref); err != nil {
+			return nil, errors.Wrap(err, "[ Client ] failed to authenticate peer")
+		}
+		return s.finishHandshake(tlsConn, reply.Ref)
+	}
+
+	if err = s.verifyFull(reply, binding, serverRole); err != nil {
+		return nil, errors.Wrap(err, "[ Client ] failed to authenticate peer")
+	}
+	if err = s.checkExpected(address, reply.Ref); err != nil {
+		s.dropClientSession(address)
+		return nil, errors.Wrap(err, "[ Client ] failed to authenticate peer")
+	}
+	if session != nil {
+		// Peer does not know our session anymore, fall back to full authentication.
+		s.dropClientSession(address)
+		hello, err = s.fullHello(binding, clientRole)
+		if err != nil {
+			return nil, errors.Wrap(err, "[ Client ] failed to create hello")
+		}
+		if err = writeHello(tlsConn, hello); err != nil {
+			return nil, errors.Wrap(err, "[ Client ] failed to send hello")
+		}
+	}
+
+	session, err = s.newSession(tlsConn, reply.Ref)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ Client ] failed to create session")
+	}
+	s.storeClientSession(address, session)
+	return s.finishHandshake(tlsConn, reply.Ref)
+}
+
+// Server secures incoming connection and authenticates remote node.
+func (s *Security) Server(conn net.Conn) (net.Conn, error) {
+	tlsConn := tls.Server(conn, s.serverConfig)
+	binding, err := s.tlsHandshake(tlsConn)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ Server ] TLS handshake failed")
+	}
+
+	hello, err := readHello(tlsConn)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ Server ] failed to receive hello")
+	}
+
+	if len(hello.SessionID) > 0 {
+		session := s.serverSession(hello.SessionID)
+		if session != nil && s.verifyResumed(hello, binding, clientRole, session) == nil {
+			if err = writeHello(tlsConn, s.resumedHello(binding, serverRole, session)); err != nil {
+				return nil, errors.Wrap(err, "[ Server ] failed to send hello")
+			}
+			return s.finishHandshake(tlsConn, session.peer)
+		}
+	}
+
+	reply, err := s.fullHello(binding, serverRole)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ Server ] failed to create hello")
+	}
+	if err = writeHello(tlsConn, reply); err != nil {
+		return nil, errors.Wrap(err, "[ Server ] failed to send hello")
+	}
+
+	if len(hello.SessionID) > 0 {
+		// Session was not resumed, client follows up with full hello.
+		hello, err = readHello(tlsConn)
+		if err != nil {
+			return nil, errors.Wrap(err, "[ Server ] failed to receive hello")
+		}
+	}
+	if err = s.verifyFull(hello, binding, clientRole); err != nil {
+		return nil, errors.Wrap(err, "[ Server ] failed to authenticate peer")
+	}
+
+	session, err := s.newSession(tlsConn, hello.Ref)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ Server ] failed to create session")
+	}
+	s.storeServerSession(session)
+	return s.finishHandshake(tlsConn, hello.Ref)
+}
+
+func (s *Security) tlsHandshake(conn *tls.Conn) ([]byte, error) {
+	err := conn.SetDeadline(time.Now().Add(handshakeTimeout))
+	if err != nil {
+		return nil, err
+	}
+	err = conn.Handshake()
+	if err != nil {
+		return nil, err
+	}
+	state := conn.ConnectionState()
+	return state.ExportKeyingMaterial(bindingLabel, nil, sha256.Size)
+}
+
+// checkExpected checks that node authenticated on address is the expected one and remembers it.
+func (s *Security) checkExpected(address string, ref core.RecordRef) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if expected, ok := s.expected[address]; ok && expected != ref {
+		return errors.Errorf("expected node %s, connected to %s", expected, ref)
+	}
+	s.connected[address] = ref
+	return nil
+}
+
+func (s *Security) finishHandshake(conn *tls.Conn, peer core.RecordRef) (net.Conn, error) {
+	err := conn.SetDeadline(time.Time{})
+	if err != nil {
+		return nil, errors.Wrap(err, "[ finishHandshake ] failed to reset deadline")
+	}
+	return &securedConn{Conn: conn, peer: peer}, nil
+}
+
+func (s *Security) fullHello(binding []byte, role string) (*handshakeHello, error) {
+	signature, err := s.service.Sign(proofData(binding, role, s.origin))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to sign channel binding")
+	}
+	return &handshakeHello{
+		Ref:       s.origin,
+		PublicKey: s.publicKey,
+		Proof:     signature.Bytes(),
+	}, nil
+}
+
+func (s *Security) resumedHello(binding []byte, role string, session *securitySession) *handshakeHello {
+	return &handshakeHello{
+		Ref:       s.origin,
+		SessionID: session.id,
+		Proof:     sessionProof(session.secret, binding, role, s.origin),
+		Resumed:   true,
+	}
+}
+
+func (s *Security) verifyFull(hello *handshakeHello, binding []byte, role string) error {
+	if len(hello.PublicKey) == 0 {
+		return errors.New("public key is missing")
+	}
+	key, err := s.keyProcessor.ImportPublicKeyBinary(hello.PublicKey)
+	if err != nil {
+		return errors.Wrap(err, "failed to import public key")
+	}
+	if !s.service.Verify(key, core.SignatureFromBytes(hello.Proof), proofData(binding, role, hello.Ref)) {
+		return errors.Errorf("invalid signature of node %s", hello.Ref)
+	}
+	if s.authorize == nil {
+		return nil
+	}
+	return errors.Wrapf(s.authorize(hello.Ref, key), "node %s is not authorized", hello.Ref)
+}
+
+func (s *Security) verifyResumed(hello *handshakeHello, binding []byte, role string, session *securitySession) error {
+	if hello.Ref != session.peer {
+		return errors.Errorf("session belongs to node %s, not %s", session.peer, hello.Ref)
+	}
+	if !hmac.Equal(hello.Proof, sessionProof(session.secret, binding, role, hello.Ref)) {
+		return errors.New("invalid session proof")
+	}
+	return nil
+}
+
+func (s *Security) newSession(conn *tls.Conn, peer core.RecordRef) (*securitySession, error) {
+	state := conn.ConnectionState()
+	id, err := state.ExportKeyingMaterial(sessionIDLabel, nil, 16)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := state.ExportKeyingMaterial(sessionSecretLabel, nil, sha256.Size)
+	if err != nil {
+		return nil, err
+	}
+	return &securitySession{
+		peer:    peer,
+		id:      id,
+		secret:  secret,
+		expires: time.Now().Add(s.sessionTTL),
+	}, nil
+}
+
+func (s *Security) clientSession(address string) *securitySession {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	session, ok := s.clientSessions[address]
+	if !ok {
+		return nil
+	}
+	if session.expired() {
+		delete(s.clientSessions, address)
+		return nil
+	}
+	return session
+}
+
+func (s *Security) storeClientSession(address string, session *securitySession) {
+	if s.sessionTTL <= 0 {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.clientSessions[address] = session
+}
+
+func (s *Security) dropClientSession(address string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.clientSessions, address)
+}
+
+func (s *Security) serverSession(id []byte) *securitySession {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	session, ok := s.serverSessions[string(id)]
+	if !ok {
+		return nil
+	}
+	if session.expired() {
+		delete(s.serverSessions, string(id))
+		return nil
+	}
+	return session
+}
+
+func (s *Security) storeServerSession(session *securitySession) {
+	if s.sessionTTL <= 0 {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for id, stored := range s.serverSessions {
+		if stored.expired() {
+			delete(s.serverSessions, id)
+		}
+	}
+	s.serverSessions[string(session.id)] = session
+}
+
+func proofData(binding []byte, role string, ref core.RecordRef) []byte {
+	data := make([]byte, 0, len(binding)+len(role)+len(ref))
+	data = append(data, binding...)
+	data = append(data, role...)
+	return append(data, ref[:]...)
+}
+
+func sessionProof(secret, binding []byte, role string, ref core.RecordRef) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(proofData(binding, role, ref)) // nolint: errcheck
+	return mac.Sum(nil)
+}
+
+func writeHello(w io.Writer, hello *handshakeHello) error {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(hello)
+	if err != nil {
+		return err
+	}
+	var size [4]byte
+	binary.BigEndian.PutUint32(size[:], uint32(buf.Len()))
+	_, err = w.Write(append(size[:], buf.Bytes()...))
+	return err
+}
+
+func readHello(r io.Reader) (*handshakeHello, error) {
+	var size [4]byte
+	_, err := io.ReadFull(r, size[:])
+	if err != nil {
+		return nil, err
+	}
+	length := binary.BigEndian.Uint32(size[:])
+	if length > maxHandshakeMessage {
+		return nil, errors.Errorf("hello is too big: %d bytes", length)
+	}
+	data := make([]byte, length)
+	_, err = io.ReadFull(r, data)
+	if err != nil {
+		return nil, err
+	}
+	hello := &handshakeHello{}
+	err = gob.NewDecoder(bytes.NewReader(data)).Decode(hello)
+	if err != nil {
+		return nil, err
+	}
+	return hello, nil
+}
+
+func newEphemeralCertificate() (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	template := x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: "insolar"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(10 * 365 * 24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, nil
+}
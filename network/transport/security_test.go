@@ -0,0 +1,252 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package transport
+
+import (
+	"crypto"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/cryptography"
+	"github.com/insolar/insolar/platformpolicy"
+	"github.com/insolar/insolar/testutils"
+)
+
+type testPeer struct {
+	ref        core.RecordRef
+	service    core.CryptographyService
+	authorized int32
+}
+
+func newTestPeer(t *testing.T) *testPeer {
+	key, err := platformpolicy.NewKeyProcessor().GeneratePrivateKey()
+	require.NoError(t, err)
+	return &testPeer{
+		ref:     testutils.RandomRef(),
+		service: cryptography.NewKeyBoundCryptographyService(key),
+	}
+}
+
+// newTestSecurity creates Security which accepts only peers from known with their own keys.
+func newTestSecurity(t *testing.T, self *testPeer, ttl time.Duration, known ...*testPeer) *Security {
+	keyProcessor := platformpolicy.NewKeyProcessor()
+	authorize := func(ref core.RecordRef, key crypto.PublicKey) error {
+		atomic.AddInt32(&self.authorized, 1)
+		actual, err := keyProcessor.ExportPublicKeyBinary(key)
+		require.NoError(t, err)
+		for _, peer := range known {
+			if peer.ref != ref {
+				continue
+			}
+			expected, err := peer.service.GetPublicKey()
+			require.NoError(t, err)
+			exported, err := keyProcessor.ExportPublicKeyBinary(expected)
+			require.NoError(t, err)
+			if string(exported) != string(actual) {
+				return errors.New("key mismatch")
+			}
+			return nil
+		}
+		return errors.New("unknown peer")
+	}
+	security, err := NewSecurity(self.ref, self.service, keyProcessor, authorize, ttl)
+	require.NoError(t, err)
+	return security
+}
+
+// tcpPipe returns both ends of loopback TCP connection, net.Pipe is not used as it has no buffering
+// and TLS 1.3 peers write simultaneously during handshake.
+func tcpPipe() (net.Conn, net.Conn, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer listener.Close()
+
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		conn, _ := listener.Accept()
+		accepted <- conn
+	}()
+	clientConn, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		return nil, nil, err
+	}
+	serverConn := <-accepted
+	if serverConn == nil {
+		clientConn.Close()
+		return nil, nil, errors.New("failed to accept connection")
+	}
+	return clientConn, serverConn, nil
+}
+
+func secureConnect(client, server *Security) (net.Conn, net.Conn, error, error) {
+	clientConn, serverConn, err := tcpPipe()
+	if err != nil {
+		return nil, nil, err, err
+	}
+	type result struct {
+		conn net.Conn
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		conn, err := server.Server(serverConn)
+		if err != nil {
+			serverConn.Close()
+		}
+		done <- result{conn, err}
+	}()
+	conn, clientErr := client.Client(clientConn, "peer")
+	if clientErr != nil {
+		clientConn.Close()
+	}
+	res := <-done
+	return conn, res.conn, clientErr, res.err
+}
+
+func TestSecurity_Handshake(t *testing.T) {
+	alice, bob := newTestPeer(t), newTestPeer(t)
+	client := newTestSecurity(t, alice, time.Minute, bob)
+	server := newTestSecurity(t, bob, time.Minute, alice)
+
+	clientConn, serverConn, clientErr, serverErr := secureConnect(client, server)
+	require.NoError(t, clientErr)
+	require.NoError(t, serverErr)
+
+	go clientConn.Write([]byte("ping")) // nolint: errcheck
+	buf := make([]byte, 4)
+	_, err := serverConn.Read(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "ping", string(buf))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&alice.authorized))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&bob.authorized))
+}
+
+func TestSecurity_ResumeWithinTTL(t *testing.T) {
+	alice, bob := newTestPeer(t), newTestPeer(t)
+	client := newTestSecurity(t, alice, 200*time.Millisecond, bob)
+	server := newTestSecurity(t, bob, 200*time.Millisecond, alice)
+
+	_, _, clientErr, serverErr := secureConnect(client, server)
+	require.NoError(t, clientErr)
+	require.NoError(t, serverErr)
+
+	// resumed session skips key authentication
+	_, _, clientErr, serverErr = secureConnect(client, server)
+	require.NoError(t, clientErr)
+	require.NoError(t, serverErr)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&alice.authorized))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&bob.authorized))
+
+	time.Sleep(300 * time.Millisecond)
+	_, _, clientErr, serverErr = secureConnect(client, server)
+	require.NoError(t, clientErr)
+	require.NoError(t, serverErr)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&alice.authorized))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&bob.authorized))
+}
+
+func TestSecurity_ServerForgotSession(t *testing.T) {
+	alice, bob := newTestPeer(t), newTestPeer(t)
+	client := newTestSecurity(t, alice, time.Minute, bob)
+	server := newTestSecurity(t, bob, time.Minute, alice)
+
+	_, _, clientErr, serverErr := secureConnect(client, server)
+	require.NoError(t, clientErr)
+	require.NoError(t, serverErr)
+
+	// client falls back to full authentication and caches new session
+	restarted := newTestSecurity(t, bob, time.Minute, alice)
+	_, _, clientErr, serverErr = secureConnect(client, restarted)
+	require.NoError(t, clientErr)
+	require.NoError(t, serverErr)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&alice.authorized))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&bob.authorized))
+
+	_, _, clientErr, serverErr = secureConnect(client, restarted)
+	require.NoError(t, clientErr)
+	require.NoError(t, serverErr)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&alice.authorized))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&bob.authorized))
+}
+
+func TestSecurity_RejectsForeignReference(t *testing.T) {
+	alice, bob, mallory := newTestPeer(t), newTestPeer(t), newTestPeer(t)
+	// mallory claims alice reference but signs with own key
+	mallory.ref = alice.ref
+	client := newTestSecurity(t, mallory, time.Minute, bob)
+	server := newTestSecurity(t, bob, time.Minute, alice)
+
+	_, _, _, serverErr := secureConnect(client, server)
+	require.Error(t, serverErr)
+	assert.Contains(t, serverErr.Error(), "key mismatch")
+}
+
+func TestSecurity_RejectsUnknownServer(t *testing.T) {
+	alice, bob := newTestPeer(t), newTestPeer(t)
+	client := newTestSecurity(t, alice, time.Minute)
+	server := newTestSecurity(t, bob, time.Minute, alice)
+
+	_, _, clientErr, _ := secureConnect(client, server)
+	require.Error(t, clientErr)
+	assert.Contains(t, clientErr.Error(), "unknown peer")
+}
+
+func TestSecurity_BindsAuthenticatedPeer(t *testing.T) {
+	alice, bob := newTestPeer(t), newTestPeer(t)
+	client := newTestSecurity(t, alice, time.Minute, bob)
+	server := newTestSecurity(t, bob, time.Minute, alice)
+
+	clientConn, serverConn, clientErr, serverErr := secureConnect(client, server)
+	require.NoError(t, clientErr)
+	require.NoError(t, serverErr)
+
+	peer, ok := authenticatedPeer(clientConn)
+	require.True(t, ok)
+	assert.Equal(t, bob.ref, peer)
+	peer, ok = authenticatedPeer(serverConn)
+	require.True(t, ok)
+	assert.Equal(t, alice.ref, peer)
+
+	_, ok = authenticatedPeer(&net.TCPConn{})
+	assert.False(t, ok)
+}
+
+func TestSecurity_RejectsUnexpectedServer(t *testing.T) {
+	alice, bob, carol := newTestPeer(t), newTestPeer(t), newTestPeer(t)
+	client := newTestSecurity(t, alice, time.Minute, bob, carol)
+	server := newTestSecurity(t, carol, time.Minute, alice)
+
+	assert.False(t, client.ExpectPeer("peer", bob.ref))
+	_, _, clientErr, _ := secureConnect(client, server)
+	assert.Error(t, clientErr)
+
+	assert.False(t, client.ExpectPeer("peer", carol.ref))
+	_, _, clientErr, serverErr := secureConnect(client, server)
+	require.NoError(t, clientErr)
+	require.NoError(t, serverErr)
+
+	assert.True(t, client.ExpectPeer("peer", bob.ref))
+}
@@ -0,0 +1,625 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package transport
+
+import (
+	"bytes"
+	"container/heap"
+	"context"
+	"io"
+	"math/rand"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/insolar/insolar/configuration"
+	consensus "github.com/insolar/insolar/consensus/packets"
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/instrumentation/inslogger"
+	"github.com/insolar/insolar/log"
+	"github.com/insolar/insolar/metrics"
+	"github.com/insolar/insolar/network"
+	"github.com/insolar/insolar/network/transport/host"
+	"github.com/insolar/insolar/network/transport/packet"
+	"github.com/insolar/insolar/network/transport/relay"
+)
+
+// SimulatedProtocol is configuration.Transport.Protocol of transport over DefaultSimulatedNetwork.
+const SimulatedProtocol = "SIMULATED"
+
+const simulatedFirstPort = 30000
+
+// LinkConditions describes faults of packets sent from one node to another.
+type LinkConditions struct {
+	Latency      time.Duration // delay of every packet
+	Jitter       time.Duration // random extra delay up to Jitter
+	Loss         float64       // probability to drop a packet
+	Duplicate    float64       // probability to deliver a packet twice
+	Reorder      float64       // probability to delay a packet by ReorderDelay, so next packets overtake it
+	ReorderDelay time.Duration
+	Partitioned  bool // drop all packets
+}
+
+// LinkRule applies Conditions to packets sent from node From to node To while simulated pulse is
+// in [FromPulse, ToPulse]. Zero references match any node, zero pulses are open bounds.
+type LinkRule struct {
+	From       core.RecordRef
+	To         core.RecordRef
+	FromPulse  core.PulseNumber
+	ToPulse    core.PulseNumber
+	Conditions LinkConditions
+}
+
+func (r *LinkRule) matches(from, to core.RecordRef, pulse core.PulseNumber) bool {
+	var any core.RecordRef
+	return (r.From == any || r.From == from) && (r.To == any || r.To == to) &&
+		(r.FromPulse == 0 || pulse >= r.FromPulse) && (r.ToPulse == 0 || pulse <= r.ToPulse)
+}
+
+// SimulationStats counts packets passed through SimulatedNetwork.
+type SimulationStats struct {
+	Sent       int
+	Delivered  int
+	Dropped    int
+	Duplicated int
+}
+
+// simulatedEvent is a packet waiting for delivery at virtual time at.
+type simulatedEvent struct {
+	at       time.Duration
+	seq      uint64
+	receiver *simulatedTransport
+	data     []byte
+}
+
+// eventQueue orders events by delivery time, events with the same time are delivered in order they were sent.
+type eventQueue []*simulatedEvent
+
+func (q eventQueue) Len() int { return len(q) }
+
+func (q eventQueue) Less(i, j int) bool {
+	if q[i].at != q[j].at {
+		return q[i].at < q[j].at
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q eventQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *eventQueue) Push(x interface{}) { *q = append(*q, x.(*simulatedEvent)) }
+
+func (q *eventQueue) Pop() interface{} {
+	old := *q
+	e := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return e
+}
+
+// SimulatedNetwork is an in-process network for tests. Transports with SimulatedProtocol send packets through it
+// instead of sockets, and link rules inject latency, loss, reordering, duplication and partitions between nodes.
+//
+// Sent packets are put into queue ordered by virtual delivery time and then by send order. Fault decisions and
+// delays are made by random source with provided seed, so the same sequence of sends gives the same deliveries.
+// By default virtual clock follows wall clock. After StopClock it moves only by Advance, so a test driving
+// a scenario from one goroutine gets the same packets in the same order on every run. Every node handles its
+// packets one by one in delivery order, but different nodes handle them concurrently, so scenarios where nodes
+// send on their own, like service network bootstrap, are reproducible by faults rather than by exact packet order.
+type SimulatedNetwork struct {
+	mutex    sync.Mutex
+	random   *rand.Rand
+	nodes    map[string]*simulatedTransport
+	rules    map[int]*LinkRule
+	order    []int
+	nextRule int
+	nextPort int
+	pulse    core.PulseNumber
+	stats    SimulationStats
+
+	queue   eventQueue
+	nextSeq uint64
+	clock   time.Duration // virtual time of the last clock update
+	epoch   time.Time     // wall time of zero virtual time while clock is running
+	stopped bool
+
+	dispatchMutex sync.Mutex
+}
+
+var defaultSimulatedNetwork = NewSimulatedNetwork(1)
+
+// DefaultSimulatedNetwork returns network used by transports created with SimulatedProtocol.
+func DefaultSimulatedNetwork() *SimulatedNetwork {
+	return defaultSimulatedNetwork
+}
+
+// NewSimulatedNetwork creates SimulatedNetwork with provided random seed.
+func NewSimulatedNetwork(seed int64) *SimulatedNetwork {
+	return &SimulatedNetwork{
+		random:   rand.New(rand.NewSource(seed)),
+		nodes:    make(map[string]*simulatedTransport),
+		rules:    make(map[int]*LinkRule),
+		nextPort: simulatedFirstPort,
+		epoch:    time.Now(),
+	}
+}
+
+// Reset removes all rules, statistics, pulse and pending packets, restarts virtual clock following wall clock
+// and reseeds random source. Transports stay attached.
+func (n *SimulatedNetwork) Reset(seed int64) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	n.random = rand.New(rand.NewSource(seed))
+	n.rules = make(map[int]*LinkRule)
+	n.order = nil
+	n.pulse = 0
+	n.stats = SimulationStats{}
+	n.queue = nil
+	n.nextSeq = 0
+	n.clock = 0
+	n.epoch = time.Now()
+	n.stopped = false
+}
+
+// StopClock stops virtual clock, after that packets are delivered only by Advance.
+func (n *SimulatedNetwork) StopClock() {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	n.updateClock()
+	n.stopped = true
+}
+
+// Advance moves virtual clock forward by d and delivers packets which are due by then.
+func (n *SimulatedNetwork) Advance(d time.Duration) {
+	n.mutex.Lock()
+	n.updateClock()
+	n.clock += d
+	if !n.stopped {
+		n.epoch = n.epoch.Add(-d)
+	}
+	n.mutex.Unlock()
+
+	n.dispatch()
+}
+
+// updateClock moves virtual clock to wall clock if it's running. Should be called under mutex.
+func (n *SimulatedNetwork) updateClock() {
+	if n.stopped {
+		return
+	}
+	if now := time.Since(n.epoch); now > n.clock {
+		n.clock = now
+	}
+}
+
+// AddRule adds link rule and returns its id. When several rules match a packet, the last added one is applied.
+func (n *SimulatedNetwork) AddRule(rule LinkRule) int {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	n.nextRule++
+	n.rules[n.nextRule] = &rule
+	n.order = append(n.order, n.nextRule)
+	return n.nextRule
+}
+
+// RemoveRule removes link rule by id.
+func (n *SimulatedNetwork) RemoveRule(id int) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	delete(n.rules, id)
+	for i, ruleID := range n.order {
+		if ruleID == id {
+			n.order = append(n.order[:i], n.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// Partition disconnects every node of one group from every node of another one and returns ids of added rules.
+func (n *SimulatedNetwork) Partition(one, another []core.RecordRef) []int {
+	var ids []int
+	for _, a := range one {
+		for _, b := range another {
+			ids = append(ids, n.AddRule(LinkRule{From: a, To: b, Conditions: LinkConditions{Partitioned: true}}))
+			ids = append(ids, n.AddRule(LinkRule{From: b, To: a, Conditions: LinkConditions{Partitioned: true}}))
+		}
+	}
+	return ids
+}
+
+// SetPulse sets current simulated pulse for pulse bounded rules. Pulse also advances when pulse packets are delivered.
+func (n *SimulatedNetwork) SetPulse(pulse core.PulseNumber) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	n.pulse = pulse
+}
+
+// Pulse returns current simulated pulse.
+func (n *SimulatedNetwork) Pulse() core.PulseNumber {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	return n.pulse
+}
+
+// Stats returns packets statistics since last Reset.
+func (n *SimulatedNetwork) Stats() SimulationStats {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	return n.stats
+}
+
+func (n *SimulatedNetwork) attach(t *simulatedTransport, address string) (string, error) {
+	addr, err := net.ResolveUDPAddr("udp", address)
+	if err != nil {
+		return "", errors.Wrap(err, "[ attach ] failed to resolve address")
+	}
+
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	if addr.Port == 0 {
+		for {
+			addr.Port = n.nextPort
+			n.nextPort++
+			if _, ok := n.nodes[addr.String()]; !ok {
+				break
+			}
+		}
+	}
+	address = addr.String()
+	if _, ok := n.nodes[address]; ok {
+		return "", errors.Errorf("[ attach ] address %s is already in use", address)
+	}
+	n.nodes[address] = t
+	return address, nil
+}
+
+func (n *SimulatedNetwork) attached(t *simulatedTransport) bool {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	return n.nodes[t.address] == t
+}
+
+func (n *SimulatedNetwork) detach(t *simulatedTransport) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	if n.nodes[t.address] == t {
+		delete(n.nodes, t.address)
+	}
+}
+
+// transmit queues delivery of data to address according to rules of link between from and to.
+func (n *SimulatedNetwork) transmit(from, to core.RecordRef, address string, data []byte) error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	receiver, ok := n.nodes[address]
+	if !ok {
+		return errors.Errorf("[ transmit ] no simulated host at %s", address)
+	}
+	n.stats.Sent++
+
+	var conditions LinkConditions
+	for i := len(n.order) - 1; i >= 0; i-- {
+		rule := n.rules[n.order[i]]
+		if rule.matches(from, to, n.pulse) {
+			conditions = rule.Conditions
+			break
+		}
+	}
+
+	if conditions.Partitioned || n.random.Float64() < conditions.Loss {
+		n.stats.Dropped++
+		return nil
+	}
+	copies := 1
+	if n.random.Float64() < conditions.Duplicate {
+		copies = 2
+		n.stats.Duplicated++
+	}
+	n.updateClock()
+	for i := 0; i < copies; i++ {
+		delay := conditions.Latency
+		if conditions.Jitter > 0 {
+			delay += time.Duration(n.random.Int63n(int64(conditions.Jitter)))
+		}
+		if n.random.Float64() < conditions.Reorder {
+			delay += conditions.ReorderDelay
+		}
+		heap.Push(&n.queue, &simulatedEvent{at: n.clock + delay, seq: n.nextSeq, receiver: receiver, data: data})
+		n.nextSeq++
+		if !n.stopped {
+			// Timer only wakes dispatching up, packets are taken in queue order.
+			time.AfterFunc(delay, n.dispatch)
+		}
+	}
+	return nil
+}
+
+// dispatch delivers due packets in queue order.
+func (n *SimulatedNetwork) dispatch() {
+	n.dispatchMutex.Lock()
+	defer n.dispatchMutex.Unlock()
+
+	for {
+		event := n.nextDue()
+		if event == nil {
+			return
+		}
+		n.deliver(event.receiver, event.data)
+	}
+}
+
+func (n *SimulatedNetwork) nextDue() *simulatedEvent {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	n.updateClock()
+	if len(n.queue) == 0 || n.queue[0].at > n.clock {
+		return nil
+	}
+	return heap.Pop(&n.queue).(*simulatedEvent)
+}
+
+func (n *SimulatedNetwork) deliver(receiver *simulatedTransport, data []byte) {
+	msg, err := receiver.serializer.DeserializePacket(bytes.NewReader(data))
+	if err != nil {
+		log.Error("[ deliver ] failed to deserialize packet: ", err)
+		return
+	}
+
+	listening := receiver.isListening()
+
+	n.mutex.Lock()
+	if !listening || n.nodes[receiver.address] != receiver {
+		n.stats.Dropped++
+		n.mutex.Unlock()
+		return
+	}
+	n.stats.Delivered++
+	if pulse, ok := msg.Data.(*packet.RequestPulse); ok && pulse.Pulse.PulseNumber > n.pulse {
+		n.pulse = pulse.Pulse.PulseNumber
+	}
+	n.mutex.Unlock()
+
+	receiver.enqueue(msg)
+}
+
+// simulatedTransport sends packets through SimulatedNetwork. It keeps sender and receiver references of packets
+// for link rules, so it implements sending itself instead of baseTransport.sendFunc.
+type simulatedTransport struct {
+	baseTransport
+	network *SimulatedNetwork
+	address string
+
+	listening bool
+	stop      chan struct{}
+
+	inboxMutex sync.Mutex
+	inbox      []*packet.Packet
+	handling   bool
+}
+
+func newSimulatedTransport(network *SimulatedNetwork, address string, proxy relay.Proxy) (*simulatedTransport, error) {
+	transport := &simulatedTransport{network: network, stop: make(chan struct{})}
+	address, err := network.attach(transport, address)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ newSimulatedTransport ] failed to attach to simulated network")
+	}
+	transport.address = address
+	transport.baseTransport = newBaseTransport(proxy, address)
+	transport.serializer = &simulatedSerializer{}
+	transport.sendFunc = func(string, []byte) error {
+		return errors.New("simulated transport sends packets with references only")
+	}
+	return transport, nil
+}
+
+// SendRequest sends request packet and returns future.
+func (t *simulatedTransport) SendRequest(ctx context.Context, msg *packet.Packet) (Future, error) {
+	future := t.futureManager.Create(msg)
+	err := t.SendPacket(ctx, msg)
+	if err != nil {
+		future.Cancel()
+		return nil, errors.Wrap(err, "Failed to send transport packet")
+	}
+	metrics.NetworkPacketSentTotal.WithLabelValues(msg.Type.String()).Inc()
+	return future, nil
+}
+
+// SendResponse sends response packet.
+func (t *simulatedTransport) SendResponse(ctx context.Context, requestID network.RequestID, msg *packet.Packet) error {
+	msg.RequestID = requestID
+
+	return t.SendPacket(ctx, msg)
+}
+
+// SendPacket sends packet through simulated network.
+func (t *simulatedTransport) SendPacket(ctx context.Context, p *packet.Packet) error {
+	var recvAddress string
+	if t.proxy.ProxyHostsCount() > 0 {
+		recvAddress = t.proxy.GetNextProxyAddress()
+	}
+	if len(recvAddress) == 0 {
+		recvAddress = p.Receiver.Address.String()
+	}
+
+	data, err := t.serializer.SerializePacket(p)
+	if err != nil {
+		return errors.Wrap(err, "Failed to serialize packet")
+	}
+
+	var from core.RecordRef
+	if p.Sender != nil {
+		from = p.Sender.NodeID
+	}
+	inslogger.FromContext(ctx).Debugf("Send %s packet to %s with RequestID = %d", p.Type, recvAddress, p.RequestID)
+	return t.network.transmit(from, p.Receiver.NodeID, recvAddress, data)
+}
+
+// enableFlowControl is not supported, simulated network applies its own link rules.
+func (t *simulatedTransport) enableFlowControl(configuration.FlowControl) {}
+
+func (t *simulatedTransport) prepareListen() error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.listening {
+		return errors.New("simulated transport is already listening")
+	}
+	if !t.network.attached(t) {
+		if _, err := t.network.attach(t, t.address); err != nil {
+			return err
+		}
+	}
+	t.disconnectStarted = make(chan bool, 1)
+	t.disconnectFinished = make(chan bool, 1)
+	t.stop = make(chan struct{})
+	t.listening = true
+	return nil
+}
+
+// Listen starts receiving packets from simulated network.
+func (t *simulatedTransport) Listen(ctx context.Context, started chan struct{}) error {
+	logger := inslogger.FromContext(ctx)
+	logger.Info("[ Listen ] Start simulated transport on ", t.address)
+	if err := t.prepareListen(); err != nil {
+		logger.Info("[ Listen ] Failed to prepare simulated transport")
+		return errors.Wrap(err, "[ Listen ] Failed to attach to simulated network")
+	}
+
+	started <- struct{}{}
+	<-t.stop
+	<-t.disconnectFinished
+	return nil
+}
+
+// Stop stops receiving packets and releases simulated address.
+func (t *simulatedTransport) Stop() {
+	t.network.detach(t)
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	log.Info("[ Stop ] Stop simulated transport")
+	t.prepareDisconnect()
+	if t.listening {
+		t.listening = false
+		close(t.stop)
+	}
+}
+
+// enqueue adds delivered packet to inbox. Packets of inbox are handled one by one in order they were delivered,
+// so blocked handler doesn't stop deliveries to other transports.
+func (t *simulatedTransport) enqueue(msg *packet.Packet) {
+	t.inboxMutex.Lock()
+	defer t.inboxMutex.Unlock()
+
+	t.inbox = append(t.inbox, msg)
+	if !t.handling {
+		t.handling = true
+		go t.handleInbox()
+	}
+}
+
+func (t *simulatedTransport) handleInbox() {
+	for {
+		t.inboxMutex.Lock()
+		if len(t.inbox) == 0 {
+			t.handling = false
+			t.inboxMutex.Unlock()
+			return
+		}
+		msg := t.inbox[0]
+		t.inbox = t.inbox[1:]
+		t.inboxMutex.Unlock()
+
+		t.packetHandler.Handle(context.Background(), msg)
+	}
+}
+
+func (t *simulatedTransport) isListening() bool {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.listening
+}
+
+const (
+	simulatedHostFrame byte = iota
+	simulatedConsensusFrame
+)
+
+// simulatedSerializer encodes consensus packets like UDP transport and other packets like TCP one,
+// so the same simulated network carries host and consensus traffic.
+type simulatedSerializer struct {
+	baseSerializer
+	udp udpSerializer
+}
+
+func (s *simulatedSerializer) SerializePacket(q *packet.Packet) ([]byte, error) {
+	if _, ok := q.Data.(consensus.ConsensusPacket); ok {
+		data, err := s.udp.SerializePacket(q)
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte{simulatedConsensusFrame}, data...), nil
+	}
+	data, err := s.baseSerializer.SerializePacket(q)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{simulatedHostFrame}, data...), nil
+}
+
+func (s *simulatedSerializer) DeserializePacket(conn io.Reader) (*packet.Packet, error) {
+	var frame [1]byte
+	if _, err := io.ReadFull(conn, frame[:]); err != nil {
+		return nil, err
+	}
+	switch frame[0] {
+	case simulatedHostFrame:
+		return s.baseSerializer.DeserializePacket(conn)
+	case simulatedConsensusFrame:
+		data, err := consensus.ExtractPacket(conn)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not extract ConsensusPacket")
+		}
+		header, err := data.GetPacketHeader()
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get routing information from ConsensusPacket")
+		}
+		return &packet.Packet{
+			Sender:   &host.Host{ShortID: header.OriginID},
+			Receiver: &host.Host{ShortID: header.TargetID},
+			Type:     header.PacketType,
+			Data:     data,
+		}, nil
+	default:
+		return nil, errors.New("unknown simulated frame " + strconv.Itoa(int(frame[0])))
+	}
+}
@@ -0,0 +1,232 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package transport
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/network/transport/host"
+	"github.com/insolar/insolar/network/transport/packet"
+	"github.com/insolar/insolar/network/transport/packet/types"
+	"github.com/insolar/insolar/network/transport/relay"
+	"github.com/insolar/insolar/testutils"
+)
+
+type simulatedNode struct {
+	transport *simulatedTransport
+	host      *host.Host
+}
+
+func newSimulatedNode(t *testing.T, network *SimulatedNetwork) *simulatedNode {
+	tp, err := newSimulatedTransport(network, "127.0.0.1:0", relay.NewProxy())
+	require.NoError(t, err)
+	h, err := host.NewHostN(tp.PublicAddress(), testutils.RandomRef())
+	require.NoError(t, err)
+
+	ListenAndWaitUntilReady(context.Background(), tp)
+	return &simulatedNode{transport: tp, host: h}
+}
+
+func (n *simulatedNode) stop() {
+	go n.transport.Stop()
+	<-n.transport.Stopped()
+	n.transport.Close()
+}
+
+func (n *simulatedNode) send(t *testing.T, receiver *simulatedNode, data interface{}) {
+	msg := packet.NewBuilder(n.host).Receiver(receiver.host).Type(packet.TestPacket).Request(data).Build()
+	_, err := n.transport.SendRequest(context.Background(), msg)
+	require.NoError(t, err)
+}
+
+func receive(n *simulatedNode, timeout time.Duration) *packet.Packet {
+	select {
+	case msg := <-n.transport.Packets():
+		return msg
+	case <-time.After(timeout):
+		return nil
+	}
+}
+
+func TestSimulatedNetwork_Partition(t *testing.T) {
+	network := NewSimulatedNetwork(1)
+	node1, node2 := newSimulatedNode(t, network), newSimulatedNode(t, network)
+	defer node1.stop()
+	defer node2.stop()
+
+	rules := network.Partition([]core.RecordRef{node1.host.NodeID}, []core.RecordRef{node2.host.NodeID})
+	node1.send(t, node2, &packet.RequestTest{Data: []byte("lost")})
+	assert.Nil(t, receive(node2, 100*time.Millisecond))
+	assert.Equal(t, SimulationStats{Sent: 1, Dropped: 1}, network.Stats())
+
+	for _, id := range rules {
+		network.RemoveRule(id)
+	}
+	node1.send(t, node2, &packet.RequestTest{Data: []byte("delivered")})
+	msg := receive(node2, time.Second)
+	require.NotNil(t, msg)
+	assert.Equal(t, []byte("delivered"), msg.Data.(*packet.RequestTest).Data)
+}
+
+func TestSimulatedNetwork_UnknownAddress(t *testing.T) {
+	network := NewSimulatedNetwork(1)
+	node := newSimulatedNode(t, network)
+	defer node.stop()
+
+	h, err := host.NewHostN("127.0.0.1:1", testutils.RandomRef())
+	require.NoError(t, err)
+	msg := packet.NewBuilder(node.host).Receiver(h).Type(types.Ping).Build()
+	_, err = node.transport.SendRequest(context.Background(), msg)
+	assert.Error(t, err)
+}
+
+func TestSimulatedNetwork_AddressInUse(t *testing.T) {
+	network := NewSimulatedNetwork(1)
+	node := newSimulatedNode(t, network)
+	defer node.stop()
+
+	_, err := newSimulatedTransport(network, node.transport.PublicAddress(), relay.NewProxy())
+	assert.Error(t, err)
+}
+
+func TestSimulatedNetwork_Duplicate(t *testing.T) {
+	network := NewSimulatedNetwork(1)
+	node1, node2 := newSimulatedNode(t, network), newSimulatedNode(t, network)
+	defer node1.stop()
+	defer node2.stop()
+
+	network.AddRule(LinkRule{Conditions: LinkConditions{Duplicate: 1}})
+	node1.send(t, node2, &packet.RequestTest{Data: []byte("twice")})
+	assert.NotNil(t, receive(node2, time.Second))
+	assert.NotNil(t, receive(node2, time.Second))
+	assert.Equal(t, SimulationStats{Sent: 1, Delivered: 2, Duplicated: 1}, network.Stats())
+}
+
+func TestSimulatedNetwork_Latency(t *testing.T) {
+	network := NewSimulatedNetwork(1)
+	node1, node2 := newSimulatedNode(t, network), newSimulatedNode(t, network)
+	defer node1.stop()
+	defer node2.stop()
+
+	network.AddRule(LinkRule{From: node1.host.NodeID, Conditions: LinkConditions{Latency: 200 * time.Millisecond}})
+	start := time.Now()
+	node1.send(t, node2, &packet.RequestTest{Data: []byte("slow")})
+	require.NotNil(t, receive(node2, time.Second))
+	assert.True(t, time.Since(start) >= 200*time.Millisecond)
+
+	start = time.Now()
+	node2.send(t, node1, &packet.RequestTest{Data: []byte("fast")})
+	require.NotNil(t, receive(node1, time.Second))
+	assert.True(t, time.Since(start) < 200*time.Millisecond)
+}
+
+func TestSimulatedNetwork_Reorder(t *testing.T) {
+	network := NewSimulatedNetwork(1)
+	node1, node2 := newSimulatedNode(t, network), newSimulatedNode(t, network)
+	defer node1.stop()
+	defer node2.stop()
+
+	rule := network.AddRule(LinkRule{Conditions: LinkConditions{Reorder: 1, ReorderDelay: 200 * time.Millisecond}})
+	node1.send(t, node2, &packet.RequestTest{Data: []byte("first")})
+	network.RemoveRule(rule)
+	node1.send(t, node2, &packet.RequestTest{Data: []byte("second")})
+
+	msg := receive(node2, time.Second)
+	require.NotNil(t, msg)
+	assert.Equal(t, []byte("second"), msg.Data.(*packet.RequestTest).Data)
+	msg = receive(node2, time.Second)
+	require.NotNil(t, msg)
+	assert.Equal(t, []byte("first"), msg.Data.(*packet.RequestTest).Data)
+}
+
+func TestSimulatedNetwork_PulseRule(t *testing.T) {
+	network := NewSimulatedNetwork(1)
+	node1, node2 := newSimulatedNode(t, network), newSimulatedNode(t, network)
+	defer node1.stop()
+	defer node2.stop()
+
+	network.AddRule(LinkRule{FromPulse: 10, ToPulse: 20, Conditions: LinkConditions{Partitioned: true}})
+	network.SetPulse(5)
+	node1.send(t, node2, &packet.RequestPulse{Pulse: core.Pulse{PulseNumber: 12}})
+	require.NotNil(t, receive(node2, time.Second))
+	assert.Equal(t, core.PulseNumber(12), network.Pulse())
+
+	node1.send(t, node2, &packet.RequestTest{Data: []byte("lost")})
+	assert.Nil(t, receive(node2, 100*time.Millisecond))
+
+	network.SetPulse(21)
+	node1.send(t, node2, &packet.RequestTest{Data: []byte("delivered")})
+	assert.NotNil(t, receive(node2, time.Second))
+}
+
+func TestSimulatedNetwork_LossIsReproducible(t *testing.T) {
+	run := func() []bool {
+		network := NewSimulatedNetwork(42)
+		node1, node2 := newSimulatedNode(t, network), newSimulatedNode(t, network)
+		defer node1.stop()
+		defer node2.stop()
+
+		network.AddRule(LinkRule{Conditions: LinkConditions{Loss: 0.5}})
+		var delivered []bool
+		for i := 0; i < 20; i++ {
+			node1.send(t, node2, &packet.RequestTest{Data: []byte{byte(i)}})
+			delivered = append(delivered, receive(node2, 50*time.Millisecond) != nil)
+		}
+		return delivered
+	}
+
+	first := run()
+	assert.Contains(t, first, true)
+	assert.Contains(t, first, false)
+	assert.Equal(t, first, run())
+}
+
+func TestSimulatedNetwork_StoppedClock(t *testing.T) {
+	run := func() []byte {
+		network := NewSimulatedNetwork(42)
+		network.StopClock()
+		node1, node2 := newSimulatedNode(t, network), newSimulatedNode(t, network)
+		defer node1.stop()
+		defer node2.stop()
+
+		network.AddRule(LinkRule{Conditions: LinkConditions{Latency: time.Second, Jitter: time.Second}})
+		for i := 0; i < 10; i++ {
+			node1.send(t, node2, &packet.RequestTest{Data: []byte{byte(i)}})
+		}
+		network.Advance(time.Second - 1)
+		assert.Nil(t, receive(node2, 50*time.Millisecond))
+
+		network.Advance(time.Second)
+		var order []byte
+		for i := 0; i < 10; i++ {
+			msg := receive(node2, time.Second)
+			require.NotNil(t, msg)
+			order = append(order, msg.Data.(*packet.RequestTest).Data...)
+		}
+		return order
+	}
+
+	first := run()
+	assert.NotEqual(t, []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, first)
+	assert.Equal(t, first, run())
+}
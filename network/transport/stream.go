@@ -0,0 +1,122 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package transport
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/insolar/insolar/network/transport/packet"
+)
+
+// Stream is a bidirectional byte stream to remote host, multiplexed with other streams and packets.
+// Write blocks while remote side doesn't read written data. Stream is not safe for concurrent writes or reads.
+type Stream interface {
+	io.ReadWriteCloser
+
+	// CloseWrite closes writing side of the stream, remote side reads io.EOF after all written data.
+	CloseWrite() error
+
+	// SetDeadline sets deadline for Read and Write calls.
+	SetDeadline(t time.Time) error
+
+	// Packet returns packet the stream was opened with.
+	Packet() *packet.Packet
+}
+
+// ErrStreamsNotSupported is returned by OpenStream of transports without streams.
+var ErrStreamsNotSupported = errors.New("transport does not support streams")
+
+// streamPreamble starts TCP connections and QUIC streams which carry streams instead of packets.
+// It could not be confused with packet length prefix, packets are never that large.
+var streamPreamble = []byte{0xff, 'S', 'T', 'M'}
+
+// readStreamPreamble checks if reader starts with streamPreamble and skips it.
+func readStreamPreamble(reader *bufio.Reader) (bool, error) {
+	prefix, err := reader.Peek(len(streamPreamble))
+	if err != nil {
+		return false, err
+	}
+	if !bytes.Equal(prefix, streamPreamble) {
+		return false, nil
+	}
+	_, err = reader.Discard(len(streamPreamble))
+	return true, err
+}
+
+// OpenStream returns ErrStreamsNotSupported, transports with streams override it.
+func (t *baseTransport) OpenStream(ctx context.Context, p *packet.Packet) (Stream, error) {
+	return nil, ErrStreamsNotSupported
+}
+
+// flowStream is an outgoing stream which writes are limited by transport flow control. Stream data is
+// written after queued packets of higher priority and it shares bandwidth limit with packets to the peer.
+type flowStream struct {
+	Stream
+
+	ctx      context.Context
+	flow     *flowController
+	address  string
+	priority Priority
+}
+
+func (s *flowStream) Write(p []byte) (int, error) {
+	written := 0
+	for written < len(p) {
+		n := len(p) - written
+		if n > streamFrameSize {
+			n = streamFrameSize
+		}
+		if err := s.flow.Acquire(s.ctx, s.address, s.priority, n); err != nil {
+			return written, err
+		}
+		n, err := s.Stream.Write(p[written : written+n])
+		written += n
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// limitStream applies flow control of the transport to outgoing stream opened with packet p.
+func (t *baseTransport) limitStream(ctx context.Context, address string, p *packet.Packet, stream Stream) Stream {
+	if t.flow == nil {
+		return stream
+	}
+	return &flowStream{
+		Stream:   stream,
+		ctx:      ctx,
+		flow:     t.flow,
+		address:  address,
+		priority: packetPriority(ctx, p),
+	}
+}
+
+// Streams returns incoming streams channel.
+func (t *baseTransport) Streams() <-chan Stream {
+	return t.streams
+}
+
+func (t *baseTransport) acceptStream(stream Stream) {
+	t.streams <- stream
+}
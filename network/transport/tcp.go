@@ -17,14 +17,18 @@
 package transport
 
 import (
+	"bufio"
 	"context"
 	"io"
 	"net"
+	"sync"
 
 	"github.com/pkg/errors"
 
+	"github.com/insolar/insolar/core"
 	"github.com/insolar/insolar/instrumentation/inslogger"
 	"github.com/insolar/insolar/log"
+	"github.com/insolar/insolar/network/transport/packet"
 	"github.com/insolar/insolar/network/transport/pool"
 	"github.com/insolar/insolar/network/transport/relay"
 	"github.com/insolar/insolar/network/utils"
@@ -36,20 +40,66 @@ type tcpTransport struct {
 	pool     pool.ConnectionPool
 	listener net.Listener
 	addr     string
+	security *Security
+	factory  *tcpConnectionFactory
+
+	sessionsMutex sync.Mutex
+	sessions      map[string]*muxSession
+	accepted      map[*muxSession]struct{}
 }
 
 func newTCPTransport(addr string, proxy relay.Proxy, publicAddress string) (*tcpTransport, error) {
+	factory := &tcpConnectionFactory{}
+	transport := &tcpTransport{
+		baseTransport: newBaseTransport(proxy, publicAddress),
+		addr:          addr,
+		pool:          pool.NewConnectionPool(factory),
+		factory:       factory,
+		sessions:      make(map[string]*muxSession),
+		accepted:      make(map[*muxSession]struct{}),
+	}
+
+	transport.sendFunc = transport.send
+
+	return transport, nil
+}
+
+func newTLSTransport(addr string, proxy relay.Proxy, publicAddress string, security *Security) (*tcpTransport, error) {
+	factory := &tcpConnectionFactory{security: security}
 	transport := &tcpTransport{
 		baseTransport: newBaseTransport(proxy, publicAddress),
 		addr:          addr,
-		pool:          pool.NewConnectionPool(&tcpConnectionFactory{}),
+		pool:          pool.NewConnectionPool(factory),
+		security:      security,
+		factory:       factory,
+		sessions:      make(map[string]*muxSession),
+		accepted:      make(map[*muxSession]struct{}),
 	}
 
 	transport.sendFunc = transport.send
+	transport.expectPeer = transport.expectSecuredPeer
 
 	return transport, nil
 }
 
+// expectSecuredPeer makes connections to address authenticate provided node, connections authenticated with
+// other node are closed.
+func (t *tcpTransport) expectSecuredPeer(address string, ref core.RecordRef) {
+	if !t.security.ExpectPeer(address, ref) {
+		return
+	}
+	log.Warnf("[ expectSecuredPeer ] Node on %s is changed to %s, reconnecting", address, ref)
+	if addr, err := net.ResolveTCPAddr("tcp", address); err == nil {
+		t.pool.CloseConnection(context.Background(), addr)
+	}
+	t.sessionsMutex.Lock()
+	session := t.sessions[address]
+	t.sessionsMutex.Unlock()
+	if session != nil {
+		utils.CloseVerbose(session)
+	}
+}
+
 func (t *tcpTransport) send(address string, data []byte) error {
 	ctx := context.Background()
 	logger := inslogger.FromContext(ctx)
@@ -139,13 +189,129 @@ func (t *tcpTransport) Stop() {
 
 	utils.CloseVerbose(t.listener)
 	t.pool.Reset()
+	t.closeSessions()
+}
+
+// OpenStream opens stream over connection dedicated to streams to the packet receiver.
+func (t *tcpTransport) OpenStream(ctx context.Context, p *packet.Packet) (Stream, error) {
+	address := p.Receiver.Address.String()
+	if t.expectPeer != nil && !p.Receiver.NodeID.IsEmpty() {
+		t.expectPeer(address, p.Receiver.NodeID)
+	}
+	session, err := t.getSession(ctx, address)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ OpenStream ] Failed to get stream session")
+	}
+	stream, err := session.open(p)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ OpenStream ] Failed to open stream")
+	}
+	return t.limitStream(ctx, address, p, stream), nil
+}
+
+func (t *tcpTransport) getSession(ctx context.Context, address string) (*muxSession, error) {
+	t.sessionsMutex.Lock()
+	session, ok := t.sessions[address]
+	t.sessionsMutex.Unlock()
+	if ok && !session.closed() {
+		return session, nil
+	}
+
+	addr, err := net.ResolveTCPAddr("tcp", address)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ getSession ] Failed to resolve net address")
+	}
+	conn, err := t.factory.CreateConnection(ctx, addr)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ getSession ] Failed to create connection")
+	}
+	if _, err := conn.Write(streamPreamble); err != nil {
+		utils.CloseVerbose(conn)
+		return nil, errors.Wrap(err, "[ getSession ] Failed to write stream preamble")
+	}
+
+	session = newMuxSession(conn, bufio.NewReader(conn), nil)
+	session.onClose = func() {
+		t.sessionsMutex.Lock()
+		defer t.sessionsMutex.Unlock()
+		if t.sessions[address] == session {
+			delete(t.sessions, address)
+		}
+	}
+
+	t.sessionsMutex.Lock()
+	if existing, ok := t.sessions[address]; ok && !existing.closed() {
+		t.sessionsMutex.Unlock()
+		utils.CloseVerbose(session)
+		return existing, nil
+	}
+	t.sessions[address] = session
+	t.sessionsMutex.Unlock()
+
+	go session.serve()
+	return session, nil
+}
+
+func (t *tcpTransport) serveSession(conn net.Conn, reader io.Reader) {
+	peer, secured := authenticatedPeer(conn)
+	session := newMuxSession(conn, reader, func(stream *muxStream) {
+		if secured && !isSentBy(stream.Packet(), peer) {
+			log.Warnf("[ serveSession ] Drop stream: sender is not authenticated node %s", peer)
+			utils.CloseVerbose(stream)
+			return
+		}
+		t.acceptStream(stream)
+	})
+	session.onClose = func() {
+		t.sessionsMutex.Lock()
+		defer t.sessionsMutex.Unlock()
+		delete(t.accepted, session)
+	}
+
+	t.sessionsMutex.Lock()
+	t.accepted[session] = struct{}{}
+	t.sessionsMutex.Unlock()
+
+	session.serve()
+}
+
+func (t *tcpTransport) closeSessions() {
+	t.sessionsMutex.Lock()
+	var sessions []*muxSession
+	for _, session := range t.sessions {
+		sessions = append(sessions, session)
+	}
+	for session := range t.accepted {
+		sessions = append(sessions, session)
+	}
+	t.sessionsMutex.Unlock()
+
+	for _, session := range sessions {
+		utils.CloseVerbose(session)
+	}
 }
 
 func (t *tcpTransport) handleAcceptedConnection(conn net.Conn) {
+	if t.security != nil {
+		secured, err := t.security.Server(conn)
+		if err != nil {
+			log.Error("[ handleAcceptedConnection ] Failed to secure connection: ", err.Error())
+			utils.CloseVerbose(conn)
+			return
+		}
+		conn = secured
+	}
+
+	reader := bufio.NewReader(conn)
+	if isStream, _ := readStreamPreamble(reader); isStream {
+		t.serveSession(conn, reader)
+		return
+	}
 	defer utils.CloseVerbose(conn)
+	peer, secured := authenticatedPeer(conn)
 
 	for {
-		msg, err := t.serializer.DeserializePacket(conn)
+		msg, err := t.serializer.DeserializePacket(reader)
 
 		if err != nil {
 			if err == io.EOF || err == io.ErrUnexpectedEOF {
@@ -154,6 +320,8 @@ func (t *tcpTransport) handleAcceptedConnection(conn net.Conn) {
 			}
 
 			log.Error("[ handleAcceptedConnection ] Failed to deserialize packet: ", err.Error())
+		} else if secured && !isSentBy(msg, peer) {
+			log.Warnf("[ handleAcceptedConnection ] Drop packet %d: sender is not authenticated node %s", msg.RequestID, peer)
 		} else {
 			ctx, logger := inslogger.WithTraceField(context.Background(), msg.TraceID)
 			logger.Debug("[ handleAcceptedConnection ] Handling packet: ", msg.RequestID)
@@ -163,9 +331,16 @@ func (t *tcpTransport) handleAcceptedConnection(conn net.Conn) {
 	}
 }
 
-type tcpConnectionFactory struct{}
+// isSentBy checks that packet sender is the node.
+func isSentBy(p *packet.Packet, ref core.RecordRef) bool {
+	return p != nil && p.Sender != nil && p.Sender.NodeID == ref
+}
+
+type tcpConnectionFactory struct {
+	security *Security
+}
 
-func (*tcpConnectionFactory) CreateConnection(ctx context.Context, address net.Addr) (net.Conn, error) {
+func (f *tcpConnectionFactory) CreateConnection(ctx context.Context, address net.Addr) (net.Conn, error) {
 	logger := inslogger.FromContext(ctx)
 
 	tcpAddress, ok := address.(*net.TCPAddr)
@@ -189,5 +364,15 @@ func (*tcpConnectionFactory) CreateConnection(ctx context.Context, address net.A
 		logger.Errorln("[ createConnection ] Failed to set connection no delay: ", err.Error())
 	}
 
+	if f.security != nil {
+		secured, err := f.security.Client(conn, address.String())
+		if err != nil {
+			utils.CloseVerbose(conn)
+			logger.Errorf("[ createConnection ] Failed to secure connection to %s: %s", address, err.Error())
+			return nil, errors.Wrap(err, "[ createConnection ] Failed to secure connection")
+		}
+		return secured, nil
+	}
+
 	return conn, nil
 }
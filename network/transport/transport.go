@@ -54,6 +54,12 @@ type Transport interface {
 	// Packets returns channel to listen incoming packets.
 	Packets() <-chan *packet.Packet
 
+	// OpenStream opens stream to packet receiver, the packet is passed to remote side with the stream.
+	OpenStream(ctx context.Context, p *packet.Packet) (Stream, error)
+
+	// Streams returns channel to listen incoming streams.
+	Streams() <-chan Stream
+
 	// Stopped returns signal channel to support graceful shutdown.
 	Stopped() <-chan bool
 
@@ -63,6 +69,31 @@ type Transport interface {
 
 // NewTransport creates new Transport with particular configuration
 func NewTransport(cfg configuration.Transport, proxy relay.Proxy) (Transport, error) {
+	return NewSecureTransport(cfg, proxy, nil)
+}
+
+// NewSecureTransport creates new Transport with particular configuration, TLS protocol authenticates peers with security
+func NewSecureTransport(cfg configuration.Transport, proxy relay.Proxy, security *Security) (Transport, error) {
+	transport, err := newTransport(cfg, proxy, security)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.FlowControl.Enabled {
+		transport.enableFlowControl(cfg.FlowControl)
+	}
+	return transport, nil
+}
+
+type flowControlledTransport interface {
+	Transport
+	enableFlowControl(cfg configuration.FlowControl)
+}
+
+func newTransport(cfg configuration.Transport, proxy relay.Proxy, security *Security) (flowControlledTransport, error) {
+	if cfg.Protocol == SimulatedProtocol {
+		return newSimulatedTransport(DefaultSimulatedNetwork(), cfg.Address, proxy)
+	}
+
 	// TODO: let each transport creates connection in their constructor
 	conn, publicAddress, err := NewConnection(cfg)
 	if err != nil {
@@ -75,6 +106,13 @@ func NewTransport(cfg configuration.Transport, proxy relay.Proxy) (Transport, er
 		utils.CloseVerbose(conn)
 
 		return newTCPTransport(conn.LocalAddr().String(), proxy, publicAddress)
+	case "TLS":
+		utils.CloseVerbose(conn)
+		if security == nil {
+			return nil, errors.New("[ NewTransport ] TLS transport requires node security")
+		}
+
+		return newTLSTransport(conn.LocalAddr().String(), proxy, publicAddress, security)
 	case "PURE_UDP":
 		return newUDPTransport(conn, proxy, publicAddress)
 	case "QUIC":
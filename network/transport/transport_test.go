@@ -20,19 +20,26 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/gob"
+	"io/ioutil"
 	"testing"
+	"time"
 
 	"github.com/insolar/insolar/configuration"
 	consensus "github.com/insolar/insolar/consensus/packets"
+	"github.com/insolar/insolar/core"
 	"github.com/insolar/insolar/network/transport/host"
 	"github.com/insolar/insolar/network/transport/packet"
 	"github.com/insolar/insolar/network/transport/packet/types"
 	"github.com/insolar/insolar/network/transport/relay"
+	"github.com/insolar/insolar/testutils"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
 
 type node struct {
 	config    configuration.Transport
+	security  *Security
+	ref       core.RecordRef
 	transport Transport
 	host      *host.Host
 }
@@ -65,8 +72,9 @@ func setupNode(t *transportSuite, n *node) {
 	var err error
 	n.host, err = host.NewHost(n.config.Address)
 	t.Assert().NoError(err)
+	n.host.NodeID = n.ref
 
-	n.transport, err = NewTransport(n.config, relay.NewProxy())
+	n.transport, err = NewSecureTransport(n.config, relay.NewProxy(), n.security)
 	t.Require().NoError(err)
 	t.Require().NotNil(n.transport)
 	t.Require().Implements((*Transport)(nil), n.transport)
@@ -153,6 +161,45 @@ func (t *transportSuite) TestSendBigPacket() {
 	t.Assert().Equal(data, receivedData)
 }
 
+func (t *transportSuite) TestStream() {
+	if t.node1.config.Protocol == "PURE_UDP" {
+		t.T().Skip("Skipping TestStream for PURE_UDP")
+	}
+	ctx := context.Background()
+	builder := packet.NewBuilder(t.node1.host).Receiver(t.node2.host).Type(packet.TestPacket)
+	requestMsg := builder.Request(&packet.RequestTest{Data: []byte("stream")}).Build()
+
+	stream, err := t.node1.transport.OpenStream(ctx, requestMsg)
+	if t.node1.config.Protocol == SimulatedProtocol {
+		t.Assert().Equal(ErrStreamsNotSupported, err)
+		return
+	}
+	t.Require().NoError(err)
+	defer stream.Close()
+
+	data, _ := generateRandomBytes(1024 * 1024)
+	go func() {
+		_, err := stream.Write(data)
+		t.Assert().NoError(err)
+		t.Assert().NoError(stream.CloseWrite())
+	}()
+
+	remote := <-t.node2.transport.Streams()
+	defer remote.Close()
+	t.Assert().Equal(packet.TestPacket, remote.Packet().Type)
+	t.Assert().Equal([]byte("stream"), remote.Packet().Data.(*packet.RequestTest).Data)
+	received, err := ioutil.ReadAll(remote)
+	t.Require().NoError(err)
+	t.Assert().Equal(data, received)
+
+	_, err = remote.Write([]byte("done"))
+	t.Require().NoError(err)
+	t.Require().NoError(remote.CloseWrite())
+	reply, err := ioutil.ReadAll(stream)
+	t.Require().NoError(err)
+	t.Assert().Equal([]byte("done"), reply)
+}
+
 func (t *consensusSuite) TestSendPacketConsensus() {
 	ctx := context.Background()
 	builder := packet.NewBuilder(t.node1.host).Receiver(t.node2.host).Type(types.Phase1)
@@ -178,6 +225,69 @@ func TestTCPTransport(t *testing.T) {
 	suite.Run(t, NewSuite(cfg1, cfg2))
 }
 
+func TestTCPTransportFlowControl(t *testing.T) {
+	cfg1 := configuration.Transport{Protocol: "TCP", Address: "127.0.0.1:17023", FlowControl: configuration.NewFlowControl()}
+	cfg2 := configuration.Transport{Protocol: "TCP", Address: "127.0.0.1:17024", FlowControl: configuration.NewFlowControl()}
+
+	suite.Run(t, NewSuite(cfg1, cfg2))
+}
+
+func TestTLSTransport(t *testing.T) {
+	peer1, peer2 := newTestPeer(t), newTestPeer(t)
+	cfg1 := configuration.Transport{Protocol: "TLS", Address: "127.0.0.1:17020", BehindNAT: false}
+	cfg2 := configuration.Transport{Protocol: "TLS", Address: "127.0.0.1:17021", BehindNAT: false}
+
+	s := NewSuite(cfg1, cfg2)
+	s.node1.security, s.node1.ref = newTestSecurity(t, peer1, time.Minute, peer2), peer1.ref
+	s.node2.security, s.node2.ref = newTestSecurity(t, peer2, time.Minute, peer1), peer2.ref
+	suite.Run(t, s)
+}
+
+func TestTLSTransportDropsForgedSender(t *testing.T) {
+	peer1, peer2 := newTestPeer(t), newTestPeer(t)
+	s := NewSuite(
+		configuration.Transport{Protocol: "TLS", Address: "127.0.0.1:17023", BehindNAT: false},
+		configuration.Transport{Protocol: "TLS", Address: "127.0.0.1:17024", BehindNAT: false},
+	)
+	s.SetT(t)
+	s.node1.security, s.node1.ref = newTestSecurity(t, peer1, time.Minute, peer2), peer1.ref
+	s.node2.security, s.node2.ref = newTestSecurity(t, peer2, time.Minute, peer1), peer2.ref
+	s.SetupTest()
+	s.BeforeTest("", "")
+	defer s.AfterTest("", "")
+
+	ctx := context.Background()
+	forged, err := host.NewHostN(s.node1.config.Address, testutils.RandomRef())
+	require.NoError(t, err)
+	p := packet.NewBuilder(forged).Type(types.Ping).Receiver(s.node2.host).Build()
+	_, err = s.node1.transport.SendRequest(ctx, p)
+	require.NoError(t, err)
+
+	p = packet.NewBuilder(s.node1.host).Type(types.Ping).Receiver(s.node2.host).Build()
+	_, err = s.node1.transport.SendRequest(ctx, p)
+	require.NoError(t, err)
+
+	select {
+	case msg := <-s.node2.transport.Packets():
+		require.Equal(t, peer1.ref, msg.Sender.NodeID)
+	case <-time.After(5 * time.Second):
+		t.Fatal("packet of authenticated sender is not received")
+	}
+}
+
+func TestTLSTransportRequiresSecurity(t *testing.T) {
+	cfg := configuration.Transport{Protocol: "TLS", Address: "127.0.0.1:17022", BehindNAT: false}
+	_, err := NewTransport(cfg, relay.NewProxy())
+	require.Error(t, err)
+}
+
+func TestSimulatedTransport(t *testing.T) {
+	cfg1 := configuration.Transport{Protocol: SimulatedProtocol, Address: "127.0.0.1:17025"}
+	cfg2 := configuration.Transport{Protocol: SimulatedProtocol, Address: "127.0.0.1:17026"}
+
+	suite.Run(t, NewConsensusSuite(cfg1, cfg2))
+}
+
 func TestQuicTransport(t *testing.T) {
 	t.Skip("QUIC internals racing atm. Skip until we want to use it in production")
 
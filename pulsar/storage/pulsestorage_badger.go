@@ -86,28 +86,31 @@ type BadgerStorageImpl struct {
 }
 
 func (storage *BadgerStorageImpl) GetLastPulse() (*core.Pulse, error) {
-	var pulseNumber core.Pulse
-
+	var pulse *core.Pulse
 	err := storage.db.View(func(txn *badger.Txn) error {
-		item, err := txn.Get([]byte(LastPulseRecordID))
-		if err != nil {
-			return err
-		}
-		val, err := item.Value()
-		if err != nil {
-			return err
-		}
+		var err error
+		pulse, err = getLastPulse(txn)
+		return err
+	})
+	return pulse, err
+}
 
-		r := bytes.NewBuffer(val)
-		decoder := gob.NewDecoder(r)
-		err = decoder.Decode(&pulseNumber)
-		if err != nil {
-			return err
-		}
+func getLastPulse(txn *badger.Txn) (*core.Pulse, error) {
+	item, err := txn.Get([]byte(LastPulseRecordID))
+	if err != nil {
+		return nil, err
+	}
+	val, err := item.Value()
+	if err != nil {
+		return nil, err
+	}
 
-		return nil
-	})
-	return &pulseNumber, err
+	var pulse core.Pulse
+	err = gob.NewDecoder(bytes.NewBuffer(val)).Decode(&pulse)
+	if err != nil {
+		return nil, err
+	}
+	return &pulse, nil
 }
 
 func (storage *BadgerStorageImpl) SetLastPulse(pulse *core.Pulse) error {
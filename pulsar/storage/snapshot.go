@@ -0,0 +1,134 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package pulsarstorage
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/dgraph-io/badger"
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/utils/snapshot"
+	"github.com/pkg/errors"
+)
+
+// SnapshotSource is a source name of pulsar storage snapshots.
+const SnapshotSource = "pulsar"
+
+// restoreBatchSize is a number of entries written in one transaction on restore.
+const restoreBatchSize = 1000
+
+// Snapshotter provides online backups of pulsar storage.
+type Snapshotter interface {
+	// Backup writes consistent copy of pulses since provided one (all pulses for zero) to w.
+	// Returns the latest pulse included into backup.
+	Backup(w io.Writer, since core.PulseNumber) (core.PulseNumber, error)
+	// Restore writes snapshot or incremental backup from r into storage.
+	Restore(r io.Reader) (*snapshot.Header, error)
+}
+
+// Backup implements Snapshotter.
+func (storage *BadgerStorageImpl) Backup(w io.Writer, since core.PulseNumber) (core.PulseNumber, error) {
+	var last *core.Pulse
+	err := storage.db.View(func(txn *badger.Txn) error {
+		var err error
+		// Last pulse is read in the same transaction, so it matches pulses in backup.
+		last, err = getLastPulse(txn)
+		if err != nil {
+			return errors.Wrap(err, "failed to get last pulse")
+		}
+
+		sw, err := snapshot.NewWriter(w, snapshot.Header{
+			Source: SnapshotSource,
+			Pulse:  last.PulseNumber,
+			Since:  since,
+		})
+		if err != nil {
+			return err
+		}
+
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+		pulsePrefix := []byte(PulseRecordID)
+		for it.Rewind(); it.Valid(); it.Next() {
+			key := it.Item().KeyCopy(nil)
+			if since > 0 && bytes.HasPrefix(key, pulsePrefix) && len(key) == len(pulsePrefix)+core.PulseNumberSize &&
+				core.NewPulseNumber(key[len(pulsePrefix):]) < since {
+				continue
+			}
+			value, err := it.Item().ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			if err := sw.Write(key, value); err != nil {
+				return err
+			}
+		}
+		return sw.Close()
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "[ Backup ] failed to write backup")
+	}
+	return last.PulseNumber, nil
+}
+
+// Restore implements Snapshotter.
+func (storage *BadgerStorageImpl) Restore(r io.Reader) (*snapshot.Header, error) {
+	sr, err := snapshot.NewReader(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ Restore ] failed to read snapshot header")
+	}
+	header := sr.Header()
+	if header.Source != SnapshotSource {
+		return nil, errors.Errorf("[ Restore ] snapshot of %q can't be restored into pulsar storage", header.Source)
+	}
+
+	type entry struct{ k, v []byte }
+	batch := make([]entry, 0, restoreBatchSize)
+	flush := func() error {
+		err := storage.db.Update(func(txn *badger.Txn) error {
+			for _, e := range batch {
+				if err := txn.Set(e.k, e.v); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		batch = batch[:0]
+		return err
+	}
+
+	for {
+		k, v, err := sr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "[ Restore ] failed to read snapshot")
+		}
+		batch = append(batch, entry{k: k, v: v})
+		if len(batch) == restoreBatchSize {
+			if err := flush(); err != nil {
+				return nil, errors.Wrap(err, "[ Restore ] failed to write entries")
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return nil, errors.Wrap(err, "[ Restore ] failed to write entries")
+	}
+	return &header, nil
+}
@@ -30,6 +30,11 @@ type ArtifactManagerMock struct {
 	ActivatePrototypePreCounter uint64
 	ActivatePrototypeMock       mArtifactManagerMockActivatePrototype
 
+	CountPrototypeObjectsFunc       func(p context.Context, p1 core.RecordRef) (r int, r1 error)
+	CountPrototypeObjectsCounter    uint64
+	CountPrototypeObjectsPreCounter uint64
+	CountPrototypeObjectsMock       mArtifactManagerMockCountPrototypeObjects
+
 	DeactivateObjectFunc       func(p context.Context, p1 core.RecordRef, p2 core.RecordRef, p3 core.ObjectDescriptor) (r *core.RecordID, r1 error)
 	DeactivateObjectCounter    uint64
 	DeactivateObjectPreCounter uint64
@@ -70,6 +75,21 @@ type ArtifactManagerMock struct {
 	GetObjectPreCounter uint64
 	GetObjectMock       mArtifactManagerMockGetObject
 
+	GetObjectHistoryFunc       func(p context.Context, p1 core.RecordRef, p2 *core.PulseNumber, p3 int) (r []core.ObjectHistoryState, r1 error)
+	GetObjectHistoryCounter    uint64
+	GetObjectHistoryPreCounter uint64
+	GetObjectHistoryMock       mArtifactManagerMockGetObjectHistory
+
+	GetPrototypeObjectsFunc       func(p context.Context, p1 core.RecordRef, p2 *core.RecordID, p3 int) (r []core.RecordRef, r1 *core.RecordID, r2 error)
+	GetPrototypeObjectsCounter    uint64
+	GetPrototypeObjectsPreCounter uint64
+	GetPrototypeObjectsMock       mArtifactManagerMockGetPrototypeObjects
+
+	GetRecordProofFunc       func(p context.Context, p1 core.RecordID) (r *core.RecordProof, r1 error)
+	GetRecordProofCounter    uint64
+	GetRecordProofPreCounter uint64
+	GetRecordProofMock       mArtifactManagerMockGetRecordProof
+
 	HasPendingRequestsFunc       func(p context.Context, p1 core.RecordRef) (r bool, r1 error)
 	HasPendingRequestsCounter    uint64
 	HasPendingRequestsPreCounter uint64
@@ -116,6 +136,7 @@ func NewArtifactManagerMock(t minimock.Tester) *ArtifactManagerMock {
 
 	m.ActivateObjectMock = mArtifactManagerMockActivateObject{mock: m}
 	m.ActivatePrototypeMock = mArtifactManagerMockActivatePrototype{mock: m}
+	m.CountPrototypeObjectsMock = mArtifactManagerMockCountPrototypeObjects{mock: m}
 	m.DeactivateObjectMock = mArtifactManagerMockDeactivateObject{mock: m}
 	m.DeclareTypeMock = mArtifactManagerMockDeclareType{mock: m}
 	m.DeployCodeMock = mArtifactManagerMockDeployCode{mock: m}
@@ -124,6 +145,9 @@ func NewArtifactManagerMock(t minimock.Tester) *ArtifactManagerMock {
 	m.GetCodeMock = mArtifactManagerMockGetCode{mock: m}
 	m.GetDelegateMock = mArtifactManagerMockGetDelegate{mock: m}
 	m.GetObjectMock = mArtifactManagerMockGetObject{mock: m}
+	m.GetObjectHistoryMock = mArtifactManagerMockGetObjectHistory{mock: m}
+	m.GetPrototypeObjectsMock = mArtifactManagerMockGetPrototypeObjects{mock: m}
+	m.GetRecordProofMock = mArtifactManagerMockGetRecordProof{mock: m}
 	m.HasPendingRequestsMock = mArtifactManagerMockHasPendingRequests{mock: m}
 	m.RegisterRequestMock = mArtifactManagerMockRegisterRequest{mock: m}
 	m.RegisterResultMock = mArtifactManagerMockRegisterResult{mock: m}
@@ -446,6 +470,157 @@ func (m *ArtifactManagerMock) ActivatePrototypeFinished() bool {
 	return true
 }
 
+type mArtifactManagerMockCountPrototypeObjects struct {
+	mock              *ArtifactManagerMock
+	mainExpectation   *ArtifactManagerMockCountPrototypeObjectsExpectation
+	expectationSeries []*ArtifactManagerMockCountPrototypeObjectsExpectation
+}
+
+type ArtifactManagerMockCountPrototypeObjectsExpectation struct {
+	input  *ArtifactManagerMockCountPrototypeObjectsInput
+	result *ArtifactManagerMockCountPrototypeObjectsResult
+}
+
+type ArtifactManagerMockCountPrototypeObjectsInput struct {
+	p  context.Context
+	p1 core.RecordRef
+}
+
+type ArtifactManagerMockCountPrototypeObjectsResult struct {
+	r  int
+	r1 error
+}
+
+//Expect specifies that invocation of ArtifactManager.CountPrototypeObjects is expected from 1 to Infinity times
+func (m *mArtifactManagerMockCountPrototypeObjects) Expect(p context.Context, p1 core.RecordRef) *mArtifactManagerMockCountPrototypeObjects {
+	m.mock.CountPrototypeObjectsFunc = nil
+	m.expectationSeries = nil
+
+	if m.mainExpectation == nil {
+		m.mainExpectation = &ArtifactManagerMockCountPrototypeObjectsExpectation{}
+	}
+	m.mainExpectation.input = &ArtifactManagerMockCountPrototypeObjectsInput{p, p1}
+	return m
+}
+
+//Return specifies results of invocation of ArtifactManager.CountPrototypeObjects
+func (m *mArtifactManagerMockCountPrototypeObjects) Return(r int, r1 error) *ArtifactManagerMock {
+	m.mock.CountPrototypeObjectsFunc = nil
+	m.expectationSeries = nil
+
+	if m.mainExpectation == nil {
+		m.mainExpectation = &ArtifactManagerMockCountPrototypeObjectsExpectation{}
+	}
+	m.mainExpectation.result = &ArtifactManagerMockCountPrototypeObjectsResult{r, r1}
+	return m.mock
+}
+
+//ExpectOnce specifies that invocation of ArtifactManager.CountPrototypeObjects is expected once
+func (m *mArtifactManagerMockCountPrototypeObjects) ExpectOnce(p context.Context, p1 core.RecordRef) *ArtifactManagerMockCountPrototypeObjectsExpectation {
+	m.mock.CountPrototypeObjectsFunc = nil
+	m.mainExpectation = nil
+
+	expectation := &ArtifactManagerMockCountPrototypeObjectsExpectation{}
+	expectation.input = &ArtifactManagerMockCountPrototypeObjectsInput{p, p1}
+	m.expectationSeries = append(m.expectationSeries, expectation)
+	return expectation
+}
+
+func (e *ArtifactManagerMockCountPrototypeObjectsExpectation) Return(r int, r1 error) {
+	e.result = &ArtifactManagerMockCountPrototypeObjectsResult{r, r1}
+}
+
+//Set uses given function f as a mock of ArtifactManager.CountPrototypeObjects method
+func (m *mArtifactManagerMockCountPrototypeObjects) Set(f func(p context.Context, p1 core.RecordRef) (r int, r1 error)) *ArtifactManagerMock {
+	m.mainExpectation = nil
+	m.expectationSeries = nil
+
+	m.mock.CountPrototypeObjectsFunc = f
+	return m.mock
+}
+
+//CountPrototypeObjects implements github.com/insolar/insolar/core.ArtifactManager interface
+func (m *ArtifactManagerMock) CountPrototypeObjects(p context.Context, p1 core.RecordRef) (r int, r1 error) {
+	counter := atomic.AddUint64(&m.CountPrototypeObjectsPreCounter, 1)
+	defer atomic.AddUint64(&m.CountPrototypeObjectsCounter, 1)
+
+	if len(m.CountPrototypeObjectsMock.expectationSeries) > 0 {
+		if counter > uint64(len(m.CountPrototypeObjectsMock.expectationSeries)) {
+			m.t.Fatalf("Unexpected call to ArtifactManagerMock.CountPrototypeObjects. %v %v", p, p1)
+			return
+		}
+
+		input := m.CountPrototypeObjectsMock.expectationSeries[counter-1].input
+		testify_assert.Equal(m.t, *input, ArtifactManagerMockCountPrototypeObjectsInput{p, p1}, "ArtifactManager.CountPrototypeObjects got unexpected parameters")
+
+		result := m.CountPrototypeObjectsMock.expectationSeries[counter-1].result
+		if result == nil {
+			m.t.Fatal("No results are set for the ArtifactManagerMock.CountPrototypeObjects")
+			return
+		}
+
+		r = result.r
+		r1 = result.r1
+
+		return
+	}
+
+	if m.CountPrototypeObjectsMock.mainExpectation != nil {
+
+		input := m.CountPrototypeObjectsMock.mainExpectation.input
+		if input != nil {
+			testify_assert.Equal(m.t, *input, ArtifactManagerMockCountPrototypeObjectsInput{p, p1}, "ArtifactManager.CountPrototypeObjects got unexpected parameters")
+		}
+
+		result := m.CountPrototypeObjectsMock.mainExpectation.result
+		if result == nil {
+			m.t.Fatal("No results are set for the ArtifactManagerMock.CountPrototypeObjects")
+		}
+
+		r = result.r
+		r1 = result.r1
+
+		return
+	}
+
+	if m.CountPrototypeObjectsFunc == nil {
+		m.t.Fatalf("Unexpected call to ArtifactManagerMock.CountPrototypeObjects. %v %v", p, p1)
+		return
+	}
+
+	return m.CountPrototypeObjectsFunc(p, p1)
+}
+
+//CountPrototypeObjectsMinimockCounter returns a count of ArtifactManagerMock.CountPrototypeObjectsFunc invocations
+func (m *ArtifactManagerMock) CountPrototypeObjectsMinimockCounter() uint64 {
+	return atomic.LoadUint64(&m.CountPrototypeObjectsCounter)
+}
+
+//CountPrototypeObjectsMinimockPreCounter returns the value of ArtifactManagerMock.CountPrototypeObjects invocations
+func (m *ArtifactManagerMock) CountPrototypeObjectsMinimockPreCounter() uint64 {
+	return atomic.LoadUint64(&m.CountPrototypeObjectsPreCounter)
+}
+
+//CountPrototypeObjectsFinished returns true if mock invocations count is ok
+func (m *ArtifactManagerMock) CountPrototypeObjectsFinished() bool {
+	// if expectation series were set then invocations count should be equal to expectations count
+	if len(m.CountPrototypeObjectsMock.expectationSeries) > 0 {
+		return atomic.LoadUint64(&m.CountPrototypeObjectsCounter) == uint64(len(m.CountPrototypeObjectsMock.expectationSeries))
+	}
+
+	// if main expectation was set then invocations count should be greater than zero
+	if m.CountPrototypeObjectsMock.mainExpectation != nil {
+		return atomic.LoadUint64(&m.CountPrototypeObjectsCounter) > 0
+	}
+
+	// if func was set then invocations count should be greater than zero
+	if m.CountPrototypeObjectsFunc != nil {
+		return atomic.LoadUint64(&m.CountPrototypeObjectsCounter) > 0
+	}
+
+	return true
+}
+
 type mArtifactManagerMockDeactivateObject struct {
 	mock              *ArtifactManagerMock
 	mainExpectation   *ArtifactManagerMockDeactivateObjectExpectation
@@ -1648,6 +1823,466 @@ func (m *ArtifactManagerMock) GetObjectFinished() bool {
 	return true
 }
 
+type mArtifactManagerMockGetObjectHistory struct {
+	mock              *ArtifactManagerMock
+	mainExpectation   *ArtifactManagerMockGetObjectHistoryExpectation
+	expectationSeries []*ArtifactManagerMockGetObjectHistoryExpectation
+}
+
+type ArtifactManagerMockGetObjectHistoryExpectation struct {
+	input  *ArtifactManagerMockGetObjectHistoryInput
+	result *ArtifactManagerMockGetObjectHistoryResult
+}
+
+type ArtifactManagerMockGetObjectHistoryInput struct {
+	p  context.Context
+	p1 core.RecordRef
+	p2 *core.PulseNumber
+	p3 int
+}
+
+type ArtifactManagerMockGetObjectHistoryResult struct {
+	r  []core.ObjectHistoryState
+	r1 error
+}
+
+//Expect specifies that invocation of ArtifactManager.GetObjectHistory is expected from 1 to Infinity times
+func (m *mArtifactManagerMockGetObjectHistory) Expect(p context.Context, p1 core.RecordRef, p2 *core.PulseNumber, p3 int) *mArtifactManagerMockGetObjectHistory {
+	m.mock.GetObjectHistoryFunc = nil
+	m.expectationSeries = nil
+
+	if m.mainExpectation == nil {
+		m.mainExpectation = &ArtifactManagerMockGetObjectHistoryExpectation{}
+	}
+	m.mainExpectation.input = &ArtifactManagerMockGetObjectHistoryInput{p, p1, p2, p3}
+	return m
+}
+
+//Return specifies results of invocation of ArtifactManager.GetObjectHistory
+func (m *mArtifactManagerMockGetObjectHistory) Return(r []core.ObjectHistoryState, r1 error) *ArtifactManagerMock {
+	m.mock.GetObjectHistoryFunc = nil
+	m.expectationSeries = nil
+
+	if m.mainExpectation == nil {
+		m.mainExpectation = &ArtifactManagerMockGetObjectHistoryExpectation{}
+	}
+	m.mainExpectation.result = &ArtifactManagerMockGetObjectHistoryResult{r, r1}
+	return m.mock
+}
+
+//ExpectOnce specifies that invocation of ArtifactManager.GetObjectHistory is expected once
+func (m *mArtifactManagerMockGetObjectHistory) ExpectOnce(p context.Context, p1 core.RecordRef, p2 *core.PulseNumber, p3 int) *ArtifactManagerMockGetObjectHistoryExpectation {
+	m.mock.GetObjectHistoryFunc = nil
+	m.mainExpectation = nil
+
+	expectation := &ArtifactManagerMockGetObjectHistoryExpectation{}
+	expectation.input = &ArtifactManagerMockGetObjectHistoryInput{p, p1, p2, p3}
+	m.expectationSeries = append(m.expectationSeries, expectation)
+	return expectation
+}
+
+func (e *ArtifactManagerMockGetObjectHistoryExpectation) Return(r []core.ObjectHistoryState, r1 error) {
+	e.result = &ArtifactManagerMockGetObjectHistoryResult{r, r1}
+}
+
+//Set uses given function f as a mock of ArtifactManager.GetObjectHistory method
+func (m *mArtifactManagerMockGetObjectHistory) Set(f func(p context.Context, p1 core.RecordRef, p2 *core.PulseNumber, p3 int) (r []core.ObjectHistoryState, r1 error)) *ArtifactManagerMock {
+	m.mainExpectation = nil
+	m.expectationSeries = nil
+
+	m.mock.GetObjectHistoryFunc = f
+	return m.mock
+}
+
+//GetObjectHistory implements github.com/insolar/insolar/core.ArtifactManager interface
+func (m *ArtifactManagerMock) GetObjectHistory(p context.Context, p1 core.RecordRef, p2 *core.PulseNumber, p3 int) (r []core.ObjectHistoryState, r1 error) {
+	counter := atomic.AddUint64(&m.GetObjectHistoryPreCounter, 1)
+	defer atomic.AddUint64(&m.GetObjectHistoryCounter, 1)
+
+	if len(m.GetObjectHistoryMock.expectationSeries) > 0 {
+		if counter > uint64(len(m.GetObjectHistoryMock.expectationSeries)) {
+			m.t.Fatalf("Unexpected call to ArtifactManagerMock.GetObjectHistory. %v %v %v %v", p, p1, p2, p3)
+			return
+		}
+
+		input := m.GetObjectHistoryMock.expectationSeries[counter-1].input
+		testify_assert.Equal(m.t, *input, ArtifactManagerMockGetObjectHistoryInput{p, p1, p2, p3}, "ArtifactManager.GetObjectHistory got unexpected parameters")
+
+		result := m.GetObjectHistoryMock.expectationSeries[counter-1].result
+		if result == nil {
+			m.t.Fatal("No results are set for the ArtifactManagerMock.GetObjectHistory")
+			return
+		}
+
+		r = result.r
+		r1 = result.r1
+
+		return
+	}
+
+	if m.GetObjectHistoryMock.mainExpectation != nil {
+
+		input := m.GetObjectHistoryMock.mainExpectation.input
+		if input != nil {
+			testify_assert.Equal(m.t, *input, ArtifactManagerMockGetObjectHistoryInput{p, p1, p2, p3}, "ArtifactManager.GetObjectHistory got unexpected parameters")
+		}
+
+		result := m.GetObjectHistoryMock.mainExpectation.result
+		if result == nil {
+			m.t.Fatal("No results are set for the ArtifactManagerMock.GetObjectHistory")
+		}
+
+		r = result.r
+		r1 = result.r1
+
+		return
+	}
+
+	if m.GetObjectHistoryFunc == nil {
+		m.t.Fatalf("Unexpected call to ArtifactManagerMock.GetObjectHistory. %v %v %v %v", p, p1, p2, p3)
+		return
+	}
+
+	return m.GetObjectHistoryFunc(p, p1, p2, p3)
+}
+
+//GetObjectHistoryMinimockCounter returns a count of ArtifactManagerMock.GetObjectHistoryFunc invocations
+func (m *ArtifactManagerMock) GetObjectHistoryMinimockCounter() uint64 {
+	return atomic.LoadUint64(&m.GetObjectHistoryCounter)
+}
+
+//GetObjectHistoryMinimockPreCounter returns the value of ArtifactManagerMock.GetObjectHistory invocations
+func (m *ArtifactManagerMock) GetObjectHistoryMinimockPreCounter() uint64 {
+	return atomic.LoadUint64(&m.GetObjectHistoryPreCounter)
+}
+
+//GetObjectHistoryFinished returns true if mock invocations count is ok
+func (m *ArtifactManagerMock) GetObjectHistoryFinished() bool {
+	// if expectation series were set then invocations count should be equal to expectations count
+	if len(m.GetObjectHistoryMock.expectationSeries) > 0 {
+		return atomic.LoadUint64(&m.GetObjectHistoryCounter) == uint64(len(m.GetObjectHistoryMock.expectationSeries))
+	}
+
+	// if main expectation was set then invocations count should be greater than zero
+	if m.GetObjectHistoryMock.mainExpectation != nil {
+		return atomic.LoadUint64(&m.GetObjectHistoryCounter) > 0
+	}
+
+	// if func was set then invocations count should be greater than zero
+	if m.GetObjectHistoryFunc != nil {
+		return atomic.LoadUint64(&m.GetObjectHistoryCounter) > 0
+	}
+
+	return true
+}
+
+type mArtifactManagerMockGetPrototypeObjects struct {
+	mock              *ArtifactManagerMock
+	mainExpectation   *ArtifactManagerMockGetPrototypeObjectsExpectation
+	expectationSeries []*ArtifactManagerMockGetPrototypeObjectsExpectation
+}
+
+type ArtifactManagerMockGetPrototypeObjectsExpectation struct {
+	input  *ArtifactManagerMockGetPrototypeObjectsInput
+	result *ArtifactManagerMockGetPrototypeObjectsResult
+}
+
+type ArtifactManagerMockGetPrototypeObjectsInput struct {
+	p  context.Context
+	p1 core.RecordRef
+	p2 *core.RecordID
+	p3 int
+}
+
+type ArtifactManagerMockGetPrototypeObjectsResult struct {
+	r  []core.RecordRef
+	r1 *core.RecordID
+	r2 error
+}
+
+//Expect specifies that invocation of ArtifactManager.GetPrototypeObjects is expected from 1 to Infinity times
+func (m *mArtifactManagerMockGetPrototypeObjects) Expect(p context.Context, p1 core.RecordRef, p2 *core.RecordID, p3 int) *mArtifactManagerMockGetPrototypeObjects {
+	m.mock.GetPrototypeObjectsFunc = nil
+	m.expectationSeries = nil
+
+	if m.mainExpectation == nil {
+		m.mainExpectation = &ArtifactManagerMockGetPrototypeObjectsExpectation{}
+	}
+	m.mainExpectation.input = &ArtifactManagerMockGetPrototypeObjectsInput{p, p1, p2, p3}
+	return m
+}
+
+//Return specifies results of invocation of ArtifactManager.GetPrototypeObjects
+func (m *mArtifactManagerMockGetPrototypeObjects) Return(r []core.RecordRef, r1 *core.RecordID, r2 error) *ArtifactManagerMock {
+	m.mock.GetPrototypeObjectsFunc = nil
+	m.expectationSeries = nil
+
+	if m.mainExpectation == nil {
+		m.mainExpectation = &ArtifactManagerMockGetPrototypeObjectsExpectation{}
+	}
+	m.mainExpectation.result = &ArtifactManagerMockGetPrototypeObjectsResult{r, r1, r2}
+	return m.mock
+}
+
+//ExpectOnce specifies that invocation of ArtifactManager.GetPrototypeObjects is expected once
+func (m *mArtifactManagerMockGetPrototypeObjects) ExpectOnce(p context.Context, p1 core.RecordRef, p2 *core.RecordID, p3 int) *ArtifactManagerMockGetPrototypeObjectsExpectation {
+	m.mock.GetPrototypeObjectsFunc = nil
+	m.mainExpectation = nil
+
+	expectation := &ArtifactManagerMockGetPrototypeObjectsExpectation{}
+	expectation.input = &ArtifactManagerMockGetPrototypeObjectsInput{p, p1, p2, p3}
+	m.expectationSeries = append(m.expectationSeries, expectation)
+	return expectation
+}
+
+func (e *ArtifactManagerMockGetPrototypeObjectsExpectation) Return(r []core.RecordRef, r1 *core.RecordID, r2 error) {
+	e.result = &ArtifactManagerMockGetPrototypeObjectsResult{r, r1, r2}
+}
+
+//Set uses given function f as a mock of ArtifactManager.GetPrototypeObjects method
+func (m *mArtifactManagerMockGetPrototypeObjects) Set(f func(p context.Context, p1 core.RecordRef, p2 *core.RecordID, p3 int) (r []core.RecordRef, r1 *core.RecordID, r2 error)) *ArtifactManagerMock {
+	m.mainExpectation = nil
+	m.expectationSeries = nil
+
+	m.mock.GetPrototypeObjectsFunc = f
+	return m.mock
+}
+
+//GetPrototypeObjects implements github.com/insolar/insolar/core.ArtifactManager interface
+func (m *ArtifactManagerMock) GetPrototypeObjects(p context.Context, p1 core.RecordRef, p2 *core.RecordID, p3 int) (r []core.RecordRef, r1 *core.RecordID, r2 error) {
+	counter := atomic.AddUint64(&m.GetPrototypeObjectsPreCounter, 1)
+	defer atomic.AddUint64(&m.GetPrototypeObjectsCounter, 1)
+
+	if len(m.GetPrototypeObjectsMock.expectationSeries) > 0 {
+		if counter > uint64(len(m.GetPrototypeObjectsMock.expectationSeries)) {
+			m.t.Fatalf("Unexpected call to ArtifactManagerMock.GetPrototypeObjects. %v %v %v %v", p, p1, p2, p3)
+			return
+		}
+
+		input := m.GetPrototypeObjectsMock.expectationSeries[counter-1].input
+		testify_assert.Equal(m.t, *input, ArtifactManagerMockGetPrototypeObjectsInput{p, p1, p2, p3}, "ArtifactManager.GetPrototypeObjects got unexpected parameters")
+
+		result := m.GetPrototypeObjectsMock.expectationSeries[counter-1].result
+		if result == nil {
+			m.t.Fatal("No results are set for the ArtifactManagerMock.GetPrototypeObjects")
+			return
+		}
+
+		r = result.r
+		r1 = result.r1
+		r2 = result.r2
+
+		return
+	}
+
+	if m.GetPrototypeObjectsMock.mainExpectation != nil {
+
+		input := m.GetPrototypeObjectsMock.mainExpectation.input
+		if input != nil {
+			testify_assert.Equal(m.t, *input, ArtifactManagerMockGetPrototypeObjectsInput{p, p1, p2, p3}, "ArtifactManager.GetPrototypeObjects got unexpected parameters")
+		}
+
+		result := m.GetPrototypeObjectsMock.mainExpectation.result
+		if result == nil {
+			m.t.Fatal("No results are set for the ArtifactManagerMock.GetPrototypeObjects")
+		}
+
+		r = result.r
+		r1 = result.r1
+		r2 = result.r2
+
+		return
+	}
+
+	if m.GetPrototypeObjectsFunc == nil {
+		m.t.Fatalf("Unexpected call to ArtifactManagerMock.GetPrototypeObjects. %v %v %v %v", p, p1, p2, p3)
+		return
+	}
+
+	return m.GetPrototypeObjectsFunc(p, p1, p2, p3)
+}
+
+//GetPrototypeObjectsMinimockCounter returns a count of ArtifactManagerMock.GetPrototypeObjectsFunc invocations
+func (m *ArtifactManagerMock) GetPrototypeObjectsMinimockCounter() uint64 {
+	return atomic.LoadUint64(&m.GetPrototypeObjectsCounter)
+}
+
+//GetPrototypeObjectsMinimockPreCounter returns the value of ArtifactManagerMock.GetPrototypeObjects invocations
+func (m *ArtifactManagerMock) GetPrototypeObjectsMinimockPreCounter() uint64 {
+	return atomic.LoadUint64(&m.GetPrototypeObjectsPreCounter)
+}
+
+//GetPrototypeObjectsFinished returns true if mock invocations count is ok
+func (m *ArtifactManagerMock) GetPrototypeObjectsFinished() bool {
+	// if expectation series were set then invocations count should be equal to expectations count
+	if len(m.GetPrototypeObjectsMock.expectationSeries) > 0 {
+		return atomic.LoadUint64(&m.GetPrototypeObjectsCounter) == uint64(len(m.GetPrototypeObjectsMock.expectationSeries))
+	}
+
+	// if main expectation was set then invocations count should be greater than zero
+	if m.GetPrototypeObjectsMock.mainExpectation != nil {
+		return atomic.LoadUint64(&m.GetPrototypeObjectsCounter) > 0
+	}
+
+	// if func was set then invocations count should be greater than zero
+	if m.GetPrototypeObjectsFunc != nil {
+		return atomic.LoadUint64(&m.GetPrototypeObjectsCounter) > 0
+	}
+
+	return true
+}
+
+type mArtifactManagerMockGetRecordProof struct {
+	mock              *ArtifactManagerMock
+	mainExpectation   *ArtifactManagerMockGetRecordProofExpectation
+	expectationSeries []*ArtifactManagerMockGetRecordProofExpectation
+}
+
+type ArtifactManagerMockGetRecordProofExpectation struct {
+	input  *ArtifactManagerMockGetRecordProofInput
+	result *ArtifactManagerMockGetRecordProofResult
+}
+
+type ArtifactManagerMockGetRecordProofInput struct {
+	p  context.Context
+	p1 core.RecordID
+}
+
+type ArtifactManagerMockGetRecordProofResult struct {
+	r  *core.RecordProof
+	r1 error
+}
+
+//Expect specifies that invocation of ArtifactManager.GetRecordProof is expected from 1 to Infinity times
+func (m *mArtifactManagerMockGetRecordProof) Expect(p context.Context, p1 core.RecordID) *mArtifactManagerMockGetRecordProof {
+	m.mock.GetRecordProofFunc = nil
+	m.expectationSeries = nil
+
+	if m.mainExpectation == nil {
+		m.mainExpectation = &ArtifactManagerMockGetRecordProofExpectation{}
+	}
+	m.mainExpectation.input = &ArtifactManagerMockGetRecordProofInput{p, p1}
+	return m
+}
+
+//Return specifies results of invocation of ArtifactManager.GetRecordProof
+func (m *mArtifactManagerMockGetRecordProof) Return(r *core.RecordProof, r1 error) *ArtifactManagerMock {
+	m.mock.GetRecordProofFunc = nil
+	m.expectationSeries = nil
+
+	if m.mainExpectation == nil {
+		m.mainExpectation = &ArtifactManagerMockGetRecordProofExpectation{}
+	}
+	m.mainExpectation.result = &ArtifactManagerMockGetRecordProofResult{r, r1}
+	return m.mock
+}
+
+//ExpectOnce specifies that invocation of ArtifactManager.GetRecordProof is expected once
+func (m *mArtifactManagerMockGetRecordProof) ExpectOnce(p context.Context, p1 core.RecordID) *ArtifactManagerMockGetRecordProofExpectation {
+	m.mock.GetRecordProofFunc = nil
+	m.mainExpectation = nil
+
+	expectation := &ArtifactManagerMockGetRecordProofExpectation{}
+	expectation.input = &ArtifactManagerMockGetRecordProofInput{p, p1}
+	m.expectationSeries = append(m.expectationSeries, expectation)
+	return expectation
+}
+
+func (e *ArtifactManagerMockGetRecordProofExpectation) Return(r *core.RecordProof, r1 error) {
+	e.result = &ArtifactManagerMockGetRecordProofResult{r, r1}
+}
+
+//Set uses given function f as a mock of ArtifactManager.GetRecordProof method
+func (m *mArtifactManagerMockGetRecordProof) Set(f func(p context.Context, p1 core.RecordID) (r *core.RecordProof, r1 error)) *ArtifactManagerMock {
+	m.mainExpectation = nil
+	m.expectationSeries = nil
+
+	m.mock.GetRecordProofFunc = f
+	return m.mock
+}
+
+//GetRecordProof implements github.com/insolar/insolar/core.ArtifactManager interface
+func (m *ArtifactManagerMock) GetRecordProof(p context.Context, p1 core.RecordID) (r *core.RecordProof, r1 error) {
+	counter := atomic.AddUint64(&m.GetRecordProofPreCounter, 1)
+	defer atomic.AddUint64(&m.GetRecordProofCounter, 1)
+
+	if len(m.GetRecordProofMock.expectationSeries) > 0 {
+		if counter > uint64(len(m.GetRecordProofMock.expectationSeries)) {
+			m.t.Fatalf("Unexpected call to ArtifactManagerMock.GetRecordProof. %v %v", p, p1)
+			return
+		}
+
+		input := m.GetRecordProofMock.expectationSeries[counter-1].input
+		testify_assert.Equal(m.t, *input, ArtifactManagerMockGetRecordProofInput{p, p1}, "ArtifactManager.GetRecordProof got unexpected parameters")
+
+		result := m.GetRecordProofMock.expectationSeries[counter-1].result
+		if result == nil {
+			m.t.Fatal("No results are set for the ArtifactManagerMock.GetRecordProof")
+			return
+		}
+
+		r = result.r
+		r1 = result.r1
+
+		return
+	}
+
+	if m.GetRecordProofMock.mainExpectation != nil {
+
+		input := m.GetRecordProofMock.mainExpectation.input
+		if input != nil {
+			testify_assert.Equal(m.t, *input, ArtifactManagerMockGetRecordProofInput{p, p1}, "ArtifactManager.GetRecordProof got unexpected parameters")
+		}
+
+		result := m.GetRecordProofMock.mainExpectation.result
+		if result == nil {
+			m.t.Fatal("No results are set for the ArtifactManagerMock.GetRecordProof")
+		}
+
+		r = result.r
+		r1 = result.r1
+
+		return
+	}
+
+	if m.GetRecordProofFunc == nil {
+		m.t.Fatalf("Unexpected call to ArtifactManagerMock.GetRecordProof. %v %v", p, p1)
+		return
+	}
+
+	return m.GetRecordProofFunc(p, p1)
+}
+
+//GetRecordProofMinimockCounter returns a count of ArtifactManagerMock.GetRecordProofFunc invocations
+func (m *ArtifactManagerMock) GetRecordProofMinimockCounter() uint64 {
+	return atomic.LoadUint64(&m.GetRecordProofCounter)
+}
+
+//GetRecordProofMinimockPreCounter returns the value of ArtifactManagerMock.GetRecordProof invocations
+func (m *ArtifactManagerMock) GetRecordProofMinimockPreCounter() uint64 {
+	return atomic.LoadUint64(&m.GetRecordProofPreCounter)
+}
+
+//GetRecordProofFinished returns true if mock invocations count is ok
+func (m *ArtifactManagerMock) GetRecordProofFinished() bool {
+	// if expectation series were set then invocations count should be equal to expectations count
+	if len(m.GetRecordProofMock.expectationSeries) > 0 {
+		return atomic.LoadUint64(&m.GetRecordProofCounter) == uint64(len(m.GetRecordProofMock.expectationSeries))
+	}
+
+	// if main expectation was set then invocations count should be greater than zero
+	if m.GetRecordProofMock.mainExpectation != nil {
+		return atomic.LoadUint64(&m.GetRecordProofCounter) > 0
+	}
+
+	// if func was set then invocations count should be greater than zero
+	if m.GetRecordProofFunc != nil {
+		return atomic.LoadUint64(&m.GetRecordProofCounter) > 0
+	}
+
+	return true
+}
+
 type mArtifactManagerMockHasPendingRequests struct {
 	mock              *ArtifactManagerMock
 	mainExpectation   *ArtifactManagerMockHasPendingRequestsExpectation
@@ -2712,6 +3347,9 @@ func (m *ArtifactManagerMock) ValidateCallCounters() {
 	if !m.ActivatePrototypeFinished() {
 		m.t.Fatal("Expected call to ArtifactManagerMock.ActivatePrototype")
 	}
+	if !m.CountPrototypeObjectsFinished() {
+		m.t.Fatal("Expected call to ArtifactManagerMock.CountPrototypeObjects")
+	}
 
 	if !m.DeactivateObjectFinished() {
 		m.t.Fatal("Expected call to ArtifactManagerMock.DeactivateObject")
@@ -2744,6 +3382,15 @@ func (m *ArtifactManagerMock) ValidateCallCounters() {
 	if !m.GetObjectFinished() {
 		m.t.Fatal("Expected call to ArtifactManagerMock.GetObject")
 	}
+	if !m.GetObjectHistoryFinished() {
+		m.t.Fatal("Expected call to ArtifactManagerMock.GetObjectHistory")
+	}
+	if !m.GetPrototypeObjectsFinished() {
+		m.t.Fatal("Expected call to ArtifactManagerMock.GetPrototypeObjects")
+	}
+	if !m.GetRecordProofFinished() {
+		m.t.Fatal("Expected call to ArtifactManagerMock.GetRecordProof")
+	}
 
 	if !m.HasPendingRequestsFinished() {
 		m.t.Fatal("Expected call to ArtifactManagerMock.HasPendingRequests")
@@ -2797,6 +3444,9 @@ func (m *ArtifactManagerMock) MinimockFinish() {
 	if !m.ActivatePrototypeFinished() {
 		m.t.Fatal("Expected call to ArtifactManagerMock.ActivatePrototype")
 	}
+	if !m.CountPrototypeObjectsFinished() {
+		m.t.Fatal("Expected call to ArtifactManagerMock.CountPrototypeObjects")
+	}
 
 	if !m.DeactivateObjectFinished() {
 		m.t.Fatal("Expected call to ArtifactManagerMock.DeactivateObject")
@@ -2829,6 +3479,15 @@ func (m *ArtifactManagerMock) MinimockFinish() {
 	if !m.GetObjectFinished() {
 		m.t.Fatal("Expected call to ArtifactManagerMock.GetObject")
 	}
+	if !m.GetObjectHistoryFinished() {
+		m.t.Fatal("Expected call to ArtifactManagerMock.GetObjectHistory")
+	}
+	if !m.GetPrototypeObjectsFinished() {
+		m.t.Fatal("Expected call to ArtifactManagerMock.GetPrototypeObjects")
+	}
+	if !m.GetRecordProofFinished() {
+		m.t.Fatal("Expected call to ArtifactManagerMock.GetRecordProof")
+	}
 
 	if !m.HasPendingRequestsFinished() {
 		m.t.Fatal("Expected call to ArtifactManagerMock.HasPendingRequests")
@@ -2874,6 +3533,7 @@ func (m *ArtifactManagerMock) MinimockWait(timeout time.Duration) {
 		ok := true
 		ok = ok && m.ActivateObjectFinished()
 		ok = ok && m.ActivatePrototypeFinished()
+		ok = ok && m.CountPrototypeObjectsFinished()
 		ok = ok && m.DeactivateObjectFinished()
 		ok = ok && m.DeclareTypeFinished()
 		ok = ok && m.DeployCodeFinished()
@@ -2882,6 +3542,9 @@ func (m *ArtifactManagerMock) MinimockWait(timeout time.Duration) {
 		ok = ok && m.GetCodeFinished()
 		ok = ok && m.GetDelegateFinished()
 		ok = ok && m.GetObjectFinished()
+		ok = ok && m.GetObjectHistoryFinished()
+		ok = ok && m.GetPrototypeObjectsFinished()
+		ok = ok && m.GetRecordProofFinished()
 		ok = ok && m.HasPendingRequestsFinished()
 		ok = ok && m.RegisterRequestFinished()
 		ok = ok && m.RegisterResultFinished()
@@ -2904,6 +3567,9 @@ func (m *ArtifactManagerMock) MinimockWait(timeout time.Duration) {
 			if !m.ActivatePrototypeFinished() {
 				m.t.Error("Expected call to ArtifactManagerMock.ActivatePrototype")
 			}
+			if !m.CountPrototypeObjectsFinished() {
+				m.t.Error("Expected call to ArtifactManagerMock.CountPrototypeObjects")
+			}
 
 			if !m.DeactivateObjectFinished() {
 				m.t.Error("Expected call to ArtifactManagerMock.DeactivateObject")
@@ -2936,6 +3602,15 @@ func (m *ArtifactManagerMock) MinimockWait(timeout time.Duration) {
 			if !m.GetObjectFinished() {
 				m.t.Error("Expected call to ArtifactManagerMock.GetObject")
 			}
+			if !m.GetObjectHistoryFinished() {
+				m.t.Error("Expected call to ArtifactManagerMock.GetObjectHistory")
+			}
+			if !m.GetPrototypeObjectsFinished() {
+				m.t.Error("Expected call to ArtifactManagerMock.GetPrototypeObjects")
+			}
+			if !m.GetRecordProofFinished() {
+				m.t.Error("Expected call to ArtifactManagerMock.GetRecordProof")
+			}
 
 			if !m.HasPendingRequestsFinished() {
 				m.t.Error("Expected call to ArtifactManagerMock.HasPendingRequests")
@@ -2984,6 +3659,9 @@ func (m *ArtifactManagerMock) AllMocksCalled() bool {
 	if !m.ActivatePrototypeFinished() {
 		return false
 	}
+	if !m.CountPrototypeObjectsFinished() {
+		return false
+	}
 
 	if !m.DeactivateObjectFinished() {
 		return false
@@ -3016,6 +3694,15 @@ func (m *ArtifactManagerMock) AllMocksCalled() bool {
 	if !m.GetObjectFinished() {
 		return false
 	}
+	if !m.GetObjectHistoryFinished() {
+		return false
+	}
+	if !m.GetPrototypeObjectsFinished() {
+		return false
+	}
+	if !m.GetRecordProofFinished() {
+		return false
+	}
 
 	if !m.HasPendingRequestsFinished() {
 		return false
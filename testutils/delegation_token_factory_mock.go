@@ -29,6 +29,11 @@ type DelegationTokenFactoryMock struct {
 	IssueGetCodeRedirectPreCounter uint64
 	IssueGetCodeRedirectMock       mDelegationTokenFactoryMockIssueGetCodeRedirect
 
+	IssueGetObjectHistoryRedirectFunc       func(p *core.RecordRef, p1 core.Message) (r core.DelegationToken, r1 error)
+	IssueGetObjectHistoryRedirectCounter    uint64
+	IssueGetObjectHistoryRedirectPreCounter uint64
+	IssueGetObjectHistoryRedirectMock       mDelegationTokenFactoryMockIssueGetObjectHistoryRedirect
+
 	IssueGetObjectRedirectFunc       func(p *core.RecordRef, p1 core.Message) (r core.DelegationToken, r1 error)
 	IssueGetObjectRedirectCounter    uint64
 	IssueGetObjectRedirectPreCounter uint64
@@ -55,6 +60,7 @@ func NewDelegationTokenFactoryMock(t minimock.Tester) *DelegationTokenFactoryMoc
 
 	m.IssueGetChildrenRedirectMock = mDelegationTokenFactoryMockIssueGetChildrenRedirect{mock: m}
 	m.IssueGetCodeRedirectMock = mDelegationTokenFactoryMockIssueGetCodeRedirect{mock: m}
+	m.IssueGetObjectHistoryRedirectMock = mDelegationTokenFactoryMockIssueGetObjectHistoryRedirect{mock: m}
 	m.IssueGetObjectRedirectMock = mDelegationTokenFactoryMockIssueGetObjectRedirect{mock: m}
 	m.IssuePendingExecutionMock = mDelegationTokenFactoryMockIssuePendingExecution{mock: m}
 	m.VerifyMock = mDelegationTokenFactoryMockVerify{mock: m}
@@ -364,6 +370,157 @@ func (m *DelegationTokenFactoryMock) IssueGetCodeRedirectFinished() bool {
 	return true
 }
 
+type mDelegationTokenFactoryMockIssueGetObjectHistoryRedirect struct {
+	mock              *DelegationTokenFactoryMock
+	mainExpectation   *DelegationTokenFactoryMockIssueGetObjectHistoryRedirectExpectation
+	expectationSeries []*DelegationTokenFactoryMockIssueGetObjectHistoryRedirectExpectation
+}
+
+type DelegationTokenFactoryMockIssueGetObjectHistoryRedirectExpectation struct {
+	input  *DelegationTokenFactoryMockIssueGetObjectHistoryRedirectInput
+	result *DelegationTokenFactoryMockIssueGetObjectHistoryRedirectResult
+}
+
+type DelegationTokenFactoryMockIssueGetObjectHistoryRedirectInput struct {
+	p  *core.RecordRef
+	p1 core.Message
+}
+
+type DelegationTokenFactoryMockIssueGetObjectHistoryRedirectResult struct {
+	r  core.DelegationToken
+	r1 error
+}
+
+//Expect specifies that invocation of DelegationTokenFactory.IssueGetObjectHistoryRedirect is expected from 1 to Infinity times
+func (m *mDelegationTokenFactoryMockIssueGetObjectHistoryRedirect) Expect(p *core.RecordRef, p1 core.Message) *mDelegationTokenFactoryMockIssueGetObjectHistoryRedirect {
+	m.mock.IssueGetObjectHistoryRedirectFunc = nil
+	m.expectationSeries = nil
+
+	if m.mainExpectation == nil {
+		m.mainExpectation = &DelegationTokenFactoryMockIssueGetObjectHistoryRedirectExpectation{}
+	}
+	m.mainExpectation.input = &DelegationTokenFactoryMockIssueGetObjectHistoryRedirectInput{p, p1}
+	return m
+}
+
+//Return specifies results of invocation of DelegationTokenFactory.IssueGetObjectHistoryRedirect
+func (m *mDelegationTokenFactoryMockIssueGetObjectHistoryRedirect) Return(r core.DelegationToken, r1 error) *DelegationTokenFactoryMock {
+	m.mock.IssueGetObjectHistoryRedirectFunc = nil
+	m.expectationSeries = nil
+
+	if m.mainExpectation == nil {
+		m.mainExpectation = &DelegationTokenFactoryMockIssueGetObjectHistoryRedirectExpectation{}
+	}
+	m.mainExpectation.result = &DelegationTokenFactoryMockIssueGetObjectHistoryRedirectResult{r, r1}
+	return m.mock
+}
+
+//ExpectOnce specifies that invocation of DelegationTokenFactory.IssueGetObjectHistoryRedirect is expected once
+func (m *mDelegationTokenFactoryMockIssueGetObjectHistoryRedirect) ExpectOnce(p *core.RecordRef, p1 core.Message) *DelegationTokenFactoryMockIssueGetObjectHistoryRedirectExpectation {
+	m.mock.IssueGetObjectHistoryRedirectFunc = nil
+	m.mainExpectation = nil
+
+	expectation := &DelegationTokenFactoryMockIssueGetObjectHistoryRedirectExpectation{}
+	expectation.input = &DelegationTokenFactoryMockIssueGetObjectHistoryRedirectInput{p, p1}
+	m.expectationSeries = append(m.expectationSeries, expectation)
+	return expectation
+}
+
+func (e *DelegationTokenFactoryMockIssueGetObjectHistoryRedirectExpectation) Return(r core.DelegationToken, r1 error) {
+	e.result = &DelegationTokenFactoryMockIssueGetObjectHistoryRedirectResult{r, r1}
+}
+
+//Set uses given function f as a mock of DelegationTokenFactory.IssueGetObjectHistoryRedirect method
+func (m *mDelegationTokenFactoryMockIssueGetObjectHistoryRedirect) Set(f func(p *core.RecordRef, p1 core.Message) (r core.DelegationToken, r1 error)) *DelegationTokenFactoryMock {
+	m.mainExpectation = nil
+	m.expectationSeries = nil
+
+	m.mock.IssueGetObjectHistoryRedirectFunc = f
+	return m.mock
+}
+
+//IssueGetObjectHistoryRedirect implements github.com/insolar/insolar/core.DelegationTokenFactory interface
+func (m *DelegationTokenFactoryMock) IssueGetObjectHistoryRedirect(p *core.RecordRef, p1 core.Message) (r core.DelegationToken, r1 error) {
+	counter := atomic.AddUint64(&m.IssueGetObjectHistoryRedirectPreCounter, 1)
+	defer atomic.AddUint64(&m.IssueGetObjectHistoryRedirectCounter, 1)
+
+	if len(m.IssueGetObjectHistoryRedirectMock.expectationSeries) > 0 {
+		if counter > uint64(len(m.IssueGetObjectHistoryRedirectMock.expectationSeries)) {
+			m.t.Fatalf("Unexpected call to DelegationTokenFactoryMock.IssueGetObjectHistoryRedirect. %v %v", p, p1)
+			return
+		}
+
+		input := m.IssueGetObjectHistoryRedirectMock.expectationSeries[counter-1].input
+		testify_assert.Equal(m.t, *input, DelegationTokenFactoryMockIssueGetObjectHistoryRedirectInput{p, p1}, "DelegationTokenFactory.IssueGetObjectHistoryRedirect got unexpected parameters")
+
+		result := m.IssueGetObjectHistoryRedirectMock.expectationSeries[counter-1].result
+		if result == nil {
+			m.t.Fatal("No results are set for the DelegationTokenFactoryMock.IssueGetObjectHistoryRedirect")
+			return
+		}
+
+		r = result.r
+		r1 = result.r1
+
+		return
+	}
+
+	if m.IssueGetObjectHistoryRedirectMock.mainExpectation != nil {
+
+		input := m.IssueGetObjectHistoryRedirectMock.mainExpectation.input
+		if input != nil {
+			testify_assert.Equal(m.t, *input, DelegationTokenFactoryMockIssueGetObjectHistoryRedirectInput{p, p1}, "DelegationTokenFactory.IssueGetObjectHistoryRedirect got unexpected parameters")
+		}
+
+		result := m.IssueGetObjectHistoryRedirectMock.mainExpectation.result
+		if result == nil {
+			m.t.Fatal("No results are set for the DelegationTokenFactoryMock.IssueGetObjectHistoryRedirect")
+		}
+
+		r = result.r
+		r1 = result.r1
+
+		return
+	}
+
+	if m.IssueGetObjectHistoryRedirectFunc == nil {
+		m.t.Fatalf("Unexpected call to DelegationTokenFactoryMock.IssueGetObjectHistoryRedirect. %v %v", p, p1)
+		return
+	}
+
+	return m.IssueGetObjectHistoryRedirectFunc(p, p1)
+}
+
+//IssueGetObjectHistoryRedirectMinimockCounter returns a count of DelegationTokenFactoryMock.IssueGetObjectHistoryRedirectFunc invocations
+func (m *DelegationTokenFactoryMock) IssueGetObjectHistoryRedirectMinimockCounter() uint64 {
+	return atomic.LoadUint64(&m.IssueGetObjectHistoryRedirectCounter)
+}
+
+//IssueGetObjectHistoryRedirectMinimockPreCounter returns the value of DelegationTokenFactoryMock.IssueGetObjectHistoryRedirect invocations
+func (m *DelegationTokenFactoryMock) IssueGetObjectHistoryRedirectMinimockPreCounter() uint64 {
+	return atomic.LoadUint64(&m.IssueGetObjectHistoryRedirectPreCounter)
+}
+
+//IssueGetObjectHistoryRedirectFinished returns true if mock invocations count is ok
+func (m *DelegationTokenFactoryMock) IssueGetObjectHistoryRedirectFinished() bool {
+	// if expectation series were set then invocations count should be equal to expectations count
+	if len(m.IssueGetObjectHistoryRedirectMock.expectationSeries) > 0 {
+		return atomic.LoadUint64(&m.IssueGetObjectHistoryRedirectCounter) == uint64(len(m.IssueGetObjectHistoryRedirectMock.expectationSeries))
+	}
+
+	// if main expectation was set then invocations count should be greater than zero
+	if m.IssueGetObjectHistoryRedirectMock.mainExpectation != nil {
+		return atomic.LoadUint64(&m.IssueGetObjectHistoryRedirectCounter) > 0
+	}
+
+	// if func was set then invocations count should be greater than zero
+	if m.IssueGetObjectHistoryRedirectFunc != nil {
+		return atomic.LoadUint64(&m.IssueGetObjectHistoryRedirectCounter) > 0
+	}
+
+	return true
+}
+
 type mDelegationTokenFactoryMockIssueGetObjectRedirect struct {
 	mock              *DelegationTokenFactoryMock
 	mainExpectation   *DelegationTokenFactoryMockIssueGetObjectRedirectExpectation
@@ -827,6 +984,9 @@ func (m *DelegationTokenFactoryMock) ValidateCallCounters() {
 	if !m.IssueGetCodeRedirectFinished() {
 		m.t.Fatal("Expected call to DelegationTokenFactoryMock.IssueGetCodeRedirect")
 	}
+	if !m.IssueGetObjectHistoryRedirectFinished() {
+		m.t.Fatal("Expected call to DelegationTokenFactoryMock.IssueGetObjectHistoryRedirect")
+	}
 
 	if !m.IssueGetObjectRedirectFinished() {
 		m.t.Fatal("Expected call to DelegationTokenFactoryMock.IssueGetObjectRedirect")
@@ -864,6 +1024,9 @@ func (m *DelegationTokenFactoryMock) MinimockFinish() {
 	if !m.IssueGetCodeRedirectFinished() {
 		m.t.Fatal("Expected call to DelegationTokenFactoryMock.IssueGetCodeRedirect")
 	}
+	if !m.IssueGetObjectHistoryRedirectFinished() {
+		m.t.Fatal("Expected call to DelegationTokenFactoryMock.IssueGetObjectHistoryRedirect")
+	}
 
 	if !m.IssueGetObjectRedirectFinished() {
 		m.t.Fatal("Expected call to DelegationTokenFactoryMock.IssueGetObjectRedirect")
@@ -893,6 +1056,7 @@ func (m *DelegationTokenFactoryMock) MinimockWait(timeout time.Duration) {
 		ok := true
 		ok = ok && m.IssueGetChildrenRedirectFinished()
 		ok = ok && m.IssueGetCodeRedirectFinished()
+		ok = ok && m.IssueGetObjectHistoryRedirectFinished()
 		ok = ok && m.IssueGetObjectRedirectFinished()
 		ok = ok && m.IssuePendingExecutionFinished()
 		ok = ok && m.VerifyFinished()
@@ -911,6 +1075,9 @@ func (m *DelegationTokenFactoryMock) MinimockWait(timeout time.Duration) {
 			if !m.IssueGetCodeRedirectFinished() {
 				m.t.Error("Expected call to DelegationTokenFactoryMock.IssueGetCodeRedirect")
 			}
+			if !m.IssueGetObjectHistoryRedirectFinished() {
+				m.t.Error("Expected call to DelegationTokenFactoryMock.IssueGetObjectHistoryRedirect")
+			}
 
 			if !m.IssueGetObjectRedirectFinished() {
 				m.t.Error("Expected call to DelegationTokenFactoryMock.IssueGetObjectRedirect")
@@ -943,6 +1110,9 @@ func (m *DelegationTokenFactoryMock) AllMocksCalled() bool {
 	if !m.IssueGetCodeRedirectFinished() {
 		return false
 	}
+	if !m.IssueGetObjectHistoryRedirectFinished() {
+		return false
+	}
 
 	if !m.IssueGetObjectRedirectFinished() {
 		return false
@@ -25,6 +25,11 @@ type HeavySyncMock struct {
 	ResetPreCounter uint64
 	ResetMock       mHeavySyncMockReset
 
+	ResumeFunc       func(p context.Context, p1 core.RecordID, p2 core.PulseNumber) (r []byte, r1 error)
+	ResumeCounter    uint64
+	ResumePreCounter uint64
+	ResumeMock       mHeavySyncMockResume
+
 	StartFunc       func(p context.Context, p1 core.RecordID, p2 core.PulseNumber) (r error)
 	StartCounter    uint64
 	StartPreCounter uint64
@@ -35,7 +40,7 @@ type HeavySyncMock struct {
 	StopPreCounter uint64
 	StopMock       mHeavySyncMockStop
 
-	StoreFunc       func(p context.Context, p1 core.RecordID, p2 core.PulseNumber, p3 []core.KV) (r error)
+	StoreFunc       func(p context.Context, p1 core.RecordID, p2 core.PulseNumber, p3 []byte, p4 []byte, p5 []core.KV) (r []byte, r1 error)
 	StoreCounter    uint64
 	StorePreCounter uint64
 	StoreMock       mHeavySyncMockStore
@@ -50,6 +55,7 @@ func NewHeavySyncMock(t minimock.Tester) *HeavySyncMock {
 	}
 
 	m.ResetMock = mHeavySyncMockReset{mock: m}
+	m.ResumeMock = mHeavySyncMockResume{mock: m}
 	m.StartMock = mHeavySyncMockStart{mock: m}
 	m.StopMock = mHeavySyncMockStop{mock: m}
 	m.StoreMock = mHeavySyncMockStore{mock: m}
@@ -206,6 +212,158 @@ func (m *HeavySyncMock) ResetFinished() bool {
 	return true
 }
 
+type mHeavySyncMockResume struct {
+	mock              *HeavySyncMock
+	mainExpectation   *HeavySyncMockResumeExpectation
+	expectationSeries []*HeavySyncMockResumeExpectation
+}
+
+type HeavySyncMockResumeExpectation struct {
+	input  *HeavySyncMockResumeInput
+	result *HeavySyncMockResumeResult
+}
+
+type HeavySyncMockResumeInput struct {
+	p  context.Context
+	p1 core.RecordID
+	p2 core.PulseNumber
+}
+
+type HeavySyncMockResumeResult struct {
+	r  []byte
+	r1 error
+}
+
+//Expect specifies that invocation of HeavySync.Resume is expected from 1 to Infinity times
+func (m *mHeavySyncMockResume) Expect(p context.Context, p1 core.RecordID, p2 core.PulseNumber) *mHeavySyncMockResume {
+	m.mock.ResumeFunc = nil
+	m.expectationSeries = nil
+
+	if m.mainExpectation == nil {
+		m.mainExpectation = &HeavySyncMockResumeExpectation{}
+	}
+	m.mainExpectation.input = &HeavySyncMockResumeInput{p, p1, p2}
+	return m
+}
+
+//Return specifies results of invocation of HeavySync.Resume
+func (m *mHeavySyncMockResume) Return(r []byte, r1 error) *HeavySyncMock {
+	m.mock.ResumeFunc = nil
+	m.expectationSeries = nil
+
+	if m.mainExpectation == nil {
+		m.mainExpectation = &HeavySyncMockResumeExpectation{}
+	}
+	m.mainExpectation.result = &HeavySyncMockResumeResult{r, r1}
+	return m.mock
+}
+
+//ExpectOnce specifies that invocation of HeavySync.Resume is expected once
+func (m *mHeavySyncMockResume) ExpectOnce(p context.Context, p1 core.RecordID, p2 core.PulseNumber) *HeavySyncMockResumeExpectation {
+	m.mock.ResumeFunc = nil
+	m.mainExpectation = nil
+
+	expectation := &HeavySyncMockResumeExpectation{}
+	expectation.input = &HeavySyncMockResumeInput{p, p1, p2}
+	m.expectationSeries = append(m.expectationSeries, expectation)
+	return expectation
+}
+
+func (e *HeavySyncMockResumeExpectation) Return(r []byte, r1 error) {
+	e.result = &HeavySyncMockResumeResult{r, r1}
+}
+
+//Set uses given function f as a mock of HeavySync.Resume method
+func (m *mHeavySyncMockResume) Set(f func(p context.Context, p1 core.RecordID, p2 core.PulseNumber) (r []byte, r1 error)) *HeavySyncMock {
+	m.mainExpectation = nil
+	m.expectationSeries = nil
+
+	m.mock.ResumeFunc = f
+	return m.mock
+}
+
+//Resume implements github.com/insolar/insolar/core.HeavySync interface
+func (m *HeavySyncMock) Resume(p context.Context, p1 core.RecordID, p2 core.PulseNumber) (r []byte, r1 error) {
+	counter := atomic.AddUint64(&m.ResumePreCounter, 1)
+	defer atomic.AddUint64(&m.ResumeCounter, 1)
+
+	if len(m.ResumeMock.expectationSeries) > 0 {
+		if counter > uint64(len(m.ResumeMock.expectationSeries)) {
+			m.t.Fatalf("Unexpected call to HeavySyncMock.Resume. %v %v %v", p, p1, p2)
+			return
+		}
+
+		input := m.ResumeMock.expectationSeries[counter-1].input
+		testify_assert.Equal(m.t, *input, HeavySyncMockResumeInput{p, p1, p2}, "HeavySync.Resume got unexpected parameters")
+
+		result := m.ResumeMock.expectationSeries[counter-1].result
+		if result == nil {
+			m.t.Fatal("No results are set for the HeavySyncMock.Resume")
+			return
+		}
+
+		r = result.r
+		r1 = result.r1
+
+		return
+	}
+
+	if m.ResumeMock.mainExpectation != nil {
+
+		input := m.ResumeMock.mainExpectation.input
+		if input != nil {
+			testify_assert.Equal(m.t, *input, HeavySyncMockResumeInput{p, p1, p2}, "HeavySync.Resume got unexpected parameters")
+		}
+
+		result := m.ResumeMock.mainExpectation.result
+		if result == nil {
+			m.t.Fatal("No results are set for the HeavySyncMock.Resume")
+		}
+
+		r = result.r
+		r1 = result.r1
+
+		return
+	}
+
+	if m.ResumeFunc == nil {
+		m.t.Fatalf("Unexpected call to HeavySyncMock.Resume. %v %v %v", p, p1, p2)
+		return
+	}
+
+	return m.ResumeFunc(p, p1, p2)
+}
+
+//ResumeMinimockCounter returns a count of HeavySyncMock.ResumeFunc invocations
+func (m *HeavySyncMock) ResumeMinimockCounter() uint64 {
+	return atomic.LoadUint64(&m.ResumeCounter)
+}
+
+//ResumeMinimockPreCounter returns the value of HeavySyncMock.Resume invocations
+func (m *HeavySyncMock) ResumeMinimockPreCounter() uint64 {
+	return atomic.LoadUint64(&m.ResumePreCounter)
+}
+
+//ResumeFinished returns true if mock invocations count is ok
+func (m *HeavySyncMock) ResumeFinished() bool {
+	// if expectation series were set then invocations count should be equal to expectations count
+	if len(m.ResumeMock.expectationSeries) > 0 {
+		return atomic.LoadUint64(&m.ResumeCounter) == uint64(len(m.ResumeMock.expectationSeries))
+	}
+
+	// if main expectation was set then invocations count should be greater than zero
+	if m.ResumeMock.mainExpectation != nil {
+		return atomic.LoadUint64(&m.ResumeCounter) > 0
+	}
+
+	// if func was set then invocations count should be greater than zero
+	if m.ResumeFunc != nil {
+		return atomic.LoadUint64(&m.ResumeCounter) > 0
+	}
+
+	return true
+}
+
 type mHeavySyncMockStart struct {
 	mock              *HeavySyncMock
 	mainExpectation   *HeavySyncMockStartExpectation
@@ -519,54 +677,57 @@ type HeavySyncMockStoreInput struct {
 	p  context.Context
 	p1 core.RecordID
 	p2 core.PulseNumber
-	p3 []core.KV
+	p3 []byte
+	p4 []byte
+	p5 []core.KV
 }
 
 type HeavySyncMockStoreResult struct {
-	r error
+	r  []byte
+	r1 error
 }
 
 //Expect specifies that invocation of HeavySync.Store is expected from 1 to Infinity times
-func (m *mHeavySyncMockStore) Expect(p context.Context, p1 core.RecordID, p2 core.PulseNumber, p3 []core.KV) *mHeavySyncMockStore {
+func (m *mHeavySyncMockStore) Expect(p context.Context, p1 core.RecordID, p2 core.PulseNumber, p3 []byte, p4 []byte, p5 []core.KV) *mHeavySyncMockStore {
 	m.mock.StoreFunc = nil
 	m.expectationSeries = nil
 
 	if m.mainExpectation == nil {
 		m.mainExpectation = &HeavySyncMockStoreExpectation{}
 	}
-	m.mainExpectation.input = &HeavySyncMockStoreInput{p, p1, p2, p3}
+	m.mainExpectation.input = &HeavySyncMockStoreInput{p, p1, p2, p3, p4, p5}
 	return m
 }
 
 //Return specifies results of invocation of HeavySync.Store
-func (m *mHeavySyncMockStore) Return(r error) *HeavySyncMock {
+func (m *mHeavySyncMockStore) Return(r []byte, r1 error) *HeavySyncMock {
 	m.mock.StoreFunc = nil
 	m.expectationSeries = nil
 
 	if m.mainExpectation == nil {
 		m.mainExpectation = &HeavySyncMockStoreExpectation{}
 	}
-	m.mainExpectation.result = &HeavySyncMockStoreResult{r}
+	m.mainExpectation.result = &HeavySyncMockStoreResult{r, r1}
 	return m.mock
 }
 
 //ExpectOnce specifies that invocation of HeavySync.Store is expected once
-func (m *mHeavySyncMockStore) ExpectOnce(p context.Context, p1 core.RecordID, p2 core.PulseNumber, p3 []core.KV) *HeavySyncMockStoreExpectation {
+func (m *mHeavySyncMockStore) ExpectOnce(p context.Context, p1 core.RecordID, p2 core.PulseNumber, p3 []byte, p4 []byte, p5 []core.KV) *HeavySyncMockStoreExpectation {
 	m.mock.StoreFunc = nil
 	m.mainExpectation = nil
 
 	expectation := &HeavySyncMockStoreExpectation{}
-	expectation.input = &HeavySyncMockStoreInput{p, p1, p2, p3}
+	expectation.input = &HeavySyncMockStoreInput{p, p1, p2, p3, p4, p5}
 	m.expectationSeries = append(m.expectationSeries, expectation)
 	return expectation
 }
 
-func (e *HeavySyncMockStoreExpectation) Return(r error) {
-	e.result = &HeavySyncMockStoreResult{r}
+func (e *HeavySyncMockStoreExpectation) Return(r []byte, r1 error) {
+	e.result = &HeavySyncMockStoreResult{r, r1}
 }
 
 //Set uses given function f as a mock of HeavySync.Store method
-func (m *mHeavySyncMockStore) Set(f func(p context.Context, p1 core.RecordID, p2 core.PulseNumber, p3 []core.KV) (r error)) *HeavySyncMock {
+func (m *mHeavySyncMockStore) Set(f func(p context.Context, p1 core.RecordID, p2 core.PulseNumber, p3 []byte, p4 []byte, p5 []core.KV) (r []byte, r1 error)) *HeavySyncMock {
 	m.mainExpectation = nil
 	m.expectationSeries = nil
 
@@ -575,18 +736,18 @@ func (m *mHeavySyncMockStore) Set(f func(p context.Context, p1 core.RecordID, p2
 }
 
 //Store implements github.com/insolar/insolar/core.HeavySync interface
-func (m *HeavySyncMock) Store(p context.Context, p1 core.RecordID, p2 core.PulseNumber, p3 []core.KV) (r error) {
+func (m *HeavySyncMock) Store(p context.Context, p1 core.RecordID, p2 core.PulseNumber, p3 []byte, p4 []byte, p5 []core.KV) (r []byte, r1 error) {
 	counter := atomic.AddUint64(&m.StorePreCounter, 1)
 	defer atomic.AddUint64(&m.StoreCounter, 1)
 
 	if len(m.StoreMock.expectationSeries) > 0 {
 		if counter > uint64(len(m.StoreMock.expectationSeries)) {
-			m.t.Fatalf("Unexpected call to HeavySyncMock.Store. %v %v %v %v", p, p1, p2, p3)
+			m.t.Fatalf("Unexpected call to HeavySyncMock.Store. %v %v %v %v %v %v", p, p1, p2, p3, p4, p5)
 			return
 		}
 
 		input := m.StoreMock.expectationSeries[counter-1].input
-		testify_assert.Equal(m.t, *input, HeavySyncMockStoreInput{p, p1, p2, p3}, "HeavySync.Store got unexpected parameters")
+		testify_assert.Equal(m.t, *input, HeavySyncMockStoreInput{p, p1, p2, p3, p4, p5}, "HeavySync.Store got unexpected parameters")
 
 		result := m.StoreMock.expectationSeries[counter-1].result
 		if result == nil {
@@ -595,6 +756,7 @@ func (m *HeavySyncMock) Store(p context.Context, p1 core.RecordID, p2 core.Pulse
 		}
 
 		r = result.r
+		r1 = result.r1
 
 		return
 	}
@@ -603,7 +765,7 @@ func (m *HeavySyncMock) Store(p context.Context, p1 core.RecordID, p2 core.Pulse
 
 		input := m.StoreMock.mainExpectation.input
 		if input != nil {
-			testify_assert.Equal(m.t, *input, HeavySyncMockStoreInput{p, p1, p2, p3}, "HeavySync.Store got unexpected parameters")
+			testify_assert.Equal(m.t, *input, HeavySyncMockStoreInput{p, p1, p2, p3, p4, p5}, "HeavySync.Store got unexpected parameters")
 		}
 
 		result := m.StoreMock.mainExpectation.result
@@ -612,16 +774,17 @@ func (m *HeavySyncMock) Store(p context.Context, p1 core.RecordID, p2 core.Pulse
 		}
 
 		r = result.r
+		r1 = result.r1
 
 		return
 	}
 
 	if m.StoreFunc == nil {
-		m.t.Fatalf("Unexpected call to HeavySyncMock.Store. %v %v %v %v", p, p1, p2, p3)
+		m.t.Fatalf("Unexpected call to HeavySyncMock.Store. %v %v %v %v %v %v", p, p1, p2, p3, p4, p5)
 		return
 	}
 
-	return m.StoreFunc(p, p1, p2, p3)
+	return m.StoreFunc(p, p1, p2, p3, p4, p5)
 }
 
 //StoreMinimockCounter returns a count of HeavySyncMock.StoreFunc invocations
@@ -661,6 +824,9 @@ func (m *HeavySyncMock) ValidateCallCounters() {
 	if !m.ResetFinished() {
 		m.t.Fatal("Expected call to HeavySyncMock.Reset")
 	}
+	if !m.ResumeFinished() {
+		m.t.Fatal("Expected call to HeavySyncMock.Resume")
+	}
 
 	if !m.StartFinished() {
 		m.t.Fatal("Expected call to HeavySyncMock.Start")
@@ -694,6 +860,9 @@ func (m *HeavySyncMock) MinimockFinish() {
 	if !m.ResetFinished() {
 		m.t.Fatal("Expected call to HeavySyncMock.Reset")
 	}
+	if !m.ResumeFinished() {
+		m.t.Fatal("Expected call to HeavySyncMock.Resume")
+	}
 
 	if !m.StartFinished() {
 		m.t.Fatal("Expected call to HeavySyncMock.Start")
@@ -722,6 +891,7 @@ func (m *HeavySyncMock) MinimockWait(timeout time.Duration) {
 	for {
 		ok := true
 		ok = ok && m.ResetFinished()
+		ok = ok && m.ResumeFinished()
 		ok = ok && m.StartFinished()
 		ok = ok && m.StopFinished()
 		ok = ok && m.StoreFinished()
@@ -736,6 +906,9 @@ func (m *HeavySyncMock) MinimockWait(timeout time.Duration) {
 			if !m.ResetFinished() {
 				m.t.Error("Expected call to HeavySyncMock.Reset")
 			}
+			if !m.ResumeFinished() {
+				m.t.Error("Expected call to HeavySyncMock.Resume")
+			}
 
 			if !m.StartFinished() {
 				m.t.Error("Expected call to HeavySyncMock.Start")
@@ -764,6 +937,9 @@ func (m *HeavySyncMock) AllMocksCalled() bool {
 	if !m.ResetFinished() {
 		return false
 	}
+	if !m.ResumeFinished() {
+		return false
+	}
 
 	if !m.StartFinished() {
 		return false
@@ -41,6 +41,11 @@ type MessageBusMock struct {
 	OnPulsePreCounter uint64
 	OnPulseMock       mMessageBusMockOnPulse
 
+	PublishFunc       func(p context.Context, p1 core.Message, p2 *core.MessageSendOptions) (r <-chan core.Delivery, r1 error)
+	PublishCounter    uint64
+	PublishPreCounter uint64
+	PublishMock       mMessageBusMockPublish
+
 	RegisterFunc       func(p core.MessageType, p1 core.MessageHandler) (r error)
 	RegisterCounter    uint64
 	RegisterPreCounter uint64
@@ -50,6 +55,11 @@ type MessageBusMock struct {
 	SendCounter    uint64
 	SendPreCounter uint64
 	SendMock       mMessageBusMockSend
+
+	SubscribeFunc       func(p core.MessageType, p1 core.MessageHandler) (r error)
+	SubscribeCounter    uint64
+	SubscribePreCounter uint64
+	SubscribeMock       mMessageBusMockSubscribe
 }
 
 //NewMessageBusMock returns a mock for github.com/insolar/insolar/core.MessageBus
@@ -64,8 +74,10 @@ func NewMessageBusMock(t minimock.Tester) *MessageBusMock {
 	m.NewPlayerMock = mMessageBusMockNewPlayer{mock: m}
 	m.NewRecorderMock = mMessageBusMockNewRecorder{mock: m}
 	m.OnPulseMock = mMessageBusMockOnPulse{mock: m}
+	m.PublishMock = mMessageBusMockPublish{mock: m}
 	m.RegisterMock = mMessageBusMockRegister{mock: m}
 	m.SendMock = mMessageBusMockSend{mock: m}
+	m.SubscribeMock = mMessageBusMockSubscribe{mock: m}
 
 	return m
 }
@@ -644,6 +656,158 @@ func (m *MessageBusMock) OnPulseFinished() bool {
 	return true
 }
 
+type mMessageBusMockPublish struct {
+	mock              *MessageBusMock
+	mainExpectation   *MessageBusMockPublishExpectation
+	expectationSeries []*MessageBusMockPublishExpectation
+}
+
+type MessageBusMockPublishExpectation struct {
+	input  *MessageBusMockPublishInput
+	result *MessageBusMockPublishResult
+}
+
+type MessageBusMockPublishInput struct {
+	p  context.Context
+	p1 core.Message
+	p2 *core.MessageSendOptions
+}
+
+type MessageBusMockPublishResult struct {
+	r  <-chan core.Delivery
+	r1 error
+}
+
+//Expect specifies that invocation of MessageBus.Publish is expected from 1 to Infinity times
+func (m *mMessageBusMockPublish) Expect(p context.Context, p1 core.Message, p2 *core.MessageSendOptions) *mMessageBusMockPublish {
+	m.mock.PublishFunc = nil
+	m.expectationSeries = nil
+
+	if m.mainExpectation == nil {
+		m.mainExpectation = &MessageBusMockPublishExpectation{}
+	}
+	m.mainExpectation.input = &MessageBusMockPublishInput{p, p1, p2}
+	return m
+}
+
+//Return specifies results of invocation of MessageBus.Publish
+func (m *mMessageBusMockPublish) Return(r <-chan core.Delivery, r1 error) *MessageBusMock {
+	m.mock.PublishFunc = nil
+	m.expectationSeries = nil
+
+	if m.mainExpectation == nil {
+		m.mainExpectation = &MessageBusMockPublishExpectation{}
+	}
+	m.mainExpectation.result = &MessageBusMockPublishResult{r, r1}
+	return m.mock
+}
+
+//ExpectOnce specifies that invocation of MessageBus.Publish is expected once
+func (m *mMessageBusMockPublish) ExpectOnce(p context.Context, p1 core.Message, p2 *core.MessageSendOptions) *MessageBusMockPublishExpectation {
+	m.mock.PublishFunc = nil
+	m.mainExpectation = nil
+
+	expectation := &MessageBusMockPublishExpectation{}
+	expectation.input = &MessageBusMockPublishInput{p, p1, p2}
+	m.expectationSeries = append(m.expectationSeries, expectation)
+	return expectation
+}
+
+func (e *MessageBusMockPublishExpectation) Return(r <-chan core.Delivery, r1 error) {
+	e.result = &MessageBusMockPublishResult{r, r1}
+}
+
+//Set uses given function f as a mock of MessageBus.Publish method
+func (m *mMessageBusMockPublish) Set(f func(p context.Context, p1 core.Message, p2 *core.MessageSendOptions) (r <-chan core.Delivery, r1 error)) *MessageBusMock {
+	m.mainExpectation = nil
+	m.expectationSeries = nil
+
+	m.mock.PublishFunc = f
+	return m.mock
+}
+
+//Publish implements github.com/insolar/insolar/core.MessageBus interface
+func (m *MessageBusMock) Publish(p context.Context, p1 core.Message, p2 *core.MessageSendOptions) (r <-chan core.Delivery, r1 error) {
+	counter := atomic.AddUint64(&m.PublishPreCounter, 1)
+	defer atomic.AddUint64(&m.PublishCounter, 1)
+
+	if len(m.PublishMock.expectationSeries) > 0 {
+		if counter > uint64(len(m.PublishMock.expectationSeries)) {
+			m.t.Fatalf("Unexpected call to MessageBusMock.Publish. %v %v %v", p, p1, p2)
+			return
+		}
+
+		input := m.PublishMock.expectationSeries[counter-1].input
+		testify_assert.Equal(m.t, *input, MessageBusMockPublishInput{p, p1, p2}, "MessageBus.Publish got unexpected parameters")
+
+		result := m.PublishMock.expectationSeries[counter-1].result
+		if result == nil {
+			m.t.Fatal("No results are set for the MessageBusMock.Publish")
+			return
+		}
+
+		r = result.r
+		r1 = result.r1
+
+		return
+	}
+
+	if m.PublishMock.mainExpectation != nil {
+
+		input := m.PublishMock.mainExpectation.input
+		if input != nil {
+			testify_assert.Equal(m.t, *input, MessageBusMockPublishInput{p, p1, p2}, "MessageBus.Publish got unexpected parameters")
+		}
+
+		result := m.PublishMock.mainExpectation.result
+		if result == nil {
+			m.t.Fatal("No results are set for the MessageBusMock.Publish")
+		}
+
+		r = result.r
+		r1 = result.r1
+
+		return
+	}
+
+	if m.PublishFunc == nil {
+		m.t.Fatalf("Unexpected call to MessageBusMock.Publish. %v %v %v", p, p1, p2)
+		return
+	}
+
+	return m.PublishFunc(p, p1, p2)
+}
+
+//PublishMinimockCounter returns a count of MessageBusMock.PublishFunc invocations
+func (m *MessageBusMock) PublishMinimockCounter() uint64 {
+	return atomic.LoadUint64(&m.PublishCounter)
+}
+
+//PublishMinimockPreCounter returns the value of MessageBusMock.Publish invocations
+func (m *MessageBusMock) PublishMinimockPreCounter() uint64 {
+	return atomic.LoadUint64(&m.PublishPreCounter)
+}
+
+//PublishFinished returns true if mock invocations count is ok
+func (m *MessageBusMock) PublishFinished() bool {
+	// if expectation series were set then invocations count should be equal to expectations count
+	if len(m.PublishMock.expectationSeries) > 0 {
+		return atomic.LoadUint64(&m.PublishCounter) == uint64(len(m.PublishMock.expectationSeries))
+	}
+
+	// if main expectation was set then invocations count should be greater than zero
+	if m.PublishMock.mainExpectation != nil {
+		return atomic.LoadUint64(&m.PublishCounter) > 0
+	}
+
+	// if func was set then invocations count should be greater than zero
+	if m.PublishFunc != nil {
+		return atomic.LoadUint64(&m.PublishCounter) > 0
+	}
+
+	return true
+}
+
 type mMessageBusMockRegister struct {
 	mock              *MessageBusMock
 	mainExpectation   *MessageBusMockRegisterExpectation
@@ -944,6 +1108,154 @@ func (m *MessageBusMock) SendFinished() bool {
 	return true
 }
 
+type mMessageBusMockSubscribe struct {
+	mock              *MessageBusMock
+	mainExpectation   *MessageBusMockSubscribeExpectation
+	expectationSeries []*MessageBusMockSubscribeExpectation
+}
+
+type MessageBusMockSubscribeExpectation struct {
+	input  *MessageBusMockSubscribeInput
+	result *MessageBusMockSubscribeResult
+}
+
+type MessageBusMockSubscribeInput struct {
+	p  core.MessageType
+	p1 core.MessageHandler
+}
+
+type MessageBusMockSubscribeResult struct {
+	r error
+}
+
+//Expect specifies that invocation of MessageBus.Subscribe is expected from 1 to Infinity times
+func (m *mMessageBusMockSubscribe) Expect(p core.MessageType, p1 core.MessageHandler) *mMessageBusMockSubscribe {
+	m.mock.SubscribeFunc = nil
+	m.expectationSeries = nil
+
+	if m.mainExpectation == nil {
+		m.mainExpectation = &MessageBusMockSubscribeExpectation{}
+	}
+	m.mainExpectation.input = &MessageBusMockSubscribeInput{p, p1}
+	return m
+}
+
+//Return specifies results of invocation of MessageBus.Subscribe
+func (m *mMessageBusMockSubscribe) Return(r error) *MessageBusMock {
+	m.mock.SubscribeFunc = nil
+	m.expectationSeries = nil
+
+	if m.mainExpectation == nil {
+		m.mainExpectation = &MessageBusMockSubscribeExpectation{}
+	}
+	m.mainExpectation.result = &MessageBusMockSubscribeResult{r}
+	return m.mock
+}
+
+//ExpectOnce specifies that invocation of MessageBus.Subscribe is expected once
+func (m *mMessageBusMockSubscribe) ExpectOnce(p core.MessageType, p1 core.MessageHandler) *MessageBusMockSubscribeExpectation {
+	m.mock.SubscribeFunc = nil
+	m.mainExpectation = nil
+
+	expectation := &MessageBusMockSubscribeExpectation{}
+	expectation.input = &MessageBusMockSubscribeInput{p, p1}
+	m.expectationSeries = append(m.expectationSeries, expectation)
+	return expectation
+}
+
+func (e *MessageBusMockSubscribeExpectation) Return(r error) {
+	e.result = &MessageBusMockSubscribeResult{r}
+}
+
+//Set uses given function f as a mock of MessageBus.Subscribe method
+func (m *mMessageBusMockSubscribe) Set(f func(p core.MessageType, p1 core.MessageHandler) (r error)) *MessageBusMock {
+	m.mainExpectation = nil
+	m.expectationSeries = nil
+
+	m.mock.SubscribeFunc = f
+	return m.mock
+}
+
+//Subscribe implements github.com/insolar/insolar/core.MessageBus interface
+func (m *MessageBusMock) Subscribe(p core.MessageType, p1 core.MessageHandler) (r error) {
+	counter := atomic.AddUint64(&m.SubscribePreCounter, 1)
+	defer atomic.AddUint64(&m.SubscribeCounter, 1)
+
+	if len(m.SubscribeMock.expectationSeries) > 0 {
+		if counter > uint64(len(m.SubscribeMock.expectationSeries)) {
+			m.t.Fatalf("Unexpected call to MessageBusMock.Subscribe. %v %v", p, p1)
+			return
+		}
+
+		input := m.SubscribeMock.expectationSeries[counter-1].input
+		testify_assert.Equal(m.t, *input, MessageBusMockSubscribeInput{p, p1}, "MessageBus.Subscribe got unexpected parameters")
+
+		result := m.SubscribeMock.expectationSeries[counter-1].result
+		if result == nil {
+			m.t.Fatal("No results are set for the MessageBusMock.Subscribe")
+			return
+		}
+
+		r = result.r
+
+		return
+	}
+
+	if m.SubscribeMock.mainExpectation != nil {
+
+		input := m.SubscribeMock.mainExpectation.input
+		if input != nil {
+			testify_assert.Equal(m.t, *input, MessageBusMockSubscribeInput{p, p1}, "MessageBus.Subscribe got unexpected parameters")
+		}
+
+		result := m.SubscribeMock.mainExpectation.result
+		if result == nil {
+			m.t.Fatal("No results are set for the MessageBusMock.Subscribe")
+		}
+
+		r = result.r
+
+		return
+	}
+
+	if m.SubscribeFunc == nil {
+		m.t.Fatalf("Unexpected call to MessageBusMock.Subscribe. %v %v", p, p1)
+		return
+	}
+
+	return m.SubscribeFunc(p, p1)
+}
+
+//SubscribeMinimockCounter returns a count of MessageBusMock.SubscribeFunc invocations
+func (m *MessageBusMock) SubscribeMinimockCounter() uint64 {
+	return atomic.LoadUint64(&m.SubscribeCounter)
+}
+
+//SubscribeMinimockPreCounter returns the value of MessageBusMock.Subscribe invocations
+func (m *MessageBusMock) SubscribeMinimockPreCounter() uint64 {
+	return atomic.LoadUint64(&m.SubscribePreCounter)
+}
+
+//SubscribeFinished returns true if mock invocations count is ok
+func (m *MessageBusMock) SubscribeFinished() bool {
+	// if expectation series were set then invocations count should be equal to expectations count
+	if len(m.SubscribeMock.expectationSeries) > 0 {
+		return atomic.LoadUint64(&m.SubscribeCounter) == uint64(len(m.SubscribeMock.expectationSeries))
+	}
+
+	// if main expectation was set then invocations count should be greater than zero
+	if m.SubscribeMock.mainExpectation != nil {
+		return atomic.LoadUint64(&m.SubscribeCounter) > 0
+	}
+
+	// if func was set then invocations count should be greater than zero
+	if m.SubscribeFunc != nil {
+		return atomic.LoadUint64(&m.SubscribeCounter) > 0
+	}
+
+	return true
+}
+
 //ValidateCallCounters checks that all mocked methods of the interface have been called at least once
 //Deprecated: please use MinimockFinish method or use Finish method of minimock.Controller
 func (m *MessageBusMock) ValidateCallCounters() {
@@ -964,6 +1276,10 @@ func (m *MessageBusMock) ValidateCallCounters() {
 		m.t.Fatal("Expected call to MessageBusMock.OnPulse")
 	}
 
+	if !m.PublishFinished() {
+		m.t.Fatal("Expected call to MessageBusMock.Publish")
+	}
+
 	if !m.RegisterFinished() {
 		m.t.Fatal("Expected call to MessageBusMock.Register")
 	}
@@ -972,6 +1288,10 @@ func (m *MessageBusMock) ValidateCallCounters() {
 		m.t.Fatal("Expected call to MessageBusMock.Send")
 	}
 
+	if !m.SubscribeFinished() {
+		m.t.Fatal("Expected call to MessageBusMock.Subscribe")
+	}
+
 }
 
 //CheckMocksCalled checks that all mocked methods of the interface have been called at least once
@@ -1005,6 +1325,10 @@ func (m *MessageBusMock) MinimockFinish() {
 		m.t.Fatal("Expected call to MessageBusMock.OnPulse")
 	}
 
+	if !m.PublishFinished() {
+		m.t.Fatal("Expected call to MessageBusMock.Publish")
+	}
+
 	if !m.RegisterFinished() {
 		m.t.Fatal("Expected call to MessageBusMock.Register")
 	}
@@ -1013,6 +1337,10 @@ func (m *MessageBusMock) MinimockFinish() {
 		m.t.Fatal("Expected call to MessageBusMock.Send")
 	}
 
+	if !m.SubscribeFinished() {
+		m.t.Fatal("Expected call to MessageBusMock.Subscribe")
+	}
+
 }
 
 //Wait waits for all mocked methods to be called at least once
@@ -1031,8 +1359,10 @@ func (m *MessageBusMock) MinimockWait(timeout time.Duration) {
 		ok = ok && m.NewPlayerFinished()
 		ok = ok && m.NewRecorderFinished()
 		ok = ok && m.OnPulseFinished()
+		ok = ok && m.PublishFinished()
 		ok = ok && m.RegisterFinished()
 		ok = ok && m.SendFinished()
+		ok = ok && m.SubscribeFinished()
 
 		if ok {
 			return
@@ -1057,6 +1387,10 @@ func (m *MessageBusMock) MinimockWait(timeout time.Duration) {
 				m.t.Error("Expected call to MessageBusMock.OnPulse")
 			}
 
+			if !m.PublishFinished() {
+				m.t.Error("Expected call to MessageBusMock.Publish")
+			}
+
 			if !m.RegisterFinished() {
 				m.t.Error("Expected call to MessageBusMock.Register")
 			}
@@ -1065,6 +1399,10 @@ func (m *MessageBusMock) MinimockWait(timeout time.Duration) {
 				m.t.Error("Expected call to MessageBusMock.Send")
 			}
 
+			if !m.SubscribeFinished() {
+				m.t.Error("Expected call to MessageBusMock.Subscribe")
+			}
+
 			m.t.Fatalf("Some mocks were not called on time: %s", timeout)
 			return
 		default:
@@ -1093,6 +1431,10 @@ func (m *MessageBusMock) AllMocksCalled() bool {
 		return false
 	}
 
+	if !m.PublishFinished() {
+		return false
+	}
+
 	if !m.RegisterFinished() {
 		return false
 	}
@@ -1101,5 +1443,9 @@ func (m *MessageBusMock) AllMocksCalled() bool {
 		return false
 	}
 
+	if !m.SubscribeFinished() {
+		return false
+	}
+
 	return true
 }
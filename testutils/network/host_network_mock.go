@@ -37,6 +37,11 @@ type HostNetworkMock struct {
 	NewRequestBuilderPreCounter uint64
 	NewRequestBuilderMock       mHostNetworkMockNewRequestBuilder
 
+	OpenStreamFunc       func(p context.Context, p1 network.Request, p2 core.RecordRef) (r network.Stream, r1 error)
+	OpenStreamCounter    uint64
+	OpenStreamPreCounter uint64
+	OpenStreamMock       mHostNetworkMockOpenStream
+
 	PublicAddressFunc       func() (r string)
 	PublicAddressCounter    uint64
 	PublicAddressPreCounter uint64
@@ -47,6 +52,11 @@ type HostNetworkMock struct {
 	RegisterRequestHandlerPreCounter uint64
 	RegisterRequestHandlerMock       mHostNetworkMockRegisterRequestHandler
 
+	RegisterStreamHandlerFunc       func(p types.PacketType, p1 network.StreamHandler)
+	RegisterStreamHandlerCounter    uint64
+	RegisterStreamHandlerPreCounter uint64
+	RegisterStreamHandlerMock       mHostNetworkMockRegisterStreamHandler
+
 	SendRequestFunc       func(p context.Context, p1 network.Request, p2 core.RecordRef) (r network.Future, r1 error)
 	SendRequestCounter    uint64
 	SendRequestPreCounter uint64
@@ -74,8 +84,10 @@ func NewHostNetworkMock(t minimock.Tester) *HostNetworkMock {
 	m.BuildResponseMock = mHostNetworkMockBuildResponse{mock: m}
 	m.GetNodeIDMock = mHostNetworkMockGetNodeID{mock: m}
 	m.NewRequestBuilderMock = mHostNetworkMockNewRequestBuilder{mock: m}
+	m.OpenStreamMock = mHostNetworkMockOpenStream{mock: m}
 	m.PublicAddressMock = mHostNetworkMockPublicAddress{mock: m}
 	m.RegisterRequestHandlerMock = mHostNetworkMockRegisterRequestHandler{mock: m}
+	m.RegisterStreamHandlerMock = mHostNetworkMockRegisterStreamHandler{mock: m}
 	m.SendRequestMock = mHostNetworkMockSendRequest{mock: m}
 	m.StartMock = mHostNetworkMockStart{mock: m}
 	m.StopMock = mHostNetworkMockStop{mock: m}
@@ -500,6 +512,158 @@ func (m *HostNetworkMock) NewRequestBuilderFinished() bool {
 	return true
 }
 
+type mHostNetworkMockOpenStream struct {
+	mock              *HostNetworkMock
+	mainExpectation   *HostNetworkMockOpenStreamExpectation
+	expectationSeries []*HostNetworkMockOpenStreamExpectation
+}
+
+type HostNetworkMockOpenStreamExpectation struct {
+	input  *HostNetworkMockOpenStreamInput
+	result *HostNetworkMockOpenStreamResult
+}
+
+type HostNetworkMockOpenStreamInput struct {
+	p  context.Context
+	p1 network.Request
+	p2 core.RecordRef
+}
+
+type HostNetworkMockOpenStreamResult struct {
+	r  network.Stream
+	r1 error
+}
+
+//Expect specifies that invocation of HostNetwork.OpenStream is expected from 1 to Infinity times
+func (m *mHostNetworkMockOpenStream) Expect(p context.Context, p1 network.Request, p2 core.RecordRef) *mHostNetworkMockOpenStream {
+	m.mock.OpenStreamFunc = nil
+	m.expectationSeries = nil
+
+	if m.mainExpectation == nil {
+		m.mainExpectation = &HostNetworkMockOpenStreamExpectation{}
+	}
+	m.mainExpectation.input = &HostNetworkMockOpenStreamInput{p, p1, p2}
+	return m
+}
+
+//Return specifies results of invocation of HostNetwork.OpenStream
+func (m *mHostNetworkMockOpenStream) Return(r network.Stream, r1 error) *HostNetworkMock {
+	m.mock.OpenStreamFunc = nil
+	m.expectationSeries = nil
+
+	if m.mainExpectation == nil {
+		m.mainExpectation = &HostNetworkMockOpenStreamExpectation{}
+	}
+	m.mainExpectation.result = &HostNetworkMockOpenStreamResult{r, r1}
+	return m.mock
+}
+
+//ExpectOnce specifies that invocation of HostNetwork.OpenStream is expected once
+func (m *mHostNetworkMockOpenStream) ExpectOnce(p context.Context, p1 network.Request, p2 core.RecordRef) *HostNetworkMockOpenStreamExpectation {
+	m.mock.OpenStreamFunc = nil
+	m.mainExpectation = nil
+
+	expectation := &HostNetworkMockOpenStreamExpectation{}
+	expectation.input = &HostNetworkMockOpenStreamInput{p, p1, p2}
+	m.expectationSeries = append(m.expectationSeries, expectation)
+	return expectation
+}
+
+func (e *HostNetworkMockOpenStreamExpectation) Return(r network.Stream, r1 error) {
+	e.result = &HostNetworkMockOpenStreamResult{r, r1}
+}
+
+//Set uses given function f as a mock of HostNetwork.OpenStream method
+func (m *mHostNetworkMockOpenStream) Set(f func(p context.Context, p1 network.Request, p2 core.RecordRef) (r network.Stream, r1 error)) *HostNetworkMock {
+	m.mainExpectation = nil
+	m.expectationSeries = nil
+
+	m.mock.OpenStreamFunc = f
+	return m.mock
+}
+
+//OpenStream implements github.com/insolar/insolar/network.HostNetwork interface
+func (m *HostNetworkMock) OpenStream(p context.Context, p1 network.Request, p2 core.RecordRef) (r network.Stream, r1 error) {
+	counter := atomic.AddUint64(&m.OpenStreamPreCounter, 1)
+	defer atomic.AddUint64(&m.OpenStreamCounter, 1)
+
+	if len(m.OpenStreamMock.expectationSeries) > 0 {
+		if counter > uint64(len(m.OpenStreamMock.expectationSeries)) {
+			m.t.Fatalf("Unexpected call to HostNetworkMock.OpenStream. %v %v %v", p, p1, p2)
+			return
+		}
+
+		input := m.OpenStreamMock.expectationSeries[counter-1].input
+		testify_assert.Equal(m.t, *input, HostNetworkMockOpenStreamInput{p, p1, p2}, "HostNetwork.OpenStream got unexpected parameters")
+
+		result := m.OpenStreamMock.expectationSeries[counter-1].result
+		if result == nil {
+			m.t.Fatal("No results are set for the HostNetworkMock.OpenStream")
+			return
+		}
+
+		r = result.r
+		r1 = result.r1
+
+		return
+	}
+
+	if m.OpenStreamMock.mainExpectation != nil {
+
+		input := m.OpenStreamMock.mainExpectation.input
+		if input != nil {
+			testify_assert.Equal(m.t, *input, HostNetworkMockOpenStreamInput{p, p1, p2}, "HostNetwork.OpenStream got unexpected parameters")
+		}
+
+		result := m.OpenStreamMock.mainExpectation.result
+		if result == nil {
+			m.t.Fatal("No results are set for the HostNetworkMock.OpenStream")
+		}
+
+		r = result.r
+		r1 = result.r1
+
+		return
+	}
+
+	if m.OpenStreamFunc == nil {
+		m.t.Fatalf("Unexpected call to HostNetworkMock.OpenStream. %v %v %v", p, p1, p2)
+		return
+	}
+
+	return m.OpenStreamFunc(p, p1, p2)
+}
+
+//OpenStreamMinimockCounter returns a count of HostNetworkMock.OpenStreamFunc invocations
+func (m *HostNetworkMock) OpenStreamMinimockCounter() uint64 {
+	return atomic.LoadUint64(&m.OpenStreamCounter)
+}
+
+//OpenStreamMinimockPreCounter returns the value of HostNetworkMock.OpenStream invocations
+func (m *HostNetworkMock) OpenStreamMinimockPreCounter() uint64 {
+	return atomic.LoadUint64(&m.OpenStreamPreCounter)
+}
+
+//OpenStreamFinished returns true if mock invocations count is ok
+func (m *HostNetworkMock) OpenStreamFinished() bool {
+	// if expectation series were set then invocations count should be equal to expectations count
+	if len(m.OpenStreamMock.expectationSeries) > 0 {
+		return atomic.LoadUint64(&m.OpenStreamCounter) == uint64(len(m.OpenStreamMock.expectationSeries))
+	}
+
+	// if main expectation was set then invocations count should be greater than zero
+	if m.OpenStreamMock.mainExpectation != nil {
+		return atomic.LoadUint64(&m.OpenStreamCounter) > 0
+	}
+
+	// if func was set then invocations count should be greater than zero
+	if m.OpenStreamFunc != nil {
+		return atomic.LoadUint64(&m.OpenStreamCounter) > 0
+	}
+
+	return true
+}
+
 type mHostNetworkMockPublicAddress struct {
 	mock              *HostNetworkMock
 	mainExpectation   *HostNetworkMockPublicAddressExpectation
@@ -758,6 +922,130 @@ func (m *HostNetworkMock) RegisterRequestHandlerFinished() bool {
 	return true
 }
 
+type mHostNetworkMockRegisterStreamHandler struct {
+	mock              *HostNetworkMock
+	mainExpectation   *HostNetworkMockRegisterStreamHandlerExpectation
+	expectationSeries []*HostNetworkMockRegisterStreamHandlerExpectation
+}
+
+type HostNetworkMockRegisterStreamHandlerExpectation struct {
+	input *HostNetworkMockRegisterStreamHandlerInput
+}
+
+type HostNetworkMockRegisterStreamHandlerInput struct {
+	p  types.PacketType
+	p1 network.StreamHandler
+}
+
+//Expect specifies that invocation of HostNetwork.RegisterStreamHandler is expected from 1 to Infinity times
+func (m *mHostNetworkMockRegisterStreamHandler) Expect(p types.PacketType, p1 network.StreamHandler) *mHostNetworkMockRegisterStreamHandler {
+	m.mock.RegisterStreamHandlerFunc = nil
+	m.expectationSeries = nil
+
+	if m.mainExpectation == nil {
+		m.mainExpectation = &HostNetworkMockRegisterStreamHandlerExpectation{}
+	}
+	m.mainExpectation.input = &HostNetworkMockRegisterStreamHandlerInput{p, p1}
+	return m
+}
+
+//Return specifies results of invocation of HostNetwork.RegisterStreamHandler
+func (m *mHostNetworkMockRegisterStreamHandler) Return() *HostNetworkMock {
+	m.mock.RegisterStreamHandlerFunc = nil
+	m.expectationSeries = nil
+
+	if m.mainExpectation == nil {
+		m.mainExpectation = &HostNetworkMockRegisterStreamHandlerExpectation{}
+	}
+
+	return m.mock
+}
+
+//ExpectOnce specifies that invocation of HostNetwork.RegisterStreamHandler is expected once
+func (m *mHostNetworkMockRegisterStreamHandler) ExpectOnce(p types.PacketType, p1 network.StreamHandler) *HostNetworkMockRegisterStreamHandlerExpectation {
+	m.mock.RegisterStreamHandlerFunc = nil
+	m.mainExpectation = nil
+
+	expectation := &HostNetworkMockRegisterStreamHandlerExpectation{}
+	expectation.input = &HostNetworkMockRegisterStreamHandlerInput{p, p1}
+	m.expectationSeries = append(m.expectationSeries, expectation)
+	return expectation
+}
+
+//Set uses given function f as a mock of HostNetwork.RegisterStreamHandler method
+func (m *mHostNetworkMockRegisterStreamHandler) Set(f func(p types.PacketType, p1 network.StreamHandler)) *HostNetworkMock {
+	m.mainExpectation = nil
+	m.expectationSeries = nil
+
+	m.mock.RegisterStreamHandlerFunc = f
+	return m.mock
+}
+
+//RegisterStreamHandler implements github.com/insolar/insolar/network.HostNetwork interface
+func (m *HostNetworkMock) RegisterStreamHandler(p types.PacketType, p1 network.StreamHandler) {
+	counter := atomic.AddUint64(&m.RegisterStreamHandlerPreCounter, 1)
+	defer atomic.AddUint64(&m.RegisterStreamHandlerCounter, 1)
+
+	if len(m.RegisterStreamHandlerMock.expectationSeries) > 0 {
+		if counter > uint64(len(m.RegisterStreamHandlerMock.expectationSeries)) {
+			m.t.Fatalf("Unexpected call to HostNetworkMock.RegisterStreamHandler. %v %v", p, p1)
+			return
+		}
+
+		input := m.RegisterStreamHandlerMock.expectationSeries[counter-1].input
+		testify_assert.Equal(m.t, *input, HostNetworkMockRegisterStreamHandlerInput{p, p1}, "HostNetwork.RegisterStreamHandler got unexpected parameters")
+
+		return
+	}
+
+	if m.RegisterStreamHandlerMock.mainExpectation != nil {
+
+		input := m.RegisterStreamHandlerMock.mainExpectation.input
+		if input != nil {
+			testify_assert.Equal(m.t, *input, HostNetworkMockRegisterStreamHandlerInput{p, p1}, "HostNetwork.RegisterStreamHandler got unexpected parameters")
+		}
+
+		return
+	}
+
+	if m.RegisterStreamHandlerFunc == nil {
+		m.t.Fatalf("Unexpected call to HostNetworkMock.RegisterStreamHandler. %v %v", p, p1)
+		return
+	}
+
+	m.RegisterStreamHandlerFunc(p, p1)
+}
+
+//RegisterStreamHandlerMinimockCounter returns a count of HostNetworkMock.RegisterStreamHandlerFunc invocations
+func (m *HostNetworkMock) RegisterStreamHandlerMinimockCounter() uint64 {
+	return atomic.LoadUint64(&m.RegisterStreamHandlerCounter)
+}
+
+//RegisterStreamHandlerMinimockPreCounter returns the value of HostNetworkMock.RegisterStreamHandler invocations
+func (m *HostNetworkMock) RegisterStreamHandlerMinimockPreCounter() uint64 {
+	return atomic.LoadUint64(&m.RegisterStreamHandlerPreCounter)
+}
+
+//RegisterStreamHandlerFinished returns true if mock invocations count is ok
+func (m *HostNetworkMock) RegisterStreamHandlerFinished() bool {
+	// if expectation series were set then invocations count should be equal to expectations count
+	if len(m.RegisterStreamHandlerMock.expectationSeries) > 0 {
+		return atomic.LoadUint64(&m.RegisterStreamHandlerCounter) == uint64(len(m.RegisterStreamHandlerMock.expectationSeries))
+	}
+
+	// if main expectation was set then invocations count should be greater than zero
+	if m.RegisterStreamHandlerMock.mainExpectation != nil {
+		return atomic.LoadUint64(&m.RegisterStreamHandlerCounter) > 0
+	}
+
+	// if func was set then invocations count should be greater than zero
+	if m.RegisterStreamHandlerFunc != nil {
+		return atomic.LoadUint64(&m.RegisterStreamHandlerCounter) > 0
+	}
+
+	return true
+}
+
 type mHostNetworkMockSendRequest struct {
 	mock              *HostNetworkMock
 	mainExpectation   *HostNetworkMockSendRequestExpectation
@@ -1159,6 +1447,10 @@ func (m *HostNetworkMock) ValidateCallCounters() {
 		m.t.Fatal("Expected call to HostNetworkMock.NewRequestBuilder")
 	}
 
+	if !m.OpenStreamFinished() {
+		m.t.Fatal("Expected call to HostNetworkMock.OpenStream")
+	}
+
 	if !m.PublicAddressFinished() {
 		m.t.Fatal("Expected call to HostNetworkMock.PublicAddress")
 	}
@@ -1167,6 +1459,10 @@ func (m *HostNetworkMock) ValidateCallCounters() {
 		m.t.Fatal("Expected call to HostNetworkMock.RegisterRequestHandler")
 	}
 
+	if !m.RegisterStreamHandlerFinished() {
+		m.t.Fatal("Expected call to HostNetworkMock.RegisterStreamHandler")
+	}
+
 	if !m.SendRequestFinished() {
 		m.t.Fatal("Expected call to HostNetworkMock.SendRequest")
 	}
@@ -1208,6 +1504,10 @@ func (m *HostNetworkMock) MinimockFinish() {
 		m.t.Fatal("Expected call to HostNetworkMock.NewRequestBuilder")
 	}
 
+	if !m.OpenStreamFinished() {
+		m.t.Fatal("Expected call to HostNetworkMock.OpenStream")
+	}
+
 	if !m.PublicAddressFinished() {
 		m.t.Fatal("Expected call to HostNetworkMock.PublicAddress")
 	}
@@ -1216,6 +1516,10 @@ func (m *HostNetworkMock) MinimockFinish() {
 		m.t.Fatal("Expected call to HostNetworkMock.RegisterRequestHandler")
 	}
 
+	if !m.RegisterStreamHandlerFinished() {
+		m.t.Fatal("Expected call to HostNetworkMock.RegisterStreamHandler")
+	}
+
 	if !m.SendRequestFinished() {
 		m.t.Fatal("Expected call to HostNetworkMock.SendRequest")
 	}
@@ -1245,8 +1549,10 @@ func (m *HostNetworkMock) MinimockWait(timeout time.Duration) {
 		ok = ok && m.BuildResponseFinished()
 		ok = ok && m.GetNodeIDFinished()
 		ok = ok && m.NewRequestBuilderFinished()
+		ok = ok && m.OpenStreamFinished()
 		ok = ok && m.PublicAddressFinished()
 		ok = ok && m.RegisterRequestHandlerFinished()
+		ok = ok && m.RegisterStreamHandlerFinished()
 		ok = ok && m.SendRequestFinished()
 		ok = ok && m.StartFinished()
 		ok = ok && m.StopFinished()
@@ -1270,6 +1576,10 @@ func (m *HostNetworkMock) MinimockWait(timeout time.Duration) {
 				m.t.Error("Expected call to HostNetworkMock.NewRequestBuilder")
 			}
 
+			if !m.OpenStreamFinished() {
+				m.t.Error("Expected call to HostNetworkMock.OpenStream")
+			}
+
 			if !m.PublicAddressFinished() {
 				m.t.Error("Expected call to HostNetworkMock.PublicAddress")
 			}
@@ -1278,6 +1588,10 @@ func (m *HostNetworkMock) MinimockWait(timeout time.Duration) {
 				m.t.Error("Expected call to HostNetworkMock.RegisterRequestHandler")
 			}
 
+			if !m.RegisterStreamHandlerFinished() {
+				m.t.Error("Expected call to HostNetworkMock.RegisterStreamHandler")
+			}
+
 			if !m.SendRequestFinished() {
 				m.t.Error("Expected call to HostNetworkMock.SendRequest")
 			}
@@ -1314,6 +1628,10 @@ func (m *HostNetworkMock) AllMocksCalled() bool {
 		return false
 	}
 
+	if !m.OpenStreamFinished() {
+		return false
+	}
+
 	if !m.PublicAddressFinished() {
 		return false
 	}
@@ -1322,6 +1640,10 @@ func (m *HostNetworkMock) AllMocksCalled() bool {
 		return false
 	}
 
+	if !m.RegisterStreamHandlerFinished() {
+		return false
+	}
+
 	if !m.SendRequestFinished() {
 		return false
 	}
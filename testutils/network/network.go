@@ -31,6 +31,9 @@ func (n *testNetwork) SendCascadeMessage(data core.Cascade, method string, msg c
 }
 func (n *testNetwork) RemoteProcedureRegister(name string, method core.RemoteProcedure) {
 
+}
+func (n *testNetwork) RemoteStreamProcedureRegister(name string, method core.RemoteStreamProcedure) {
+
 }
 
 func GetTestNetwork() core.Network {
@@ -32,6 +32,7 @@ import (
 	"github.com/insolar/insolar/core"
 	"github.com/insolar/insolar/core/delegationtoken"
 	"github.com/insolar/insolar/core/message"
+	"github.com/insolar/insolar/core/reply"
 	"github.com/insolar/insolar/instrumentation/inslogger"
 	"github.com/insolar/insolar/messagebus"
 	"github.com/insolar/insolar/platformpolicy"
@@ -46,6 +47,7 @@ type TapeRecord struct {
 
 type TestMessageBus struct {
 	handlers     map[core.MessageType]core.MessageHandler
+	subscribers  map[core.MessageType][]core.MessageHandler
 	pf           message.ParcelFactory
 	PulseStorage core.PulseStorage
 	ReadingTape  []TapeRecord
@@ -99,7 +101,11 @@ func NewTestMessageBus(t *testing.T) *TestMessageBus {
 	cm.Register(platformpolicy.NewPlatformCryptographyScheme())
 	cm.Inject(delegationTokenFactory, parcelFactory, cryptoServiceMock)
 
-	return &TestMessageBus{handlers: map[core.MessageType]core.MessageHandler{}, pf: parcelFactory}
+	return &TestMessageBus{
+		handlers:    map[core.MessageType]core.MessageHandler{},
+		subscribers: map[core.MessageType][]core.MessageHandler{},
+		pf:          parcelFactory,
+	}
 }
 
 func (mb *TestMessageBus) Register(p core.MessageType, handler core.MessageHandler) error {
@@ -168,6 +174,48 @@ func (mb *TestMessageBus) Send(ctx context.Context, m core.Message, _ *core.Mess
 	return reply, err
 }
 
+func (mb *TestMessageBus) Subscribe(p core.MessageType, handler core.MessageHandler) error {
+	mb.subscribers[p] = append(mb.subscribers[p], handler)
+	return nil
+}
+
+func (mb *TestMessageBus) Publish(ctx context.Context, m core.Message, _ *core.MessageSendOptions) (<-chan core.Delivery, error) {
+	currentPulse, err := mb.PulseStorage.Current(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	parcel, err := mb.pf.Create(ctx, m, testutils.RandomRef(), nil, core.Pulse{PulseNumber: currentPulse.PulseNumber, Entropy: core.Entropy{}})
+	if err != nil {
+		return nil, err
+	}
+	t := parcel.Message().Type()
+	subscribers := mb.subscribers[t]
+	if handler, ok := mb.handlers[t]; ok {
+		subscribers = append([]core.MessageHandler{handler}, subscribers...)
+	}
+	if len(subscribers) == 0 {
+		return nil, errors.New(fmt.Sprint("no subscribers for message type:", t.String()))
+	}
+
+	ctx = parcel.Context(context.Background())
+
+	ack := &reply.Ack{Errors: make([]string, len(subscribers)), Replies: make([]core.Reply, len(subscribers))}
+	for i, handler := range subscribers {
+		rep, err := handler(ctx, parcel)
+		if err != nil {
+			ack.Errors[i] = err.Error()
+			continue
+		}
+		ack.Replies[i] = rep
+	}
+
+	deliveries := make(chan core.Delivery, 1)
+	deliveries <- core.Delivery{Reply: ack, Err: ack.Err()}
+	close(deliveries)
+	return deliveries, nil
+}
+
 func (mb *TestMessageBus) OnPulse(context.Context, core.Pulse) error {
 	return nil
 }
@@ -0,0 +1,236 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+// Package snapshot implements binary stream format of storage snapshots and incremental backups.
+//
+// Stream starts with magic bytes and header, followed by key-value entries and terminated
+// by end marker with entries count, so truncated streams are detected on reading.
+package snapshot
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+
+	"github.com/insolar/insolar/core"
+	"github.com/pkg/errors"
+)
+
+const (
+	version = 1
+	// maxChunkSize limits key and value size accepted by Reader.
+	maxChunkSize = 1 << 30
+)
+
+var magic = []byte("INSSNAP")
+
+var (
+	// ErrBadFormat is returned by Reader if stream is not a snapshot.
+	ErrBadFormat = errors.New("not a snapshot stream")
+	// ErrTruncated is returned by Reader if stream ends before end marker.
+	ErrTruncated = errors.New("snapshot stream is truncated")
+)
+
+// Header describes snapshot content.
+type Header struct {
+	// Source is a kind of storage snapshot is taken from.
+	Source string
+	// Pulse is the latest pulse included into snapshot.
+	Pulse core.PulseNumber
+	// Since is a pulse incremental backup starts from, zero for full snapshot.
+	Since core.PulseNumber
+}
+
+// IsFull checks if snapshot contains all data of storage.
+func (h Header) IsFull() bool {
+	return h.Since == 0
+}
+
+// Writer writes snapshot stream.
+type Writer struct {
+	w     *bufio.Writer
+	count uint64
+	buf   [binary.MaxVarintLen64]byte
+}
+
+// NewWriter writes magic and header to w and returns Writer for entries.
+func NewWriter(w io.Writer, h Header) (*Writer, error) {
+	sw := &Writer{w: bufio.NewWriter(w)}
+	if _, err := sw.w.Write(magic); err != nil {
+		return nil, errors.Wrap(err, "[ NewWriter ] failed to write magic")
+	}
+	if err := sw.w.WriteByte(version); err != nil {
+		return nil, errors.Wrap(err, "[ NewWriter ] failed to write version")
+	}
+	if err := sw.writeChunk([]byte(h.Source)); err != nil {
+		return nil, errors.Wrap(err, "[ NewWriter ] failed to write header")
+	}
+	if _, err := sw.w.Write(h.Pulse.Bytes()); err != nil {
+		return nil, errors.Wrap(err, "[ NewWriter ] failed to write header")
+	}
+	if _, err := sw.w.Write(h.Since.Bytes()); err != nil {
+		return nil, errors.Wrap(err, "[ NewWriter ] failed to write header")
+	}
+	return sw, nil
+}
+
+func (w *Writer) writeUvarint(n uint64) error {
+	l := binary.PutUvarint(w.buf[:], n)
+	_, err := w.w.Write(w.buf[:l])
+	return err
+}
+
+func (w *Writer) writeChunk(b []byte) error {
+	if err := w.writeUvarint(uint64(len(b))); err != nil {
+		return err
+	}
+	_, err := w.w.Write(b)
+	return err
+}
+
+// Write appends key-value entry to stream. Key must not be empty.
+func (w *Writer) Write(key, value []byte) error {
+	if len(key) == 0 {
+		return errors.New("[ Write ] empty key")
+	}
+	if err := w.writeChunk(key); err != nil {
+		return errors.Wrap(err, "[ Write ] failed to write key")
+	}
+	if err := w.writeChunk(value); err != nil {
+		return errors.Wrap(err, "[ Write ] failed to write value")
+	}
+	w.count++
+	return nil
+}
+
+// Count returns number of written entries.
+func (w *Writer) Count() uint64 {
+	return w.count
+}
+
+// Close writes end marker and flushes stream. It does not close underlying writer.
+func (w *Writer) Close() error {
+	if err := w.writeUvarint(0); err != nil {
+		return errors.Wrap(err, "[ Close ] failed to write end marker")
+	}
+	if err := w.writeUvarint(w.count); err != nil {
+		return errors.Wrap(err, "[ Close ] failed to write end marker")
+	}
+	return errors.Wrap(w.w.Flush(), "[ Close ] failed to flush")
+}
+
+// Reader reads snapshot stream.
+type Reader struct {
+	r      *bufio.Reader
+	header Header
+	count  uint64
+	done   bool
+}
+
+// NewReader reads and checks magic and header of snapshot stream.
+func NewReader(r io.Reader) (*Reader, error) {
+	sr := &Reader{r: bufio.NewReader(r)}
+
+	head := make([]byte, len(magic)+1)
+	if _, err := io.ReadFull(sr.r, head); err != nil {
+		return nil, ErrBadFormat
+	}
+	if !bytes.Equal(head[:len(magic)], magic) {
+		return nil, ErrBadFormat
+	}
+	if head[len(magic)] != version {
+		return nil, errors.Errorf("[ NewReader ] unsupported snapshot version %d", head[len(magic)])
+	}
+
+	source, err := sr.readChunk()
+	if err != nil {
+		return nil, err
+	}
+	pulses := make([]byte, 2*core.PulseNumberSize)
+	if _, err := io.ReadFull(sr.r, pulses); err != nil {
+		return nil, ErrTruncated
+	}
+	sr.header = Header{
+		Source: string(source),
+		Pulse:  core.NewPulseNumber(pulses[:core.PulseNumberSize]),
+		Since:  core.NewPulseNumber(pulses[core.PulseNumberSize:]),
+	}
+	return sr, nil
+}
+
+// Header returns snapshot header.
+func (r *Reader) Header() Header {
+	return r.header
+}
+
+func (r *Reader) readUvarint() (uint64, error) {
+	n, err := binary.ReadUvarint(r.r)
+	if err != nil {
+		return 0, ErrTruncated
+	}
+	return n, nil
+}
+
+func (r *Reader) readChunk() ([]byte, error) {
+	l, err := r.readUvarint()
+	if err != nil {
+		return nil, err
+	}
+	return r.readBytes(l)
+}
+
+func (r *Reader) readBytes(l uint64) ([]byte, error) {
+	if l > maxChunkSize {
+		return nil, ErrBadFormat
+	}
+	b := make([]byte, l)
+	if _, err := io.ReadFull(r.r, b); err != nil {
+		return nil, ErrTruncated
+	}
+	return b, nil
+}
+
+// Next returns next entry of stream. It returns io.EOF after end marker is read
+// and ErrTruncated if stream ends unexpectedly.
+func (r *Reader) Next() (key, value []byte, err error) {
+	if r.done {
+		return nil, nil, io.EOF
+	}
+	l, err := r.readUvarint()
+	if err != nil {
+		return nil, nil, err
+	}
+	if l == 0 {
+		count, err := r.readUvarint()
+		if err != nil {
+			return nil, nil, err
+		}
+		if count != r.count {
+			return nil, nil, ErrTruncated
+		}
+		r.done = true
+		return nil, nil, io.EOF
+	}
+	if key, err = r.readBytes(l); err != nil {
+		return nil, nil, err
+	}
+	if value, err = r.readChunk(); err != nil {
+		return nil, nil, err
+	}
+	r.count++
+	return key, value, nil
+}
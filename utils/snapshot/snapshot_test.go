@@ -0,0 +1,80 @@
+/*
+ *    Copyright 2018 Insolar
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package snapshot
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeSnapshot(t *testing.T, h Header, n int) []byte {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, h)
+	require.NoError(t, err)
+	for i := 0; i < n; i++ {
+		require.NoError(t, w.Write([]byte(fmt.Sprintf("key%d", i)), []byte(fmt.Sprintf("value%d", i))))
+	}
+	assert.Equal(t, uint64(n), w.Count())
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestSnapshot_WriteRead(t *testing.T) {
+	h := Header{Source: "ledger", Pulse: 65600, Since: 65540}
+	data := writeSnapshot(t, h, 3)
+
+	r, err := NewReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, h, r.Header())
+	assert.False(t, r.Header().IsFull())
+
+	for i := 0; i < 3; i++ {
+		k, v, err := r.Next()
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("key%d", i), string(k))
+		assert.Equal(t, fmt.Sprintf("value%d", i), string(v))
+	}
+	_, _, err = r.Next()
+	assert.Equal(t, io.EOF, err)
+	_, _, err = r.Next()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestSnapshot_Truncated(t *testing.T) {
+	data := writeSnapshot(t, Header{Source: "ledger", Pulse: 65600}, 3)
+
+	r, err := NewReader(bytes.NewReader(data[:len(data)-10]))
+	require.NoError(t, err)
+	for err == nil {
+		_, _, err = r.Next()
+	}
+	assert.Equal(t, ErrTruncated, err)
+}
+
+func TestSnapshot_BadFormat(t *testing.T) {
+	_, err := NewReader(bytes.NewReader([]byte("definitely not a snapshot")))
+	assert.Equal(t, ErrBadFormat, err)
+
+	w, err := NewWriter(&bytes.Buffer{}, Header{})
+	require.NoError(t, err)
+	assert.Error(t, w.Write(nil, []byte("value")))
+}